@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/cache"
+	"cobra-template/internal/infrastructure/database"
+	"cobra-template/internal/infrastructure/repository"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/internal/service"
+	"cobra-template/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var archiveSemester string
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive a semester that has ended",
+	Long: `Move the registrations and waitlist entries of a semester whose last day is over to the
+archive tables and purge the Redis keys of its sections and students, so the live tables
+and the cache only hold the semesters still being registered for. Reports and course
+history keep reading the archived rows. The semester is marked archived and inactive;
+running the command again for it only purges the cache again.`,
+	Run: runArchive,
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.Flags().StringVar(&archiveSemester, "semester", "", "Code or ID of the semester to archive")
+	archiveCmd.MarkFlagRequired("semester")
+}
+
+func runArchive(cmd *cobra.Command, args []string) {
+	cfg := config.Get()
+
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	cacheService := cache.NewRedisCacheWithConfig(&cfg.Cache)
+	defer cacheService.Close()
+
+	termLocation, err := time.LoadLocation(cfg.Institution.Timezone)
+	if err != nil {
+		logger.Warn("Unknown institution timezone %q, using UTC: %v", cfg.Institution.Timezone, err)
+		termLocation = time.UTC
+	}
+
+	var waitlistRepo interfaces.WaitlistRepository = repository.NewWaitlistRepository(db)
+	if cfg.Registration.WaitlistRepository == "redis" {
+		waitlistRepo = repository.NewRedisWaitlistRepository(cacheService.GetClient())
+	}
+	semesterRepo := repository.NewSemesterRepository(db)
+	archiver := service.NewSemesterArchiver(
+		semesterRepo,
+		repository.NewSectionRepository(db),
+		repository.NewRegistrationRepository(db),
+		waitlistRepo,
+		cacheService,
+		termLocation,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	semesterID, err := uuid.Parse(archiveSemester)
+	if err != nil {
+		semester, err := semesterRepo.GetByCode(ctx, archiveSemester)
+		if err != nil {
+			logger.Error("Failed to get semester %s: %v", archiveSemester, err)
+			os.Exit(1)
+		}
+		if semester == nil {
+			logger.Error("Semester %s not found", archiveSemester)
+			os.Exit(1)
+		}
+		semesterID = semester.SemesterID
+	}
+
+	report, err := archiver.Archive(ctx, semesterID)
+	if err != nil {
+		logger.Error("Failed to archive semester %s: %v", archiveSemester, err)
+		os.Exit(1)
+	}
+
+	if report.AlreadyArchived {
+		fmt.Printf("Semester %s was already archived\n", report.SemesterCode)
+	} else {
+		fmt.Printf("Archived semester %s: %d registrations and %d waitlist entries\n",
+			report.SemesterCode, report.Registrations, report.WaitlistEntries)
+	}
+	fmt.Printf("Purged the cache of %d sections and %d students\n", report.Sections, report.Students)
+	if report.KeysFailed > 0 {
+		logger.Error("Failed to purge %d cache keys, run the command again to retry them", report.KeysFailed)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"cobra-template/internal/auth"
+	"cobra-template/internal/config"
+	"cobra-template/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenSubject   string
+	tokenRole      string
+	tokenStudentID string
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Authentication tooling",
+	Long:  "Tooling for the JWT authentication of the registration API",
+}
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Issue an access token",
+	Long: `Sign an access token with the configured auth.jwt_secret. Meant for local testing and
+operational scripts; users normally get their tokens from the identity provider.`,
+	Run: runAuthToken,
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authTokenCmd)
+	authTokenCmd.Flags().StringVar(&tokenSubject, "subject", "", "Subject (user ID) of the token")
+	authTokenCmd.Flags().StringVar(&tokenRole, "role", string(auth.RoleStudent), "Role: student, advisor, registrar or admin")
+	authTokenCmd.Flags().StringVar(&tokenStudentID, "student-id", "", "Student the token acts for (required for the student role)")
+	authTokenCmd.MarkFlagRequired("subject")
+}
+
+func runAuthToken(cmd *cobra.Command, args []string) {
+	cfg := config.Get()
+
+	authenticator, err := auth.NewAuthenticator(&cfg.Auth)
+	if err != nil {
+		logger.Error("Failed to create authenticator: %v", err)
+		os.Exit(1)
+	}
+
+	var studentID *uuid.UUID
+	if tokenStudentID != "" {
+		id, err := uuid.Parse(tokenStudentID)
+		if err != nil {
+			logger.Error("Invalid student ID %q: %v", tokenStudentID, err)
+			os.Exit(1)
+		}
+		studentID = &id
+	}
+
+	token, err := authenticator.Issue(tokenSubject, auth.Role(tokenRole), studentID)
+	if err != nil {
+		logger.Error("Failed to issue token: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(token)
+}
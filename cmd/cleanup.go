@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/database"
+	"cobra-template/internal/infrastructure/repository"
+	"cobra-template/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanupBatchSize int
+	cleanupPause     time.Duration
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Prune expired records",
+	Long:  "Maintenance jobs that remove records past their expiry",
+}
+
+var cleanupIdempotencyCmd = &cobra.Command{
+	Use:   "idempotency",
+	Short: "Prune expired idempotency keys",
+	Long: `Delete the idempotency keys persisted to Postgres by the hybrid store once they have
+expired, a batch at a time so the table is never locked for long. Redis expires its own
+copies and is not touched.`,
+	Run: runCleanupIdempotency,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanupCmd)
+	cleanupCmd.AddCommand(cleanupIdempotencyCmd)
+	cleanupIdempotencyCmd.Flags().IntVar(&cleanupBatchSize, "batch-size", 1000, "Keys deleted per statement")
+	cleanupIdempotencyCmd.Flags().DurationVar(&cleanupPause, "pause", 100*time.Millisecond, "Pause between batches")
+}
+
+func runCleanupIdempotency(cmd *cobra.Command, args []string) {
+	cfg := config.Get()
+
+	if cleanupBatchSize <= 0 {
+		logger.Error("--batch-size must be positive")
+		os.Exit(1)
+	}
+
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	// Keys that expire while the job runs are left for the next run
+	cutoff := time.Now()
+	var total int64
+	for batch := 1; ; batch++ {
+		deleted, err := idempotencyRepo.PruneExpired(ctx, cutoff, cleanupBatchSize)
+		if err != nil {
+			logger.Error("Failed to prune idempotency keys after deleting %d: %v", total, err)
+			os.Exit(1)
+		}
+		total += deleted
+		logger.Debug("Idempotency cleanup batch %d deleted %d keys", batch, deleted)
+
+		if deleted < int64(cleanupBatchSize) {
+			break
+		}
+		time.Sleep(cleanupPause)
+	}
+
+	fmt.Printf("Deleted %d expired idempotency keys\n", total)
+}
@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"cobra-template/internal/config"
 	"cobra-template/internal/infrastructure/database"
@@ -24,6 +27,31 @@ var migrateUpCmd = &cobra.Command{
 	Run:   runMigrateUp,
 }
 
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [n]",
+	Short: "Revert applied migrations",
+	Long: `Revert the last n applied migrations, one by default, latest first, by running their
+paired .down.sql files.
+
+Only migrations with a down file can be reverted, so the latest migration without one is the
+rollback floor: a rollback that would revert it or an earlier migration is refused before
+anything is reverted.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runMigrateDown,
+}
+
+var migrateToCmd = &cobra.Command{
+	Use:   "to <id>",
+	Short: "Migrate to a given migration",
+	Long: `Revert the applied migrations after the given one and apply the pending migrations up
+to and including it.
+
+Reverting stops at the rollback floor, the latest migration without a down file: a target
+before it is refused before anything is reverted or applied.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runMigrateTo,
+}
+
 var migrateStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show migration status",
@@ -31,10 +59,38 @@ var migrateStatusCmd = &cobra.Command{
 	Run:   runMigrateStatus,
 }
 
+var migratePartitionsCmd = &cobra.Command{
+	Use:   "partitions",
+	Short: "Create and list semester partitions",
+	Long:  "Create the registrations and waitlist partitions of any semester missing them, then list every partition with its estimated row count",
+	Run:   runMigratePartitions,
+}
+
+var migrateAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Report the query plans of hot queries",
+	Long:  "Run the registration, roster, waitlist and catalog queries under EXPLAIN ANALYZE and report the slow ones and the tables they scan in full",
+	Run:   runMigrateAnalyze,
+}
+
+var (
+	analyzeSlowMS int
+	migrateDryRun bool
+)
+
 func init() {
 	rootCmd.AddCommand(migrateCmd)
 	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateToCmd)
 	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.AddCommand(migratePartitionsCmd)
+	migrateCmd.AddCommand(migrateAnalyzeCmd)
+
+	for _, command := range []*cobra.Command{migrateUpCmd, migrateDownCmd, migrateToCmd} {
+		command.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Print the SQL that would be run without running it")
+	}
+	migrateAnalyzeCmd.Flags().IntVar(&analyzeSlowMS, "slow-ms", 50, "Execution time in milliseconds above which a query is reported as slow")
 }
 
 func runMigrateUp(cmd *cobra.Command, args []string) {
@@ -59,12 +115,81 @@ func runMigrateUp(cmd *cobra.Command, args []string) {
 
 	// Run migrations
 	migrationRunner := database.NewMigrationRunner(db, "migrations")
+	migrationRunner.SetDryRun(migrateDryRun)
 	if err := migrationRunner.RunMigrations(); err != nil {
 		logger.Error("Migration failed: %v", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("Migrations completed successfully!")
+	if !migrateDryRun {
+		fmt.Println("Migrations completed successfully!")
+	}
+}
+
+func runMigrateDown(cmd *cobra.Command, args []string) {
+	steps := 1
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			logger.Error("Number of migrations to revert must be a positive integer, got %q", args[0])
+			os.Exit(1)
+		}
+		steps = n
+	}
+
+	// Load configuration
+	cfg := config.Get()
+
+	// Connect to database
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	migrationRunner := database.NewMigrationRunner(db, "migrations")
+	migrationRunner.SetDryRun(migrateDryRun)
+	if err := migrationRunner.RollbackMigrations(steps); err != nil {
+		logger.Error("Rollback failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+func runMigrateTo(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Get()
+
+	// Connect to database
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	migrationRunner := database.NewMigrationRunner(db, "migrations")
+	migrationRunner.SetDryRun(migrateDryRun)
+	if err := migrationRunner.MigrateTo(args[0]); err != nil {
+		logger.Error("Migration failed: %v", err)
+		os.Exit(1)
+	}
 }
 
 func runMigrateStatus(cmd *cobra.Command, args []string) {
@@ -105,3 +230,97 @@ func runMigrateStatus(cmd *cobra.Command, args []string) {
 		fmt.Printf("%s - %s [%s]\n", migration.ID, migration.Description, status)
 	}
 }
+
+func runMigratePartitions(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Get()
+
+	// Connect to database
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	partitionManager := database.NewPartitionManager(db)
+	if err := partitionManager.EnsureSemesterPartitions(); err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+
+	partitions, err := partitionManager.ListPartitions()
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Semester Partitions:")
+	fmt.Println("====================")
+	for _, partition := range partitions {
+		fmt.Printf("%s - %s %s [~%d rows]\n", partition.Table, partition.Partition, partition.Bound, partition.Rows)
+	}
+}
+
+func runMigrateAnalyze(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg := config.Get()
+
+	// Connect to database
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	analyzer := database.NewQueryAnalyzer(db, time.Duration(analyzeSlowMS)*time.Millisecond)
+	plans, err := analyzer.Analyze()
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Query Plans:")
+	fmt.Println("============")
+	slow := 0
+	for _, plan := range plans {
+		if plan.Skipped {
+			fmt.Printf("%s - skipped, no data to sample\n", plan.Name)
+			continue
+		}
+
+		status := "OK"
+		if plan.Slow {
+			status = "SLOW"
+			slow++
+		}
+		fmt.Printf("%s - %.2fms (planning %.2fms) [%s]\n", plan.Name, plan.ExecutionMS, plan.PlanningMS, status)
+		if len(plan.Indexes) > 0 {
+			fmt.Printf("  indexes: %s\n", strings.Join(plan.Indexes, ", "))
+		}
+		if len(plan.SeqScans) > 0 {
+			fmt.Printf("  sequential scans: %s\n", strings.Join(plan.SeqScans, ", "))
+		}
+		if plan.Slow {
+			fmt.Printf("  query: %s\n", plan.Query)
+		}
+	}
+	fmt.Printf("\n%d of %d queries slower than %dms\n", slow, len(plans), analyzeSlowMS)
+}
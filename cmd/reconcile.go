@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/cache"
+	"cobra-template/internal/infrastructure/database"
+	"cobra-template/internal/infrastructure/repository"
+	"cobra-template/internal/service"
+	"cobra-template/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reconcileHeal          bool
+	reconcileSettleSeconds int
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Check seat counters against the database",
+	Long: `Compare the Redis seat counter of every section in the active semesters with its
+capacity less its enrolled registrations and report the sections that disagree. With
+--heal, a discrepancy that holds for the settle period, with neither side moving, is
+corrected by resetting the counter from the database.`,
+	Run: runReconcile,
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().BoolVar(&reconcileHeal, "heal", false, "Reset the counters that disagree with the database")
+	reconcileCmd.Flags().IntVar(&reconcileSettleSeconds, "settle-seconds", 30, "Time a discrepancy must hold, unchanged, before it is healed")
+}
+
+func runReconcile(cmd *cobra.Command, args []string) {
+	cfg := config.Get()
+
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	cacheService := cache.NewRedisCacheWithConfig(&cfg.Cache)
+	defer cacheService.Close()
+
+	reconciler := service.NewSeatReconciler(
+		repository.NewRegistrationRepository(db),
+		repository.NewSectionRepository(db),
+		repository.NewSemesterRepository(db),
+		cacheService,
+		time.Duration(reconcileSettleSeconds)*time.Second,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	report, err := reconciler.Reconcile(ctx, reconcileHeal)
+	if err != nil {
+		logger.Error("Failed to reconcile seat counters: %v", err)
+		os.Exit(1)
+	}
+
+	for _, discrepancy := range report.Discrepancies {
+		counter := "missing"
+		if discrepancy.CounterSeats != nil {
+			counter = fmt.Sprintf("%d", *discrepancy.CounterSeats)
+		}
+		status := ""
+		if discrepancy.Healed {
+			status = " (healed)"
+		}
+		fmt.Printf("%-10s %-4s %s  counter %-7s expected %d (%d enrolled of %d)%s\n",
+			discrepancy.CourseCode, discrepancy.SectionNumber, discrepancy.SectionID, counter,
+			discrepancy.ExpectedSeats, discrepancy.Enrolled, discrepancy.TotalSeats, status)
+	}
+	fmt.Printf("Checked %d sections: %d discrepancies, %d healed\n",
+		report.Sections, len(report.Discrepancies), report.Healed)
+}
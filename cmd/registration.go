@@ -2,23 +2,30 @@ package cmd
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"cobra-template/internal/api/grpcserver"
 	"cobra-template/internal/api/router"
 	"cobra-template/internal/config"
 	"cobra-template/internal/infrastructure/database"
+	"cobra-template/internal/infrastructure/tracing"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
 	"cobra-template/pkg/logger"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
 )
 
 var (
 	registrationPort    string
+	grpcPort            string
 	enableLoadTestCache bool
+	bootstrapProfile    string
 )
 
 var registrationCmd = &cobra.Command{
@@ -39,14 +46,39 @@ This includes:
 func init() {
 	rootCmd.AddCommand(registrationCmd)
 	registrationCmd.Flags().StringVarP(&registrationPort, "port", "p", "8080", "Port for the registration server to listen on")
+	registrationCmd.Flags().StringVar(&grpcPort, "grpc-port", "", "Serve the gRPC API on this port (overrides grpc.port and enables it)")
 	registrationCmd.Flags().BoolVar(&enableLoadTestCache, "load-test-cache", false, "Enable enhanced pre-caching for load testing")
+	registrationCmd.Flags().StringVar(&bootstrapProfile, "profile", "", "Bootstrap profile: dev (in-memory cache and queue, no auth), loadtest (Redis stores, full warmup, no auth) or prod (Redis stores, auth enforced); other flags still override it")
 }
 
 func startRegistrationServer() {
 	cfg := config.Get()
+	if err := config.ApplyProfile(cfg, bootstrapProfile); err != nil {
+		logger.Error("Invalid --profile: %v", err)
+		os.Exit(1)
+	}
+	if bootstrapProfile != "" {
+		logger.Info("Using the %s bootstrap profile", bootstrapProfile)
+	}
+	if enableLoadTestCache {
+		cfg.Cache.WarmupScope = config.WarmupScopeLoadTest
+	}
 	if registrationPort != "8080" {
 		cfg.Server.Port = registrationPort
 	}
+	if grpcPort != "" {
+		cfg.GRPC.Enabled = true
+		cfg.GRPC.Port = grpcPort
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing, &cfg.App)
+	if err != nil {
+		logger.Error("Failed to initialize tracing: %v", err)
+		os.Exit(1)
+	}
+	if cfg.Tracing.Enabled {
+		logger.Info("Tracing enabled, exporting spans to %s", cfg.Tracing.OTLPEndpoint)
+	}
 
 	dbConfig := database.Config{
 		Host:     cfg.Database.Host,
@@ -87,14 +119,78 @@ func startRegistrationServer() {
 		logger.Info("📚 Available endpoints:")
 		logger.Info("  POST /api/v1/register - Register for courses")
 		logger.Info("  POST /api/v1/register/drop - Drop a course")
+		logger.Info("  POST /api/v1/register/hold - Hold a seat for a few minutes")
+		logger.Info("  POST /api/v1/register/confirm - Confirm a seat hold into a registration")
+		logger.Info("  GET  /api/v1/waiting-room/{token} - Poll a place in the registration waiting room")
+		logger.Info("  GET  /api/v1/courses/search - Search courses by code, title, department, credits and meeting times")
+		logger.Info("  GET  /api/v1/semesters - List active semesters")
+		logger.Info("  GET  /api/v1/semesters/{id}/calendar - Registration windows, deadlines and holidays")
+		logger.Info("  GET  /api/v1/students/{id}/profile - Get student profile")
+		logger.Info("  PATCH /api/v1/students/{id}/profile - Update preferred name and contact info")
 		logger.Info("  GET  /api/v1/students/{id}/registrations - Get student registrations")
 		logger.Info("  GET  /api/v1/students/{id}/waitlist - Get waitlist status")
-		logger.Info("  GET  /api/v1/sections/available - Get available sections")
-		logger.Info("  POST /api/v1/cache/warmup - Manual cache warmup")
+		logger.Info("  DELETE /api/v1/students/{id}/waitlist/{section_id} - Leave a waitlist")
+		logger.Info("  GET  /api/v1/students/{id}/offers - Get waitlist seat offers")
+		logger.Info("  GET  /api/v1/students/{id}/eligibility?section_id= - Registration eligibility pre-check")
+		logger.Info("  GET  /api/v1/students/{id}/schedule-overrides - Student's schedule override requests")
+		logger.Info("  POST /api/v1/students/{id}/schedule-overrides - Ask an advisor to allow a time conflict")
+		logger.Info("  GET  /api/v1/advising/schedule-overrides?status= - Advising queue of schedule overrides")
+		logger.Info("  POST /api/v1/advising/schedule-overrides/{id}/approve - Approve an override and register the student")
+		logger.Info("  POST /api/v1/advising/schedule-overrides/{id}/deny - Deny a schedule override")
+		logger.Info("  POST /api/v1/waitlist/offers/{id}/accept - Accept a seat offer")
+		logger.Info("  POST /api/v1/waitlist/offers/{id}/decline - Decline a seat offer")
+		logger.Info("  GET  /api/v1/sections/available?tags=&attr[key]=&include= - Get available sections, filtered by tags, optionally with waitlist and enrolled counts")
+		logger.Info("  GET  /api/v1/sections/{id}/events - Registration event log (event-sourced mode)")
+		logger.Info("  GET  /api/v1/sections/{id}/events/roster?as_of= - Roster at a point in time (event-sourced mode)")
+		logger.Info("  GET  /api/v1/sections/{id}/availability/stream - Live seat availability (Server-Sent Events)")
+		logger.Info("  GET  /ws/students/{id} - WebSocket notifications for waitlist promotions and seat offers")
+		logger.Info("  GET  /api/v1/admin/queue/names - Configured queue names")
+		logger.Info("  GET  /api/v1/admin/queue/dlq - Inspect dead letter database sync jobs")
+		logger.Info("  POST /api/v1/admin/queue/dlq/replay - Replay dead letter jobs")
+		logger.Info("  GET  /api/v1/admin/queue/poison - Inspect jobs that panicked")
+		logger.Info("  GET  /api/v1/admin/kpis?window_minutes= - Live registration counters")
+		logger.Info("  GET  /api/v1/admin/audit?student_id=&section_id=&action=&from=&to= - Audit log of registration and section changes")
+		logger.Info("  GET  /api/v1/admin/reports/enrollment-forecasts?semester_id=&department= - Predicted final enrollment per section")
+		logger.Info("  POST /api/v1/admin/reports/enrollment-forecasts/run - Recompute enrollment forecasts now")
+		logger.Info("  POST /api/v1/admin/sections - Create a section")
+		logger.Info("  GET  /api/v1/admin/sections/{id} - Get a section with live seat count")
+		logger.Info("  GET  /api/v1/admin/sections/{id}/as-of?timestamp= - Enrollment and waitlist at a past time")
+		logger.Info("  PUT  /api/v1/admin/sections/{id}/capacity - Change section capacity")
+		logger.Info("  POST /api/v1/admin/sections/{id}/deactivate - Deactivate a section")
+		logger.Info("  PUT  /api/v1/admin/sections/{id}/waitlist-freeze - Freeze or unfreeze a section's waitlist")
+		logger.Info("  PUT  /api/v1/admin/sections/{id}/tags - Set section tags and attributes")
+		logger.Info("  GET  /api/v1/admin/courses - List courses in the catalog")
+		logger.Info("  POST /api/v1/admin/courses - Create a course")
+		logger.Info("  GET  /api/v1/admin/courses/{id} - Get a course")
+		logger.Info("  PATCH /api/v1/admin/courses/{id} - Update a course")
+		logger.Info("  POST /api/v1/admin/courses/{id}/deactivate - Deactivate a course")
+		logger.Info("  PUT  /api/v1/admin/courses/{id}/tags - Set course tags and attributes")
+		logger.Info("  GET  /api/v1/admin/semesters - List all semesters")
+		logger.Info("  POST /api/v1/admin/semesters - Create a semester")
+		logger.Info("  GET  /api/v1/admin/semesters/{id} - Get a semester")
+		logger.Info("  PATCH /api/v1/admin/semesters/{id} - Update semester dates or status")
+		logger.Info("  GET  /api/v1/admin/approvals - List proposed destructive operations")
+		logger.Info("  POST /api/v1/admin/approvals - Propose a destructive operation")
+		logger.Info("  GET  /api/v1/admin/approvals/{id} - Get a proposed operation")
+		logger.Info("  POST /api/v1/admin/approvals/{id}/approve - Approve and run a proposed operation")
+		logger.Info("  POST /api/v1/admin/approvals/{id}/reject - Reject a proposed operation")
+		logger.Info("  PATCH /api/v1/admin/students/{id}/profile - Update any student profile field")
+		logger.Info("  POST /api/v1/admin/students/archive - Archive a graduated cohort (read-only registrations)")
+		logger.Info("  GET  /api/v1/admin/api-keys - List API keys of machine clients")
+		logger.Info("  POST /api/v1/admin/api-keys - Issue an API key with scopes and a rate limit")
+		logger.Info("  POST /api/v1/admin/api-keys/{id}/rotate - Rotate an API key with a grace period")
+		logger.Info("  DELETE /api/v1/admin/api-keys/{id} - Revoke an API key")
+		logger.Info("  POST /api/v1/admin/exports/sections/{id}/registrations - Export section registrations as CSV")
+		logger.Info("  POST /api/v1/admin/registrations/import - Import registrations from CSV (dry_run supported)")
+		logger.Info("  GET  /api/v1/files/{key} - Download a stored file via signed URL")
+		logger.Info("  POST /api/v1/webhooks/bursar/holds - Bursar places or releases financial holds (HMAC-signed)")
+		logger.Info("  POST /api/v1/cache/warmup - Warm section caches of all active semesters (?semester_id= for one)")
 		logger.Info("  POST /api/v1/cache/warmup/loadtest - Enhanced load test cache warmup")
 		logger.Info("  GET  /api/v1/cache/stats - Cache statistics")
 		logger.Info("  GET  /api/v1/cache/loadtest/status - Load test readiness status")
+		logger.Info("  POST /graphql - GraphQL student dashboard queries")
 		logger.Info("  GET  /health - Health check")
+		logger.Info("  GET  /metrics - Prometheus metrics")
 
 		if enableLoadTestCache {
 			logger.Info("🚀 Load test cache optimization enabled")
@@ -105,18 +201,102 @@ func startRegistrationServer() {
 		}
 	}()
 
+	var grpcSrv *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcAddr := cfg.GRPC.Host + ":" + cfg.GRPC.Port
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC on %s: %v", grpcAddr, err)
+		}
+		grpcSrv = grpcserver.New(routerComponents.RegistrationService, routerComponents.Authenticator)
+
+		go func() {
+			logger.Info("🔌 Starting gRPC server on %s (registration.v1.RegistrationService)", grpcAddr)
+			if err := grpcSrv.Serve(listener); err != nil {
+				logger.Error("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
+	var diagnosticsSrv *http.Server
+	if cfg.Diagnostics.Enabled {
+		diagnosticsSrv = &http.Server{
+			Addr:         cfg.Diagnostics.Host + ":" + cfg.Diagnostics.Port,
+			Handler:      router.NewDiagnosticsRouter(&cfg.Diagnostics, routerComponents.Storage, time.Duration(cfg.Storage.SignedURLExpiryMinutes)*time.Minute),
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: time.Duration(cfg.Diagnostics.MaxProfileSeconds)*time.Second + 30*time.Second,
+		}
+
+		go func() {
+			logger.Info("🩺 Starting diagnostics server on %s (pprof, runtime stats, profiles)", diagnosticsSrv.Addr)
+			if err := diagnosticsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Diagnostics server failed: %v", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("Shutting down Course Registration Server...")
 	logger.Info("Stopping queue workers...")
-	routerComponents.QueueService.StopWorkers()
+	if routerComponents.OutboxDispatcher != nil {
+		routerComponents.OutboxDispatcher.Stop()
+	}
+	if routerComponents.ForecastService != nil {
+		routerComponents.ForecastService.Stop()
+	}
+	if routerComponents.Scheduler != nil {
+		routerComponents.Scheduler.Stop()
+	}
+	stopQueue(routerComponents.QueueService, time.Duration(cfg.Queue.DrainTimeoutSeconds)*time.Second)
+	routerComponents.RegistrationService.StopSeatSync()
+	routerComponents.RegistrationService.StopAnalytics()
+	routerComponents.StudentHub.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	if grpcSrv != nil {
+		stopGRPCServer(ctx, grpcSrv)
+	}
+	if diagnosticsSrv != nil {
+		if err := diagnosticsSrv.Shutdown(ctx); err != nil {
+			logger.Warn("Diagnostics server forced to shutdown: %v", err)
+		}
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("Server forced to shutdown: %v", err)
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Warn("Failed to flush traces: %v", err)
+	}
 
 	logger.Info("✅ Course Registration Server exited")
 }
+
+// stopQueue drains the queue when it supports it, so jobs in progress finish or go back on
+// the queue; /ready reports the drain meanwhile
+func stopQueue(queueService interfaces.QueueService, timeout time.Duration) {
+	drainable, ok := queueService.(interfaces.DrainableQueue)
+	if !ok {
+		queueService.StopWorkers()
+		return
+	}
+	drainable.Drain(timeout)
+}
+
+// stopGRPCServer lets in-flight RPCs finish, but not past the shutdown deadline
+func stopGRPCServer(ctx context.Context, srv *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warn("gRPC server forced to shutdown")
+		srv.Stop()
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/cache"
+	"cobra-template/internal/infrastructure/database"
+	"cobra-template/internal/infrastructure/repository"
+	"cobra-template/internal/service"
+	"cobra-template/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveCohort     string
+	archiveStudentIDs []string
+)
+
+var studentsCmd = &cobra.Command{
+	Use:   "students",
+	Short: "Student record maintenance",
+	Long:  "Maintenance jobs for student records",
+}
+
+var studentsArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive graduated students",
+	Long: `Move a graduated cohort (by student number prefix) and/or individual students to the
+archived status. Archived students keep their registration history but cannot register,
+drop or hold seats. Students already archived are skipped, so the job can be re-run.`,
+	Run: runStudentsArchive,
+}
+
+func init() {
+	rootCmd.AddCommand(studentsCmd)
+	studentsCmd.AddCommand(studentsArchiveCmd)
+	studentsArchiveCmd.Flags().StringVar(&archiveCohort, "cohort", "", "Archive every student whose student number starts with this prefix")
+	studentsArchiveCmd.Flags().StringSliceVar(&archiveStudentIDs, "student-id", nil, "Archive this student (repeatable)")
+}
+
+func runStudentsArchive(cmd *cobra.Command, args []string) {
+	cfg := config.Get()
+
+	req := &service.ArchiveStudentsRequest{Cohort: archiveCohort}
+	for _, raw := range archiveStudentIDs {
+		studentID, err := uuid.Parse(raw)
+		if err != nil {
+			logger.Error("Invalid student ID %q: %v", raw, err)
+			os.Exit(1)
+		}
+		req.StudentIDs = append(req.StudentIDs, studentID)
+	}
+
+	dbConfig := database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+
+	db, err := database.NewConnection(dbConfig)
+	if err != nil {
+		logger.Error("Failed to connect to database: %v", err)
+		os.Exit(1)
+	}
+
+	cacheService := cache.NewRedisCacheWithConfig(&cfg.Cache)
+	defer cacheService.Close()
+
+	studentService := service.NewStudentService(repository.NewStudentRepository(db), cacheService, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	result, err := studentService.ArchiveStudents(ctx, req)
+	if err != nil {
+		logger.Error("Failed to archive students: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Archived %d students\n", result.Archived)
+}
@@ -0,0 +1,26 @@
+package graphqlapi
+
+import (
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// maxQueryComplexity keeps a single dashboard query from fanning out into an expensive one
+const maxQueryComplexity = 500
+
+// NewHandler serves GraphQL over GET and POST. Every request gets its own section loader.
+func NewHandler(resolver *Resolver) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(maxQueryComplexity))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := withSectionLoader(r.Context(), resolver.sectionRepo, resolver.cacheService)
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
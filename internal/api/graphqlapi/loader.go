@@ -0,0 +1,96 @@
+package graphqlapi
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type sectionLoaderKey struct{}
+
+// sectionLoader loads the sections a request refers to in batches and remembers them for
+// the rest of the request, so a dashboard with many registrations costs one section query
+// instead of one per registration.
+type sectionLoader struct {
+	sectionRepo  interfaces.SectionRepository
+	cacheService interfaces.CacheService
+
+	mu       sync.Mutex
+	sections map[uuid.UUID]*domain.Section
+}
+
+func withSectionLoader(ctx context.Context, sectionRepo interfaces.SectionRepository, cacheService interfaces.CacheService) context.Context {
+	return context.WithValue(ctx, sectionLoaderKey{}, &sectionLoader{
+		sectionRepo:  sectionRepo,
+		cacheService: cacheService,
+		sections:     make(map[uuid.UUID]*domain.Section),
+	})
+}
+
+func sectionLoaderFrom(ctx context.Context) (*sectionLoader, error) {
+	loader, ok := ctx.Value(sectionLoaderKey{}).(*sectionLoader)
+	if !ok {
+		return nil, fmt.Errorf("section loader missing from request context")
+	}
+	return loader, nil
+}
+
+// Prime loads every section in ids that has not been loaded yet with a single query
+func (l *sectionLoader) Prime(ctx context.Context, ids []uuid.UUID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	missing := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := l.sections[id]; ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sections, err := l.sectionRepo.GetByIDs(ctx, missing)
+	if err != nil {
+		return fmt.Errorf("failed to load sections: %w", err)
+	}
+
+	// The seat cache is ahead of the database while registration is busy
+	seats, err := l.cacheService.GetAvailableSeatsBatch(ctx, missing)
+	if err != nil {
+		logger.Warn("GraphQL section loader could not read seat counters: %v", err)
+	}
+	for _, section := range sections {
+		if available, ok := seats[section.SectionID]; ok {
+			section.AvailableSeats = available
+		}
+		l.sections[section.SectionID] = section
+	}
+	for _, id := range missing {
+		if _, ok := l.sections[id]; !ok {
+			logger.Warn("GraphQL section loader could not find section %s", id)
+			l.sections[id] = nil
+		}
+	}
+
+	return nil
+}
+
+// Load returns a section, loading it on its own if it was not primed
+func (l *sectionLoader) Load(ctx context.Context, id uuid.UUID) (*domain.Section, error) {
+	if err := l.Prime(ctx, []uuid.UUID{id}); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sections[id], nil
+}
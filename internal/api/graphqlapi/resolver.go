@@ -0,0 +1,40 @@
+package graphqlapi
+
+//go:generate go tool gqlgen generate --config gqlgen.yml
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/internal/service"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Resolver is the root GraphQL resolver. Per-request state such as the section loader lives
+// in the request context, so one Resolver serves every request.
+type Resolver struct {
+	registrationService *service.RegistrationService
+	sectionRepo         interfaces.SectionRepository
+	cacheService        interfaces.CacheService
+}
+
+func NewResolver(
+	registrationService *service.RegistrationService,
+	sectionRepo interfaces.SectionRepository,
+	cacheService interfaces.CacheService,
+) *Resolver {
+	return &Resolver{
+		registrationService: registrationService,
+		sectionRepo:         sectionRepo,
+		cacheService:        cacheService,
+	}
+}
+
+// primeSections batch-loads the sections of a list before its items resolve them one by one
+func (r *Resolver) primeSections(ctx context.Context, sectionIDs []uuid.UUID) error {
+	loader, err := sectionLoaderFrom(ctx)
+	if err != nil {
+		return err
+	}
+	return loader.Prime(ctx, sectionIDs)
+}
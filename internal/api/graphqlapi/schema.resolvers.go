@@ -0,0 +1,111 @@
+package graphqlapi
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Student is the resolver for the student field.
+func (r *queryResolver) Student(ctx context.Context, id uuid.UUID) (*domain.Student, error) {
+	if err := auth.AuthorizeStudentRead(ctx, id); err != nil {
+		return nil, err
+	}
+
+	student, err := r.registrationService.GetStudentDetails(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return student, nil
+}
+
+// Status is the resolver for the status field.
+func (r *registrationResolver) Status(ctx context.Context, obj *domain.Registration) (string, error) {
+	return string(obj.Status), nil
+}
+
+// Section is the resolver for the section field.
+func (r *registrationResolver) Section(ctx context.Context, obj *domain.Registration) (*domain.Section, error) {
+	loader, err := sectionLoaderFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(ctx, obj.SectionID)
+}
+
+// Registrations is the resolver for the registrations field.
+func (r *studentResolver) Registrations(ctx context.Context, obj *domain.Student, status *string) ([]*domain.Registration, error) {
+	registrations, err := r.registrationService.GetStudentRegistrations(ctx, obj.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*domain.Registration, 0, len(registrations))
+	sectionIDs := make([]uuid.UUID, 0, len(registrations))
+	for _, registration := range registrations {
+		if status != nil && string(registration.Status) != *status {
+			continue
+		}
+		filtered = append(filtered, registration)
+		sectionIDs = append(sectionIDs, registration.SectionID)
+	}
+
+	if err := r.primeSections(ctx, sectionIDs); err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// Waitlist is the resolver for the waitlist field.
+func (r *studentResolver) Waitlist(ctx context.Context, obj *domain.Student) ([]*domain.WaitlistEntry, error) {
+	entries, err := r.registrationService.GetStudentWaitlistStatus(ctx, obj.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionIDs := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		sectionIDs = append(sectionIDs, entry.SectionID)
+	}
+
+	if err := r.primeSections(ctx, sectionIDs); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Section is the resolver for the section field.
+func (r *waitlistEntryResolver) Section(ctx context.Context, obj *domain.WaitlistEntry) (*domain.Section, error) {
+	loader, err := sectionLoaderFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(ctx, obj.SectionID)
+}
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Registration returns RegistrationResolver implementation.
+func (r *Resolver) Registration() RegistrationResolver { return &registrationResolver{r} }
+
+// Student returns StudentResolver implementation.
+func (r *Resolver) Student() StudentResolver { return &studentResolver{r} }
+
+// WaitlistEntry returns WaitlistEntryResolver implementation.
+func (r *Resolver) WaitlistEntry() WaitlistEntryResolver { return &waitlistEntryResolver{r} }
+
+type queryResolver struct{ *Resolver }
+type registrationResolver struct{ *Resolver }
+type studentResolver struct{ *Resolver }
+type waitlistEntryResolver struct{ *Resolver }
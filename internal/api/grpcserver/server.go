@@ -0,0 +1,308 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/service"
+	"cobra-template/pkg/logger"
+	"cobra-template/pkg/validator"
+	registrationv1 "cobra-template/proto/registration/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the gRPC RegistrationService on top of the same service layer the REST
+// handlers use.
+type Server struct {
+	registrationv1.UnimplementedRegistrationServiceServer
+	registrationService *service.RegistrationService
+}
+
+func NewServer(registrationService *service.RegistrationService) *Server {
+	return &Server{
+		registrationService: registrationService,
+	}
+}
+
+// New builds a gRPC server with the registration service, health checks and reflection
+// registered, so tools such as grpcurl work without the proto files. A nil authenticator
+// disables authentication, the same as on the REST side.
+func New(registrationService *service.RegistrationService, authenticator *auth.Authenticator) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(recoveryInterceptor, loggingInterceptor, authInterceptor(authenticator)))
+
+	registrationv1.RegisterRegistrationServiceServer(srv, NewServer(registrationService))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	reflection.Register(srv)
+
+	return srv
+}
+
+func (s *Server) Register(ctx context.Context, req *registrationv1.RegisterRequest) (*registrationv1.RegisterResponse, error) {
+	studentID, err := authorizedStudentID(ctx, req.GetStudentId())
+	if err != nil {
+		return nil, err
+	}
+
+	sectionIDs := make([]uuid.UUID, 0, len(req.GetSectionIds()))
+	for _, raw := range req.GetSectionIds() {
+		sectionID, err := parseID("section_ids", raw)
+		if err != nil {
+			return nil, err
+		}
+		sectionIDs = append(sectionIDs, sectionID)
+	}
+
+	registerReq := &service.RegisterRequest{
+		StudentID:      studentID,
+		SectionIDs:     sectionIDs,
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+	if err := validator.ValidateStruct(registerReq); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", validator.FormatValidationError(err))
+	}
+
+	response, err := s.registrationService.Register(ctx, registerReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	results := make([]*registrationv1.RegistrationResult, 0, len(response.Results))
+	for _, result := range response.Results {
+		pbResult := &registrationv1.RegistrationResult{
+			SectionId: result.SectionID.String(),
+			Status:    result.Status,
+			Message:   result.Message,
+		}
+		if result.Position != nil {
+			position := int32(*result.Position)
+			pbResult.WaitlistPosition = &position
+		}
+		results = append(results, pbResult)
+	}
+
+	return &registrationv1.RegisterResponse{Results: results}, nil
+}
+
+func (s *Server) DropCourse(ctx context.Context, req *registrationv1.DropCourseRequest) (*registrationv1.DropCourseResponse, error) {
+	studentID, err := authorizedStudentID(ctx, req.GetStudentId())
+	if err != nil {
+		return nil, err
+	}
+	sectionID, err := parseID("section_id", req.GetSectionId())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.registrationService.DropCourse(ctx, studentID, sectionID); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &registrationv1.DropCourseResponse{}, nil
+}
+
+func (s *Server) GetAvailableSections(ctx context.Context, req *registrationv1.GetAvailableSectionsRequest) (*registrationv1.GetAvailableSectionsResponse, error) {
+	semesterID, err := parseID("semester_id", req.GetSemesterId())
+	if err != nil {
+		return nil, err
+	}
+
+	sections, err := s.registrationService.GetAvailableSections(ctx, semesterID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	pbSections := make([]*registrationv1.Section, 0, len(sections))
+	for _, section := range sections {
+		pbSections = append(pbSections, toPBSection(section))
+	}
+
+	return &registrationv1.GetAvailableSectionsResponse{Sections: pbSections}, nil
+}
+
+func (s *Server) GetWaitlistStatus(ctx context.Context, req *registrationv1.GetWaitlistStatusRequest) (*registrationv1.GetWaitlistStatusResponse, error) {
+	studentID, err := authorizedStudentID(ctx, req.GetStudentId())
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.registrationService.GetStudentWaitlistStatus(ctx, studentID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	pbEntries := make([]*registrationv1.WaitlistEntry, 0, len(entries))
+	for _, entry := range entries {
+		pbEntries = append(pbEntries, toPBWaitlistEntry(entry))
+	}
+
+	return &registrationv1.GetWaitlistStatusResponse{Entries: pbEntries}, nil
+}
+
+func (s *Server) GetWaitlistPosition(ctx context.Context, req *registrationv1.GetWaitlistPositionRequest) (*registrationv1.GetWaitlistPositionResponse, error) {
+	studentID, err := authorizedStudentID(ctx, req.GetStudentId())
+	if err != nil {
+		return nil, err
+	}
+	sectionID, err := parseID("section_id", req.GetSectionId())
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.registrationService.GetStudentWaitlistStatus(ctx, studentID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	for _, entry := range entries {
+		if entry.SectionID == sectionID {
+			return &registrationv1.GetWaitlistPositionResponse{
+				Waitlisted: true,
+				Position:   int32(entry.Position),
+			}, nil
+		}
+	}
+
+	return &registrationv1.GetWaitlistPositionResponse{}, nil
+}
+
+func toPBSection(section *domain.Section) *registrationv1.Section {
+	return &registrationv1.Section{
+		SectionId:      section.SectionID.String(),
+		CourseId:       section.CourseID.String(),
+		SemesterId:     section.SemesterID.String(),
+		SectionNumber:  section.SectionNumber,
+		TotalSeats:     int32(section.TotalSeats),
+		AvailableSeats: int32(section.AvailableSeats),
+		CourseCode:     section.Course.CourseCode,
+		CourseName:     section.Course.CourseName,
+	}
+}
+
+func toPBWaitlistEntry(entry *domain.WaitlistEntry) *registrationv1.WaitlistEntry {
+	return &registrationv1.WaitlistEntry{
+		WaitlistId: entry.WaitlistID.String(),
+		SectionId:  entry.SectionID.String(),
+		Position:   int32(entry.Position),
+		JoinedAt:   timestamppb.New(entry.Timestamp),
+	}
+}
+
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.UUID{}, status.Errorf(codes.InvalidArgument, "invalid %s: %q", field, value)
+	}
+	return id, nil
+}
+
+// authorizedStudentID parses the student_id of a request and checks the caller may act for
+// that student
+func authorizedStudentID(ctx context.Context, value string) (uuid.UUID, error) {
+	studentID, err := parseID("student_id", value)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if err := auth.AuthorizeStudent(ctx, studentID); err != nil {
+		if errors.Is(err, auth.ErrMissingToken) {
+			return uuid.UUID{}, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return uuid.UUID{}, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return studentID, nil
+}
+
+// toStatus maps service errors to gRPC codes the same way the REST handlers map them to
+// HTTP statuses.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, service.ErrStudentBusy):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, service.ErrIdempotencyKeyConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrStudentArchived), errors.Is(err, service.ErrStudentOnHold):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case strings.Contains(err.Error(), "not found"):
+		return status.Error(codes.NotFound, err.Error())
+	case strings.Contains(err.Error(), "not in active status"), strings.Contains(err.Error(), "can only drop"),
+		errors.Is(err, service.ErrWithdrawDeadlinePassed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logger.Info("gRPC %s %s %s", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+// authInterceptor verifies the bearer token in the authorization metadata of registration
+// calls. Health checks and reflection stay open so probes and grpcurl keep working.
+func authInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	servicePrefix := "/" + registrationv1.RegistrationService_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, servicePrefix) {
+			return handler(ctx, req)
+		}
+		if authenticator == nil {
+			return handler(auth.NewContext(ctx, auth.Anonymous()), req)
+		}
+
+		token := metadataBearerToken(ctx)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error())
+		}
+		claims, err := authenticator.Verify(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(auth.NewContext(ctx, claims), req)
+	}
+}
+
+func metadataBearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		if token, found := strings.CutPrefix(value, "Bearer "); found {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// recoveryInterceptor turns a panic in a handler into an Internal error instead of taking
+// the whole process down, matching the gin recovery middleware on the REST side.
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("gRPC %s panicked: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, fmt.Sprintf("internal error: %v", r))
+		}
+	}()
+	return handler(ctx, req)
+}
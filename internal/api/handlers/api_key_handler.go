@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// IssueAPIKey creates a key for a machine client. The plaintext key is only in this response.
+func (h *APIKeyHandler) IssueAPIKey(c *gin.Context) {
+	var req service.IssueAPIKeyRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	issued, err := h.apiKeyService.Issue(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(apiKeyErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to issue API key",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "API key issued; store it now, it is not shown again",
+		Data:    issued,
+	})
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), c.Query("include_revoked") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list API keys",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+// RotateAPIKey issues a replacement key; the old one keeps working for the grace period
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	keyID, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	var req service.RotateAPIKeyRequest
+	if c.Request.ContentLength != 0 && !bindAndValidate(c, &req) {
+		return
+	}
+
+	issued, err := h.apiKeyService.Rotate(c.Request.Context(), keyID, &req)
+	if err != nil {
+		c.JSON(apiKeyErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to rotate API key",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "API key rotated; store the new key now, it is not shown again",
+		Data:    issued,
+	})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyID, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), keyID); err != nil {
+		c.JSON(apiKeyErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to revoke API key",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "API key revoked",
+	})
+}
+
+func parseAPIKeyID(c *gin.Context) (uuid.UUID, bool) {
+	keyID, err := uuid.Parse(c.Param("key_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid API key ID format",
+		})
+		return uuid.UUID{}, false
+	}
+	return keyID, true
+}
+
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrUnknownScope):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrAPIKeyRevoked):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApprovalHandler struct {
+	approvalService *service.ApprovalService
+}
+
+func NewApprovalHandler(approvalService *service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+func (h *ApprovalHandler) ProposeOperation(c *gin.Context) {
+	var req service.ProposeApprovalRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	approval, err := h.approvalService.Propose(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(approvalErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to propose operation",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Operation proposed; it runs once another admin approves it",
+		Data:    approval,
+	})
+}
+
+func (h *ApprovalHandler) ListApprovals(c *gin.Context) {
+	approvals, err := h.approvalService.ListApprovals(c.Request.Context(), domain.ApprovalStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list approvals",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Approvals retrieved successfully",
+		Data:    approvals,
+	})
+}
+
+func (h *ApprovalHandler) GetApproval(c *gin.Context) {
+	approvalID, ok := parseApprovalID(c)
+	if !ok {
+		return
+	}
+
+	approval, err := h.approvalService.GetApproval(c.Request.Context(), approvalID)
+	if err != nil {
+		c.JSON(approvalErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get approval",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Approval retrieved successfully",
+		Data:    approval,
+	})
+}
+
+// Approve confirms the proposal and runs the operation. An operation that fails after
+// approval is reported on the approval rather than as an error, since it cannot be retried.
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	approvalID, ok := parseApprovalID(c)
+	if !ok {
+		return
+	}
+
+	approval, err := h.approvalService.Approve(c.Request.Context(), approvalID)
+	if err != nil {
+		c.JSON(approvalErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to approve operation",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if approval.Status == domain.ApprovalFailed {
+		c.JSON(http.StatusOK, APIResponse{
+			Success: false,
+			Message: "Operation approved but failed",
+			Data:    approval,
+			Errors:  approval.Result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Operation approved and executed",
+		Data:    approval,
+	})
+}
+
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	approvalID, ok := parseApprovalID(c)
+	if !ok {
+		return
+	}
+
+	approval, err := h.approvalService.Reject(c.Request.Context(), approvalID)
+	if err != nil {
+		c.JSON(approvalErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to reject operation",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Operation rejected",
+		Data:    approval,
+	})
+}
+
+func parseApprovalID(c *gin.Context) (uuid.UUID, bool) {
+	approvalID, err := uuid.Parse(c.Param("approval_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid approval ID format",
+		})
+		return uuid.UUID{}, false
+	}
+	return approvalID, true
+}
+
+func approvalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrApprovalNotFound),
+		errors.Is(err, service.ErrSectionNotFound),
+		errors.Is(err, service.ErrSemesterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrApprovalNotPending),
+		errors.Is(err, service.ErrApprovalPending):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrApprovalExpired):
+		return http.StatusGone
+	case errors.Is(err, service.ErrSelfApproval),
+		errors.Is(err, service.ErrAnonymousApprover):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrUnknownOperation):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
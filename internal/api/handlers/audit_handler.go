@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEvents returns audit events, newest first, filtered by the optional student_id,
+// section_id, action and correlation_id, and by from and to as RFC3339 timestamps
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid audit filter",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	events, err := h.auditService.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidAuditRange) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to list audit events",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Audit events retrieved successfully",
+		Data:    map[string]interface{}{"events": events, "count": len(events)},
+	})
+}
+
+func parseAuditFilter(c *gin.Context) (service.AuditEventFilter, error) {
+	filter := service.AuditEventFilter{
+		Action:        domain.AuditAction(c.Query("action")),
+		CorrelationID: c.Query("correlation_id"),
+	}
+
+	if raw := c.Query("student_id"); raw != "" {
+		studentID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("student_id must be a UUID")
+		}
+		filter.StudentID = &studentID
+	}
+	if raw := c.Query("section_id"); raw != "" {
+		sectionID, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("section_id must be a UUID")
+		}
+		filter.SectionID = &sectionID
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, errors.New("from must be an RFC3339 timestamp")
+		}
+		filter.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, errors.New("to must be an RFC3339 timestamp")
+		}
+		filter.To = &to
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return filter, errors.New("limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	bursarSignatureHeader = "X-Bursar-Signature"
+	bursarTimestampHeader = "X-Bursar-Timestamp"
+	maxWebhookBodyBytes   = 64 << 10
+)
+
+var (
+	errWebhookSignatureMissing = errors.New("missing signature or timestamp")
+	errWebhookSignatureStale   = errors.New("signature timestamp is outside the allowed window")
+	errWebhookSignatureInvalid = errors.New("signature does not match")
+)
+
+// BillingWebhookHandler receives the bursar's callbacks. They carry no token; instead the
+// bursar signs "<timestamp>.<body>" with the shared secret using HMAC-SHA256 and sends the
+// hex digest in X-Bursar-Signature and the unix timestamp in X-Bursar-Timestamp.
+type BillingWebhookHandler struct {
+	holdService *service.StudentHoldService
+	secret      []byte
+	tolerance   time.Duration
+}
+
+func NewBillingWebhookHandler(holdService *service.StudentHoldService, secret string, tolerance time.Duration) *BillingWebhookHandler {
+	return &BillingWebhookHandler{
+		holdService: holdService,
+		secret:      []byte(secret),
+		tolerance:   tolerance,
+	}
+}
+
+func (h *BillingWebhookHandler) HandleHoldEvent(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodyBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Failed to read request body",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if len(body) > maxWebhookBodyBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, APIResponse{
+			Success: false,
+			Message: "Request body too large",
+		})
+		return
+	}
+
+	if err := h.verifySignature(c.GetHeader(bursarTimestampHeader), c.GetHeader(bursarSignatureHeader), body); err != nil {
+		c.JSON(http.StatusUnauthorized, APIResponse{
+			Success: false,
+			Message: "Invalid webhook signature",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	var event service.BursarHoldEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid request format",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &event) {
+		return
+	}
+
+	hold, err := h.holdService.ApplyBursarEvent(c.Request.Context(), &event)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to apply hold event",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Hold event applied",
+		Data:    hold,
+	})
+}
+
+// verifySignature checks the signature against the shared secret and refuses timestamps
+// outside the tolerance, so a captured callback cannot be replayed later
+func (h *BillingWebhookHandler) verifySignature(timestamp, signature string, body []byte) error {
+	if timestamp == "" || signature == "" {
+		return errWebhookSignatureMissing
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errWebhookSignatureMissing
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > h.tolerance || age < -h.tolerance {
+		return errWebhookSignatureStale
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errWebhookSignatureInvalid
+	}
+	return nil
+}
+
+func holdErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrStudentNotFound), errors.Is(err, service.ErrHoldNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrHoldOtherStudent):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CacheAdminHandler struct {
+	cacheWarmer  *service.SectionCacheWarmer
+	cacheService interfaces.CacheService
+}
+
+func NewCacheAdminHandler(cacheWarmer *service.SectionCacheWarmer, cacheService interfaces.CacheService) *CacheAdminHandler {
+	return &CacheAdminHandler{
+		cacheWarmer:  cacheWarmer,
+		cacheService: cacheService,
+	}
+}
+
+// Warmup reseeds the section caches of every active semester, or of the one given as
+// ?semester_id=
+func (h *CacheAdminHandler) Warmup(c *gin.Context) {
+	if semesterIDStr := c.Query("semester_id"); semesterIDStr != "" {
+		semesterID, err := uuid.Parse(semesterIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "Invalid semester ID format",
+			})
+			return
+		}
+
+		result, err := h.cacheWarmer.WarmSemester(c.Request.Context(), semesterID)
+		if err != nil {
+			c.JSON(semesterErrorStatus(err), APIResponse{
+				Success: false,
+				Message: "Failed to warm semester caches",
+				Errors:  err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{
+			Success: true,
+			Message: "Semester caches warmed",
+			Data:    result,
+		})
+		return
+	}
+
+	results, err := h.cacheWarmer.WarmActiveSemesters(c.Request.Context())
+	h.respondWarmup(c, results, err)
+}
+
+// WarmupForLoadTest warms every active semester including section details
+func (h *CacheAdminHandler) WarmupForLoadTest(c *gin.Context) {
+	results, err := h.cacheWarmer.WarmForLoadTest(c.Request.Context())
+	h.respondWarmup(c, results, err)
+}
+
+// respondWarmup reports the semesters that were warmed even when others failed
+func (h *CacheAdminHandler) respondWarmup(c *gin.Context, results []service.SemesterWarmup, err error) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to warm some caches",
+			Data:    results,
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Caches warmed",
+		Data:    results,
+	})
+}
+
+func (h *CacheAdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.cacheService.GetCacheStats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get cache statistics",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Cache statistics retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// GetLoadTestStatus reports whether the cache is warm enough to start a load test. It
+// answers 503 until it is, so scripts can poll it before starting.
+func (h *CacheAdminHandler) GetLoadTestStatus(c *gin.Context) {
+	report, err := h.cacheWarmer.Readiness(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to check cache readiness",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if !report.Ready {
+		c.JSON(http.StatusServiceUnavailable, APIResponse{
+			Success: false,
+			Message: "Cache is not ready for load testing",
+			Data:    report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Cache is ready for load testing",
+		Data:    report,
+	})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CourseAdminHandler struct {
+	courseService *service.CourseService
+}
+
+func NewCourseAdminHandler(courseService *service.CourseService) *CourseAdminHandler {
+	return &CourseAdminHandler{
+		courseService: courseService,
+	}
+}
+
+func (h *CourseAdminHandler) CreateCourse(c *gin.Context) {
+	var req service.CreateCourseRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	course, err := h.courseService.CreateCourse(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(courseErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to create course",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Course created successfully",
+		Data:    course,
+	})
+}
+
+func (h *CourseAdminHandler) ListCourses(c *gin.Context) {
+	courses, err := h.courseService.ListCourses(c.Request.Context(), c.Query("include_inactive") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list courses",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Courses retrieved successfully",
+		Data:    courses,
+	})
+}
+
+func (h *CourseAdminHandler) GetCourse(c *gin.Context) {
+	courseID, ok := parseCourseID(c)
+	if !ok {
+		return
+	}
+
+	course, err := h.courseService.GetCourse(c.Request.Context(), courseID)
+	if err != nil {
+		c.JSON(courseErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get course",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Course retrieved successfully",
+		Data:    course,
+	})
+}
+
+func (h *CourseAdminHandler) UpdateCourse(c *gin.Context) {
+	courseID, ok := parseCourseID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateCourseRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	course, err := h.courseService.UpdateCourse(c.Request.Context(), courseID, &req)
+	if err != nil {
+		c.JSON(courseErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update course",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Course updated successfully",
+		Data:    course,
+	})
+}
+
+func (h *CourseAdminHandler) DeactivateCourse(c *gin.Context) {
+	courseID, ok := parseCourseID(c)
+	if !ok {
+		return
+	}
+
+	course, err := h.courseService.DeactivateCourse(c.Request.Context(), courseID)
+	if err != nil {
+		c.JSON(courseErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to deactivate course",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Course deactivated",
+		Data:    course,
+	})
+}
+
+func parseCourseID(c *gin.Context) (uuid.UUID, bool) {
+	courseID, err := uuid.Parse(c.Param("course_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid course ID format",
+		})
+		return uuid.UUID{}, false
+	}
+	return courseID, true
+}
+
+func courseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCourseNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCourseExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
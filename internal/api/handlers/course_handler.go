@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+	"cobra-template/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseHandler struct {
+	courseService *service.CourseService
+}
+
+func NewCourseHandler(courseService *service.CourseService) *CourseHandler {
+	return &CourseHandler{
+		courseService: courseService,
+	}
+}
+
+// SearchCourses handles GET /courses/search, e.g.
+// ?code=CS1&department=cs&min_credits=3&days=MWF&start_after=09:00&has_seats=true&limit=20.
+// Pass next_cursor back as cursor for the next page.
+func (h *CourseHandler) SearchCourses(c *gin.Context) {
+	var req service.CourseSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid search query",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Validation failed",
+			Errors:  validator.FormatValidationError(err),
+		})
+		return
+	}
+
+	page, err := h.courseService.Search(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidCursor) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to search courses",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Courses retrieved successfully",
+		Data:    page,
+	})
+}
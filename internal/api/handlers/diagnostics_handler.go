@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultProfileSeconds = 30
+	profileKeyPrefix      = "profiles/"
+)
+
+type DiagnosticsHandler struct {
+	storage           interfaces.StorageService
+	signedURLExpiry   time.Duration
+	maxProfileSeconds int
+	startedAt         time.Time
+}
+
+func NewDiagnosticsHandler(cfg *config.DiagnosticsConfig, storage interfaces.StorageService, signedURLExpiry time.Duration) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		storage:           storage,
+		signedURLExpiry:   signedURLExpiry,
+		maxProfileSeconds: cfg.MaxProfileSeconds,
+		startedAt:         time.Now(),
+	}
+}
+
+type RuntimeStats struct {
+	Uptime        string    `json:"uptime"`
+	GoVersion     string    `json:"go_version"`
+	NumCPU        int       `json:"num_cpu"`
+	GOMAXPROCS    int       `json:"gomaxprocs"`
+	NumGoroutine  int       `json:"num_goroutine"`
+	HeapAlloc     uint64    `json:"heap_alloc_bytes"`
+	HeapInuse     uint64    `json:"heap_inuse_bytes"`
+	HeapObjects   uint64    `json:"heap_objects"`
+	Sys           uint64    `json:"sys_bytes"`
+	NumGC         uint32    `json:"num_gc"`
+	PauseTotal    string    `json:"gc_pause_total"`
+	LastGC        time.Time `json:"last_gc"`
+	GCCPUFraction float64   `json:"gc_cpu_fraction"`
+}
+
+type ProfileInfo struct {
+	Name        string    `json:"name"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+	DownloadURL string    `json:"download_url,omitempty"`
+}
+
+func (h *DiagnosticsHandler) GetRuntimeStats(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := RuntimeStats{
+		Uptime:        time.Since(h.startedAt).Round(time.Second).String(),
+		GoVersion:     runtime.Version(),
+		NumCPU:        runtime.NumCPU(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		NumGoroutine:  runtime.NumGoroutine(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapInuse:     mem.HeapInuse,
+		HeapObjects:   mem.HeapObjects,
+		Sys:           mem.Sys,
+		NumGC:         mem.NumGC,
+		PauseTotal:    time.Duration(mem.PauseTotalNs).String(),
+		GCCPUFraction: mem.GCCPUFraction,
+	}
+	if mem.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC))
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Runtime statistics retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// CaptureProfile records a profile and stores it in the profile directory. CPU and trace
+// profiles run for the requested number of seconds, the others are point-in-time snapshots.
+func (h *DiagnosticsHandler) CaptureProfile(c *gin.Context) {
+	profileType := c.DefaultQuery("type", "cpu")
+
+	seconds := defaultProfileSeconds
+	if raw := c.Query("seconds"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "seconds must be a positive integer",
+			})
+			return
+		}
+		seconds = parsed
+	}
+	if h.maxProfileSeconds > 0 && seconds > h.maxProfileSeconds {
+		seconds = h.maxProfileSeconds
+	}
+
+	if profileType != "cpu" && profileType != "trace" && pprof.Lookup(profileType) == nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: fmt.Sprintf("Unknown profile type: %s", profileType),
+		})
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s.pprof", profileType, time.Now().UTC().Format("20060102T150405Z"))
+	if profileType == "trace" {
+		name = strings.TrimSuffix(name, ".pprof") + ".trace"
+	}
+
+	// Profiles are written to a temporary file and uploaded to storage once complete
+	file, err := os.CreateTemp("", "profile-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to create profile file",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	duration := time.Duration(seconds) * time.Second
+	logger.Info("Capturing %s profile %s", profileType, name)
+
+	switch profileType {
+	case "cpu":
+		if err := pprof.StartCPUProfile(file); err != nil {
+			c.JSON(http.StatusConflict, APIResponse{
+				Success: false,
+				Message: "CPU profile already in progress",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		h.wait(c, duration)
+		pprof.StopCPUProfile()
+	case "trace":
+		if err := trace.Start(file); err != nil {
+			c.JSON(http.StatusConflict, APIResponse{
+				Success: false,
+				Message: "Execution trace already in progress",
+				Errors:  err.Error(),
+			})
+			return
+		}
+		h.wait(c, duration)
+		trace.Stop()
+	default:
+		if profileType == "heap" {
+			runtime.GC()
+		}
+		if err := pprof.Lookup(profileType).WriteTo(file, 0); err != nil {
+			c.JSON(http.StatusInternalServerError, APIResponse{
+				Success: false,
+				Message: "Failed to write profile",
+				Errors:  err.Error(),
+			})
+			return
+		}
+	}
+
+	info, err := file.Stat()
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to read profile file",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	ctx := context.WithoutCancel(c.Request.Context())
+	if err := h.storage.Put(ctx, profileKeyPrefix+name, file, info.Size(), "application/octet-stream"); err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to store profile",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	profile := ProfileInfo{
+		Name:      name,
+		SizeBytes: info.Size(),
+		CreatedAt: time.Now(),
+	}
+	if downloadURL, err := h.storage.SignedURL(ctx, profileKeyPrefix+name, h.signedURLExpiry); err == nil {
+		profile.DownloadURL = downloadURL
+	} else {
+		logger.Warn("Failed to sign download URL for profile %s: %v", name, err)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Profile captured successfully",
+		Data:    profile,
+	})
+}
+
+func (h *DiagnosticsHandler) ListProfiles(c *gin.Context) {
+	objects, err := h.storage.List(c.Request.Context(), profileKeyPrefix)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list profiles",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	profiles := make([]ProfileInfo, 0, len(objects))
+	for _, object := range objects {
+		profiles = append(profiles, ProfileInfo{
+			Name:      strings.TrimPrefix(object.Key, profileKeyPrefix),
+			SizeBytes: object.Size,
+			CreatedAt: object.LastModified,
+		})
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].CreatedAt.After(profiles[j].CreatedAt)
+	})
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Profiles retrieved successfully",
+		Data:    map[string]any{"profiles": profiles},
+	})
+}
+
+func (h *DiagnosticsHandler) DownloadProfile(c *gin.Context) {
+	name := path.Base(c.Param("name"))
+
+	reader, info, err := h.storage.Get(c.Request.Context(), profileKeyPrefix+name)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrObjectNotFound) {
+			c.JSON(http.StatusNotFound, APIResponse{
+				Success: false,
+				Message: "Profile not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to read profile",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, info.Size, "application/octet-stream", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", name),
+	})
+}
+
+// wait blocks for the profiling duration or until the client goes away
+func (h *DiagnosticsHandler) wait(c *gin.Context, duration time.Duration) {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-c.Request.Context().Done():
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ExportHandler struct {
+	exportService *service.ExportService
+	storage       interfaces.StorageService
+}
+
+func NewExportHandler(exportService *service.ExportService, storage interfaces.StorageService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		storage:       storage,
+	}
+}
+
+func (h *ExportHandler) ExportSectionRegistrations(c *gin.Context) {
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return
+	}
+
+	result, err := h.exportService.ExportSectionRegistrations(c.Request.Context(), sectionID)
+	if err != nil {
+		if errors.Is(err, service.ErrExportSectionNotFound) {
+			c.JSON(http.StatusNotFound, APIResponse{
+				Success: false,
+				Message: "Section not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to export section registrations",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section registrations exported successfully",
+		Data:    result,
+	})
+}
+
+// DownloadFile serves objects of the local storage backend behind signed URLs. Cloud
+// backends hand out provider signed URLs instead, so the route reports 404 for them.
+func (h *ExportHandler) DownloadFile(c *gin.Context) {
+	verifier, ok := h.storage.(interfaces.SignedURLVerifier)
+	if !ok {
+		c.JSON(http.StatusNotFound, APIResponse{
+			Success: false,
+			Message: "File downloads are served by the storage provider",
+		})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid or missing expires parameter",
+		})
+		return
+	}
+
+	if err := verifier.VerifySignedURL(key, expires, c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, APIResponse{
+			Success: false,
+			Message: "Download link is invalid or has expired",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	reader, info, err := h.storage.Get(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrObjectNotFound) {
+			c.JSON(http.StatusNotFound, APIResponse{
+				Success: false,
+				Message: "File not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to read file",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer reader.Close()
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size, contentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", path.Base(key)),
+	})
+}
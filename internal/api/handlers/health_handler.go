@@ -5,50 +5,95 @@ import (
 	"time"
 
 	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
-type HealthHandler struct{}
+// HealthHandler serves the probes. While the queue drains on shutdown the instance reports
+// itself not ready, so load balancers stop sending it registrations during a rolling deploy.
+// Otherwise it is ready unless Postgres or Redis is down; a degraded instance stays ready.
+type HealthHandler struct {
+	healthService *service.HealthService
+	queue         interfaces.DrainableQueue
+}
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+// NewHealthHandler creates the handler; queue may be nil when the queue cannot drain
+func NewHealthHandler(healthService *service.HealthService, queue interfaces.DrainableQueue) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+		queue:         queue,
+	}
 }
 
 type HealthResponse struct {
-	Status    string            `json:"status"`
-	Timestamp time.Time         `json:"timestamp"`
-	Version   string            `json:"version"`
-	Services  map[string]string `json:"services"`
+	Status       string                             `json:"status"`
+	Degraded     bool                               `json:"degraded"`
+	Timestamp    time.Time                          `json:"timestamp"`
+	Version      string                             `json:"version"`
+	Dependencies map[string]service.DependencyCheck `json:"dependencies"`
+	Drain        *interfaces.DrainStatus            `json:"drain,omitempty"`
+}
+
+// drainStatus reports the queue drain, or nil when no drain has started
+func (h *HealthHandler) drainStatus() *interfaces.DrainStatus {
+	if h.queue == nil {
+		return nil
+	}
+	status := h.queue.DrainStatus()
+	if status.StartedAt == nil {
+		return nil
+	}
+	return &status
 }
 
+// HealthCheck checks every dependency and reports each one, answering 503 when the instance
+// cannot take registrations
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	cfg := config.Get()
 
-	services := make(map[string]string)
-
-	services["database"] = "healthy"
-
-	services["cache"] = "healthy"
-
+	report := h.healthService.Check(c.Request.Context())
 	response := HealthResponse{
-		Status:    "healthy",
-		Timestamp: time.Now(),
-		Version:   cfg.App.Version,
-		Services:  services,
+		Status:       report.Status,
+		Degraded:     report.Degraded,
+		Timestamp:    report.CheckedAt,
+		Version:      cfg.App.Version,
+		Dependencies: report.Dependencies,
+		Drain:        h.drainStatus(),
 	}
 
-	c.JSON(http.StatusOK, response)
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
 }
 
 func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
+	if drain := h.drainStatus(); drain != nil {
+		c.JSON(http.StatusServiceUnavailable, map[string]any{
+			"ready":     false,
+			"timestamp": time.Now(),
+			"drain":     drain,
+		})
+		return
+	}
 
+	report := h.healthService.Check(c.Request.Context())
 	response := map[string]any{
-		"ready":     true,
-		"timestamp": time.Now(),
+		"ready":        report.Ready(),
+		"status":       report.Status,
+		"degraded":     report.Degraded,
+		"dependencies": report.Dependencies,
+		"timestamp":    report.CheckedAt,
 	}
 
-	c.JSON(http.StatusOK, response)
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
 }
 
 func (h *HealthHandler) LivenessCheck(c *gin.Context) {
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// ImportRegistrations registers students from a CSV of student_number, course_code and
+// section_number. The file is sent either as the "file" field of a multipart form or as the
+// raw text/csv body. With dry_run=true the rows are only checked for eligibility.
+func (h *ImportHandler) ImportRegistrations(c *gin.Context) {
+	var opts service.ImportOptions
+
+	if raw := c.Query("semester_id"); raw != "" {
+		semesterID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "Invalid semester ID format",
+			})
+			return
+		}
+		opts.SemesterID = semesterID
+	}
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "dry_run must be true or false",
+			})
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	body, err := importBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid upload",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer body.Close()
+
+	report, err := h.importService.ImportRegistrations(c.Request.Context(), body, opts)
+	if err != nil {
+		c.JSON(importErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to import registrations",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	message := "Registrations imported"
+	if opts.DryRun {
+		message = "Registration import checked (dry run)"
+	}
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// importBody opens the uploaded CSV without buffering the whole file in memory
+func importBody(c *gin.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("multipart form has no file field")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+func importErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrImportHeader), errors.Is(err, service.ErrImportTooLarge):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrSemesterNotFound), errors.Is(err, service.ErrNoSemester):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
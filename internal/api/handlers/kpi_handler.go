@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KPIHandler struct {
+	kpiService *service.KPIService
+}
+
+func NewKPIHandler(kpiService *service.KPIService) *KPIHandler {
+	return &KPIHandler{
+		kpiService: kpiService,
+	}
+}
+
+// GetKPIs returns the live registration counters. window_minutes sets how many complete
+// minutes the per-minute series covers.
+func (h *KPIHandler) GetKPIs(c *gin.Context) {
+	window, err := strconv.Atoi(c.DefaultQuery("window_minutes", strconv.Itoa(service.DefaultKPIWindowMinutes)))
+	if err != nil || window <= 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "window_minutes must be a positive integer",
+		})
+		return
+	}
+	if window > service.MaxKPIWindowMinutes {
+		window = service.MaxKPIWindowMinutes
+	}
+
+	snapshot, err := h.kpiService.GetKPIs(c.Request.Context(), window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get KPIs",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "KPIs retrieved successfully",
+		Data:    snapshot,
+	})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionNumberHandler lets staff issue the permission numbers instructors hand to
+// students who may register past consent requirements and reserved seats
+type PermissionNumberHandler struct {
+	registrationService *service.RegistrationService
+}
+
+func NewPermissionNumberHandler(registrationService *service.RegistrationService) *PermissionNumberHandler {
+	return &PermissionNumberHandler{
+		registrationService: registrationService,
+	}
+}
+
+func (h *PermissionNumberHandler) Issue(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.IssuePermissionNumbersRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	permissions, err := h.registrationService.IssuePermissionNumbers(c.Request.Context(), sectionID, &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrSectionNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrInvalidPermissionExpiry):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to issue permission numbers",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Permission numbers issued successfully",
+		Data:    permissions,
+	})
+}
+
+func (h *PermissionNumberHandler) List(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	permissions, err := h.registrationService.ListPermissionNumbers(c.Request.Context(), sectionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list permission numbers",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Permission numbers retrieved successfully",
+		Data:    permissions,
+	})
+}
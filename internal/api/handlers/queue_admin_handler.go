@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 500
+)
+
+type QueueAdminHandler struct {
+	names           interfaces.QueueNames
+	deadLetterQueue interfaces.DeadLetterQueue
+	poisonQueue     interfaces.PoisonQueue
+}
+
+// NewQueueAdminHandler builds the queue admin handler. Queue implementations without a
+// dead letter or poison queue are reported as unsupported by the endpoints.
+func NewQueueAdminHandler(queueService interfaces.QueueService) *QueueAdminHandler {
+	deadLetterQueue, _ := queueService.(interfaces.DeadLetterQueue)
+	poisonQueue, _ := queueService.(interfaces.PoisonQueue)
+	return &QueueAdminHandler{
+		names:           queueService.Names(),
+		deadLetterQueue: deadLetterQueue,
+		poisonQueue:     poisonQueue,
+	}
+}
+
+type ReplayDeadLetterRequest struct {
+	JobIDs []string `json:"job_ids"`
+}
+
+// GetQueueNames reports the configured queue names, including any environment prefix
+func (h *QueueAdminHandler) GetQueueNames(c *gin.Context) {
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Queue names retrieved successfully",
+		Data:    h.names,
+	})
+}
+
+func (h *QueueAdminHandler) GetDeadLetterJobs(c *gin.Context) {
+	if !h.requireDeadLetterQueue(c) {
+		return
+	}
+
+	offset, limit, ok := parseQueuePage(c)
+	if !ok {
+		return
+	}
+
+	jobs, total, err := h.deadLetterQueue.ListDeadDatabaseSyncJobs(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get dead letter jobs",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Dead letter jobs retrieved successfully",
+		Data: map[string]any{
+			"queue":  h.names.DatabaseSync,
+			"jobs":   jobs,
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// ReplayDeadLetterJobs moves dead jobs back onto the database sync queue. An empty job_ids
+// list replays every dead job.
+func (h *QueueAdminHandler) ReplayDeadLetterJobs(c *gin.Context) {
+	if !h.requireDeadLetterQueue(c) {
+		return
+	}
+
+	var req ReplayDeadLetterRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "Invalid request format",
+				Errors:  err.Error(),
+			})
+			return
+		}
+	}
+
+	replayed, err := h.deadLetterQueue.ReplayDeadDatabaseSyncJobs(c.Request.Context(), req.JobIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to replay dead letter jobs",
+			Errors:  err.Error(),
+			Data:    map[string]any{"replayed": replayed},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Dead letter jobs replayed successfully",
+		Data:    map[string]any{"replayed": replayed},
+	})
+}
+
+// GetPoisonJobs lists jobs whose handler panicked. They are kept for inspection only and
+// are never retried automatically.
+func (h *QueueAdminHandler) GetPoisonJobs(c *gin.Context) {
+	if h.poisonQueue == nil {
+		c.JSON(http.StatusNotImplemented, APIResponse{
+			Success: false,
+			Message: "Poison queue is not supported by the configured queue",
+		})
+		return
+	}
+
+	offset, limit, ok := parseQueuePage(c)
+	if !ok {
+		return
+	}
+
+	jobs, total, err := h.poisonQueue.ListPoisonJobs(c.Request.Context(), offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get poison jobs",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Poison jobs retrieved successfully",
+		Data: map[string]any{
+			"queue":  h.names.Poison,
+			"jobs":   jobs,
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+func parseQueuePage(c *gin.Context) (int, int, bool) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "offset must be a non-negative integer",
+		})
+		return 0, 0, false
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDeadLetterLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "limit must be a positive integer",
+		})
+		return 0, 0, false
+	}
+	if limit > maxDeadLetterLimit {
+		limit = maxDeadLetterLimit
+	}
+
+	return offset, limit, true
+}
+
+func (h *QueueAdminHandler) requireDeadLetterQueue(c *gin.Context) bool {
+	if h.deadLetterQueue == nil {
+		c.JSON(http.StatusNotImplemented, APIResponse{
+			Success: false,
+			Message: "Dead letter queue is not supported by the configured queue",
+		})
+		return false
+	}
+	return true
+}
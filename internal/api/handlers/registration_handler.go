@@ -1,8 +1,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
 	"cobra-template/internal/service"
 	"cobra-template/pkg/validator"
 
@@ -17,34 +26,27 @@ type APIResponse struct {
 	Errors  any    `json:"errors,omitempty"`
 }
 
+// RegistrationHandler serves the registration endpoints. With strictJSON, request bodies
+// carrying fields the endpoint does not know are rejected rather than silently ignored.
 type RegistrationHandler struct {
 	registrationService *service.RegistrationService
+	strictJSON          bool
 }
 
-func NewRegistrationHandler(registrationService *service.RegistrationService) *RegistrationHandler {
+func NewRegistrationHandler(registrationService *service.RegistrationService, strictJSON bool) *RegistrationHandler {
 	return &RegistrationHandler{
 		registrationService: registrationService,
+		strictJSON:          strictJSON,
 	}
 }
 
 func (h *RegistrationHandler) Register(c *gin.Context) {
 	var req service.RegisterRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, APIResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Errors:  err.Error(),
-		})
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
-	if req.IdempotencyKey == "" {
-		if headerKey := c.GetHeader("Idempotency-Key"); headerKey != "" {
-			req.IdempotencyKey = headerKey
-		}
-	}
-
 	if err := validator.ValidateStruct(&req); err != nil {
 		validationErrors := validator.FormatValidationError(err)
 		c.JSON(http.StatusBadRequest, APIResponse{
@@ -55,9 +57,13 @@ func (h *RegistrationHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if !authorizeStudent(c, req.StudentID) {
+		return
+	}
+
 	response, err := h.registrationService.Register(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, APIResponse{
+		c.JSON(registrationErrorStatus(err), APIResponse{
 			Success: false,
 			Message: "Registration failed",
 			Errors:  err.Error(),
@@ -80,12 +86,7 @@ func (h *RegistrationHandler) DropCourse(c *gin.Context) {
 
 	var req DropRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, APIResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Errors:  err.Error(),
-		})
+	if !h.bindJSON(c, &req) {
 		return
 	}
 	if err := validator.ValidateStruct(&req); err != nil {
@@ -97,9 +98,12 @@ func (h *RegistrationHandler) DropCourse(c *gin.Context) {
 		})
 		return
 	}
-	err := h.registrationService.DropCourse(c.Request.Context(), req.StudentID, req.SectionID)
+	if !authorizeStudent(c, req.StudentID) {
+		return
+	}
+	outcome, err := h.registrationService.DropCourse(c.Request.Context(), req.StudentID, req.SectionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, APIResponse{
+		c.JSON(registrationErrorStatus(err), APIResponse{
 			Success: false,
 			Message: "Failed to drop course",
 			Errors:  err.Error(),
@@ -107,9 +111,14 @@ func (h *RegistrationHandler) DropCourse(c *gin.Context) {
 		return
 	}
 
+	message := "Course dropped successfully"
+	if outcome == domain.StatusWithdrawn {
+		message = "Withdrawn from course after the drop deadline; the seat is not released"
+	}
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
-		Message: "Course dropped successfully",
+		Message: message,
+		Data:    map[string]interface{}{"action": outcome},
 	})
 }
 
@@ -132,7 +141,37 @@ func (h *RegistrationHandler) GetAvailableSections(c *gin.Context) {
 		return
 	}
 
-	sections, err := h.registrationService.GetAvailableSections(c.Request.Context(), semesterID)
+	// ?tags=online,evening&attr[campus]=north keeps sections matching all of them
+	filter := service.SectionTagFilter{Attributes: c.QueryMap("attr")}
+	for _, value := range c.QueryArray("tags") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+
+	// ?include=waitlist_count,enrolled_count adds those counts to every section
+	var include service.SectionCountsInclude
+	for _, value := range c.QueryArray("include") {
+		for _, name := range strings.Split(value, ",") {
+			switch strings.TrimSpace(name) {
+			case "waitlist_count":
+				include.WaitlistCount = true
+			case "enrolled_count":
+				include.EnrolledCount = true
+			case "":
+			default:
+				c.JSON(http.StatusBadRequest, APIResponse{
+					Success: false,
+					Message: "include may only list waitlist_count and enrolled_count",
+				})
+				return
+			}
+		}
+	}
+
+	sections, err := h.registrationService.SearchAvailableSections(c.Request.Context(), semesterID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, APIResponse{
 			Success: false,
@@ -142,10 +181,15 @@ func (h *RegistrationHandler) GetAvailableSections(c *gin.Context) {
 		return
 	}
 
+	var data any = sections
+	if include.WaitlistCount || include.EnrolledCount {
+		data = h.registrationService.WithSectionCounts(c.Request.Context(), sections, include)
+	}
+
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
 		Message: "Available sections retrieved successfully",
-		Data:    map[string]any{"sections": sections},
+		Data:    map[string]any{"sections": data},
 	})
 }
 
@@ -177,6 +221,47 @@ func (h *RegistrationHandler) GetWaitlistStatus(c *gin.Context) {
 	})
 }
 
+func (h *RegistrationHandler) LeaveWaitlist(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("student_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid student ID format",
+		})
+		return
+	}
+
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return
+	}
+
+	if err := h.registrationService.LeaveWaitlist(c.Request.Context(), studentID, sectionID); err != nil {
+		if errors.Is(err, service.ErrNotOnWaitlist) {
+			c.JSON(http.StatusNotFound, APIResponse{
+				Success: false,
+				Message: "Student is not on the waitlist for this section",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to leave waitlist",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Removed from waitlist successfully",
+	})
+}
+
 func (h *RegistrationHandler) GetStudentRegistrations(c *gin.Context) {
 	studentIDStr := c.Param("student_id")
 	studentID, err := uuid.Parse(studentIDStr)
@@ -204,3 +289,727 @@ func (h *RegistrationHandler) GetStudentRegistrations(c *gin.Context) {
 		Data:    map[string]interface{}{"registrations": registrations},
 	})
 }
+
+// GetRegistrationHistory returns the student's status transitions grouped into a timeline per
+// section
+func (h *RegistrationHandler) GetRegistrationHistory(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	timelines, err := h.registrationService.GetRegistrationHistory(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to retrieve registration history",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Registration history retrieved successfully",
+		Data:    map[string]interface{}{"sections": timelines},
+	})
+}
+
+// GetStudentSchedule returns the student's weekly schedule for the semester given by
+// ?semester_id=
+func (h *RegistrationHandler) GetStudentSchedule(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+	semesterID, err := uuid.Parse(c.Query("semester_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "semester_id is required and must be a UUID",
+		})
+		return
+	}
+
+	schedule, err := h.registrationService.GetStudentSchedule(c.Request.Context(), studentID, semesterID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSemesterNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to retrieve student schedule",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Student schedule retrieved successfully",
+		Data:    schedule,
+	})
+}
+
+// GetStudentScheduleICS downloads the student's schedule for the semester given by
+// ?semester_id= as an iCalendar feed that calendar apps can subscribe to
+func (h *RegistrationHandler) GetStudentScheduleICS(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+	semesterID, err := uuid.Parse(c.Query("semester_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "semester_id is required and must be a UUID",
+		})
+		return
+	}
+
+	schedule, err := h.registrationService.GetStudentSchedule(c.Request.Context(), studentID, semesterID)
+	if err == nil && schedule.StartDate.IsZero() {
+		err = service.ErrScheduleWithoutTerm
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSemesterNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to export student schedule",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	filename := "schedule.ics"
+	if schedule.SemesterCode != "" {
+		filename = fmt.Sprintf("schedule-%s.ics", schedule.SemesterCode)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := schedule.WriteICS(c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+func (h *RegistrationHandler) CheckEligibility(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("student_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid student ID format",
+		})
+		return
+	}
+
+	sectionID, err := uuid.Parse(c.Query("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "section_id query parameter must be a valid UUID",
+		})
+		return
+	}
+
+	eligibility, err := h.registrationService.CheckEligibility(c.Request.Context(), studentID, sectionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to check registration eligibility",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Registration eligibility checked successfully",
+		Data:    eligibility,
+	})
+}
+
+type SectionRosterResponse struct {
+	SectionID  uuid.UUID             `json:"section_id"`
+	AsOf       time.Time             `json:"as_of"`
+	Sequence   int64                 `json:"sequence"`
+	Enrolled   []*domain.RosterEntry `json:"enrolled"`
+	Waitlisted []*domain.RosterEntry `json:"waitlisted"`
+	Dropped    []*domain.RosterEntry `json:"dropped"`
+}
+
+func (h *RegistrationHandler) GetSectionEvents(c *gin.Context) {
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return
+	}
+
+	afterSequence, err := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil || afterSequence < 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "after must be a non-negative sequence number",
+		})
+		return
+	}
+
+	events, err := h.registrationService.GetSectionEvents(c.Request.Context(), sectionID, afterSequence)
+	if err != nil {
+		c.JSON(eventStoreErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to retrieve registration events",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Registration events retrieved successfully",
+		Data:    map[string]any{"events": events},
+	})
+}
+
+// GetSectionRosterAt rebuilds the section roster from the event store as of the as_of
+// query parameter (RFC3339), defaulting to now.
+func (h *RegistrationHandler) GetSectionRosterAt(c *gin.Context) {
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return
+	}
+
+	asOf := time.Now()
+	if raw := c.Query("as_of"); raw != "" {
+		asOf, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "as_of must be an RFC3339 timestamp",
+			})
+			return
+		}
+	}
+
+	roster, err := h.registrationService.GetSectionRosterAt(c.Request.Context(), sectionID, asOf)
+	if err != nil {
+		c.JSON(eventStoreErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to rebuild section roster",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section roster retrieved successfully",
+		Data: SectionRosterResponse{
+			SectionID:  sectionID,
+			AsOf:       asOf,
+			Sequence:   roster.Sequence,
+			Enrolled:   roster.WithStatus(domain.StatusEnrolled),
+			Waitlisted: roster.WithStatus(domain.StatusWaitlisted),
+			Dropped:    roster.WithStatus(domain.StatusDropped),
+		},
+	})
+}
+
+const (
+	defaultRosterLimit = 100
+	maxRosterLimit     = 500
+)
+
+// GetSectionRoster returns the students enrolled in a section, a page at a time through
+// offset and limit, together with its whole waitlist. With format=csv the full roster is
+// downloaded as a CSV file instead.
+func (h *RegistrationHandler) GetSectionRoster(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "format must be json or csv",
+		})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "offset must be a non-negative integer",
+		})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRosterLimit)))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "limit must be a positive integer",
+		})
+		return
+	}
+	limit = min(limit, maxRosterLimit)
+
+	roster, err := h.registrationService.GetSectionRoster(c.Request.Context(), sectionID)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get section roster",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if format == "csv" {
+		filename := fmt.Sprintf("roster-%s-%s.csv", roster.CourseCode, roster.SectionNumber)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		if err := roster.WriteCSV(c.Writer); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section roster retrieved successfully",
+		Data: map[string]any{
+			"roster": roster.Page(offset, limit),
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// GetSectionStateAsOf returns the enrollment and waitlist of a section at the RFC3339
+// timestamp. An optional student_id adds that student's entry and event history.
+func (h *RegistrationHandler) GetSectionStateAsOf(c *gin.Context) {
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return
+	}
+
+	asOf, err := time.Parse(time.RFC3339, c.Query("timestamp"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "timestamp must be an RFC3339 timestamp",
+		})
+		return
+	}
+
+	var studentID *uuid.UUID
+	if raw := c.Query("student_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "Invalid student ID format",
+			})
+			return
+		}
+		studentID = &id
+	}
+
+	state, err := h.registrationService.GetSectionStateAsOf(c.Request.Context(), sectionID, asOf, studentID)
+	if err != nil {
+		status := eventStoreErrorStatus(err)
+		switch {
+		case errors.Is(err, service.ErrAsOfInFuture):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrSectionNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to rebuild section state",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section state retrieved successfully",
+		Data:    state,
+	})
+}
+
+// seatStreamHeartbeat keeps idle availability streams from being closed by proxies
+const seatStreamHeartbeat = 15 * time.Second
+
+// StreamSeatAvailability streams the section's seat count as Server-Sent Events: an
+// "availability" event with the current count on connect and another on every change.
+func (h *RegistrationHandler) StreamSeatAvailability(c *gin.Context) {
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	current, changes, err := h.registrationService.WatchSeatAvailability(ctx, sectionID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSectionNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to stream seat availability",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	// The server write timeout is sized for ordinary requests, not for a page left open for
+	// the whole registration period. Writers that cannot clear it just get cut off earlier.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("availability", current)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(seatStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case change, ok := <-changes:
+			if !ok {
+				return false
+			}
+			c.SSEvent("availability", change)
+			return true
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
+
+func eventStoreErrorStatus(err error) int {
+	if errors.Is(err, service.ErrEventStoreDisabled) {
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
+
+type HoldSeatRequest struct {
+	StudentID uuid.UUID `json:"student_id" validate:"required"`
+	SectionID uuid.UUID `json:"section_id" validate:"required"`
+}
+
+type ConfirmSeatHoldRequest struct {
+	StudentID uuid.UUID `json:"student_id" validate:"required"`
+	HoldID    uuid.UUID `json:"hold_id" validate:"required"`
+}
+
+func (h *RegistrationHandler) HoldSeat(c *gin.Context) {
+	var req HoldSeatRequest
+	if !h.bindAndValidate(c, &req) || !authorizeStudent(c, req.StudentID) {
+		return
+	}
+
+	hold, err := h.registrationService.HoldSeat(c.Request.Context(), req.StudentID, req.SectionID)
+	if err != nil {
+		c.JSON(seatHoldErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to hold seat",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Seat held",
+		Data:    hold,
+	})
+}
+
+func (h *RegistrationHandler) ConfirmSeatHold(c *gin.Context) {
+	var req ConfirmSeatHoldRequest
+	if !h.bindAndValidate(c, &req) || !authorizeStudent(c, req.StudentID) {
+		return
+	}
+
+	hold, err := h.registrationService.ConfirmSeatHold(c.Request.Context(), req.HoldID, req.StudentID)
+	if err != nil {
+		c.JSON(seatHoldErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to confirm seat hold",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Seat hold confirmed",
+		Data:    hold,
+	})
+}
+
+func bindAndValidate(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid request format",
+			Errors:  err.Error(),
+		})
+		return false
+	}
+	return validateRequest(c, req)
+}
+
+// bindJSON decodes the body into req, rejecting unknown fields when the handler is strict
+func (h *RegistrationHandler) bindJSON(c *gin.Context, req any) bool {
+	var err error
+	if h.strictJSON {
+		err = decodeStrictJSON(c.Request.Body, req)
+	} else {
+		err = c.ShouldBindJSON(req)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid request format",
+			Errors:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func (h *RegistrationHandler) bindAndValidate(c *gin.Context, req any) bool {
+	return h.bindJSON(c, req) && validateRequest(c, req)
+}
+
+// decodeStrictJSON decodes exactly one JSON object, rejecting fields req does not declare
+// and anything after the object. A misspelled optional field such as idempotency_key would
+// otherwise go unnoticed.
+func decodeStrictJSON(body io.Reader, req any) error {
+	if body == nil {
+		return errors.New("request body is empty")
+	}
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("request body must contain a single JSON object")
+	}
+	return nil
+}
+
+func validateRequest(c *gin.Context, req any) bool {
+	if err := validator.ValidateStruct(req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Validation failed",
+			Errors:  validator.FormatValidationError(err),
+		})
+		return false
+	}
+
+	return true
+}
+
+// authorizeStudent rejects the request unless the caller may act for studentID. It covers
+// requests that name the student in the body rather than the path.
+func authorizeStudent(c *gin.Context, studentID uuid.UUID) bool {
+	err := auth.AuthorizeStudent(c.Request.Context(), studentID)
+	if err == nil {
+		return true
+	}
+
+	status := http.StatusForbidden
+	if errors.Is(err, auth.ErrMissingToken) {
+		status = http.StatusUnauthorized
+	}
+	c.JSON(status, APIResponse{
+		Success: false,
+		Message: http.StatusText(status),
+		Errors:  err.Error(),
+	})
+	return false
+}
+
+func seatHoldErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSeatHoldsDisabled):
+		return http.StatusNotImplemented
+	case errors.Is(err, service.ErrStudentArchived), errors.Is(err, service.ErrStudentOnHold):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrSeatHoldNotFound), errors.Is(err, service.ErrSectionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrSeatHoldExpired):
+		return http.StatusGone
+	case errors.Is(err, service.ErrSeatHoldNotHeld), errors.Is(err, service.ErrNoSeatsAvailable),
+		errors.Is(err, service.ErrAlreadyRegistered), errors.Is(err, service.ErrStudentBusy),
+		errors.Is(err, service.ErrSectionInactive), errors.Is(err, service.ErrRegistrationClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type SeatOfferActionRequest struct {
+	StudentID uuid.UUID `json:"student_id" validate:"required"`
+}
+
+func (h *RegistrationHandler) GetSeatOffers(c *gin.Context) {
+	studentIDStr := c.Param("student_id")
+	studentID, err := uuid.Parse(studentIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid student ID format",
+		})
+		return
+	}
+
+	offers, err := h.registrationService.GetStudentSeatOffers(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to retrieve seat offers",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Seat offers retrieved successfully",
+		Data:    map[string]any{"offers": offers},
+	})
+}
+
+func (h *RegistrationHandler) AcceptSeatOffer(c *gin.Context) {
+	offerID, req, ok := h.bindSeatOfferAction(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.registrationService.AcceptSeatOffer(c.Request.Context(), offerID, req.StudentID)
+	if err != nil {
+		c.JSON(seatOfferErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to accept seat offer",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Seat offer accepted",
+		Data:    offer,
+	})
+}
+
+func (h *RegistrationHandler) DeclineSeatOffer(c *gin.Context) {
+	offerID, req, ok := h.bindSeatOfferAction(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.registrationService.DeclineSeatOffer(c.Request.Context(), offerID, req.StudentID)
+	if err != nil {
+		c.JSON(seatOfferErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to decline seat offer",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Seat offer declined",
+		Data:    offer,
+	})
+}
+
+func (h *RegistrationHandler) bindSeatOfferAction(c *gin.Context) (uuid.UUID, *SeatOfferActionRequest, bool) {
+	offerID, err := uuid.Parse(c.Param("offer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid offer ID format",
+		})
+		return uuid.UUID{}, nil, false
+	}
+
+	var req SeatOfferActionRequest
+	if !h.bindAndValidate(c, &req) || !authorizeStudent(c, req.StudentID) {
+		return uuid.UUID{}, nil, false
+	}
+
+	return offerID, &req, true
+}
+
+func seatOfferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSeatOfferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrStudentArchived):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrSeatOfferNotPending), errors.Is(err, service.ErrSeatOfferExpired):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func registrationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrStudentBusy), errors.Is(err, service.ErrIdempotencyKeyConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrStudentArchived), errors.Is(err, service.ErrStudentOnHold):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrPermissionNumberNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPermissionNumberUsed):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrPermissionNumberSection):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrWithdrawDeadlinePassed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
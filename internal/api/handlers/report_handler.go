@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReportHandler struct {
+	forecastService      *service.ForecastService
+	sectionReportService *service.SectionReportService
+}
+
+func NewReportHandler(forecastService *service.ForecastService, sectionReportService *service.SectionReportService) *ReportHandler {
+	return &ReportHandler{
+		forecastService:      forecastService,
+		sectionReportService: sectionReportService,
+	}
+}
+
+// GetSectionStats reports the capacity, enrollment, waitlist, drops, fill rate and time to
+// fill of every section of a semester. With format=csv the report is streamed as a CSV file.
+func (h *ReportHandler) GetSectionStats(c *gin.Context) {
+	semesterID, err := uuid.Parse(c.Query("semester_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "A valid semester_id is required",
+		})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "format must be json or csv",
+		})
+		return
+	}
+
+	report, err := h.sectionReportService.GetSectionStats(c.Request.Context(), semesterID)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get section statistics",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if format == "csv" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "sections-"+semesterID.String()+".csv"))
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		if err := service.WriteSectionStatsCSV(c.Writer, report); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section statistics retrieved successfully",
+		Data:    report,
+	})
+}
+
+// GetEnrollmentForecasts returns the latest enrollment forecasts of a semester, for one
+// department when ?department= is given
+func (h *ReportHandler) GetEnrollmentForecasts(c *gin.Context) {
+	semesterID, err := uuid.Parse(c.Query("semester_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "A valid semester_id is required",
+		})
+		return
+	}
+	department := strings.ToUpper(strings.TrimSpace(c.Query("department")))
+
+	report, err := h.forecastService.GetForecasts(c.Request.Context(), semesterID, department)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get enrollment forecasts",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Enrollment forecasts retrieved successfully",
+		Data:    report,
+	})
+}
+
+// RunEnrollmentForecasts recomputes the forecasts now instead of waiting for the nightly run
+func (h *ReportHandler) RunEnrollmentForecasts(c *gin.Context) {
+	result, err := h.forecastService.RunForecasts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to run enrollment forecasts",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Enrollment forecasts updated",
+		Data:    result,
+	})
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ScheduleOverrideHandler serves the schedule override workflow: students ask to take a
+// section that clashes with their schedule and advisors work through the queue.
+type ScheduleOverrideHandler struct {
+	registrationService *service.RegistrationService
+}
+
+func NewScheduleOverrideHandler(registrationService *service.RegistrationService) *ScheduleOverrideHandler {
+	return &ScheduleOverrideHandler{
+		registrationService: registrationService,
+	}
+}
+
+func (h *ScheduleOverrideHandler) RequestOverride(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ScheduleOverrideRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	override, err := h.registrationService.RequestScheduleOverride(c.Request.Context(), studentID, &req)
+	if err != nil {
+		c.JSON(scheduleOverrideErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to request schedule override",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Schedule override requested; an advisor will review it",
+		Data:    override,
+	})
+}
+
+func (h *ScheduleOverrideHandler) GetStudentOverrides(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	overrides, err := h.registrationService.GetStudentScheduleOverrides(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list schedule overrides",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Schedule overrides retrieved successfully",
+		Data:    overrides,
+	})
+}
+
+// ListOverrides is the advising queue; it lists pending overrides unless ?status= says otherwise
+func (h *ScheduleOverrideHandler) ListOverrides(c *gin.Context) {
+	overrides, err := h.registrationService.ListScheduleOverrides(c.Request.Context(), domain.ScheduleOverrideStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(scheduleOverrideErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to list schedule overrides",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Schedule overrides retrieved successfully",
+		Data:    overrides,
+	})
+}
+
+// Approve approves the override and registers the student. A registration that does not go
+// through, such as one for a section that has since closed, is reported on the override.
+func (h *ScheduleOverrideHandler) Approve(c *gin.Context) {
+	overrideID, decision, ok := parseOverrideDecision(c)
+	if !ok {
+		return
+	}
+
+	override, err := h.registrationService.ApproveScheduleOverride(c.Request.Context(), overrideID, decision)
+	if err != nil {
+		c.JSON(scheduleOverrideErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to approve schedule override",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Schedule override approved; registration " + override.RegistrationStatus,
+		Data:    override,
+	})
+}
+
+func (h *ScheduleOverrideHandler) Deny(c *gin.Context) {
+	overrideID, decision, ok := parseOverrideDecision(c)
+	if !ok {
+		return
+	}
+
+	override, err := h.registrationService.DenyScheduleOverride(c.Request.Context(), overrideID, decision)
+	if err != nil {
+		c.JSON(scheduleOverrideErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to deny schedule override",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Schedule override denied",
+		Data:    override,
+	})
+}
+
+// parseOverrideDecision reads the override ID and the optional decision note
+func parseOverrideDecision(c *gin.Context) (uuid.UUID, *service.ScheduleOverrideDecision, bool) {
+	overrideID, err := uuid.Parse(c.Param("override_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid override ID format",
+		})
+		return uuid.UUID{}, nil, false
+	}
+
+	var decision service.ScheduleOverrideDecision
+	if c.Request.ContentLength != 0 && !bindAndValidate(c, &decision) {
+		return uuid.UUID{}, nil, false
+	}
+	return overrideID, &decision, true
+}
+
+func scheduleOverrideErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrScheduleOverrideNotFound),
+		errors.Is(err, service.ErrSectionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrScheduleOverrideExists),
+		errors.Is(err, service.ErrScheduleOverrideDecided),
+		errors.Is(err, service.ErrNoScheduleConflict),
+		errors.Is(err, service.ErrStudentBusy):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrStudentArchived):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrInvalidOverrideStatus):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
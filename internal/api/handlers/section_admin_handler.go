@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+	"cobra-template/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SectionAdminHandler struct {
+	sectionService *service.SectionService
+}
+
+func NewSectionAdminHandler(sectionService *service.SectionService) *SectionAdminHandler {
+	return &SectionAdminHandler{
+		sectionService: sectionService,
+	}
+}
+
+func (h *SectionAdminHandler) CreateSection(c *gin.Context) {
+	var req service.CreateSectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid request format",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Validation failed",
+			Errors:  validator.FormatValidationError(err),
+		})
+		return
+	}
+
+	section, err := h.sectionService.CreateSection(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to create section",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Section created successfully",
+		Data:    section,
+	})
+}
+
+func (h *SectionAdminHandler) GetSection(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	section, err := h.sectionService.GetSection(c.Request.Context(), sectionID)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get section",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section retrieved successfully",
+		Data:    section,
+	})
+}
+
+func (h *SectionAdminHandler) UpdateCapacity(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateSectionCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid request format",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Validation failed",
+			Errors:  validator.FormatValidationError(err),
+		})
+		return
+	}
+
+	section, err := h.sectionService.UpdateCapacity(c.Request.Context(), sectionID, req.TotalSeats)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update section capacity",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section capacity updated successfully",
+		Data:    section,
+	})
+}
+
+func (h *SectionAdminHandler) DeactivateSection(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	section, err := h.sectionService.DeactivateSection(c.Request.Context(), sectionID)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to deactivate section",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section deactivated successfully",
+		Data:    section,
+	})
+}
+
+// SetWaitlistFreeze stops or restarts new waitlist adds for a section, optionally pausing
+// promotions of students already waiting
+func (h *SectionAdminHandler) SetWaitlistFreeze(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetWaitlistFreezeRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	section, err := h.sectionService.SetWaitlistFreeze(c.Request.Context(), sectionID, &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update waitlist freeze",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Waitlist freeze updated successfully",
+		Data:    section,
+	})
+}
+
+// SetWaitlistTTL sets how long new entries on the section's waitlist last before they expire
+func (h *SectionAdminHandler) SetWaitlistTTL(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetWaitlistTTLRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	section, err := h.sectionService.SetWaitlistTTL(c.Request.Context(), sectionID, &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update waitlist TTL",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Waitlist TTL updated successfully",
+		Data:    section,
+	})
+}
+
+// SetPolicy replaces the section's waitlist and registration policy
+func (h *SectionAdminHandler) SetPolicy(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetSectionPolicyRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	section, err := h.sectionService.SetPolicy(c.Request.Context(), sectionID, &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update section policy",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section policy updated successfully",
+		Data:    section,
+	})
+}
+
+// SetSeatPools replaces the section's seat pools. The response shows how many seats each
+// pool has left.
+func (h *SectionAdminHandler) SetSeatPools(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetSeatPoolsRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	section, err := h.sectionService.SetSeatPools(c.Request.Context(), sectionID, &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update seat pools",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Seat pools updated successfully",
+		Data:    section,
+	})
+}
+
+// SetSectionTags replaces the section's own catalog tags and attributes
+func (h *SectionAdminHandler) SetSectionTags(c *gin.Context) {
+	sectionID, ok := parseSectionID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetTagsRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	section, err := h.sectionService.SetSectionTags(c.Request.Context(), sectionID, &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update section tags",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Section tags updated successfully",
+		Data:    section,
+	})
+}
+
+// SetCourseTags replaces the catalog tags and attributes every section of the course inherits
+func (h *SectionAdminHandler) SetCourseTags(c *gin.Context) {
+	courseID, ok := parseCourseID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetTagsRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	course, err := h.sectionService.SetCourseTags(c.Request.Context(), courseID, &req)
+	if err != nil {
+		c.JSON(sectionErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update course tags",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Course tags updated successfully",
+		Data:    course,
+	})
+}
+
+func parseSectionID(c *gin.Context) (uuid.UUID, bool) {
+	sectionID, err := uuid.Parse(c.Param("section_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid section ID format",
+		})
+		return uuid.UUID{}, false
+	}
+	return sectionID, true
+}
+
+func sectionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSectionNotFound),
+		errors.Is(err, service.ErrCourseNotFound),
+		errors.Is(err, service.ErrSemesterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrSectionExists),
+		errors.Is(err, service.ErrCourseInactive),
+		errors.Is(err, service.ErrCapacityBelowEnrollment):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrInvalidMeetingTime),
+		errors.Is(err, service.ErrInvalidLinkedSection),
+		errors.Is(err, service.ErrInvalidSectionPolicy),
+		errors.Is(err, service.ErrInvalidSeatPools),
+		errors.Is(err, service.ErrDuplicateSeatPool):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
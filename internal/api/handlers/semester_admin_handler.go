@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SemesterAdminHandler struct {
+	semesterService *service.SemesterService
+}
+
+func NewSemesterAdminHandler(semesterService *service.SemesterService) *SemesterAdminHandler {
+	return &SemesterAdminHandler{
+		semesterService: semesterService,
+	}
+}
+
+func (h *SemesterAdminHandler) CreateSemester(c *gin.Context) {
+	var req service.CreateSemesterRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	semester, err := h.semesterService.CreateSemester(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(semesterErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to create semester",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Semester created successfully",
+		Data:    semester,
+	})
+}
+
+// ListSemesters returns every semester, including inactive ones the public list leaves out
+func (h *SemesterAdminHandler) ListSemesters(c *gin.Context) {
+	semesters, err := h.semesterService.ListAllSemesters(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list semesters",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Semesters retrieved successfully",
+		Data:    semesters,
+	})
+}
+
+func (h *SemesterAdminHandler) GetSemester(c *gin.Context) {
+	semesterID, ok := parseSemesterID(c)
+	if !ok {
+		return
+	}
+
+	semester, err := h.semesterService.GetSemester(c.Request.Context(), semesterID)
+	if err != nil {
+		c.JSON(semesterErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get semester",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Semester retrieved successfully",
+		Data:    semester,
+	})
+}
+
+func (h *SemesterAdminHandler) UpdateSemester(c *gin.Context) {
+	semesterID, ok := parseSemesterID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateSemesterRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	semester, err := h.semesterService.UpdateSemester(c.Request.Context(), semesterID, &req)
+	if err != nil {
+		c.JSON(semesterErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update semester",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Semester updated successfully",
+		Data:    semester,
+	})
+}
+
+func parseSemesterID(c *gin.Context) (uuid.UUID, bool) {
+	semesterID, err := uuid.Parse(c.Param("semester_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid semester ID format",
+		})
+		return uuid.UUID{}, false
+	}
+	return semesterID, true
+}
+
+func semesterErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrSemesterNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrSemesterExists):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidSemesterDates):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SemesterHandler struct {
+	semesterService *service.SemesterService
+}
+
+func NewSemesterHandler(semesterService *service.SemesterService) *SemesterHandler {
+	return &SemesterHandler{
+		semesterService: semesterService,
+	}
+}
+
+func (h *SemesterHandler) ListSemesters(c *gin.Context) {
+	semesters, err := h.semesterService.ListSemesters(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to get semesters",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Semesters retrieved successfully",
+		Data:    semesters,
+	})
+}
+
+func (h *SemesterHandler) GetCalendar(c *gin.Context) {
+	semesterID, ok := parseSemesterID(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.semesterService.GetCalendar(c.Request.Context(), semesterID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSemesterNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to get semester calendar",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Semester calendar retrieved successfully",
+		Data:    calendar,
+	})
+}
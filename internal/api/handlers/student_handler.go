@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"cobra-template/internal/service"
+	"cobra-template/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StudentHandler struct {
+	studentService *service.StudentService
+}
+
+func NewStudentHandler(studentService *service.StudentService) *StudentHandler {
+	return &StudentHandler{
+		studentService: studentService,
+	}
+}
+
+// GetStudent returns the student's details with counts of their enrolled credits, waitlist
+// places and holds
+func (h *StudentHandler) GetStudent(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	overview, err := h.studentService.GetStudent(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get student",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Student retrieved successfully",
+		Data:    overview,
+	})
+}
+
+func (h *StudentHandler) GetProfile(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	student, err := h.studentService.GetProfile(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to get student profile",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Student profile retrieved successfully",
+		Data:    student,
+	})
+}
+
+// UpdateProfile lets a student change their preferred name and contact details
+func (h *StudentHandler) UpdateProfile(c *gin.Context) {
+	h.updateProfile(c, service.ProfileEditorStudent)
+}
+
+// UpdateProfileAsRegistrar also allows the legal first and last name to be corrected
+func (h *StudentHandler) UpdateProfileAsRegistrar(c *gin.Context) {
+	h.updateProfile(c, service.ProfileEditorRegistrar)
+}
+
+func (h *StudentHandler) updateProfile(c *gin.Context, editor service.ProfileEditor) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateStudentProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid request format",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	if err := validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Validation failed",
+			Errors:  validator.FormatValidationError(err),
+		})
+		return
+	}
+
+	student, err := h.studentService.UpdateProfile(c.Request.Context(), studentID, &req, editor)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update student profile",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Student profile updated successfully",
+		Data:    student,
+	})
+}
+
+func (h *StudentHandler) CreateStudent(c *gin.Context) {
+	var req service.CreateStudentRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	student, err := h.studentService.CreateStudent(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to create student",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Student created successfully",
+		Data:    student,
+	})
+}
+
+// UpdateStudent changes any field of a student record an administrator maintains, including
+// the student number and enrollment status
+func (h *StudentHandler) UpdateStudent(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateStudentRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	student, err := h.studentService.UpdateStudent(c.Request.Context(), studentID, &req)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to update student",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Student updated successfully",
+		Data:    student,
+	})
+}
+
+func (h *StudentHandler) DeactivateStudent(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	student, err := h.studentService.DeactivateStudent(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to deactivate student",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Student deactivated",
+		Data:    student,
+	})
+}
+
+// ImportStudents creates students from an uploaded CSV, either the raw body or the "file"
+// field of a multipart form. dry_run=true checks the rows without creating anyone.
+func (h *StudentHandler) ImportStudents(c *gin.Context) {
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{
+				Success: false,
+				Message: "dry_run must be true or false",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	body, err := importBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid upload",
+			Errors:  err.Error(),
+		})
+		return
+	}
+	defer body.Close()
+
+	report, err := h.studentService.ImportStudents(c.Request.Context(), body, dryRun)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to import students",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	message := "Students imported"
+	if dryRun {
+		message = "Student import checked (dry run)"
+	}
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// ArchiveStudents archives a graduated cohort and/or a list of students. Their history stays
+// readable; registration changes are rejected from then on.
+func (h *StudentHandler) ArchiveStudents(c *gin.Context) {
+	var req service.ArchiveStudentsRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	result, err := h.studentService.ArchiveStudents(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(studentErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to archive students",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Students archived",
+		Data:    result,
+	})
+}
+
+func parseStudentID(c *gin.Context) (uuid.UUID, bool) {
+	studentID, err := uuid.Parse(c.Param("student_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid student ID format",
+		})
+		return uuid.UUID{}, false
+	}
+	return studentID, true
+}
+
+func studentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrStudentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrProfileFieldForbidden), errors.Is(err, service.ErrStudentArchived):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrEmailInUse), errors.Is(err, service.ErrStudentNumberInUse):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrNoArchiveSelection), errors.Is(err, service.ErrStudentImportHeader), errors.Is(err, service.ErrImportTooLarge):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StudentHoldHandler lets staff place and release the financial and advising holds that
+// keep a student from registering
+type StudentHoldHandler struct {
+	studentHoldService *service.StudentHoldService
+}
+
+func NewStudentHoldHandler(studentHoldService *service.StudentHoldService) *StudentHoldHandler {
+	return &StudentHoldHandler{
+		studentHoldService: studentHoldService,
+	}
+}
+
+func (h *StudentHoldHandler) ListHolds(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	holds, err := h.studentHoldService.ListActiveHolds(c.Request.Context(), studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, APIResponse{
+			Success: false,
+			Message: "Failed to list holds",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Holds retrieved successfully",
+		Data:    holds,
+	})
+}
+
+func (h *StudentHoldHandler) PlaceHold(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+
+	var req service.PlaceHoldRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	hold, err := h.studentHoldService.PlaceHold(c.Request.Context(), studentID, &req)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to place hold",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Hold placed successfully",
+		Data:    hold,
+	})
+}
+
+func (h *StudentHoldHandler) ReleaseHold(c *gin.Context) {
+	studentID, ok := parseStudentID(c)
+	if !ok {
+		return
+	}
+	holdID, err := uuid.Parse(c.Param("hold_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIResponse{
+			Success: false,
+			Message: "Invalid hold ID format",
+		})
+		return
+	}
+
+	hold, err := h.studentHoldService.ReleaseHold(c.Request.Context(), studentID, holdID)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), APIResponse{
+			Success: false,
+			Message: "Failed to release hold",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Hold released successfully",
+		Data:    hold,
+	})
+}
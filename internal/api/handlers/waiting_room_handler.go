@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitingRoomHandler struct {
+	waitingRoomService *service.WaitingRoomService
+}
+
+func NewWaitingRoomHandler(waitingRoomService *service.WaitingRoomService) *WaitingRoomHandler {
+	return &WaitingRoomHandler{
+		waitingRoomService: waitingRoomService,
+	}
+}
+
+// GetTicket reports a queued caller's position. Once admitted, the caller retries the
+// registration request with the token; until then it should poll again after Retry-After.
+func (h *WaitingRoomHandler) GetTicket(c *gin.Context) {
+	ticket, err := h.waitingRoomService.GetTicket(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrWaitingTicketNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, APIResponse{
+			Success: false,
+			Message: "Failed to get waiting room ticket",
+			Errors:  err.Error(),
+		})
+		return
+	}
+
+	message := "Your turn has come; retry the request with this token"
+	if !ticket.Admitted {
+		c.Header("Retry-After", strconv.Itoa(int(service.WaitingRoomPollInterval.Seconds())))
+		message = "Still waiting"
+	}
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    ticket,
+	})
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cobra-template/internal/auth"
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Authenticate verifies the bearer token, or the X-API-Key header of machine clients, and
+// stores the caller's claims in the request context. With a nil authenticator,
+// authentication is disabled and every caller is treated as an admin.
+func Authenticate(authenticator *auth.Authenticator, apiKeys *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authenticator == nil {
+			c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), auth.Anonymous()))
+			c.Next()
+			return
+		}
+
+		if plaintext := c.GetHeader(APIKeyHeader); plaintext != "" && apiKeys != nil {
+			authenticateAPIKey(c, apiKeys, plaintext)
+			return
+		}
+
+		token := bearerToken(c)
+		if token == "" {
+			abortAuth(c, http.StatusUnauthorized, auth.ErrMissingToken)
+			return
+		}
+
+		claims, err := authenticator.Verify(token)
+		if err != nil {
+			abortAuth(c, http.StatusUnauthorized, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+// APIKeyHeader carries the API key of machine clients
+const APIKeyHeader = "X-API-Key"
+
+// authenticateAPIKey verifies an API key and spends one request of its rate limit. The limit
+// headers are set on every response so clients can pace themselves.
+func authenticateAPIKey(c *gin.Context, apiKeys *service.APIKeyService, plaintext string) {
+	key, err := apiKeys.Authenticate(c.Request.Context(), plaintext)
+	if err != nil {
+		status := http.StatusUnauthorized
+		if !errors.Is(err, service.ErrInvalidAPIKey) {
+			status = http.StatusInternalServerError
+		}
+		abortAuth(c, status, err)
+		return
+	}
+
+	limit := apiKeys.Allow(c.Request.Context(), key)
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(limit.Remaining))
+	if !limit.ResetAt.IsZero() {
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(limit.ResetAt.Unix(), 10))
+	}
+	if !limit.Allowed {
+		retryAfter := int(math.Ceil(time.Until(limit.ResetAt).Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		abortAuth(c, http.StatusTooManyRequests, errors.New("API key rate limit exceeded"))
+		return
+	}
+
+	scopes := make([]auth.Scope, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = auth.Scope(scope)
+	}
+	claims := auth.ServiceClaims(key.KeyID, scopes)
+	c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), claims))
+	c.Next()
+}
+
+// RequireRole lets the request through only for users with one of roles. API key clients
+// never pass.
+func RequireRole(roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			abortAuth(c, http.StatusUnauthorized, auth.ErrMissingToken)
+			return
+		}
+		if !claims.HasRole(roles...) {
+			abortAuth(c, http.StatusForbidden, auth.ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAccess lets users with one of roles and API key clients with scope through
+func RequireAccess(scope auth.Scope, roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			abortAuth(c, http.StatusUnauthorized, auth.ErrMissingToken)
+			return
+		}
+		if !claims.Allows(scope, roles...) {
+			abortAuth(c, http.StatusForbidden, auth.ErrForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireStudentParam lets students through only for their own :student_id. Registrars and
+// admins may use any student_id, as may API key clients with a registrations scope; reads
+// need registrations:read and changes registrations:write.
+func RequireStudentParam() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		studentID, err := uuid.Parse(c.Param("student_id"))
+		if err != nil {
+			// Let the handler report the malformed ID
+			c.Next()
+			return
+		}
+
+		authorize := auth.AuthorizeStudent
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			authorize = auth.AuthorizeStudentRead
+		}
+		if err := authorize(c.Request.Context(), studentID); err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, auth.ErrMissingToken) {
+				status = http.StatusUnauthorized
+			}
+			abortAuth(c, status, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerToken reads the Authorization header. Browsers cannot set headers on WebSocket
+// handshakes, so those may pass the token as the access_token query parameter instead.
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		return c.Query("access_token")
+	}
+	return ""
+}
+
+func abortAuth(c *gin.Context, status int, err error) {
+	if status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Bearer realm="course-registration"`)
+	}
+	c.AbortWithStatusJSON(status, gin.H{
+		"success": false,
+		"message": http.StatusText(status),
+		"errors":  err.Error(),
+	})
+}
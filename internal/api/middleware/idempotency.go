@@ -1,13 +1,183 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-func IdempotencyMiddleware() gin.HandlerFunc {
+const (
+	// IdempotencyKeyHeader lets clients retry mutating requests safely
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from an earlier request
+	IdempotentReplayHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength matches the limit on idempotency_key in request bodies
+	maxIdempotencyKeyLength = 255
+	// idempotencyInFlightTTL bounds how long a key stays claimed by a request that never
+	// finishes, such as one whose server died mid-way
+	idempotencyInFlightTTL = 2 * time.Minute
+	// idempotencyResponseTTL is how long a finished response can be replayed
+	idempotencyResponseTTL = 24 * time.Hour
+)
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header safe to retry.
+// The first request with a key claims it while it runs and its response is stored when it
+// finishes; a retry gets that response back without reaching the handler. A retry that
+// arrives while the first request is still running, or that reuses the key for a different
+// request, is rejected with 409.
+//
+// Keys are scoped to the authenticated caller, so it must run after Authenticate. Server
+// errors and transient conflicts are not stored, leaving the request free to be retried.
+// When the key store cannot be reached the request goes ahead without protection.
+func IdempotencyMiddleware(repo interfaces.IdempotencyRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idempotencyKey := c.GetHeader("Idempotency-Key")
-		c.Set("idempotency_key", idempotencyKey)
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortIdempotency(c, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortIdempotency(c, http.StatusBadRequest, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		claims, _ := auth.FromContext(c.Request.Context())
+		record := &domain.IdempotencyKey{
+			Key:         idempotencyScope(claims) + ":" + key,
+			StudentID:   idempotencyStudent(claims),
+			RequestHash: idempotencyRequestHash(c.Request.Method, c.Request.URL.Path, body),
+			Status:      domain.IdempotencyProcessing,
+			CreatedAt:   time.Now(),
+			ExpiresAt:   time.Now().Add(idempotencyInFlightTTL),
+		}
+
+		ctx := c.Request.Context()
+		reserved, err := repo.Reserve(ctx, record, idempotencyInFlightTTL)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to reserve idempotency key %s, processing without it: %v", key, err)
+			c.Next()
+			return
+		}
+		if !reserved {
+			replayIdempotent(c, repo, record)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
 		c.Next()
+
+		// The outcome is recorded even if the client has gone away, which is when it retries
+		ctx = context.WithoutCancel(ctx)
+		status := recorder.Status()
+		if !storableStatus(status) {
+			if err := repo.Delete(ctx, record.Key); err != nil {
+				logger.WarnContext(ctx, "Failed to release idempotency key %s: %v", key, err)
+			}
+			return
+		}
+
+		now := time.Now()
+		record.Status = domain.IdempotencyCompleted
+		record.ResponseData = recorder.body.String()
+		record.StatusCode = status
+		record.ProcessedAt = now
+		record.ExpiresAt = now.Add(idempotencyResponseTTL)
+		if err := repo.Create(ctx, record); err != nil {
+			logger.WarnContext(ctx, "Failed to store response for idempotency key %s: %v", key, err)
+		}
+	}
+}
+
+// replayIdempotent answers a request whose key is already taken
+func replayIdempotent(c *gin.Context, repo interfaces.IdempotencyRepository, record *domain.IdempotencyKey) {
+	existing, err := repo.GetByKey(c.Request.Context(), record.Key)
+	if err != nil || existing == nil {
+		// The other request released the key between the two calls
+		abortIdempotency(c, http.StatusConflict, "A request with this Idempotency-Key has just finished; retry it")
+		return
 	}
+
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		abortIdempotency(c, http.StatusConflict, "Idempotency-Key was already used for a different request")
+	case existing.IsProcessing():
+		abortIdempotency(c, http.StatusConflict, "A request with this Idempotency-Key is still being processed")
+	default:
+		c.Header(IdempotentReplayHeader, "true")
+		c.Data(existing.StatusCode, "application/json; charset=utf-8", []byte(existing.ResponseData))
+		c.Abort()
+	}
+}
+
+func abortIdempotency(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"success": false,
+		"message": message,
+	})
+}
+
+// storableStatus reports whether a response is final. Server errors, busy students and
+// admission throttling say nothing about the request itself, so they are not replayed.
+func storableStatus(status int) bool {
+	return status < http.StatusInternalServerError &&
+		status != http.StatusConflict &&
+		status != http.StatusTooManyRequests
+}
+
+// idempotencyScope keeps one caller's keys from matching another's
+func idempotencyScope(claims *auth.Claims) string {
+	if claims == nil {
+		return "http:anonymous"
+	}
+	return "http:" + string(claims.Role) + ":" + claims.Subject
+}
+
+func idempotencyStudent(claims *auth.Claims) uuid.UUID {
+	if claims == nil || claims.StudentID == nil {
+		return uuid.Nil
+	}
+	return *claims.StudentID
+}
+
+func idempotencyRequestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body so it can be stored for replays
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
 }
@@ -37,6 +37,9 @@ func Logger() gin.HandlerFunc {
 			"method":      param.Method,
 			"path":        param.Path,
 		}
+		if requestID := logger.RequestID(c.Request.Context()); requestID != "" {
+			logFields["request_id"] = requestID
+		}
 
 		if len(c.Errors) > 0 {
 
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths are polled by probes and scrapers and would only add noise to traces
+var untracedPaths = map[string]bool{
+	"/health":  true,
+	"/ready":   true,
+	"/live":    true,
+	"/metrics": true,
+}
+
+// Tracing starts a server span for each request and puts it on the request context, so
+// service, cache, queue and database spans created further down join the same trace.
+func Tracing(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
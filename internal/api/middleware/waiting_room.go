@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"cobra-template/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WaitingRoomTokenHeader carries the ticket token of a caller retrying from the waiting room
+const WaitingRoomTokenHeader = "X-Waiting-Room-Token"
+
+// WaitingRoomPollPath is where a queued caller polls its ticket
+const WaitingRoomPollPath = "/api/v1/waiting-room/"
+
+// AdmissionControl runs requests through the waiting room. Callers over the concurrency
+// limit get 429 with a ticket to poll, and retry with its token once admitted.
+func AdmissionControl(waitingRoom *service.WaitingRoomService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if waitingRoom == nil || !waitingRoom.Enabled() {
+			c.Next()
+			return
+		}
+
+		slotID, ticket := waitingRoom.Admit(c.Request.Context(), c.GetHeader(WaitingRoomTokenHeader))
+		if ticket != nil {
+			c.Header("Retry-After", strconv.Itoa(int(service.WaitingRoomPollInterval.Seconds())))
+			c.Header("Location", WaitingRoomPollPath+ticket.Token)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Registration is busy; you have been placed in the waiting room",
+				"data":    ticket,
+			})
+			return
+		}
+
+		defer waitingRoom.Release(c.Request.Context(), slotID)
+		c.Next()
+	}
+}
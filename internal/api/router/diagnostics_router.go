@@ -0,0 +1,45 @@
+package router
+
+import (
+	"net/http/pprof"
+	"time"
+
+	"cobra-template/internal/api/handlers"
+	"cobra-template/internal/api/middleware"
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewDiagnosticsRouter builds the internal diagnostics server. It is meant to be bound to a
+// private interface only since profiles expose memory contents and runtime internals.
+func NewDiagnosticsRouter(cfg *config.DiagnosticsConfig, storage interfaces.StorageService, signedURLExpiry time.Duration) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(middleware.Logger())
+	r.Use(gin.Recovery())
+
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(cfg, storage, signedURLExpiry)
+
+	debug := r.Group("/debug")
+	{
+		profiling := debug.Group("/pprof")
+		{
+			profiling.GET("/", gin.WrapF(pprof.Index))
+			profiling.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+			profiling.GET("/profile", gin.WrapF(pprof.Profile))
+			profiling.POST("/symbol", gin.WrapF(pprof.Symbol))
+			profiling.GET("/symbol", gin.WrapF(pprof.Symbol))
+			profiling.GET("/trace", gin.WrapF(pprof.Trace))
+			profiling.GET("/:profile", gin.WrapF(pprof.Index))
+		}
+
+		debug.GET("/runtime", diagnosticsHandler.GetRuntimeStats)
+		debug.POST("/profiles", diagnosticsHandler.CaptureProfile)
+		debug.GET("/profiles", diagnosticsHandler.ListProfiles)
+		debug.GET("/profiles/:name", diagnosticsHandler.DownloadProfile)
+	}
+
+	return r
+}
@@ -2,28 +2,49 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"cobra-template/internal/api/graphqlapi"
 	"cobra-template/internal/api/handlers"
 	"cobra-template/internal/api/middleware"
+	"cobra-template/internal/api/wshub"
+	"cobra-template/internal/auth"
 	"cobra-template/internal/config"
-	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/analytics"
 	"cobra-template/internal/infrastructure/cache"
+	"cobra-template/internal/infrastructure/database"
 	"cobra-template/internal/infrastructure/queue"
 	"cobra-template/internal/infrastructure/repository"
+	"cobra-template/internal/infrastructure/scheduler"
+	"cobra-template/internal/infrastructure/storage"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
 	"cobra-template/internal/service"
+	"cobra-template/pkg/logger"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/gorm"
 )
 
 type RouterComponents struct {
-	Router       *gin.Engine
-	QueueService interfaces.QueueService
+	Router              *gin.Engine
+	QueueService        interfaces.QueueService
+	Storage             interfaces.StorageService
+	RegistrationService *service.RegistrationService
+	StudentHub          *wshub.Hub
+	// OutboxDispatcher is nil when the sync outbox could not be opened
+	OutboxDispatcher *service.OutboxDispatcher
+	// ForecastService runs the nightly enrollment forecast; nil when forecasting is disabled
+	ForecastService *service.ForecastService
+	// Scheduler runs the recurring maintenance tasks enabled in config
+	Scheduler *scheduler.Scheduler
+	// Authenticator is nil when authentication is disabled
+	Authenticator *auth.Authenticator
 }
 
 func NewRegistrationRouter(db *gorm.DB) *gin.Engine {
@@ -32,8 +53,12 @@ func NewRegistrationRouter(db *gorm.DB) *gin.Engine {
 }
 
 func NewRegistrationRouterWithQueue(db *gorm.DB) *RouterComponents {
+	cfg := config.Get()
+
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
+	r.Use(middleware.Tracing(cfg.Tracing.ServiceName))
+	r.Use(middleware.RequestID())
 	r.Use(middleware.Logger())
 	r.Use(cors.Default())
 	r.Use(gin.Recovery())
@@ -41,29 +66,92 @@ func NewRegistrationRouterWithQueue(db *gorm.DB) *RouterComponents {
 	studentRepo := repository.NewStudentRepository(db)
 	sectionRepo := repository.NewSectionRepository(db)
 	semesterRepo := repository.NewSemesterRepository(db)
+	courseRepo := repository.NewCourseRepository(db)
 
 	registrationRepo := repository.NewRegistrationRepository(db)
 
-	cfg := config.Get()
-	cacheService := cache.NewRedisCacheWithConfig(&cfg.Cache)
+	var cacheService interfaces.CacheService
+	var memoryCache *cache.MemoryCache
+	var redisClient redis.UniversalClient
+	switch cfg.Cache.Type {
+	case "memory":
+		memoryCache = cache.NewMemoryCache()
+		cacheService = memoryCache
+		// Stores without an in-memory version keep using Redis and degrade without it
+		redisClient = cache.NewRedisClient(&cfg.Cache)
+		fmt.Println("Using in-memory cache service; seat offers, seat holds, idempotency keys, KPI counters, notifications, the waiting room and API key rate limits still use Redis")
+	default:
+		redisCache := cache.NewRedisCacheWithConfig(&cfg.Cache)
+		redisCache.SetSyncOutboxNames(cache.NewSyncOutboxNames(&cfg.Queue))
+		cacheService = redisCache
+		redisClient = redisCache.GetClient()
+		if cfg.Cache.Local.Enabled {
+			cacheService = cache.NewTieredCache(redisCache, redisClient, &cfg.Cache.Local)
+			fmt.Printf("Using local cache in front of Redis (%d entries, %ds TTL)\n", cfg.Cache.Local.Size, cfg.Cache.Local.TTLSeconds)
+		}
+	}
 
 	var waitlistRepo interfaces.WaitlistRepository
 	if cfg.Registration.WaitlistRepository == "redis" {
-		waitlistRepo = repository.NewRedisWaitlistRepository(cacheService.GetClient())
+		waitlistRepo = repository.NewRedisWaitlistRepository(redisClient)
 		fmt.Println("Using Redis waitlist repository")
 	} else {
 		waitlistRepo = repository.NewWaitlistRepository(db)
 		fmt.Println("Using database waitlist repository")
 	}
-	idempotencyRepo := repository.NewRedisIdempotencyRepository(cacheService.GetClient())
+	var idempotencyRepo interfaces.IdempotencyRepository = repository.NewRedisIdempotencyRepository(redisClient)
+	if cfg.Registration.IdempotencyStore == "hybrid" {
+		idempotencyRepo = repository.NewHybridIdempotencyRepository(
+			repository.NewRedisIdempotencyRepository(redisClient),
+			repository.NewIdempotencyRepository(db),
+		)
+		fmt.Println("Using hybrid idempotency store (Redis with Postgres write-through)")
+	}
+	seatOfferRepo := repository.NewRedisSeatOfferRepository(redisClient)
+	seatHoldRepo := repository.NewRedisSeatHoldRepository(redisClient)
+	studentHoldRepo := repository.NewStudentHoldRepository(db)
+	scheduleOverrideRepo := repository.NewScheduleOverrideRepository(db)
+	permissionNumberRepo := repository.NewPermissionNumberRepository(db)
+	transitionRepo := repository.NewRegistrationTransitionRepository(db)
+	var eventStore *service.RegistrationEventStore
+	if cfg.Registration.PersistenceMode == service.PersistenceModeEventSourced {
+		eventStore = service.NewRegistrationEventStore(repository.NewRegistrationEventRepository(db), cfg.Registration.SnapshotInterval)
+		fmt.Println("Using event-sourced registration persistence")
+	}
+
 	var queueService interfaces.QueueService
-	if cfg.Queue.Type == "redis" {
-		queueService = queue.NewRedisQueue(&cfg.Cache, 3)
+	switch cfg.Queue.Type {
+	case "redis":
+		queueService = queue.NewRedisQueue(&cfg.Cache, queue.NewNames(&cfg.Queue), queue.NewLanes(&cfg.Queue), 3, cfg.Queue.RetryAttempts)
 		fmt.Println("Using Redis queue service")
-	} else {
-		queueService = queue.NewInMemoryQueue(cfg.Queue.BufferSize, 3)
+	case "redis-streams":
+		queueService = queue.NewRedisStreamsQueue(&cfg.Cache, queue.NewNames(&cfg.Queue), queue.NewLanes(&cfg.Queue), 3, cfg.Queue.RetryAttempts)
+		fmt.Println("Using Redis Streams queue service")
+	case "kafka":
+		queueService = queue.NewKafkaQueue(&cfg.Queue.Kafka, queue.NewNames(&cfg.Queue), 3, cfg.Queue.RetryAttempts)
+		fmt.Println("Using Kafka queue service")
+	default:
+		queueService = queue.NewInMemoryQueue(queue.NewNames(&cfg.Queue), queue.NewLanes(&cfg.Queue), cfg.Queue.BufferSize, 3, cfg.Queue.RetryAttempts)
 		fmt.Println("Using in-memory queue service")
 	}
+	if cfg.Queue.PriorityLanes.Enabled {
+		fmt.Printf("Using priority lanes for database sync jobs (critical:normal %d:%d)\n",
+			cfg.Queue.PriorityLanes.CriticalWeight, cfg.Queue.PriorityLanes.NormalWeight)
+	}
+
+	calendarRepo := repository.NewCalendarEventRepository(db)
+	termLocation, err := time.LoadLocation(cfg.Institution.Timezone)
+	if err != nil {
+		fmt.Printf("Warning: Unknown institution timezone %q, using UTC: %v\n", cfg.Institution.Timezone, err)
+		termLocation = time.UTC
+	}
+	semesterService := service.NewSemesterService(semesterRepo, calendarRepo, cacheService, termLocation)
+	kpiCounters := cache.NewRedisKPICounters(redisClient)
+	studentNotifier := cache.NewRedisStudentNotifier(redisClient)
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo, queueService)
+
+	sectionCacheWarmer := service.NewSectionCacheWarmer(sectionRepo, semesterService, cacheService, cfg.Cache.WarmupConcurrency, cfg.Cache.WarmupBatchSize)
 
 	registrationService := service.NewRegistrationService(
 		studentRepo,
@@ -73,116 +161,442 @@ func NewRegistrationRouterWithQueue(db *gorm.DB) *RouterComponents {
 		cacheService,
 		queueService,
 		idempotencyRepo,
+		seatOfferRepo,
+		seatHoldRepo,
+		studentHoldRepo,
+		scheduleOverrideRepo,
+		permissionNumberRepo,
+		transitionRepo,
+		eventStore,
+		semesterService,
+		sectionCacheWarmer,
+		kpiCounters,
+		studentNotifier,
+		auditService,
 		cfg.Registration.WaitlistFallbackEnabled,
+		time.Duration(cfg.Registration.SeatOfferTTLMinutes)*time.Minute,
+		time.Duration(cfg.Registration.SeatHoldTTLMinutes)*time.Minute,
+		time.Duration(cfg.Registration.WaitlistTTLHours)*time.Hour,
+		cfg.Registration.WaitlistMaxSize,
+		time.Duration(cfg.Registration.StudentLockTTLSeconds)*time.Second,
+		time.Duration(cfg.Registration.StudentLockWaitMilliseconds)*time.Millisecond,
 	)
 
-	if err := initializeMinimalCache(cacheService, sectionRepo, semesterRepo); err != nil {
-		fmt.Printf("Warning: Failed to initialize minimal cache: %v\n", err)
+	if err := initializeCache(sectionCacheWarmer, cfg.Cache.WarmupScope); err != nil {
+		fmt.Printf("Warning: Failed to initialize cache: %v\n", err)
 	}
 
+	fileStorage, err := storage.New(&cfg.Storage)
+	if err != nil {
+		fmt.Printf("Warning: Failed to initialize %s storage, falling back to local storage: %v\n", cfg.Storage.Provider, err)
+		fileStorage = storage.NewLocalStorage(&cfg.Storage.Local)
+	}
+	signedURLExpiry := time.Duration(cfg.Storage.SignedURLExpiryMinutes) * time.Minute
+	sectionService := service.NewSectionService(sectionRepo, courseRepo, semesterRepo, registrationRepo, cacheService, queueService, auditService)
+	approvalService := service.NewApprovalService(
+		repository.NewApprovalRepository(db),
+		sectionService,
+		semesterService,
+		registrationService,
+		time.Duration(cfg.Approvals.WindowMinutes)*time.Minute,
+	)
+	studentService := service.NewStudentService(studentRepo, cacheService, registrationService)
+	courseService := service.NewCourseService(courseRepo, sectionRepo, cacheService)
+	waitingRoom := cache.NewRedisWaitingRoom(redisClient, service.WaitingRoomStaleAfter)
+	waitingRoomService := service.NewWaitingRoomService(waitingRoom, cfg.Registration.ConcurrentRegistrationsLimit)
+	kpiService := service.NewKPIService(kpiCounters, sectionRepo)
+	exportService := service.NewExportService(sectionRepo, registrationRepo, fileStorage, signedURLExpiry)
+	importService := service.NewImportService(registrationService, studentRepo, courseRepo, sectionRepo, semesterRepo)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), cache.NewRedisRateLimiter(redisClient), cfg.Auth.APIKeyRateLimit)
+
+	if cfg.Registration.DBFallback.Enabled {
+		registrationService.EnableDatabaseFallback(cfg.Registration.DBFallback.FailureThreshold, time.Duration(cfg.Registration.DBFallback.OpenSeconds)*time.Second)
+		fmt.Printf("Using database fallback for registrations while Redis is unavailable (after %d failures, retried every %ds)\n",
+			cfg.Registration.DBFallback.FailureThreshold, cfg.Registration.DBFallback.OpenSeconds)
+	}
+	if cfg.Queue.SeatSyncWindowMS > 0 {
+		registrationService.EnableSeatSyncBatching(time.Duration(cfg.Queue.SeatSyncWindowMS)*time.Millisecond, cfg.Queue.SeatSyncMaxEvents)
+	}
+	analyticsSink, err := analytics.New(&cfg.Analytics, redisClient, &cfg.Queue.Kafka)
+	if err != nil {
+		fmt.Printf("Warning: Failed to initialize analytics sink, analytics events are off: %v\n", err)
+	} else if analyticsSink != nil {
+		registrationService.EnableAnalytics(analyticsSink, cfg.Analytics.BufferSize)
+		fmt.Printf("Emitting registration analytics events to the %s sink\n", cfg.Analytics.Sink)
+	}
 	queueService.SetRegistrationService(registrationService)
+	if cfg.Reminders.Enabled {
+		reminderService := service.NewReminderService(
+			semesterService,
+			sectionRepo,
+			registrationRepo,
+			waitlistRepo,
+			studentRepo,
+			studentNotifier,
+			queueService,
+			cfg.Reminders.LeadHours,
+			cfg.Reminders.MinEnrolledSections,
+		)
+		queueService.SetReminderService(reminderService)
+	}
+	queueService.SetAuditRepository(auditRepo)
 	queueService.StartWorkers()
-	registrationHandler := handlers.NewRegistrationHandler(registrationService)
-	healthHandler := handlers.NewHealthHandler()
-	r.Use(middleware.IdempotencyMiddleware())
+	maintenance := newScheduler(cfg, cacheService, registrationService, idempotencyRepo, registrationRepo, sectionRepo, semesterRepo)
+	maintenance.Start()
+	if tasks := maintenance.Tasks(); len(tasks) > 0 {
+		fmt.Printf("Scheduled maintenance tasks: %s (seat reconciliation heal: %t)\n", strings.Join(tasks, ", "), cfg.Reconciliation.Heal)
+	}
+	var outboxDispatcher *service.OutboxDispatcher
+	if memoryCache != nil {
+		outboxDispatcher = service.NewOutboxDispatcher(memoryCache.SyncOutbox(), queueService)
+		outboxDispatcher.Start()
+	} else if syncOutbox, err := cache.NewRedisSyncOutbox(context.Background(), redisClient, cache.NewSyncOutboxNames(&cfg.Queue)); err != nil {
+		fmt.Printf("Warning: Failed to open sync outbox, its jobs will not be queued until restart: %v\n", err)
+	} else {
+		outboxDispatcher = service.NewOutboxDispatcher(syncOutbox, queueService)
+		outboxDispatcher.Start()
+	}
+	forecastService := service.NewForecastService(
+		repository.NewEnrollmentForecastRepository(db),
+		registrationRepo,
+		sectionRepo,
+		semesterRepo,
+		cacheService,
+		termLocation,
+		cfg.Forecasting.RunHour,
+		cfg.Forecasting.UnderSubscribedRatio,
+	)
+	var nightlyForecasts *service.ForecastService
+	if cfg.Forecasting.Enabled {
+		forecastService.Start()
+		nightlyForecasts = forecastService
+		fmt.Printf("Forecasting enrollment nightly at %02d:00 %s\n", cfg.Forecasting.RunHour, termLocation)
+	}
+	registrationHandler := handlers.NewRegistrationHandler(registrationService, cfg.Registration.StrictJSON)
+	queueAdminHandler := handlers.NewQueueAdminHandler(queueService)
+	exportHandler := handlers.NewExportHandler(exportService, fileStorage)
+	importHandler := handlers.NewImportHandler(importService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	sectionAdminHandler := handlers.NewSectionAdminHandler(sectionService)
+	studentHandler := handlers.NewStudentHandler(studentService)
+	semesterHandler := handlers.NewSemesterHandler(semesterService)
+	semesterAdminHandler := handlers.NewSemesterAdminHandler(semesterService)
+	kpiHandler := handlers.NewKPIHandler(kpiService)
+	sectionReportService := service.NewSectionReportService(registrationRepo, semesterRepo, cacheService)
+	reportHandler := handlers.NewReportHandler(forecastService, sectionReportService)
+	courseHandler := handlers.NewCourseHandler(courseService)
+	courseAdminHandler := handlers.NewCourseAdminHandler(courseService)
+	approvalHandler := handlers.NewApprovalHandler(approvalService)
+	scheduleOverrideHandler := handlers.NewScheduleOverrideHandler(registrationService)
+	permissionNumberHandler := handlers.NewPermissionNumberHandler(registrationService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	cacheAdminHandler := handlers.NewCacheAdminHandler(sectionCacheWarmer, cacheService)
+	waitingRoomHandler := handlers.NewWaitingRoomHandler(waitingRoomService)
+	studentHoldService := service.NewStudentHoldService(studentHoldRepo, studentRepo, cacheService, studentNotifier)
+	billingWebhookHandler := handlers.NewBillingWebhookHandler(
+		studentHoldService,
+		cfg.Billing.WebhookSecret,
+		time.Duration(cfg.Billing.SignatureToleranceSeconds)*time.Second,
+	)
+	studentHoldHandler := handlers.NewStudentHoldHandler(studentHoldService)
+	drainableQueue, _ := queueService.(interfaces.DrainableQueue)
+	workerHealth, _ := queueService.(interfaces.WorkerHealth)
+	healthService := service.NewHealthService(
+		database.NewHealthChecker(db, "migrations"),
+		cache.NewRedisHealthChecker(redisClient, &cfg.Cache),
+		workerHealth,
+		!cfg.Registration.DBFallback.Enabled,
+	)
+	healthHandler := handlers.NewHealthHandler(healthService, drainableQueue)
 	r.GET("/health", healthHandler.HealthCheck)
 	r.GET("/ready", healthHandler.ReadinessCheck)
 	r.GET("/live", healthHandler.LivenessCheck)
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	graphqlHandler := gin.WrapH(graphqlapi.NewHandler(graphqlapi.NewResolver(registrationService, sectionRepo, cacheService)))
+	studentHub := wshub.NewHub(studentNotifier)
+	if err := studentHub.Start(); err != nil {
+		fmt.Printf("Warning: Failed to start student notifications: %v\n", err)
+	}
+
+	authenticator := newAuthenticator(&cfg.Auth)
+	authenticate := middleware.Authenticate(authenticator, apiKeyService)
+	requireStaff := middleware.RequireAccess(auth.ScopeAdmin, auth.RoleRegistrar, auth.RoleAdmin)
+	// Only people may manage API keys, so a leaked key cannot mint more
+	requireAdmin := middleware.RequireRole(auth.RoleAdmin)
+	requireAdvisor := middleware.RequireRole(auth.RoleAdvisor, auth.RoleRegistrar, auth.RoleAdmin)
+	ownStudent := middleware.RequireStudentParam()
+	// Replays are answered before the waiting room, so a retry does not queue again
+	idempotent := middleware.IdempotencyMiddleware(idempotencyRepo)
+
+	r.GET("/ws/students/:student_id", authenticate, ownStudent, studentHub.ServeStudent)
+	r.GET("/graphql", authenticate, graphqlHandler)
+	r.POST("/graphql", authenticate, graphqlHandler)
 	v1 := r.Group("/api/v1")
 	{
-		registration := v1.Group("/register")
+		registration := v1.Group("/register", authenticate, idempotent, middleware.AdmissionControl(waitingRoomService))
 		{
 			registration.POST("", registrationHandler.Register)
 			registration.POST("/drop", registrationHandler.DropCourse)
+			registration.POST("/hold", registrationHandler.HoldSeat)
+			registration.POST("/confirm", registrationHandler.ConfirmSeatHold)
 		}
 
-		students := v1.Group("/students")
+		v1.GET("/waiting-room/:token", authenticate, waitingRoomHandler.GetTicket)
+
+		students := v1.Group("/students", authenticate, ownStudent, idempotent)
 		{
+			students.GET("/:student_id", studentHandler.GetStudent)
+			students.GET("/:student_id/profile", studentHandler.GetProfile)
+			students.PATCH("/:student_id/profile", studentHandler.UpdateProfile)
 			students.GET("/:student_id/registrations", registrationHandler.GetStudentRegistrations)
+			students.GET("/:student_id/registrations/history", registrationHandler.GetRegistrationHistory)
+			students.GET("/:student_id/schedule", registrationHandler.GetStudentSchedule)
+			students.GET("/:student_id/schedule.ics", registrationHandler.GetStudentScheduleICS)
 			students.GET("/:student_id/waitlist", registrationHandler.GetWaitlistStatus)
+			students.DELETE("/:student_id/waitlist/:section_id", registrationHandler.LeaveWaitlist)
+			students.GET("/:student_id/offers", registrationHandler.GetSeatOffers)
+			students.GET("/:student_id/eligibility", registrationHandler.CheckEligibility)
+			students.GET("/:student_id/schedule-overrides", scheduleOverrideHandler.GetStudentOverrides)
+			students.POST("/:student_id/schedule-overrides", scheduleOverrideHandler.RequestOverride)
+		}
+
+		advising := v1.Group("/advising", authenticate, requireAdvisor)
+		{
+			advising.GET("/schedule-overrides", scheduleOverrideHandler.ListOverrides)
+			advising.POST("/schedule-overrides/:override_id/approve", scheduleOverrideHandler.Approve)
+			advising.POST("/schedule-overrides/:override_id/deny", scheduleOverrideHandler.Deny)
+		}
+
+		waitlist := v1.Group("/waitlist", authenticate, idempotent)
+		{
+			waitlist.POST("/offers/:offer_id/accept", registrationHandler.AcceptSeatOffer)
+			waitlist.POST("/offers/:offer_id/decline", registrationHandler.DeclineSeatOffer)
 		}
 
 		sections := v1.Group("/sections")
 		{
 			sections.GET("/available", registrationHandler.GetAvailableSections)
+			sections.GET("/:section_id/events", authenticate, requireStaff, registrationHandler.GetSectionEvents)
+			sections.GET("/:section_id/events/roster", authenticate, requireStaff, registrationHandler.GetSectionRosterAt)
+			sections.GET("/:section_id/roster", authenticate, requireStaff, registrationHandler.GetSectionRoster)
+			sections.GET("/:section_id/availability/stream", registrationHandler.StreamSeatAvailability)
+		}
+
+		v1.GET("/courses/search", courseHandler.SearchCourses)
+
+		semesters := v1.Group("/semesters")
+		{
+			semesters.GET("", semesterHandler.ListSemesters)
+			semesters.GET("/:semester_id/calendar", semesterHandler.GetCalendar)
+		}
+
+		cacheAdmin := v1.Group("/cache", authenticate, requireStaff)
+		{
+			cacheAdmin.POST("/warmup", cacheAdminHandler.Warmup)
+			cacheAdmin.POST("/warmup/loadtest", cacheAdminHandler.WarmupForLoadTest)
+			cacheAdmin.GET("/stats", cacheAdminHandler.GetStats)
+			cacheAdmin.GET("/loadtest/status", cacheAdminHandler.GetLoadTestStatus)
+		}
+
+		admin := v1.Group("/admin", authenticate, requireStaff)
+		{
+			admin.GET("/queue/names", queueAdminHandler.GetQueueNames)
+			admin.GET("/queue/dlq", queueAdminHandler.GetDeadLetterJobs)
+			admin.POST("/queue/dlq/replay", queueAdminHandler.ReplayDeadLetterJobs)
+			admin.GET("/queue/poison", queueAdminHandler.GetPoisonJobs)
+			admin.GET("/kpis", kpiHandler.GetKPIs)
+			admin.GET("/audit", auditHandler.ListEvents)
+			admin.GET("/reports/sections", reportHandler.GetSectionStats)
+			admin.GET("/reports/enrollment-forecasts", reportHandler.GetEnrollmentForecasts)
+			admin.POST("/reports/enrollment-forecasts/run", reportHandler.RunEnrollmentForecasts)
+			admin.POST("/exports/sections/:section_id/registrations", exportHandler.ExportSectionRegistrations)
+			admin.POST("/registrations/import", importHandler.ImportRegistrations)
+			admin.POST("/sections", sectionAdminHandler.CreateSection)
+			admin.GET("/sections/:section_id", sectionAdminHandler.GetSection)
+			admin.GET("/sections/:section_id/as-of", registrationHandler.GetSectionStateAsOf)
+			admin.PUT("/sections/:section_id/capacity", sectionAdminHandler.UpdateCapacity)
+			admin.POST("/sections/:section_id/deactivate", sectionAdminHandler.DeactivateSection)
+			admin.PUT("/sections/:section_id/waitlist-freeze", sectionAdminHandler.SetWaitlistFreeze)
+			admin.PUT("/sections/:section_id/waitlist-ttl", sectionAdminHandler.SetWaitlistTTL)
+			admin.PUT("/sections/:section_id/policy", sectionAdminHandler.SetPolicy)
+			admin.PUT("/sections/:section_id/seat-pools", sectionAdminHandler.SetSeatPools)
+			admin.GET("/sections/:section_id/permission-numbers", permissionNumberHandler.List)
+			admin.POST("/sections/:section_id/permission-numbers", permissionNumberHandler.Issue)
+			admin.PUT("/sections/:section_id/tags", sectionAdminHandler.SetSectionTags)
+			admin.GET("/courses", courseAdminHandler.ListCourses)
+			admin.POST("/courses", courseAdminHandler.CreateCourse)
+			admin.GET("/courses/:course_id", courseAdminHandler.GetCourse)
+			admin.PATCH("/courses/:course_id", courseAdminHandler.UpdateCourse)
+			admin.POST("/courses/:course_id/deactivate", courseAdminHandler.DeactivateCourse)
+			admin.PUT("/courses/:course_id/tags", sectionAdminHandler.SetCourseTags)
+			admin.GET("/semesters", semesterAdminHandler.ListSemesters)
+			admin.POST("/semesters", semesterAdminHandler.CreateSemester)
+			admin.GET("/semesters/:semester_id", semesterAdminHandler.GetSemester)
+			admin.PATCH("/semesters/:semester_id", semesterAdminHandler.UpdateSemester)
+			admin.GET("/approvals", approvalHandler.ListApprovals)
+			admin.POST("/approvals", approvalHandler.ProposeOperation)
+			admin.GET("/approvals/:approval_id", approvalHandler.GetApproval)
+			admin.POST("/approvals/:approval_id/approve", approvalHandler.Approve)
+			admin.POST("/approvals/:approval_id/reject", approvalHandler.Reject)
+			admin.POST("/students", studentHandler.CreateStudent)
+			admin.POST("/students/import", studentHandler.ImportStudents)
+			admin.GET("/students/:student_id", studentHandler.GetStudent)
+			admin.PATCH("/students/:student_id", studentHandler.UpdateStudent)
+			admin.POST("/students/:student_id/deactivate", studentHandler.DeactivateStudent)
+			admin.PATCH("/students/:student_id/profile", studentHandler.UpdateProfileAsRegistrar)
+			admin.POST("/students/archive", studentHandler.ArchiveStudents)
+			admin.GET("/students/:student_id/holds", studentHoldHandler.ListHolds)
+			admin.POST("/students/:student_id/holds", studentHoldHandler.PlaceHold)
+			admin.POST("/students/:student_id/holds/:hold_id/release", studentHoldHandler.ReleaseHold)
+			admin.GET("/api-keys", requireAdmin, apiKeyHandler.ListAPIKeys)
+			admin.POST("/api-keys", requireAdmin, apiKeyHandler.IssueAPIKey)
+			admin.POST("/api-keys/:key_id/rotate", requireAdmin, apiKeyHandler.RotateAPIKey)
+			admin.DELETE("/api-keys/:key_id", requireAdmin, apiKeyHandler.RevokeAPIKey)
+		}
+
+		v1.GET("/files/*key", exportHandler.DownloadFile)
+
+		// The bursar authenticates with a signature over the body rather than a token
+		if cfg.Billing.WebhookSecret != "" {
+			v1.POST("/webhooks/bursar/holds", billingWebhookHandler.HandleHoldEvent)
+		} else {
+			fmt.Println("Bursar hold webhook disabled: billing.webhook_secret is not set")
 		}
 
 	}
 
 	return &RouterComponents{
-		Router:       r,
-		QueueService: queueService,
+		Router:              r,
+		QueueService:        queueService,
+		Storage:             fileStorage,
+		RegistrationService: registrationService,
+		StudentHub:          studentHub,
+		Authenticator:       authenticator,
+		OutboxDispatcher:    outboxDispatcher,
+		ForecastService:     nightlyForecasts,
+		Scheduler:           maintenance,
 	}
 }
 
-// initializeMinimalCache implements minimal pre-caching for seat availability and semester sections availability only
-func initializeMinimalCache(
+// newScheduler registers the maintenance tasks enabled in cfg.Scheduler. Tasks that touch
+// shared state run on one instance at a time; seat offer expiry runs everywhere, since each
+// expiry is claimed individually and a slow sweep on one instance should not hold up others.
+func newScheduler(
+	cfg *config.Config,
 	cacheService interfaces.CacheService,
+	registrationService *service.RegistrationService,
+	idempotencyRepo interfaces.IdempotencyRepository,
+	registrationRepo interfaces.RegistrationRepository,
 	sectionRepo interfaces.SectionRepository,
 	semesterRepo interfaces.SemesterRepository,
-) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	fmt.Println("Starting minimal cache initialization...")
-	startTime := time.Now()
-
-	if err := cacheActiveSectionsMinimal(ctx, cacheService, sectionRepo); err != nil {
-		return fmt.Errorf("failed to cache active sections: %w", err)
+) *scheduler.Scheduler {
+	tasks := scheduler.New(cacheService)
+	interval := func(task config.ScheduledTaskConfig) time.Duration {
+		return time.Duration(task.IntervalSeconds) * time.Second
 	}
 
-	if err := cacheSpecificSemesterSections(ctx, cacheService, sectionRepo); err != nil {
-		return fmt.Errorf("failed to cache semester sections availability: %w", err)
+	if task := cfg.Scheduler.SeatOfferExpiry; task.Enabled {
+		tasks.Add(scheduler.Task{
+			Name:     "seat_offer_expiry",
+			Interval: interval(task),
+			Run: func(ctx context.Context) error {
+				return errors.Join(registrationService.ExpireSeatOffers(ctx), registrationService.ExpireSeatHolds(ctx))
+			},
+		})
 	}
-
-	duration := time.Since(startTime)
-	fmt.Printf("✅ Minimal cache initialization completed in %v\n", duration)
-	return nil
-}
-
-func cacheActiveSectionsMinimal(ctx context.Context, cacheService interfaces.CacheService, sectionRepo interfaces.SectionRepository) error {
-	sections, err := sectionRepo.GetAllActive(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get active sections: %w", err)
+	if task := cfg.Scheduler.WaitlistExpiry; task.Enabled {
+		tasks.Add(scheduler.Task{
+			Name:      "waitlist_expiry",
+			Interval:  interval(task),
+			Singleton: true,
+			Run:       registrationService.ExpireWaitlistEntries,
+		})
+	}
+	if task := cfg.Scheduler.IdempotencyCleanup; task.Enabled {
+		idempotencyService := service.NewIdempotencyService(idempotencyRepo)
+		tasks.Add(scheduler.Task{
+			Name:      "idempotency_cleanup",
+			Interval:  interval(task),
+			Singleton: true,
+			Run:       idempotencyService.CleanupExpiredKeys,
+		})
+	}
+	if task := cfg.Scheduler.SeatReconciliation; task.Enabled {
+		reconciler := service.NewSeatReconciler(
+			registrationRepo,
+			sectionRepo,
+			semesterRepo,
+			cacheService,
+			time.Duration(cfg.Reconciliation.SettleSeconds)*time.Second,
+		)
+		tasks.Add(scheduler.Task{
+			Name:      "seat_reconciliation",
+			Interval:  interval(task),
+			Timeout:   10 * time.Minute,
+			Singleton: true,
+			Run: func(ctx context.Context) error {
+				_, err := reconciler.Reconcile(ctx, cfg.Reconciliation.Heal)
+				return err
+			},
+		})
 	}
+	if task := cfg.Scheduler.CacheRefresh; task.Enabled {
+		tasks.Add(scheduler.Task{
+			Name:      "cache_refresh",
+			Interval:  interval(task),
+			Singleton: true,
+			Run: func(ctx context.Context) error {
+				_, err := registrationService.RefreshAllSectionCaches(ctx)
+				return err
+			},
+		})
+	}
+	return tasks
+}
 
-	cached := 0
-	for _, section := range sections {
-		if err := cacheService.SetAvailableSeats(ctx, section.SectionID, section.AvailableSeats, 24*time.Hour); err != nil {
-			fmt.Printf("Warning: Failed to cache seats for section %s: %v\n", section.SectionID, err)
-			continue
-		}
-		cached++
+// initializeCache warms the caches of every active semester at startup. The active scope
+// seeds seat availability and the available sections lists; the loadtest scope also caches
+// the details of every open section.
+func initializeCache(sectionCacheWarmer *service.SectionCacheWarmer, scope string) error {
+	warm := sectionCacheWarmer.WarmActiveSemesters
+	switch scope {
+	case config.WarmupScopeNone:
+		fmt.Println("Skipping cache initialization")
+		return nil
+	case config.WarmupScopeLoadTest:
+		warm = sectionCacheWarmer.WarmForLoadTest
 	}
 
-	fmt.Printf("📊 Cached seat availability for %d active sections\n", cached)
-	return nil
-}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-func cacheSpecificSemesterSections(ctx context.Context, cacheService interfaces.CacheService, sectionRepo interfaces.SectionRepository) error {
-	semesterID := uuid.MustParse("e093bb58-78e2-4985-bb7f-7a9b36c9102d")
+	fmt.Printf("Starting %s cache initialization...\n", scope)
+	startTime := time.Now()
 
-	sections, err := sectionRepo.GetBySemester(ctx, semesterID)
+	results, err := warm(ctx)
+	for _, result := range results {
+		fmt.Printf("📊 Semester %s: cached %d sections, %d with seats available\n", result.SemesterCode, result.Sections, result.Available)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get sections for semester %s: %w", semesterID, err)
+		return fmt.Errorf("failed to warm semester caches: %w", err)
 	}
 
-	availableSections := make([]*domain.Section, 0)
-	for _, section := range sections {
-
-		if cachedSeats, cacheErr := cacheService.GetAvailableSeats(ctx, section.SectionID); cacheErr == nil {
-			section.AvailableSeats = cachedSeats
-		}
+	duration := time.Since(startTime)
+	fmt.Printf("✅ Cache initialization completed for %d semesters in %v\n", len(results), duration)
+	return nil
+}
 
-		if section.AvailableSeats > 0 {
-			availableSections = append(availableSections, section)
-		}
+// newAuthenticator returns nil when authentication is disabled. An enabled but unusable
+// configuration stops startup rather than leaving the API open.
+func newAuthenticator(cfg *config.AuthConfig) *auth.Authenticator {
+	if !cfg.Enabled {
+		logger.Warn("Authentication is disabled; every caller is treated as an admin")
+		return nil
 	}
 
-	if err := cacheService.SetAvailableSections(ctx, semesterID, availableSections, 8*time.Hour); err != nil {
-		return fmt.Errorf("failed to cache available sections for semester %s: %w", semesterID, err)
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		logger.Fatal("Failed to configure authentication: %v", err)
 	}
-
-	fmt.Printf("📊 Cached %d available sections for semester %s\n", len(availableSections), semesterID)
-	return nil
+	return authenticator
 }
@@ -0,0 +1,230 @@
+// Package wshub pushes real-time student events, such as waitlist promotions and seat
+// offers, to students over WebSocket connections.
+package wshub
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"cobra-template/internal/api/handlers"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+	// sendBuffer is how many undelivered events a connection may have before it is dropped
+	sendBuffer = 16
+	// maxMessageSize bounds what a client may send; clients only send control frames
+	maxMessageSize = 512
+)
+
+// Hub keeps the open connections of this instance by student and forwards each published
+// student event to that student's connections. A student may be connected from several tabs.
+type Hub struct {
+	notifier interfaces.StudentNotifier
+	upgrader websocket.Upgrader
+
+	mu      sync.RWMutex
+	clients map[uuid.UUID]map[*client]struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type client struct {
+	hub       *Hub
+	conn      *websocket.Conn
+	studentID uuid.UUID
+	send      chan interfaces.StudentEvent
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewHub(notifier interfaces.StudentNotifier) *Hub {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Hub{
+		notifier: notifier,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Matches the CORS policy of the REST API, which accepts any origin
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		clients: make(map[uuid.UUID]map[*client]struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start subscribes to student events and begins forwarding them to connected students
+func (h *Hub) Start() error {
+	events, err := h.notifier.SubscribeAll(h.ctx)
+	if err != nil {
+		return err
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		for event := range events {
+			h.dispatch(event)
+		}
+	}()
+	return nil
+}
+
+// Close stops forwarding events and closes every open connection
+func (h *Hub) Close() {
+	h.cancel()
+	h.wg.Wait()
+
+	h.mu.Lock()
+	var open []*client
+	for _, clients := range h.clients {
+		for c := range clients {
+			open = append(open, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range open {
+		c.close()
+	}
+}
+
+// ServeStudent upgrades the request to a WebSocket that receives the student's events as
+// JSON messages until either side closes it
+func (h *Hub) ServeStudent(c *gin.Context) {
+	studentID, err := uuid.Parse(c.Param("student_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, handlers.APIResponse{
+			Success: false,
+			Message: "Invalid student ID format",
+		})
+		return
+	}
+
+	// Upgrade writes its own error response when the handshake is invalid
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn("Failed to open WebSocket for student %s: %v", studentID, err)
+		return
+	}
+
+	cl := &client{
+		hub:       h,
+		conn:      conn,
+		studentID: studentID,
+		send:      make(chan interfaces.StudentEvent, sendBuffer),
+		done:      make(chan struct{}),
+	}
+	h.register(cl)
+
+	go cl.writePump()
+	go cl.readPump()
+}
+
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[c.studentID] == nil {
+		h.clients[c.studentID] = make(map[*client]struct{})
+	}
+	h.clients[c.studentID][c] = struct{}{}
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := h.clients[c.studentID]
+	delete(clients, c)
+	if len(clients) == 0 {
+		delete(h.clients, c.studentID)
+	}
+}
+
+func (h *Hub) dispatch(event interfaces.StudentEvent) {
+	h.mu.RLock()
+	var slow []*client
+	for c := range h.clients[event.StudentID] {
+		select {
+		case c.send <- event:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	// A client this far behind is gone or stuck; it can reconnect and reload its state
+	for _, c := range slow {
+		logger.Warn("Dropping WebSocket of student %s that stopped reading events", c.studentID)
+		c.close()
+	}
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		c.hub.unregister(c)
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// readPump only handles control frames; reading is what notices that the peer went away
+func (c *client) readPump() {
+	defer c.close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.hub.ctx.Done():
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.conn.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+			return
+		case event := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Package auth verifies the bearer tokens callers present and decides what the caller behind
+// a token may do.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cobra-template/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type Role string
+
+const (
+	// RoleStudent may only act on their own registrations
+	RoleStudent Role = "student"
+	// RoleRegistrar manages registrations on behalf of students and uses the admin API
+	RoleRegistrar Role = "registrar"
+	// RoleAdvisor decides the schedule overrides students ask for
+	RoleAdvisor Role = "advisor"
+	// RoleAdmin can do everything a registrar can, including operating the service
+	RoleAdmin Role = "admin"
+	// RoleService is a machine client authenticated by API key. What it may do is decided by
+	// the scopes of its key, never by role checks.
+	RoleService Role = "service"
+)
+
+type Scope string
+
+const (
+	// ScopeRegistrationsRead reads any student's registrations, waitlists and profile
+	ScopeRegistrationsRead Scope = "registrations:read"
+	// ScopeRegistrationsWrite registers, drops and changes registrations for any student
+	ScopeRegistrationsWrite Scope = "registrations:write"
+	// ScopeAdmin uses the registrar admin API
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes lists every scope an API key may be granted
+var Scopes = []Scope{ScopeRegistrationsRead, ScopeRegistrationsWrite, ScopeAdmin}
+
+// ValidScope reports whether scope is one of Scopes
+func ValidScope(scope Scope) bool {
+	for _, known := range Scopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrMissingToken  = errors.New("missing bearer token")
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrNoSecret      = errors.New("auth.jwt_secret must be set when authentication is enabled")
+	ErrUnknownRole   = errors.New("token has an unknown role")
+	ErrNoStudentID   = errors.New("student tokens must carry a student_id claim")
+	ErrForbidden     = errors.New("not allowed for this role")
+	ErrNotOwnStudent = errors.New("students may only act on their own student_id")
+)
+
+// Claims are the JWT claims the API reads. The subject identifies the user; student tokens
+// also carry the student record they belong to.
+type Claims struct {
+	Role      Role       `json:"role"`
+	StudentID *uuid.UUID `json:"student_id,omitempty"`
+	// Scopes are only set for API key clients
+	Scopes []Scope `json:"-"`
+	jwt.RegisteredClaims
+}
+
+// ServiceClaims describes a machine client authenticated by the API key keyID
+func ServiceClaims(keyID uuid.UUID, scopes []Scope) *Claims {
+	return &Claims{
+		Role:             RoleService,
+		Scopes:           scopes,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "api-key:" + keyID.String()},
+	}
+}
+
+// HasRole reports whether the caller has one of roles
+func (c *Claims) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if c.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether an API key client was granted scope
+func (c *Claims) HasScope(scope Scope) bool {
+	if c.Role != RoleService {
+		return false
+	}
+	for _, granted := range c.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the caller passes a check that users meet with one of roles and API
+// key clients meet with scope
+func (c *Claims) Allows(scope Scope, roles ...Role) bool {
+	if c.Role == RoleService {
+		return c.HasScope(scope)
+	}
+	return c.HasRole(roles...)
+}
+
+// CanActFor reports whether the caller may read or change studentID's registrations.
+// Registrars and admins act for any student; students only for themselves. API key clients
+// need the registrations:write scope.
+func (c *Claims) CanActFor(studentID uuid.UUID) bool {
+	if c.HasRole(RoleRegistrar, RoleAdmin) || c.HasScope(ScopeRegistrationsWrite) {
+		return true
+	}
+	return c.Role == RoleStudent && c.StudentID != nil && *c.StudentID == studentID
+}
+
+// Authenticator signs and verifies HS256 tokens with the configured shared secret
+type Authenticator struct {
+	secret   []byte
+	issuer   string
+	audience string
+	tokenTTL time.Duration
+}
+
+func NewAuthenticator(cfg *config.AuthConfig) (*Authenticator, error) {
+	if cfg.JWTSecret == "" {
+		return nil, ErrNoSecret
+	}
+
+	return &Authenticator{
+		secret:   []byte(cfg.JWTSecret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		tokenTTL: time.Duration(cfg.TokenTTLMinutes) * time.Minute,
+	}, nil
+}
+
+// Verify parses a token and checks its signature, expiry, issuer, audience and role
+func (a *Authenticator) Verify(token string) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if a.issuer != "" {
+		options = append(options, jwt.WithIssuer(a.issuer))
+	}
+	if a.audience != "" {
+		options = append(options, jwt.WithAudience(a.audience))
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return a.secret, nil
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err := claims.validate(); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// Issue signs a token for subject. It is meant for tooling and tests; users normally get
+// their tokens from the institution's identity provider.
+func (a *Authenticator) Issue(subject string, role Role, studentID *uuid.UUID) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Role:      role,
+		StudentID: studentID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    a.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.tokenTTL)),
+		},
+	}
+	if a.audience != "" {
+		claims.Audience = jwt.ClaimStrings{a.audience}
+	}
+	if err := claims.validate(); err != nil {
+		return "", err
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
+
+// CanReadFor is CanActFor for reads, which API key clients may also do with the
+// registrations:read scope
+func (c *Claims) CanReadFor(studentID uuid.UUID) bool {
+	return c.CanActFor(studentID) || c.HasScope(ScopeRegistrationsRead)
+}
+
+func (c *Claims) validate() error {
+	switch c.Role {
+	case RoleStudent:
+		if c.StudentID == nil || *c.StudentID == uuid.Nil {
+			return ErrNoStudentID
+		}
+	case RoleAdvisor, RoleRegistrar, RoleAdmin:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownRole, c.Role)
+	}
+	return nil
+}
+
+type claimsKey struct{}
+
+// NewContext returns a copy of ctx carrying the caller's claims
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the caller's claims, if the request was authenticated
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// Anonymous stands in for the caller when authentication is disabled, which keeps local
+// development and existing deployments working unchanged
+func Anonymous() *Claims {
+	return &Claims{
+		Role:             RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "anonymous"},
+	}
+}
+
+// AuthorizeStudent checks that the caller in ctx may act for studentID
+func AuthorizeStudent(ctx context.Context, studentID uuid.UUID) error {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return ErrMissingToken
+	}
+	if !claims.CanActFor(studentID) {
+		return ErrNotOwnStudent
+	}
+	return nil
+}
+
+// AuthorizeStudentRead checks that the caller in ctx may read studentID's data
+func AuthorizeStudentRead(ctx context.Context, studentID uuid.UUID) error {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return ErrMissingToken
+	}
+	if !claims.CanReadFor(studentID) {
+		return ErrNotOwnStudent
+	}
+	return nil
+}
@@ -7,13 +7,26 @@ import (
 )
 
 type Config struct {
-	App          AppConfig          `mapstructure:"app"`
-	Server       ServerConfig       `mapstructure:"server"`
-	Database     DatabaseConfig     `mapstructure:"database"`
-	Cache        CacheConfig        `mapstructure:"cache"`
-	Queue        QueueConfig        `mapstructure:"queue"`
-	Registration RegistrationConfig `mapstructure:"registration"`
-	Log          LogConfig          `mapstructure:"log"`
+	App            AppConfig            `mapstructure:"app"`
+	Institution    InstitutionConfig    `mapstructure:"institution"`
+	Server         ServerConfig         `mapstructure:"server"`
+	GRPC           GRPCConfig           `mapstructure:"grpc"`
+	Auth           AuthConfig           `mapstructure:"auth"`
+	Database       DatabaseConfig       `mapstructure:"database"`
+	Cache          CacheConfig          `mapstructure:"cache"`
+	Queue          QueueConfig          `mapstructure:"queue"`
+	Registration   RegistrationConfig   `mapstructure:"registration"`
+	Reminders      RemindersConfig      `mapstructure:"reminders"`
+	Forecasting    ForecastingConfig    `mapstructure:"forecasting"`
+	Reconciliation ReconciliationConfig `mapstructure:"reconciliation"`
+	Scheduler      SchedulerConfig      `mapstructure:"scheduler"`
+	Approvals      ApprovalsConfig      `mapstructure:"approvals"`
+	Billing        BillingConfig        `mapstructure:"billing"`
+	Analytics      AnalyticsConfig      `mapstructure:"analytics"`
+	Log            LogConfig            `mapstructure:"log"`
+	Diagnostics    DiagnosticsConfig    `mapstructure:"diagnostics"`
+	Storage        StorageConfig        `mapstructure:"storage"`
+	Tracing        TracingConfig        `mapstructure:"tracing"`
 }
 
 type AppConfig struct {
@@ -23,6 +36,13 @@ type AppConfig struct {
 	Description string `mapstructure:"description"`
 }
 
+// InstitutionConfig describes the institution a deployment serves. Timezone is the IANA
+// zone that registration windows and deadlines are evaluated in.
+type InstitutionConfig struct {
+	Name     string `mapstructure:"name"`
+	Timezone string `mapstructure:"timezone"`
+}
+
 type ServerConfig struct {
 	Host           string `mapstructure:"host"`
 	Port           string `mapstructure:"port"`
@@ -31,6 +51,26 @@ type ServerConfig struct {
 	MaxHeaderBytes int    `mapstructure:"max_header_bytes"`
 }
 
+// GRPCConfig controls the gRPC API served next to the REST API
+type GRPCConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Host    string `mapstructure:"host"`
+	Port    string `mapstructure:"port"`
+}
+
+// AuthConfig controls bearer token authentication. Tokens are HS256 JWTs signed with
+// JWTSecret; Issuer and Audience are checked when set. The secret can also be supplied
+// through the AUTH_JWT_SECRET environment variable.
+type AuthConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	JWTSecret       string `mapstructure:"jwt_secret"`
+	Issuer          string `mapstructure:"issuer"`
+	Audience        string `mapstructure:"audience"`
+	TokenTTLMinutes int    `mapstructure:"token_ttl_minutes"`
+	// APIKeyRateLimit is the per-minute request budget of API keys without their own limit
+	APIKeyRateLimit int `mapstructure:"api_key_rate_limit"`
+}
+
 type DatabaseConfig struct {
 	Driver                 string `mapstructure:"driver"`
 	Host                   string `mapstructure:"host"`
@@ -45,17 +85,42 @@ type DatabaseConfig struct {
 }
 
 type CacheConfig struct {
-	Type        string         `mapstructure:"type"`
-	Host        string         `mapstructure:"host"`
-	Port        int            `mapstructure:"port"`
-	Password    string         `mapstructure:"password"`
-	DB          int            `mapstructure:"db"`
-	MaxRetries  int            `mapstructure:"max_retries"`
-	PoolSize    int            `mapstructure:"pool_size"`
-	PoolTimeout int            `mapstructure:"pool_timeout"`
-	IdleTimeout int            `mapstructure:"idle_timeout"`
-	TTLMinutes  int            `mapstructure:"ttl_minutes"`
+	Type              string `mapstructure:"type"`
+	Host              string `mapstructure:"host"`
+	Port              int    `mapstructure:"port"`
+	Password          string `mapstructure:"password"`
+	DB                int    `mapstructure:"db"`
+	MaxRetries        int    `mapstructure:"max_retries"`
+	PoolSize          int    `mapstructure:"pool_size"`
+	PoolTimeout       int    `mapstructure:"pool_timeout"`
+	IdleTimeout       int    `mapstructure:"idle_timeout"`
+	TTLMinutes        int    `mapstructure:"ttl_minutes"`
+	WarmupConcurrency int    `mapstructure:"warmup_concurrency"`
+	// WarmupBatchSize is the number of seat counters written in one round trip on warmup
+	WarmupBatchSize int `mapstructure:"warmup_batch_size"`
+	// WarmupScope is what is cached at startup: none, active or loadtest
+	WarmupScope string         `mapstructure:"warmup_scope"`
 	Sentinel    SentinelConfig `mapstructure:"sentinel"`
+	// ScriptRetry bounds the retries of seat and waitlist Lua scripts on transient errors
+	ScriptRetry ScriptRetryConfig `mapstructure:"script_retry"`
+	// Local is the in-process cache in front of Redis for hot read paths
+	Local LocalCacheConfig `mapstructure:"local"`
+}
+
+// LocalCacheConfig sizes the in-process cache. Entries live for TTLSeconds at most; writes
+// on any server drop them everywhere sooner through Redis pub/sub.
+type LocalCacheConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	Size       int  `mapstructure:"size"`
+	TTLSeconds int  `mapstructure:"ttl_seconds"`
+}
+
+// ScriptRetryConfig is the retry budget of a Lua script call. Delays grow from BaseDelayMs
+// and are capped at MaxDelayMs, with full jitter.
+type ScriptRetryConfig struct {
+	MaxAttempts int `mapstructure:"max_attempts"`
+	BaseDelayMs int `mapstructure:"base_delay_ms"`
+	MaxDelayMs  int `mapstructure:"max_delay_ms"`
 }
 
 type SentinelConfig struct {
@@ -70,15 +135,159 @@ type QueueConfig struct {
 	BufferSize    int    `mapstructure:"buffer_size"`
 	WorkerCount   int    `mapstructure:"worker_count"`
 	RetryAttempts int    `mapstructure:"retry_attempts"`
+	// Environment prefixes every queue name and the sync outbox, so several environments can
+	// share one Redis
+	Environment string           `mapstructure:"environment"`
+	Names       QueueNamesConfig `mapstructure:"names"`
+	// SeatSyncWindowMS coalesces seat update jobs into one write per section per window;
+	// zero writes every job as it comes
+	SeatSyncWindowMS  int `mapstructure:"seat_sync_window_ms"`
+	SeatSyncMaxEvents int `mapstructure:"seat_sync_max_events"`
+	// PriorityLanes keeps registration-critical sync jobs from waiting behind slower ones
+	PriorityLanes PriorityLanesConfig `mapstructure:"priority_lanes"`
+	// Kafka is used when Type is "kafka"
+	Kafka KafkaConfig `mapstructure:"kafka"`
+	// DrainTimeoutSeconds bounds how long shutdown waits for jobs in progress before putting
+	// them back on the queue
+	DrainTimeoutSeconds int `mapstructure:"drain_timeout_seconds"`
+}
+
+type KafkaConfig struct {
+	Brokers []string `mapstructure:"brokers"`
+	GroupID string   `mapstructure:"group_id"`
+}
+
+// PriorityLanesConfig splits database sync jobs into a critical lane for the jobs that keep
+// seat counts consistent and a normal lane for the rest, dequeued in proportion to the weights
+type PriorityLanesConfig struct {
+	Enabled        bool `mapstructure:"enabled"`
+	CriticalWeight int  `mapstructure:"critical_weight"`
+	NormalWeight   int  `mapstructure:"normal_weight"`
+}
+
+type QueueNamesConfig struct {
+	DatabaseSync  string `mapstructure:"database_sync"`
+	Waitlist      string `mapstructure:"waitlist"`
+	WaitlistEntry string `mapstructure:"waitlist_entry"`
+	Poison        string `mapstructure:"poison"`
+	Reminders     string `mapstructure:"reminders"`
+	Audit         string `mapstructure:"audit"`
 }
 
 type RegistrationConfig struct {
-	MaxCoursesPerStudent         int    `mapstructure:"max_courses_per_student"`
+	MaxCoursesPerStudent int `mapstructure:"max_courses_per_student"`
+	// WaitlistMaxSize caps each section's waitlist unless the section sets its own cap; 0 is
+	// no cap
 	WaitlistMaxSize              int    `mapstructure:"waitlist_max_size"`
 	RegistrationTimeoutMinutes   int    `mapstructure:"registration_timeout_minutes"`
 	ConcurrentRegistrationsLimit int    `mapstructure:"concurrent_registrations_limit"`
 	WaitlistRepository           string `mapstructure:"waitlist_repository"`
 	WaitlistFallbackEnabled      bool   `mapstructure:"waitlist_fallback_enabled"`
+	// IdempotencyStore is redis, or hybrid to also persist idempotency keys to Postgres
+	IdempotencyStore    string `mapstructure:"idempotency_store"`
+	SeatOfferTTLMinutes int    `mapstructure:"seat_offer_ttl_minutes"`
+	SeatHoldTTLMinutes  int    `mapstructure:"seat_hold_ttl_minutes"`
+	// WaitlistTTLHours is how long a waitlist entry lasts unless its section sets its own
+	// lifetime; 0 keeps entries until the student leaves or is promoted
+	WaitlistTTLHours            int    `mapstructure:"waitlist_ttl_hours"`
+	PersistenceMode             string `mapstructure:"persistence_mode"`
+	SnapshotInterval            int    `mapstructure:"snapshot_interval"`
+	StudentLockTTLSeconds       int    `mapstructure:"student_lock_ttl_seconds"`
+	StudentLockWaitMilliseconds int    `mapstructure:"student_lock_wait_ms"`
+	// StrictJSON rejects registration requests with unknown or trailing fields instead of
+	// ignoring them
+	StrictJSON bool `mapstructure:"strict_json"`
+	// DBFallback takes seats straight from Postgres while Redis is unreachable
+	DBFallback DBFallbackConfig `mapstructure:"db_fallback"`
+}
+
+// DBFallbackConfig controls registration without Redis. After FailureThreshold seat counter
+// calls in a row fail, the circuit opens and registrations lock the section row in Postgres
+// instead; every OpenSeconds one registration tries the counter again and closes the
+// circuit if it answers.
+type DBFallbackConfig struct {
+	Enabled          bool `mapstructure:"enabled"`
+	FailureThreshold int  `mapstructure:"failure_threshold"`
+	OpenSeconds      int  `mapstructure:"open_seconds"`
+}
+
+// RemindersConfig controls the deadline reminders sent to students ahead of the add/drop
+// deadline. A student is reminded when they are still on a waitlist, or when they registered
+// for the semester but are enrolled in fewer than MinEnrolledSections sections.
+type RemindersConfig struct {
+	Enabled             bool  `mapstructure:"enabled"`
+	LeadHours           []int `mapstructure:"lead_hours"`
+	MinEnrolledSections int   `mapstructure:"min_enrolled_sections"`
+}
+
+// ForecastingConfig controls the nightly enrollment forecast. Each section's final
+// enrollment is predicted from how earlier semesters filled up by the same day of their
+// registration window. A section predicted above capacity is flagged as over-subscribed,
+// one predicted below UnderSubscribedRatio of capacity as under-subscribed.
+type ForecastingConfig struct {
+	Enabled              bool    `mapstructure:"enabled"`
+	RunHour              int     `mapstructure:"run_hour"`
+	UnderSubscribedRatio float64 `mapstructure:"under_subscribed_ratio"`
+}
+
+// ReconciliationConfig controls the comparison of the Redis seat counters with the enrolled
+// registrations in the database, scheduled by scheduler.seat_reconciliation. Discrepancies
+// are always reported. With Heal set, a counter that still disagrees after SettleSeconds,
+// with neither side having moved, is reset from the database; the wait lets queued database
+// syncs catch up first.
+type ReconciliationConfig struct {
+	Heal          bool `mapstructure:"heal"`
+	SettleSeconds int  `mapstructure:"settle_seconds"`
+}
+
+// SchedulerConfig turns the recurring maintenance tasks on and off and sets how often they run
+type SchedulerConfig struct {
+	// IdempotencyCleanup prunes expired idempotency keys from Postgres; only the hybrid
+	// idempotency store keeps them there
+	IdempotencyCleanup ScheduledTaskConfig `mapstructure:"idempotency_cleanup"`
+	// SeatOfferExpiry releases the seats of waitlist offers and seat holds that ran out
+	SeatOfferExpiry ScheduledTaskConfig `mapstructure:"seat_offer_expiry"`
+	// WaitlistExpiry takes students off waitlists once their entry has expired
+	WaitlistExpiry     ScheduledTaskConfig `mapstructure:"waitlist_expiry"`
+	SeatReconciliation ScheduledTaskConfig `mapstructure:"seat_reconciliation"`
+	// CacheRefresh reseeds the seat counters and section lists of active semesters
+	CacheRefresh ScheduledTaskConfig `mapstructure:"cache_refresh"`
+}
+
+type ScheduledTaskConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds"`
+}
+
+// ApprovalsConfig controls the two-person approval of destructive admin operations. A
+// proposal lapses unless a second admin approves it within WindowMinutes.
+type ApprovalsConfig struct {
+	WindowMinutes int `mapstructure:"window_minutes"`
+}
+
+// BillingConfig controls the webhook the bursar places and releases financial holds
+// through. Callbacks are signed with WebhookSecret; without one the webhook is disabled.
+// Signatures older than SignatureToleranceSeconds are refused so captured callbacks cannot
+// be replayed.
+type BillingConfig struct {
+	WebhookSecret             string `mapstructure:"webhook_secret"`
+	SignatureToleranceSeconds int    `mapstructure:"signature_tolerance_seconds"`
+}
+
+// AnalyticsConfig chooses where registration analytics events go. Sink is "redis" for a
+// Redis stream, "kafka" for a topic on the queue's brokers, "file" for JSON lines, or
+// "none". Events wait in a buffer of BufferSize and are dropped when it is full, so a slow
+// sink never holds up a registration.
+type AnalyticsConfig struct {
+	Sink       string `mapstructure:"sink"`
+	BufferSize int    `mapstructure:"buffer_size"`
+	// Stream and StreamMaxLen are used by the redis sink; older events are trimmed
+	Stream       string `mapstructure:"stream"`
+	StreamMaxLen int64  `mapstructure:"stream_max_len"`
+	// Topic is used by the kafka sink
+	Topic string `mapstructure:"topic"`
+	// FilePath is used by the file sink
+	FilePath string `mapstructure:"file_path"`
 }
 
 type LogConfig struct {
@@ -88,12 +297,58 @@ type LogConfig struct {
 	FilePath string `mapstructure:"file_path"`
 }
 
+// DiagnosticsConfig controls the internal server exposing pprof and on-demand profiles
+type DiagnosticsConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	Host              string `mapstructure:"host"`
+	Port              string `mapstructure:"port"`
+	MaxProfileSeconds int    `mapstructure:"max_profile_seconds"`
+}
+
+// TracingConfig controls OpenTelemetry tracing and the OTLP/HTTP exporter spans are sent to
+type TracingConfig struct {
+	Enabled      bool    `mapstructure:"enabled"`
+	ServiceName  string  `mapstructure:"service_name"`
+	OTLPEndpoint string  `mapstructure:"otlp_endpoint"`
+	Insecure     bool    `mapstructure:"insecure"`
+	SampleRatio  float64 `mapstructure:"sample_ratio"`
+}
+
+// StorageConfig selects where exports, archives and profiles are written: local, s3 or gcs
+type StorageConfig struct {
+	Provider               string             `mapstructure:"provider"`
+	SignedURLExpiryMinutes int                `mapstructure:"signed_url_expiry_minutes"`
+	Local                  LocalStorageConfig `mapstructure:"local"`
+	S3                     BucketConfig       `mapstructure:"s3"`
+	GCS                    BucketConfig       `mapstructure:"gcs"`
+}
+
+type LocalStorageConfig struct {
+	BasePath   string `mapstructure:"base_path"`
+	BaseURL    string `mapstructure:"base_url"`
+	SigningKey string `mapstructure:"signing_key"`
+}
+
+// BucketConfig describes an S3 compatible bucket. GCS buckets are accessed through the
+// S3 interoperability API using HMAC keys.
+type BucketConfig struct {
+	Endpoint        string `mapstructure:"endpoint"`
+	Region          string `mapstructure:"region"`
+	Bucket          string `mapstructure:"bucket"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	UseSSL          bool   `mapstructure:"use_ssl"`
+}
+
 var config *Config
 
 func Init() {
 	config = &Config{}
 
 	setDefaults()
+	// Secrets are kept out of the config files
+	_ = viper.BindEnv("auth.jwt_secret", "AUTH_JWT_SECRET")
+	_ = viper.BindEnv("billing.webhook_secret", "BILLING_WEBHOOK_SECRET")
 	if err := viper.Unmarshal(config); err != nil {
 		log.Fatalf("Unable to decode config: %v", err)
 	}
@@ -111,6 +366,8 @@ func setDefaults() {
 	viper.SetDefault("app.name", "cobra-template")
 	viper.SetDefault("app.version", "1.0.0")
 	viper.SetDefault("app.environment", "development")
+	viper.SetDefault("institution.name", "")
+	viper.SetDefault("institution.timezone", "UTC")
 	viper.SetDefault("server.host", "localhost")
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.read_timeout", 15)
@@ -136,6 +393,15 @@ func setDefaults() {
 	viper.SetDefault("cache.pool_timeout", 30)
 	viper.SetDefault("cache.idle_timeout", 300)
 	viper.SetDefault("cache.ttl_minutes", 60)
+	viper.SetDefault("cache.warmup_concurrency", 4)
+	viper.SetDefault("cache.warmup_batch_size", 500)
+	viper.SetDefault("cache.warmup_scope", WarmupScopeActive)
+	viper.SetDefault("cache.script_retry.max_attempts", 3)
+	viper.SetDefault("cache.script_retry.base_delay_ms", 25)
+	viper.SetDefault("cache.script_retry.max_delay_ms", 250)
+	viper.SetDefault("cache.local.enabled", false)
+	viper.SetDefault("cache.local.size", 10000)
+	viper.SetDefault("cache.local.ttl_seconds", 5)
 	viper.SetDefault("cache.sentinel.enabled", true)
 	viper.SetDefault("cache.sentinel.master_name", "mymaster")
 	viper.SetDefault("cache.sentinel.sentinel_addrs", []string{"redis-sentinel-1:26379", "redis-sentinel-2:26379", "redis-sentinel-3:26379"})
@@ -144,14 +410,97 @@ func setDefaults() {
 	viper.SetDefault("queue.buffer_size", 1000)
 	viper.SetDefault("queue.worker_count", 10)
 	viper.SetDefault("queue.retry_attempts", 3)
+	viper.SetDefault("queue.environment", "")
+	viper.SetDefault("queue.names.database_sync", "database_sync")
+	viper.SetDefault("queue.names.waitlist", "waitlist")
+	viper.SetDefault("queue.names.waitlist_entry", "waitlist_entry")
+	viper.SetDefault("queue.names.poison", "poison")
+	viper.SetDefault("queue.names.reminders", "reminders")
+	viper.SetDefault("queue.names.audit", "audit")
+	viper.SetDefault("queue.seat_sync_window_ms", 500)
+	viper.SetDefault("queue.seat_sync_max_events", 100)
+	viper.SetDefault("queue.priority_lanes.enabled", false)
+	viper.SetDefault("queue.priority_lanes.critical_weight", 4)
+	viper.SetDefault("queue.priority_lanes.normal_weight", 1)
+	viper.SetDefault("queue.kafka.brokers", []string{"localhost:9092"})
+	viper.SetDefault("queue.kafka.group_id", "registration-workers")
+	viper.SetDefault("queue.drain_timeout_seconds", 20)
 	viper.SetDefault("registration.max_courses_per_student", 6)
 	viper.SetDefault("registration.waitlist_max_size", 50)
 	viper.SetDefault("registration.registration_timeout_minutes", 5)
 	viper.SetDefault("registration.concurrent_registrations_limit", 100)
 	viper.SetDefault("registration.waitlist_repository", "redis")
 	viper.SetDefault("registration.waitlist_fallback_enabled", true)
+	viper.SetDefault("registration.idempotency_store", "redis")
+	viper.SetDefault("registration.seat_offer_ttl_minutes", 30)
+	viper.SetDefault("registration.seat_hold_ttl_minutes", 10)
+	viper.SetDefault("registration.waitlist_ttl_hours", 0)
+	viper.SetDefault("registration.persistence_mode", "state")
+	viper.SetDefault("registration.snapshot_interval", 100)
+	viper.SetDefault("registration.student_lock_ttl_seconds", 10)
+	viper.SetDefault("registration.student_lock_wait_ms", 2000)
+	viper.SetDefault("registration.strict_json", false)
+	viper.SetDefault("registration.db_fallback.enabled", false)
+	viper.SetDefault("registration.db_fallback.failure_threshold", 5)
+	viper.SetDefault("registration.db_fallback.open_seconds", 30)
+	viper.SetDefault("reminders.enabled", true)
+	viper.SetDefault("reminders.lead_hours", []int{48})
+	viper.SetDefault("reminders.min_enrolled_sections", 1)
+	viper.SetDefault("forecasting.enabled", true)
+	viper.SetDefault("forecasting.run_hour", 2)
+	viper.SetDefault("forecasting.under_subscribed_ratio", 0.5)
+	viper.SetDefault("reconciliation.heal", false)
+	viper.SetDefault("reconciliation.settle_seconds", 30)
+	viper.SetDefault("scheduler.idempotency_cleanup.enabled", true)
+	viper.SetDefault("scheduler.idempotency_cleanup.interval_seconds", 3600)
+	viper.SetDefault("scheduler.seat_offer_expiry.enabled", true)
+	viper.SetDefault("scheduler.seat_offer_expiry.interval_seconds", 15)
+	viper.SetDefault("scheduler.waitlist_expiry.enabled", true)
+	viper.SetDefault("scheduler.waitlist_expiry.interval_seconds", 300)
+	viper.SetDefault("scheduler.seat_reconciliation.enabled", true)
+	viper.SetDefault("scheduler.seat_reconciliation.interval_seconds", 900)
+	viper.SetDefault("scheduler.cache_refresh.enabled", false)
+	viper.SetDefault("scheduler.cache_refresh.interval_seconds", 900)
+	viper.SetDefault("approvals.window_minutes", 60)
+	viper.SetDefault("billing.webhook_secret", "")
+	viper.SetDefault("billing.signature_tolerance_seconds", 300)
+	viper.SetDefault("analytics.sink", "none")
+	viper.SetDefault("analytics.buffer_size", 1000)
+	viper.SetDefault("analytics.stream", "analytics:registration")
+	viper.SetDefault("analytics.stream_max_len", 100000)
+	viper.SetDefault("analytics.topic", "registration-analytics")
+	viper.SetDefault("analytics.file_path", "logs/analytics.jsonl")
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
 	viper.SetDefault("log.output", "stdout")
 	viper.SetDefault("log.file_path", "")
+	viper.SetDefault("grpc.enabled", false)
+	viper.SetDefault("grpc.host", "")
+	viper.SetDefault("grpc.port", "9090")
+	viper.SetDefault("auth.enabled", false)
+	viper.SetDefault("auth.jwt_secret", "")
+	viper.SetDefault("auth.issuer", "")
+	viper.SetDefault("auth.audience", "")
+	viper.SetDefault("auth.token_ttl_minutes", 60)
+	viper.SetDefault("auth.api_key_rate_limit", 600)
+	viper.SetDefault("diagnostics.enabled", true)
+	viper.SetDefault("diagnostics.host", "127.0.0.1")
+	viper.SetDefault("diagnostics.port", "6060")
+	viper.SetDefault("diagnostics.max_profile_seconds", 120)
+	viper.SetDefault("storage.provider", "local")
+	viper.SetDefault("storage.signed_url_expiry_minutes", 15)
+	viper.SetDefault("storage.local.base_path", "./storage")
+	viper.SetDefault("storage.local.base_url", "http://localhost:8080")
+	viper.SetDefault("storage.local.signing_key", "")
+	viper.SetDefault("storage.s3.endpoint", "s3.amazonaws.com")
+	viper.SetDefault("storage.s3.region", "us-east-1")
+	viper.SetDefault("storage.s3.use_ssl", true)
+	viper.SetDefault("storage.gcs.endpoint", "storage.googleapis.com")
+	viper.SetDefault("storage.gcs.region", "auto")
+	viper.SetDefault("storage.gcs.use_ssl", true)
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.service_name", "course-registration")
+	viper.SetDefault("tracing.otlp_endpoint", "localhost:4318")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 }
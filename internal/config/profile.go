@@ -0,0 +1,58 @@
+package config
+
+import "fmt"
+
+// Bootstrap profiles of the registration command
+const (
+	ProfileDev      = "dev"
+	ProfileLoadTest = "loadtest"
+	ProfileProd     = "prod"
+)
+
+// Cache warmup scopes run at startup
+const (
+	// WarmupScopeNone skips the startup warmup; the caches fill on first use
+	WarmupScopeNone = "none"
+	// WarmupScopeActive seeds seat counters and available sections of active semesters
+	WarmupScopeActive = "active"
+	// WarmupScopeLoadTest also caches the details of every open section
+	WarmupScopeLoadTest = "loadtest"
+)
+
+// ApplyProfile overrides the settings that make up a bootstrap profile, leaving connection
+// details and everything else from the config file alone:
+//
+//   - dev runs on one machine: in-memory cache and queue, the database waitlist, no auth
+//   - loadtest uses the Redis stores a cluster would, with the local cache in front of
+//     Redis and every open section warmed before traffic arrives, and no auth
+//   - prod uses the Redis stores and enforces authentication
+//
+// An empty profile changes nothing.
+func ApplyProfile(cfg *Config, profile string) error {
+	switch profile {
+	case "":
+	case ProfileDev:
+		cfg.Cache.Type = "memory"
+		cfg.Cache.Local.Enabled = false
+		cfg.Cache.WarmupScope = WarmupScopeActive
+		cfg.Queue.Type = "memory"
+		cfg.Registration.WaitlistRepository = "database"
+		cfg.Auth.Enabled = false
+	case ProfileLoadTest:
+		cfg.Cache.Type = "redis"
+		cfg.Cache.Local.Enabled = true
+		cfg.Cache.WarmupScope = WarmupScopeLoadTest
+		cfg.Queue.Type = "redis"
+		cfg.Registration.WaitlistRepository = "redis"
+		cfg.Auth.Enabled = false
+	case ProfileProd:
+		cfg.Cache.Type = "redis"
+		cfg.Cache.WarmupScope = WarmupScopeActive
+		cfg.Queue.Type = "redis"
+		cfg.Registration.WaitlistRepository = "redis"
+		cfg.Auth.Enabled = true
+	default:
+		return fmt.Errorf("unknown profile %q (want %s, %s or %s)", profile, ProfileDev, ProfileLoadTest, ProfileProd)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,13 +14,17 @@ type Registration struct {
 	RegistrationID   uuid.UUID          `json:"registration_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	StudentID        uuid.UUID          `json:"student_id" gorm:"type:uuid;not null;constraint:OnDelete:CASCADE"`
 	SectionID        uuid.UUID          `json:"section_id" gorm:"type:uuid;not null;constraint:OnDelete:CASCADE"`
+	SemesterID       uuid.UUID          `json:"semester_id" gorm:"type:uuid;not null"`
 	Status           RegistrationStatus `json:"status" gorm:"type:registration_status;default:enrolled"`
 	RegistrationDate time.Time          `json:"registration_date" gorm:"type:timestamptz;default:now()"`
 	CreatedAt        time.Time          `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt        time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
 	Version          int                `json:"version" gorm:"default:1"`
-	Student          Student            `json:"student,omitempty" gorm:"foreignKey:StudentID;references:StudentID"`
-	Section          Section            `json:"section,omitempty" gorm:"foreignKey:SectionID;references:SectionID"`
+	// SeatPoolID is the seat pool the seat was taken from, if any, so dropping the
+	// registration gives the seat back to the pool
+	SeatPoolID *uuid.UUID `json:"seat_pool_id,omitempty" gorm:"type:uuid"`
+	Student    Student    `json:"student,omitempty" gorm:"foreignKey:StudentID;references:StudentID"`
+	Section    Section    `json:"section,omitempty" gorm:"foreignKey:SectionID;references:SectionID"`
 }
 
 func (Registration) TableName() string {
@@ -29,33 +36,89 @@ func (r *Registration) BeforeCreate(db *gorm.DB) error {
 }
 
 type Student struct {
-	StudentID        uuid.UUID `json:"student_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	StudentNumber    string    `json:"student_number" gorm:"type:text;unique;not null"`
-	FirstName        string    `json:"first_name" gorm:"type:varchar(100);not null"`
-	LastName         string    `json:"last_name" gorm:"type:varchar(100);not null"`
-	EnrollmentStatus string    `json:"enrollment_status" gorm:"type:varchar(20);default:'active'"`
-	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	Version          int       `json:"version" gorm:"default:1"`
+	StudentID        uuid.UUID  `json:"student_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	StudentNumber    string     `json:"student_number" gorm:"type:text;unique;not null"`
+	FirstName        string     `json:"first_name" gorm:"type:varchar(100);not null"`
+	LastName         string     `json:"last_name" gorm:"type:varchar(100);not null"`
+	PreferredName    string     `json:"preferred_name,omitempty" gorm:"type:varchar(100)"`
+	Email            string     `json:"email,omitempty" gorm:"type:varchar(255)"`
+	Phone            string     `json:"phone,omitempty" gorm:"type:varchar(20)"`
+	EnrollmentStatus string     `json:"enrollment_status" gorm:"type:varchar(20);default:'active'"`
+	ArchivedAt       *time.Time `json:"archived_at,omitempty" gorm:"type:timestamptz"`
+	// Major, Cohort and Year are what seat pools decide eligibility on. Year is the year of
+	// study, 1 for first-years; zero when unknown.
+	Major  string `json:"major,omitempty" gorm:"type:varchar(100)"`
+	Cohort string `json:"cohort,omitempty" gorm:"type:varchar(50)"`
+	Year   int    `json:"year,omitempty" gorm:"not null;default:0"`
+	// DeadlineReminders is whether the student wants reminders ahead of registration deadlines
+	DeadlineReminders bool      `json:"deadline_reminders" gorm:"not null;default:true"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	Version           int       `json:"version" gorm:"default:1"`
 }
 
+const (
+	EnrollmentStatusActive = "active"
+	// EnrollmentStatusInactive is a student an administrator deactivated, e.g. on leave.
+	// They keep their registrations but cannot take new seats until reactivated.
+	EnrollmentStatusInactive = "inactive"
+	// EnrollmentStatusArchived marks alumni. Their history stays readable but they can no
+	// longer register, drop or hold seats.
+	EnrollmentStatusArchived = "archived"
+)
+
 func (Student) TableName() string {
 	return "students"
 }
 
+func (s *Student) IsArchived() bool {
+	return s.EnrollmentStatus == EnrollmentStatusArchived
+}
+
+// DisplayName is the name to address the student by in notifications and rosters
+func (s *Student) DisplayName() string {
+	if s.PreferredName != "" {
+		return s.PreferredName
+	}
+	return s.FirstName
+}
+
 type Course struct {
 	CourseID   uuid.UUID `json:"course_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	CourseCode string    `json:"course_code" gorm:"type:text;unique;not null"`
 	CourseName string    `json:"course_name" gorm:"type:text;not null"`
-	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	Version    int       `json:"version" gorm:"default:1"`
+	// Department is the code of the offering department, e.g. "CS"
+	Department  string `json:"department,omitempty" gorm:"type:varchar(20)"`
+	CreditHours int    `json:"credit_hours" gorm:"not null;default:0"`
+	// Active courses are offered; inactive ones stay for history but take no new sections
+	// or registrations
+	Active bool `json:"active" gorm:"not null;default:true"`
+	// AllowRepeat lets students register for the course again after taking it, in a later
+	// semester or in another section of the same one
+	AllowRepeat bool `json:"allow_repeat" gorm:"not null;default:false"`
+	// Tags and Attributes describe the course in the catalog, e.g. "writing-intensive".
+	// Every section of the course inherits them.
+	Tags       []string          `json:"tags" gorm:"type:jsonb;serializer:json;not null"`
+	Attributes map[string]string `json:"attributes" gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+	Version    int               `json:"version" gorm:"default:1"`
 }
 
 func (Course) TableName() string {
 	return "courses"
 }
 
+func (c *Course) BeforeCreate(db *gorm.DB) error {
+	if c.Tags == nil {
+		c.Tags = []string{}
+	}
+	if c.Attributes == nil {
+		c.Attributes = map[string]string{}
+	}
+	return nil
+}
+
 type Semester struct {
 	SemesterID        uuid.UUID `json:"semester_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	SemesterCode      string    `json:"semester_code" gorm:"type:text;unique;not null"`
@@ -64,15 +127,123 @@ type Semester struct {
 	EndDate           time.Time `json:"end_date" gorm:"type:date;not null"`
 	RegistrationStart time.Time `json:"registration_start" gorm:"type:timestamptz;not null"`
 	RegistrationEnd   time.Time `json:"registration_end" gorm:"type:timestamptz;not null"`
-	IsActive          bool      `json:"is_active" gorm:"default:true"`
-	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt         time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	// Dropping a course before DropDeadline frees its seat; until WithdrawDeadline it is a
+	// withdrawal, which keeps the seat. Nil deadlines never pass.
+	DropDeadline     *time.Time `json:"drop_deadline,omitempty" gorm:"type:timestamptz"`
+	WithdrawDeadline *time.Time `json:"withdraw_deadline,omitempty" gorm:"type:timestamptz"`
+	IsActive         bool       `json:"is_active" gorm:"default:true"`
+	// ArchivedAt is when the semester's registrations and waitlist entries were moved to
+	// the archive tables
+	ArchivedAt *time.Time `json:"archived_at,omitempty" gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 func (Semester) TableName() string {
 	return "semesters"
 }
 
+// DropOutcome is what dropping a course at the given time does: StatusDropped before the
+// drop deadline, StatusWithdrawn before the withdraw deadline, and false once both passed
+func (s *Semester) DropOutcome(at time.Time) (RegistrationStatus, bool) {
+	if s.DropDeadline == nil || !at.After(*s.DropDeadline) {
+		return StatusDropped, true
+	}
+	if s.WithdrawDeadline == nil || !at.After(*s.WithdrawDeadline) {
+		return StatusWithdrawn, true
+	}
+	return "", false
+}
+
+// StartsAt is the first instant of the semester in the term's time zone. StartDate and
+// EndDate are calendar dates, so they only become instants once a zone is known.
+func (s *Semester) StartsAt(loc *time.Location) time.Time {
+	return localMidnight(s.StartDate, loc)
+}
+
+// EndsAt is the instant the semester's last day ends in the term's time zone
+func (s *Semester) EndsAt(loc *time.Location) time.Time {
+	return localMidnight(s.EndDate, loc).AddDate(0, 0, 1)
+}
+
+func localMidnight(date time.Time, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+type CalendarEventType string
+
+const (
+	CalendarRegistrationWindow CalendarEventType = "registration_window"
+	CalendarAddDropDeadline    CalendarEventType = "add_drop_deadline"
+	CalendarWithdrawalDeadline CalendarEventType = "withdrawal_deadline"
+	CalendarHoliday            CalendarEventType = "holiday"
+)
+
+// CalendarEvent is a key date of a semester. Deadlines only use StartsAt; windows and
+// holidays spanning several days also set EndsAt.
+type CalendarEvent struct {
+	EventID    uuid.UUID         `json:"event_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	SemesterID uuid.UUID         `json:"semester_id" gorm:"type:uuid;not null;index"`
+	EventType  CalendarEventType `json:"event_type" gorm:"type:varchar(30);not null"`
+	Name       string            `json:"name" gorm:"type:text;not null"`
+	StartsAt   time.Time         `json:"starts_at" gorm:"type:timestamptz;not null"`
+	EndsAt     *time.Time        `json:"ends_at,omitempty" gorm:"type:timestamptz"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (CalendarEvent) TableName() string {
+	return "calendar_events"
+}
+
+// SemesterCalendar is a semester with its key dates in chronological order. Timezone is the
+// term-local zone the dates are expressed in and UTCOffset its current offset from UTC.
+type SemesterCalendar struct {
+	Semester  Semester        `json:"semester"`
+	Timezone  string          `json:"timezone,omitempty"`
+	UTCOffset string          `json:"utc_offset,omitempty"`
+	Events    []CalendarEvent `json:"events"`
+}
+
+// InLocation returns a copy of the calendar with every instant expressed in loc
+func (c SemesterCalendar) InLocation(loc *time.Location) SemesterCalendar {
+	local := c
+	local.Timezone = loc.String()
+	local.UTCOffset = time.Now().In(loc).Format("-07:00")
+	local.Semester.RegistrationStart = c.Semester.RegistrationStart.In(loc)
+	local.Semester.RegistrationEnd = c.Semester.RegistrationEnd.In(loc)
+	if c.Semester.DropDeadline != nil {
+		dropDeadline := c.Semester.DropDeadline.In(loc)
+		local.Semester.DropDeadline = &dropDeadline
+	}
+	if c.Semester.WithdrawDeadline != nil {
+		withdrawDeadline := c.Semester.WithdrawDeadline.In(loc)
+		local.Semester.WithdrawDeadline = &withdrawDeadline
+	}
+
+	local.Events = make([]CalendarEvent, len(c.Events))
+	for i, event := range c.Events {
+		event.StartsAt = event.StartsAt.In(loc)
+		if event.EndsAt != nil {
+			endsAt := event.EndsAt.In(loc)
+			event.EndsAt = &endsAt
+		}
+		local.Events[i] = event
+	}
+	return local
+}
+
+// Deadline returns the earliest event of the given type, if the calendar has one
+func (c *SemesterCalendar) Deadline(eventType CalendarEventType) (time.Time, bool) {
+	for _, event := range c.Events {
+		if event.EventType == eventType {
+			return event.StartsAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
 type Section struct {
 	SectionID      uuid.UUID `json:"section_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	CourseID       uuid.UUID `json:"course_id" gorm:"type:uuid;not null;constraint:OnDelete:CASCADE"`
@@ -81,21 +252,142 @@ type Section struct {
 	TotalSeats     int       `json:"total_seats" gorm:"not null;check:total_seats > 0"`
 	AvailableSeats int       `json:"available_seats" gorm:"not null;check:available_seats >= 0;default:0"`
 	IsActive       bool      `json:"is_active" gorm:"default:true"`
-	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	Version        int       `json:"version" gorm:"default:1"`
-	Course         Course    `json:"course,omitempty" gorm:"foreignKey:CourseID;references:CourseID"`
-	Semester       Semester  `json:"semester,omitempty" gorm:"foreignKey:SemesterID;references:SemesterID"`
+	// A frozen waitlist takes no new students. Promotions out of it carry on unless they are
+	// paused too, in which case seats freed meanwhile are kept for the waitlist.
+	WaitlistFrozen           bool `json:"waitlist_frozen" gorm:"not null;default:false"`
+	WaitlistPromotionsPaused bool `json:"waitlist_promotions_paused" gorm:"not null;default:false"`
+	// WaitlistTTLHours is how long a student stays on the waitlist before their entry expires.
+	// Nil uses registration.waitlist_ttl_hours; zero means the entries never expire.
+	WaitlistTTLHours *int `json:"waitlist_ttl_hours,omitempty" gorm:"check:waitlist_ttl_hours >= 0"`
+	// Policy is stored in columns of the sections table
+	Policy SectionPolicy `json:"policy" gorm:"embedded"`
+	// MeetingDays are the weekdays the section meets on as letters from WeekdayLetters, e.g.
+	// "MWF"; StartTime and EndTime are HH:MM in term-local time. Sections without a fixed
+	// schedule, such as online ones, leave them empty.
+	MeetingDays string `json:"meeting_days,omitempty" gorm:"type:varchar(7)"`
+	StartTime   string `json:"start_time,omitempty" gorm:"type:varchar(5)"`
+	EndTime     string `json:"end_time,omitempty" gorm:"type:varchar(5)"`
+	// Room and Instructor are shown on student schedules; either may be left empty until
+	// it is assigned
+	Room       string `json:"room,omitempty" gorm:"type:varchar(50)"`
+	Instructor string `json:"instructor,omitempty" gorm:"type:varchar(100)"`
+	// LinkedSectionID is set on a component such as a lab to the section it is taken with,
+	// usually the lecture. ComponentIDs lists the active components of a section; a student
+	// registers for the section together with one of them, or not at all.
+	LinkedSectionID *uuid.UUID  `json:"linked_section_id,omitempty" gorm:"type:uuid"`
+	ComponentIDs    []uuid.UUID `json:"component_ids,omitempty" gorm:"-"`
+	// Tags and Attributes are section specific, e.g. "evening" or campus=north; the
+	// section also carries everything set on its course
+	Tags       []string          `json:"tags" gorm:"type:jsonb;serializer:json;not null"`
+	Attributes map[string]string `json:"attributes" gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt  time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+	Version    int               `json:"version" gorm:"default:1"`
+	Course     Course            `json:"course,omitempty" gorm:"foreignKey:CourseID;references:CourseID"`
+	Semester   Semester          `json:"semester,omitempty" gorm:"foreignKey:SemesterID;references:SemesterID"`
+	// SeatPools set aside some of the section's seats for particular students
+	SeatPools []SeatPool `json:"seat_pools,omitempty" gorm:"foreignKey:SectionID;references:SectionID"`
 }
 
+// SectionPolicy holds the registration rules a section sets for itself
+type SectionPolicy struct {
+	// AllowWaitlist lets students queue for the section once it is full
+	AllowWaitlist bool `json:"allow_waitlist" gorm:"not null;default:true"`
+	// ReservedSeats are the last seats of the section, which registration does not take;
+	// waitlist promotions still fill them
+	ReservedSeats int `json:"reserved_seats" gorm:"not null;default:0;check:reserved_seats >= 0"`
+	// InstructorConsentRequired turns away registrations that the instructor has not
+	// consented to
+	InstructorConsentRequired bool `json:"instructor_consent_required" gorm:"not null;default:false"`
+	// MaxWaitlist caps how many students can wait for the section. Nil uses
+	// registration.waitlist_max_size; zero means the waitlist is unbounded.
+	MaxWaitlist *int `json:"max_waitlist,omitempty" gorm:"column:waitlist_max_size;check:waitlist_max_size >= 0"`
+}
+
+// SeatPool sets aside seats of a section for the students its eligibility rules admit, such
+// as majors or first-years. Each rule lists the values it accepts and an empty rule accepts
+// anyone; a student must pass every rule. Pooled seats count towards the section's seats,
+// and a student the pools admit takes a pooled seat before a general one.
+type SeatPool struct {
+	PoolID    uuid.UUID `json:"pool_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	SectionID uuid.UUID `json:"section_id" gorm:"type:uuid;not null;constraint:OnDelete:CASCADE"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	Seats     int       `json:"seats" gorm:"not null;check:seats > 0"`
+	Majors    []string  `json:"majors" gorm:"type:jsonb;serializer:json;not null"`
+	Cohorts   []string  `json:"cohorts" gorm:"type:jsonb;serializer:json;not null"`
+	Years     []int     `json:"years" gorm:"type:jsonb;serializer:json;not null"`
+	// Remaining is the pool's seats left on the counter, filled in for administrators
+	Remaining *int      `json:"remaining,omitempty" gorm:"-"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (SeatPool) TableName() string {
+	return "section_seat_pools"
+}
+
+// Admits reports whether the student passes every eligibility rule of the pool
+func (p *SeatPool) Admits(student *Student) bool {
+	return (len(p.Majors) == 0 || slices.Contains(p.Majors, student.Major)) &&
+		(len(p.Cohorts) == 0 || slices.Contains(p.Cohorts, student.Cohort)) &&
+		(len(p.Years) == 0 || slices.Contains(p.Years, student.Year))
+}
+
+// WeekdayLetters are the day letters of MeetingDays from Monday to Sunday. R is Thursday
+// and U is Sunday.
+const WeekdayLetters = "MTWRFSU"
+
 func (Section) TableName() string {
 	return "sections"
 }
 
 func (s *Section) BeforeCreate(db *gorm.DB) error {
+	if s.Tags == nil {
+		s.Tags = []string{}
+	}
+	if s.Attributes == nil {
+		s.Attributes = map[string]string{}
+	}
 	return nil
 }
 
+// HasTag reports whether the section or its course is tagged with tag
+func (s *Section) HasTag(tag string) bool {
+	for _, tags := range [][]string{s.Tags, s.Course.Tags} {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ClashesWith reports whether the two sections meet in the same semester on a common day at
+// overlapping times. Sections without a fixed schedule never clash.
+func (s *Section) ClashesWith(other *Section) bool {
+	if s.SemesterID != other.SemesterID || s.SectionID == other.SectionID {
+		return false
+	}
+	if s.StartTime == "" || s.EndTime == "" || other.StartTime == "" || other.EndTime == "" {
+		return false
+	}
+	if !strings.ContainsAny(s.MeetingDays, other.MeetingDays) {
+		return false
+	}
+	// HH:MM times order correctly as strings
+	return s.StartTime < other.EndTime && other.StartTime < s.EndTime
+}
+
+// Attribute returns the value of an attribute, preferring the section's own value over the
+// one inherited from its course
+func (s *Section) Attribute(key string) (string, bool) {
+	if value, ok := s.Attributes[key]; ok {
+		return value, true
+	}
+	value, ok := s.Course.Attributes[key]
+	return value, ok
+}
+
 type RegistrationStatus string
 
 const (
@@ -103,19 +395,30 @@ const (
 	StatusWaitlisted RegistrationStatus = "waitlisted"
 	StatusDropped    RegistrationStatus = "dropped"
 	StatusFailed     RegistrationStatus = "failed"
+	// StatusWithdrawn is a course left after the drop deadline. The student keeps the seat.
+	StatusWithdrawn RegistrationStatus = "withdrawn"
 )
 
+// HoldsSeat is whether a registration in this status counts against the section's seats
+func (s RegistrationStatus) HoldsSeat() bool {
+	return s == StatusEnrolled || s == StatusWithdrawn
+}
+
 type WaitlistEntry struct {
 	WaitlistID uuid.UUID  `json:"waitlist_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	StudentID  uuid.UUID  `json:"student_id" gorm:"type:uuid;not null;constraint:OnDelete:CASCADE"`
 	SectionID  uuid.UUID  `json:"section_id" gorm:"type:uuid;not null;constraint:OnDelete:CASCADE"`
+	SemesterID uuid.UUID  `json:"semester_id" gorm:"type:uuid;not null"`
 	Position   int        `json:"position" gorm:"not null"`
 	Timestamp  time.Time  `json:"timestamp" gorm:"type:timestamptz;default:now()"`
 	ExpiresAt  *time.Time `json:"expires_at,omitempty" gorm:"type:timestamptz"`
-	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
-	Student    Student    `json:"student,omitempty" gorm:"foreignKey:StudentID;references:StudentID"`
-	Section    Section    `json:"section,omitempty" gorm:"foreignKey:SectionID;references:SectionID"`
+	// LinkedSectionID is the other section of a linked pair the student registered for
+	// together. The student is promoted only once a seat can be taken in both.
+	LinkedSectionID *uuid.UUID `json:"linked_section_id,omitempty" gorm:"type:uuid"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	Student         Student    `json:"student,omitempty" gorm:"foreignKey:StudentID;references:StudentID"`
+	Section         Section    `json:"section,omitempty" gorm:"foreignKey:SectionID;references:SectionID"`
 }
 
 func (WaitlistEntry) TableName() string {
@@ -161,17 +464,493 @@ func GetCheckConstraints() []string {
 	}
 }
 
+// IdempotencyStatus tells a request still being processed apart from a finished one whose
+// response can be replayed. Keys stored without a status are finished.
+type IdempotencyStatus string
+
+const (
+	IdempotencyProcessing IdempotencyStatus = "processing"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
 type IdempotencyKey struct {
-	Key          string    `json:"key"`
-	StudentID    uuid.UUID `json:"student_id"`
-	RequestHash  string    `json:"request_hash"`
-	ResponseData string    `json:"response_data"`
-	StatusCode   int       `json:"status_code"`
-	ProcessedAt  time.Time `json:"processed_at"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	Key          string            `json:"key" gorm:"primaryKey"`
+	StudentID    uuid.UUID         `json:"student_id"`
+	RequestHash  string            `json:"request_hash"`
+	Status       IdempotencyStatus `json:"status,omitempty"`
+	ResponseData string            `json:"response_data"`
+	StatusCode   int               `json:"status_code"`
+	ProcessedAt  time.Time         `json:"processed_at"`
+	ExpiresAt    time.Time         `json:"expires_at"`
+	CreatedAt    time.Time         `json:"created_at"`
 }
 
 func (i *IdempotencyKey) IsExpired() bool {
 	return time.Now().After(i.ExpiresAt)
 }
+
+// IsProcessing reports whether the request that took the key has not finished yet
+func (i *IdempotencyKey) IsProcessing() bool {
+	return i.Status == IdempotencyProcessing
+}
+
+type SeatOfferStatus string
+
+const (
+	OfferStatusPending  SeatOfferStatus = "pending"
+	OfferStatusAccepted SeatOfferStatus = "accepted"
+	OfferStatusDeclined SeatOfferStatus = "declined"
+	OfferStatusExpired  SeatOfferStatus = "expired"
+)
+
+// SeatOffer is a time-limited hold on a freed seat for the student at the head of a waitlist.
+// The seat stays decremented in the cache until the offer is accepted, declined or expires.
+type SeatOffer struct {
+	OfferID    uuid.UUID `json:"offer_id"`
+	StudentID  uuid.UUID `json:"student_id"`
+	SectionID  uuid.UUID `json:"section_id"`
+	WaitlistID uuid.UUID `json:"waitlist_id"`
+	// SeatPoolID is the seat pool the held seat was taken from, nil for a seat no pool holds
+	SeatPoolID *uuid.UUID      `json:"seat_pool_id,omitempty"`
+	Status     SeatOfferStatus `json:"status"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+func (o *SeatOffer) IsExpired() bool {
+	return time.Now().After(o.ExpiresAt)
+}
+
+type SeatHoldStatus string
+
+const (
+	HoldStatusHeld      SeatHoldStatus = "held"
+	HoldStatusConfirmed SeatHoldStatus = "confirmed"
+	HoldStatusExpired   SeatHoldStatus = "expired"
+)
+
+// SeatHold reserves a seat for a student while they finish registering. The seat is taken
+// from the counter when the hold is placed and returned if it is not confirmed in time.
+type SeatHold struct {
+	HoldID    uuid.UUID      `json:"hold_id"`
+	StudentID uuid.UUID      `json:"student_id"`
+	SectionID uuid.UUID      `json:"section_id"`
+	Status    SeatHoldStatus `json:"status"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (h *SeatHold) IsExpired() bool {
+	return time.Now().After(h.ExpiresAt)
+}
+
+type RegistrationEventType string
+
+const (
+	EventRegistered   RegistrationEventType = "registered"
+	EventWaitlisted   RegistrationEventType = "waitlisted"
+	EventPromoted     RegistrationEventType = "promoted"
+	EventDropped      RegistrationEventType = "dropped"
+	EventWithdrawn    RegistrationEventType = "withdrawn"
+	EventLeftWaitlist RegistrationEventType = "waitlist_left"
+)
+
+// Transition is the change of registration status an event stands for. An empty status is
+// no registration or waitlist entry at all.
+func (t RegistrationEventType) Transition() (from, to RegistrationStatus) {
+	switch t {
+	case EventRegistered:
+		return "", StatusEnrolled
+	case EventPromoted:
+		return StatusWaitlisted, StatusEnrolled
+	case EventWaitlisted:
+		return "", StatusWaitlisted
+	case EventDropped:
+		return StatusEnrolled, StatusDropped
+	case EventWithdrawn:
+		return StatusEnrolled, StatusWithdrawn
+	case EventLeftWaitlist:
+		return StatusWaitlisted, ""
+	}
+	return "", ""
+}
+
+// RegistrationTransition is one change of a student's status in a section, kept whatever the
+// persistence mode so students and staff can see how a registration got where it is
+type RegistrationTransition struct {
+	TransitionID uuid.UUID             `json:"transition_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	StudentID    uuid.UUID             `json:"student_id" gorm:"type:uuid;not null"`
+	SectionID    uuid.UUID             `json:"section_id" gorm:"type:uuid;not null"`
+	EventType    RegistrationEventType `json:"event_type" gorm:"type:varchar(20);not null"`
+	FromStatus   RegistrationStatus    `json:"from_status,omitempty" gorm:"type:varchar(20)"`
+	ToStatus     RegistrationStatus    `json:"to_status,omitempty" gorm:"type:varchar(20)"`
+	Position     *int                  `json:"waitlist_position,omitempty"`
+	OccurredAt   time.Time             `json:"occurred_at" gorm:"type:timestamptz;not null"`
+	CreatedAt    time.Time             `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (RegistrationTransition) TableName() string {
+	return "registration_transitions"
+}
+
+// RegistrationEvent is an entry of the append-only registration event store. Sequence is
+// assigned by the database and gives the global order in which events are replayed.
+type RegistrationEvent struct {
+	Sequence   int64                 `json:"sequence" gorm:"primaryKey;autoIncrement"`
+	EventID    uuid.UUID             `json:"event_id" gorm:"type:uuid;unique;not null;default:uuid_generate_v4()"`
+	EventType  RegistrationEventType `json:"event_type" gorm:"type:varchar(20);not null"`
+	StudentID  uuid.UUID             `json:"student_id" gorm:"type:uuid;not null"`
+	SectionID  uuid.UUID             `json:"section_id" gorm:"type:uuid;not null"`
+	Position   *int                  `json:"position,omitempty"`
+	OccurredAt time.Time             `json:"occurred_at" gorm:"type:timestamptz;not null"`
+	CreatedAt  time.Time             `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (RegistrationEvent) TableName() string {
+	return "registration_events"
+}
+
+// RegistrationSnapshot stores a serialized SectionRoster so replays can start from it
+// instead of the first event of a section.
+type RegistrationSnapshot struct {
+	SnapshotID uuid.UUID `json:"snapshot_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	SectionID  uuid.UUID `json:"section_id" gorm:"type:uuid;not null"`
+	Sequence   int64     `json:"sequence" gorm:"not null"`
+	AsOf       time.Time `json:"as_of" gorm:"type:timestamptz;not null"`
+	State      string    `json:"state" gorm:"type:jsonb;not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (RegistrationSnapshot) TableName() string {
+	return "registration_snapshots"
+}
+
+type RosterEntry struct {
+	StudentID uuid.UUID          `json:"student_id"`
+	Status    RegistrationStatus `json:"status"`
+	Position  *int               `json:"waitlist_position,omitempty"`
+	Since     time.Time          `json:"since"`
+}
+
+// SectionRoster is the registration state of a section derived from its events
+type SectionRoster struct {
+	SectionID uuid.UUID                  `json:"section_id"`
+	Sequence  int64                      `json:"sequence"`
+	AsOf      time.Time                  `json:"as_of"`
+	Entries   map[uuid.UUID]*RosterEntry `json:"entries"`
+}
+
+func NewSectionRoster(sectionID uuid.UUID) *SectionRoster {
+	return &SectionRoster{
+		SectionID: sectionID,
+		Entries:   make(map[uuid.UUID]*RosterEntry),
+	}
+}
+
+// Apply folds one event into the roster. Events must be applied in sequence order.
+func (r *SectionRoster) Apply(event *RegistrationEvent) {
+	entry, exists := r.Entries[event.StudentID]
+	if !exists {
+		entry = &RosterEntry{StudentID: event.StudentID}
+		r.Entries[event.StudentID] = entry
+	}
+
+	switch event.EventType {
+	case EventRegistered, EventPromoted:
+		entry.Status = StatusEnrolled
+		entry.Position = nil
+		entry.Since = event.OccurredAt
+	case EventWaitlisted:
+		// A late waitlist persist must not demote a student who is already enrolled
+		if entry.Status != StatusEnrolled {
+			entry.Status = StatusWaitlisted
+			entry.Position = event.Position
+			entry.Since = event.OccurredAt
+		}
+	case EventDropped:
+		entry.Status = StatusDropped
+		entry.Position = nil
+		entry.Since = event.OccurredAt
+	case EventWithdrawn:
+		entry.Status = StatusWithdrawn
+		entry.Position = nil
+		entry.Since = event.OccurredAt
+	case EventLeftWaitlist:
+		if !exists || entry.Status == StatusWaitlisted {
+			r.removeFromWaitlist(entry)
+		}
+	}
+
+	r.Sequence = event.Sequence
+	if event.OccurredAt.After(r.AsOf) {
+		r.AsOf = event.OccurredAt
+	}
+}
+
+// removeFromWaitlist takes a student off the roster and moves everyone behind them up one place
+func (r *SectionRoster) removeFromWaitlist(left *RosterEntry) {
+	delete(r.Entries, left.StudentID)
+	if left.Position == nil {
+		return
+	}
+
+	for _, entry := range r.Entries {
+		if entry.Status == StatusWaitlisted && entry.Position != nil && *entry.Position > *left.Position {
+			position := *entry.Position - 1
+			entry.Position = &position
+		}
+	}
+}
+
+// WithStatus returns the roster entries in the given status, ordered by waitlist position
+// and then by the time they entered it.
+func (r *SectionRoster) WithStatus(status RegistrationStatus) []*RosterEntry {
+	entries := make([]*RosterEntry, 0)
+	for _, entry := range r.Entries {
+		if entry.Status == status {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Position != nil && entries[j].Position != nil && *entries[i].Position != *entries[j].Position {
+			return *entries[i].Position < *entries[j].Position
+		}
+		return entries[i].Since.Before(entries[j].Since)
+	})
+
+	return entries
+}
+
+// APIKey authenticates a machine client such as an SIS sync job. Only the SHA-256 hash of the
+// secret is stored; Prefix is the public part of the key used to look it up.
+type APIKey struct {
+	KeyID              uuid.UUID  `json:"key_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name               string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix             string     `json:"prefix" gorm:"type:varchar(16);uniqueIndex;not null"`
+	KeyHash            string     `json:"-" gorm:"type:char(64);not null"`
+	Scopes             []string   `json:"scopes" gorm:"type:jsonb;serializer:json;not null"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute" gorm:"not null;default:0"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty" gorm:"type:timestamptz"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty" gorm:"type:timestamptz"`
+	LastUsedAt         *time.Time `json:"last_used_at,omitempty" gorm:"type:timestamptz"`
+	RotatedFromID      *uuid.UUID `json:"rotated_from_id,omitempty" gorm:"type:uuid"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key may still authenticate at the given time
+func (k *APIKey) IsActive(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || at.Before(*k.ExpiresAt)
+}
+
+// Destructive admin operations that only run once a second admin approves them
+const (
+	OperationDeactivateSection  = "section.deactivate"
+	OperationDropAllFromSection = "section.drop_all"
+	OperationDeactivateSemester = "semester.deactivate"
+)
+
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalExecuted ApprovalStatus = "executed"
+	ApprovalFailed   ApprovalStatus = "failed"
+	ApprovalRejected ApprovalStatus = "rejected"
+	ApprovalExpired  ApprovalStatus = "expired"
+)
+
+// AdminApproval is a destructive operation proposed by one admin and confirmed or rejected
+// by another. The row doubles as the audit record of who asked for the operation, who
+// allowed it and how it went.
+type AdminApproval struct {
+	ApprovalID uuid.UUID      `json:"approval_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Operation  string         `json:"operation" gorm:"type:varchar(50);not null"`
+	TargetID   uuid.UUID      `json:"target_id" gorm:"type:uuid;not null"`
+	Reason     string         `json:"reason" gorm:"type:text;not null"`
+	Status     ApprovalStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	ProposedBy string         `json:"proposed_by" gorm:"type:varchar(255);not null"`
+	ProposedAt time.Time      `json:"proposed_at" gorm:"type:timestamptz;not null"`
+	ExpiresAt  time.Time      `json:"expires_at" gorm:"type:timestamptz;not null"`
+	DecidedBy  *string        `json:"decided_by,omitempty" gorm:"type:varchar(255)"`
+	DecidedAt  *time.Time     `json:"decided_at,omitempty" gorm:"type:timestamptz"`
+	// Result is what the operation did, or why it failed
+	Result    string    `json:"result,omitempty" gorm:"type:text"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (AdminApproval) TableName() string {
+	return "admin_approvals"
+}
+
+// Kinds of student hold
+const (
+	HoldTypeFinancial = "financial"
+	HoldTypeAdvising  = "advising"
+)
+
+// Systems that place holds
+const (
+	HoldSourceBursar = "bursar"
+	// HoldSourceStaff is a hold placed by hand through the admin API
+	HoldSourceStaff = "staff"
+)
+
+// StudentHold keeps a student from taking new seats until it is released. Holds placed by
+// another system carry that system's reference, so its release finds the same hold.
+type StudentHold struct {
+	HoldID      uuid.UUID  `json:"hold_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	StudentID   uuid.UUID  `json:"student_id" gorm:"type:uuid;not null"`
+	HoldType    string     `json:"hold_type" gorm:"type:varchar(30);not null"`
+	Source      string     `json:"source" gorm:"type:varchar(30);not null"`
+	ExternalRef string     `json:"external_ref,omitempty" gorm:"type:varchar(255)"`
+	Reason      string     `json:"reason,omitempty" gorm:"type:text"`
+	PlacedAt    time.Time  `json:"placed_at" gorm:"type:timestamptz;not null"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty" gorm:"type:timestamptz"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (StudentHold) TableName() string {
+	return "student_holds"
+}
+
+func (h *StudentHold) IsActive() bool {
+	return h.ReleasedAt == nil
+}
+
+type ScheduleOverrideStatus string
+
+const (
+	ScheduleOverridePending  ScheduleOverrideStatus = "pending"
+	ScheduleOverrideApproved ScheduleOverrideStatus = "approved"
+	ScheduleOverrideDenied   ScheduleOverrideStatus = "denied"
+)
+
+// ScheduleOverride asks an advisor to let a student take a section that meets at the same
+// time as sections they are enrolled in. Approving it registers the student, seat
+// permitting, and records the outcome; the approval also lets later registrations for the
+// section past the time-conflict check.
+type ScheduleOverride struct {
+	OverrideID          uuid.UUID              `json:"override_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	StudentID           uuid.UUID              `json:"student_id" gorm:"type:uuid;not null"`
+	SectionID           uuid.UUID              `json:"section_id" gorm:"type:uuid;not null"`
+	ConflictingSections []uuid.UUID            `json:"conflicting_sections" gorm:"type:jsonb;serializer:json;not null"`
+	Reason              string                 `json:"reason" gorm:"type:text;not null"`
+	Status              ScheduleOverrideStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	DecidedBy           string                 `json:"decided_by,omitempty" gorm:"type:varchar(255)"`
+	DecisionNote        string                 `json:"decision_note,omitempty" gorm:"type:text"`
+	DecidedAt           *time.Time             `json:"decided_at,omitempty" gorm:"type:timestamptz"`
+	RegistrationStatus  string                 `json:"registration_status,omitempty" gorm:"type:varchar(30)"`
+	RegistrationMessage string                 `json:"registration_message,omitempty" gorm:"type:text"`
+	CreatedAt           time.Time              `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time              `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (ScheduleOverride) TableName() string {
+	return "schedule_overrides"
+}
+
+// PermissionNumber lets one student register for a section past its instructor-consent
+// requirement and into its reserved and pooled seats. Instructors hand the code out;
+// it works once, and only for StudentID when that is set.
+type PermissionNumber struct {
+	PermissionID uuid.UUID  `json:"permission_id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	SectionID    uuid.UUID  `json:"section_id" gorm:"type:uuid;not null"`
+	Code         string     `json:"code" gorm:"type:varchar(20);unique;not null"`
+	StudentID    *uuid.UUID `json:"student_id,omitempty" gorm:"type:uuid"`
+	IssuedBy     string     `json:"issued_by,omitempty" gorm:"type:varchar(255)"`
+	Note         string     `json:"note,omitempty" gorm:"type:text"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" gorm:"type:timestamptz"`
+	UsedBy       *uuid.UUID `json:"used_by,omitempty" gorm:"type:uuid"`
+	UsedAt       *time.Time `json:"used_at,omitempty" gorm:"type:timestamptz"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (PermissionNumber) TableName() string {
+	return "permission_numbers"
+}
+
+// Usable reports whether the number can still be used by the student at now
+func (p *PermissionNumber) Usable(studentID uuid.UUID, now time.Time) bool {
+	return p.UsedAt == nil &&
+		(p.ExpiresAt == nil || now.Before(*p.ExpiresAt)) &&
+		(p.StudentID == nil || *p.StudentID == studentID)
+}
+
+type ForecastOutlook string
+
+const (
+	OutlookOverSubscribed  ForecastOutlook = "over_subscribed"
+	OutlookUnderSubscribed ForecastOutlook = "under_subscribed"
+	OutlookOnTrack         ForecastOutlook = "on_track"
+)
+
+// EnrollmentForecast is the latest prediction of a section's final enrollment.
+// HistorySemesters is how many earlier semesters the registration curve was built from;
+// without any, the prediction is simply the current enrollment.
+type EnrollmentForecast struct {
+	SectionID           uuid.UUID       `json:"section_id" gorm:"type:uuid;primary_key"`
+	SemesterID          uuid.UUID       `json:"semester_id" gorm:"type:uuid;not null"`
+	CourseCode          string          `json:"course_code" gorm:"type:text;not null"`
+	SectionNumber       string          `json:"section_number" gorm:"type:varchar(10);not null"`
+	Department          string          `json:"department,omitempty" gorm:"type:varchar(20)"`
+	Capacity            int             `json:"capacity" gorm:"not null"`
+	Enrolled            int             `json:"enrolled" gorm:"not null"`
+	PredictedEnrollment int             `json:"predicted_enrollment" gorm:"not null"`
+	Outlook             ForecastOutlook `json:"outlook" gorm:"type:varchar(20);not null"`
+	HistorySemesters    int             `json:"history_semesters" gorm:"not null"`
+	GeneratedAt         time.Time       `json:"generated_at" gorm:"type:timestamptz;not null"`
+}
+
+func (EnrollmentForecast) TableName() string {
+	return "enrollment_forecasts"
+}
+
+type AuditAction string
+
+const (
+	AuditRegistered       AuditAction = "registration.enrolled"
+	AuditDropped          AuditAction = "registration.dropped"
+	AuditWithdrawn        AuditAction = "registration.withdrawn"
+	AuditWaitlistAdded    AuditAction = "waitlist.added"
+	AuditWaitlistRemoved  AuditAction = "waitlist.removed"
+	AuditWaitlistExpired  AuditAction = "waitlist.expired"
+	AuditCapacityChanged  AuditAction = "section.capacity_changed"
+	AuditPolicyChanged    AuditAction = "section.policy_changed"
+	AuditSeatPoolsChanged AuditAction = "section.seat_pools_changed"
+	AuditPermissionIssued AuditAction = "permission.issued"
+	AuditPermissionUsed   AuditAction = "permission.used"
+)
+
+// AuditEvent records who changed a registration or section, and how. Before and After are
+// snapshots of the fields the change touched. CorrelationID ties the event to the request
+// or job that made it.
+type AuditEvent struct {
+	EventID       uuid.UUID      `json:"event_id" gorm:"type:uuid;primary_key"`
+	Action        AuditAction    `json:"action" gorm:"type:varchar(50);not null"`
+	ActorID       string         `json:"actor_id" gorm:"type:varchar(255);not null"`
+	ActorRole     string         `json:"actor_role,omitempty" gorm:"type:varchar(20)"`
+	StudentID     *uuid.UUID     `json:"student_id,omitempty" gorm:"type:uuid"`
+	SectionID     *uuid.UUID     `json:"section_id,omitempty" gorm:"type:uuid"`
+	Before        map[string]any `json:"before,omitempty" gorm:"type:jsonb;serializer:json"`
+	After         map[string]any `json:"after,omitempty" gorm:"type:jsonb;serializer:json"`
+	CorrelationID string         `json:"correlation_id,omitempty" gorm:"type:varchar(64)"`
+	OccurredAt    time.Time      `json:"occurred_at" gorm:"type:timestamptz;not null"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
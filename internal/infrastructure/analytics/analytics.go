@@ -0,0 +1,34 @@
+package analytics
+
+import (
+	"fmt"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	SinkNone  = "none"
+	SinkRedis = "redis"
+	SinkKafka = "kafka"
+	SinkFile  = "file"
+)
+
+// New creates the sink selected by analytics.sink, or nil when analytics are off. The
+// redis sink writes through client and the kafka sink to the queue's brokers.
+func New(cfg *config.AnalyticsConfig, client redis.UniversalClient, kafkaCfg *config.KafkaConfig) (interfaces.AnalyticsSink, error) {
+	switch cfg.Sink {
+	case SinkNone, "":
+		return nil, nil
+	case SinkRedis:
+		return NewRedisStreamSink(client, cfg.Stream, cfg.StreamMaxLen), nil
+	case SinkKafka:
+		return NewKafkaSink(kafkaCfg.Brokers, cfg.Topic), nil
+	case SinkFile:
+		return NewFileSink(cfg.FilePath)
+	default:
+		return nil, fmt.Errorf("unknown analytics sink: %s", cfg.Sink)
+	}
+}
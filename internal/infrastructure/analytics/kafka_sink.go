@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchTimeout bounds how long an event waits for others to share its write
+const kafkaBatchTimeout = 50 * time.Millisecond
+
+// KafkaSink publishes events to a topic, keyed by section so each section's events stay
+// in order on one partition
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireOne,
+			BatchTimeout:           kafkaBatchTimeout,
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+func (s *KafkaSink) Emit(ctx context.Context, event interfaces.AnalyticsEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics event: %w", err)
+	}
+
+	err = s.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.SectionID.String()),
+		Value: payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish analytics event to %s: %w", s.writer.Topic, err)
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// analyticsEventField is the stream entry field holding the JSON event
+const analyticsEventField = "event"
+
+// RedisStreamSink appends events to a Redis stream, trimmed to about maxLen entries so
+// a dashboard that stops reading cannot fill Redis
+type RedisStreamSink struct {
+	client redis.UniversalClient
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamSink(client redis.UniversalClient, stream string, maxLen int64) *RedisStreamSink {
+	return &RedisStreamSink{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (s *RedisStreamSink) Emit(ctx context.Context, event interfaces.AnalyticsEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analytics event: %w", err)
+	}
+
+	err = s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: s.maxLen > 0,
+		Values: map[string]interface{}{
+			"type":              string(event.Type),
+			analyticsEventField: payload,
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to add analytics event to stream %s: %w", s.stream, err)
+	}
+	return nil
+}
+
+// Close leaves the client open, since it is shared with the cache
+func (s *RedisStreamSink) Close() error {
+	return nil
+}
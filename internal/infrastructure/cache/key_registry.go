@@ -0,0 +1,96 @@
+package cache
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// keyRegistryPrefix prefixes the sets listing the keys written for a tracked family
+	keyRegistryPrefix = "cache:registry:"
+	// clearBatchSize is how many keys one SCAN or SSCAN step asks for and one UNLINK drops
+	clearBatchSize = 500
+)
+
+func registryKey(family interfaces.CacheKeyFamily) string {
+	return keyRegistryPrefix + family.Prefix
+}
+
+// setView writes a cached view and, for a tracked family, records its key in the family
+// registry in the same transaction, so no view is written without being listed
+func (r *RedisCache) setView(ctx context.Context, family interfaces.CacheKeyFamily, key string, data []byte, ttl time.Duration) error {
+	if !family.Tracked {
+		return r.client.Set(ctx, key, data, ttl).Err()
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, key, data, ttl)
+	pipe.SAdd(ctx, registryKey(family), key)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Clear drops the keys matching pattern without KEYS, which blocks Redis for as long as it
+// takes to walk the whole keyspace. A tracked family is dropped from its registry; any other
+// pattern is walked with SCAN, a batch at a time. Keys written while Clear runs may survive.
+func (r *RedisCache) Clear(ctx context.Context, pattern string) error {
+	if family, ok := interfaces.TrackedCacheView(pattern); ok {
+		return r.clearRegistry(ctx, family)
+	}
+	return r.clearScan(ctx, pattern)
+}
+
+// clearRegistry drops every key listed in the family registry along with its entry. Entries
+// of keys that already expired are dropped the same way.
+func (r *RedisCache) clearRegistry(ctx context.Context, family interfaces.CacheKeyFamily) error {
+	registry := registryKey(family)
+	var cursor uint64
+	for {
+		keys, next, err := r.client.SScan(ctx, registry, cursor, "", clearBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read key registry %s: %w", registry, err)
+		}
+
+		if len(keys) > 0 {
+			members := make([]interface{}, len(keys))
+			for i, key := range keys {
+				members[i] = key
+			}
+
+			pipe := r.client.Pipeline()
+			pipe.Unlink(ctx, keys...)
+			pipe.SRem(ctx, registry, members...)
+			if _, err := pipe.Exec(ctx); err != nil {
+				return fmt.Errorf("failed to delete keys: %w", err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (r *RedisCache) clearScan(ctx context.Context, pattern string) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, clearBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan keys for pattern %s: %w", pattern, err)
+		}
+
+		if len(keys) > 0 {
+			if err := r.client.Unlink(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("failed to delete keys: %w", err)
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// kpiMinuteRetention bounds how far back the per-minute counters can be read
+const kpiMinuteRetention = 3 * time.Hour
+
+type RedisKPICounters struct {
+	client redis.UniversalClient
+}
+
+func NewRedisKPICounters(client redis.UniversalClient) interfaces.KPICounterStore {
+	return &RedisKPICounters{
+		client: client,
+	}
+}
+
+func (k *RedisKPICounters) Increment(ctx context.Context, metric interfaces.KPIMetric, sectionID uuid.UUID, at time.Time) error {
+	minuteKey := kpiMinuteKey(metric, at)
+
+	pipe := k.client.Pipeline()
+	pipe.Incr(ctx, minuteKey)
+	pipe.Expire(ctx, minuteKey, kpiMinuteRetention)
+	pipe.HIncrBy(ctx, kpiSectionTotalsKey(metric), sectionID.String(), 1)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to increment %s counter: %w", metric, err)
+	}
+	return nil
+}
+
+func (k *RedisKPICounters) PerMinute(ctx context.Context, metric interfaces.KPIMetric, until time.Time, minutes int) ([]int64, error) {
+	if minutes <= 0 {
+		return []int64{}, nil
+	}
+
+	keys := make([]string, minutes)
+	for i := range keys {
+		keys[i] = kpiMinuteKey(metric, until.Add(-time.Duration(minutes-1-i)*time.Minute))
+	}
+
+	values, err := k.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s counters: %w", metric, err)
+	}
+
+	counts := make([]int64, minutes)
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+	return counts, nil
+}
+
+func (k *RedisKPICounters) SectionTotals(ctx context.Context, metric interfaces.KPIMetric) (map[uuid.UUID]int64, error) {
+	fields, err := k.client.HGetAll(ctx, kpiSectionTotalsKey(metric)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s totals: %w", metric, err)
+	}
+
+	totals := make(map[uuid.UUID]int64, len(fields))
+	for field, value := range fields {
+		sectionID, err := uuid.Parse(field)
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		totals[sectionID] = count
+	}
+	return totals, nil
+}
+
+func kpiMinuteKey(metric interfaces.KPIMetric, at time.Time) string {
+	return fmt.Sprintf("kpi:%s:minute:%d", metric, at.Unix()/60)
+}
+
+func kpiSectionTotalsKey(metric interfaces.KPIMetric) string {
+	return fmt.Sprintf("kpi:%s:sections", metric)
+}
@@ -0,0 +1,797 @@
+package cache
+
+import (
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// memorySweepInterval is how often expired keys are removed from the in-memory cache
+	memorySweepInterval = time.Minute
+	// memoryWaitlistEntryTTL matches the lifetime of waitlist entry documents in Redis
+	memoryWaitlistEntryTTL = 24 * time.Hour
+)
+
+type memoryItem struct {
+	value     string
+	metadata  map[string]string
+	expiresAt time.Time
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// MemoryCache is a CacheService kept in process memory, for running the server without
+// Redis during development. It uses the same keys and the same seat and waitlist semantics
+// as RedisCache, but nothing is shared between processes or kept across restarts.
+type MemoryCache struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	// waitlists holds each section's queue as student to position, the sorted set score in Redis
+	waitlists map[uuid.UUID]map[uuid.UUID]int
+	// studentWaitlists indexes the sections each student waits for
+	studentWaitlists map[uuid.UUID]map[uuid.UUID]struct{}
+	subscribers      map[uuid.UUID]map[chan interfaces.SeatChange]struct{}
+	outbox           *MemorySyncOutbox
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewMemoryCache() *MemoryCache {
+	c := &MemoryCache{
+		items:            make(map[string]memoryItem),
+		waitlists:        make(map[uuid.UUID]map[uuid.UUID]int),
+		studentWaitlists: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		subscribers:      make(map[uuid.UUID]map[chan interfaces.SeatChange]struct{}),
+		outbox:           newMemorySyncOutbox(),
+		stop:             make(chan struct{}),
+	}
+	go c.sweep()
+	return c
+}
+
+// SyncOutbox returns the outbox ReserveSeat writes to
+func (c *MemoryCache) SyncOutbox() *MemorySyncOutbox {
+	return c.outbox
+}
+
+// sweep removes expired keys so entries nobody reads again do not pile up
+func (c *MemoryCache) sweep() {
+	ticker := time.NewTicker(memorySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			for key, item := range c.items {
+				if item.expired(now) {
+					delete(c.items, key)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// get returns the live value under key. Callers must hold c.mu.
+func (c *MemoryCache) get(key string) (memoryItem, bool) {
+	item, ok := c.items[key]
+	if !ok {
+		return memoryItem{}, false
+	}
+	if item.expired(time.Now()) {
+		delete(c.items, key)
+		return memoryItem{}, false
+	}
+	return item, true
+}
+
+// set stores value under key. A ttl of zero or less never expires, as in Redis. Callers
+// must hold c.mu.
+func (c *MemoryCache) set(key, value string, ttl time.Duration) {
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	c.items[key] = item
+}
+
+// replace changes the value under key and keeps its expiry. Callers must hold c.mu.
+func (c *MemoryCache) replace(key, value string) {
+	item := c.items[key]
+	item.value = value
+	c.items[key] = item
+}
+
+func (c *MemoryCache) lookup(cache, key string) (string, bool) {
+	c.mu.Lock()
+	item, ok := c.get(key)
+	c.mu.Unlock()
+
+	result := metrics.CacheHit
+	if !ok {
+		result = metrics.CacheMiss
+	}
+	metrics.CacheLookups.WithLabelValues(cache, result).Inc()
+	return item.value, ok
+}
+
+func (c *MemoryCache) setJSON(key string, data interface{}, ttl time.Duration) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.set(key, string(jsonData), ttl)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *MemoryCache) GetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	counter, err := c.GetSeatCounter(ctx, sectionID)
+	if err != nil {
+		return -1, err
+	}
+	return counter.Available, nil
+}
+
+func (c *MemoryCache) GetAvailableSeatsBatch(ctx context.Context, sectionIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	seats := make(map[uuid.UUID]int, len(sectionIDs))
+	for _, sectionID := range sectionIDs {
+		if counter, err := c.GetSeatCounter(ctx, sectionID); err == nil {
+			seats[sectionID] = counter.Available
+		}
+	}
+	return seats, nil
+}
+
+func (c *MemoryCache) GetSeatCounter(ctx context.Context, sectionID uuid.UUID) (*interfaces.SeatCounter, error) {
+	val, ok := c.lookup("section_seats", interfaces.SectionSeatsKey.Key(sectionID))
+	if !ok {
+		return nil, fmt.Errorf("section seats not cached")
+	}
+
+	var counter interfaces.SeatCounter
+	if err := json.Unmarshal([]byte(val), &counter); err != nil {
+		return nil, fmt.Errorf("invalid seat counter in cache: %w", err)
+	}
+	return &counter, nil
+}
+
+func (c *MemoryCache) SetSeatCounter(ctx context.Context, sectionID uuid.UUID, counter interfaces.SeatCounter, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setSeatCounter(sectionID, counter, ttl)
+	return nil
+}
+
+func (c *MemoryCache) SetSeatCounters(ctx context.Context, counters map[uuid.UUID]interfaces.SeatCounter, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for sectionID, counter := range counters {
+		c.setSeatCounter(sectionID, counter, ttl)
+	}
+	return nil
+}
+
+// setSeatCounter replaces the counter, moving its version on from the one it replaces.
+// Callers must hold c.mu.
+func (c *MemoryCache) setSeatCounter(sectionID uuid.UUID, counter interfaces.SeatCounter, ttl time.Duration) {
+	counter.Version = 1
+	if current, ok := c.seatCounter(sectionID); ok {
+		counter.Version = current.Version + 1
+	}
+	c.set(interfaces.SectionSeatsKey.Key(sectionID), encodeSeatCounter(counter), ttl)
+	c.publishSeatChange(sectionID, counter.Available)
+}
+
+func (c *MemoryCache) SetSeatCapacity(ctx context.Context, sectionID uuid.UUID, total, reserved int) (*interfaces.SeatCounter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter, ok := c.seatCounter(sectionID)
+	if !ok {
+		return nil, fmt.Errorf("seat key not found for section %s", sectionID.String())
+	}
+	available := counter.Available + total - counter.Total
+	if available < 0 {
+		return &counter, interfaces.ErrCapacityBelowEnrolled
+	}
+
+	counter.Available = available
+	counter.Total = total
+	counter.Reserved = reserved
+	c.updateSeatCounter(sectionID, counter)
+	return &counter, nil
+}
+
+// seatCounter returns the live counter of the section. Callers must hold c.mu.
+func (c *MemoryCache) seatCounter(sectionID uuid.UUID) (interfaces.SeatCounter, bool) {
+	item, ok := c.get(interfaces.SectionSeatsKey.Key(sectionID))
+	if !ok {
+		return interfaces.SeatCounter{}, false
+	}
+	var counter interfaces.SeatCounter
+	if err := json.Unmarshal([]byte(item.value), &counter); err != nil {
+		return interfaces.SeatCounter{}, false
+	}
+	return counter, true
+}
+
+// updateSeatCounter writes back a changed counter with its version moved on, keeping its
+// expiry. Callers must hold c.mu.
+func (c *MemoryCache) updateSeatCounter(sectionID uuid.UUID, counter interfaces.SeatCounter) interfaces.SeatCounter {
+	counter.Version++
+	c.replace(interfaces.SectionSeatsKey.Key(sectionID), encodeSeatCounter(counter))
+	c.publishSeatChange(sectionID, counter.Available)
+	return counter
+}
+
+func encodeSeatCounter(counter interfaces.SeatCounter) string {
+	data, _ := json.Marshal(counter)
+	return string(data)
+}
+
+// decrementSeats takes one seat unless no more than reserved are left, like
+// reserveSeatScript. Callers must hold c.mu.
+func (c *MemoryCache) decrementSeats(sectionID uuid.UUID, reserved int) (interfaces.SeatCounter, error) {
+	counter, ok := c.seatCounter(sectionID)
+	if !ok {
+		return interfaces.SeatCounter{}, fmt.Errorf("seat key not found for section %s", sectionID.String())
+	}
+	if counter.Available <= reserved {
+		return interfaces.SeatCounter{}, fmt.Errorf("failed to decrement seats: No seats available")
+	}
+
+	counter.Available--
+	return c.updateSeatCounter(sectionID, counter), nil
+}
+
+// incrementSeats adds a seat. A missing counter starts from zero, as HINCRBY does. Callers
+// must hold c.mu.
+func (c *MemoryCache) incrementSeats(sectionID uuid.UUID) (int, error) {
+	counter, _ := c.seatCounter(sectionID)
+	counter.Available++
+	return c.updateSeatCounter(sectionID, counter).Available, nil
+}
+
+func (c *MemoryCache) DecrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.decrementSeats(sectionID, 0)
+	return err
+}
+
+func (c *MemoryCache) DecrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter, err := c.decrementSeats(sectionID, 0)
+	if err != nil {
+		return -1, err
+	}
+	return counter.Available, nil
+}
+
+func (c *MemoryCache) IncrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.incrementSeats(sectionID)
+	return err
+}
+
+func (c *MemoryCache) IncrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.incrementSeats(sectionID)
+}
+
+func (c *MemoryCache) CompareAndSetAvailableSeats(ctx context.Context, sectionID uuid.UUID, expected, seats int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter, ok := c.seatCounter(sectionID)
+	if !ok {
+		return false, fmt.Errorf("seat key not found for section %s", sectionID.String())
+	}
+	if counter.Available != expected {
+		return false, nil
+	}
+
+	counter.Available = seats
+	c.updateSeatCounter(sectionID, counter)
+	return true, nil
+}
+
+// publishSeatChange hands a new seat count to every subscriber that has room for it. Like
+// the Redis version it is best effort. Callers must hold c.mu.
+func (c *MemoryCache) publishSeatChange(sectionID uuid.UUID, seats int) {
+	change := interfaces.SeatChange{
+		SectionID:      sectionID,
+		AvailableSeats: seats,
+		ChangedAt:      time.Now(),
+	}
+	for subscriber := range c.subscribers[sectionID] {
+		select {
+		case subscriber <- change:
+		default:
+		}
+	}
+}
+
+func (c *MemoryCache) SubscribeSeatChanges(ctx context.Context, sectionID uuid.UUID) (<-chan interfaces.SeatChange, error) {
+	changes := make(chan interfaces.SeatChange, 16)
+
+	c.mu.Lock()
+	if c.subscribers[sectionID] == nil {
+		c.subscribers[sectionID] = make(map[chan interfaces.SeatChange]struct{})
+	}
+	c.subscribers[sectionID][changes] = struct{}{}
+	c.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subscribers[sectionID], changes)
+		if len(c.subscribers[sectionID]) == 0 {
+			delete(c.subscribers, sectionID)
+		}
+		close(changes)
+	}()
+
+	return changes, nil
+}
+
+func (c *MemoryCache) GetSectionDetails(ctx context.Context, sectionID uuid.UUID) (interface{}, error) {
+	val, ok := c.lookup("section_details", interfaces.SectionDetailsCache.Key(sectionID))
+	if !ok {
+		return nil, fmt.Errorf("section details not cached")
+	}
+	return json.RawMessage(val), nil
+}
+
+func (c *MemoryCache) SetSectionDetails(ctx context.Context, sectionID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.setJSON(interfaces.SectionDetailsCache.Key(sectionID), data, ttl); err != nil {
+		return fmt.Errorf("failed to marshal section details: %w", err)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetCourseDetails(ctx context.Context, courseID uuid.UUID) (interface{}, error) {
+	val, ok := c.lookup("course_details", interfaces.CourseDetailsCache.Key(courseID))
+	if !ok {
+		return nil, fmt.Errorf("course details not cached")
+	}
+	return json.RawMessage(val), nil
+}
+
+func (c *MemoryCache) SetCourseDetails(ctx context.Context, courseID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.setJSON(interfaces.CourseDetailsCache.Key(courseID), data, ttl); err != nil {
+		return fmt.Errorf("failed to marshal course details: %w", err)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetStudentDetails(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
+	val, ok := c.lookup("student_details", interfaces.StudentDetailsCache.Key(studentID))
+	if !ok {
+		return nil, fmt.Errorf("student details not cached")
+	}
+	return json.RawMessage(val), nil
+}
+
+func (c *MemoryCache) SetStudentDetails(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.setJSON(interfaces.StudentDetailsCache.Key(studentID), data, ttl); err != nil {
+		return fmt.Errorf("failed to marshal student details: %w", err)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetStudentRegistrations(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
+	val, ok := c.lookup("student_registrations", interfaces.StudentRegistrationsCache.Key(studentID))
+	if !ok {
+		return nil, fmt.Errorf("student registrations not cached")
+	}
+	return json.RawMessage(val), nil
+}
+
+func (c *MemoryCache) SetStudentRegistrations(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.setJSON(interfaces.StudentRegistrationsCache.Key(studentID), data, ttl); err != nil {
+		return fmt.Errorf("failed to marshal student registrations: %w", err)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetStudentWaitlistStatus(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
+	val, ok := c.lookup("student_waitlist", interfaces.StudentWaitlistCache.Key(studentID))
+	if !ok {
+		return nil, fmt.Errorf("student waitlist status not cached")
+	}
+	return json.RawMessage(val), nil
+}
+
+func (c *MemoryCache) SetStudentWaitlistStatus(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.setJSON(interfaces.StudentWaitlistCache.Key(studentID), data, ttl); err != nil {
+		return fmt.Errorf("failed to marshal student waitlist: %w", err)
+	}
+	return nil
+}
+
+func (c *MemoryCache) GetAvailableSections(ctx context.Context, semesterID uuid.UUID) (interface{}, error) {
+	val, ok := c.lookup("available_sections", interfaces.AvailableSectionsCache.Key(semesterID))
+	if !ok {
+		return nil, fmt.Errorf("available sections not cached")
+	}
+	return json.RawMessage(val), nil
+}
+
+func (c *MemoryCache) SetAvailableSections(ctx context.Context, semesterID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.setJSON(interfaces.AvailableSectionsCache.Key(semesterID), data, ttl); err != nil {
+		return fmt.Errorf("failed to marshal available sections: %w", err)
+	}
+	return nil
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	val, ok := c.lookup("generic", key)
+	if !ok {
+		return "", fmt.Errorf("key not found")
+	}
+	return val, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	c.set(key, value, ttl)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *MemoryCache) GetWithMetadata(ctx context.Context, key string) (string, map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.get(key + ":data")
+	if !ok {
+		return "", nil, fmt.Errorf("key not found")
+	}
+
+	metadata := make(map[string]string, len(item.metadata))
+	for k, v := range item.metadata {
+		metadata[k] = v
+	}
+	return item.value, metadata, nil
+}
+
+func (c *MemoryCache) SetWithMetadata(ctx context.Context, key string, value string, metadata map[string]string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key+":data", value, ttl)
+	item := c.items[key+":data"]
+	item.metadata = make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		item.metadata[k] = v
+	}
+	c.items[key+":data"] = item
+	return nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+	return nil
+}
+
+// Clear deletes the keys matching a Redis glob pattern. Waitlists are not plain keys and
+// are left alone.
+func (c *MemoryCache) Clear(ctx context.Context, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("failed to get keys for pattern %s: %w", pattern, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		if matched, _ := path.Match(pattern, key); matched {
+			delete(c.items, key)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) InvalidateStudentCache(ctx context.Context, studentID uuid.UUID, semesterIDs ...uuid.UUID) error {
+	return c.invalidate(ctx, interfaces.CacheInvalidationSet(interfaces.CacheScopeStudent, studentID, semesterIDs...))
+}
+
+func (c *MemoryCache) InvalidateSectionCache(ctx context.Context, sectionID uuid.UUID) error {
+	set := interfaces.CacheInvalidationSet(interfaces.CacheScopeSection, sectionID)
+	// Available section lists include this section
+	set.Patterns = append(set.Patterns, interfaces.AvailableSectionsCache.Pattern())
+	return c.invalidate(ctx, set)
+}
+
+func (c *MemoryCache) invalidate(ctx context.Context, set interfaces.InvalidationSet) error {
+	c.mu.Lock()
+	for _, key := range set.Keys {
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+
+	for _, pattern := range set.Patterns {
+		if err := c.Clear(ctx, pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func waitlistEntryKey(sectionID, studentID uuid.UUID) string {
+	return fmt.Sprintf("waitlist:entry:%s:%s", sectionID.String(), studentID.String())
+}
+
+func (c *MemoryCache) AddToWaitlist(ctx context.Context, sectionID, studentID uuid.UUID, position int, entry interface{}, maxSize int) error {
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal waitlist entry: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, waiting := c.waitlists[sectionID][studentID]; maxSize > 0 && !waiting && len(c.waitlists[sectionID]) >= maxSize {
+		return interfaces.ErrWaitlistFull
+	}
+	if c.waitlists[sectionID] == nil {
+		c.waitlists[sectionID] = make(map[uuid.UUID]int)
+	}
+	c.waitlists[sectionID][studentID] = position
+	c.set(waitlistEntryKey(sectionID, studentID), string(entryData), memoryWaitlistEntryTTL)
+	if c.studentWaitlists[studentID] == nil {
+		c.studentWaitlists[studentID] = make(map[uuid.UUID]struct{})
+	}
+	c.studentWaitlists[studentID][sectionID] = struct{}{}
+	return nil
+}
+
+func (c *MemoryCache) RemoveFromWaitlist(ctx context.Context, sectionID, studentID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeFromWaitlist(sectionID, studentID)
+	return nil
+}
+
+// removeFromWaitlist drops the queue member, entry document and student index together.
+// Callers must hold c.mu.
+func (c *MemoryCache) removeFromWaitlist(sectionID, studentID uuid.UUID) {
+	delete(c.waitlists[sectionID], studentID)
+	if len(c.waitlists[sectionID]) == 0 {
+		delete(c.waitlists, sectionID)
+	}
+	delete(c.items, waitlistEntryKey(sectionID, studentID))
+	delete(c.studentWaitlists[studentID], sectionID)
+	if len(c.studentWaitlists[studentID]) == 0 {
+		delete(c.studentWaitlists, studentID)
+	}
+}
+
+type memoryWaitlistMember struct {
+	studentID uuid.UUID
+	position  int
+}
+
+// queue returns a section's waitlist in sorted set order: by position, then by member.
+// Callers must hold c.mu.
+func (c *MemoryCache) queue(sectionID uuid.UUID) []memoryWaitlistMember {
+	members := make([]memoryWaitlistMember, 0, len(c.waitlists[sectionID]))
+	for studentID, position := range c.waitlists[sectionID] {
+		members = append(members, memoryWaitlistMember{studentID: studentID, position: position})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].position != members[j].position {
+			return members[i].position < members[j].position
+		}
+		return members[i].studentID.String() < members[j].studentID.String()
+	})
+	return members
+}
+
+// moveWaitlistEntry records a new position for a student both in the queue and in their
+// entry document. Callers must hold c.mu.
+func (c *MemoryCache) moveWaitlistEntry(sectionID, studentID uuid.UUID, position int) {
+	c.waitlists[sectionID][studentID] = position
+
+	key := waitlistEntryKey(sectionID, studentID)
+	item, ok := c.get(key)
+	if !ok {
+		return
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(item.value), &entry); err != nil {
+		return
+	}
+	entry["position"] = position
+	if data, err := json.Marshal(entry); err == nil {
+		c.replace(key, string(data))
+	}
+}
+
+func (c *MemoryCache) LeaveWaitlist(ctx context.Context, sectionID, studentID uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	position, ok := c.waitlists[sectionID][studentID]
+	if !ok {
+		return -1, nil
+	}
+	c.removeFromWaitlist(sectionID, studentID)
+
+	for _, member := range c.queue(sectionID) {
+		if member.position > position {
+			c.moveWaitlistEntry(sectionID, member.studentID, member.position-1)
+		}
+	}
+	return position, nil
+}
+
+func (c *MemoryCache) CompactWaitlist(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	moved := make(map[uuid.UUID]int)
+	for i, member := range c.queue(sectionID) {
+		if position := i + 1; member.position != position {
+			c.moveWaitlistEntry(sectionID, member.studentID, position)
+			moved[member.studentID] = position
+		}
+	}
+	return moved, nil
+}
+
+func (c *MemoryCache) GetNextInWaitlist(ctx context.Context, sectionID uuid.UUID) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	members := c.queue(sectionID)
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	item, ok := c.get(waitlistEntryKey(sectionID, members[0].studentID))
+	if !ok {
+		// Entry expired, clean up the queue
+		delete(c.waitlists[sectionID], members[0].studentID)
+		return nil, nil
+	}
+
+	var entry interface{}
+	if err := json.Unmarshal([]byte(item.value), &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+func (c *MemoryCache) GetWaitlistPosition(ctx context.Context, sectionID, studentID uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for rank, member := range c.queue(sectionID) {
+		if member.studentID == studentID {
+			return rank + 1, nil
+		}
+	}
+	return -1, nil
+}
+
+func (c *MemoryCache) GetWaitlistSize(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waitlists[sectionID]), nil
+}
+
+func (c *MemoryCache) GetWaitlistSizes(ctx context.Context, sectionIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sizes := make(map[uuid.UUID]int, len(sectionIDs))
+	for _, sectionID := range sectionIDs {
+		sizes[sectionID] = len(c.waitlists[sectionID])
+	}
+	return sizes, nil
+}
+
+func (c *MemoryCache) GetStudentWaitlists(ctx context.Context, studentID uuid.UUID) ([]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	waitlists := make([]interface{}, 0, len(c.studentWaitlists[studentID]))
+	for sectionID := range c.studentWaitlists[studentID] {
+		item, ok := c.get(waitlistEntryKey(sectionID, studentID))
+		if !ok {
+			// Entry expired, clean up the index and any queue member left behind
+			c.removeFromWaitlist(sectionID, studentID)
+			continue
+		}
+
+		var entry interface{}
+		if err := json.Unmarshal([]byte(item.value), &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal waitlist entry: %w", err)
+		}
+		waitlists = append(waitlists, entry)
+	}
+	return waitlists, nil
+}
+
+func (c *MemoryCache) AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, held := c.get(key); held {
+		return false, nil
+	}
+	c.set(key, token, ttl)
+	return true, nil
+}
+
+func (c *MemoryCache) ReleaseLock(ctx context.Context, key, token string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, held := c.get(key)
+	if !held || item.value != token {
+		return false, nil
+	}
+	delete(c.items, key)
+	return true, nil
+}
+
+func (c *MemoryCache) GetCacheStats(ctx context.Context) (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return map[string]interface{}{
+		"backend":    "memory",
+		"total_keys": len(c.items),
+		"waitlists":  len(c.waitlists),
+		"hit_rate":   "calculated_by_middleware",
+	}, nil
+}
+
+func (c *MemoryCache) Health(ctx context.Context) error {
+	return nil
+}
+
+// Close stops the expiry sweep. The cache stays usable.
+func (c *MemoryCache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+	})
+	return nil
+}
+
+var _ interfaces.CacheService = (*MemoryCache)(nil)
@@ -0,0 +1,282 @@
+package cache
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (c *MemoryCache) ReserveSeat(ctx context.Context, sectionID uuid.UUID, seat interfaces.SeatRequest, jobs []interfaces.DatabaseSyncJob) (*interfaces.SeatCounter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pool, err := c.pickSeat(sectionID, seat)
+	if err != nil {
+		return nil, err
+	}
+	return c.takeSeat(sectionID, pool, jobs), nil
+}
+
+func (c *MemoryCache) ReserveSeats(ctx context.Context, reservations []interfaces.SeatReservation) ([]*interfaces.SeatCounter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pools := make([]*uuid.UUID, len(reservations))
+	for i, reservation := range reservations {
+		pool, err := c.pickSeat(reservation.SectionID, reservation.Seat)
+		if err != nil {
+			return nil, &interfaces.SeatReservationError{SectionID: reservation.SectionID, Err: err}
+		}
+		pools[i] = pool
+	}
+	counters := make([]*interfaces.SeatCounter, len(reservations))
+	for i, reservation := range reservations {
+		counters[i] = c.takeSeat(reservation.SectionID, pools[i], reservation.Jobs)
+	}
+	return counters, nil
+}
+
+// pickSeat checks that the request can have a seat in the section without taking it, and
+// returns the pool the seat comes from, nil for a seat no pool holds. Callers must hold c.mu.
+func (c *MemoryCache) pickSeat(sectionID uuid.UUID, seat interfaces.SeatRequest) (*uuid.UUID, error) {
+	counter, ok := c.seatCounter(sectionID)
+	if !ok {
+		return nil, fmt.Errorf("seat key not found for section %s", sectionID.String())
+	}
+	if seat.HoldForWaitlist && len(c.waitlists[sectionID]) > 0 {
+		return nil, interfaces.ErrSeatsHeldForWaitlist
+	}
+
+	reserved := seat.Reserved
+	var pool *uuid.UUID
+	if seat.HasPools {
+		pools, ok := c.seatPools(sectionID)
+		if !ok {
+			return nil, interfaces.ErrSeatPoolsNotLoaded
+		}
+		for _, eligible := range seat.Pools {
+			if pools[eligible] > 0 {
+				pool = &eligible
+				break
+			}
+		}
+		if pool != nil {
+			// A pooled seat only needs a seat on the counter, as in reserveSeatScript
+			reserved = 0
+		} else {
+			for _, left := range pools {
+				reserved += max(left, 0)
+			}
+		}
+	}
+	if counter.Available <= reserved {
+		return nil, interfaces.ErrNoSeatsLeft
+	}
+	return pool, nil
+}
+
+// takeSeat takes a seat pickSeat found, from pool when it is set, and appends the jobs to
+// the outbox stamped with the pool. Callers must hold c.mu.
+func (c *MemoryCache) takeSeat(sectionID uuid.UUID, pool *uuid.UUID, jobs []interfaces.DatabaseSyncJob) *interfaces.SeatCounter {
+	counter, _ := c.seatCounter(sectionID)
+	counter.Available--
+	counter = c.updateSeatCounter(sectionID, counter)
+	if pool != nil {
+		pools, _ := c.seatPools(sectionID)
+		pools[*pool]--
+		c.replace(interfaces.SectionSeatPoolsKey.Key(sectionID), encodeSeatPools(pools))
+		stamped := make([]interfaces.DatabaseSyncJob, len(jobs))
+		for i, job := range jobs {
+			job.SeatPoolID = pool
+			stamped[i] = job
+		}
+		jobs = stamped
+	}
+	c.outbox.append(jobs)
+	counter.Pool = pool
+	return &counter
+}
+
+func (c *MemoryCache) ReleaseSeat(ctx context.Context, sectionID uuid.UUID, pool *uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pool != nil {
+		if pools, ok := c.seatPools(sectionID); ok {
+			if _, ok := pools[*pool]; ok {
+				pools[*pool]++
+				c.replace(interfaces.SectionSeatPoolsKey.Key(sectionID), encodeSeatPools(pools))
+			}
+		}
+	}
+	return c.incrementSeats(sectionID)
+}
+
+func (c *MemoryCache) RetakeSeat(ctx context.Context, sectionID uuid.UUID, pool *uuid.UUID) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if pool != nil {
+		if pools, ok := c.seatPools(sectionID); ok {
+			if _, ok := pools[*pool]; ok {
+				pools[*pool]--
+				c.replace(interfaces.SectionSeatPoolsKey.Key(sectionID), encodeSeatPools(pools))
+			}
+		}
+	}
+	counter, _ := c.seatCounter(sectionID)
+	counter.Available--
+	return c.updateSeatCounter(sectionID, counter).Available, nil
+}
+
+func (c *MemoryCache) SetSeatPools(ctx context.Context, sectionID uuid.UUID, remaining map[uuid.UUID]int, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := interfaces.SectionSeatPoolsKey.Key(sectionID)
+	if len(remaining) == 0 {
+		delete(c.items, key)
+		return nil
+	}
+	c.set(key, encodeSeatPools(remaining), ttl)
+	return nil
+}
+
+func (c *MemoryCache) GetSeatPools(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pools, _ := c.seatPools(sectionID)
+	return pools, nil
+}
+
+// seatPools decodes the seat pool counters of a section, the hash in Redis. Callers must
+// hold c.mu.
+func (c *MemoryCache) seatPools(sectionID uuid.UUID) (map[uuid.UUID]int, bool) {
+	item, ok := c.get(interfaces.SectionSeatPoolsKey.Key(sectionID))
+	if !ok {
+		return nil, false
+	}
+	var pools map[uuid.UUID]int
+	if err := json.Unmarshal([]byte(item.value), &pools); err != nil {
+		return nil, false
+	}
+	return pools, true
+}
+
+func encodeSeatPools(pools map[uuid.UUID]int) string {
+	data, _ := json.Marshal(pools)
+	return string(data)
+}
+
+type memoryOutboxEntry struct {
+	interfaces.OutboxEntry
+	consumer  string
+	claimedAt time.Time
+}
+
+// MemorySyncOutbox is the outbox of a MemoryCache. Like the Redis stream it hands each entry
+// to one dispatcher at a time and lets another claim it once it has gone unacknowledged for
+// minIdle.
+type MemorySyncOutbox struct {
+	mu      sync.Mutex
+	nextID  uint64
+	entries []*memoryOutboxEntry
+	added   chan struct{}
+}
+
+func newMemorySyncOutbox() *MemorySyncOutbox {
+	return &MemorySyncOutbox{added: make(chan struct{}, 1)}
+}
+
+func (o *MemorySyncOutbox) append(jobs []interfaces.DatabaseSyncJob) {
+	o.mu.Lock()
+	for _, job := range jobs {
+		o.nextID++
+		o.entries = append(o.entries, &memoryOutboxEntry{
+			OutboxEntry: interfaces.OutboxEntry{ID: fmt.Sprintf("%d-0", o.nextID), Job: job},
+		})
+	}
+	o.mu.Unlock()
+
+	select {
+	case o.added <- struct{}{}:
+	default:
+	}
+}
+
+func (o *MemorySyncOutbox) Read(ctx context.Context, consumer string, count int, block, minIdle time.Duration) ([]interfaces.OutboxEntry, error) {
+	if entries := o.claim(consumer, count, minIdle); len(entries) > 0 {
+		return entries, nil
+	}
+
+	timer := time.NewTimer(block)
+	defer timer.Stop()
+	select {
+	case <-o.added:
+	case <-timer.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return o.claim(consumer, count, minIdle), nil
+}
+
+// claim takes up to count entries that nobody holds or whose holder left them for minIdle
+func (o *MemorySyncOutbox) claim(consumer string, count int, minIdle time.Duration) []interfaces.OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	var entries []interfaces.OutboxEntry
+	for _, entry := range o.entries {
+		if len(entries) >= count {
+			break
+		}
+		if entry.consumer != "" && now.Sub(entry.claimedAt) < minIdle {
+			continue
+		}
+		entry.consumer = consumer
+		entry.claimedAt = now
+		entries = append(entries, entry.OutboxEntry)
+	}
+	return entries
+}
+
+func (o *MemorySyncOutbox) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	acked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		acked[id] = true
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	remaining := o.entries[:0]
+	for _, entry := range o.entries {
+		if !acked[entry.ID] {
+			remaining = append(remaining, entry)
+		}
+	}
+	for i := len(remaining); i < len(o.entries); i++ {
+		o.entries[i] = nil
+	}
+	o.entries = remaining
+	return nil
+}
+
+func (o *MemorySyncOutbox) Pending(ctx context.Context) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return int64(len(o.entries)), nil
+}
+
+var _ interfaces.SyncOutbox = (*MemorySyncOutbox)(nil)
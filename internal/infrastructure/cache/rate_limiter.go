@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type RedisRateLimiter struct {
+	client redis.UniversalClient
+}
+
+func NewRedisRateLimiter(client redis.UniversalClient) interfaces.RateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+	}
+}
+
+// Allow counts the request in the window containing now. The counter expires with its
+// window, so idle keys cost nothing.
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (*interfaces.RateLimitResult, error) {
+	now := time.Now()
+	windowStart := now.Truncate(window)
+	counterKey := fmt.Sprintf("ratelimit:%s:%d", key, windowStart.Unix())
+
+	pipe := l.client.Pipeline()
+	count := pipe.Incr(ctx, counterKey)
+	pipe.ExpireAt(ctx, counterKey, windowStart.Add(window+time.Second))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to count request for %s: %w", key, err)
+	}
+
+	used := int(count.Val())
+	return &interfaces.RateLimitResult{
+		Allowed:   used <= limit,
+		Limit:     limit,
+		Remaining: max(limit-used, 0),
+		ResetAt:   windowStart.Add(window),
+	}, nil
+}
@@ -2,7 +2,10 @@ package cache
 
 import (
 	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/internal/infrastructure/tracing"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -16,6 +19,8 @@ import (
 
 type RedisCache struct {
 	client redis.UniversalClient
+	retry  scriptRetry
+	outbox SyncOutboxNames
 }
 
 func NewRedisCache(addr, password string, db int) *RedisCache {
@@ -24,16 +29,32 @@ func NewRedisCache(addr, password string, db int) *RedisCache {
 		Password: password,
 		DB:       db,
 	})
+	rdb.AddHook(tracing.NewRedisHook())
 
 	return &RedisCache{
 		client: rdb,
+		retry:  defaultScriptRetry(),
+		outbox: DefaultSyncOutboxNames(),
 	}
 }
 
 // NewRedisCacheWithConfig creates a new Redis cache instance using configuration
 func NewRedisCacheWithConfig(cfg *config.CacheConfig) *RedisCache {
-	
+	return &RedisCache{
+		client: NewRedisClient(cfg),
+		retry:  newScriptRetry(cfg.ScriptRetry),
+		outbox: DefaultSyncOutboxNames(),
+	}
+}
 
+// SetSyncOutboxNames sets the outbox stream seat reservations write their jobs to, which
+// must be the one the RedisSyncOutbox dispatching them reads
+func (r *RedisCache) SetSyncOutboxNames(names SyncOutboxNames) {
+	r.outbox = names
+}
+
+// NewRedisClient creates the Sentinel-backed Redis client described by the configuration
+func NewRedisClient(cfg *config.CacheConfig) redis.UniversalClient {
 	rdb := redis.NewFailoverClient(&redis.FailoverOptions{
 		MasterName:       cfg.Sentinel.MasterName,
 		SentinelAddrs:    cfg.Sentinel.SentinelAddrs,
@@ -45,16 +66,16 @@ func NewRedisCacheWithConfig(cfg *config.CacheConfig) *RedisCache {
 		PoolTimeout:      time.Duration(cfg.PoolTimeout) * time.Second,
 		IdleTimeout:      time.Duration(cfg.IdleTimeout) * time.Second,
 	})
+	rdb.AddHook(tracing.NewRedisHook())
 
-	return &RedisCache{
-		client: rdb,
-	}
+	return rdb
 }
 
 func (r *RedisCache) GetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
-	key := fmt.Sprintf("section:seats:%s", sectionID.String())
+	key := interfaces.SectionSeatsKey.Key(sectionID)
 
-	val, err := r.client.Get(ctx, key).Result()
+	val, err := r.client.HGet(ctx, key, seatCounterAvailable).Result()
+	recordLookup("section_seats", err)
 	if err != nil {
 		if err == redis.Nil {
 			return -1, fmt.Errorf("section seats not cached")
@@ -70,62 +91,195 @@ func (r *RedisCache) GetAvailableSeats(ctx context.Context, sectionID uuid.UUID)
 	return seats, nil
 }
 
-func (r *RedisCache) SetAvailableSeats(ctx context.Context, sectionID uuid.UUID, seats int, ttl time.Duration) error {
-	key := fmt.Sprintf("section:seats:%s", sectionID.String())
+func (r *RedisCache) GetAvailableSeatsBatch(ctx context.Context, sectionIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	seats := make(map[uuid.UUID]int, len(sectionIDs))
+	if len(sectionIDs) == 0 {
+		return seats, nil
+	}
+
+	pipe := r.client.Pipeline()
+	values := make([]*redis.StringCmd, len(sectionIDs))
+	for i, sectionID := range sectionIDs {
+		values[i] = pipe.HGet(ctx, interfaces.SectionSeatsKey.Key(sectionID), seatCounterAvailable)
+	}
+	// Exec reports the first miss as redis.Nil; misses are read off each command below
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get seats from cache: %w", err)
+	}
+
+	for i, sectionID := range sectionIDs {
+		val, err := values[i].Result()
+		recordLookup("section_seats", err)
+		if err != nil {
+			continue
+		}
+		available, err := strconv.Atoi(val)
+		if err != nil {
+			logger.Warn("Ignoring invalid seats value in cache for section %s: %v", sectionID, err)
+			continue
+		}
+		seats[sectionID] = available
+	}
+	return seats, nil
+}
 
-	err := r.client.Set(ctx, key, seats, ttl).Err()
+func (r *RedisCache) GetSeatCounter(ctx context.Context, sectionID uuid.UUID) (*interfaces.SeatCounter, error) {
+	key := interfaces.SectionSeatsKey.Key(sectionID)
+
+	fields, err := r.client.HGetAll(ctx, key).Result()
+	if err == nil && fields[seatCounterAvailable] == "" {
+		err = redis.Nil
+	}
+	recordLookup("section_seats", err)
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("section seats not cached")
+		}
+		return nil, fmt.Errorf("failed to get seat counter from cache: %w", err)
+	}
+
+	counter, err := parseSeatCounter(fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seat counter in cache: %w", err)
+	}
+	return counter, nil
+}
+
+func (r *RedisCache) SetSeatCounter(ctx context.Context, sectionID uuid.UUID, counter interfaces.SeatCounter, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		setSeatCounter(ctx, pipe, sectionID, counter, ttl)
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set seats in cache: %w", err)
 	}
 
+	r.publishSeatChange(ctx, sectionID, counter.Available)
 	return nil
 }
 
-func (r *RedisCache) DecrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error {
-	key := fmt.Sprintf("section:seats:%s", sectionID.String())
+// SetSeatCounters pipelines the writes and their seat change notices, so warming a
+// semester costs a round trip per batch rather than two per section
+func (r *RedisCache) SetSeatCounters(ctx context.Context, counters map[uuid.UUID]interfaces.SeatCounter, ttl time.Duration) error {
+	if len(counters) == 0 {
+		return nil
+	}
 
-	luaScript := `
-		local key = KEYS[1]
-		local current = redis.call("GET", key)
-		if current == false then
-			return redis.error_reply("Key does not exist")
-		end
-		local value = tonumber(current)
-		if value <= 0 then
-			return redis.error_reply("No seats available")
-		end
-		return redis.call("DECR", key)
-	`
+	now := time.Now()
+	pipe := r.client.Pipeline()
+	for sectionID, counter := range counters {
+		setSeatCounter(ctx, pipe, sectionID, counter, ttl)
+
+		payload, err := json.Marshal(interfaces.SeatChange{
+			SectionID:      sectionID,
+			AvailableSeats: counter.Available,
+			ChangedAt:      now,
+		})
+		if err != nil {
+			continue
+		}
+		pipe.Publish(ctx, seatChangesChannel(sectionID), payload)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set seats of %d sections in cache: %w", len(counters), err)
+	}
+	return nil
+}
 
-	err := r.client.Eval(ctx, luaScript, []string{key}).Err()
+// setSeatCounter queues the writes that replace a seat counter on pipe
+func setSeatCounter(ctx context.Context, pipe redis.Pipeliner, sectionID uuid.UUID, counter interfaces.SeatCounter, ttl time.Duration) {
+	key := interfaces.SectionSeatsKey.Key(sectionID)
+	pipe.HSet(ctx, key,
+		seatCounterAvailable, counter.Available,
+		seatCounterTotal, counter.Total,
+		seatCounterReserved, counter.Reserved,
+	)
+	pipe.HIncrBy(ctx, key, seatCounterVersion, 1)
+	if ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	} else {
+		pipe.Persist(ctx, key)
+	}
+}
+
+// setSeatCapacityScript sets the total and reserved seats of the counter in KEYS[1] to
+// ARGV[1] and ARGV[2], moving its available seats by the change in total. It refuses a total
+// below the seats taken. Returns 1 or 0 for done or refused, then the counter's available,
+// total, reserved and version fields.
+var setSeatCapacityScript = redis.NewScript(`
+	local counter = redis.call("HMGET", KEYS[1], "available", "total")
+	if counter[1] == false or counter[2] == false then
+		return redis.error_reply("Key does not exist")
+	end
+	local total = tonumber(ARGV[1])
+	local available = tonumber(counter[1]) + total - tonumber(counter[2])
+	if available < 0 then
+		local current = redis.call("HMGET", KEYS[1], "available", "total", "reserved", "version")
+		return {0, tonumber(current[1]), tonumber(current[2]), tonumber(current[3] or "0"), tonumber(current[4] or "0")}
+	end
+	redis.call("HSET", KEYS[1], "available", available, "total", total, "reserved", ARGV[2])
+	local version = redis.call("HINCRBY", KEYS[1], "version", 1)
+	return {1, available, total, tonumber(ARGV[2]), version}
+`)
+
+func (r *RedisCache) SetSeatCapacity(ctx context.Context, sectionID uuid.UUID, total, reserved int) (*interfaces.SeatCounter, error) {
+	key := interfaces.SectionSeatsKey.Key(sectionID)
+
+	result, err := r.retry.run(ctx, r.client, "set_seat_capacity", setSeatCapacityScript, false, []string{key}, total, reserved).Int64Slice()
 	if err != nil {
-		// Check if the error is due to key not existing
 		if strings.Contains(err.Error(), "Key does not exist") {
-			return fmt.Errorf("seat key not found for section %s", sectionID.String())
+			return nil, fmt.Errorf("seat key not found for section %s", sectionID.String())
 		}
-		return fmt.Errorf("failed to decrement seats: %w", err)
+		return nil, fmt.Errorf("failed to set seat capacity: %w", err)
+	}
+	if len(result) != 5 {
+		return nil, fmt.Errorf("unexpected result type from Redis")
 	}
 
-	return nil
+	counter := &interfaces.SeatCounter{
+		Available: int(result[1]),
+		Total:     int(result[2]),
+		Reserved:  int(result[3]),
+		Version:   result[4],
+	}
+	if result[0] == 0 {
+		return counter, interfaces.ErrCapacityBelowEnrolled
+	}
+
+	r.publishSeatChange(ctx, sectionID, counter.Available)
+	return counter, nil
 }
 
-func (r *RedisCache) DecrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
-	key := fmt.Sprintf("section:seats:%s", sectionID.String())
+// decrementSeatsScript takes one seat from the counter in KEYS[1] unless none are left
+var decrementSeatsScript = redis.NewScript(`
+	local current = redis.call("HGET", KEYS[1], "available")
+	if current == false then
+		return redis.error_reply("Key does not exist")
+	end
+	if tonumber(current) <= 0 then
+		return redis.error_reply("No seats available")
+	end
+	redis.call("HINCRBY", KEYS[1], "version", 1)
+	return redis.call("HINCRBY", KEYS[1], "available", -1)
+`)
+
+// incrementSeatsScript gives one seat back to the counter in KEYS[1]. A missing counter
+// starts from zero, as HINCRBY does.
+var incrementSeatsScript = redis.NewScript(`
+	redis.call("HINCRBY", KEYS[1], "version", 1)
+	return redis.call("HINCRBY", KEYS[1], "available", 1)
+`)
 
-	luaScript := `
-		local key = KEYS[1]
-		local current = redis.call("GET", key)
-		if current == false then
-			return redis.error_reply("Key does not exist")
-		end
-		local value = tonumber(current)
-		if value <= 0 then
-			return redis.error_reply("No seats available")
-		end
-		return redis.call("DECR", key)
-	`
+func (r *RedisCache) DecrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error {
+	_, err := r.DecrementAndGetAvailableSeats(ctx, sectionID)
+	return err
+}
+
+func (r *RedisCache) DecrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	key := interfaces.SectionSeatsKey.Key(sectionID)
 
-	result, err := r.client.Eval(ctx, luaScript, []string{key}).Result()
+	seats, err := r.retry.run(ctx, r.client, "decrement_seats", decrementSeatsScript, false, []string{key}).Int()
 	if err != nil {
 		// Check if the error is due to key not existing
 		if strings.Contains(err.Error(), "Key does not exist") {
@@ -134,40 +288,126 @@ func (r *RedisCache) DecrementAndGetAvailableSeats(ctx context.Context, sectionI
 		return -1, fmt.Errorf("failed to decrement seats: %w", err)
 	}
 
-	newValue, ok := result.(int64)
-	if !ok {
-		return -1, fmt.Errorf("unexpected result type from Redis")
-	}
-
-	return int(newValue), nil
+	r.publishSeatChange(ctx, sectionID, seats)
+	return seats, nil
 }
 
 func (r *RedisCache) IncrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
-	key := fmt.Sprintf("section:seats:%s", sectionID.String())
+	key := interfaces.SectionSeatsKey.Key(sectionID)
 
-	result, err := r.client.Incr(ctx, key).Result()
+	seats, err := r.retry.run(ctx, r.client, "increment_seats", incrementSeatsScript, false, []string{key}).Int()
 	if err != nil {
 		return -1, fmt.Errorf("failed to increment seats: %w", err)
 	}
 
-	return int(result), nil
+	r.publishSeatChange(ctx, sectionID, seats)
+	return seats, nil
+}
+
+// compareAndSetSeatsScript swaps the available seats of the counter in KEYS[1] to ARGV[2]
+// only while they still equal ARGV[1]. Returns 1 on swap, 0 on mismatch and -1 if the
+// counter is gone.
+var compareAndSetSeatsScript = redis.NewScript(`
+	local current = redis.call("HGET", KEYS[1], "available")
+	if current == false then
+		return -1
+	end
+	if tonumber(current) ~= tonumber(ARGV[1]) then
+		return 0
+	end
+	redis.call("HSET", KEYS[1], "available", ARGV[2])
+	redis.call("HINCRBY", KEYS[1], "version", 1)
+	return 1
+`)
+
+func (r *RedisCache) CompareAndSetAvailableSeats(ctx context.Context, sectionID uuid.UUID, expected, seats int) (bool, error) {
+	key := interfaces.SectionSeatsKey.Key(sectionID)
+
+	result, err := r.retry.run(ctx, r.client, "compare_and_set_seats", compareAndSetSeatsScript, true, []string{key}, expected, seats).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to compare and set seats: %w", err)
+	}
+	if result < 0 {
+		return false, fmt.Errorf("seat key not found for section %s", sectionID.String())
+	}
+
+	if result == 1 {
+		r.publishSeatChange(ctx, sectionID, seats)
+	}
+	return result == 1, nil
 }
 
 func (r *RedisCache) IncrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error {
-	key := fmt.Sprintf("section:seats:%s", sectionID.String())
+	_, err := r.IncrementAndGetAvailableSeats(ctx, sectionID)
+	return err
+}
 
-	err := r.client.Incr(ctx, key).Err()
+// publishSeatChange announces a new seat count to availability streams. Publishing is best
+// effort: a missed message only delays a live view until the next change.
+func (r *RedisCache) publishSeatChange(ctx context.Context, sectionID uuid.UUID, seats int) {
+	payload, err := json.Marshal(interfaces.SeatChange{
+		SectionID:      sectionID,
+		AvailableSeats: seats,
+		ChangedAt:      time.Now(),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to increment seats: %w", err)
+		return
 	}
 
-	return nil
+	if err := r.client.Publish(ctx, seatChangesChannel(sectionID), payload).Err(); err != nil {
+		logger.Warn("Failed to publish seat change for section %s: %v", sectionID, err)
+	}
+}
+
+func (r *RedisCache) SubscribeSeatChanges(ctx context.Context, sectionID uuid.UUID) (<-chan interfaces.SeatChange, error) {
+	pubsub := r.client.Subscribe(ctx, seatChangesChannel(sectionID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to seat changes: %w", err)
+	}
+
+	changes := make(chan interfaces.SeatChange, 16)
+	go func() {
+		defer close(changes)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var change interfaces.SeatChange
+				if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+					logger.Warn("Ignoring malformed seat change for section %s: %v", sectionID, err)
+					continue
+				}
+
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
+
+func seatChangesChannel(sectionID uuid.UUID) string {
+	return fmt.Sprintf("section:seats:changes:%s", sectionID.String())
 }
 
 func (r *RedisCache) GetSectionDetails(ctx context.Context, sectionID uuid.UUID) (interface{}, error) {
-	key := fmt.Sprintf("section:details:%s", sectionID.String())
+	key := interfaces.SectionDetailsCache.Key(sectionID)
 
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("section_details", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("section details not cached")
@@ -180,14 +420,14 @@ func (r *RedisCache) GetSectionDetails(ctx context.Context, sectionID uuid.UUID)
 }
 
 func (r *RedisCache) SetSectionDetails(ctx context.Context, sectionID uuid.UUID, data interface{}, ttl time.Duration) error {
-	key := fmt.Sprintf("section:details:%s", sectionID.String())
+	key := interfaces.SectionDetailsCache.Key(sectionID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal section details: %w", err)
 	}
 
-	err = r.client.Set(ctx, key, jsonData, ttl).Err()
+	err = r.setView(ctx, interfaces.SectionDetailsCache, key, jsonData, ttl)
 	if err != nil {
 		return fmt.Errorf("failed to set section details: %w", err)
 	}
@@ -196,9 +436,10 @@ func (r *RedisCache) SetSectionDetails(ctx context.Context, sectionID uuid.UUID,
 }
 
 func (r *RedisCache) GetCourseDetails(ctx context.Context, courseID uuid.UUID) (interface{}, error) {
-	key := fmt.Sprintf("course:details:%s", courseID.String())
+	key := interfaces.CourseDetailsCache.Key(courseID)
 
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("course_details", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("course details not cached")
@@ -211,7 +452,7 @@ func (r *RedisCache) GetCourseDetails(ctx context.Context, courseID uuid.UUID) (
 }
 
 func (r *RedisCache) SetCourseDetails(ctx context.Context, courseID uuid.UUID, data interface{}, ttl time.Duration) error {
-	key := fmt.Sprintf("course:details:%s", courseID.String())
+	key := interfaces.CourseDetailsCache.Key(courseID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -227,9 +468,10 @@ func (r *RedisCache) SetCourseDetails(ctx context.Context, courseID uuid.UUID, d
 }
 
 func (r *RedisCache) GetStudentDetails(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
-	key := fmt.Sprintf("student:details:%s", studentID.String())
+	key := interfaces.StudentDetailsCache.Key(studentID)
 
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("student_details", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("student details not cached")
@@ -242,7 +484,7 @@ func (r *RedisCache) GetStudentDetails(ctx context.Context, studentID uuid.UUID)
 }
 
 func (r *RedisCache) SetStudentDetails(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
-	key := fmt.Sprintf("student:details:%s", studentID.String())
+	key := interfaces.StudentDetailsCache.Key(studentID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -258,9 +500,10 @@ func (r *RedisCache) SetStudentDetails(ctx context.Context, studentID uuid.UUID,
 }
 
 func (r *RedisCache) GetStudentRegistrations(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
-	key := fmt.Sprintf("student:registrations:%s", studentID.String())
+	key := interfaces.StudentRegistrationsCache.Key(studentID)
 
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("student_registrations", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("student registrations not cached")
@@ -273,7 +516,7 @@ func (r *RedisCache) GetStudentRegistrations(ctx context.Context, studentID uuid
 }
 
 func (r *RedisCache) SetStudentRegistrations(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
-	key := fmt.Sprintf("student:registrations:%s", studentID.String())
+	key := interfaces.StudentRegistrationsCache.Key(studentID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -289,9 +532,10 @@ func (r *RedisCache) SetStudentRegistrations(ctx context.Context, studentID uuid
 }
 
 func (r *RedisCache) GetStudentWaitlistStatus(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
-	key := fmt.Sprintf("student:waitlist:%s", studentID.String())
+	key := interfaces.StudentWaitlistCache.Key(studentID)
 
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("student_waitlist", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("student waitlist status not cached")
@@ -304,7 +548,7 @@ func (r *RedisCache) GetStudentWaitlistStatus(ctx context.Context, studentID uui
 }
 
 func (r *RedisCache) SetStudentWaitlistStatus(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
-	key := fmt.Sprintf("student:waitlist:%s", studentID.String())
+	key := interfaces.StudentWaitlistCache.Key(studentID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -320,9 +564,10 @@ func (r *RedisCache) SetStudentWaitlistStatus(ctx context.Context, studentID uui
 }
 
 func (r *RedisCache) GetAvailableSections(ctx context.Context, semesterID uuid.UUID) (interface{}, error) {
-	key := fmt.Sprintf("sections:available:%s", semesterID.String())
+	key := interfaces.AvailableSectionsCache.Key(semesterID)
 
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("available_sections", err)
 	if err != nil {
 		if err == redis.Nil {
 			return nil, fmt.Errorf("available sections not cached")
@@ -336,14 +581,14 @@ func (r *RedisCache) GetAvailableSections(ctx context.Context, semesterID uuid.U
 
 func (r *RedisCache) SetAvailableSections(ctx context.Context, semesterID uuid.UUID, data interface{}, ttl time.Duration) error {
 
-	key := fmt.Sprintf("sections:available:%s", semesterID.String())
+	key := interfaces.AvailableSectionsCache.Key(semesterID)
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal available sections: %w", err)
 	}
 
-	err = r.client.Set(ctx, key, jsonData, ttl).Err()
+	err = r.setView(ctx, interfaces.AvailableSectionsCache, key, jsonData, ttl)
 	if err != nil {
 		return fmt.Errorf("failed to set available sections: %w", err)
 	}
@@ -352,43 +597,35 @@ func (r *RedisCache) SetAvailableSections(ctx context.Context, semesterID uuid.U
 }
 
 // Cache invalidation methods
-func (r *RedisCache) InvalidateStudentCache(ctx context.Context, studentID uuid.UUID) error {
-	pattern := fmt.Sprintf("student:*:%s", studentID.String())
-	return r.Clear(ctx, pattern)
+func (r *RedisCache) InvalidateStudentCache(ctx context.Context, studentID uuid.UUID, semesterIDs ...uuid.UUID) error {
+	return r.invalidate(ctx, interfaces.CacheInvalidationSet(interfaces.CacheScopeStudent, studentID, semesterIDs...))
 }
 
 func (r *RedisCache) InvalidateSectionCache(ctx context.Context, sectionID uuid.UUID) error {
-	// Clear section-specific cache
-	sectionPattern := fmt.Sprintf("section:*:%s", sectionID.String())
-	if err := r.Clear(ctx, sectionPattern); err != nil {
-		return err
-	}
-
-	// Clear available sections cache (since it includes this section)
-	availableSectionsPattern := "sections:available:*"
-	return r.Clear(ctx, availableSectionsPattern)
+	set := interfaces.CacheInvalidationSet(interfaces.CacheScopeSection, sectionID)
+	// Available section lists include this section
+	set.Patterns = append(set.Patterns, interfaces.AvailableSectionsCache.Pattern())
+	return r.invalidate(ctx, set)
 }
 
-func (r *RedisCache) Delete(ctx context.Context, key string) error {
-	err := r.client.Del(ctx, key).Err()
-	if err != nil {
-		return fmt.Errorf("failed to delete key %s: %w", key, err)
+func (r *RedisCache) invalidate(ctx context.Context, set interfaces.InvalidationSet) error {
+	if len(set.Keys) > 0 {
+		if err := r.client.Del(ctx, set.Keys...).Err(); err != nil {
+			return fmt.Errorf("failed to delete keys: %w", err)
+		}
+	}
+	for _, pattern := range set.Patterns {
+		if err := r.Clear(ctx, pattern); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
-func (r *RedisCache) Clear(ctx context.Context, pattern string) error {
-	keys, err := r.client.Keys(ctx, pattern).Result()
+func (r *RedisCache) Delete(ctx context.Context, key string) error {
+	err := r.client.Del(ctx, key).Err()
 	if err != nil {
-		return fmt.Errorf("failed to get keys for pattern %s: %w", pattern, err)
-	}
-
-	if len(keys) > 0 {
-		err = r.client.Del(ctx, keys...).Err()
-		if err != nil {
-			return fmt.Errorf("failed to delete keys: %w", err)
-		}
+		return fmt.Errorf("failed to delete key %s: %w", key, err)
 	}
 
 	return nil
@@ -405,6 +642,7 @@ func (r *RedisCache) Health(ctx context.Context) error {
 // Generic cache operations for HTTP responses and other data
 func (r *RedisCache) Get(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
+	recordLookup("generic", err)
 	if err != nil {
 		if err == redis.Nil {
 			return "", fmt.Errorf("key not found")
@@ -471,65 +709,246 @@ func (r *RedisCache) SetWithMetadata(ctx context.Context, key string, value stri
 	return nil
 }
 
-// Waitlist management using Redis sorted sets
-func (r *RedisCache) AddToWaitlist(ctx context.Context, sectionID, studentID uuid.UUID, position int, entry interface{}) error {
-	waitlistKey := fmt.Sprintf("waitlist:section:%s", sectionID.String())
-	entryKey := fmt.Sprintf("waitlist:entry:%s:%s", sectionID.String(), studentID.String())
-	studentWaitlistKey := fmt.Sprintf("waitlist:student:%s", studentID.String())
+// addToWaitlistScript writes the sorted set member, entry document and student index together,
+// so a failure part way cannot leave a student queued without an entry or the reverse. The
+// cap in ARGV[6] is checked in the same step, so concurrent adds cannot overshoot it; it
+// returns 0 without writing anything when the waitlist is full.
+var addToWaitlistScript = redis.NewScript(`
+	local maxSize = tonumber(ARGV[6])
+	if maxSize > 0 and redis.call("ZSCORE", KEYS[1], ARGV[2]) == false and redis.call("ZCARD", KEYS[1]) >= maxSize then
+		return 0
+	end
+
+	redis.call("ZADD", KEYS[1], ARGV[1], ARGV[2])
+	redis.call("SET", KEYS[2], ARGV[3], "PX", ARGV[5])
+	redis.call("SADD", KEYS[3], ARGV[4])
+	redis.call("PEXPIRE", KEYS[3], ARGV[5])
+	return 1
+`)
+
+// removeFromWaitlistScript is the inverse of addToWaitlistScript
+var removeFromWaitlistScript = redis.NewScript(`
+	local removed = redis.call("ZREM", KEYS[1], ARGV[1])
+	removed = removed + redis.call("DEL", KEYS[2])
+	removed = removed + redis.call("SREM", KEYS[3], ARGV[2])
+	return removed
+`)
 
+// Waitlist management using Redis sorted sets
+func (r *RedisCache) AddToWaitlist(ctx context.Context, sectionID, studentID uuid.UUID, position int, entry interface{}, maxSize int) error {
 	// Serialize entry data
 	entryData, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal waitlist entry: %w", err)
 	}
 
-	// Use pipeline for atomic operations
-	pipe := r.client.Pipeline()
-
-	// Add to section waitlist sorted set (score = position for ordering)
-	pipe.ZAdd(ctx, waitlistKey, &redis.Z{
-		Score:  float64(position),
-		Member: studentID.String(),
-	})
-
-	// Store detailed entry information
-	pipe.Set(ctx, entryKey, entryData, 24*time.Hour)
-
-	// Add to student's waitlist set
-	pipe.SAdd(ctx, studentWaitlistKey, sectionID.String())
-	pipe.Expire(ctx, studentWaitlistKey, 24*time.Hour)
-
-	_, err = pipe.Exec(ctx)
+	// Score = position for ordering; the student's set lists the sections they wait for
+	added, err := r.retry.run(ctx, r.client, "add_to_waitlist", addToWaitlistScript, true, waitlistCacheKeys(sectionID, studentID),
+		position, studentID.String(), entryData, sectionID.String(), (24 * time.Hour).Milliseconds(), maxSize,
+	).Int()
 	if err != nil {
 		return fmt.Errorf("failed to add to waitlist: %w", err)
 	}
+	if added == 0 {
+		return interfaces.ErrWaitlistFull
+	}
 
 	return nil
 }
 
 func (r *RedisCache) RemoveFromWaitlist(ctx context.Context, sectionID, studentID uuid.UUID) error {
+	err := r.retry.run(ctx, r.client, "remove_from_waitlist", removeFromWaitlistScript, true, waitlistCacheKeys(sectionID, studentID),
+		studentID.String(), sectionID.String(),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to remove from waitlist: %w", err)
+	}
+
+	return nil
+}
+
+// waitlistCacheKeys returns the section sorted set, entry document and student index keys
+func waitlistCacheKeys(sectionID, studentID uuid.UUID) []string {
+	return []string{
+		fmt.Sprintf("waitlist:section:%s", sectionID.String()),
+		fmt.Sprintf("waitlist:entry:%s:%s", sectionID.String(), studentID.String()),
+		fmt.Sprintf("waitlist:student:%s", studentID.String()),
+	}
+}
+
+// errWaitlistChanged is returned by the scripts that renumber a waitlist when a student
+// joined it after its entry keys were read, so the keys are read again
+const errWaitlistChanged = "Waitlist changed"
+
+// waitlistChangedAttempts bounds how often the entry keys of a busy waitlist are read again
+const waitlistChangedAttempts = 3
+
+// leaveWaitlistScript removes ARGV[1] from the section waitlist and shifts later students
+// up one place, both in the sorted set and in their entry documents. KEYS[4] onwards are
+// the entry documents of the members listed from ARGV[3]; a member behind the student
+// without one fails the script before anything is written.
+var leaveWaitlistScript = redis.NewScript(`
+	local entryKeys = {}
+	for i = 4, #KEYS do
+		entryKeys[ARGV[i - 1]] = KEYS[i]
+	end
+
+	local score = redis.call("ZSCORE", KEYS[1], ARGV[1])
+	if score == false then
+		return -1
+	end
+	local position = tonumber(score)
+
+	local behind = redis.call("ZRANGEBYSCORE", KEYS[1], "(" .. position, "+inf")
+	for _, member in ipairs(behind) do
+		if entryKeys[member] == nil then
+			return redis.error_reply("Waitlist changed")
+		end
+	end
+
+	redis.call("ZREM", KEYS[1], ARGV[1])
+	redis.call("DEL", KEYS[2])
+	redis.call("SREM", KEYS[3], ARGV[2])
+
+	for _, member in ipairs(behind) do
+		local newPosition = redis.call("ZINCRBY", KEYS[1], -1, member)
+		local entryKey = entryKeys[member]
+		local data = redis.call("GET", entryKey)
+		if data then
+			local entry = cjson.decode(data)
+			entry["position"] = tonumber(newPosition)
+			local ttl = redis.call("PTTL", entryKey)
+			if ttl > 0 then
+				redis.call("SET", entryKey, cjson.encode(entry), "PX", ttl)
+			else
+				redis.call("SET", entryKey, cjson.encode(entry))
+			end
+		end
+	end
+
+	return position
+`)
+
+func (r *RedisCache) LeaveWaitlist(ctx context.Context, sectionID, studentID uuid.UUID) (int, error) {
 	waitlistKey := fmt.Sprintf("waitlist:section:%s", sectionID.String())
 	entryKey := fmt.Sprintf("waitlist:entry:%s:%s", sectionID.String(), studentID.String())
 	studentWaitlistKey := fmt.Sprintf("waitlist:student:%s", studentID.String())
 
-	// Use pipeline for atomic operations
-	pipe := r.client.Pipeline()
-
-	// Remove from section waitlist sorted set
-	pipe.ZRem(ctx, waitlistKey, studentID.String())
+	for attempt := 1; ; attempt++ {
+		members, memberKeys, err := r.waitlistEntryKeys(ctx, sectionID)
+		if err != nil {
+			return -1, fmt.Errorf("failed to leave waitlist: %w", err)
+		}
 
-	// Remove detailed entry
-	pipe.Del(ctx, entryKey)
+		keys := append([]string{waitlistKey, entryKey, studentWaitlistKey}, memberKeys...)
+		args := append([]any{studentID.String(), sectionID.String()}, members...)
+		position, err := r.retry.run(ctx, r.client, "leave_waitlist", leaveWaitlistScript, false, keys, args...).Int()
+		if err != nil && strings.Contains(err.Error(), errWaitlistChanged) && attempt < waitlistChangedAttempts {
+			continue
+		}
+		if err != nil {
+			return -1, fmt.Errorf("failed to leave waitlist: %w", err)
+		}
 
-	// Remove from student's waitlist set
-	pipe.SRem(ctx, studentWaitlistKey, sectionID.String())
+		return position, nil
+	}
+}
 
-	_, err := pipe.Exec(ctx)
+// waitlistEntryKeys returns the members of the section waitlist and the keys of their entry
+// documents, which the scripts renumbering it declare
+func (r *RedisCache) waitlistEntryKeys(ctx context.Context, sectionID uuid.UUID) ([]any, []string, error) {
+	waitlistKey := fmt.Sprintf("waitlist:section:%s", sectionID.String())
+	members, err := r.client.ZRange(ctx, waitlistKey, 0, -1).Result()
 	if err != nil {
-		return fmt.Errorf("failed to remove from waitlist: %w", err)
+		return nil, nil, fmt.Errorf("failed to get waitlist members: %w", err)
 	}
 
-	return nil
+	args := make([]any, len(members))
+	keys := make([]string, len(members))
+	for i, member := range members {
+		args[i] = member
+		keys[i] = fmt.Sprintf("waitlist:entry:%s:%s", sectionID.String(), member)
+	}
+	return args, keys, nil
+}
+
+// compactWaitlistScript renumbers the section waitlist to 1..n in queue order and rewrites
+// the position in each moved entry document. KEYS[2] onwards are the entry documents of the
+// members listed in ARGV; a member without one fails the script before anything is written.
+// It returns the moved members and their new positions as a flat member, position list.
+var compactWaitlistScript = redis.NewScript(`
+	local entryKeys = {}
+	for i = 2, #KEYS do
+		entryKeys[ARGV[i - 1]] = KEYS[i]
+	end
+
+	local members = redis.call("ZRANGE", KEYS[1], 0, -1, "WITHSCORES")
+	for i = 1, #members, 2 do
+		if entryKeys[members[i]] == nil then
+			return redis.error_reply("Waitlist changed")
+		end
+	end
+
+	local moved = {}
+	local position = 0
+
+	for i = 1, #members, 2 do
+		local member = members[i]
+		position = position + 1
+		if tonumber(members[i + 1]) ~= position then
+			redis.call("ZADD", KEYS[1], position, member)
+			local entryKey = entryKeys[member]
+			local data = redis.call("GET", entryKey)
+			if data then
+				local entry = cjson.decode(data)
+				entry["position"] = position
+				local ttl = redis.call("PTTL", entryKey)
+				if ttl > 0 then
+					redis.call("SET", entryKey, cjson.encode(entry), "PX", ttl)
+				else
+					redis.call("SET", entryKey, cjson.encode(entry))
+				end
+			end
+			table.insert(moved, member)
+			table.insert(moved, position)
+		end
+	end
+
+	return moved
+`)
+
+func (r *RedisCache) CompactWaitlist(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error) {
+	waitlistKey := fmt.Sprintf("waitlist:section:%s", sectionID.String())
+
+	var result []any
+	for attempt := 1; ; attempt++ {
+		members, memberKeys, err := r.waitlistEntryKeys(ctx, sectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compact waitlist: %w", err)
+		}
+
+		result, err = r.retry.run(ctx, r.client, "compact_waitlist", compactWaitlistScript, true, append([]string{waitlistKey}, memberKeys...), members...).Slice()
+		if err != nil && strings.Contains(err.Error(), errWaitlistChanged) && attempt < waitlistChangedAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to compact waitlist: %w", err)
+		}
+		break
+	}
+
+	moved := make(map[uuid.UUID]int, len(result)/2)
+	for i := 0; i+1 < len(result); i += 2 {
+		member, _ := result[i].(string)
+		position, _ := result[i+1].(int64)
+		studentID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		moved[studentID] = int(position)
+	}
+
+	return moved, nil
 }
 
 func (r *RedisCache) GetNextInWaitlist(ctx context.Context, sectionID uuid.UUID) (interface{}, error) {
@@ -591,6 +1010,27 @@ func (r *RedisCache) GetWaitlistSize(ctx context.Context, sectionID uuid.UUID) (
 	return int(count), nil
 }
 
+func (r *RedisCache) GetWaitlistSizes(ctx context.Context, sectionIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	sizes := make(map[uuid.UUID]int, len(sectionIDs))
+	if len(sectionIDs) == 0 {
+		return sizes, nil
+	}
+
+	pipe := r.client.Pipeline()
+	counts := make([]*redis.IntCmd, len(sectionIDs))
+	for i, sectionID := range sectionIDs {
+		counts[i] = pipe.ZCard(ctx, fmt.Sprintf("waitlist:section:%s", sectionID.String()))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get waitlist sizes: %w", err)
+	}
+
+	for i, sectionID := range sectionIDs {
+		sizes[sectionID] = int(counts[i].Val())
+	}
+	return sizes, nil
+}
+
 func (r *RedisCache) GetStudentWaitlists(ctx context.Context, studentID uuid.UUID) ([]interface{}, error) {
 	studentWaitlistKey := fmt.Sprintf("waitlist:student:%s", studentID.String())
 
@@ -624,8 +1064,15 @@ func (r *RedisCache) GetStudentWaitlists(ctx context.Context, studentID uuid.UUI
 		entryData, err := cmd.Result()
 		if err != nil {
 			if err == redis.Nil {
-				// Entry expired, clean up student waitlist set
-				r.client.SRem(ctx, studentWaitlistKey, sectionIDs[i])
+				// Entry expired, clean up the student waitlist set and any queue member left behind
+				sectionID, parseErr := uuid.Parse(sectionIDs[i])
+				if parseErr != nil {
+					r.client.SRem(ctx, studentWaitlistKey, sectionIDs[i])
+					continue
+				}
+				if err := r.RemoveFromWaitlist(ctx, sectionID, studentID); err != nil {
+					logger.Warn("Failed to repair waitlist for student %s in section %s: %v", studentID, sectionID, err)
+				}
 				continue
 			}
 			return nil, fmt.Errorf("failed to get waitlist entry data: %w", err)
@@ -642,6 +1089,31 @@ func (r *RedisCache) GetStudentWaitlists(ctx context.Context, studentID uuid.UUI
 	return waitlists, nil
 }
 
+// releaseLockScript deletes the lock only if it still holds the caller's token, so a holder
+// whose lock expired cannot release a lock taken over by someone else.
+var releaseLockScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	end
+	return 0
+`)
+
+func (r *RedisCache) AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	acquired, err := r.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	return acquired, nil
+}
+
+func (r *RedisCache) ReleaseLock(ctx context.Context, key, token string) (bool, error) {
+	released, err := r.retry.run(ctx, r.client, "release_lock", releaseLockScript, true, []string{key}, token).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+	return released == 1, nil
+}
+
 // GetCacheStats returns cache statistics
 func (r *RedisCache) GetCacheStats(ctx context.Context) (map[string]interface{}, error) {
 	info, err := r.client.Info(ctx, "stats").Result()
@@ -677,3 +1149,14 @@ var _ interfaces.CacheService = (*RedisCache)(nil)
 func (r *RedisCache) GetClient() redis.UniversalClient {
 	return r.client
 }
+
+// recordLookup counts a cache read as a hit, miss or error for the cache hit rate metrics
+func recordLookup(cache string, err error) {
+	result := metrics.CacheHit
+	if err == redis.Nil {
+		result = metrics.CacheMiss
+	} else if err != nil {
+		result = metrics.CacheError
+	}
+	metrics.CacheLookups.WithLabelValues(cache, result).Inc()
+}
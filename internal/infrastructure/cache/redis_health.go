@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisHealthChecker pings Redis through the shared client and asks each configured
+// Sentinel in turn about the master
+type RedisHealthChecker struct {
+	client redis.UniversalClient
+	cfg    *config.CacheConfig
+}
+
+func NewRedisHealthChecker(client redis.UniversalClient, cfg *config.CacheConfig) interfaces.RedisHealth {
+	return &RedisHealthChecker{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+func (h *RedisHealthChecker) Ping(ctx context.Context) error {
+	return h.client.Ping(ctx).Err()
+}
+
+// Sentinel reports the master as the first Sentinel that answers sees it, flagging it down
+// or failing over if any Sentinel does
+func (h *RedisHealthChecker) Sentinel(ctx context.Context) (interfaces.SentinelState, error) {
+	state := interfaces.SentinelState{
+		MasterName: h.cfg.Sentinel.MasterName,
+		Configured: len(h.cfg.Sentinel.SentinelAddrs),
+	}
+
+	var lastErr error
+	for _, addr := range h.cfg.Sentinel.SentinelAddrs {
+		master, err := h.sentinelMaster(ctx, addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		state.Reachable++
+
+		flags := strings.Split(master["flags"], ",")
+		for _, flag := range flags {
+			switch flag {
+			case "s_down", "o_down":
+				state.MasterDown = true
+			case "failover_in_progress":
+				state.FailoverInProgress = true
+			}
+		}
+		if state.Reachable == 1 {
+			state.MasterAddr = net.JoinHostPort(master["ip"], master["port"])
+			state.Flags = flags
+		}
+	}
+
+	if state.Reachable == 0 {
+		return state, fmt.Errorf("none of %d sentinels answered: %w", state.Configured, lastErr)
+	}
+	return state, nil
+}
+
+func (h *RedisHealthChecker) sentinelMaster(ctx context.Context, addr string) (map[string]string, error) {
+	sentinel := redis.NewSentinelClient(&redis.Options{
+		Addr:       addr,
+		Password:   h.cfg.Sentinel.SentinelPassword,
+		MaxRetries: -1,
+	})
+	defer sentinel.Close()
+
+	master, err := sentinel.Master(ctx, h.cfg.Sentinel.MasterName).Result()
+	if err != nil {
+		return nil, fmt.Errorf("sentinel %s: %w", addr, err)
+	}
+	return master, nil
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Script retry defaults for caches built without configuration
+const (
+	defaultScriptAttempts  = 3
+	defaultScriptBaseDelay = 25 * time.Millisecond
+	defaultScriptMaxDelay  = 250 * time.Millisecond
+)
+
+// notExecutedPrefixes are Redis error replies sent instead of running a command, mostly while
+// a replica is being promoted. A script rejected with one of them never ran.
+var notExecutedPrefixes = []string{"READONLY", "LOADING", "MASTERDOWN", "TRYAGAIN", "CLUSTERDOWN"}
+
+// scriptRetry is the retry budget for Lua script calls
+type scriptRetry struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+func defaultScriptRetry() scriptRetry {
+	return scriptRetry{attempts: defaultScriptAttempts, baseDelay: defaultScriptBaseDelay, maxDelay: defaultScriptMaxDelay}
+}
+
+func newScriptRetry(cfg config.ScriptRetryConfig) scriptRetry {
+	retry := defaultScriptRetry()
+	if cfg.MaxAttempts > 0 {
+		retry.attempts = cfg.MaxAttempts
+	}
+	if cfg.BaseDelayMs > 0 {
+		retry.baseDelay = time.Duration(cfg.BaseDelayMs) * time.Millisecond
+	}
+	if cfg.MaxDelayMs > 0 {
+		retry.maxDelay = time.Duration(cfg.MaxDelayMs) * time.Millisecond
+	}
+	return retry
+}
+
+// run calls a Lua script, retrying transient errors within the budget. Scripts that are
+// not idempotent, such as seat decrements, are only retried when the error proves the
+// script never ran; a timeout after the call was sent may hide a decrement that happened.
+// Retries stop as soon as ctx is done. A transient error left after the last attempt is
+// wrapped in interfaces.ErrCacheTransient, or in interfaces.ErrCacheNotExecuted when the
+// script never ran.
+func (r scriptRetry) run(ctx context.Context, client redis.UniversalClient, name string, script *redis.Script, idempotent bool, keys []string, args ...any) *redis.Cmd {
+	var cmd *redis.Cmd
+	for attempt := 1; ; attempt++ {
+		cmd = script.Run(ctx, client, keys, args...)
+		err := cmd.Err()
+		if err == nil || err == redis.Nil || ctx.Err() != nil {
+			return cmd
+		}
+
+		retryable := scriptNotExecuted(err) || (idempotent && transientRedisError(err))
+		if !retryable || attempt >= r.attempts {
+			if scriptNotExecuted(err) {
+				cmd.SetErr(fmt.Errorf("%w: %w", interfaces.ErrCacheNotExecuted, err))
+			} else if transientRedisError(err) {
+				cmd.SetErr(fmt.Errorf("%w: %w", interfaces.ErrCacheTransient, err))
+			}
+			return cmd
+		}
+
+		metrics.CacheScriptRetries.WithLabelValues(name).Inc()
+		logger.Warn("Retrying %s script after transient Redis error (attempt %d of %d): %v", name, attempt, r.attempts, err)
+
+		select {
+		case <-time.After(r.delay(attempt)):
+		case <-ctx.Done():
+			return cmd
+		}
+	}
+}
+
+// delay is a random wait up to an exponentially growing cap, so clients that failed
+// together do not all retry together
+func (r scriptRetry) delay(attempt int) time.Duration {
+	ceiling := r.baseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > r.maxDelay {
+		ceiling = r.maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+// scriptNotExecuted reports whether err shows the script was never run: Redis refused it
+// or no connection could be made to send it
+func scriptNotExecuted(err error) bool {
+	msg := err.Error()
+	for _, prefix := range notExecutedPrefixes {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	if strings.Contains(msg, "connection pool timeout") {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// transientRedisError reports whether err is a failure that is expected to clear on its
+// own, as opposed to a script error or bad reply that would fail again
+func transientRedisError(err error) bool {
+	if scriptNotExecuted(err) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
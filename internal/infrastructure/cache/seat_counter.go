@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+)
+
+// Fields of a seat counter hash
+const (
+	seatCounterAvailable = "available"
+	seatCounterTotal     = "total"
+	seatCounterReserved  = "reserved"
+	seatCounterVersion   = "version"
+)
+
+// parseSeatCounter reads a seat counter hash. Counters made by giving back a seat to a
+// section that had none lack everything but available and version, which read as zero.
+func parseSeatCounter(fields map[string]string) (*interfaces.SeatCounter, error) {
+	counter := &interfaces.SeatCounter{}
+	for name, field := range map[string]*int{
+		seatCounterAvailable: &counter.Available,
+		seatCounterTotal:     &counter.Total,
+		seatCounterReserved:  &counter.Reserved,
+	} {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		*field = parsed
+	}
+	if value, ok := fields[seatCounterVersion]; ok {
+		version, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", seatCounterVersion, err)
+		}
+		counter.Version = version
+	}
+	return counter, nil
+}
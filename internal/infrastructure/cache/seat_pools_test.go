@@ -0,0 +1,202 @@
+package cache
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// seedSection sets the section's seat counter and, when pools is not nil, its seat pools
+func seedSection(t *testing.T, cache interfaces.CacheService, counter interfaces.SeatCounter, pools map[uuid.UUID]int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	sectionID := uuid.New()
+	if err := cache.SetSeatCounter(ctx, sectionID, counter, time.Hour); err != nil {
+		t.Fatalf("SetSeatCounter: %v", err)
+	}
+	if pools != nil {
+		if err := cache.SetSeatPools(ctx, sectionID, pools, time.Hour); err != nil {
+			t.Fatalf("SetSeatPools: %v", err)
+		}
+	}
+	return sectionID
+}
+
+// seatPools returns the seats left in the section's seat pools
+func seatPools(t *testing.T, cache interfaces.CacheService, sectionID uuid.UUID) map[uuid.UUID]int {
+	t.Helper()
+	pools, err := cache.GetSeatPools(context.Background(), sectionID)
+	if err != nil {
+		t.Fatalf("GetSeatPools: %v", err)
+	}
+	return pools
+}
+
+func TestReserveSeatPoolSelection(t *testing.T) {
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			emptyPool, firstPool, secondPool := uuid.New(), uuid.New(), uuid.New()
+			// 6 seats left: 1 in the first pool, 2 in the second and 3 general ones
+			sectionID := seedSection(t, cache, interfaces.SeatCounter{Available: 6, Total: 6},
+				map[uuid.UUID]int{emptyPool: 0, firstPool: 1, secondPool: 2})
+
+			seat := interfaces.SeatRequest{HasPools: true, Pools: []uuid.UUID{emptyPool, firstPool, secondPool}}
+			counter, err := cache.ReserveSeat(ctx, sectionID, seat, nil)
+			if err != nil {
+				t.Fatalf("ReserveSeat: %v", err)
+			}
+			if counter.Pool == nil || *counter.Pool != firstPool {
+				t.Fatalf("seat taken from pool %v, want the first eligible pool with seats, %s", counter.Pool, firstPool)
+			}
+			if counter.Available != 5 {
+				t.Fatalf("%d seats available, want 5", counter.Available)
+			}
+			if pools := seatPools(t, cache, sectionID); pools[firstPool] != 0 || pools[secondPool] != 2 {
+				t.Fatalf("seat pools are %v after taking from the first pool", pools)
+			}
+
+			// With its eligible pools empty, a student falls back to the general seats
+			seat = interfaces.SeatRequest{HasPools: true, Pools: []uuid.UUID{emptyPool, firstPool}}
+			for i := 0; i < 3; i++ {
+				counter, err := cache.ReserveSeat(ctx, sectionID, seat, nil)
+				if err != nil {
+					t.Fatalf("ReserveSeat of general seat %d: %v", i+1, err)
+				}
+				if counter.Pool != nil {
+					t.Fatalf("general seat %d taken from pool %s", i+1, *counter.Pool)
+				}
+			}
+
+			// Only the second pool's seats are left, which the student may not take
+			if _, err := cache.ReserveSeat(ctx, sectionID, seat, nil); !errors.Is(err, interfaces.ErrNoSeatsLeft) {
+				t.Fatalf("ReserveSeat with only pooled seats left returned %v, want %v", err, interfaces.ErrNoSeatsLeft)
+			}
+			counter, err = cache.ReserveSeat(ctx, sectionID, interfaces.SeatRequest{HasPools: true, Pools: []uuid.UUID{secondPool}}, nil)
+			if err != nil {
+				t.Fatalf("ReserveSeat from the second pool: %v", err)
+			}
+			if counter.Pool == nil || *counter.Pool != secondPool || counter.Available != 1 {
+				t.Fatalf("got pool %v with %d seats available, want pool %s with 1", counter.Pool, counter.Available, secondPool)
+			}
+		})
+	}
+}
+
+func TestReserveSeatPoolsNotLoaded(t *testing.T) {
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			sectionID := seedSection(t, cache, interfaces.SeatCounter{Available: 5, Total: 5}, nil)
+
+			seat := interfaces.SeatRequest{HasPools: true, Pools: []uuid.UUID{uuid.New()}}
+			if _, err := cache.ReserveSeat(context.Background(), sectionID, seat, nil); !errors.Is(err, interfaces.ErrSeatPoolsNotLoaded) {
+				t.Fatalf("ReserveSeat without pool counters returned %v, want %v", err, interfaces.ErrSeatPoolsNotLoaded)
+			}
+		})
+	}
+}
+
+func TestReserveSeatKeepsReservedSeats(t *testing.T) {
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			sectionID := seedSection(t, cache, interfaces.SeatCounter{Available: 3, Total: 3, Reserved: 2}, nil)
+
+			seat := interfaces.SeatRequest{Reserved: 2}
+			counter, err := cache.ReserveSeat(ctx, sectionID, seat, nil)
+			if err != nil {
+				t.Fatalf("ReserveSeat of the unreserved seat: %v", err)
+			}
+			if counter.Available != 2 {
+				t.Fatalf("%d seats available, want 2", counter.Available)
+			}
+			if _, err := cache.ReserveSeat(ctx, sectionID, seat, nil); !errors.Is(err, interfaces.ErrNoSeatsLeft) {
+				t.Fatalf("ReserveSeat of a reserved seat returned %v, want %v", err, interfaces.ErrNoSeatsLeft)
+			}
+		})
+	}
+}
+
+func TestReserveSeatsAllOrNothing(t *testing.T) {
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			open := seedSection(t, cache, interfaces.SeatCounter{Available: 2, Total: 2}, nil)
+			full := seedSection(t, cache, interfaces.SeatCounter{Available: 0, Total: 2}, nil)
+
+			_, err := cache.ReserveSeats(ctx, []interfaces.SeatReservation{{SectionID: open}, {SectionID: full}})
+			var reservationErr *interfaces.SeatReservationError
+			if !errors.As(err, &reservationErr) {
+				t.Fatalf("ReserveSeats with a full section returned %v, want a SeatReservationError", err)
+			}
+			if reservationErr.SectionID != full || !errors.Is(err, interfaces.ErrNoSeatsLeft) {
+				t.Fatalf("ReserveSeats failed on section %s with %v, want section %s with %v", reservationErr.SectionID, err, full, interfaces.ErrNoSeatsLeft)
+			}
+			counter, err := cache.GetSeatCounter(ctx, open)
+			if err != nil {
+				t.Fatalf("GetSeatCounter: %v", err)
+			}
+			if counter.Available != 2 {
+				t.Fatalf("open section has %d seats available after a failed ReserveSeats, want 2", counter.Available)
+			}
+
+			counters, err := cache.ReserveSeats(ctx, []interfaces.SeatReservation{{SectionID: open}, {SectionID: open}})
+			if err != nil {
+				t.Fatalf("ReserveSeats: %v", err)
+			}
+			if len(counters) != 2 || counters[1].Available != 0 {
+				t.Fatalf("ReserveSeats returned %d counters, want 2 leaving no seat", len(counters))
+			}
+		})
+	}
+}
+
+func TestReleaseAndRetakeSeatPool(t *testing.T) {
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			pool := uuid.New()
+			sectionID := seedSection(t, cache, interfaces.SeatCounter{Available: 2, Total: 2}, map[uuid.UUID]int{pool: 1})
+
+			counter, err := cache.ReserveSeat(ctx, sectionID, interfaces.SeatRequest{HasPools: true, Pools: []uuid.UUID{pool}}, nil)
+			if err != nil {
+				t.Fatalf("ReserveSeat: %v", err)
+			}
+
+			available, err := cache.ReleaseSeat(ctx, sectionID, counter.Pool)
+			if err != nil {
+				t.Fatalf("ReleaseSeat: %v", err)
+			}
+			if available != 2 {
+				t.Fatalf("%d seats available after a release, want 2", available)
+			}
+			if pools := seatPools(t, cache, sectionID); pools[pool] != 1 {
+				t.Fatalf("pool has %d seats after a release, want its seat back", pools[pool])
+			}
+
+			available, err = cache.RetakeSeat(ctx, sectionID, counter.Pool)
+			if err != nil {
+				t.Fatalf("RetakeSeat: %v", err)
+			}
+			if available != 1 {
+				t.Fatalf("%d seats available after a retake, want 1", available)
+			}
+			if pools := seatPools(t, cache, sectionID); pools[pool] != 0 {
+				t.Fatalf("pool has %d seats after a retake, want 0", pools[pool])
+			}
+
+			// A pool no longer in the section gives its seat back to the general seats only
+			gone := uuid.New()
+			if _, err := cache.ReleaseSeat(ctx, sectionID, &gone); err != nil {
+				t.Fatalf("ReleaseSeat to a removed pool: %v", err)
+			}
+			if pools := seatPools(t, cache, sectionID); len(pools) != 1 {
+				t.Fatalf("seat pools are %v after releasing to a removed pool, want only %s", pools, pool)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const studentEventsChannelPrefix = "student:events:"
+
+type RedisStudentNotifier struct {
+	client redis.UniversalClient
+}
+
+func NewRedisStudentNotifier(client redis.UniversalClient) interfaces.StudentNotifier {
+	return &RedisStudentNotifier{
+		client: client,
+	}
+}
+
+func (n *RedisStudentNotifier) Publish(ctx context.Context, event interfaces.StudentEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal student event: %w", err)
+	}
+
+	channel := studentEventsChannelPrefix + event.StudentID.String()
+	if err := n.client.Publish(ctx, channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish student event: %w", err)
+	}
+	return nil
+}
+
+func (n *RedisStudentNotifier) SubscribeAll(ctx context.Context) (<-chan interfaces.StudentEvent, error) {
+	pubsub := n.client.PSubscribe(ctx, studentEventsChannelPrefix+"*")
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to student events: %w", err)
+	}
+
+	events := make(chan interfaces.StudentEvent, 64)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event interfaces.StudentEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					logger.Warn("Ignoring malformed student event on %s: %v", msg.Channel, err)
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
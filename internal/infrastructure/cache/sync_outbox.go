@@ -0,0 +1,534 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// syncOutboxStream holds database sync jobs until a dispatcher has queued them
+	syncOutboxStream = "outbox:database_sync"
+	// syncOutboxGroup is the consumer group every dispatcher reads the outbox through
+	syncOutboxGroup = "dispatchers"
+)
+
+// SyncOutboxNames are the outbox stream and the consumer group the dispatchers read it through
+type SyncOutboxNames struct {
+	Stream string
+	Group  string
+}
+
+// DefaultSyncOutboxNames are the outbox names used when no queue environment is set
+func DefaultSyncOutboxNames() SyncOutboxNames {
+	return SyncOutboxNames{Stream: syncOutboxStream, Group: syncOutboxGroup}
+}
+
+// NewSyncOutboxNames prefixes the outbox names with the queue environment like the queue
+// names, so environments sharing one Redis do not dispatch each other's jobs
+func NewSyncOutboxNames(cfg *config.QueueConfig) SyncOutboxNames {
+	names := DefaultSyncOutboxNames()
+	if cfg.Environment != "" {
+		prefix := cfg.Environment + ":"
+		names.Stream = prefix + names.Stream
+		names.Group = prefix + names.Group
+	}
+	return names
+}
+
+// reserveSeatScript takes a seat from the counter in KEYS[1] and appends the jobs to the
+// outbox stream in KEYS[2]. Either both happen or, when no seat the request may take is
+// left, neither. ARGV[1] is the number of reserved seats, ARGV[2] is 1 when the section has
+// seat pools, whose counters are in the hash in KEYS[3], ARGV[3] is 1 when the seats are
+// held for the waitlist in KEYS[4] while it has anyone on it, and ARGV[4] is the number of
+// pools the student is eligible for, listed next; the jobs follow. The seat comes from the
+// first eligible pool with seats left, or else from the seats no pool holds. Returns the
+// seats left, the pool used, empty for none, and the counter's total, reserved and version
+// fields.
+var reserveSeatScript = redis.NewScript(`
+	local current = redis.call("HGET", KEYS[1], "available")
+	if current == false then
+		return redis.error_reply("Key does not exist")
+	end
+	if ARGV[3] == "1" and redis.call("ZCARD", KEYS[4]) > 0 then
+		return redis.error_reply("Seats held for waitlist")
+	end
+	current = tonumber(current)
+	local reserved = tonumber(ARGV[1])
+	local eligible = tonumber(ARGV[4])
+	local pool = ""
+	if ARGV[2] == "1" then
+		if redis.call("EXISTS", KEYS[3]) == 0 then
+			return redis.error_reply("Seat pools not loaded")
+		end
+		for i = 5, 4 + eligible do
+			if tonumber(redis.call("HGET", KEYS[3], ARGV[i]) or "0") > 0 then
+				pool = ARGV[i]
+				break
+			end
+		end
+		if pool == "" then
+			for _, left in ipairs(redis.call("HVALS", KEYS[3])) do
+				if tonumber(left) > 0 then
+					reserved = reserved + tonumber(left)
+				end
+			end
+		end
+	end
+	if current <= 0 or (pool == "" and current <= reserved) then
+		return redis.error_reply("No seats available")
+	end
+	if pool ~= "" then
+		redis.call("HINCRBY", KEYS[3], pool, -1)
+	end
+	local seats = redis.call("HINCRBY", KEYS[1], "available", -1)
+	local version = redis.call("HINCRBY", KEYS[1], "version", 1)
+	for i = 5 + eligible, #ARGV do
+		redis.call("XADD", KEYS[2], "*", "job", ARGV[i], "pool", pool)
+	end
+	local counter = redis.call("HMGET", KEYS[1], "total", "reserved")
+	return {seats, pool, tonumber(counter[1] or "0"), tonumber(counter[2] or "0"), version}
+`)
+
+func (r *RedisCache) ReserveSeat(ctx context.Context, sectionID uuid.UUID, seat interfaces.SeatRequest, jobs []interfaces.DatabaseSyncJob) (*interfaces.SeatCounter, error) {
+	keys := []string{
+		interfaces.SectionSeatsKey.Key(sectionID),
+		r.outbox.Stream,
+		interfaces.SectionSeatPoolsKey.Key(sectionID),
+		fmt.Sprintf("waitlist:section:%s", sectionID.String()),
+	}
+
+	hasPools, holdForWaitlist := 0, 0
+	if seat.HasPools {
+		hasPools = 1
+	}
+	if seat.HoldForWaitlist {
+		holdForWaitlist = 1
+	}
+	args := make([]any, 0, len(seat.Pools)+len(jobs)+4)
+	args = append(args, seat.Reserved, hasPools, holdForWaitlist, len(seat.Pools))
+	for _, pool := range seat.Pools {
+		args = append(args, pool.String())
+	}
+	for _, job := range jobs {
+		data, err := json.Marshal(job)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal outbox job: %w", err)
+		}
+		args = append(args, data)
+	}
+
+	result, err := r.retry.run(ctx, r.client, "reserve_seat", reserveSeatScript, false, keys, args...).Slice()
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "Key does not exist"):
+			return nil, fmt.Errorf("seat key not found for section %s", sectionID.String())
+		case strings.Contains(err.Error(), "Seat pools not loaded"):
+			return nil, interfaces.ErrSeatPoolsNotLoaded
+		case strings.Contains(err.Error(), "Seats held for waitlist"):
+			return nil, interfaces.ErrSeatsHeldForWaitlist
+		case strings.Contains(err.Error(), "No seats available"):
+			return nil, interfaces.ErrNoSeatsLeft
+		}
+		return nil, fmt.Errorf("failed to reserve seat: %w", err)
+	}
+	if len(result) != 5 {
+		return nil, fmt.Errorf("unexpected result type from Redis")
+	}
+	seats, ok1 := result[0].(int64)
+	total, ok2 := result[2].(int64)
+	reserved, ok3 := result[3].(int64)
+	version, ok4 := result[4].(int64)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, fmt.Errorf("unexpected result type from Redis")
+	}
+
+	r.publishSeatChange(ctx, sectionID, int(seats))
+	return &interfaces.SeatCounter{
+		Available: int(seats),
+		Total:     int(total),
+		Reserved:  int(reserved),
+		Version:   version,
+		Pool:      seatPoolResult(result[1]),
+	}, nil
+}
+
+// seatPoolResult reads the pool a reserve script took a seat from, empty for none
+func seatPoolResult(value any) *uuid.UUID {
+	text, ok := value.(string)
+	if !ok || text == "" {
+		return nil
+	}
+	pool, err := uuid.Parse(text)
+	if err != nil {
+		return nil
+	}
+	return &pool
+}
+
+// reserveSeatsScript takes a seat in each of ARGV[1] sections like reserveSeatScript, all of
+// them or none. KEYS[1] is the outbox stream, followed by the counter, pools and waitlist
+// keys of every section. For each section ARGV holds its reserved seats, pools flag, hold
+// flag, eligible pool count and job count, then its eligible pools and its jobs. Every seat
+// is checked before any is taken; a section without one fails the script with an error
+// naming its place in the list. Returns what reserveSeatScript does for each section.
+var reserveSeatsScript = redis.NewScript(`
+	local count = tonumber(ARGV[1])
+	local picked = {}
+	local arg = 2
+	for s = 1, count do
+		local seats, pools, waitlist = KEYS[3 * s - 1], KEYS[3 * s], KEYS[3 * s + 1]
+		local reserved = tonumber(ARGV[arg])
+		local eligible = tonumber(ARGV[arg + 3])
+		local jobs = tonumber(ARGV[arg + 4])
+		local first = arg + 5
+		local current = redis.call("HGET", seats, "available")
+		if current == false then
+			return redis.error_reply("Section " .. s .. ": Key does not exist")
+		end
+		if ARGV[arg + 2] == "1" and redis.call("ZCARD", waitlist) > 0 then
+			return redis.error_reply("Section " .. s .. ": Seats held for waitlist")
+		end
+		current = tonumber(current)
+		local pool = ""
+		if ARGV[arg + 1] == "1" then
+			if redis.call("EXISTS", pools) == 0 then
+				return redis.error_reply("Section " .. s .. ": Seat pools not loaded")
+			end
+			for i = first, first + eligible - 1 do
+				if tonumber(redis.call("HGET", pools, ARGV[i]) or "0") > 0 then
+					pool = ARGV[i]
+					break
+				end
+			end
+			if pool == "" then
+				for _, left in ipairs(redis.call("HVALS", pools)) do
+					if tonumber(left) > 0 then
+						reserved = reserved + tonumber(left)
+					end
+				end
+			end
+		end
+		if current <= 0 or (pool == "" and current <= reserved) then
+			return redis.error_reply("Section " .. s .. ": No seats available")
+		end
+		picked[s] = {pool, first + eligible, jobs}
+		arg = first + eligible + jobs
+	end
+
+	local results = {}
+	for s = 1, count do
+		local seats, pools = KEYS[3 * s - 1], KEYS[3 * s]
+		local pool, first, jobs = picked[s][1], picked[s][2], picked[s][3]
+		if pool ~= "" then
+			redis.call("HINCRBY", pools, pool, -1)
+		end
+		local left = redis.call("HINCRBY", seats, "available", -1)
+		local version = redis.call("HINCRBY", seats, "version", 1)
+		for i = first, first + jobs - 1 do
+			redis.call("XADD", KEYS[1], "*", "job", ARGV[i], "pool", pool)
+		end
+		local counter = redis.call("HMGET", seats, "total", "reserved")
+		results[s] = {left, pool, tonumber(counter[1] or "0"), tonumber(counter[2] or "0"), version}
+	end
+	return results
+`)
+
+func (r *RedisCache) ReserveSeats(ctx context.Context, reservations []interfaces.SeatReservation) ([]*interfaces.SeatCounter, error) {
+	keys := make([]string, 0, 1+3*len(reservations))
+	keys = append(keys, r.outbox.Stream)
+	args := []any{len(reservations)}
+	for _, reservation := range reservations {
+		keys = append(keys,
+			interfaces.SectionSeatsKey.Key(reservation.SectionID),
+			interfaces.SectionSeatPoolsKey.Key(reservation.SectionID),
+			fmt.Sprintf("waitlist:section:%s", reservation.SectionID.String()),
+		)
+
+		seat := reservation.Seat
+		hasPools, holdForWaitlist := 0, 0
+		if seat.HasPools {
+			hasPools = 1
+		}
+		if seat.HoldForWaitlist {
+			holdForWaitlist = 1
+		}
+		args = append(args, seat.Reserved, hasPools, holdForWaitlist, len(seat.Pools), len(reservation.Jobs))
+		for _, pool := range seat.Pools {
+			args = append(args, pool.String())
+		}
+		for _, job := range reservation.Jobs {
+			data, err := json.Marshal(job)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal outbox job: %w", err)
+			}
+			args = append(args, data)
+		}
+	}
+
+	result, err := r.retry.run(ctx, r.client, "reserve_seats", reserveSeatsScript, false, keys, args...).Slice()
+	if err != nil {
+		return nil, reserveSeatsError(reservations, err)
+	}
+	if len(result) != len(reservations) {
+		return nil, fmt.Errorf("unexpected result type from Redis")
+	}
+
+	counters := make([]*interfaces.SeatCounter, len(reservations))
+	for i, item := range result {
+		fields, ok := item.([]any)
+		if !ok || len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected result type from Redis")
+		}
+		seats, ok1 := fields[0].(int64)
+		total, ok2 := fields[2].(int64)
+		reserved, ok3 := fields[3].(int64)
+		version, ok4 := fields[4].(int64)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, fmt.Errorf("unexpected result type from Redis")
+		}
+		counters[i] = &interfaces.SeatCounter{
+			Available: int(seats),
+			Total:     int(total),
+			Reserved:  int(reserved),
+			Version:   version,
+			Pool:      seatPoolResult(fields[1]),
+		}
+	}
+	for i, reservation := range reservations {
+		r.publishSeatChange(ctx, reservation.SectionID, counters[i].Available)
+	}
+	return counters, nil
+}
+
+// reserveSeatsError turns an error of reserveSeatsScript that names a section into the
+// SeatReservationError of that section. Anything else, such as a transient error, is
+// returned as it is.
+func reserveSeatsError(reservations []interfaces.SeatReservation, err error) error {
+	message := err.Error()
+	var place int
+	at := strings.Index(message, "Section ")
+	if at < 0 {
+		return fmt.Errorf("failed to reserve seats: %w", err)
+	}
+	if _, scanErr := fmt.Sscanf(message[at:], "Section %d:", &place); scanErr != nil || place < 1 || place > len(reservations) {
+		return fmt.Errorf("failed to reserve seats: %w", err)
+	}
+
+	sectionID := reservations[place-1].SectionID
+	var cause error
+	switch {
+	case strings.Contains(message, "Key does not exist"):
+		cause = fmt.Errorf("seat key not found for section %s", sectionID.String())
+	case strings.Contains(message, "Seat pools not loaded"):
+		cause = interfaces.ErrSeatPoolsNotLoaded
+	case strings.Contains(message, "Seats held for waitlist"):
+		cause = interfaces.ErrSeatsHeldForWaitlist
+	case strings.Contains(message, "No seats available"):
+		cause = interfaces.ErrNoSeatsLeft
+	default:
+		return fmt.Errorf("failed to reserve seats: %w", err)
+	}
+	return &interfaces.SeatReservationError{SectionID: sectionID, Err: cause}
+}
+
+// retakeSeatScript takes a seat off the counter in KEYS[1] and, if the hash in KEYS[2] still
+// has a counter for the pool in ARGV[1], off that pool, undoing releaseSeatScript
+var retakeSeatScript = redis.NewScript(`
+	if ARGV[1] ~= "" and redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+		redis.call("HINCRBY", KEYS[2], ARGV[1], -1)
+	end
+	redis.call("HINCRBY", KEYS[1], "version", 1)
+	return redis.call("HINCRBY", KEYS[1], "available", -1)
+`)
+
+// releaseSeatScript adds a seat to the counter in KEYS[1] and, if the hash in KEYS[2] still
+// has a counter for the pool in ARGV[1], to that pool
+var releaseSeatScript = redis.NewScript(`
+	if ARGV[1] ~= "" and redis.call("HEXISTS", KEYS[2], ARGV[1]) == 1 then
+		redis.call("HINCRBY", KEYS[2], ARGV[1], 1)
+	end
+	redis.call("HINCRBY", KEYS[1], "version", 1)
+	return redis.call("HINCRBY", KEYS[1], "available", 1)
+`)
+
+func (r *RedisCache) ReleaseSeat(ctx context.Context, sectionID uuid.UUID, pool *uuid.UUID) (int, error) {
+	keys := []string{interfaces.SectionSeatsKey.Key(sectionID), interfaces.SectionSeatPoolsKey.Key(sectionID)}
+	poolID := ""
+	if pool != nil {
+		poolID = pool.String()
+	}
+
+	seats, err := r.retry.run(ctx, r.client, "release_seat", releaseSeatScript, false, keys, poolID).Int()
+	if err != nil {
+		return -1, fmt.Errorf("failed to release seat: %w", err)
+	}
+
+	r.publishSeatChange(ctx, sectionID, seats)
+	return seats, nil
+}
+
+func (r *RedisCache) RetakeSeat(ctx context.Context, sectionID uuid.UUID, pool *uuid.UUID) (int, error) {
+	keys := []string{interfaces.SectionSeatsKey.Key(sectionID), interfaces.SectionSeatPoolsKey.Key(sectionID)}
+	poolID := ""
+	if pool != nil {
+		poolID = pool.String()
+	}
+
+	seats, err := r.retry.run(ctx, r.client, "retake_seat", retakeSeatScript, false, keys, poolID).Int()
+	if err != nil {
+		return -1, fmt.Errorf("failed to retake seat: %w", err)
+	}
+
+	r.publishSeatChange(ctx, sectionID, seats)
+	return seats, nil
+}
+
+func (r *RedisCache) SetSeatPools(ctx context.Context, sectionID uuid.UUID, remaining map[uuid.UUID]int, ttl time.Duration) error {
+	key := interfaces.SectionSeatPoolsKey.Key(sectionID)
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(remaining) > 0 {
+		values := make(map[string]any, len(remaining))
+		for pool, seats := range remaining {
+			values[pool.String()] = seats
+		}
+		pipe.HSet(ctx, key, values)
+		pipe.Expire(ctx, key, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set seat pools in cache: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisCache) GetSeatPools(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error) {
+	values, err := r.client.HGetAll(ctx, interfaces.SectionSeatPoolsKey.Key(sectionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat pools from cache: %w", err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	remaining := make(map[uuid.UUID]int, len(values))
+	for field, value := range values {
+		pool, err := uuid.Parse(field)
+		if err != nil {
+			continue
+		}
+		seats, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seat pool value in cache: %w", err)
+		}
+		remaining[pool] = seats
+	}
+	return remaining, nil
+}
+
+// RedisSyncOutbox reads the outbox stream through a consumer group, so entries a dispatcher
+// claimed but never acknowledged can be claimed again by another
+type RedisSyncOutbox struct {
+	client redis.UniversalClient
+	names  SyncOutboxNames
+}
+
+// NewRedisSyncOutbox opens the outbox stream the RedisCache with the same names writes to
+func NewRedisSyncOutbox(ctx context.Context, client redis.UniversalClient, names SyncOutboxNames) (*RedisSyncOutbox, error) {
+	err := client.XGroupCreateMkStream(ctx, names.Stream, names.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create outbox consumer group: %w", err)
+	}
+	return &RedisSyncOutbox{client: client, names: names}, nil
+}
+
+func (o *RedisSyncOutbox) Read(ctx context.Context, consumer string, count int, block, minIdle time.Duration) ([]interfaces.OutboxEntry, error) {
+	stale, _, err := o.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   o.names.Stream,
+		Group:    o.names.Group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    int64(count),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim stale outbox entries: %w", err)
+	}
+	if len(stale) > 0 {
+		return o.decode(ctx, stale), nil
+	}
+
+	streams, err := o.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    o.names.Group,
+		Consumer: consumer,
+		Streams:  []string{o.names.Stream, ">"},
+		Count:    int64(count),
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	var entries []interfaces.OutboxEntry
+	for _, stream := range streams {
+		entries = append(entries, o.decode(ctx, stream.Messages)...)
+	}
+	return entries, nil
+}
+
+// decode turns stream messages into entries. A message that does not hold a job can never
+// be delivered, so it is acknowledged and dropped rather than claimed over and over.
+func (o *RedisSyncOutbox) decode(ctx context.Context, messages []redis.XMessage) []interfaces.OutboxEntry {
+	entries := make([]interfaces.OutboxEntry, 0, len(messages))
+	for _, message := range messages {
+		var job interfaces.DatabaseSyncJob
+		data, _ := message.Values["job"].(string)
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			_ = o.Ack(ctx, message.ID)
+			continue
+		}
+		// reserveSeatScript records the seat pool it picked next to the job
+		if pool, err := uuid.Parse(fmt.Sprint(message.Values["pool"])); err == nil {
+			job.SeatPoolID = &pool
+		}
+		entries = append(entries, interfaces.OutboxEntry{ID: message.ID, Job: job})
+	}
+	return entries
+}
+
+// Ack acknowledges delivered entries and removes them from the stream
+func (o *RedisSyncOutbox) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pipe := o.client.TxPipeline()
+	pipe.XAck(ctx, o.names.Stream, o.names.Group, ids...)
+	pipe.XDel(ctx, o.names.Stream, ids...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge outbox entries: %w", err)
+	}
+	return nil
+}
+
+func (o *RedisSyncOutbox) Pending(ctx context.Context) (int64, error) {
+	length, err := o.client.XLen(ctx, o.names.Stream).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get outbox length: %w", err)
+	}
+	return length, nil
+}
+
+var _ interfaces.SyncOutbox = (*RedisSyncOutbox)(nil)
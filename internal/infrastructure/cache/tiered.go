@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// localInvalidationChannel carries the keys every server must drop from its local cache
+	localInvalidationChannel = "cache:local:invalidate"
+	// localResubscribeDelay is how long the invalidation listener waits before subscribing
+	// again after losing its subscription
+	localResubscribeDelay = time.Second
+)
+
+// localInvalidation is broadcast after a write. Origin lets the sender skip its own message.
+type localInvalidation struct {
+	Origin   string   `json:"origin"`
+	Keys     []string `json:"keys,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// TieredCache puts a short-lived in-process cache in front of another CacheService for the
+// hottest reads: student details, course details and available sections. Writes and
+// invalidations go to the wrapped cache first and are then broadcast over Redis pub/sub so
+// every server drops its local copy; if a broadcast is missed, the local TTL bounds how
+// long a server can serve the old value. Everything else passes straight through.
+type TieredCache struct {
+	interfaces.CacheService
+	client redis.UniversalClient
+	local  *localLRU
+	origin string
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewTieredCache(inner interfaces.CacheService, client redis.UniversalClient, cfg *config.LocalCacheConfig) *TieredCache {
+	size := cfg.Size
+	if size <= 0 {
+		size = 10000
+	}
+	ttl := time.Duration(cfg.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = 5 * time.Second
+	}
+
+	c := &TieredCache{
+		CacheService: inner,
+		client:       client,
+		local:        newLocalLRU(size, ttl),
+		origin:       uuid.NewString(),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go c.listen()
+	return c
+}
+
+// getLocal serves key from the local cache, filling it from the wrapped cache on a miss
+func (c *TieredCache) getLocal(cache, key string, load func() (interface{}, error)) (interface{}, error) {
+	if value, ok := c.local.get(key); ok {
+		metrics.CacheLookups.WithLabelValues("local_"+cache, metrics.CacheHit).Inc()
+		return json.RawMessage(value), nil
+	}
+	metrics.CacheLookups.WithLabelValues("local_"+cache, metrics.CacheMiss).Inc()
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		c.local.set(key, raw)
+	}
+	return value, nil
+}
+
+func (c *TieredCache) GetStudentDetails(ctx context.Context, studentID uuid.UUID) (interface{}, error) {
+	return c.getLocal("student_details", interfaces.StudentDetailsCache.Key(studentID), func() (interface{}, error) {
+		return c.CacheService.GetStudentDetails(ctx, studentID)
+	})
+}
+
+func (c *TieredCache) SetStudentDetails(ctx context.Context, studentID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.CacheService.SetStudentDetails(ctx, studentID, data, ttl); err != nil {
+		return err
+	}
+	c.invalidate(ctx, localInvalidation{Keys: []string{interfaces.StudentDetailsCache.Key(studentID)}})
+	return nil
+}
+
+func (c *TieredCache) GetCourseDetails(ctx context.Context, courseID uuid.UUID) (interface{}, error) {
+	return c.getLocal("course_details", interfaces.CourseDetailsCache.Key(courseID), func() (interface{}, error) {
+		return c.CacheService.GetCourseDetails(ctx, courseID)
+	})
+}
+
+func (c *TieredCache) SetCourseDetails(ctx context.Context, courseID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.CacheService.SetCourseDetails(ctx, courseID, data, ttl); err != nil {
+		return err
+	}
+	c.invalidate(ctx, localInvalidation{Keys: []string{interfaces.CourseDetailsCache.Key(courseID)}})
+	return nil
+}
+
+func (c *TieredCache) GetAvailableSections(ctx context.Context, semesterID uuid.UUID) (interface{}, error) {
+	return c.getLocal("available_sections", interfaces.AvailableSectionsCache.Key(semesterID), func() (interface{}, error) {
+		return c.CacheService.GetAvailableSections(ctx, semesterID)
+	})
+}
+
+func (c *TieredCache) SetAvailableSections(ctx context.Context, semesterID uuid.UUID, data interface{}, ttl time.Duration) error {
+	if err := c.CacheService.SetAvailableSections(ctx, semesterID, data, ttl); err != nil {
+		return err
+	}
+	c.invalidate(ctx, localInvalidation{Keys: []string{interfaces.AvailableSectionsCache.Key(semesterID)}})
+	return nil
+}
+
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	if err := c.CacheService.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.invalidate(ctx, localInvalidation{Keys: []string{key}})
+	return nil
+}
+
+func (c *TieredCache) Clear(ctx context.Context, pattern string) error {
+	if err := c.CacheService.Clear(ctx, pattern); err != nil {
+		return err
+	}
+	c.invalidate(ctx, localInvalidation{Patterns: []string{pattern}})
+	return nil
+}
+
+func (c *TieredCache) InvalidateStudentCache(ctx context.Context, studentID uuid.UUID, semesterIDs ...uuid.UUID) error {
+	if err := c.CacheService.InvalidateStudentCache(ctx, studentID, semesterIDs...); err != nil {
+		return err
+	}
+	set := interfaces.CacheInvalidationSet(interfaces.CacheScopeStudent, studentID, semesterIDs...)
+	c.invalidate(ctx, localInvalidation{Keys: set.Keys, Patterns: set.Patterns})
+	return nil
+}
+
+func (c *TieredCache) InvalidateSectionCache(ctx context.Context, sectionID uuid.UUID) error {
+	if err := c.CacheService.InvalidateSectionCache(ctx, sectionID); err != nil {
+		return err
+	}
+	set := interfaces.CacheInvalidationSet(interfaces.CacheScopeSection, sectionID)
+	c.invalidate(ctx, localInvalidation{Keys: set.Keys, Patterns: append(set.Patterns, interfaces.AvailableSectionsCache.Pattern())})
+	return nil
+}
+
+// invalidate drops keys from the local cache and tells the other servers to do the same
+func (c *TieredCache) invalidate(ctx context.Context, message localInvalidation) {
+	c.dropLocal(message)
+
+	message.Origin = c.origin
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	if err := c.client.Publish(ctx, localInvalidationChannel, payload).Err(); err != nil {
+		logger.Warn("Failed to broadcast local cache invalidation: %v", err)
+	}
+}
+
+func (c *TieredCache) dropLocal(message localInvalidation) {
+	c.local.delete(message.Keys...)
+	for _, pattern := range message.Patterns {
+		c.local.deleteMatching(pattern)
+	}
+}
+
+// listen applies the invalidations broadcast by other servers until Close, subscribing
+// again whenever the subscription is lost
+func (c *TieredCache) listen() {
+	defer close(c.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	for {
+		c.receive(ctx)
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(localResubscribeDelay):
+		}
+	}
+}
+
+func (c *TieredCache) receive(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, localInvalidationChannel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("Failed to subscribe to local cache invalidations: %v", err)
+		}
+		return
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var message localInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil || message.Origin == c.origin {
+				continue
+			}
+			c.dropLocal(message)
+		}
+	}
+}
+
+// Close stops listening for invalidations and closes the wrapped cache
+func (c *TieredCache) Close() error {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+	return c.CacheService.Close()
+}
+
+var _ interfaces.CacheService = (*TieredCache)(nil)
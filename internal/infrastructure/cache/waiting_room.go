@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// waitingRoomSlotsKey holds the in-flight and admitted slots scored by when they lapse
+	waitingRoomSlotsKey = "waitingroom:slots"
+	// waitingRoomQueueKey holds the queued tokens scored by arrival
+	waitingRoomQueueKey = "waitingroom:queue"
+	// waitingRoomSeenKey holds the queued tokens scored by their last poll
+	waitingRoomSeenKey = "waitingroom:seen"
+)
+
+// waitingRoomPurge drops lapsed slots and the queued tokens nobody has polled for since
+// ARGV[2], freeing their places for the callers still waiting
+const waitingRoomPurge = `
+	redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+	local stale = redis.call("ZRANGEBYSCORE", KEYS[3], "-inf", ARGV[2])
+	for _, token in ipairs(stale) do
+		redis.call("ZREM", KEYS[2], token)
+		redis.call("ZREM", KEYS[3], token)
+	end
+`
+
+var enterWaitingRoomScript = redis.NewScript(waitingRoomPurge + `
+	if redis.call("ZCARD", KEYS[2]) > 0 or redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[3]) then
+		return 0
+	end
+	redis.call("ZADD", KEYS[1], ARGV[5], ARGV[4])
+	return 1
+`)
+
+var joinWaitingRoomScript = redis.NewScript(`
+	redis.call("ZADD", KEYS[1], "NX", ARGV[2], ARGV[1])
+	redis.call("ZADD", KEYS[2], ARGV[2], ARGV[1])
+	return redis.call("ZRANK", KEYS[1], ARGV[1]) + 1
+`)
+
+// pollWaitingRoomScript returns -1 for an unknown token, 0 once it is admitted and its
+// position otherwise. Queued tokens are admitted from the front of the line into free slots.
+var pollWaitingRoomScript = redis.NewScript(waitingRoomPurge + `
+	if redis.call("ZSCORE", KEYS[1], ARGV[4]) then
+		return 0
+	end
+	if not redis.call("ZSCORE", KEYS[2], ARGV[4]) then
+		return -1
+	end
+	redis.call("ZADD", KEYS[3], ARGV[6], ARGV[4])
+
+	local free = tonumber(ARGV[3]) - redis.call("ZCARD", KEYS[1])
+	if free > 0 then
+		local admitted = redis.call("ZRANGE", KEYS[2], 0, free - 1)
+		for _, token in ipairs(admitted) do
+			redis.call("ZREM", KEYS[2], token)
+			redis.call("ZREM", KEYS[3], token)
+			redis.call("ZADD", KEYS[1], ARGV[5], token)
+		end
+	end
+
+	if redis.call("ZSCORE", KEYS[1], ARGV[4]) then
+		return 0
+	end
+	return redis.call("ZRANK", KEYS[2], ARGV[4]) + 1
+`)
+
+var claimWaitingRoomScript = redis.NewScript(`
+	local lapses = redis.call("ZSCORE", KEYS[1], ARGV[1])
+	if not lapses or tonumber(lapses) <= tonumber(ARGV[2]) then
+		return 0
+	end
+	redis.call("ZADD", KEYS[1], "XX", ARGV[3], ARGV[1])
+	return 1
+`)
+
+// RedisWaitingRoom keeps the waiting room in sorted sets shared by every instance. Queued
+// callers that stop polling for staleAfter lose their place.
+type RedisWaitingRoom struct {
+	client     redis.UniversalClient
+	staleAfter time.Duration
+}
+
+func NewRedisWaitingRoom(client redis.UniversalClient, staleAfter time.Duration) interfaces.WaitingRoom {
+	return &RedisWaitingRoom{
+		client:     client,
+		staleAfter: staleAfter,
+	}
+}
+
+func (w *RedisWaitingRoom) Enter(ctx context.Context, slotID string, limit int, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	entered, err := enterWaitingRoomScript.Run(ctx, w.client, w.keys(),
+		now.UnixMilli(), now.Add(-w.staleAfter).UnixMilli(), limit, slotID, now.Add(ttl).UnixMilli()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to enter waiting room: %w", err)
+	}
+	return entered == 1, nil
+}
+
+func (w *RedisWaitingRoom) Join(ctx context.Context, token string) (*interfaces.WaitingTicket, error) {
+	position, err := joinWaitingRoomScript.Run(ctx, w.client, []string{waitingRoomQueueKey, waitingRoomSeenKey},
+		token, time.Now().UnixMilli()).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to join waiting room: %w", err)
+	}
+	return &interfaces.WaitingTicket{Token: token, Position: position}, nil
+}
+
+func (w *RedisWaitingRoom) Poll(ctx context.Context, token string, limit int, admitTTL time.Duration) (*interfaces.WaitingTicket, error) {
+	now := time.Now()
+	position, err := pollWaitingRoomScript.Run(ctx, w.client, w.keys(),
+		now.UnixMilli(), now.Add(-w.staleAfter).UnixMilli(), limit, token, now.Add(admitTTL).UnixMilli(), now.UnixMilli()).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to poll waiting room: %w", err)
+	}
+	if position < 0 {
+		return nil, nil
+	}
+	return &interfaces.WaitingTicket{Token: token, Position: position, Admitted: position == 0}, nil
+}
+
+func (w *RedisWaitingRoom) Claim(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	claimed, err := claimWaitingRoomScript.Run(ctx, w.client, []string{waitingRoomSlotsKey},
+		token, now.UnixMilli(), now.Add(ttl).UnixMilli()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim waiting room slot: %w", err)
+	}
+	return claimed == 1, nil
+}
+
+func (w *RedisWaitingRoom) Release(ctx context.Context, slotID string) error {
+	if err := w.client.ZRem(ctx, waitingRoomSlotsKey, slotID).Err(); err != nil {
+		return fmt.Errorf("failed to release waiting room slot: %w", err)
+	}
+	return nil
+}
+
+func (w *RedisWaitingRoom) keys() []string {
+	return []string{waitingRoomSlotsKey, waitingRoomQueueKey, waitingRoomSeenKey}
+}
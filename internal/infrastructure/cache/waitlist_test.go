@@ -0,0 +1,190 @@
+package cache
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// testCaches returns a Redis cache backed by miniredis and a memory cache, so each
+// test runs against both implementations
+func testCaches(t *testing.T) map[string]interfaces.CacheService {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]interfaces.CacheService{
+		"redis":  &RedisCache{client: client, retry: defaultScriptRetry(), outbox: DefaultSyncOutboxNames()},
+		"memory": NewMemoryCache(),
+	}
+}
+
+func TestAddToWaitlistConcurrentCap(t *testing.T) {
+	const (
+		students = 50
+		maxSize  = 10
+	)
+
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			sectionID := uuid.New()
+
+			var (
+				wg              sync.WaitGroup
+				mu              sync.Mutex
+				added, refused  int
+				unexpectedError error
+			)
+			for i := 0; i < students; i++ {
+				wg.Add(1)
+				go func(position int) {
+					defer wg.Done()
+					studentID := uuid.New()
+					entry := map[string]any{"student_id": studentID, "position": position}
+					err := cache.AddToWaitlist(ctx, sectionID, studentID, position, entry, maxSize)
+
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case err == nil:
+						added++
+					case errors.Is(err, interfaces.ErrWaitlistFull):
+						refused++
+					default:
+						unexpectedError = err
+					}
+				}(i + 1)
+			}
+			wg.Wait()
+
+			if unexpectedError != nil {
+				t.Fatalf("AddToWaitlist returned an unexpected error: %v", unexpectedError)
+			}
+			if added != maxSize || refused != students-maxSize {
+				t.Fatalf("got %d added and %d refused, want %d added and %d refused", added, refused, maxSize, students-maxSize)
+			}
+			size, err := cache.GetWaitlistSize(ctx, sectionID)
+			if err != nil {
+				t.Fatalf("GetWaitlistSize: %v", err)
+			}
+			if size != maxSize {
+				t.Fatalf("waitlist size is %d, want %d", size, maxSize)
+			}
+		})
+	}
+}
+
+func TestAddToWaitlistReaddWhenFull(t *testing.T) {
+	const maxSize = 3
+
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			sectionID := uuid.New()
+
+			waiting := make([]uuid.UUID, maxSize)
+			for i := range waiting {
+				waiting[i] = uuid.New()
+				if err := cache.AddToWaitlist(ctx, sectionID, waiting[i], i+1, map[string]any{"position": i + 1}, maxSize); err != nil {
+					t.Fatalf("AddToWaitlist of student %d: %v", i+1, err)
+				}
+			}
+
+			if err := cache.AddToWaitlist(ctx, sectionID, uuid.New(), maxSize+1, map[string]any{"position": maxSize + 1}, maxSize); !errors.Is(err, interfaces.ErrWaitlistFull) {
+				t.Fatalf("AddToWaitlist of a new student to a full waitlist returned %v, want %v", err, interfaces.ErrWaitlistFull)
+			}
+
+			// A student already waiting is re-added in a new place despite the cap
+			if err := cache.AddToWaitlist(ctx, sectionID, waiting[0], maxSize+1, map[string]any{"position": maxSize + 1}, maxSize); err != nil {
+				t.Fatalf("AddToWaitlist of a waiting student to a full waitlist: %v", err)
+			}
+			position, err := cache.GetWaitlistPosition(ctx, sectionID, waiting[0])
+			if err != nil {
+				t.Fatalf("GetWaitlistPosition: %v", err)
+			}
+			if position != maxSize {
+				t.Fatalf("re-added student is at position %d, want the last, %d", position, maxSize)
+			}
+			size, err := cache.GetWaitlistSize(ctx, sectionID)
+			if err != nil {
+				t.Fatalf("GetWaitlistSize: %v", err)
+			}
+			if size != maxSize {
+				t.Fatalf("waitlist size is %d, want %d", size, maxSize)
+			}
+		})
+	}
+}
+
+// entryPosition returns the position recorded in the student's entry document
+func entryPosition(t *testing.T, cache interfaces.CacheService, studentID uuid.UUID) int {
+	t.Helper()
+	entries, err := cache.GetStudentWaitlists(context.Background(), studentID)
+	if err != nil {
+		t.Fatalf("GetStudentWaitlists: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("student %s has %d waitlist entries, want 1", studentID, len(entries))
+	}
+	entry, ok := entries[0].(map[string]any)
+	if !ok {
+		t.Fatalf("waitlist entry is a %T, want a map", entries[0])
+	}
+	position, _ := entry["position"].(float64)
+	return int(position)
+}
+
+func TestLeaveAndCompactWaitlist(t *testing.T) {
+	for name, cache := range testCaches(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			sectionID := uuid.New()
+
+			students := make([]uuid.UUID, 4)
+			for i := range students {
+				students[i] = uuid.New()
+				if err := cache.AddToWaitlist(ctx, sectionID, students[i], i+1, map[string]any{"position": i + 1}, 0); err != nil {
+					t.Fatalf("AddToWaitlist of student %d: %v", i+1, err)
+				}
+			}
+
+			position, err := cache.LeaveWaitlist(ctx, sectionID, students[1])
+			if err != nil {
+				t.Fatalf("LeaveWaitlist: %v", err)
+			}
+			if position != 2 {
+				t.Fatalf("LeaveWaitlist returned position %d, want 2", position)
+			}
+			for i, want := range map[int]int{0: 1, 2: 2, 3: 3} {
+				if got := entryPosition(t, cache, students[i]); got != want {
+					t.Fatalf("entry of student %d is at position %d after a leave, want %d", i+1, got, want)
+				}
+			}
+
+			// Removing without shifting leaves a gap that compaction closes
+			if err := cache.RemoveFromWaitlist(ctx, sectionID, students[0]); err != nil {
+				t.Fatalf("RemoveFromWaitlist: %v", err)
+			}
+			moved, err := cache.CompactWaitlist(ctx, sectionID)
+			if err != nil {
+				t.Fatalf("CompactWaitlist: %v", err)
+			}
+			if len(moved) != 2 || moved[students[2]] != 1 || moved[students[3]] != 2 {
+				t.Fatalf("CompactWaitlist moved %v, want students 3 and 4 to positions 1 and 2", moved)
+			}
+			for i, want := range map[int]int{2: 1, 3: 2} {
+				if got := entryPosition(t, cache, students[i]); got != want {
+					t.Fatalf("entry of student %d is at position %d after compaction, want %d", i+1, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// hotQuery is a query run on every registration, schedule or catalog request. The sample
+// picks the busiest key to run it with, so the plan is the one real traffic gets.
+type hotQuery struct {
+	name   string
+	sample string
+	query  string
+}
+
+var hotQueries = []hotQuery{
+	{
+		name:   "student registrations",
+		sample: "SELECT student_id FROM registrations GROUP BY student_id ORDER BY count(*) DESC LIMIT 1",
+		query:  "SELECT * FROM registrations WHERE student_id = ?",
+	},
+	{
+		name:   "section roster",
+		sample: "SELECT section_id FROM registrations GROUP BY section_id ORDER BY count(*) DESC LIMIT 1",
+		query:  "SELECT student_id FROM registrations WHERE section_id = ? AND status = 'enrolled'",
+	},
+	{
+		name:   "waitlist next in line",
+		sample: "SELECT section_id FROM waitlist GROUP BY section_id ORDER BY count(*) DESC LIMIT 1",
+		query:  "SELECT * FROM waitlist WHERE section_id = ? ORDER BY position ASC LIMIT 1",
+	},
+	{
+		name:   "semester sections",
+		sample: "SELECT semester_id FROM sections GROUP BY semester_id ORDER BY count(*) DESC LIMIT 1",
+		query:  "SELECT * FROM sections WHERE semester_id = ?",
+	},
+	{
+		name:   "available sections",
+		sample: "SELECT semester_id FROM sections GROUP BY semester_id ORDER BY count(*) DESC LIMIT 1",
+		query:  "SELECT * FROM sections WHERE semester_id = ? AND is_active AND available_seats > 0",
+	},
+}
+
+// QueryPlan is what EXPLAIN ANALYZE found for one hot query. Queries without sample data
+// are skipped.
+type QueryPlan struct {
+	Name        string
+	Query       string
+	PlanningMS  float64
+	ExecutionMS float64
+	// SeqScans lists the tables read in full, usually a missing index
+	SeqScans []string
+	Indexes  []string
+	Slow     bool
+	Skipped  bool
+}
+
+type QueryAnalyzer struct {
+	db            *gorm.DB
+	slowThreshold time.Duration
+}
+
+func NewQueryAnalyzer(db *gorm.DB, slowThreshold time.Duration) *QueryAnalyzer {
+	return &QueryAnalyzer{
+		db:            db,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// Analyze runs every hot query under EXPLAIN ANALYZE. The queries only read, so running
+// them against production is safe, though they do take the time they report.
+func (qa *QueryAnalyzer) Analyze() ([]QueryPlan, error) {
+	plans := make([]QueryPlan, 0, len(hotQueries))
+	for _, hq := range hotQueries {
+		plan, err := qa.analyze(hq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze %s: %w", hq.name, err)
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, nil
+}
+
+func (qa *QueryAnalyzer) analyze(hq hotQuery) (*QueryPlan, error) {
+	plan := &QueryPlan{Name: hq.name, Query: hq.query}
+
+	var sample uuid.UUID
+	err := qa.db.Raw(hq.sample).Row().Scan(&sample)
+	if errors.Is(err, sql.ErrNoRows) {
+		plan.Skipped = true
+		return plan, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick sample: %w", err)
+	}
+
+	// The sample is inlined rather than bound so the planner sees the value
+	query := qa.db.Dialector.Explain(hq.query, sample)
+	var output string
+	if err := qa.db.Raw("EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) " + query).Row().Scan(&output); err != nil {
+		return nil, err
+	}
+
+	var explained []struct {
+		Plan          planNode `json:"Plan"`
+		PlanningTime  float64  `json:"Planning Time"`
+		ExecutionTime float64  `json:"Execution Time"`
+	}
+	if err := json.Unmarshal([]byte(output), &explained); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(explained) == 0 {
+		return nil, errors.New("empty plan")
+	}
+
+	plan.PlanningMS = explained[0].PlanningTime
+	plan.ExecutionMS = explained[0].ExecutionTime
+	explained[0].Plan.walk(func(node planNode) {
+		switch {
+		case node.NodeType == "Seq Scan":
+			plan.SeqScans = append(plan.SeqScans, node.RelationName)
+		case node.IndexName != "":
+			plan.Indexes = append(plan.Indexes, node.IndexName)
+		}
+	})
+	plan.Slow = time.Duration(plan.ExecutionMS*float64(time.Millisecond)) > qa.slowThreshold
+	return plan, nil
+}
+
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	IndexName    string     `json:"Index Name"`
+	Plans        []planNode `json:"Plans"`
+}
+
+func (n planNode) walk(visit func(planNode)) {
+	visit(n)
+	for _, child := range n.Plans {
+		child.walk(visit)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"time"
 
+	"cobra-template/internal/infrastructure/tracing"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -20,7 +22,8 @@ type Config struct {
 }
 
 func NewConnection(config Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s connect_timeout=10",
+	// Sessions run in UTC so timestamps read back are UTC whatever the server's zone is
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s connect_timeout=10 TimeZone=UTC",
 		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
 
 	log.Printf("DEBUG: Attempting to connect with DSN: %s", dsn)
@@ -38,6 +41,10 @@ func NewConnection(config Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"gorm.io/gorm"
+)
+
+// HealthChecker reports whether Postgres answers and whether every migration in
+// migrationsDir has been applied
+type HealthChecker struct {
+	db            *gorm.DB
+	migrationsDir string
+}
+
+func NewHealthChecker(db *gorm.DB, migrationsDir string) interfaces.DatabaseHealth {
+	return &HealthChecker{
+		db:            db,
+		migrationsDir: migrationsDir,
+	}
+}
+
+func (h *HealthChecker) Ping(ctx context.Context) error {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (h *HealthChecker) PendingMigrations(ctx context.Context) ([]string, error) {
+	return NewMigrationRunner(h.db.WithContext(ctx), h.migrationsDir).PendingMigrations()
+}
@@ -1,10 +1,12 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -12,16 +14,24 @@ import (
 	"gorm.io/gorm"
 )
 
+// downSuffix ends the file that reverts a migration, next to the migration it is paired
+// with: 031_course_repeat_policy.down.sql reverts 031_course_repeat_policy.sql
+const downSuffix = ".down.sql"
+
+// Migration is one migration file. DownSQL is the content of its down file, empty when the
+// migration cannot be reverted.
 type Migration struct {
 	ID          string
 	Description string
 	SQL         string
+	DownSQL     string
 	AppliedAt   *time.Time
 }
 
 type MigrationRunner struct {
 	db            *gorm.DB
 	migrationsDir string
+	dryRun        bool
 }
 
 func NewMigrationRunner(db *gorm.DB, migrationsDir string) *MigrationRunner {
@@ -31,6 +41,12 @@ func NewMigrationRunner(db *gorm.DB, migrationsDir string) *MigrationRunner {
 	}
 }
 
+// SetDryRun makes the runner print the SQL of the migrations it would apply or revert
+// instead of running it
+func (mr *MigrationRunner) SetDryRun(dryRun bool) {
+	mr.dryRun = dryRun
+}
+
 func (mr *MigrationRunner) createMigrationsTable() error {
 	sql := `
 	CREATE TABLE IF NOT EXISTS schema_migrations (
@@ -65,7 +81,8 @@ func (mr *MigrationRunner) getMigrationFiles() ([]string, error) {
 			return err
 		}
 
-		if !d.IsDir() && strings.HasSuffix(d.Name(), ".sql") {
+		// Down files are read with the migration they revert
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".sql") && !strings.HasSuffix(d.Name(), downSuffix) {
 			files = append(files, path)
 		}
 
@@ -96,13 +113,124 @@ func (mr *MigrationRunner) readMigrationFile(filePath string) (*Migration, error
 	description := strings.TrimSuffix(parts[1], ".sql")
 	description = strings.ReplaceAll(description, "_", " ")
 
+	downSQL, err := os.ReadFile(strings.TrimSuffix(filePath, ".sql") + downSuffix)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
 	return &Migration{
 		ID:          id,
 		Description: description,
 		SQL:         string(content),
+		DownSQL:     string(downSQL),
 	}, nil
 }
 
+// loadMigrations reads every migration file in order
+func (mr *MigrationRunner) loadMigrations() ([]*Migration, error) {
+	files, err := mr.getMigrationFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration files: %w", err)
+	}
+
+	migrations := make([]*Migration, 0, len(files))
+	for _, file := range files {
+		migration, err := mr.readMigrationFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", file, err)
+		}
+		migrations = append(migrations, migration)
+	}
+	return migrations, nil
+}
+
+// apply runs a migration and records it in schema_migrations in one transaction
+func (mr *MigrationRunner) apply(migration *Migration) error {
+	if mr.dryRun {
+		fmt.Printf("-- Apply migration: %s - %s\n%s\n", migration.ID, migration.Description, migration.SQL)
+		return nil
+	}
+
+	err := mr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.SQL).Error; err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", migration.ID, err)
+		}
+
+		if err := tx.Exec("INSERT INTO schema_migrations (id, description) VALUES (?, ?)",
+			migration.ID, migration.Description).Error; err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied migration: %s - %s\n", migration.ID, migration.Description)
+	return nil
+}
+
+// revert runs a migration's down file and removes it from schema_migrations in one
+// transaction, so a down file that fails leaves the migration applied
+func (mr *MigrationRunner) revert(migration *Migration) error {
+	if mr.dryRun {
+		fmt.Printf("-- Revert migration: %s - %s\n%s\n", migration.ID, migration.Description, migration.DownSQL)
+		return nil
+	}
+
+	err := mr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(migration.DownSQL).Error; err != nil {
+			return fmt.Errorf("failed to revert migration %s: %w", migration.ID, err)
+		}
+
+		if err := tx.Exec("DELETE FROM schema_migrations WHERE id = ?", migration.ID).Error; err != nil {
+			return fmt.Errorf("failed to record revert of migration %s: %w", migration.ID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reverted migration: %s - %s\n", migration.ID, migration.Description)
+	return nil
+}
+
+// rollbackFloor returns the index of the earliest migration the schema can be rolled back
+// to: the latest migration without a down file, since every one after it can be reverted.
+// It is -1 when every migration can be reverted.
+func rollbackFloor(migrations []*Migration) int {
+	floor := len(migrations) - 1
+	for floor >= 0 && strings.TrimSpace(migrations[floor].DownSQL) != "" {
+		floor--
+	}
+	return floor
+}
+
+// checkRollbackFloor refuses a rollback that would leave the schema at the migration with
+// the target index, -1 for none, when that is below the rollback floor, before any migration
+// is reverted
+func checkRollbackFloor(migrations []*Migration, target int) error {
+	floor := rollbackFloor(migrations)
+	if target >= floor {
+		return nil
+	}
+	return fmt.Errorf("cannot roll back below migration %s, which has no down file", migrations[floor].ID)
+}
+
+// revertAll reverts the migrations in the order given. Callers check the rollback floor
+// first, so every one of them has a down file.
+func (mr *MigrationRunner) revertAll(migrations []*Migration) error {
+	for _, migration := range migrations {
+		if err := mr.revert(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (mr *MigrationRunner) RunMigrations() error {
 
 	if err := mr.createMigrationsTable(); err != nil {
@@ -114,55 +242,146 @@ func (mr *MigrationRunner) RunMigrations() error {
 		return fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
-	files, err := mr.getMigrationFiles()
+	migrations, err := mr.loadMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to get migration files: %w", err)
+		return err
 	}
 
 	pendingCount := 0
-	for _, file := range files {
-		migration, err := mr.readMigrationFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %w", file, err)
-		}
-
+	for _, migration := range migrations {
 		if applied[migration.ID] {
 			continue
 		}
 
-		err = mr.db.Transaction(func(tx *gorm.DB) error {
-
-			if err := tx.Exec(migration.SQL).Error; err != nil {
-				return fmt.Errorf("failed to execute migration %s: %w", migration.ID, err)
-			}
-
-			if err := tx.Exec("INSERT INTO schema_migrations (id, description) VALUES (?, ?)",
-				migration.ID, migration.Description).Error; err != nil {
-				return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
-			}
-
-			return nil
-		})
-
-		if err != nil {
+		if err := mr.apply(migration); err != nil {
 			return err
 		}
-
-		fmt.Printf("Applied migration: %s - %s\n", migration.ID, migration.Description)
 		pendingCount++
 	}
 
-	if pendingCount == 0 {
+	switch {
+	case pendingCount == 0:
 		fmt.Println("No pending migrations to apply")
-	} else {
+	case mr.dryRun:
+		fmt.Printf("Dry run: %d migrations would be applied\n", pendingCount)
+	default:
 		fmt.Printf("Successfully applied %d migrations\n", pendingCount)
 	}
 
 	return nil
 }
 
-func (mr *MigrationRunner) GetMigrationStatus() ([]Migration, error) {
+// RollbackMigrations reverts the last steps applied migrations, latest first
+func (mr *MigrationRunner) RollbackMigrations(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	if err := mr.createMigrationsTable(); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied, err := mr.getAppliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	migrations, err := mr.loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	var revert []*Migration
+	target := len(migrations) - 1
+	for i := len(migrations) - 1; i >= 0 && len(revert) < steps; i-- {
+		if applied[migrations[i].ID] {
+			revert = append(revert, migrations[i])
+			target = i - 1
+		}
+	}
+	if len(revert) == 0 {
+		fmt.Println("No applied migrations to revert")
+		return nil
+	}
+
+	if err := checkRollbackFloor(migrations, target); err != nil {
+		return err
+	}
+	if err := mr.revertAll(revert); err != nil {
+		return err
+	}
+	if mr.dryRun {
+		fmt.Printf("Dry run: %d migrations would be reverted\n", len(revert))
+		return nil
+	}
+	fmt.Printf("Successfully reverted %d migrations\n", len(revert))
+	return nil
+}
+
+// MigrateTo brings the schema to the migration with the given ID: the applied migrations
+// after it are reverted, latest first, then the pending migrations up to and including it
+// are applied
+func (mr *MigrationRunner) MigrateTo(id string) error {
+	if err := mr.createMigrationsTable(); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied, err := mr.getAppliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	migrations, err := mr.loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	target := slices.IndexFunc(migrations, func(migration *Migration) bool {
+		return migration.ID == id
+	})
+	if target < 0 {
+		return fmt.Errorf("migration %s not found", id)
+	}
+
+	var revert, apply []*Migration
+	for i := len(migrations) - 1; i > target; i-- {
+		if applied[migrations[i].ID] {
+			revert = append(revert, migrations[i])
+		}
+	}
+	for _, migration := range migrations[:target+1] {
+		if !applied[migration.ID] {
+			apply = append(apply, migration)
+		}
+	}
+	if len(revert) == 0 && len(apply) == 0 {
+		fmt.Printf("Already at migration %s\n", id)
+		return nil
+	}
 
+	if len(revert) > 0 {
+		if err := checkRollbackFloor(migrations, target); err != nil {
+			return err
+		}
+	}
+	if err := mr.revertAll(revert); err != nil {
+		return err
+	}
+	for _, migration := range apply {
+		if err := mr.apply(migration); err != nil {
+			return err
+		}
+	}
+	if mr.dryRun {
+		fmt.Printf("Dry run: migrating to %s would revert %d and apply %d migrations\n", id, len(revert), len(apply))
+		return nil
+	}
+	fmt.Printf("Migrated to %s: reverted %d, applied %d\n", id, len(revert), len(apply))
+	return nil
+}
+
+// PendingMigrations returns the IDs of the migration files not yet applied, in order
+func (mr *MigrationRunner) PendingMigrations() ([]string, error) {
 	applied, err := mr.getAppliedMigrations()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
@@ -173,13 +392,30 @@ func (mr *MigrationRunner) GetMigrationStatus() ([]Migration, error) {
 		return nil, fmt.Errorf("failed to get migration files: %w", err)
 	}
 
-	var migrations []Migration
+	var pending []string
 	for _, file := range files {
-		migration, err := mr.readMigrationFile(file)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read migration file %s: %w", file, err)
+		id, _, _ := strings.Cut(filepath.Base(file), "_")
+		if !applied[id] {
+			pending = append(pending, id)
 		}
+	}
+	return pending, nil
+}
 
+func (mr *MigrationRunner) GetMigrationStatus() ([]Migration, error) {
+
+	applied, err := mr.getAppliedMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	loaded, err := mr.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, migration := range loaded {
 		if applied[migration.ID] {
 
 			var appliedAt time.Time
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// migrationsWithDown builds migrations 001 onwards, with a down file where down says so
+func migrationsWithDown(down ...bool) []*Migration {
+	migrations := make([]*Migration, len(down))
+	for i, hasDown := range down {
+		migrations[i] = &Migration{ID: fmt.Sprintf("%03d", i+1)}
+		if hasDown {
+			migrations[i].DownSQL = "DROP TABLE t;"
+		}
+	}
+	return migrations
+}
+
+func TestRollbackFloor(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []*Migration
+		want       int
+	}{
+		{name: "no migrations", migrations: nil, want: -1},
+		{name: "every migration has a down file", migrations: migrationsWithDown(true, true, true), want: -1},
+		{name: "no migration has a down file", migrations: migrationsWithDown(false, false, false), want: 2},
+		{name: "down files from the third on", migrations: migrationsWithDown(false, false, true, true), want: 1},
+		{name: "a gap below the latest down files", migrations: migrationsWithDown(true, false, true, true), want: 1},
+		{name: "blank down file", migrations: []*Migration{{ID: "001"}, {ID: "002", DownSQL: " \n"}}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollbackFloor(tt.migrations); got != tt.want {
+				t.Fatalf("rollbackFloor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRollbackFloor(t *testing.T) {
+	migrations := migrationsWithDown(false, false, true, true)
+
+	tests := []struct {
+		name    string
+		target  int
+		wantErr bool
+	}{
+		{name: "revert nothing", target: 3},
+		{name: "revert the latest", target: 2},
+		{name: "revert down to the floor", target: 1},
+		{name: "revert the floor", target: 0, wantErr: true},
+		{name: "revert everything", target: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRollbackFloor(migrations, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRollbackFloor(%d) error = %v, want error %t", tt.target, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "migration 002") {
+				t.Fatalf("checkRollbackFloor(%d) error %q does not name the floor, migration 002", tt.target, err)
+			}
+		})
+	}
+
+	if err := checkRollbackFloor(migrationsWithDown(true, true), -1); err != nil {
+		t.Fatalf("checkRollbackFloor of fully revertible migrations: %v", err)
+	}
+}
+
+// TestMigrationsHaveDownFiles keeps the rollback floor of the shipped migrations where it
+// is, so a new migration without a down file is noticed
+func TestMigrationsHaveDownFiles(t *testing.T) {
+	runner := NewMigrationRunner(nil, "../../../migrations")
+	migrations, err := runner.loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	floor := rollbackFloor(migrations)
+	if floor < 0 || migrations[floor].ID != "002" {
+		t.Fatalf("rollback floor is at index %d, want migration 002", floor)
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// PartitionedTables are the tables partitioned by semester. The partitions of archived
+// semesters belong to the archive tables.
+var PartitionedTables = []string{"registrations", "waitlist", "registrations_archive", "waitlist_archive"}
+
+// Partition describes one semester partition of a partitioned table. Rows is the planner's
+// estimate, which is current as of the last ANALYZE.
+type Partition struct {
+	Table     string
+	Partition string
+	Bound     string
+	Rows      int64
+}
+
+type PartitionManager struct {
+	db *gorm.DB
+}
+
+func NewPartitionManager(db *gorm.DB) *PartitionManager {
+	return &PartitionManager{db: db}
+}
+
+// EnsureSemesterPartitions creates the partitions of every semester that has none yet. New
+// semesters get theirs when they are inserted, so this only catches up semesters created
+// while the trigger was missing and moves their rows out of the default partitions.
+func (pm *PartitionManager) EnsureSemesterPartitions() error {
+	if err := pm.db.Exec("SELECT create_semester_partitions(semester_id) FROM semesters").Error; err != nil {
+		return fmt.Errorf("failed to create semester partitions: %w", err)
+	}
+	return nil
+}
+
+// ListPartitions returns the partitions of every partitioned table, default partitions
+// included
+func (pm *PartitionManager) ListPartitions() ([]Partition, error) {
+	var partitions []Partition
+	err := pm.db.Raw(`
+		SELECT parent.relname AS "table",
+			child.relname AS partition,
+			pg_get_expr(child.relpartbound, child.oid) AS bound,
+			GREATEST(child.reltuples, 0)::BIGINT AS rows
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname IN ?
+		ORDER BY parent.relname, child.relname`, PartitionedTables).
+		Scan(&partitions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+	return partitions, nil
+}
@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "registration"
+
+// Queue backend label values
+const (
+	BackendMemory       = "memory"
+	BackendRedis        = "redis"
+	BackendRedisStreams = "redis-streams"
+	BackendKafka        = "kafka"
+)
+
+// Default queue name label values. Queues label their metrics with the configured names,
+// which carry the environment prefix when one is set.
+const (
+	QueueDatabaseSync  = "database_sync"
+	QueueWaitlist      = "waitlist"
+	QueueWaitlistEntry = "waitlist_entry"
+)
+
+// Background worker label values for workers that do not consume a queue
+const (
+	WorkerRetryScheduler  = "retry_scheduler"
+	WorkerReminders       = "reminders"
+	WorkerStreamReclaimer = "stream_reclaimer"
+)
+
+// Seat counter anomaly label values
+const (
+	SeatAnomalyNegative      = "negative"
+	SeatAnomalyAboveCapacity = "above_capacity"
+)
+
+// Seat counter reconciliation result label values
+const (
+	SeatReconcileCorrected = "corrected"
+	SeatReconcileSkipped   = "skipped"
+	SeatReconcileFailed    = "failed"
+)
+
+// Seat sync version conflict result label values
+const (
+	SeatSyncConflictRetried   = "retried"
+	SeatSyncConflictExhausted = "exhausted"
+)
+
+// Waitlist promotion latency stage label values
+const (
+	PromotionOffered  = "offered"
+	PromotionEnrolled = "enrolled"
+)
+
+// Scheduled task run result label values
+const (
+	ScheduledTaskSucceeded = "success"
+	ScheduledTaskFailed    = "failure"
+	ScheduledTaskSkipped   = "skipped"
+)
+
+// Database fallback registration result label values
+const (
+	FallbackEnrolled = "enrolled"
+	FallbackFull     = "full"
+	FallbackFailed   = "failed"
+)
+
+// Cache lookup result label values
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+var (
+	QueueJobsEnqueued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_enqueued_total",
+		Help:      "Number of jobs enqueued, by queue backend and queue name.",
+	}, []string{"backend", "queue"})
+
+	QueueJobsDequeued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_dequeued_total",
+		Help:      "Number of jobs dequeued by workers, by queue backend and queue name.",
+	}, []string{"backend", "queue"})
+
+	QueueJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "job_processing_seconds",
+		Help:      "Time spent processing a dequeued job, by queue backend, queue name and result.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"backend", "queue", "result"})
+
+	QueueWorkersBusy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "workers_busy",
+		Help:      "Number of queue workers currently processing a job.",
+	}, []string{"backend"})
+
+	QueueWorkerBusyRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "worker_busy_ratio",
+		Help:      "Fraction of queue workers currently processing a job.",
+	}, []string{"backend"})
+
+	QueueDeadLetterDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "dead_letter_depth",
+		Help:      "Number of database sync jobs in the dead letter queue.",
+	}, []string{"backend"})
+
+	QueueLaneJobsDequeued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "lane_jobs_dequeued_total",
+		Help:      "Number of database sync jobs dequeued, by queue backend and priority lane.",
+	}, []string{"backend", "lane"})
+
+	QueueRedeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "redeliveries_total",
+		Help:      "Number of jobs claimed again after their consumer left them unacknowledged, by queue backend and queue name.",
+	}, []string{"backend", "queue"})
+
+	QueueJobPanics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "job_panics_total",
+		Help:      "Number of jobs whose handler panicked and that were moved to the poison queue.",
+	}, []string{"backend", "queue"})
+
+	QueueWorkerRestarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "worker_restarts_total",
+		Help:      "Number of queue workers restarted after a panic, by queue backend and worker.",
+	}, []string{"backend", "worker"})
+
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Cache lookups by cache name and result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	CacheScriptRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "script_retries_total",
+		Help:      "Lua script calls retried after a transient Redis error, by script.",
+	}, []string{"script"})
+
+	SeatCounterAnomalies = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "seats",
+		Name:      "counter_anomalies_total",
+		Help:      "Seat counter reads that were negative or above the section's capacity, by reason.",
+	}, []string{"reason"})
+
+	SeatCounterReconciliations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "seats",
+		Name:      "counter_reconciliations_total",
+		Help:      "Seat counters rebuilt from the database after an anomaly or a reconciliation run, by result (corrected, skipped, failed).",
+	}, []string{"result"})
+
+	SeatCounterDiscrepancies = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "seats",
+		Name:      "counter_discrepancies",
+		Help:      "Sections whose seat counter disagreed with their enrolled registrations at the last reconciliation run.",
+	})
+
+	WaitlistPromotionLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "waitlist",
+		Name:      "promotion_latency_seconds",
+		Help:      "Time from a seat being freed to the waitlisted student being offered it or having their enrollment recorded, by stage (offered, enrolled).",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"stage"})
+
+	SeatSyncConflicts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "seats",
+		Name:      "sync_conflicts_total",
+		Help:      "Seat count writes to the database that lost an optimistic lock race, by result (retried, exhausted).",
+	}, []string{"result"})
+
+	SeatCounterCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "seats",
+		Name:      "counter_circuit_open",
+		Help:      "1 while the seat counter circuit breaker is open and registrations take seats from the database, 0 otherwise.",
+	})
+
+	FallbackRegistrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "seats",
+		Name:      "database_fallback_total",
+		Help:      "Seat reservations made straight against the database while the seat counter was unavailable, by result (enrolled, full, failed).",
+	}, []string{"result"})
+
+	SchedulerTaskRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "task_runs_total",
+		Help:      "Runs of scheduled maintenance tasks, by task and result (success, failure, skipped when another instance ran it).",
+	}, []string{"task", "result"})
+
+	SchedulerTaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "task_duration_seconds",
+		Help:      "Time taken by one run of a scheduled maintenance task.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+	}, []string{"task"})
+)
+
+// ObserveJob records the processing latency and outcome of a single job
+func ObserveJob(backend, queue string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	QueueJobDuration.WithLabelValues(backend, queue, result).Observe(time.Since(start).Seconds())
+}
+
+// WorkerTracker keeps the busy worker gauges of one queue backend up to date
+type WorkerTracker struct {
+	backend string
+	total   int64
+	busy    atomic.Int64
+}
+
+func NewWorkerTracker(backend string, total int) *WorkerTracker {
+	QueueWorkersBusy.WithLabelValues(backend).Set(0)
+	QueueWorkerBusyRatio.WithLabelValues(backend).Set(0)
+
+	return &WorkerTracker{
+		backend: backend,
+		total:   int64(total),
+	}
+}
+
+// Begin marks a worker as busy. Callers must pair it with End.
+func (t *WorkerTracker) Begin() {
+	t.update(t.busy.Add(1))
+}
+
+func (t *WorkerTracker) End() {
+	t.update(t.busy.Add(-1))
+}
+
+func (t *WorkerTracker) update(busy int64) {
+	QueueWorkersBusy.WithLabelValues(t.backend).Set(float64(busy))
+	if t.total > 0 {
+		QueueWorkerBusyRatio.WithLabelValues(t.backend).Set(float64(busy) / float64(t.total))
+	}
+}
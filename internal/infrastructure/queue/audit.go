@@ -0,0 +1,136 @@
+package queue
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func (rq *RedisQueue) SetAuditRepository(repo interfaces.AuditRepository) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	rq.auditRepo = repo
+}
+
+func (rq *RedisQueue) EnqueueAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	if err := rq.client.LPush(ctx, rq.keys.audit, data).Err(); err != nil {
+		return fmt.Errorf("failed to enqueue audit event: %w", err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(rq.backend, rq.names.Audit).Inc()
+	return nil
+}
+
+// auditWorker writes queued audit events to the audit repository. One worker is enough:
+// writes are single inserts and the log only has to keep up, not be instant.
+func (rq *RedisQueue) auditWorker(workerID int) {
+	logger.Info("Redis audit worker started")
+
+	for {
+		select {
+		case <-rq.ctx.Done():
+			logger.Info("Redis audit worker stopped")
+			return
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+			result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout, rq.keys.audit).Result()
+			cancel()
+
+			if err != nil {
+				if err != redis.Nil && err != context.DeadlineExceeded {
+					logger.Error("Redis audit worker failed to dequeue audit event: %v", err)
+				}
+				time.Sleep(WorkerSleepDuration)
+				continue
+			}
+			if len(result) != 2 {
+				continue
+			}
+			metrics.QueueJobsDequeued.WithLabelValues(rq.backend, rq.names.Audit).Inc()
+
+			var event domain.AuditEvent
+			if err := json.Unmarshal([]byte(result[1]), &event); err != nil {
+				logger.Error("Skipping malformed audit event: %v", err)
+				continue
+			}
+			writeAuditEvent(rq.ctx, rq.auditRepo, rq.backend, rq.names.Audit, &event, rq.maxRetries)
+		}
+	}
+}
+
+func (q *Queue) SetAuditRepository(repo interfaces.AuditRepository) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.auditRepo = repo
+}
+
+// EnqueueAuditEvent buffers the event in process. Events still buffered when the process
+// stops are lost, which is why deployments that need a complete log use a Redis queue.
+func (q *Queue) EnqueueAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	select {
+	case q.auditQueue <- event:
+		metrics.QueueJobsEnqueued.WithLabelValues(q.backend, q.names.Audit).Inc()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return fmt.Errorf("audit queue is full")
+	}
+}
+
+func (q *Queue) auditWorker(workerID int) {
+	logger.Info("Audit worker started")
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			logger.Info("Audit worker stopped")
+			return
+		case event := <-q.auditQueue:
+			metrics.QueueJobsDequeued.WithLabelValues(q.backend, q.names.Audit).Inc()
+			writeAuditEvent(q.ctx, q.auditRepo, q.backend, q.names.Audit, &event, q.maxRetries)
+		}
+	}
+}
+
+// writeAuditEvent stores an event, retrying with backoff while the database is unavailable.
+// An event that still cannot be stored is logged in full so it can be recovered by hand.
+func writeAuditEvent(ctx context.Context, repo interfaces.AuditRepository, backend, queue string, event *domain.AuditEvent, maxRetries int) {
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		writeCtx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+		err := runJob(func() error { return repo.Create(writeCtx, event) })
+		cancel()
+		metrics.ObserveJob(backend, queue, start, err)
+		if err == nil {
+			return
+		}
+
+		if attempt > maxRetries {
+			data, _ := json.Marshal(event)
+			logger.Error("Dropping audit event %s after %d attempts: %v; event: %s", event.EventID, attempt, err, data)
+			return
+		}
+		logger.Warn("Failed to write audit event %s (attempt %d): %v", event.EventID, attempt, err)
+
+		select {
+		case <-ctx.Done():
+			data, _ := json.Marshal(event)
+			logger.Error("Audit worker stopped before audit event %s was written; event: %s", event.EventID, data)
+			return
+		case <-time.After(retryBackoff(attempt)):
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package queue
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// drainPollInterval is how often a drain checks whether the in-memory buffers are empty
+const drainPollInterval = 100 * time.Millisecond
+
+// requeueFunc puts a job that was taken but not finished back on its queue
+type requeueFunc func(ctx context.Context) error
+
+type inFlightJob struct {
+	queue   string
+	job     any
+	requeue requeueFunc
+}
+
+// drainState tracks the jobs in progress so that a drain can wait for them and put back the
+// ones it stopped waiting for. A job put back may still finish in the old process, so it
+// can run twice; database sync jobs carry dedup keys for this.
+type drainState struct {
+	// redelivers is set for backends that deliver a job again when it is not acknowledged,
+	// so an unfinished job without a requeue func is not lost
+	redelivers bool
+
+	mu       sync.Mutex
+	status   interfaces.DrainStatus
+	nextID   uint64
+	inFlight map[uint64]inFlightJob
+}
+
+// track records a job as in progress until the returned func is called. requeue may be nil
+// when the backend redelivers the job by itself.
+func (d *drainState) track(queue string, job any, requeue requeueFunc) (done func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.inFlight == nil {
+		d.inFlight = make(map[uint64]inFlightJob)
+	}
+	d.nextID++
+	id := d.nextID
+	d.inFlight[id] = inFlightJob{queue: queue, job: job, requeue: requeue}
+
+	return func() {
+		d.mu.Lock()
+		delete(d.inFlight, id)
+		d.mu.Unlock()
+	}
+}
+
+// begin marks the drain as started. It reports false if a drain already started.
+func (d *drainState) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.status.Draining || d.status.FinishedAt != nil {
+		return false
+	}
+	now := time.Now()
+	d.status.Draining = true
+	d.status.StartedAt = &now
+	return true
+}
+
+// finish ends the drain. When it timed out, the jobs still in progress are put back.
+func (d *drainState) finish(timedOut bool) interfaces.DrainStatus {
+	d.mu.Lock()
+	unfinished := make([]inFlightJob, 0, len(d.inFlight))
+	for _, job := range d.inFlight {
+		unfinished = append(unfinished, job)
+	}
+	d.mu.Unlock()
+
+	var requeued, redelivered int
+	if timedOut {
+		for _, job := range unfinished {
+			switch {
+			case job.requeue != nil:
+				ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+				err := job.requeue(ctx)
+				cancel()
+				if err != nil {
+					d.abandon(job.queue, job.job, err.Error())
+					continue
+				}
+				requeued++
+			case d.redelivers:
+				redelivered++
+			default:
+				d.abandon(job.queue, job.job, "the queue does not outlive the process")
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	d.status.Draining = false
+	d.status.FinishedAt = &now
+	d.status.TimedOut = timedOut
+	d.status.Requeued += requeued
+	d.status.Redelivered += redelivered
+
+	if timedOut {
+		logger.Warn("Queue drain timed out with %d jobs in progress: %d requeued, %d left for redelivery, %d abandoned",
+			len(unfinished), d.status.Requeued, d.status.Redelivered, d.status.Abandoned)
+	} else {
+		logger.Info("Queue drained in %v", now.Sub(*d.status.StartedAt).Round(time.Millisecond))
+	}
+	return d.snapshotLocked()
+}
+
+// abandon logs a job that cannot be kept in full, so it can be recovered by hand
+func (d *drainState) abandon(queue string, job any, reason string) {
+	data, _ := json.Marshal(job)
+	logger.Error("Abandoning %s job on shutdown (%s); job: %s", queue, reason, data)
+
+	d.mu.Lock()
+	d.status.Abandoned++
+	d.mu.Unlock()
+}
+
+func (d *drainState) snapshot() interfaces.DrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snapshotLocked()
+}
+
+func (d *drainState) snapshotLocked() interfaces.DrainStatus {
+	status := d.status
+	status.InFlight = len(d.inFlight)
+	return status
+}
+
+// waitTimeout waits for wg for up to timeout and reports whether it finished
+func waitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,468 @@
+package queue
+
+import (
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/internal/infrastructure/tracing"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchTimeout bounds how long a write waits for more messages to batch with; writes
+// are synchronous, so it adds directly to enqueue latency
+const kafkaBatchTimeout = 10 * time.Millisecond
+
+// errQueueStopped is returned when the queue stopped while a job was waiting for a retry
+var errQueueStopped = errors.New("queue stopped")
+
+// KafkaQueue publishes jobs to Kafka topics keyed by section ID, so all jobs of a section go
+// to one partition and are processed in order. Each worker reads through the consumer group
+// with its own reader and commits a job's offset only once the job is handled; a failing job
+// is retried in place, holding back the jobs behind it on its partition, until its retries
+// run out. Priority lanes do not apply, since they would reorder a section's jobs.
+//
+// Kafka has no delayed delivery, so reminders are kept in process as with the in-memory
+// queue, as are dead-lettered and poison jobs.
+type KafkaQueue struct {
+	*Queue
+	writer  *kafka.Writer
+	brokers []string
+	groupID string
+	topics  kafkaTopics
+
+	readersMu sync.Mutex
+	readers   map[string]*kafka.Reader // shared readers of the Dequeue methods
+}
+
+type kafkaTopics struct {
+	databaseSync  string
+	waitlist      string
+	waitlistEntry string
+}
+
+func NewKafkaQueue(cfg *config.KafkaConfig, names interfaces.QueueNames, workers, maxRetries int) interfaces.QueueService {
+	kq := &KafkaQueue{
+		Queue: newInMemoryQueue(names, Lanes{}, 0, workers, maxRetries, metrics.BackendKafka),
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(cfg.Brokers...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			BatchTimeout:           kafkaBatchTimeout,
+			AllowAutoTopicCreation: true,
+		},
+		brokers: cfg.Brokers,
+		groupID: cfg.GroupID,
+		topics: kafkaTopics{
+			databaseSync:  kafkaTopic(names.DatabaseSync),
+			waitlist:      kafkaTopic(names.Waitlist),
+			waitlistEntry: kafkaTopic(names.WaitlistEntry),
+		},
+		readers: make(map[string]*kafka.Reader),
+	}
+	// Offsets are committed once a job finishes, so the group delivers unfinished jobs again
+	kq.drain.redelivers = true
+	return kq
+}
+
+// kafkaTopic turns a queue name into a valid topic name. The environment prefix is joined
+// with a colon, which topic names may not contain.
+func kafkaTopic(name string) string {
+	return strings.ReplaceAll(name, ":", ".")
+}
+
+func (kq *KafkaQueue) newReader(topic string) *kafka.Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     kq.brokers,
+		GroupID:     kq.groupID,
+		Topic:       topic,
+		MaxWait:     DefaultDequeueTimeout,
+		StartOffset: kafka.FirstOffset,
+	})
+}
+
+func (kq *KafkaQueue) StartWorkers() {
+	kq.mu.Lock()
+	defer kq.mu.Unlock()
+
+	if kq.started {
+		return
+	}
+
+	if kq.registrationService == nil {
+		logger.Warn("Registration service not set, workers cannot process jobs")
+		return
+	}
+
+	logger.Info("Starting %d Kafka queue workers in consumer group %s", kq.workers, kq.groupID)
+
+	for i := 0; i < kq.workers; i++ {
+		kq.startWorker(kq.names.DatabaseSync, i, func(workerID int) {
+			kq.consume(kq.topics.databaseSync, workerID, kq.handleDatabaseSync)
+		})
+	}
+
+	for i := 0; i < kq.workers; i++ {
+		kq.startWorker(kq.names.Waitlist, i, func(workerID int) {
+			kq.consume(kq.topics.waitlist, workerID, kq.handleWaitlistProcessing)
+		})
+	}
+
+	for i := 0; i < kq.workers; i++ {
+		kq.startWorker(kq.names.WaitlistEntry, i, func(workerID int) {
+			kq.consume(kq.topics.waitlistEntry, workerID, kq.handleWaitlistEntry)
+		})
+	}
+
+	if kq.reminderService != nil {
+		kq.startWorker(metrics.WorkerReminders, 0, func(int) { kq.reminderPlanWorker() })
+	}
+
+	// Audit events stay in process; see EnqueueAuditEvent
+	if kq.auditRepo != nil {
+		kq.startWorker(kq.names.Audit, 0, kq.auditWorker)
+	}
+
+	kq.started = true
+	logger.Info("Kafka queue workers started successfully")
+}
+
+// StopWorkers stops the workers, which leave the consumer group as they go, and closes the
+// shared readers. The writer stays open for jobs enqueued while the server drains.
+func (kq *KafkaQueue) StopWorkers() {
+	kq.Queue.StopWorkers()
+	kq.closeReaders()
+}
+
+// Drain stops the consumers and waits up to timeout for the jobs in progress. Jobs still
+// running when the time is up are not committed, so the consumer group delivers them again.
+func (kq *KafkaQueue) Drain(timeout time.Duration) interfaces.DrainStatus {
+	kq.mu.Lock()
+	if !kq.started || !kq.drain.begin() {
+		kq.mu.Unlock()
+		return kq.drain.snapshot()
+	}
+
+	logger.Info("Draining Kafka queue workers for up to %v...", timeout)
+	kq.cancel()
+	finished := waitTimeout(&kq.wg, timeout)
+	kq.started = false
+	kq.abandonBuffered()
+	kq.mu.Unlock()
+
+	status := kq.drain.finish(!finished)
+	kq.closeReaders()
+	return status
+}
+
+func (kq *KafkaQueue) closeReaders() {
+	kq.readersMu.Lock()
+	defer kq.readersMu.Unlock()
+	for topic, reader := range kq.readers {
+		if err := reader.Close(); err != nil {
+			logger.Warn("Failed to close Kafka reader for %s: %v", topic, err)
+		}
+		delete(kq.readers, topic)
+	}
+}
+
+func (kq *KafkaQueue) publish(ctx context.Context, topic string, sectionID uuid.UUID, job any) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return kq.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(sectionID.String()),
+		Value: data,
+	})
+}
+
+func (kq *KafkaQueue) EnqueueDatabaseSync(ctx context.Context, job interfaces.DatabaseSyncJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, kq.backend, kq.names.DatabaseSync)
+	defer func() { tracing.End(span, err) }()
+
+	if job.JobID == "" {
+		job.JobID = newJobID()
+	}
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
+	if err := kq.publish(ctx, kq.topics.databaseSync, job.SectionID, job); err != nil {
+		return fmt.Errorf("failed to enqueue database sync job: %w", err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(kq.backend, kq.names.DatabaseSync).Inc()
+	logger.Debug("Enqueued database sync job: %s for student %s, section %s",
+		job.JobType, job.StudentID, job.SectionID)
+	return nil
+}
+
+func (kq *KafkaQueue) EnqueueWaitlistProcessing(ctx context.Context, job interfaces.WaitlistPromotionJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, kq.backend, kq.names.Waitlist)
+	defer func() { tracing.End(span, err) }()
+
+	if err := kq.publish(ctx, kq.topics.waitlist, job.SectionID, job); err != nil {
+		return fmt.Errorf("failed to enqueue waitlist processing for section %s: %w", job.SectionID, err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(kq.backend, kq.names.Waitlist).Inc()
+	logger.Debug("Enqueued waitlist processing for section: %s", job.SectionID)
+	return nil
+}
+
+func (kq *KafkaQueue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.WaitlistJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, kq.backend, kq.names.WaitlistEntry)
+	defer func() { tracing.End(span, err) }()
+
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
+	if err := kq.publish(ctx, kq.topics.waitlistEntry, job.SectionID, job); err != nil {
+		return fmt.Errorf("failed to enqueue waitlist entry job: %w", err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(kq.backend, kq.names.WaitlistEntry).Inc()
+	logger.Debug("Enqueued waitlist entry job for student %s, section %s, position %d",
+		job.StudentID, job.SectionID, job.Position)
+	return nil
+}
+
+// The Dequeue methods read through one reader per topic shared by all callers and commit
+// the job as they return it, so a caller outside the workers gets it at most once.
+
+func (kq *KafkaQueue) DequeueDatabaseSync(ctx context.Context) (*interfaces.DatabaseSyncJob, error) {
+	value, err := kq.dequeue(ctx, kq.topics.databaseSync)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var job interfaces.DatabaseSyncJob
+	if err := json.Unmarshal(value, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal database sync job: %w", err)
+	}
+	return &job, nil
+}
+
+func (kq *KafkaQueue) DequeueWaitlistProcessing(ctx context.Context) (*interfaces.WaitlistPromotionJob, error) {
+	value, err := kq.dequeue(ctx, kq.topics.waitlist)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var job interfaces.WaitlistPromotionJob
+	if err := json.Unmarshal(value, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist promotion job: %w", err)
+	}
+	return &job, nil
+}
+
+func (kq *KafkaQueue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.WaitlistJob, error) {
+	value, err := kq.dequeue(ctx, kq.topics.waitlistEntry)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	var job interfaces.WaitlistJob
+	if err := json.Unmarshal(value, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist entry job: %w", err)
+	}
+	return &job, nil
+}
+
+// dequeue returns the next message on topic, or nil when none arrived before ctx ended
+func (kq *KafkaQueue) dequeue(ctx context.Context, topic string) ([]byte, error) {
+	kq.readersMu.Lock()
+	reader, ok := kq.readers[topic]
+	if !ok {
+		reader = kq.newReader(topic)
+		kq.readers[topic] = reader
+	}
+	kq.readersMu.Unlock()
+
+	msg, err := reader.FetchMessage(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read from %s: %w", topic, err)
+	}
+	if err := reader.CommitMessages(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to commit message on %s: %w", topic, err)
+	}
+	return msg.Value, nil
+}
+
+// consume runs a worker's reader until the queue stops. handle reports false when the queue
+// stopped before the job was handled, which leaves its offset uncommitted for the consumer
+// that takes over the partition.
+func (kq *KafkaQueue) consume(topic string, workerID int, handle func(workerID int, value []byte) bool) {
+	logger.Info("Kafka worker %d for %s started", workerID, topic)
+
+	reader := kq.newReader(topic)
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Warn("Failed to close Kafka reader for %s: %v", topic, err)
+		}
+		logger.Info("Kafka worker %d for %s stopped", workerID, topic)
+	}()
+
+	for {
+		msg, err := reader.FetchMessage(kq.ctx)
+		if err != nil {
+			if kq.ctx.Err() != nil {
+				return
+			}
+			logger.Error("Kafka worker %d error: failed to read from %s: %v", workerID, topic, err)
+			time.Sleep(WorkerSleepDuration)
+			continue
+		}
+
+		if !handle(workerID, msg.Value) {
+			return
+		}
+
+		if err := reader.CommitMessages(context.Background(), msg); err != nil {
+			logger.Error("Kafka worker %d failed to commit offset %d on %s: %v", workerID, msg.Offset, topic, err)
+		}
+	}
+}
+
+// runWithRetries runs process until it succeeds, panics or has failed maxRetries+1 times,
+// backing off between attempts. It returns the last error, or errQueueStopped when the
+// queue stopped during a backoff.
+func (kq *KafkaQueue) runWithRetries(ctx context.Context, queue, jobID string, process func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := kq.runAttempt(ctx, queue, process)
+
+		var perr *panicError
+		if err == nil || errors.As(err, &perr) || attempt > kq.maxRetries {
+			return err
+		}
+
+		delay := retryBackoff(attempt)
+		logger.WarnContext(ctx, "%s job %s failed, retry %d/%d in %v: %v", queue, jobID, attempt, kq.maxRetries, delay, err)
+		select {
+		case <-kq.ctx.Done():
+			return errQueueStopped
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (kq *KafkaQueue) runAttempt(parent context.Context, queue string, process func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(parent, DefaultJobTimeout)
+	defer cancel()
+
+	kq.workerTracker.Begin()
+	defer kq.workerTracker.End()
+
+	start := time.Now()
+	err := runJob(func() error { return process(ctx) })
+	metrics.ObserveJob(kq.backend, queue, start, err)
+	return err
+}
+
+func (kq *KafkaQueue) handleDatabaseSync(workerID int, value []byte) bool {
+	var job interfaces.DatabaseSyncJob
+	if err := json.Unmarshal(value, &job); err != nil {
+		logger.Error("Dropping malformed database sync job: %v", err)
+		return true
+	}
+	metrics.QueueJobsDequeued.WithLabelValues(kq.backend, kq.names.DatabaseSync).Inc()
+	ctx := logger.WithRequestID(context.Background(), job.RequestID)
+	logger.InfoContext(ctx, "Kafka worker %d processing database sync job: %s for student %s, section %s",
+		workerID, job.JobType, job.StudentID, job.SectionID)
+
+	defer kq.drain.track(kq.names.DatabaseSync, job, nil)()
+	err := kq.runWithRetries(ctx, kq.names.DatabaseSync, job.JobID, func(ctx context.Context) error {
+		return kq.registrationService.ProcessDatabaseSyncJob(ctx, job)
+	})
+
+	var perr *panicError
+	switch {
+	case errors.Is(err, errQueueStopped):
+		return false
+	case errors.As(err, &perr):
+		kq.poisonJob(kq.names.DatabaseSync, job, perr)
+	case err != nil:
+		job.Attempts = kq.maxRetries + 1
+		job.LastError = err.Error()
+		kq.deadLetter(&job)
+	default:
+		logger.InfoContext(ctx, "Kafka worker %d successfully processed database sync job", workerID)
+	}
+	return true
+}
+
+func (kq *KafkaQueue) handleWaitlistProcessing(workerID int, value []byte) bool {
+	var job interfaces.WaitlistPromotionJob
+	if err := json.Unmarshal(value, &job); err != nil {
+		logger.Error("Dropping malformed waitlist promotion job: %v", err)
+		return true
+	}
+	metrics.QueueJobsDequeued.WithLabelValues(kq.backend, kq.names.Waitlist).Inc()
+	logger.Info("Kafka worker %d processing waitlist for section %s", workerID, job.SectionID)
+
+	defer kq.drain.track(kq.names.Waitlist, job, nil)()
+	err := kq.runWithRetries(context.Background(), kq.names.Waitlist, job.SeatEventID, func(ctx context.Context) error {
+		return kq.registrationService.ProcessWaitlist(ctx, job)
+	})
+	return kq.settle(context.Background(), workerID, kq.names.Waitlist, job, err)
+}
+
+func (kq *KafkaQueue) handleWaitlistEntry(workerID int, value []byte) bool {
+	var job interfaces.WaitlistJob
+	if err := json.Unmarshal(value, &job); err != nil {
+		logger.Error("Dropping malformed waitlist entry job: %v", err)
+		return true
+	}
+	metrics.QueueJobsDequeued.WithLabelValues(kq.backend, kq.names.WaitlistEntry).Inc()
+	ctx := logger.WithRequestID(context.Background(), job.RequestID)
+	logger.InfoContext(ctx, "Kafka worker %d processing waitlist entry for student %s, section %s, position %d",
+		workerID, job.StudentID, job.SectionID, job.Position)
+
+	defer kq.drain.track(kq.names.WaitlistEntry, job, nil)()
+	err := kq.runWithRetries(ctx, kq.names.WaitlistEntry, job.StudentID.String(), func(ctx context.Context) error {
+		return kq.registrationService.ProcessWaitlistJob(ctx, job)
+	})
+	return kq.settle(ctx, workerID, kq.names.WaitlistEntry, job, err)
+}
+
+// settle finishes a waitlist job. Waitlist jobs have no dead letter queue; one whose
+// retries ran out is dropped.
+func (kq *KafkaQueue) settle(ctx context.Context, workerID int, queue string, job any, err error) bool {
+	var perr *panicError
+	switch {
+	case errors.Is(err, errQueueStopped):
+		return false
+	case errors.As(err, &perr):
+		kq.poisonJob(queue, job, perr)
+	case err != nil:
+		logger.ErrorContext(ctx, "Kafka worker %d giving up on %s job after %d attempts: %v", workerID, queue, kq.maxRetries+1, err)
+	default:
+		logger.InfoContext(ctx, "Kafka worker %d successfully processed %s job", workerID, queue)
+	}
+	return true
+}
+
+// ReplayDeadDatabaseSyncJobs publishes dead jobs again with a fresh retry budget
+func (kq *KafkaQueue) ReplayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string) (int, error) {
+	return kq.replayDeadDatabaseSyncJobs(ctx, jobIDs, kq.EnqueueDatabaseSync)
+}
+
+var _ interfaces.QueueService = (*KafkaQueue)(nil)
+var _ interfaces.DeadLetterQueue = (*KafkaQueue)(nil)
+var _ interfaces.PoisonQueue = (*KafkaQueue)(nil)
+var _ interfaces.DrainableQueue = (*KafkaQueue)(nil)
@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"sync/atomic"
+)
+
+// Priority lane label values
+const (
+	LaneCritical = "critical"
+	LaneNormal   = "normal"
+)
+
+// criticalJobTypes are the database sync jobs that keep Postgres in step with the seat
+// counters. Every other job type, including slower ones added later, uses the normal lane.
+var criticalJobTypes = map[interfaces.JobType]bool{
+	interfaces.JobTypeCreateRegistration:  true,
+	interfaces.JobTypePromoteRegistration: true,
+	interfaces.JobTypeUpdateSeats:         true,
+	interfaces.JobTypeDropRegistration:    true,
+}
+
+// Lanes are the weights of the database sync priority lanes. A zero Lanes keeps a single
+// lane.
+type Lanes struct {
+	CriticalWeight int
+	NormalWeight   int
+}
+
+// NewLanes resolves the configured lane weights, or a single lane when lanes are disabled
+func NewLanes(cfg *config.QueueConfig) Lanes {
+	if !cfg.PriorityLanes.Enabled {
+		return Lanes{}
+	}
+	lanes := Lanes{
+		CriticalWeight: cfg.PriorityLanes.CriticalWeight,
+		NormalWeight:   cfg.PriorityLanes.NormalWeight,
+	}
+	if lanes.CriticalWeight < 1 {
+		lanes.CriticalWeight = 1
+	}
+	if lanes.NormalWeight < 1 {
+		lanes.NormalWeight = 1
+	}
+	return lanes
+}
+
+func (l Lanes) enabled() bool {
+	return l.CriticalWeight > 0 && l.NormalWeight > 0
+}
+
+// laneScheduler routes jobs to lanes and decides which lane each dequeue tries first. Of
+// every CriticalWeight+NormalWeight dequeues, CriticalWeight try the critical lane first and
+// the rest the normal lane, so a flood in either lane cannot starve the other.
+type laneScheduler struct {
+	lanes Lanes
+	turn  atomic.Uint64
+}
+
+func newLaneScheduler(lanes Lanes) *laneScheduler {
+	return &laneScheduler{lanes: lanes}
+}
+
+// laneOf is the lane a job is enqueued on. With lanes disabled every job shares the normal
+// lane, which is the queue used before lanes existed.
+func (s *laneScheduler) laneOf(job interfaces.DatabaseSyncJob) string {
+	if s.lanes.enabled() && criticalJobTypes[job.JobType] {
+		return LaneCritical
+	}
+	return LaneNormal
+}
+
+// order is the order in which the next dequeue tries the lanes. Both lanes are always
+// tried, so jobs left in the critical lane after lanes are disabled still drain.
+func (s *laneScheduler) order() [2]string {
+	if !s.lanes.enabled() {
+		return [2]string{LaneNormal, LaneCritical}
+	}
+	period := uint64(s.lanes.CriticalWeight + s.lanes.NormalWeight)
+	if s.turn.Add(1)%period < uint64(s.lanes.CriticalWeight) {
+		return [2]string{LaneCritical, LaneNormal}
+	}
+	return [2]string{LaneNormal, LaneCritical}
+}
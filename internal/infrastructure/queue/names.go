@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+)
+
+const queueKeyPrefix = "queue:"
+
+// DefaultNames are the queue names used when none are configured
+func DefaultNames() interfaces.QueueNames {
+	return interfaces.QueueNames{
+		DatabaseSync:  metrics.QueueDatabaseSync,
+		Waitlist:      metrics.QueueWaitlist,
+		WaitlistEntry: metrics.QueueWaitlistEntry,
+		Poison:        "poison",
+		Reminders:     "reminders",
+		Audit:         "audit",
+	}
+}
+
+// NewNames resolves the configured queue names, prefixed with the environment when one is
+// set. Names left empty fall back to the defaults.
+func NewNames(cfg *config.QueueConfig) interfaces.QueueNames {
+	names := DefaultNames()
+	override(&names.DatabaseSync, cfg.Names.DatabaseSync)
+	override(&names.Waitlist, cfg.Names.Waitlist)
+	override(&names.WaitlistEntry, cfg.Names.WaitlistEntry)
+	override(&names.Poison, cfg.Names.Poison)
+	override(&names.Reminders, cfg.Names.Reminders)
+	override(&names.Audit, cfg.Names.Audit)
+
+	if cfg.Environment != "" {
+		prefix := cfg.Environment + ":"
+		names.DatabaseSync = prefix + names.DatabaseSync
+		names.Waitlist = prefix + names.Waitlist
+		names.WaitlistEntry = prefix + names.WaitlistEntry
+		names.Poison = prefix + names.Poison
+		names.Reminders = prefix + names.Reminders
+		names.Audit = prefix + names.Audit
+	}
+
+	return names
+}
+
+func override(name *string, configured string) {
+	if configured != "" {
+		*name = configured
+	}
+}
+
+// redisQueueKeys are the Redis keys derived from the queue names
+type redisQueueKeys struct {
+	databaseSync              string
+	databaseSyncRetry         string // ZSET scored by due time in unix ms
+	databaseSyncCritical      string // critical priority lane
+	databaseSyncCriticalRetry string
+	databaseSyncDead          string
+	poison                    string
+	waitlist                  string
+	waitlistEntry             string
+	reminders                 string // ZSET of reminder IDs scored by due time in unix ms
+	reminderJobs              string // HASH of reminder ID to job
+	audit                     string
+}
+
+func newRedisQueueKeys(names interfaces.QueueNames) redisQueueKeys {
+	return redisQueueKeys{
+		databaseSync:              queueKeyPrefix + names.DatabaseSync,
+		databaseSyncRetry:         queueKeyPrefix + names.DatabaseSync + ":retry",
+		databaseSyncCritical:      queueKeyPrefix + names.DatabaseSync + ":" + LaneCritical,
+		databaseSyncCriticalRetry: queueKeyPrefix + names.DatabaseSync + ":" + LaneCritical + ":retry",
+		databaseSyncDead:          queueKeyPrefix + names.DatabaseSync + ":dead",
+		poison:                    queueKeyPrefix + names.Poison,
+		waitlist:                  queueKeyPrefix + names.Waitlist,
+		waitlistEntry:             queueKeyPrefix + names.WaitlistEntry,
+		reminders:                 queueKeyPrefix + names.Reminders,
+		reminderJobs:              queueKeyPrefix + names.Reminders + ":jobs",
+		audit:                     queueKeyPrefix + names.Audit,
+	}
+}
+
+// databaseSyncLane is the list holding a lane's database sync jobs. The normal lane keeps
+// the key of the single queue used before lanes existed.
+func (k redisQueueKeys) databaseSyncLane(lane string) string {
+	if lane == LaneCritical {
+		return k.databaseSyncCritical
+	}
+	return k.databaseSync
+}
+
+func (k redisQueueKeys) databaseSyncRetryLane(lane string) string {
+	if lane == LaneCritical {
+		return k.databaseSyncCriticalRetry
+	}
+	return k.databaseSyncRetry
+}
@@ -1,48 +1,86 @@
 package queue
 
 import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/internal/infrastructure/tracing"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
 	serviceInterfaces "cobra-template/internal/interfaces/service"
 	"cobra-template/pkg/logger"
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 type Queue struct {
-	databaseSyncQueue  chan interfaces.DatabaseSyncJob
-	waitlistQueue      chan uuid.UUID
-	waitlistEntryQueue chan interfaces.WaitlistJob
-
-	workers int
-	ctx     context.Context
-	cancel  context.CancelFunc
-	wg      sync.WaitGroup
-	started bool
-	mu      sync.RWMutex
+	names   interfaces.QueueNames
+	lanes   *laneScheduler
+	backend string
+
+	databaseSyncQueue    chan interfaces.DatabaseSyncJob
+	databaseSyncCritical chan interfaces.DatabaseSyncJob
+	waitlistQueue        chan interfaces.WaitlistPromotionJob
+	waitlistEntryQueue   chan interfaces.WaitlistJob
+	auditQueue           chan domain.AuditEvent
+
+	workers    int
+	maxRetries int
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	started    bool
+	mu         sync.RWMutex
+
+	deadJobs   []interfaces.DatabaseSyncJob
+	poisonJobs []interfaces.PoisonJob
+	deadMu     sync.Mutex
+
+	reminders   map[string]*time.Timer
+	remindersMu sync.Mutex
 
 	registrationService serviceInterfaces.RegistrationService
+	reminderService     serviceInterfaces.ReminderService
+	auditRepo           interfaces.AuditRepository
+	workerTracker       *metrics.WorkerTracker
+	liveness            workerLiveness
+	drain               drainState
+}
+
+func NewInMemoryQueue(names interfaces.QueueNames, lanes Lanes, bufferSize, workers, maxRetries int) interfaces.QueueService {
+	return newInMemoryQueue(names, lanes, bufferSize, workers, maxRetries, metrics.BackendMemory)
 }
 
-func NewInMemoryQueue(bufferSize, workers int) interfaces.QueueService {
+// newInMemoryQueue creates an in-memory queue that labels its metrics with backend
+func newInMemoryQueue(names interfaces.QueueNames, lanes Lanes, bufferSize, workers, maxRetries int, backend string) *Queue {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	queue := &Queue{
-		databaseSyncQueue:  make(chan interfaces.DatabaseSyncJob, bufferSize),
-		waitlistQueue:      make(chan uuid.UUID, bufferSize),
-		waitlistEntryQueue: make(chan interfaces.WaitlistJob, bufferSize),
-		workers:            workers,
-		ctx:                ctx,
-		cancel:             cancel,
-		started:            false,
+		names:                names,
+		lanes:                newLaneScheduler(lanes),
+		backend:              backend,
+		databaseSyncQueue:    make(chan interfaces.DatabaseSyncJob, bufferSize),
+		databaseSyncCritical: make(chan interfaces.DatabaseSyncJob, bufferSize),
+		waitlistQueue:        make(chan interfaces.WaitlistPromotionJob, bufferSize),
+		waitlistEntryQueue:   make(chan interfaces.WaitlistJob, bufferSize),
+		auditQueue:           make(chan domain.AuditEvent, bufferSize),
+		reminders:            make(map[string]*time.Timer),
+		workers:              workers,
+		maxRetries:           maxRetries,
+		workerTracker:        metrics.NewWorkerTracker(backend, workers*3),
+		ctx:                  ctx,
+		cancel:               cancel,
+		started:              false,
 	}
 
 	return queue
 }
 
+func (q *Queue) Names() interfaces.QueueNames {
+	return q.names
+}
+
 func (q *Queue) SetRegistrationService(service interface{}) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -70,24 +108,37 @@ func (q *Queue) StartWorkers() {
 	logger.Info("Starting %d queue workers", q.workers)
 
 	for i := 0; i < q.workers; i++ {
-		q.wg.Add(1)
-		go q.databaseSyncWorker(i)
+		q.startWorker(q.names.DatabaseSync, i, q.databaseSyncWorker)
 	}
 
 	for i := 0; i < q.workers; i++ {
-		q.wg.Add(1)
-		go q.waitlistProcessingWorker(i)
+		q.startWorker(q.names.Waitlist, i, q.waitlistProcessingWorker)
 	}
 
 	for i := 0; i < q.workers; i++ {
-		q.wg.Add(1)
-		go q.waitlistEntryWorker(i)
+		q.startWorker(q.names.WaitlistEntry, i, q.waitlistEntryWorker)
+	}
+
+	if q.reminderService != nil {
+		q.startWorker(metrics.WorkerReminders, 0, func(int) { q.reminderPlanWorker() })
+	}
+
+	if q.auditRepo != nil {
+		q.startWorker(q.names.Audit, 0, q.auditWorker)
 	}
 
 	q.started = true
 	logger.Info("Queue workers started successfully")
 }
 
+func (q *Queue) startWorker(name string, workerID int, worker func(workerID int)) {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		superviseWorker(q.ctx, &q.liveness, q.backend, name, workerID, worker)
+	}()
+}
+
 func (q *Queue) StopWorkers() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -103,9 +154,79 @@ func (q *Queue) StopWorkers() {
 	logger.Info("Queue workers stopped")
 }
 
-func (q *Queue) EnqueueDatabaseSync(ctx context.Context, job interfaces.DatabaseSyncJob) error {
+// Drain stops the workers without losing buffered jobs where it can. The buffers do not
+// survive the process, so unlike the other backends the workers keep taking jobs until the
+// buffers are empty; what is left when the time is up is logged in full.
+func (q *Queue) Drain(timeout time.Duration) interfaces.DrainStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.started || !q.drain.begin() {
+		return q.drain.snapshot()
+	}
+
+	logger.Info("Draining queue workers for up to %v...", timeout)
+	deadline := time.Now().Add(timeout)
+	for q.buffered() > 0 && time.Now().Before(deadline) {
+		time.Sleep(drainPollInterval)
+	}
+
+	q.cancel()
+	finished := waitTimeout(&q.wg, time.Until(deadline))
+	q.started = false
+	q.abandonBuffered()
+	return q.drain.finish(!finished)
+}
+
+func (q *Queue) DrainStatus() interfaces.DrainStatus {
+	return q.drain.snapshot()
+}
+
+func (q *Queue) WorkerStatus() interfaces.WorkerStatus {
+	return q.liveness.status()
+}
+
+// buffered counts the jobs waiting in the buffers
+func (q *Queue) buffered() int {
+	return len(q.databaseSyncQueue) + len(q.databaseSyncCritical) + len(q.waitlistQueue) +
+		len(q.waitlistEntryQueue) + len(q.auditQueue)
+}
+
+// abandonBuffered empties the buffers once the workers have stopped, logging each job
+func (q *Queue) abandonBuffered() {
+	const reason = "still buffered when the workers stopped"
+	for {
+		select {
+		case job := <-q.databaseSyncCritical:
+			q.drain.abandon(q.names.DatabaseSync, job, reason)
+		case job := <-q.databaseSyncQueue:
+			q.drain.abandon(q.names.DatabaseSync, job, reason)
+		case job := <-q.waitlistQueue:
+			q.drain.abandon(q.names.Waitlist, job, reason)
+		case job := <-q.waitlistEntryQueue:
+			q.drain.abandon(q.names.WaitlistEntry, job, reason)
+		case event := <-q.auditQueue:
+			q.drain.abandon(q.names.Audit, event, reason)
+		default:
+			return
+		}
+	}
+}
+
+func (q *Queue) EnqueueDatabaseSync(ctx context.Context, job interfaces.DatabaseSyncJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, q.backend, q.names.DatabaseSync)
+	defer func() { tracing.End(span, err) }()
+
+	if job.JobID == "" {
+		job.JobID = newJobID()
+	}
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
 	select {
-	case q.databaseSyncQueue <- job:
+	case q.databaseSyncLane(q.lanes.laneOf(job)) <- job:
+		metrics.QueueJobsEnqueued.WithLabelValues(q.backend, q.names.DatabaseSync).Inc()
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -114,18 +235,47 @@ func (q *Queue) EnqueueDatabaseSync(ctx context.Context, job interfaces.Database
 	}
 }
 
+// DequeueDatabaseSync takes a job from the lane the lane scheduler picks first, falling back
+// to the other lane, and otherwise waits for whichever lane gets a job first
 func (q *Queue) DequeueDatabaseSync(ctx context.Context) (*interfaces.DatabaseSyncJob, error) {
+	for _, lane := range q.lanes.order() {
+		select {
+		case job := <-q.databaseSyncLane(lane):
+			return q.dequeuedDatabaseSync(lane, job), nil
+		default:
+		}
+	}
+
 	select {
+	case job := <-q.databaseSyncCritical:
+		return q.dequeuedDatabaseSync(LaneCritical, job), nil
 	case job := <-q.databaseSyncQueue:
-		return &job, nil
+		return q.dequeuedDatabaseSync(LaneNormal, job), nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
 }
 
-func (q *Queue) EnqueueWaitlistProcessing(ctx context.Context, sectionID uuid.UUID) error {
+func (q *Queue) databaseSyncLane(lane string) chan interfaces.DatabaseSyncJob {
+	if lane == LaneCritical {
+		return q.databaseSyncCritical
+	}
+	return q.databaseSyncQueue
+}
+
+func (q *Queue) dequeuedDatabaseSync(lane string, job interfaces.DatabaseSyncJob) *interfaces.DatabaseSyncJob {
+	metrics.QueueJobsDequeued.WithLabelValues(q.backend, q.names.DatabaseSync).Inc()
+	metrics.QueueLaneJobsDequeued.WithLabelValues(q.backend, lane).Inc()
+	return &job
+}
+
+func (q *Queue) EnqueueWaitlistProcessing(ctx context.Context, job interfaces.WaitlistPromotionJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, q.backend, q.names.Waitlist)
+	defer func() { tracing.End(span, err) }()
+
 	select {
-	case q.waitlistQueue <- sectionID:
+	case q.waitlistQueue <- job:
+		metrics.QueueJobsEnqueued.WithLabelValues(q.backend, q.names.Waitlist).Inc()
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -133,18 +283,27 @@ func (q *Queue) EnqueueWaitlistProcessing(ctx context.Context, sectionID uuid.UU
 		return fmt.Errorf("waitlist queue is full")
 	}
 }
-func (q *Queue) DequeueWaitlistProcessing(ctx context.Context) (uuid.UUID, error) {
+func (q *Queue) DequeueWaitlistProcessing(ctx context.Context) (*interfaces.WaitlistPromotionJob, error) {
 	select {
-	case id := <-q.waitlistQueue:
-		return id, nil
+	case job := <-q.waitlistQueue:
+		metrics.QueueJobsDequeued.WithLabelValues(q.backend, q.names.Waitlist).Inc()
+		return &job, nil
 	case <-ctx.Done():
-		return uuid.UUID{}, ctx.Err()
+		return nil, ctx.Err()
 	}
 }
 
-func (q *Queue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.WaitlistJob) error {
+func (q *Queue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.WaitlistJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, q.backend, q.names.WaitlistEntry)
+	defer func() { tracing.End(span, err) }()
+
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
 	select {
 	case q.waitlistEntryQueue <- job:
+		metrics.QueueJobsEnqueued.WithLabelValues(q.backend, q.names.WaitlistEntry).Inc()
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -156,6 +315,7 @@ func (q *Queue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.Waitlis
 func (q *Queue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.WaitlistJob, error) {
 	select {
 	case job := <-q.waitlistEntryQueue:
+		metrics.QueueJobsDequeued.WithLabelValues(q.backend, q.names.WaitlistEntry).Inc()
 		return &job, nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -163,8 +323,6 @@ func (q *Queue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.WaitlistJ
 }
 
 func (q *Queue) databaseSyncWorker(workerID int) {
-	defer q.wg.Done()
-
 	logger.Info("Database sync worker %d started", workerID)
 
 	for {
@@ -194,8 +352,6 @@ func (q *Queue) databaseSyncWorker(workerID int) {
 }
 
 func (q *Queue) waitlistProcessingWorker(workerID int) {
-	defer q.wg.Done()
-
 	logger.Info("Waitlist processing worker %d started", workerID)
 
 	for {
@@ -206,7 +362,7 @@ func (q *Queue) waitlistProcessingWorker(workerID int) {
 		default:
 
 			ctx, cancel := context.WithTimeout(q.ctx, 5*time.Second)
-			sectionID, err := q.DequeueWaitlistProcessing(ctx)
+			job, err := q.DequeueWaitlistProcessing(ctx)
 			cancel()
 
 			if err != nil {
@@ -217,14 +373,12 @@ func (q *Queue) waitlistProcessingWorker(workerID int) {
 				continue
 			}
 
-			q.processWaitlistProcessing(workerID, sectionID)
+			q.processWaitlistProcessing(workerID, job)
 		}
 	}
 }
 
 func (q *Queue) waitlistEntryWorker(workerID int) {
-	defer q.wg.Done()
-
 	logger.Info("Waitlist entry worker %d started", workerID)
 
 	for {
@@ -254,26 +408,50 @@ func (q *Queue) waitlistEntryWorker(workerID int) {
 }
 
 func (q *Queue) processDatabaseSyncJob(workerID int, job *interfaces.DatabaseSyncJob) {
-	logger.Info("Worker %d processing database sync job: %s for student %s, section %s",
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), job.RequestID), 30*time.Second)
+	defer cancel()
+
+	logger.InfoContext(ctx, "Worker %d processing database sync job: %s for student %s, section %s",
 		workerID, job.JobType, job.StudentID, job.SectionID)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	q.workerTracker.Begin()
+	defer q.workerTracker.End()
+	defer q.drain.track(q.names.DatabaseSync, *job, nil)()
+
+	start := time.Now()
+	err := runJob(func() error { return q.registrationService.ProcessDatabaseSyncJob(ctx, *job) })
+	metrics.ObserveJob(q.backend, q.names.DatabaseSync, start, err)
 
-	if err := q.registrationService.ProcessDatabaseSyncJob(ctx, *job); err != nil {
-		logger.Error("Worker %d failed to process database sync job: %v", workerID, err)
+	var perr *panicError
+	if errors.As(err, &perr) {
+		q.poisonJob(q.names.DatabaseSync, job, perr)
+	} else if err != nil {
+		logger.ErrorContext(ctx, "Worker %d failed to process database sync job: %v", workerID, err)
+		q.handleFailedDatabaseSyncJob(job, err)
 	} else {
-		logger.Info("Worker %d successfully processed database sync job", workerID)
+		logger.InfoContext(ctx, "Worker %d successfully processed database sync job", workerID)
 	}
 }
 
-func (q *Queue) processWaitlistProcessing(workerID int, sectionID uuid.UUID) {
+func (q *Queue) processWaitlistProcessing(workerID int, job *interfaces.WaitlistPromotionJob) {
+	sectionID := job.SectionID
 	logger.Info("Worker %d processing waitlist for section %s", workerID, sectionID)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := q.registrationService.ProcessWaitlist(ctx, sectionID); err != nil {
+	q.workerTracker.Begin()
+	defer q.workerTracker.End()
+	defer q.drain.track(q.names.Waitlist, *job, nil)()
+
+	start := time.Now()
+	err := runJob(func() error { return q.registrationService.ProcessWaitlist(ctx, *job) })
+	metrics.ObserveJob(q.backend, q.names.Waitlist, start, err)
+
+	var perr *panicError
+	if errors.As(err, &perr) {
+		q.poisonJob(q.names.Waitlist, job, perr)
+	} else if err != nil {
 		logger.Error("Worker %d failed to process waitlist for section %s: %v", workerID, sectionID, err)
 
 	} else {
@@ -282,18 +460,160 @@ func (q *Queue) processWaitlistProcessing(workerID int, sectionID uuid.UUID) {
 }
 
 func (q *Queue) processWaitlistEntryJob(workerID int, job *interfaces.WaitlistJob) {
-	logger.Info("Worker %d processing waitlist entry for student %s, section %s, position %d",
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), job.RequestID), 30*time.Second)
+	defer cancel()
+
+	logger.InfoContext(ctx, "Worker %d processing waitlist entry for student %s, section %s, position %d",
 		workerID, job.StudentID, job.SectionID, job.Position)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	q.workerTracker.Begin()
+	defer q.workerTracker.End()
+	defer q.drain.track(q.names.WaitlistEntry, *job, nil)()
+
+	start := time.Now()
+	err := runJob(func() error { return q.registrationService.ProcessWaitlistJob(ctx, *job) })
+	metrics.ObserveJob(q.backend, q.names.WaitlistEntry, start, err)
 
-	if err := q.registrationService.ProcessWaitlistJob(ctx, *job); err != nil {
-		logger.Error("Worker %d failed to process waitlist entry: %v", workerID, err)
+	var perr *panicError
+	if errors.As(err, &perr) {
+		q.poisonJob(q.names.WaitlistEntry, job, perr)
+	} else if err != nil {
+		logger.ErrorContext(ctx, "Worker %d failed to process waitlist entry: %v", workerID, err)
 
 	} else {
-		logger.Info("Worker %d successfully processed waitlist entry", workerID)
+		logger.InfoContext(ctx, "Worker %d successfully processed waitlist entry", workerID)
+	}
+}
+
+func (q *Queue) handleFailedDatabaseSyncJob(job *interfaces.DatabaseSyncJob, jobErr error) {
+	job.Attempts++
+	job.LastError = jobErr.Error()
+
+	if job.Attempts > q.maxRetries {
+		q.deadLetter(job)
+		return
+	}
+
+	delay := retryBackoff(job.Attempts)
+	retryJob := *job
+	time.AfterFunc(delay, func() {
+		if err := q.EnqueueDatabaseSync(q.ctx, retryJob); err != nil {
+			logger.Error("Failed to re-enqueue database sync job %s: %v", retryJob.JobID, err)
+		}
+	})
+
+	logger.Warn("Database sync job %s scheduled for retry %d/%d in %v", job.JobID, job.Attempts, q.maxRetries, delay)
+}
+
+// deadLetter parks a job whose retries are exhausted
+func (q *Queue) deadLetter(job *interfaces.DatabaseSyncJob) {
+	failedAt := time.Now()
+	job.FailedAt = &failedAt
+
+	q.deadMu.Lock()
+	q.deadJobs = append([]interfaces.DatabaseSyncJob{*job}, q.deadJobs...)
+	metrics.QueueDeadLetterDepth.WithLabelValues(q.backend).Set(float64(len(q.deadJobs)))
+	q.deadMu.Unlock()
+
+	logger.Error("Database sync job %s moved to dead letter queue after %d attempts: %s",
+		job.JobID, job.Attempts, job.LastError)
+}
+
+// poisonJob sets aside a job whose handler panicked instead of retrying it
+func (q *Queue) poisonJob(queue string, job any, perr *panicError) {
+	metrics.QueueJobPanics.WithLabelValues(q.backend, queue).Inc()
+	logger.Error("Job on %s queue panicked, moving it to the poison queue: %v\n%s", queue, perr.value, perr.stack)
+
+	poison, err := newPoisonJob(queue, job, perr)
+	if err != nil {
+		logger.Error("Failed to isolate poison job: %v", err)
+		return
+	}
+
+	q.deadMu.Lock()
+	q.poisonJobs = append([]interfaces.PoisonJob{poison}, q.poisonJobs...)
+	q.deadMu.Unlock()
+}
+
+func (q *Queue) ListPoisonJobs(ctx context.Context, offset, limit int) ([]interfaces.PoisonJob, int64, error) {
+	q.deadMu.Lock()
+	defer q.deadMu.Unlock()
+
+	total := int64(len(q.poisonJobs))
+	if offset >= len(q.poisonJobs) {
+		return []interfaces.PoisonJob{}, total, nil
+	}
+
+	end := offset + limit
+	if end > len(q.poisonJobs) {
+		end = len(q.poisonJobs)
+	}
+
+	jobs := make([]interfaces.PoisonJob, end-offset)
+	copy(jobs, q.poisonJobs[offset:end])
+	return jobs, total, nil
+}
+
+func (q *Queue) ListDeadDatabaseSyncJobs(ctx context.Context, offset, limit int) ([]interfaces.DatabaseSyncJob, int64, error) {
+	q.deadMu.Lock()
+	defer q.deadMu.Unlock()
+
+	total := int64(len(q.deadJobs))
+	if offset >= len(q.deadJobs) {
+		return []interfaces.DatabaseSyncJob{}, total, nil
 	}
+
+	end := offset + limit
+	if end > len(q.deadJobs) {
+		end = len(q.deadJobs)
+	}
+
+	jobs := make([]interfaces.DatabaseSyncJob, end-offset)
+	copy(jobs, q.deadJobs[offset:end])
+	return jobs, total, nil
+}
+
+func (q *Queue) ReplayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string) (int, error) {
+	return q.replayDeadDatabaseSyncJobs(ctx, jobIDs, q.EnqueueDatabaseSync)
+}
+
+// replayDeadDatabaseSyncJobs hands the given dead jobs, or all of them, to enqueue
+func (q *Queue) replayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string, enqueue func(context.Context, interfaces.DatabaseSyncJob) error) (int, error) {
+	wanted := make(map[string]bool, len(jobIDs))
+	for _, id := range jobIDs {
+		wanted[id] = true
+	}
+
+	q.deadMu.Lock()
+	var replay []interfaces.DatabaseSyncJob
+	remaining := make([]interfaces.DatabaseSyncJob, 0, len(q.deadJobs))
+	for _, job := range q.deadJobs {
+		if len(wanted) == 0 || wanted[job.JobID] {
+			replay = append(replay, job)
+		} else {
+			remaining = append(remaining, job)
+		}
+	}
+	q.deadJobs = remaining
+	metrics.QueueDeadLetterDepth.WithLabelValues(q.backend).Set(float64(len(q.deadJobs)))
+	q.deadMu.Unlock()
+
+	replayed := 0
+	for _, job := range replay {
+		job.Attempts = 0
+		job.LastError = ""
+		job.FailedAt = nil
+		if err := enqueue(ctx, job); err != nil {
+			return replayed, fmt.Errorf("failed to replay job %s: %w", job.JobID, err)
+		}
+		replayed++
+	}
+
+	logger.Info("Replayed %d dead database sync jobs", replayed)
+	return replayed, nil
 }
 
 var _ interfaces.QueueService = (*Queue)(nil)
+var _ interfaces.DeadLetterQueue = (*Queue)(nil)
+var _ interfaces.PoisonQueue = (*Queue)(nil)
+var _ interfaces.DrainableQueue = (*Queue)(nil)
@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+)
+
+const WorkerRestartDelay = 1 * time.Second
+
+// panicError carries a recovered panic and the stack of the goroutine that raised it
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// runJob calls fn and turns a panic inside it into a *panicError
+func runJob(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
+
+func newPoisonJob(queue string, job any, perr *panicError) (interfaces.PoisonJob, error) {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return interfaces.PoisonJob{}, fmt.Errorf("failed to marshal poison job: %w", err)
+	}
+
+	return interfaces.PoisonJob{
+		Queue:    queue,
+		Payload:  payload,
+		Panic:    fmt.Sprint(perr.value),
+		Stack:    string(perr.stack),
+		FailedAt: time.Now(),
+	}, nil
+}
+
+// workerLiveness counts the workers a queue started, those still running and how often
+// one was restarted, for the health checks
+type workerLiveness struct {
+	started  atomic.Int64
+	running  atomic.Int64
+	restarts atomic.Int64
+}
+
+// status reads the counters without the queue's lock, which a drain holds throughout
+func (l *workerLiveness) status() interfaces.WorkerStatus {
+	started := l.started.Load()
+	return interfaces.WorkerStatus{
+		Started:  started > 0,
+		Expected: int(started),
+		Running:  int(l.running.Load()),
+		Restarts: l.restarts.Load(),
+	}
+}
+
+// superviseWorker runs worker until it returns on its own, restarting it after a panic so a
+// single bad job or transient bug cannot permanently remove a worker from the pool.
+func superviseWorker(ctx context.Context, live *workerLiveness, backend, name string, workerID int, worker func(workerID int)) {
+	live.started.Add(1)
+	live.running.Add(1)
+	defer live.running.Add(-1)
+
+	for {
+		if !runWorker(name, workerID, worker) {
+			return
+		}
+
+		metrics.QueueWorkerRestarts.WithLabelValues(backend, name).Inc()
+		live.restarts.Add(1)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(WorkerRestartDelay):
+		}
+
+		logger.Warn("Restarting %s worker %d after panic", name, workerID)
+	}
+}
+
+func runWorker(name string, workerID int, worker func(workerID int)) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			logger.Error("%s worker %d panicked: %v\n%s", name, workerID, r, debug.Stack())
+		}
+	}()
+
+	worker(workerID)
+	return false
+}
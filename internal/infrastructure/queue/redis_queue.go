@@ -2,11 +2,14 @@ package queue
 
 import (
 	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/internal/infrastructure/tracing"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
 	serviceInterfaces "cobra-template/internal/interfaces/service"
 	"cobra-template/pkg/logger"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -16,29 +19,53 @@ import (
 )
 
 const (
-	DatabaseSyncQueueKey  = "queue:database_sync"
-	WaitlistQueueKey      = "queue:waitlist"
-	WaitlistEntryQueueKey = "queue:waitlist_entry"
 	DefaultDequeueTimeout = 2 * time.Second // Reasonable timeout for polling
 	DefaultJobTimeout     = 30 * time.Second
 	WorkerSleepDuration   = 50 * time.Millisecond // Sleep when no work available
 )
 
-type RedisQueue struct {
-	client redis.UniversalClient
+// promoteDueRetriesScript moves retry jobs whose backoff has elapsed back onto the main queue
+var promoteDueRetriesScript = redis.NewScript(`
+	local jobs = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, tonumber(ARGV[2]))
+	for _, job in ipairs(jobs) do
+		redis.call("ZREM", KEYS[1], job)
+		redis.call("LPUSH", KEYS[2], job)
+	end
+	return #jobs
+`)
 
-	workers int
-	ctx     context.Context
-	cancel  context.CancelFunc
-	wg      sync.WaitGroup
-	started bool
-	mu      sync.RWMutex
+type RedisQueue struct {
+	client  redis.UniversalClient
+	names   interfaces.QueueNames
+	keys    redisQueueKeys
+	lanes   *laneScheduler
+	backend string
+
+	workers    int
+	maxRetries int
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	started    bool
+	mu         sync.RWMutex
 
 	registrationService serviceInterfaces.RegistrationService
+	reminderService     serviceInterfaces.ReminderService
+	auditRepo           interfaces.AuditRepository
+	workerTracker       *metrics.WorkerTracker
+	liveness            workerLiveness
+	drain               drainState
+}
+
+// NewRedisQueue creates a new Redis-based queue service. Database sync jobs that fail are
+// retried with exponential backoff up to maxRetries times before being dead-lettered, and
+// are split into priority lanes when lanes has weights.
+func NewRedisQueue(cfg *config.CacheConfig, names interfaces.QueueNames, lanes Lanes, workers, maxRetries int) interfaces.QueueService {
+	return newRedisQueue(cfg, names, lanes, workers, maxRetries, metrics.BackendRedis)
 }
 
-// NewRedisQueue creates a new Redis-based queue service
-func NewRedisQueue(cfg *config.CacheConfig, workers int) interfaces.QueueService {
+// newRedisQueue creates a Redis queue that labels its metrics with backend
+func newRedisQueue(cfg *config.CacheConfig, names interfaces.QueueNames, lanes Lanes, workers, maxRetries int, backend string) *RedisQueue {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	rdb := redis.NewFailoverClient(&redis.FailoverOptions{
@@ -52,18 +79,30 @@ func NewRedisQueue(cfg *config.CacheConfig, workers int) interfaces.QueueService
 		PoolTimeout:      time.Duration(cfg.PoolTimeout) * time.Second,
 		IdleTimeout:      time.Duration(cfg.IdleTimeout) * time.Second,
 	})
+	rdb.AddHook(tracing.NewRedisHook())
 
 	queue := &RedisQueue{
-		client:  rdb,
-		workers: workers,
-		ctx:     ctx,
-		cancel:  cancel,
-		started: false,
+		client:        rdb,
+		names:         names,
+		keys:          newRedisQueueKeys(names),
+		lanes:         newLaneScheduler(lanes),
+		backend:       backend,
+		workers:       workers,
+		maxRetries:    maxRetries,
+		workerTracker: metrics.NewWorkerTracker(backend, workers*3),
+		drain:         drainState{redelivers: true},
+		ctx:           ctx,
+		cancel:        cancel,
+		started:       false,
 	}
 
 	return queue
 }
 
+func (rq *RedisQueue) Names() interfaces.QueueNames {
+	return rq.names
+}
+
 func (rq *RedisQueue) SetRegistrationService(service interface{}) {
 	rq.mu.Lock()
 	defer rq.mu.Unlock()
@@ -92,26 +131,45 @@ func (rq *RedisQueue) StartWorkers() {
 
 	// Start database sync workers
 	for i := 0; i < rq.workers; i++ {
-		rq.wg.Add(1)
-		go rq.databaseSyncWorker(i)
+		rq.startWorker(rq.names.DatabaseSync, i, rq.databaseSyncWorker)
 	}
 
 	// Start waitlist processing workers
 	for i := 0; i < rq.workers; i++ {
-		rq.wg.Add(1)
-		go rq.waitlistProcessingWorker(i)
+		rq.startWorker(rq.names.Waitlist, i, rq.waitlistProcessingWorker)
 	}
 
 	// Start waitlist entry workers
 	for i := 0; i < rq.workers; i++ {
-		rq.wg.Add(1)
-		go rq.waitlistEntryWorker(i)
+		rq.startWorker(rq.names.WaitlistEntry, i, rq.waitlistEntryWorker)
+	}
+
+	// Start the retry scheduler for failed database sync jobs
+	rq.startWorker(metrics.WorkerRetryScheduler, 0, func(int) { rq.retrySchedulerWorker() })
+
+	// Start the deadline reminder planner and sender
+	if rq.reminderService != nil {
+		rq.startWorker(metrics.WorkerReminders, 0, func(int) { rq.reminderWorker() })
+	}
+
+	// Start the audit log writer
+	if rq.auditRepo != nil {
+		rq.startWorker(rq.names.Audit, 0, rq.auditWorker)
 	}
 
 	rq.started = true
 	logger.Info("Redis queue workers started successfully")
 }
 
+// startWorker runs a worker under supervision so it is restarted if it panics
+func (rq *RedisQueue) startWorker(name string, workerID int, worker func(workerID int)) {
+	rq.wg.Add(1)
+	go func() {
+		defer rq.wg.Done()
+		superviseWorker(rq.ctx, &rq.liveness, rq.backend, name, workerID, worker)
+	}()
+}
+
 func (rq *RedisQueue) StopWorkers() {
 	rq.mu.Lock()
 	defer rq.mu.Unlock()
@@ -127,26 +185,77 @@ func (rq *RedisQueue) StopWorkers() {
 	logger.Info("Redis queue workers stopped")
 }
 
+// Drain stops the workers taking jobs and waits up to timeout for the jobs in progress. Jobs
+// that have not finished by then are pushed back to the front of their lists; jobs waiting
+// for a retry are already kept in Redis.
+func (rq *RedisQueue) Drain(timeout time.Duration) interfaces.DrainStatus {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	if !rq.started || !rq.drain.begin() {
+		return rq.drain.snapshot()
+	}
+
+	logger.Info("Draining Redis queue workers for up to %v...", timeout)
+	rq.cancel()
+	finished := waitTimeout(&rq.wg, timeout)
+	rq.started = false
+	return rq.drain.finish(!finished)
+}
+
+func (rq *RedisQueue) DrainStatus() interfaces.DrainStatus {
+	return rq.drain.snapshot()
+}
+
+func (rq *RedisQueue) WorkerStatus() interfaces.WorkerStatus {
+	return rq.liveness.status()
+}
+
+// putBack returns a requeueFunc that pushes job to the end of key that workers pop next
+func (rq *RedisQueue) putBack(key string, job any) requeueFunc {
+	return func(ctx context.Context) error {
+		data, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+		return rq.client.RPush(ctx, key, data).Err()
+	}
+}
+
 // EnqueueDatabaseSync adds a database sync job to the Redis queue
-func (rq *RedisQueue) EnqueueDatabaseSync(ctx context.Context, job interfaces.DatabaseSyncJob) error {
+func (rq *RedisQueue) EnqueueDatabaseSync(ctx context.Context, job interfaces.DatabaseSyncJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, rq.backend, rq.names.DatabaseSync)
+	defer func() { tracing.End(span, err) }()
+
+	if job.JobID == "" {
+		job.JobID = newJobID()
+	}
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
 	data, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("failed to marshal database sync job: %w", err)
 	}
 
-	err = rq.client.LPush(ctx, DatabaseSyncQueueKey, data).Err()
+	err = rq.client.LPush(ctx, rq.keys.databaseSyncLane(rq.lanes.laneOf(job)), data).Err()
 	if err != nil {
 		return fmt.Errorf("failed to enqueue database sync job: %w", err)
 	}
 
+	metrics.QueueJobsEnqueued.WithLabelValues(rq.backend, rq.names.DatabaseSync).Inc()
 	logger.Debug("Enqueued database sync job: %s for student %s, section %s",
 		job.JobType, job.StudentID, job.SectionID)
 	return nil
 }
 
-// DequeueDatabaseSync retrieves a database sync job from the Redis queue
+// DequeueDatabaseSync retrieves a database sync job from the Redis queue, taking it from the
+// lanes in the order the lane scheduler picks for this dequeue
 func (rq *RedisQueue) DequeueDatabaseSync(ctx context.Context) (*interfaces.DatabaseSyncJob, error) {
-	result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout, DatabaseSyncQueueKey).Result()
+	order := rq.lanes.order()
+	result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout,
+		rq.keys.databaseSyncLane(order[0]), rq.keys.databaseSyncLane(order[1])).Result()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil // No items available, return nil job
@@ -161,6 +270,13 @@ func (rq *RedisQueue) DequeueDatabaseSync(ctx context.Context) (*interfaces.Data
 		return nil, fmt.Errorf("unexpected Redis BRPOP result format")
 	}
 
+	metrics.QueueJobsDequeued.WithLabelValues(rq.backend, rq.names.DatabaseSync).Inc()
+	lane := LaneNormal
+	if result[0] == rq.keys.databaseSyncCritical {
+		lane = LaneCritical
+	}
+	metrics.QueueLaneJobsDequeued.WithLabelValues(rq.backend, lane).Inc()
+
 	var job interfaces.DatabaseSyncJob
 	err = json.Unmarshal([]byte(result[1]), &job)
 	if err != nil {
@@ -170,54 +286,79 @@ func (rq *RedisQueue) DequeueDatabaseSync(ctx context.Context) (*interfaces.Data
 	return &job, nil
 }
 
-// EnqueueWaitlistProcessing adds a section ID for waitlist processing to the Redis queue
-func (rq *RedisQueue) EnqueueWaitlistProcessing(ctx context.Context, sectionID uuid.UUID) error {
-	err := rq.client.LPush(ctx, WaitlistQueueKey, sectionID.String()).Err()
+// EnqueueWaitlistProcessing adds a waitlist promotion job to the Redis queue
+func (rq *RedisQueue) EnqueueWaitlistProcessing(ctx context.Context, job interfaces.WaitlistPromotionJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, rq.backend, rq.names.Waitlist)
+	defer func() { tracing.End(span, err) }()
+
+	jobData, err := json.Marshal(job)
 	if err != nil {
-		return fmt.Errorf("failed to enqueue waitlist processing for section %s: %w", sectionID, err)
+		return fmt.Errorf("failed to marshal waitlist promotion job: %w", err)
 	}
 
-	logger.Debug("Enqueued waitlist processing for section: %s", sectionID)
+	err = rq.client.LPush(ctx, rq.keys.waitlist, jobData).Err()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue waitlist processing for section %s: %w", job.SectionID, err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(rq.backend, rq.names.Waitlist).Inc()
+	logger.Debug("Enqueued waitlist processing for section: %s", job.SectionID)
 	return nil
 }
 
-// DequeueWaitlistProcessing retrieves a section ID for waitlist processing from the Redis queue
-func (rq *RedisQueue) DequeueWaitlistProcessing(ctx context.Context) (uuid.UUID, error) {
-	result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout, WaitlistQueueKey).Result()
+// DequeueWaitlistProcessing retrieves a waitlist promotion job from the Redis queue. It
+// returns nil when no job arrived before the dequeue timeout.
+func (rq *RedisQueue) DequeueWaitlistProcessing(ctx context.Context) (*interfaces.WaitlistPromotionJob, error) {
+	result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout, rq.keys.waitlist).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return uuid.UUID{}, nil // No items available, return empty UUID
+			return nil, nil // No items available
 		}
 		if err == context.DeadlineExceeded {
-			return uuid.UUID{}, nil // Timeout is expected when no jobs, return empty UUID
+			return nil, nil // Timeout is expected when no jobs
 		}
-		return uuid.UUID{}, fmt.Errorf("failed to dequeue waitlist processing: %w", err)
+		return nil, fmt.Errorf("failed to dequeue waitlist processing: %w", err)
 	}
 
 	if len(result) != 2 {
-		return uuid.UUID{}, fmt.Errorf("unexpected Redis BRPOP result format")
+		return nil, fmt.Errorf("unexpected Redis BRPOP result format")
 	}
 
-	sectionID, err := uuid.Parse(result[1])
-	if err != nil {
-		return uuid.UUID{}, fmt.Errorf("failed to parse section ID: %w", err)
+	metrics.QueueJobsDequeued.WithLabelValues(rq.backend, rq.names.Waitlist).Inc()
+
+	// Items pushed before promotion jobs carried a seat event are bare section IDs
+	if sectionID, err := uuid.Parse(result[1]); err == nil {
+		return &interfaces.WaitlistPromotionJob{SectionID: sectionID}, nil
+	}
+
+	var job interfaces.WaitlistPromotionJob
+	if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist promotion job: %w", err)
 	}
 
-	return sectionID, nil
+	return &job, nil
 }
 
 // EnqueueWaitlistEntry adds a waitlist entry job to the Redis queue
-func (rq *RedisQueue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.WaitlistJob) error {
+func (rq *RedisQueue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.WaitlistJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, rq.backend, rq.names.WaitlistEntry)
+	defer func() { tracing.End(span, err) }()
+
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
 	data, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("failed to marshal waitlist entry job: %w", err)
 	}
 
-	err = rq.client.LPush(ctx, WaitlistEntryQueueKey, data).Err()
+	err = rq.client.LPush(ctx, rq.keys.waitlistEntry, data).Err()
 	if err != nil {
 		return fmt.Errorf("failed to enqueue waitlist entry job: %w", err)
 	}
 
+	metrics.QueueJobsEnqueued.WithLabelValues(rq.backend, rq.names.WaitlistEntry).Inc()
 	logger.Debug("Enqueued waitlist entry job for student %s, section %s, position %d",
 		job.StudentID, job.SectionID, job.Position)
 	return nil
@@ -225,7 +366,7 @@ func (rq *RedisQueue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.W
 
 // DequeueWaitlistEntry retrieves a waitlist entry job from the Redis queue
 func (rq *RedisQueue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.WaitlistJob, error) {
-	result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout, WaitlistEntryQueueKey).Result()
+	result, err := rq.client.BRPop(ctx, DefaultDequeueTimeout, rq.keys.waitlistEntry).Result()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil // No items available, return nil job
@@ -240,6 +381,8 @@ func (rq *RedisQueue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.Wai
 		return nil, fmt.Errorf("unexpected Redis BRPOP result format")
 	}
 
+	metrics.QueueJobsDequeued.WithLabelValues(rq.backend, rq.names.WaitlistEntry).Inc()
+
 	var job interfaces.WaitlistJob
 	err = json.Unmarshal([]byte(result[1]), &job)
 	if err != nil {
@@ -251,8 +394,6 @@ func (rq *RedisQueue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.Wai
 
 // Worker methods
 func (rq *RedisQueue) databaseSyncWorker(workerID int) {
-	defer rq.wg.Done()
-
 	logger.Info("Redis database sync worker %d started", workerID)
 
 	for {
@@ -283,8 +424,6 @@ func (rq *RedisQueue) databaseSyncWorker(workerID int) {
 }
 
 func (rq *RedisQueue) waitlistProcessingWorker(workerID int) {
-	defer rq.wg.Done()
-
 	logger.Info("Redis waitlist processing worker %d started", workerID)
 
 	for {
@@ -295,7 +434,7 @@ func (rq *RedisQueue) waitlistProcessingWorker(workerID int) {
 		default:
 			// Create a timeout context for each dequeue operation
 			ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
-			sectionID, err := rq.DequeueWaitlistProcessing(ctx)
+			job, err := rq.DequeueWaitlistProcessing(ctx)
 			cancel()
 
 			if err != nil {
@@ -304,9 +443,8 @@ func (rq *RedisQueue) waitlistProcessingWorker(workerID int) {
 				continue
 			}
 
-			// Check if we got a valid UUID (not empty)
-			if sectionID != (uuid.UUID{}) {
-				rq.processWaitlistProcessing(workerID, sectionID)
+			if job != nil {
+				rq.processWaitlistProcessing(workerID, job)
 			} else {
 				// No jobs available, sleep briefly to avoid busy polling
 				time.Sleep(WorkerSleepDuration)
@@ -316,8 +454,6 @@ func (rq *RedisQueue) waitlistProcessingWorker(workerID int) {
 }
 
 func (rq *RedisQueue) waitlistEntryWorker(workerID int) {
-	defer rq.wg.Done()
-
 	logger.Info("Redis waitlist entry worker %d started", workerID)
 
 	for {
@@ -349,26 +485,50 @@ func (rq *RedisQueue) waitlistEntryWorker(workerID int) {
 
 // Job processing methods
 func (rq *RedisQueue) processDatabaseSyncJob(workerID int, job *interfaces.DatabaseSyncJob) {
-	logger.Info("Redis worker %d processing database sync job: %s for student %s, section %s",
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), job.RequestID), DefaultJobTimeout)
+	defer cancel()
+
+	logger.InfoContext(ctx, "Redis worker %d processing database sync job: %s for student %s, section %s",
 		workerID, job.JobType, job.StudentID, job.SectionID)
 
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
-	defer cancel()
+	rq.workerTracker.Begin()
+	defer rq.workerTracker.End()
+	defer rq.drain.track(rq.names.DatabaseSync, *job, rq.putBack(rq.keys.databaseSyncLane(rq.lanes.laneOf(*job)), *job))()
+
+	start := time.Now()
+	err := runJob(func() error { return rq.registrationService.ProcessDatabaseSyncJob(ctx, *job) })
+	metrics.ObserveJob(rq.backend, rq.names.DatabaseSync, start, err)
 
-	if err := rq.registrationService.ProcessDatabaseSyncJob(ctx, *job); err != nil {
-		logger.Error("Redis worker %d failed to process database sync job: %v", workerID, err)
+	var perr *panicError
+	if errors.As(err, &perr) {
+		rq.poisonJob(rq.names.DatabaseSync, job, perr)
+	} else if err != nil {
+		logger.ErrorContext(ctx, "Redis worker %d failed to process database sync job: %v", workerID, err)
+		rq.handleFailedDatabaseSyncJob(job, err)
 	} else {
-		logger.Info("Redis worker %d successfully processed database sync job", workerID)
+		logger.InfoContext(ctx, "Redis worker %d successfully processed database sync job", workerID)
 	}
 }
 
-func (rq *RedisQueue) processWaitlistProcessing(workerID int, sectionID uuid.UUID) {
+func (rq *RedisQueue) processWaitlistProcessing(workerID int, job *interfaces.WaitlistPromotionJob) {
+	sectionID := job.SectionID
 	logger.Info("Redis worker %d processing waitlist for section %s", workerID, sectionID)
 
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
 	defer cancel()
 
-	if err := rq.registrationService.ProcessWaitlist(ctx, sectionID); err != nil {
+	rq.workerTracker.Begin()
+	defer rq.workerTracker.End()
+	defer rq.drain.track(rq.names.Waitlist, *job, rq.putBack(rq.keys.waitlist, *job))()
+
+	start := time.Now()
+	err := runJob(func() error { return rq.registrationService.ProcessWaitlist(ctx, *job) })
+	metrics.ObserveJob(rq.backend, rq.names.Waitlist, start, err)
+
+	var perr *panicError
+	if errors.As(err, &perr) {
+		rq.poisonJob(rq.names.Waitlist, job, perr)
+	} else if err != nil {
 		logger.Error("Redis worker %d failed to process waitlist for section %s: %v", workerID, sectionID, err)
 	} else {
 		logger.Info("Redis worker %d successfully processed waitlist for section %s", workerID, sectionID)
@@ -376,18 +536,245 @@ func (rq *RedisQueue) processWaitlistProcessing(workerID int, sectionID uuid.UUI
 }
 
 func (rq *RedisQueue) processWaitlistEntryJob(workerID int, job *interfaces.WaitlistJob) {
-	logger.Info("Redis worker %d processing waitlist entry for student %s, section %s, position %d",
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), job.RequestID), DefaultJobTimeout)
+	defer cancel()
+
+	logger.InfoContext(ctx, "Redis worker %d processing waitlist entry for student %s, section %s, position %d",
 		workerID, job.StudentID, job.SectionID, job.Position)
 
+	rq.workerTracker.Begin()
+	defer rq.workerTracker.End()
+	defer rq.drain.track(rq.names.WaitlistEntry, *job, rq.putBack(rq.keys.waitlistEntry, *job))()
+
+	start := time.Now()
+	err := runJob(func() error { return rq.registrationService.ProcessWaitlistJob(ctx, *job) })
+	metrics.ObserveJob(rq.backend, rq.names.WaitlistEntry, start, err)
+
+	var perr *panicError
+	if errors.As(err, &perr) {
+		rq.poisonJob(rq.names.WaitlistEntry, job, perr)
+	} else if err != nil {
+		logger.ErrorContext(ctx, "Redis worker %d failed to process waitlist entry: %v", workerID, err)
+	} else {
+		logger.InfoContext(ctx, "Redis worker %d successfully processed waitlist entry", workerID)
+	}
+}
+
+// handleFailedDatabaseSyncJob schedules a backoff retry for a failed job, or moves it to the
+// dead letter queue once its retries are exhausted
+func (rq *RedisQueue) handleFailedDatabaseSyncJob(job *interfaces.DatabaseSyncJob, jobErr error) {
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
 	defer cancel()
 
-	if err := rq.registrationService.ProcessWaitlistJob(ctx, *job); err != nil {
-		logger.Error("Redis worker %d failed to process waitlist entry: %v", workerID, err)
-	} else {
-		logger.Info("Redis worker %d successfully processed waitlist entry", workerID)
+	job.Attempts++
+	job.LastError = jobErr.Error()
+
+	if job.Attempts > rq.maxRetries {
+		failedAt := time.Now()
+		job.FailedAt = &failedAt
+
+		data, err := json.Marshal(job)
+		if err != nil {
+			logger.Error("Failed to marshal dead database sync job %s: %v", job.JobID, err)
+			return
+		}
+
+		if err := rq.client.LPush(ctx, rq.keys.databaseSyncDead, data).Err(); err != nil {
+			logger.Error("Failed to move database sync job %s to dead letter queue: %v", job.JobID, err)
+			return
+		}
+
+		logger.Error("Database sync job %s moved to dead letter queue after %d attempts: %s",
+			job.JobID, job.Attempts, job.LastError)
+		return
+	}
+
+	delay := retryBackoff(job.Attempts)
+	data, err := json.Marshal(job)
+	if err != nil {
+		logger.Error("Failed to marshal database sync job %s for retry: %v", job.JobID, err)
+		return
+	}
+
+	err = rq.client.ZAdd(ctx, rq.keys.databaseSyncRetryLane(rq.lanes.laneOf(*job)), &redis.Z{
+		Score:  float64(time.Now().Add(delay).UnixMilli()),
+		Member: data,
+	}).Err()
+	if err != nil {
+		logger.Error("Failed to schedule retry for database sync job %s: %v", job.JobID, err)
+		return
+	}
+
+	logger.Warn("Database sync job %s scheduled for retry %d/%d in %v", job.JobID, job.Attempts, rq.maxRetries, delay)
+}
+
+func (rq *RedisQueue) retrySchedulerWorker() {
+	logger.Info("Redis retry scheduler started")
+
+	ticker := time.NewTicker(RetryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rq.ctx.Done():
+			logger.Info("Redis retry scheduler stopped")
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+			moved := 0
+			var err error
+			for _, lane := range []string{LaneCritical, LaneNormal} {
+				keys := []string{rq.keys.databaseSyncRetryLane(lane), rq.keys.databaseSyncLane(lane)}
+				n, laneErr := promoteDueRetriesScript.Run(ctx, rq.client, keys, time.Now().UnixMilli(), 100).Int()
+				if laneErr != nil {
+					err = laneErr
+					continue
+				}
+				moved += n
+			}
+			if depth, depthErr := rq.client.LLen(ctx, rq.keys.databaseSyncDead).Result(); depthErr == nil {
+				metrics.QueueDeadLetterDepth.WithLabelValues(rq.backend).Set(float64(depth))
+			}
+			cancel()
+
+			if err != nil {
+				logger.Error("Redis retry scheduler error: %v", err)
+				continue
+			}
+			if moved > 0 {
+				logger.Debug("Re-enqueued %d database sync jobs after backoff", moved)
+			}
+		}
+	}
+}
+
+// poisonJob sets aside a job whose handler panicked instead of retrying it
+func (rq *RedisQueue) poisonJob(queue string, job any, perr *panicError) {
+	metrics.QueueJobPanics.WithLabelValues(rq.backend, queue).Inc()
+	logger.Error("Job on %s queue panicked, moving it to the poison queue: %v\n%s", queue, perr.value, perr.stack)
+
+	poison, err := newPoisonJob(queue, job, perr)
+	if err != nil {
+		logger.Error("Failed to isolate poison job: %v", err)
+		return
+	}
+
+	data, err := json.Marshal(poison)
+	if err != nil {
+		logger.Error("Failed to marshal poison job: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+	defer cancel()
+
+	if err := rq.client.LPush(ctx, rq.keys.poison, data).Err(); err != nil {
+		logger.Error("Failed to move job to poison queue: %v", err)
 	}
 }
 
+// ListPoisonJobs returns a page of poison jobs, newest first, and the total count
+func (rq *RedisQueue) ListPoisonJobs(ctx context.Context, offset, limit int) ([]interfaces.PoisonJob, int64, error) {
+	total, err := rq.client.LLen(ctx, rq.keys.poison).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count poison jobs: %w", err)
+	}
+
+	items, err := rq.client.LRange(ctx, rq.keys.poison, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list poison jobs: %w", err)
+	}
+
+	jobs := make([]interfaces.PoisonJob, 0, len(items))
+	for _, item := range items {
+		var job interfaces.PoisonJob
+		if err := json.Unmarshal([]byte(item), &job); err != nil {
+			logger.Warn("Skipping malformed poison job: %v", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, total, nil
+}
+
+// ListDeadDatabaseSyncJobs returns a page of dead-lettered jobs, newest first, and the total count
+func (rq *RedisQueue) ListDeadDatabaseSyncJobs(ctx context.Context, offset, limit int) ([]interfaces.DatabaseSyncJob, int64, error) {
+	total, err := rq.client.LLen(ctx, rq.keys.databaseSyncDead).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get dead letter queue length: %w", err)
+	}
+
+	values, err := rq.client.LRange(ctx, rq.keys.databaseSyncDead, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read dead letter queue: %w", err)
+	}
+
+	jobs := make([]interfaces.DatabaseSyncJob, 0, len(values))
+	for _, value := range values {
+		var job interfaces.DatabaseSyncJob
+		if err := json.Unmarshal([]byte(value), &job); err != nil {
+			logger.Warn("Skipping malformed dead letter entry: %v", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, total, nil
+}
+
+// ReplayDeadDatabaseSyncJobs moves dead jobs back onto the main queue with a fresh retry budget
+func (rq *RedisQueue) ReplayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string) (int, error) {
+	return rq.replayDeadDatabaseSyncJobs(ctx, jobIDs, rq.EnqueueDatabaseSync)
+}
+
+// replayDeadDatabaseSyncJobs hands the given dead jobs, or all of them, to enqueue
+func (rq *RedisQueue) replayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string, enqueue func(context.Context, interfaces.DatabaseSyncJob) error) (int, error) {
+	values, err := rq.client.LRange(ctx, rq.keys.databaseSyncDead, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read dead letter queue: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(jobIDs))
+	for _, id := range jobIDs {
+		wanted[id] = true
+	}
+
+	replayed := 0
+	for _, value := range values {
+		var job interfaces.DatabaseSyncJob
+		if err := json.Unmarshal([]byte(value), &job); err != nil {
+			continue
+		}
+
+		if len(wanted) > 0 && !wanted[job.JobID] {
+			continue
+		}
+
+		// Only replay entries we actually removed so concurrent replays don't duplicate jobs
+		removed, err := rq.client.LRem(ctx, rq.keys.databaseSyncDead, 1, value).Result()
+		if err != nil {
+			return replayed, fmt.Errorf("failed to remove job %s from dead letter queue: %w", job.JobID, err)
+		}
+		if removed == 0 {
+			continue
+		}
+
+		job.Attempts = 0
+		job.LastError = ""
+		job.FailedAt = nil
+		if err := enqueue(ctx, job); err != nil {
+			return replayed, fmt.Errorf("failed to replay job %s: %w", job.JobID, err)
+		}
+		replayed++
+	}
+
+	logger.Info("Replayed %d dead database sync jobs", replayed)
+	return replayed, nil
+}
+
 // Ensure RedisQueue implements QueueService interface
 var _ interfaces.QueueService = (*RedisQueue)(nil)
+var _ interfaces.DeadLetterQueue = (*RedisQueue)(nil)
+var _ interfaces.PoisonQueue = (*RedisQueue)(nil)
+var _ interfaces.DrainableQueue = (*RedisQueue)(nil)
@@ -0,0 +1,596 @@
+package queue
+
+import (
+	"cobra-template/internal/config"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/internal/infrastructure/tracing"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// StreamClaimIdle is how long a delivered job may go unacknowledged before another
+	// consumer claims it. It is longer than a job may run, so only jobs whose worker died or
+	// failed them are claimed.
+	StreamClaimIdle = 2 * DefaultJobTimeout
+	// streamGroup is the consumer group every instance reads the job streams through
+	streamGroup      = "workers"
+	streamClaimBatch = 10
+	streamJobField   = "job"
+)
+
+// streamMessage is a job read from a stream, with the number of times it has been delivered
+type streamMessage struct {
+	stream     string
+	id         string
+	payload    string
+	deliveries int64
+}
+
+// streamConsumer is a job stream and the handler for the jobs read from it
+type streamConsumer struct {
+	stream string
+	queue  string
+	handle func(workerID int, msg *streamMessage)
+}
+
+// RedisStreamsQueue keeps its jobs in Redis Streams read through a consumer group. A job
+// stays pending until its worker acknowledges it, so the jobs of a worker that crashed are
+// claimed by another consumer once StreamClaimIdle has passed, and a job that failed is
+// delivered again the same way. Every delivery is counted; a database sync job delivered
+// more than maxRetries+1 times is dead-lettered. Reminders, poison and dead letter jobs are
+// kept as in RedisQueue.
+type RedisStreamsQueue struct {
+	*RedisQueue
+	consumer  string
+	claimIdle time.Duration
+}
+
+func NewRedisStreamsQueue(cfg *config.CacheConfig, names interfaces.QueueNames, lanes Lanes, workers, maxRetries int) interfaces.QueueService {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "worker"
+	}
+
+	return &RedisStreamsQueue{
+		RedisQueue: newRedisQueue(cfg, names, lanes, workers, maxRetries, metrics.BackendRedisStreams),
+		consumer:   fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8]),
+		claimIdle:  StreamClaimIdle,
+	}
+}
+
+// streamKey is the stream holding the jobs of the list with the given key. Streams get
+// their own keys so switching queue types never meets a key of the wrong type.
+func streamKey(listKey string) string {
+	return listKey + ":stream"
+}
+
+func (sq *RedisStreamsQueue) consumers() []streamConsumer {
+	return []streamConsumer{
+		{streamKey(sq.keys.databaseSyncCritical), sq.names.DatabaseSync, sq.handleDatabaseSync},
+		{streamKey(sq.keys.databaseSync), sq.names.DatabaseSync, sq.handleDatabaseSync},
+		{streamKey(sq.keys.waitlist), sq.names.Waitlist, sq.handleWaitlistProcessing},
+		{streamKey(sq.keys.waitlistEntry), sq.names.WaitlistEntry, sq.handleWaitlistEntry},
+	}
+}
+
+func (sq *RedisStreamsQueue) StartWorkers() {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	if sq.started {
+		return
+	}
+
+	if sq.registrationService == nil {
+		logger.Warn("Registration service not set, workers cannot process jobs")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+	err := sq.createGroups(ctx)
+	cancel()
+	if err != nil {
+		logger.Error("Failed to create stream consumer groups, workers not started: %v", err)
+		return
+	}
+
+	logger.Info("Starting %d Redis Streams queue workers as consumer %s", sq.workers, sq.consumer)
+
+	for i := 0; i < sq.workers; i++ {
+		sq.startWorker(sq.names.DatabaseSync, i, sq.databaseSyncStreamWorker)
+	}
+
+	for i := 0; i < sq.workers; i++ {
+		sq.startWorker(sq.names.Waitlist, i, sq.waitlistStreamWorker)
+	}
+
+	for i := 0; i < sq.workers; i++ {
+		sq.startWorker(sq.names.WaitlistEntry, i, sq.waitlistEntryStreamWorker)
+	}
+
+	// Claim the jobs of crashed workers and the jobs that failed
+	sq.startWorker(metrics.WorkerStreamReclaimer, 0, func(int) { sq.reclaimWorker() })
+
+	if sq.reminderService != nil {
+		sq.startWorker(metrics.WorkerReminders, 0, func(int) { sq.reminderWorker() })
+	}
+
+	if sq.auditRepo != nil {
+		sq.startWorker(sq.names.Audit, 0, sq.auditWorker)
+	}
+
+	sq.started = true
+	logger.Info("Redis Streams queue workers started successfully")
+}
+
+func (sq *RedisStreamsQueue) createGroups(ctx context.Context) error {
+	for _, consumer := range sq.consumers() {
+		err := sq.client.XGroupCreateMkStream(ctx, consumer.stream, streamGroup, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("failed to create consumer group on %s: %w", consumer.stream, err)
+		}
+	}
+	return nil
+}
+
+func (sq *RedisStreamsQueue) add(ctx context.Context, stream string, job any) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return sq.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{streamJobField: data},
+	}).Err()
+}
+
+// EnqueueDatabaseSync adds a database sync job to the stream of its lane
+func (sq *RedisStreamsQueue) EnqueueDatabaseSync(ctx context.Context, job interfaces.DatabaseSyncJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, sq.backend, sq.names.DatabaseSync)
+	defer func() { tracing.End(span, err) }()
+
+	if job.JobID == "" {
+		job.JobID = newJobID()
+	}
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
+	stream := streamKey(sq.keys.databaseSyncLane(sq.lanes.laneOf(job)))
+	if err := sq.add(ctx, stream, job); err != nil {
+		return fmt.Errorf("failed to enqueue database sync job: %w", err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(sq.backend, sq.names.DatabaseSync).Inc()
+	logger.Debug("Enqueued database sync job: %s for student %s, section %s",
+		job.JobType, job.StudentID, job.SectionID)
+	return nil
+}
+
+// EnqueueWaitlistProcessing adds a waitlist promotion job to its stream
+func (sq *RedisStreamsQueue) EnqueueWaitlistProcessing(ctx context.Context, job interfaces.WaitlistPromotionJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, sq.backend, sq.names.Waitlist)
+	defer func() { tracing.End(span, err) }()
+
+	if err := sq.add(ctx, streamKey(sq.keys.waitlist), job); err != nil {
+		return fmt.Errorf("failed to enqueue waitlist processing for section %s: %w", job.SectionID, err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(sq.backend, sq.names.Waitlist).Inc()
+	logger.Debug("Enqueued waitlist processing for section: %s", job.SectionID)
+	return nil
+}
+
+// EnqueueWaitlistEntry adds a waitlist entry job to its stream
+func (sq *RedisStreamsQueue) EnqueueWaitlistEntry(ctx context.Context, job interfaces.WaitlistJob) (err error) {
+	ctx, span := tracing.StartEnqueue(ctx, sq.backend, sq.names.WaitlistEntry)
+	defer func() { tracing.End(span, err) }()
+
+	if job.RequestID == "" {
+		job.RequestID = logger.RequestID(ctx)
+	}
+
+	if err := sq.add(ctx, streamKey(sq.keys.waitlistEntry), job); err != nil {
+		return fmt.Errorf("failed to enqueue waitlist entry job: %w", err)
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(sq.backend, sq.names.WaitlistEntry).Inc()
+	logger.Debug("Enqueued waitlist entry job for student %s, section %s, position %d",
+		job.StudentID, job.SectionID, job.Position)
+	return nil
+}
+
+// The Dequeue methods acknowledge the job as they return it, so a caller outside the workers
+// gets it at most once. The workers acknowledge only after the job has been processed.
+
+func (sq *RedisStreamsQueue) DequeueDatabaseSync(ctx context.Context) (*interfaces.DatabaseSyncJob, error) {
+	msg, err := sq.readNext(ctx, sq.databaseSyncStreams()...)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	sq.ack(msg)
+
+	var job interfaces.DatabaseSyncJob
+	if err := json.Unmarshal([]byte(msg.payload), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal database sync job: %w", err)
+	}
+	return &job, nil
+}
+
+func (sq *RedisStreamsQueue) DequeueWaitlistProcessing(ctx context.Context) (*interfaces.WaitlistPromotionJob, error) {
+	msg, err := sq.readNext(ctx, streamKey(sq.keys.waitlist))
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	sq.ack(msg)
+
+	var job interfaces.WaitlistPromotionJob
+	if err := json.Unmarshal([]byte(msg.payload), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist promotion job: %w", err)
+	}
+	return &job, nil
+}
+
+func (sq *RedisStreamsQueue) DequeueWaitlistEntry(ctx context.Context) (*interfaces.WaitlistJob, error) {
+	msg, err := sq.readNext(ctx, streamKey(sq.keys.waitlistEntry))
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	sq.ack(msg)
+
+	var job interfaces.WaitlistJob
+	if err := json.Unmarshal([]byte(msg.payload), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waitlist entry job: %w", err)
+	}
+	return &job, nil
+}
+
+// databaseSyncStreams are the lane streams in the order the next read tries them
+func (sq *RedisStreamsQueue) databaseSyncStreams() []string {
+	order := sq.lanes.order()
+	return []string{
+		streamKey(sq.keys.databaseSyncLane(order[0])),
+		streamKey(sq.keys.databaseSyncLane(order[1])),
+	}
+}
+
+// readNext reads one new job. With several streams it tries each in order without waiting
+// before waiting on all of them, so the order decides which stream is served first.
+func (sq *RedisStreamsQueue) readNext(ctx context.Context, streams ...string) (*streamMessage, error) {
+	if len(streams) > 1 {
+		for _, stream := range streams {
+			msg, err := sq.readGroup(ctx, -1, stream)
+			if err != nil || msg != nil {
+				return msg, err
+			}
+		}
+	}
+	return sq.readGroup(ctx, DefaultDequeueTimeout, streams...)
+}
+
+// readGroup reads one new job from streams, waiting up to block for one; a negative block
+// does not wait
+func (sq *RedisStreamsQueue) readGroup(ctx context.Context, block time.Duration, streams ...string) (*streamMessage, error) {
+	args := make([]string, 0, 2*len(streams))
+	args = append(args, streams...)
+	for range streams {
+		args = append(args, ">")
+	}
+
+	result, err := sq.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    streamGroup,
+		Consumer: sq.consumer,
+		Streams:  args,
+		Count:    1,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil || err == context.DeadlineExceeded {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read job stream: %w", err)
+	}
+
+	for _, stream := range result {
+		for _, message := range stream.Messages {
+			return newStreamMessage(stream.Stream, message, 1), nil
+		}
+	}
+	return nil, nil
+}
+
+func newStreamMessage(stream string, message redis.XMessage, deliveries int64) *streamMessage {
+	payload, _ := message.Values[streamJobField].(string)
+	return &streamMessage{
+		stream:     stream,
+		id:         message.ID,
+		payload:    payload,
+		deliveries: deliveries,
+	}
+}
+
+// ack acknowledges a job and removes it from its stream
+func (sq *RedisStreamsQueue) ack(msg *streamMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+	defer cancel()
+
+	pipe := sq.client.TxPipeline()
+	pipe.XAck(ctx, msg.stream, streamGroup, msg.id)
+	pipe.XDel(ctx, msg.stream, msg.id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("Failed to acknowledge job %s on %s, it will be delivered again: %v", msg.id, msg.stream, err)
+	}
+}
+
+// Worker methods
+func (sq *RedisStreamsQueue) databaseSyncStreamWorker(workerID int) {
+	sq.consume("database sync", workerID, sq.databaseSyncStreams, sq.handleDatabaseSync)
+}
+
+func (sq *RedisStreamsQueue) waitlistStreamWorker(workerID int) {
+	streams := func() []string { return []string{streamKey(sq.keys.waitlist)} }
+	sq.consume("waitlist processing", workerID, streams, sq.handleWaitlistProcessing)
+}
+
+func (sq *RedisStreamsQueue) waitlistEntryStreamWorker(workerID int) {
+	streams := func() []string { return []string{streamKey(sq.keys.waitlistEntry)} }
+	sq.consume("waitlist entry", workerID, streams, sq.handleWaitlistEntry)
+}
+
+func (sq *RedisStreamsQueue) consume(kind string, workerID int, streams func() []string, handle func(workerID int, msg *streamMessage)) {
+	logger.Info("Redis Streams %s worker %d started", kind, workerID)
+
+	for {
+		select {
+		case <-sq.ctx.Done():
+			logger.Info("Redis Streams %s worker %d stopped", kind, workerID)
+			return
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+			msg, err := sq.readNext(ctx, streams()...)
+			cancel()
+
+			if err != nil {
+				logger.Error("Redis Streams %s worker %d error: %v", kind, workerID, err)
+				time.Sleep(WorkerSleepDuration)
+				continue
+			}
+
+			if msg != nil {
+				handle(workerID, msg)
+			} else {
+				time.Sleep(WorkerSleepDuration)
+			}
+		}
+	}
+}
+
+// reclaimWorker claims the jobs left unacknowledged for longer than claimIdle, by this
+// consumer or another, and runs them again
+func (sq *RedisStreamsQueue) reclaimWorker() {
+	logger.Info("Redis Streams reclaimer started")
+
+	ticker := time.NewTicker(sq.claimIdle / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sq.ctx.Done():
+			logger.Info("Redis Streams reclaimer stopped")
+			return
+		case <-ticker.C:
+			for _, consumer := range sq.consumers() {
+				sq.reclaim(consumer)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+			if depth, err := sq.client.LLen(ctx, sq.keys.databaseSyncDead).Result(); err == nil {
+				metrics.QueueDeadLetterDepth.WithLabelValues(sq.backend).Set(float64(depth))
+			}
+			cancel()
+		}
+	}
+}
+
+func (sq *RedisStreamsQueue) reclaim(consumer streamConsumer) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+	pending, err := sq.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: consumer.stream,
+		Group:  streamGroup,
+		Idle:   sq.claimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  streamClaimBatch,
+	}).Result()
+	cancel()
+	if err != nil {
+		logger.Error("Failed to list pending jobs on %s: %v", consumer.stream, err)
+		return
+	}
+
+	for _, entry := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+		// The idle check makes the claim fail if another consumer claimed the job first
+		messages, err := sq.client.XClaim(ctx, &redis.XClaimArgs{
+			Stream:   consumer.stream,
+			Group:    streamGroup,
+			Consumer: sq.consumer,
+			MinIdle:  sq.claimIdle,
+			Messages: []string{entry.ID},
+		}).Result()
+		cancel()
+		if err != nil {
+			logger.Error("Failed to claim job %s on %s: %v", entry.ID, consumer.stream, err)
+			continue
+		}
+
+		for _, message := range messages {
+			metrics.QueueRedeliveries.WithLabelValues(sq.backend, consumer.queue).Inc()
+			logger.Warn("Claimed job %s on %s from %s, delivery %d", message.ID, consumer.stream, entry.Consumer, entry.RetryCount+1)
+			consumer.handle(0, newStreamMessage(consumer.stream, message, entry.RetryCount+1))
+		}
+	}
+}
+
+// Job handling methods. A job that fails is left unacknowledged and so delivered again
+// after claimIdle; a job whose handler panicked is moved to the poison queue.
+
+func (sq *RedisStreamsQueue) handleDatabaseSync(workerID int, msg *streamMessage) {
+	var job interfaces.DatabaseSyncJob
+	if err := json.Unmarshal([]byte(msg.payload), &job); err != nil {
+		logger.Error("Dropping malformed database sync job %s: %v", msg.id, err)
+		sq.ack(msg)
+		return
+	}
+	metrics.QueueJobsDequeued.WithLabelValues(sq.backend, sq.names.DatabaseSync).Inc()
+	lane := LaneNormal
+	if msg.stream == streamKey(sq.keys.databaseSyncCritical) {
+		lane = LaneCritical
+	}
+	metrics.QueueLaneJobsDequeued.WithLabelValues(sq.backend, lane).Inc()
+
+	job.Attempts = int(msg.deliveries - 1)
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), job.RequestID), DefaultJobTimeout)
+	defer cancel()
+
+	logger.InfoContext(ctx, "Redis Streams worker %d processing database sync job: %s for student %s, section %s (delivery %d)",
+		workerID, job.JobType, job.StudentID, job.SectionID, msg.deliveries)
+
+	sq.workerTracker.Begin()
+	defer sq.workerTracker.End()
+	defer sq.drain.track(sq.names.DatabaseSync, job, nil)()
+
+	start := time.Now()
+	err := runJob(func() error { return sq.registrationService.ProcessDatabaseSyncJob(ctx, job) })
+	metrics.ObserveJob(sq.backend, sq.names.DatabaseSync, start, err)
+
+	var perr *panicError
+	switch {
+	case errors.As(err, &perr):
+		sq.poisonJob(sq.names.DatabaseSync, job, perr)
+	case err != nil && msg.deliveries > int64(sq.maxRetries):
+		logger.ErrorContext(ctx, "Redis Streams worker %d failed to process database sync job: %v", workerID, err)
+		if !sq.deadLetter(&job, err) {
+			return
+		}
+	case err != nil:
+		logger.WarnContext(ctx, "Database sync job %s failed on delivery %d/%d, retrying in %v: %v",
+			job.JobID, msg.deliveries, sq.maxRetries+1, sq.claimIdle, err)
+		return
+	default:
+		logger.InfoContext(ctx, "Redis Streams worker %d successfully processed database sync job", workerID)
+	}
+	sq.ack(msg)
+}
+
+// deadLetter parks a job whose deliveries are exhausted. It reports false when the job could
+// not be parked and must stay pending.
+func (sq *RedisStreamsQueue) deadLetter(job *interfaces.DatabaseSyncJob, jobErr error) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+	defer cancel()
+
+	failedAt := time.Now()
+	job.Attempts++
+	job.LastError = jobErr.Error()
+	job.FailedAt = &failedAt
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		logger.Error("Failed to marshal dead database sync job %s: %v", job.JobID, err)
+		return false
+	}
+	if err := sq.client.LPush(ctx, sq.keys.databaseSyncDead, data).Err(); err != nil {
+		logger.Error("Failed to move database sync job %s to dead letter queue: %v", job.JobID, err)
+		return false
+	}
+
+	logger.Error("Database sync job %s moved to dead letter queue after %d attempts: %s",
+		job.JobID, job.Attempts, job.LastError)
+	return true
+}
+
+func (sq *RedisStreamsQueue) handleWaitlistProcessing(workerID int, msg *streamMessage) {
+	var job interfaces.WaitlistPromotionJob
+	if err := json.Unmarshal([]byte(msg.payload), &job); err != nil {
+		logger.Error("Dropping malformed waitlist promotion job %s: %v", msg.id, err)
+		sq.ack(msg)
+		return
+	}
+	metrics.QueueJobsDequeued.WithLabelValues(sq.backend, sq.names.Waitlist).Inc()
+	logger.Info("Redis Streams worker %d processing waitlist for section %s", workerID, job.SectionID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+	defer cancel()
+
+	sq.workerTracker.Begin()
+	defer sq.workerTracker.End()
+	defer sq.drain.track(sq.names.Waitlist, job, nil)()
+
+	start := time.Now()
+	err := runJob(func() error { return sq.registrationService.ProcessWaitlist(ctx, job) })
+	metrics.ObserveJob(sq.backend, sq.names.Waitlist, start, err)
+	sq.settle(ctx, workerID, msg, sq.names.Waitlist, job, err)
+}
+
+func (sq *RedisStreamsQueue) handleWaitlistEntry(workerID int, msg *streamMessage) {
+	var job interfaces.WaitlistJob
+	if err := json.Unmarshal([]byte(msg.payload), &job); err != nil {
+		logger.Error("Dropping malformed waitlist entry job %s: %v", msg.id, err)
+		sq.ack(msg)
+		return
+	}
+	metrics.QueueJobsDequeued.WithLabelValues(sq.backend, sq.names.WaitlistEntry).Inc()
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), job.RequestID), DefaultJobTimeout)
+	defer cancel()
+
+	logger.InfoContext(ctx, "Redis Streams worker %d processing waitlist entry for student %s, section %s, position %d",
+		workerID, job.StudentID, job.SectionID, job.Position)
+
+	sq.workerTracker.Begin()
+	defer sq.workerTracker.End()
+	defer sq.drain.track(sq.names.WaitlistEntry, job, nil)()
+
+	start := time.Now()
+	err := runJob(func() error { return sq.registrationService.ProcessWaitlistJob(ctx, job) })
+	metrics.ObserveJob(sq.backend, sq.names.WaitlistEntry, start, err)
+	sq.settle(ctx, workerID, msg, sq.names.WaitlistEntry, job, err)
+}
+
+// settle acknowledges a waitlist job unless it failed with deliveries left. Waitlist jobs
+// have no dead letter queue; one that keeps failing is dropped.
+func (sq *RedisStreamsQueue) settle(ctx context.Context, workerID int, msg *streamMessage, queue string, job any, err error) {
+	var perr *panicError
+	switch {
+	case errors.As(err, &perr):
+		sq.poisonJob(queue, job, perr)
+	case err != nil && msg.deliveries > int64(sq.maxRetries):
+		logger.ErrorContext(ctx, "Redis Streams worker %d giving up on %s job %s after %d deliveries: %v", workerID, queue, msg.id, msg.deliveries, err)
+	case err != nil:
+		logger.WarnContext(ctx, "Redis Streams worker %d failed %s job %s on delivery %d, retrying in %v: %v", workerID, queue, msg.id, msg.deliveries, sq.claimIdle, err)
+		return
+	default:
+		logger.InfoContext(ctx, "Redis Streams worker %d successfully processed %s job", workerID, queue)
+	}
+	sq.ack(msg)
+}
+
+// ReplayDeadDatabaseSyncJobs moves dead jobs back onto their streams with a fresh retry budget
+func (sq *RedisStreamsQueue) ReplayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string) (int, error) {
+	return sq.replayDeadDatabaseSyncJobs(ctx, jobIDs, sq.EnqueueDatabaseSync)
+}
+
+var _ interfaces.QueueService = (*RedisStreamsQueue)(nil)
+var _ interfaces.DeadLetterQueue = (*RedisStreamsQueue)(nil)
+var _ interfaces.PoisonQueue = (*RedisStreamsQueue)(nil)
+var _ interfaces.DrainableQueue = (*RedisStreamsQueue)(nil)
@@ -0,0 +1,246 @@
+package queue
+
+import (
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// ReminderPollInterval is how often due reminders are picked up, so they go out at most
+	// this late
+	ReminderPollInterval = 30 * time.Second
+	// ReminderPlanInterval is how often semester calendars are checked for new deadlines
+	ReminderPlanInterval = 15 * time.Minute
+	reminderBatchSize    = 50
+)
+
+// scheduleReminderScript stores a reminder unless one with the same ID is already scheduled
+var scheduleReminderScript = redis.NewScript(`
+	if redis.call("ZADD", KEYS[1], "NX", ARGV[2], ARGV[1]) == 0 then
+		return 0
+	end
+	redis.call("HSET", KEYS[2], ARGV[1], ARGV[3])
+	return 1
+`)
+
+// popDueRemindersScript removes and returns the jobs of reminders whose time has come, so
+// each one is handed to exactly one instance
+var popDueRemindersScript = redis.NewScript(`
+	local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, tonumber(ARGV[2]))
+	local jobs = {}
+	for _, id in ipairs(ids) do
+		redis.call("ZREM", KEYS[1], id)
+		local job = redis.call("HGET", KEYS[2], id)
+		redis.call("HDEL", KEYS[2], id)
+		if job then
+			table.insert(jobs, job)
+		end
+	end
+	return jobs
+`)
+
+func (rq *RedisQueue) SetReminderService(service interface{}) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	if reminderService, ok := service.(serviceInterfaces.ReminderService); ok {
+		rq.reminderService = reminderService
+	} else {
+		logger.Error("Invalid service type provided to SetReminderService")
+	}
+}
+
+func (rq *RedisQueue) ScheduleReminder(ctx context.Context, job interfaces.ReminderJob, at time.Time) (bool, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal reminder job: %w", err)
+	}
+
+	keys := []string{rq.keys.reminders, rq.keys.reminderJobs}
+	added, err := scheduleReminderScript.Run(ctx, rq.client, keys, job.ReminderID, at.UnixMilli(), data).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule reminder %s: %w", job.ReminderID, err)
+	}
+	if added == 0 {
+		return false, nil
+	}
+
+	metrics.QueueJobsEnqueued.WithLabelValues(rq.backend, rq.names.Reminders).Inc()
+	return true, nil
+}
+
+// reminderWorker plans reminders for upcoming deadlines and sends the ones that are due.
+// Every instance runs it; scheduling and popping are atomic, so each reminder is sent once.
+func (rq *RedisQueue) reminderWorker() {
+	logger.Info("Redis reminder worker started")
+
+	ticker := time.NewTicker(ReminderPollInterval)
+	defer ticker.Stop()
+
+	var lastPlanned time.Time
+	for {
+		select {
+		case <-rq.ctx.Done():
+			logger.Info("Redis reminder worker stopped")
+			return
+		case <-ticker.C:
+			if time.Since(lastPlanned) >= ReminderPlanInterval {
+				planReminders(rq.reminderService)
+				lastPlanned = time.Now()
+			}
+			rq.runDueReminders()
+		}
+	}
+}
+
+func (rq *RedisQueue) runDueReminders() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+	keys := []string{rq.keys.reminders, rq.keys.reminderJobs}
+	values, err := popDueRemindersScript.Run(ctx, rq.client, keys, time.Now().UnixMilli(), reminderBatchSize).StringSlice()
+	cancel()
+	if err != nil && err != redis.Nil {
+		logger.Error("Failed to pick up due reminders: %v", err)
+		return
+	}
+
+	for _, value := range values {
+		var job interfaces.ReminderJob
+		if err := json.Unmarshal([]byte(value), &job); err != nil {
+			logger.Error("Skipping malformed reminder job: %v", err)
+			continue
+		}
+		metrics.QueueJobsDequeued.WithLabelValues(rq.backend, rq.names.Reminders).Inc()
+		rq.processReminderJob(&job)
+	}
+}
+
+func (rq *RedisQueue) processReminderJob(job *interfaces.ReminderJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout*4)
+	defer cancel()
+
+	start := time.Now()
+	err := runJob(func() error { return rq.reminderService.SendDeadlineReminders(ctx, *job) })
+	metrics.ObserveJob(rq.backend, rq.names.Reminders, start, err)
+
+	var perr *panicError
+	if errors.As(err, &perr) {
+		rq.poisonJob(rq.names.Reminders, job, perr)
+	} else if err != nil {
+		retryReminder(rq, job, err, rq.maxRetries)
+	}
+}
+
+func (q *Queue) SetReminderService(service interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if reminderService, ok := service.(serviceInterfaces.ReminderService); ok {
+		q.reminderService = reminderService
+	} else {
+		logger.Error("Invalid service type provided to SetReminderService")
+	}
+}
+
+// ScheduleReminder keeps the reminder in a timer. Pending reminders are lost on restart and
+// replanned by the next planning run if they are still ahead.
+func (q *Queue) ScheduleReminder(ctx context.Context, job interfaces.ReminderJob, at time.Time) (bool, error) {
+	q.remindersMu.Lock()
+	defer q.remindersMu.Unlock()
+
+	if _, scheduled := q.reminders[job.ReminderID]; scheduled {
+		return false, nil
+	}
+
+	q.reminders[job.ReminderID] = time.AfterFunc(time.Until(at), func() {
+		q.remindersMu.Lock()
+		delete(q.reminders, job.ReminderID)
+		q.remindersMu.Unlock()
+
+		if q.ctx.Err() != nil {
+			return
+		}
+		q.processReminderJob(&job)
+	})
+
+	metrics.QueueJobsEnqueued.WithLabelValues(q.backend, q.names.Reminders).Inc()
+	return true, nil
+}
+
+func (q *Queue) reminderPlanWorker() {
+	logger.Info("Reminder planning worker started")
+
+	ticker := time.NewTicker(ReminderPlanInterval)
+	defer ticker.Stop()
+
+	planReminders(q.reminderService)
+	for {
+		select {
+		case <-q.ctx.Done():
+			logger.Info("Reminder planning worker stopped")
+			return
+		case <-ticker.C:
+			planReminders(q.reminderService)
+		}
+	}
+}
+
+func (q *Queue) processReminderJob(job *interfaces.ReminderJob) {
+	q.mu.RLock()
+	reminderService := q.reminderService
+	q.mu.RUnlock()
+	if reminderService == nil {
+		logger.Warn("Reminder service not set, dropping reminder %s", job.ReminderID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout*4)
+	defer cancel()
+
+	start := time.Now()
+	err := runJob(func() error { return reminderService.SendDeadlineReminders(ctx, *job) })
+	metrics.ObserveJob(q.backend, q.names.Reminders, start, err)
+
+	var perr *panicError
+	if errors.As(err, &perr) {
+		q.poisonJob(q.names.Reminders, job, perr)
+	} else if err != nil {
+		retryReminder(q, job, err, q.maxRetries)
+	}
+}
+
+func planReminders(reminderService serviceInterfaces.ReminderService) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJobTimeout)
+	defer cancel()
+
+	if err := runJob(func() error { return reminderService.PlanDeadlineReminders(ctx) }); err != nil {
+		logger.Error("Failed to plan deadline reminders: %v", err)
+	}
+}
+
+// retryReminder reschedules a failed reminder with backoff until its retries run out
+func retryReminder(queue interfaces.QueueService, job *interfaces.ReminderJob, jobErr error, maxRetries int) {
+	job.Attempts++
+	if job.Attempts > maxRetries {
+		logger.Error("Giving up on reminder %s after %d attempts: %v", job.ReminderID, job.Attempts, jobErr)
+		return
+	}
+
+	delay := retryBackoff(job.Attempts)
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDequeueTimeout)
+	defer cancel()
+
+	if _, err := queue.ScheduleReminder(ctx, *job, time.Now().Add(delay)); err != nil {
+		logger.Error("Failed to reschedule reminder %s: %v", job.ReminderID, err)
+		return
+	}
+	logger.Warn("Reminder %s failed, retry %d/%d in %v: %v", job.ReminderID, job.Attempts, maxRetries, delay, jobErr)
+}
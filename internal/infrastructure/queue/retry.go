@@ -0,0 +1,34 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RetryBaseDelay    = 1 * time.Second
+	RetryMaxDelay     = 60 * time.Second
+	RetryPollInterval = 1 * time.Second
+)
+
+// retryBackoff returns the exponential delay before the given retry attempt (1-based)
+func retryBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := RetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= RetryMaxDelay {
+			return RetryMaxDelay
+		}
+	}
+
+	return delay
+}
+
+func newJobID() string {
+	return uuid.New().String()
+}
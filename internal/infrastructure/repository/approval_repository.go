@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApprovalRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalRepository(db *gorm.DB) interfaces.ApprovalRepository {
+	return &ApprovalRepository{
+		db: db,
+	}
+}
+
+func (r *ApprovalRepository) Create(ctx context.Context, approval *domain.AdminApproval) error {
+	return r.db.WithContext(ctx).Create(approval).Error
+}
+
+func (r *ApprovalRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AdminApproval, error) {
+	var approval domain.AdminApproval
+	err := r.db.WithContext(ctx).Where("approval_id = ?", id).First(&approval).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *ApprovalRepository) GetPending(ctx context.Context, operation string, targetID uuid.UUID) (*domain.AdminApproval, error) {
+	var approval domain.AdminApproval
+	err := r.db.WithContext(ctx).
+		Where("operation = ? AND target_id = ? AND status = ?", operation, targetID, domain.ApprovalPending).
+		First(&approval).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *ApprovalRepository) List(ctx context.Context, status domain.ApprovalStatus) ([]*domain.AdminApproval, error) {
+	var approvals []*domain.AdminApproval
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("proposed_at DESC").Find(&approvals).Error
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+func (r *ApprovalRepository) Decide(ctx context.Context, id uuid.UUID, status domain.ApprovalStatus, decidedBy *string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.AdminApproval{}).
+		Where("approval_id = ? AND status = ?", id, domain.ApprovalPending).
+		Updates(map[string]any{
+			"status":     status,
+			"decided_by": decidedBy,
+			"decided_at": at,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to decide approval: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ApprovalRepository) Complete(ctx context.Context, id uuid.UUID, status domain.ApprovalStatus, result string) error {
+	res := r.db.WithContext(ctx).
+		Model(&domain.AdminApproval{}).
+		Where("approval_id = ?", id).
+		Updates(map[string]any{
+			"status":     status,
+			"result":     result,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return fmt.Errorf("failed to record approval outcome: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("approval %s not found", id)
+	}
+	return nil
+}
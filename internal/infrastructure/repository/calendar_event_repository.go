@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CalendarEventRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarEventRepository(db *gorm.DB) interfaces.CalendarEventRepository {
+	return &CalendarEventRepository{
+		db: db,
+	}
+}
+
+func (r *CalendarEventRepository) Create(ctx context.Context, event *domain.CalendarEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *CalendarEventRepository) GetBySemesterID(ctx context.Context, semesterID uuid.UUID) ([]*domain.CalendarEvent, error) {
+	var events []*domain.CalendarEvent
+	err := r.db.WithContext(ctx).
+		Where("semester_id = ?", semesterID).
+		Order("starts_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
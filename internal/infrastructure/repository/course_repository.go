@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	domain "cobra-template/internal/domain/registration"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
@@ -36,6 +39,20 @@ func (r *CourseRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.C
 	return &course, nil
 }
 
+func (r *CourseRepository) UpdateTags(ctx context.Context, courseID uuid.UUID, tags []string, attributes map[string]string) error {
+	result := r.db.WithContext(ctx).Model(&domain.Course{CourseID: courseID}).
+		Select("tags", "attributes", "updated_at").
+		Updates(&domain.Course{Tags: tags, Attributes: attributes, UpdatedAt: time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update course tags: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("course %s not found", courseID)
+	}
+
+	return nil
+}
+
 func (r *CourseRepository) GetByCode(ctx context.Context, courseCode string) (*domain.Course, error) {
 	var course domain.Course
 	err := r.db.WithContext(ctx).First(&course, "course_code = ?", courseCode).Error
@@ -51,10 +68,8 @@ func (r *CourseRepository) GetByCode(ctx context.Context, courseCode string) (*d
 func (r *CourseRepository) GetAllActive(ctx context.Context) ([]*domain.Course, error) {
 	var courses []*domain.Course
 
-	// Get all active courses - you might want to add additional criteria
-	// like checking if the course has active sections in current semester
 	err := r.db.WithContext(ctx).
-		Where("active = ?", true). // Assuming there's an active field
+		Where("active = ?", true).
 		Find(&courses).Error
 
 	if err != nil {
@@ -63,3 +78,93 @@ func (r *CourseRepository) GetAllActive(ctx context.Context) ([]*domain.Course,
 
 	return courses, nil
 }
+
+func (r *CourseRepository) List(ctx context.Context, includeInactive bool) ([]*domain.Course, error) {
+	query := r.db.WithContext(ctx).Order("course_code")
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+
+	var courses []*domain.Course
+	if err := query.Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("failed to list courses: %w", err)
+	}
+	return courses, nil
+}
+
+func (r *CourseRepository) Update(ctx context.Context, course *domain.Course) error {
+	result := r.db.WithContext(ctx).Model(&domain.Course{}).
+		Where("course_id = ?", course.CourseID).
+		Updates(map[string]any{
+			"course_name":  course.CourseName,
+			"department":   course.Department,
+			"credit_hours": course.CreditHours,
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update course: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("course %s not found", course.CourseID)
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) SetActive(ctx context.Context, courseID uuid.UUID, active bool) error {
+	result := r.db.WithContext(ctx).Model(&domain.Course{}).
+		Where("course_id = ?", courseID).
+		Updates(map[string]any{
+			"active":     active,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update course status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("course %s not found", courseID)
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) Search(ctx context.Context, filter interfaces.CourseSearchFilter, afterCode string, limit int) ([]*domain.Course, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Course{}).Where("active = ?", true)
+	if filter.CodePrefix != "" {
+		query = query.Where("course_code LIKE ?", escapeLike(strings.ToUpper(filter.CodePrefix))+"%")
+	}
+	if filter.Title != "" {
+		query = query.Where("course_name ILIKE ?", "%"+escapeLike(filter.Title)+"%")
+	}
+	if filter.Department != "" {
+		query = query.Where("LOWER(department) = LOWER(?)", filter.Department)
+	}
+	if filter.MinCredits != nil {
+		query = query.Where("credit_hours >= ?", *filter.MinCredits)
+	}
+	if filter.MaxCredits != nil {
+		query = query.Where("credit_hours <= ?", *filter.MaxCredits)
+	}
+	if afterCode != "" {
+		query = query.Where("course_code > ?", afterCode)
+	}
+
+	sections := sectionSearchConditions(r.db.Model(&domain.Section{}).Select("1").
+		Where("sections.course_id = courses.course_id"), filter)
+
+	var courses []*domain.Course
+	err := query.Where("EXISTS (?)", sections).
+		Order("course_code").
+		Limit(limit).
+		Find(&courses).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search courses: %w", err)
+	}
+	return courses, nil
+}
+
+// escapeLike escapes the LIKE wildcards in user input so they match literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EnrollmentForecastRepository struct {
+	db *gorm.DB
+}
+
+func NewEnrollmentForecastRepository(db *gorm.DB) interfaces.EnrollmentForecastRepository {
+	return &EnrollmentForecastRepository{
+		db: db,
+	}
+}
+
+func (r *EnrollmentForecastRepository) GetRegistrationCurves(ctx context.Context, closedBefore time.Time) ([]interfaces.RegistrationDayCount, error) {
+	var rows []struct {
+		SemesterID uuid.UUID
+		Day        int
+		Count      int
+	}
+	// Registrations made before the window opened, such as imports, count towards day 0
+	err := r.db.WithContext(ctx).
+		Table("registrations r").
+		Select(`r.semester_id,
+			GREATEST(0, FLOOR(EXTRACT(EPOCH FROM (r.registration_date - sem.registration_start)) / 86400))::int AS day,
+			COUNT(*) AS count`).
+		Joins("JOIN semesters sem ON sem.semester_id = r.semester_id").
+		Where("r.status = ? AND sem.registration_end < ?", domain.StatusEnrolled, closedBefore).
+		Group("r.semester_id, day").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]interfaces.RegistrationDayCount, len(rows))
+	for i, row := range rows {
+		counts[i] = interfaces.RegistrationDayCount{SemesterID: row.SemesterID, Day: row.Day, Count: row.Count}
+	}
+	return counts, nil
+}
+
+func (r *EnrollmentForecastRepository) ReplaceForSemester(ctx context.Context, semesterID uuid.UUID, forecasts []*domain.EnrollmentForecast) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("semester_id = ?", semesterID).Delete(&domain.EnrollmentForecast{}).Error; err != nil {
+			return err
+		}
+		if len(forecasts) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(forecasts, 500).Error
+	})
+}
+
+func (r *EnrollmentForecastRepository) GetBySemester(ctx context.Context, semesterID uuid.UUID, department string) ([]*domain.EnrollmentForecast, error) {
+	query := r.db.WithContext(ctx).Where("semester_id = ?", semesterID)
+	if department != "" {
+		query = query.Where("department = ?", department)
+	}
+
+	var forecasts []*domain.EnrollmentForecast
+	if err := query.Order("course_code ASC, section_number ASC").Find(&forecasts).Error; err != nil {
+		return nil, err
+	}
+	return forecasts, nil
+}
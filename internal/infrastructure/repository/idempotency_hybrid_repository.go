@@ -0,0 +1,97 @@
+package repository
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var _ interfaces.IdempotencyRepository = (*HybridIdempotencyRepository)(nil)
+
+// HybridIdempotencyRepository keeps idempotency keys in Redis for fast lookups and writes
+// them through to Postgres, so a Redis flush or failover does not forget which requests were
+// already processed. Reads fall back to Postgres on a Redis miss and put the key back in
+// Redis for the rest of its life.
+type HybridIdempotencyRepository struct {
+	redis *RedisIdempotencyRepository
+	db    *IdempotencyRepository
+}
+
+func NewHybridIdempotencyRepository(redis *RedisIdempotencyRepository, db *IdempotencyRepository) *HybridIdempotencyRepository {
+	return &HybridIdempotencyRepository{redis: redis, db: db}
+}
+
+func (r *HybridIdempotencyRepository) Create(ctx context.Context, key *domain.IdempotencyKey) error {
+	if err := r.db.Create(ctx, key); err != nil {
+		return fmt.Errorf("failed to store idempotency key in Postgres: %w", err)
+	}
+	return r.redis.Create(ctx, key)
+}
+
+// Reserve takes the key in Redis, then in Postgres. A key Redis no longer has but Postgres
+// still holds is not reserved; the Redis reservation is dropped again so that reads find the
+// Postgres record.
+func (r *HybridIdempotencyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, ttl time.Duration) (bool, error) {
+	reserved, err := r.redis.Reserve(ctx, key, ttl)
+	if err != nil || !reserved {
+		return reserved, err
+	}
+
+	reserved, err = r.db.Reserve(ctx, key, ttl)
+	if err == nil && reserved {
+		return true, nil
+	}
+	if delErr := r.redis.Delete(ctx, key.Key); delErr != nil {
+		logger.Warn("Failed to release Redis reservation of idempotency key %s: %v", key.Key, delErr)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key in Postgres: %w", err)
+	}
+	return false, nil
+}
+
+func (r *HybridIdempotencyRepository) GetByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error) {
+	cached, err := r.redis.GetByKey(ctx, key)
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, ErrIdempotencyKeyNotFound) {
+		logger.Warn("Failed to read idempotency key %s from Redis, reading Postgres: %v", key, err)
+	}
+
+	stored, err := r.db.GetByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key from Postgres: %w", err)
+	}
+	if stored == nil || stored.IsExpired() {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+
+	if err := r.redis.SetWithTTL(ctx, stored, time.Until(stored.ExpiresAt)); err != nil {
+		logger.Warn("Failed to restore idempotency key %s to Redis: %v", key, err)
+	}
+	return stored, nil
+}
+
+// GetKeysByStudentID reads Postgres, where the student's keys are indexed
+func (r *HybridIdempotencyRepository) GetKeysByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.IdempotencyKey, error) {
+	return r.db.GetByStudentID(ctx, studentID)
+}
+
+// DeleteExpired prunes Postgres; Redis expires its copies itself
+func (r *HybridIdempotencyRepository) DeleteExpired(ctx context.Context) error {
+	return r.db.DeleteExpired(ctx)
+}
+
+func (r *HybridIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	if err := r.db.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key from Postgres: %w", err)
+	}
+	return r.redis.Delete(ctx, key)
+}
@@ -47,6 +47,19 @@ func (r *RedisIdempotencyRepository) Create(ctx context.Context, key *domain.Ide
 	return nil
 }
 
+func (r *RedisIdempotencyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal idempotency key: %w", err)
+	}
+
+	reserved, err := r.client.SetNX(ctx, r.getRedisKey(key.Key), string(data), ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key in Redis: %w", err)
+	}
+	return reserved, nil
+}
+
 func (r *RedisIdempotencyRepository) GetByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error) {
 	redisKey := r.getRedisKey(key)
 
@@ -121,47 +134,45 @@ func (r *RedisIdempotencyRepository) Exists(ctx context.Context, key string) (bo
 	return exists > 0, nil
 }
 
+// GetKeysByStudentID scans every stored key for the student's. Each page of the scan is read
+// as it comes, so no page is dropped; the hybrid store answers this from Postgres instead.
 func (r *RedisIdempotencyRepository) GetKeysByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.IdempotencyKey, error) {
-
 	pattern := r.prefix + "*"
 
+	var result []*domain.IdempotencyKey
 	var cursor uint64
-	var keys []string
-
 	for {
-		var err error
-		keys, cursor, err = r.client.Scan(ctx, cursor, pattern, 100).Result()
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, 100).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan Redis keys: %w", err)
 		}
 
-		if cursor == 0 {
-			break
-		}
-	}
+		if len(keys) > 0 {
+			values, err := r.client.MGet(ctx, keys...).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get multiple keys from Redis: %w", err)
+			}
 
-	var result []*domain.IdempotencyKey
+			for _, val := range values {
+				raw, ok := val.(string)
+				if !ok {
+					continue
+				}
 
-	if len(keys) > 0 {
-		values, err := r.client.MGet(ctx, keys...).Result()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get multiple keys from Redis: %w", err)
-		}
+				var idempotencyKey domain.IdempotencyKey
+				if err := json.Unmarshal([]byte(raw), &idempotencyKey); err != nil {
+					continue
+				}
 
-		for _, val := range values {
-			if val == nil {
-				continue
-			}
-
-			var idempotencyKey domain.IdempotencyKey
-			err = json.Unmarshal([]byte(val.(string)), &idempotencyKey)
-			if err != nil {
-				continue
+				if idempotencyKey.StudentID == studentID {
+					result = append(result, &idempotencyKey)
+				}
 			}
+		}
 
-			if idempotencyKey.StudentID == studentID {
-				result = append(result, &idempotencyKey)
-			}
+		cursor = next
+		if cursor == 0 {
+			break
 		}
 	}
 
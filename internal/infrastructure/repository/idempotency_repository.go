@@ -6,9 +6,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// idempotencyPruneBatch is how many expired keys DeleteExpired removes per statement
+const idempotencyPruneBatch = 1000
+
 var _ interfaces.IdempotencyRepository = (*IdempotencyRepository)(nil)
 
 type IdempotencyRepository struct {
@@ -19,21 +24,76 @@ func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
 	return &IdempotencyRepository{db: db}
 }
 
+// Create stores key, replacing the reservation or earlier record under the same key
 func (r *IdempotencyRepository) Create(ctx context.Context, key *domain.IdempotencyKey) error {
-	return r.db.WithContext(ctx).Create(key).Error
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(key).Error
+}
+
+// Reserve first clears an expired record under the key, since rows outlive their expiry
+// until DeleteExpired runs
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, ttl time.Duration) (bool, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("key = ? AND expires_at < ?", key.Key, time.Now()).Delete(&domain.IdempotencyKey{}).Error; err != nil {
+		return false, err
+	}
+
+	key.ExpiresAt = time.Now().Add(ttl)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
 }
 
 func (r *IdempotencyRepository) GetByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error) {
 	var idempotencyKey domain.IdempotencyKey
 	err := r.db.WithContext(ctx).Where("key = ?", key).First(&idempotencyKey).Error
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &idempotencyKey, nil
 }
 
+// GetByStudentID returns the student's unexpired keys, newest first
+func (r *IdempotencyRepository) GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.IdempotencyKey, error) {
+	var keys []*domain.IdempotencyKey
+	err := r.db.WithContext(ctx).
+		Where("student_id = ? AND expires_at >= ?", studentID, time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteExpired removes every expired key, a batch at a time
 func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) error {
-	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.IdempotencyKey{}).Error
+	for {
+		deleted, err := r.PruneExpired(ctx, time.Now(), idempotencyPruneBatch)
+		if err != nil {
+			return err
+		}
+		if deleted < idempotencyPruneBatch {
+			return nil
+		}
+	}
+}
+
+// PruneExpired removes up to limit keys that expired before the given time and returns how
+// many it removed. Small batches keep each statement's locks short on a busy table.
+func (r *IdempotencyRepository) PruneExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(
+		`DELETE FROM idempotency_keys WHERE key IN (
+			SELECT key FROM idempotency_keys WHERE expires_at < ? ORDER BY expires_at LIMIT ?
+		)`, before, limit)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
 }
 
 func (r *IdempotencyRepository) Delete(ctx context.Context, key string) error {
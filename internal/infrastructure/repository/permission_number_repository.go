@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PermissionNumberRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionNumberRepository(db *gorm.DB) interfaces.PermissionNumberRepository {
+	return &PermissionNumberRepository{
+		db: db,
+	}
+}
+
+func (r *PermissionNumberRepository) Create(ctx context.Context, permissions []*domain.PermissionNumber) error {
+	return r.db.WithContext(ctx).Create(&permissions).Error
+}
+
+func (r *PermissionNumberRepository) GetByCode(ctx context.Context, code string) (*domain.PermissionNumber, error) {
+	var permission domain.PermissionNumber
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&permission).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *PermissionNumberRepository) ListBySection(ctx context.Context, sectionID uuid.UUID) ([]*domain.PermissionNumber, error) {
+	var permissions []*domain.PermissionNumber
+	err := r.db.WithContext(ctx).
+		Where("section_id = ?", sectionID).
+		Order("created_at ASC").
+		Find(&permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *PermissionNumberRepository) MarkUsed(ctx context.Context, permissionID, studentID uuid.UUID, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PermissionNumber{}).
+		Where("permission_id = ? AND used_at IS NULL", permissionID).
+		Updates(map[string]any{
+			"used_by": studentID,
+			"used_at": usedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
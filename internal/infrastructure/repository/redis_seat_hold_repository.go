@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	seatHoldExpiryIndexKey = "seat_holds:expiry"
+	// seatHoldRetention keeps resolved holds readable for a while after they expire
+	seatHoldRetention = 24 * time.Hour
+)
+
+// transitionSeatHoldScript moves a hold out of "held" and clears its expiry entry and the
+// student's active hold pointer in the same step, so a sweep and a confirm cannot both win.
+var transitionSeatHoldScript = redis.NewScript(`
+	local status = redis.call("HGET", KEYS[1], "status")
+	if status == false or status ~= ARGV[1] then
+		return 0
+	end
+	redis.call("HSET", KEYS[1], "status", ARGV[2], "updated_at", ARGV[3])
+	if ARGV[2] ~= "held" then
+		redis.call("ZREM", KEYS[2], ARGV[4])
+		if redis.call("GET", KEYS[3]) == ARGV[4] then
+			redis.call("DEL", KEYS[3])
+		end
+	end
+	return 1
+`)
+
+type RedisSeatHoldRepository struct {
+	client redis.UniversalClient
+}
+
+func NewRedisSeatHoldRepository(client redis.UniversalClient) interfaces.SeatHoldRepository {
+	return &RedisSeatHoldRepository{
+		client: client,
+	}
+}
+
+func (r *RedisSeatHoldRepository) Create(ctx context.Context, hold *domain.SeatHold) error {
+	holdKey := seatHoldKey(hold.HoldID)
+	activeKey := activeSeatHoldKey(hold.StudentID, hold.SectionID)
+	retention := time.Until(hold.ExpiresAt) + seatHoldRetention
+
+	pipe := r.client.TxPipeline()
+
+	pipe.HSet(ctx, holdKey, map[string]interface{}{
+		"hold_id":    hold.HoldID.String(),
+		"student_id": hold.StudentID.String(),
+		"section_id": hold.SectionID.String(),
+		"status":     string(hold.Status),
+		"expires_at": hold.ExpiresAt.UnixNano(),
+		"created_at": hold.CreatedAt.UnixNano(),
+		"updated_at": hold.UpdatedAt.UnixNano(),
+	})
+	pipe.Expire(ctx, holdKey, retention)
+
+	pipe.ZAdd(ctx, seatHoldExpiryIndexKey, &redis.Z{
+		Score:  float64(hold.ExpiresAt.Unix()),
+		Member: hold.HoldID.String(),
+	})
+
+	pipe.Set(ctx, activeKey, hold.HoldID.String(), retention)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create seat hold in Redis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisSeatHoldRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SeatHold, error) {
+	fields, err := r.client.HGetAll(ctx, seatHoldKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat hold: %w", err)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return parseSeatHold(fields)
+}
+
+func (r *RedisSeatHoldRepository) GetActive(ctx context.Context, studentID, sectionID uuid.UUID) (*domain.SeatHold, error) {
+	holdID, err := r.client.Get(ctx, activeSeatHoldKey(studentID, sectionID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get active seat hold: %w", err)
+	}
+
+	id, err := uuid.Parse(holdID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid active seat hold id: %w", err)
+	}
+
+	hold, err := r.GetByID(ctx, id)
+	if err != nil || hold == nil || hold.Status != domain.HoldStatusHeld {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (r *RedisSeatHoldRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.SeatHoldStatus) (bool, error) {
+	hold, err := r.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if hold == nil {
+		return false, nil
+	}
+
+	keys := []string{seatHoldKey(id), seatHoldExpiryIndexKey, activeSeatHoldKey(hold.StudentID, hold.SectionID)}
+	args := []interface{}{string(from), string(to), time.Now().UnixNano(), id.String()}
+
+	result, err := transitionSeatHoldScript.Run(ctx, r.client, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to transition seat hold %s: %w", id, err)
+	}
+
+	return result == 1, nil
+}
+
+func (r *RedisSeatHoldRepository) PopExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	keys := []string{seatHoldExpiryIndexKey}
+	args := []interface{}{before.Unix(), limit}
+
+	members, err := popExpiredSeatOffersScript.Run(ctx, r.client, keys, args...).StringSlice()
+	if err != nil {
+		if err == redis.Nil {
+			return []uuid.UUID{}, nil
+		}
+		return nil, fmt.Errorf("failed to pop expired seat holds: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func seatHoldKey(id uuid.UUID) string {
+	return fmt.Sprintf("seat_hold:%s", id.String())
+}
+
+func activeSeatHoldKey(studentID, sectionID uuid.UUID) string {
+	return fmt.Sprintf("seat_hold:active:%s:%s", studentID.String(), sectionID.String())
+}
+
+func parseSeatHold(fields map[string]string) (*domain.SeatHold, error) {
+	var hold domain.SeatHold
+	var err error
+
+	if hold.HoldID, err = uuid.Parse(fields["hold_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat hold id: %w", err)
+	}
+	if hold.StudentID, err = uuid.Parse(fields["student_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat hold student id: %w", err)
+	}
+	if hold.SectionID, err = uuid.Parse(fields["section_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat hold section id: %w", err)
+	}
+
+	hold.Status = domain.SeatHoldStatus(fields["status"])
+	hold.ExpiresAt = parseUnixNano(fields["expires_at"])
+	hold.CreatedAt = parseUnixNano(fields["created_at"])
+	hold.UpdatedAt = parseUnixNano(fields["updated_at"])
+
+	return &hold, nil
+}
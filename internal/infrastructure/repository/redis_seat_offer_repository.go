@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	seatOfferExpiryIndexKey = "seat_offers:expiry"
+	// seatOfferRetention keeps resolved offers readable for a while after they expire
+	seatOfferRetention = 7 * 24 * time.Hour
+)
+
+var transitionSeatOfferScript = redis.NewScript(`
+	local status = redis.call("HGET", KEYS[1], "status")
+	if status == false or status ~= ARGV[1] then
+		return 0
+	end
+	redis.call("HSET", KEYS[1], "status", ARGV[2], "updated_at", ARGV[3])
+	if ARGV[2] ~= "pending" then
+		redis.call("ZREM", KEYS[2], ARGV[4])
+	end
+	return 1
+`)
+
+var popExpiredSeatOffersScript = redis.NewScript(`
+	local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, tonumber(ARGV[2]))
+	if #ids > 0 then
+		redis.call("ZREM", KEYS[1], unpack(ids))
+	end
+	return ids
+`)
+
+type RedisSeatOfferRepository struct {
+	client redis.UniversalClient
+}
+
+func NewRedisSeatOfferRepository(client redis.UniversalClient) interfaces.SeatOfferRepository {
+	return &RedisSeatOfferRepository{
+		client: client,
+	}
+}
+
+func (r *RedisSeatOfferRepository) Create(ctx context.Context, offer *domain.SeatOffer) error {
+	offerKey := seatOfferKey(offer.OfferID)
+	studentOffersKey := fmt.Sprintf("seat_offers:student:%s", offer.StudentID.String())
+	retention := time.Until(offer.ExpiresAt) + seatOfferRetention
+
+	fields := map[string]interface{}{
+		"offer_id":    offer.OfferID.String(),
+		"student_id":  offer.StudentID.String(),
+		"section_id":  offer.SectionID.String(),
+		"waitlist_id": offer.WaitlistID.String(),
+		"status":      string(offer.Status),
+		"expires_at":  offer.ExpiresAt.UnixNano(),
+		"created_at":  offer.CreatedAt.UnixNano(),
+		"updated_at":  offer.UpdatedAt.UnixNano(),
+	}
+	if offer.SeatPoolID != nil {
+		fields["seat_pool_id"] = offer.SeatPoolID.String()
+	}
+
+	pipe := r.client.TxPipeline()
+
+	pipe.HSet(ctx, offerKey, fields)
+	pipe.Expire(ctx, offerKey, retention)
+
+	pipe.ZAdd(ctx, seatOfferExpiryIndexKey, &redis.Z{
+		Score:  float64(offer.ExpiresAt.Unix()),
+		Member: offer.OfferID.String(),
+	})
+
+	pipe.SAdd(ctx, studentOffersKey, offer.OfferID.String())
+	pipe.Expire(ctx, studentOffersKey, retention)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to create seat offer in Redis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisSeatOfferRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SeatOffer, error) {
+	fields, err := r.client.HGetAll(ctx, seatOfferKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat offer: %w", err)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return parseSeatOffer(fields)
+}
+
+func (r *RedisSeatOfferRepository) GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.SeatOffer, error) {
+	studentOffersKey := fmt.Sprintf("seat_offers:student:%s", studentID.String())
+
+	offerIDs, err := r.client.SMembers(ctx, studentOffersKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student seat offers: %w", err)
+	}
+
+	if len(offerIDs) == 0 {
+		return []*domain.SeatOffer{}, nil
+	}
+
+	pipe := r.client.Pipeline()
+	offerCommands := make([]*redis.StringStringMapCmd, len(offerIDs))
+
+	for i, offerID := range offerIDs {
+		offerCommands[i] = pipe.HGetAll(ctx, fmt.Sprintf("seat_offer:%s", offerID))
+	}
+
+	_, err = pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get seat offers: %w", err)
+	}
+
+	offers := make([]*domain.SeatOffer, 0, len(offerIDs))
+	for i, cmd := range offerCommands {
+		fields, err := cmd.Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get seat offer data: %w", err)
+		}
+
+		if len(fields) == 0 {
+
+			r.client.SRem(ctx, studentOffersKey, offerIDs[i])
+			continue
+		}
+
+		offer, err := parseSeatOffer(fields)
+		if err != nil {
+			return nil, err
+		}
+
+		offers = append(offers, offer)
+	}
+
+	return offers, nil
+}
+
+func (r *RedisSeatOfferRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.SeatOfferStatus) (bool, error) {
+	keys := []string{seatOfferKey(id), seatOfferExpiryIndexKey}
+	args := []interface{}{string(from), string(to), time.Now().UnixNano(), id.String()}
+
+	result, err := transitionSeatOfferScript.Run(ctx, r.client, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to transition seat offer %s: %w", id, err)
+	}
+
+	return result == 1, nil
+}
+
+func (r *RedisSeatOfferRepository) PopExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	keys := []string{seatOfferExpiryIndexKey}
+	args := []interface{}{before.Unix(), limit}
+
+	members, err := popExpiredSeatOffersScript.Run(ctx, r.client, keys, args...).StringSlice()
+	if err != nil {
+		if err == redis.Nil {
+			return []uuid.UUID{}, nil
+		}
+		return nil, fmt.Errorf("failed to pop expired seat offers: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func seatOfferKey(id uuid.UUID) string {
+	return fmt.Sprintf("seat_offer:%s", id.String())
+}
+
+func parseSeatOffer(fields map[string]string) (*domain.SeatOffer, error) {
+	var offer domain.SeatOffer
+	var err error
+
+	if offer.OfferID, err = uuid.Parse(fields["offer_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat offer id: %w", err)
+	}
+	if offer.StudentID, err = uuid.Parse(fields["student_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat offer student id: %w", err)
+	}
+	if offer.SectionID, err = uuid.Parse(fields["section_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat offer section id: %w", err)
+	}
+	if offer.WaitlistID, err = uuid.Parse(fields["waitlist_id"]); err != nil {
+		return nil, fmt.Errorf("invalid seat offer waitlist id: %w", err)
+	}
+	if value := fields["seat_pool_id"]; value != "" {
+		pool, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seat offer seat pool id: %w", err)
+		}
+		offer.SeatPoolID = &pool
+	}
+
+	offer.Status = domain.SeatOfferStatus(fields["status"])
+	offer.ExpiresAt = parseUnixNano(fields["expires_at"])
+	offer.CreatedAt = parseUnixNano(fields["created_at"])
+	offer.UpdatedAt = parseUnixNano(fields["updated_at"])
+
+	return &offer, nil
+}
+
+func parseUnixNano(value string) time.Time {
+	nanos, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
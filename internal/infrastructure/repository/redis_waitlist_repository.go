@@ -3,16 +3,89 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	domain "cobra-template/internal/domain/registration"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 )
 
+const (
+	redisWaitlistEntryTTL = 24 * time.Hour
+	// redisWaitlistExpiryKey is a sorted set of the IDs of entries that expire, scored by
+	// their expiry in Unix milliseconds
+	redisWaitlistExpiryKey = "waitlist:expiring"
+	// renumberAttempts bounds how often RenumberPositions re-reads a waitlist that changed
+	// between reading and writing it
+	renumberAttempts = 3
+)
+
+// createWaitlistEntryScript writes the sorted set member, entry document, student index and
+// student/section mapping in one step, and indexes the entry's expiry when ARGV[6] holds
+// one. A mapping left behind by an earlier entry for the same student and section is
+// replaced and its sorted set member, expiry and document are removed, so a retry after a
+// partial write cannot leave two entries in the queue.
+var createWaitlistEntryScript = redis.NewScript(`
+	local previous = redis.call("GET", KEYS[4])
+	if previous and previous ~= ARGV[2] then
+		redis.call("ZREM", KEYS[1], previous)
+		redis.call("ZREM", KEYS[5], previous)
+		redis.call("DEL", "waitlist:entry:" .. previous)
+	end
+
+	redis.call("ZADD", KEYS[1], ARGV[1], ARGV[2])
+	redis.call("SET", KEYS[2], ARGV[3], "PX", ARGV[5])
+	redis.call("SADD", KEYS[3], ARGV[4])
+	redis.call("PEXPIRE", KEYS[3], ARGV[5])
+	redis.call("SET", KEYS[4], ARGV[2], "PX", ARGV[5])
+	if ARGV[6] ~= "" then
+		redis.call("ZADD", KEYS[5], ARGV[6], ARGV[2])
+	end
+	return 1
+`)
+
+// removeWaitlistEntryScript removes every trace of waitlist entry ARGV[1]. The mapping and
+// student index are only touched when they do not already belong to a newer entry for the
+// same student and section. It is used both for deletes and to repair partial state.
+var removeWaitlistEntryScript = redis.NewScript(`
+	local removed = redis.call("ZREM", KEYS[1], ARGV[1])
+	removed = removed + redis.call("DEL", KEYS[2])
+	redis.call("ZREM", KEYS[5], ARGV[1])
+
+	local mapped = redis.call("GET", KEYS[4])
+	if mapped == false or mapped == ARGV[1] then
+		removed = removed + redis.call("DEL", KEYS[4])
+		removed = removed + redis.call("SREM", KEYS[3], ARGV[2])
+	end
+	return removed
+`)
+
+// renumberWaitlistScript applies new positions only if every member still has the score it
+// was read with. ARGV[1] is the entry TTL; the rest are id, old score, new position, entry
+// document groups. It returns 0 without writing anything when the waitlist changed.
+var renumberWaitlistScript = redis.NewScript(`
+	for i = 2, #ARGV, 4 do
+		local score = redis.call("ZSCORE", KEYS[1], ARGV[i])
+		if score == false or tonumber(score) ~= tonumber(ARGV[i + 1]) then
+			return 0
+		end
+	end
+
+	for i = 2, #ARGV, 4 do
+		redis.call("ZADD", KEYS[1], ARGV[i + 2], ARGV[i])
+		redis.call("SET", "waitlist:entry:" .. ARGV[i], ARGV[i + 3], "PX", ARGV[1])
+	end
+	return 1
+`)
+
+var errWaitlistChanged = errors.New("waitlist changed while it was being renumbered")
+
 type RedisWaitlistRepository struct {
 	client redis.UniversalClient
 }
@@ -24,35 +97,27 @@ func NewRedisWaitlistRepository(client redis.UniversalClient) interfaces.Waitlis
 }
 
 func (r *RedisWaitlistRepository) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
-
-	waitlistKey := fmt.Sprintf("waitlist:section:%s", entry.SectionID.String())
-
-	entryKey := fmt.Sprintf("waitlist:entry:%s", entry.WaitlistID.String())
-
-	studentWaitlistKey := fmt.Sprintf("waitlist:student:%s", entry.StudentID.String())
-
 	entryData, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal waitlist entry: %w", err)
 	}
 
-	pipe := r.client.Pipeline()
-
-	pipe.ZAdd(ctx, waitlistKey, &redis.Z{
-		Score:  float64(entry.Position),
-		Member: entry.WaitlistID.String(),
-	})
-
-	pipe.Set(ctx, entryKey, entryData, 24*time.Hour)
-
-	pipe.SAdd(ctx, studentWaitlistKey, entry.SectionID.String())
-	pipe.Expire(ctx, studentWaitlistKey, 24*time.Hour)
+	expiresAt := ""
+	if entry.ExpiresAt != nil {
+		expiresAt = strconv.FormatInt(entry.ExpiresAt.UnixMilli(), 10)
+	}
 
-	studentSectionKey := fmt.Sprintf("waitlist:mapping:%s:%s", entry.StudentID.String(), entry.SectionID.String())
-	pipe.Set(ctx, studentSectionKey, entry.WaitlistID.String(), 24*time.Hour)
+	keys := waitlistEntryKeys(entry.WaitlistID, entry.StudentID, entry.SectionID)
+	args := []interface{}{
+		entry.Position,
+		entry.WaitlistID.String(),
+		entryData,
+		entry.SectionID.String(),
+		redisWaitlistEntryTTL.Milliseconds(),
+		expiresAt,
+	}
 
-	_, err = pipe.Exec(ctx)
-	if err != nil {
+	if err := createWaitlistEntryScript.Run(ctx, r.client, keys, args...).Err(); err != nil {
 		return fmt.Errorf("failed to create waitlist entry in Redis: %w", err)
 	}
 
@@ -74,6 +139,10 @@ func (r *RedisWaitlistRepository) GetByStudentAndSection(ctx context.Context, st
 	entryData, err := r.client.Get(ctx, entryKey).Result()
 	if err != nil {
 		if err == redis.Nil {
+			// The mapping outlived its entry; drop the leftovers so the student can re-join
+			if id, parseErr := uuid.Parse(waitlistID); parseErr == nil {
+				r.repairEntry(ctx, id, studentID, sectionID)
+			}
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
@@ -147,21 +216,7 @@ func (r *RedisWaitlistRepository) Delete(ctx context.Context, id uuid.UUID) erro
 		return fmt.Errorf("failed to unmarshal waitlist entry for deletion: %w", err)
 	}
 
-	pipe := r.client.Pipeline()
-
-	waitlistKey := fmt.Sprintf("waitlist:section:%s", entry.SectionID.String())
-	pipe.ZRem(ctx, waitlistKey, id.String())
-
-	pipe.Del(ctx, entryKey)
-
-	studentWaitlistKey := fmt.Sprintf("waitlist:student:%s", entry.StudentID.String())
-	pipe.SRem(ctx, studentWaitlistKey, entry.SectionID.String())
-
-	studentSectionKey := fmt.Sprintf("waitlist:mapping:%s:%s", entry.StudentID.String(), entry.SectionID.String())
-	pipe.Del(ctx, studentSectionKey)
-
-	_, err = pipe.Exec(ctx)
-	if err != nil {
+	if _, err := r.removeEntry(ctx, id, entry.StudentID, entry.SectionID); err != nil {
 		return fmt.Errorf("failed to delete waitlist entry from Redis: %w", err)
 	}
 
@@ -169,14 +224,22 @@ func (r *RedisWaitlistRepository) Delete(ctx context.Context, id uuid.UUID) erro
 }
 
 func (r *RedisWaitlistRepository) GetBySectionID(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	entries, _, err := r.loadSectionEntries(ctx, sectionID)
+	return entries, err
+}
+
+// loadSectionEntries returns the section's entries in queue order along with the sorted set
+// score each one was read with. Members whose document has expired are removed.
+func (r *RedisWaitlistRepository) loadSectionEntries(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, []float64, error) {
 	waitlistKey := fmt.Sprintf("waitlist:section:%s", sectionID.String())
 
 	result, err := r.client.ZRangeWithScores(ctx, waitlistKey, 0, -1).Result()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get section waitlist: %w", err)
+		return nil, nil, fmt.Errorf("failed to get section waitlist: %w", err)
 	}
 
 	entries := make([]*domain.WaitlistEntry, 0, len(result))
+	scores := make([]float64, 0, len(result))
 
 	pipe := r.client.Pipeline()
 	entryCommands := make([]*redis.StringCmd, len(result))
@@ -187,9 +250,10 @@ func (r *RedisWaitlistRepository) GetBySectionID(ctx context.Context, sectionID
 		entryCommands[i] = pipe.Get(ctx, entryKey)
 	}
 
+	// Exec reports the first failed command; missing documents are handled per command below
 	_, err = pipe.Exec(ctx)
 	if err != nil && err != redis.Nil {
-		return nil, fmt.Errorf("failed to get waitlist entry details: %w", err)
+		return nil, nil, fmt.Errorf("failed to get waitlist entry details: %w", err)
 	}
 
 	for i, cmd := range entryCommands {
@@ -201,18 +265,19 @@ func (r *RedisWaitlistRepository) GetBySectionID(ctx context.Context, sectionID
 				r.client.ZRem(ctx, waitlistKey, waitlistID)
 				continue
 			}
-			return nil, fmt.Errorf("failed to get waitlist entry data: %w", err)
+			return nil, nil, fmt.Errorf("failed to get waitlist entry data: %w", err)
 		}
 
 		var entry domain.WaitlistEntry
 		if err := json.Unmarshal([]byte(entryData), &entry); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal waitlist entry: %w", err)
+			return nil, nil, fmt.Errorf("failed to unmarshal waitlist entry: %w", err)
 		}
 
 		entries = append(entries, &entry)
+		scores = append(scores, result[i].Score)
 	}
 
-	return entries, nil
+	return entries, scores, nil
 }
 
 func (r *RedisWaitlistRepository) GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.WaitlistEntry, error) {
@@ -245,6 +310,7 @@ func (r *RedisWaitlistRepository) GetByStudentID(ctx context.Context, studentID
 	pipe = r.client.Pipeline()
 	entryCommands := make([]*redis.StringCmd, 0, len(sectionIDs))
 	validIndices := make([]int, 0, len(sectionIDs))
+	waitlistIDs := make([]string, 0, len(sectionIDs))
 
 	for i, cmd := range mappingCommands {
 		waitlistID, err := cmd.Result()
@@ -260,6 +326,7 @@ func (r *RedisWaitlistRepository) GetByStudentID(ctx context.Context, studentID
 		entryKey := fmt.Sprintf("waitlist:entry:%s", waitlistID)
 		entryCommands = append(entryCommands, pipe.Get(ctx, entryKey))
 		validIndices = append(validIndices, i)
+		waitlistIDs = append(waitlistIDs, waitlistID)
 	}
 
 	_, err = pipe.Exec(ctx)
@@ -271,9 +338,13 @@ func (r *RedisWaitlistRepository) GetByStudentID(ctx context.Context, studentID
 		entryData, err := cmd.Result()
 		if err != nil {
 			if err == redis.Nil {
-
-				sectionIdx := validIndices[i]
-				r.client.SRem(ctx, studentWaitlistKey, sectionIDs[sectionIdx])
+				sectionID, parseErr := uuid.Parse(sectionIDs[validIndices[i]])
+				waitlistID, idErr := uuid.Parse(waitlistIDs[i])
+				if parseErr != nil || idErr != nil {
+					r.client.SRem(ctx, studentWaitlistKey, sectionIDs[validIndices[i]])
+					continue
+				}
+				r.repairEntry(ctx, waitlistID, studentID, sectionID)
 				continue
 			}
 			return nil, fmt.Errorf("failed to get waitlist entry data: %w", err)
@@ -324,7 +395,139 @@ func (r *RedisWaitlistRepository) GetWaitlistPosition(ctx context.Context, stude
 	return int(rank) + 1, nil
 }
 
-func (r *RedisWaitlistRepository) CleanupExpiredEntries(ctx context.Context) error {
+// RenumberPositions computes the new positions from a snapshot of the waitlist and applies
+// them in one script that first checks the snapshot is still current. If another request
+// changed the waitlist in between, the snapshot is re-read.
+func (r *RedisWaitlistRepository) RenumberPositions(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	for attempt := 0; attempt < renumberAttempts; attempt++ {
+		moved, err := r.renumberPositions(ctx, sectionID)
+		if errors.Is(err, errWaitlistChanged) {
+			continue
+		}
+		return moved, err
+	}
 
-	return nil
+	return nil, fmt.Errorf("failed to renumber waitlist positions in Redis: %w", errWaitlistChanged)
+}
+
+func (r *RedisWaitlistRepository) renumberPositions(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	entries, scores, err := r.loadSectionEntries(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{redisWaitlistEntryTTL.Milliseconds()}
+	var moved []*domain.WaitlistEntry
+
+	for i, entry := range entries {
+		position := i + 1
+		if entry.Position == position && scores[i] == float64(position) {
+			continue
+		}
+
+		entry.Position = position
+		entry.UpdatedAt = time.Now()
+		entryData, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal waitlist entry: %w", err)
+		}
+
+		args = append(args,
+			entry.WaitlistID.String(),
+			strconv.FormatFloat(scores[i], 'f', -1, 64),
+			position,
+			entryData,
+		)
+		moved = append(moved, entry)
+	}
+
+	if len(moved) == 0 {
+		return nil, nil
+	}
+
+	waitlistKey := fmt.Sprintf("waitlist:section:%s", sectionID.String())
+	applied, err := renumberWaitlistScript.Run(ctx, r.client, []string{waitlistKey}, args...).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to renumber waitlist positions in Redis: %w", err)
+	}
+	if applied == 0 {
+		return nil, errWaitlistChanged
+	}
+
+	return moved, nil
+}
+
+func (r *RedisWaitlistRepository) removeEntry(ctx context.Context, waitlistID, studentID, sectionID uuid.UUID) (int, error) {
+	keys := waitlistEntryKeys(waitlistID, studentID, sectionID)
+	return removeWaitlistEntryScript.Run(ctx, r.client, keys, waitlistID.String(), sectionID.String()).Int()
+}
+
+// repairEntry clears what is left of an entry found half-written or half-removed, for example
+// a mapping whose document expired or a write interrupted before the script-based writes
+func (r *RedisWaitlistRepository) repairEntry(ctx context.Context, waitlistID, studentID, sectionID uuid.UUID) {
+	removed, err := r.removeEntry(ctx, waitlistID, studentID, sectionID)
+	if err != nil {
+		logger.Warn("Failed to repair partial waitlist entry %s for student %s in section %s: %v", waitlistID, studentID, sectionID, err)
+		return
+	}
+	if removed > 0 {
+		logger.Warn("Repaired partial waitlist entry %s for student %s in section %s", waitlistID, studentID, sectionID)
+	}
+}
+
+// waitlistEntryKeys returns the section sorted set, entry document, student index,
+// student/section mapping and expiry index keys in the order the waitlist scripts expect
+func waitlistEntryKeys(waitlistID, studentID, sectionID uuid.UUID) []string {
+	return []string{
+		fmt.Sprintf("waitlist:section:%s", sectionID.String()),
+		fmt.Sprintf("waitlist:entry:%s", waitlistID.String()),
+		fmt.Sprintf("waitlist:student:%s", studentID.String()),
+		fmt.Sprintf("waitlist:mapping:%s:%s", studentID.String(), sectionID.String()),
+		redisWaitlistExpiryKey,
+	}
+}
+
+// GetExpired reads the expiry index. IDs whose document has gone are dropped from it.
+func (r *RedisWaitlistRepository) GetExpired(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error) {
+	waitlistIDs, err := r.client.ZRangeByScore(ctx, redisWaitlistExpiryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "(" + strconv.FormatInt(before.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired waitlist entries: %w", err)
+	}
+	if len(waitlistIDs) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	entryCommands := make([]*redis.StringCmd, len(waitlistIDs))
+	for i, waitlistID := range waitlistIDs {
+		entryCommands[i] = pipe.Get(ctx, fmt.Sprintf("waitlist:entry:%s", waitlistID))
+	}
+	_, err = pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get expired waitlist entries: %w", err)
+	}
+
+	entries := make([]*domain.WaitlistEntry, 0, len(waitlistIDs))
+	for i, cmd := range entryCommands {
+		entryData, err := cmd.Result()
+		if err != nil {
+			if err == redis.Nil {
+				r.client.ZRem(ctx, redisWaitlistExpiryKey, waitlistIDs[i])
+				continue
+			}
+			return nil, fmt.Errorf("failed to get waitlist entry data: %w", err)
+		}
+
+		var entry domain.WaitlistEntry
+		if err := json.Unmarshal([]byte(entryData), &entry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal waitlist entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
 }
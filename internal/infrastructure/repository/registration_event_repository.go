@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RegistrationEventRepository struct {
+	db *gorm.DB
+}
+
+func NewRegistrationEventRepository(db *gorm.DB) interfaces.RegistrationEventRepository {
+	return &RegistrationEventRepository{
+		db: db,
+	}
+}
+
+func (r *RegistrationEventRepository) Append(ctx context.Context, event *domain.RegistrationEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *RegistrationEventRepository) GetBySection(ctx context.Context, sectionID uuid.UUID, afterSequence int64, until time.Time) ([]*domain.RegistrationEvent, error) {
+	var events []*domain.RegistrationEvent
+	query := r.db.WithContext(ctx).
+		Where("section_id = ? AND sequence > ?", sectionID, afterSequence)
+	if !until.IsZero() {
+		query = query.Where("occurred_at <= ?", until)
+	}
+
+	err := query.Order("sequence ASC").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *RegistrationEventRepository) CountSince(ctx context.Context, sectionID uuid.UUID, afterSequence int64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.RegistrationEvent{}).
+		Where("section_id = ? AND sequence > ?", sectionID, afterSequence).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *RegistrationEventRepository) GetLatestSnapshot(ctx context.Context, sectionID uuid.UUID, asOf time.Time) (*domain.RegistrationSnapshot, error) {
+	var snapshot domain.RegistrationSnapshot
+	err := r.db.WithContext(ctx).
+		Where("section_id = ? AND as_of <= ?", sectionID, asOf).
+		Order("sequence DESC").
+		First(&snapshot).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *RegistrationEventRepository) SaveSnapshot(ctx context.Context, snapshot *domain.RegistrationSnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
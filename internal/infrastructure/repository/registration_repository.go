@@ -4,9 +4,11 @@ import (
 	domain "cobra-template/internal/domain/registration"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RegistrationRepository struct {
@@ -20,6 +22,9 @@ func NewRegistrationRepository(db *gorm.DB) interfaces.RegistrationRepository {
 }
 
 func (r *RegistrationRepository) Create(ctx context.Context, registration *domain.Registration) error {
+	if err := withSectionSemester(ctx, r.db, &registration.SemesterID, registration.SectionID); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Create(registration).Error
 }
 
@@ -29,6 +34,7 @@ func (r *RegistrationRepository) GetByStudentAndSection(ctx context.Context, stu
 		Preload("Student").
 		Preload("Section").
 		Where("student_id = ? AND section_id = ?", studentID, sectionID).
+		Where(sectionSemesterCondition, sectionID).
 		First(&registration).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -40,7 +46,12 @@ func (r *RegistrationRepository) GetByStudentAndSection(ctx context.Context, stu
 }
 
 func (r *RegistrationRepository) Update(ctx context.Context, registration *domain.Registration) error {
-	return r.db.WithContext(ctx).Save(registration).Error
+	if err := withSectionSemester(ctx, r.db, &registration.SemesterID, registration.SectionID); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Where("semester_id = ?", registration.SemesterID).
+		Save(registration).Error
 }
 
 func (r *RegistrationRepository) GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.Registration, error) {
@@ -62,9 +73,161 @@ func (r *RegistrationRepository) GetBySectionID(ctx context.Context, sectionID u
 		Preload("Student").
 		Preload("Section").
 		Where("section_id = ?", sectionID).
+		Where(sectionSemesterCondition, sectionID).
 		Find(&registrations).Error
 	if err != nil {
 		return nil, err
 	}
 	return registrations, nil
 }
+
+func (r *RegistrationRepository) GetByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) ([]*domain.Registration, error) {
+	var registrations []*domain.Registration
+	err := r.db.WithContext(ctx).
+		Table(registrationsWithArchive+" AS registrations").
+		Preload("Section").
+		Joins("JOIN sections ON sections.section_id = registrations.section_id").
+		Where("registrations.student_id = ? AND sections.course_id = ?", studentID, courseID).
+		Order("registrations.registration_date").
+		Find(&registrations).Error
+	if err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+func (r *RegistrationRepository) CountEnrolledBySection(ctx context.Context, semesterID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		SectionID uuid.UUID
+		Count     int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.Registration{}).
+		Select("section_id, COUNT(*) AS count").
+		Where("semester_id = ? AND status = ?", semesterID, domain.StatusEnrolled).
+		Group("section_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.SectionID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *RegistrationRepository) CountEnrolledBySeatPool(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		SeatPoolID uuid.UUID
+		Count      int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.Registration{}).
+		Select("seat_pool_id, COUNT(*) AS count").
+		Where("section_id = ? AND status = ? AND seat_pool_id IS NOT NULL", sectionID, domain.StatusEnrolled).
+		Group("seat_pool_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.SeatPoolID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *RegistrationRepository) CountBySectionStatus(ctx context.Context, semesterID uuid.UUID) ([]interfaces.SectionEnrollmentCounts, error) {
+	var rows []interfaces.SectionEnrollmentCounts
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT s.section_id, c.course_code, s.section_number, s.total_seats,
+			COALESCE(r.enrolled, 0) AS enrolled,
+			COALESCE(r.withdrawn, 0) AS withdrawn,
+			COALESCE(r.dropped, 0) AS dropped,
+			COALESCE(w.waitlisted, 0) AS waitlisted,
+			r.last_seat_taken_at
+		FROM sections s
+		JOIN courses c ON c.course_id = s.course_id
+		LEFT JOIN (
+			SELECT section_id,
+				COUNT(*) FILTER (WHERE status = ?) AS enrolled,
+				COUNT(*) FILTER (WHERE status = ?) AS withdrawn,
+				COUNT(*) FILTER (WHERE status = ?) AS dropped,
+				MAX(registration_date) FILTER (WHERE status IN ?) AS last_seat_taken_at
+			FROM `+registrationsWithArchive+` registrations
+			WHERE semester_id = ?
+			GROUP BY section_id
+		) r ON r.section_id = s.section_id
+		LEFT JOIN (
+			SELECT section_id, COUNT(*) AS waitlisted
+			FROM `+waitlistWithArchive+` waitlist
+			WHERE semester_id = ?
+			GROUP BY section_id
+		) w ON w.section_id = s.section_id
+		WHERE s.semester_id = ?
+		ORDER BY c.course_code, s.section_number`,
+		domain.StatusEnrolled, domain.StatusWithdrawn, domain.StatusDropped,
+		[]domain.RegistrationStatus{domain.StatusEnrolled, domain.StatusWithdrawn},
+		semesterID, semesterID, semesterID,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *RegistrationRepository) EnrollLocked(ctx context.Context, registration *domain.Registration, heldSeats int) (*domain.Section, error) {
+	var section domain.Section
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The student row first, in the order every enrollment takes them, so two sections
+		// cannot deadlock and the student's own requests run one at a time
+		var studentID uuid.UUID
+		if err := tx.Model(&domain.Student{}).
+			Where("student_id = ?", registration.StudentID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Pluck("student_id", &studentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Select("section_id", "semester_id", "total_seats", "available_seats", "version").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&section, "section_id = ?", registration.SectionID).Error; err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&domain.Registration{}).
+			Where("student_id = ? AND section_id = ? AND semester_id = ?", registration.StudentID, registration.SectionID, section.SemesterID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return interfaces.ErrAlreadyRegistered
+		}
+		if section.AvailableSeats-heldSeats <= 0 {
+			return interfaces.ErrNoSeatsLeft
+		}
+
+		section.AvailableSeats--
+		section.Version++
+		if err := tx.Model(&domain.Section{}).
+			Where("section_id = ?", section.SectionID).
+			Updates(map[string]any{
+				"available_seats": section.AvailableSeats,
+				"version":         section.Version,
+				"updated_at":      time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		registration.SemesterID = section.SemesterID
+		return tx.Create(registration).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RegistrationTransitionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegistrationTransitionRepository(db *gorm.DB) interfaces.RegistrationTransitionRepository {
+	return &RegistrationTransitionRepository{
+		db: db,
+	}
+}
+
+func (r *RegistrationTransitionRepository) Append(ctx context.Context, transition *domain.RegistrationTransition) error {
+	return r.db.WithContext(ctx).Create(transition).Error
+}
+
+func (r *RegistrationTransitionRepository) ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*domain.RegistrationTransition, error) {
+	var transitions []*domain.RegistrationTransition
+	err := r.db.WithContext(ctx).
+		Where("student_id = ?", studentID).
+		Order("occurred_at ASC, created_at ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
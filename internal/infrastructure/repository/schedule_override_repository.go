@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleOverrideRepository(db *gorm.DB) interfaces.ScheduleOverrideRepository {
+	return &ScheduleOverrideRepository{
+		db: db,
+	}
+}
+
+func (r *ScheduleOverrideRepository) Create(ctx context.Context, override *domain.ScheduleOverride) error {
+	return r.db.WithContext(ctx).Create(override).Error
+}
+
+func (r *ScheduleOverrideRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleOverride, error) {
+	var override domain.ScheduleOverride
+	err := r.db.WithContext(ctx).Where("override_id = ?", id).First(&override).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *ScheduleOverrideRepository) GetOpen(ctx context.Context, studentID, sectionID uuid.UUID) (*domain.ScheduleOverride, error) {
+	var override domain.ScheduleOverride
+	err := r.db.WithContext(ctx).
+		Where("student_id = ? AND section_id = ?", studentID, sectionID).
+		Where("status IN ?", []domain.ScheduleOverrideStatus{domain.ScheduleOverridePending, domain.ScheduleOverrideApproved}).
+		First(&override).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+func (r *ScheduleOverrideRepository) ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*domain.ScheduleOverride, error) {
+	var overrides []*domain.ScheduleOverride
+	err := r.db.WithContext(ctx).
+		Where("student_id = ?", studentID).
+		Order("created_at DESC").
+		Find(&overrides).Error
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (r *ScheduleOverrideRepository) ListByStatus(ctx context.Context, status domain.ScheduleOverrideStatus, limit int) ([]*domain.ScheduleOverride, error) {
+	var overrides []*domain.ScheduleOverride
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&overrides).Error
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (r *ScheduleOverrideRepository) Decide(ctx context.Context, id uuid.UUID, status domain.ScheduleOverrideStatus, decidedBy, note string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.ScheduleOverride{}).
+		Where("override_id = ? AND status = ?", id, domain.ScheduleOverridePending).
+		Updates(map[string]any{
+			"status":        status,
+			"decided_by":    decidedBy,
+			"decision_note": note,
+			"decided_at":    at,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to decide schedule override: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ScheduleOverrideRepository) RecordRegistration(ctx context.Context, id uuid.UUID, status, message string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ScheduleOverride{}).
+		Where("override_id = ?", id).
+		Updates(map[string]any{
+			"registration_status":  status,
+			"registration_message": message,
+			"updated_at":           time.Now(),
+		}).Error
+}
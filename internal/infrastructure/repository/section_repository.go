@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	domain "cobra-template/internal/domain/registration"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
@@ -26,13 +28,20 @@ func (r *SectionRepository) Create(ctx context.Context, section *domain.Section)
 
 func (r *SectionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Section, error) {
 	var section domain.Section
-	err := r.db.WithContext(ctx).Preload("Course").Preload("Semester").First(&section, "section_id = ?", id).Error
+	err := r.db.WithContext(ctx).Preload("Course").Preload("Semester").Preload("SeatPools").First(&section, "section_id = ?", id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
 		return nil, err
 	}
+	err = r.db.WithContext(ctx).Model(&domain.Section{}).
+		Where("linked_section_id = ? AND is_active", id).
+		Order("section_number").
+		Pluck("section_id", &section.ComponentIDs).Error
+	if err != nil {
+		return nil, err
+	}
 	return &section, nil
 }
 func (r *SectionRepository) UpdateWithOptimisticLock(ctx context.Context, section *domain.Section) error {
@@ -49,7 +58,147 @@ func (r *SectionRepository) UpdateWithOptimisticLock(ctx context.Context, sectio
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("optimistic lock failure: section has been modified by another process")
+		return interfaces.ErrSectionVersionConflict
+	}
+
+	return nil
+}
+
+// UpdateCapacity writes a new capacity together with the seat count already applied to the
+// Redis counter. The counter is authoritative, so no version check is made here.
+func (r *SectionRepository) UpdateCapacity(ctx context.Context, sectionID uuid.UUID, totalSeats, availableSeats int) error {
+	result := r.db.WithContext(ctx).Model(&domain.Section{}).
+		Where("section_id = ?", sectionID).
+		Updates(map[string]any{
+			"total_seats":     totalSeats,
+			"available_seats": availableSeats,
+			"version":         gorm.Expr("version + 1"),
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update section capacity: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("section %s not found", sectionID)
+	}
+
+	return nil
+}
+
+func (r *SectionRepository) SetActive(ctx context.Context, sectionID uuid.UUID, active bool) error {
+	result := r.db.WithContext(ctx).Model(&domain.Section{}).
+		Where("section_id = ?", sectionID).
+		Updates(map[string]any{
+			"is_active":  active,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update section status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("section %s not found", sectionID)
+	}
+
+	return nil
+}
+
+func (r *SectionRepository) SetWaitlistFreeze(ctx context.Context, sectionID uuid.UUID, frozen, promotionsPaused bool) error {
+	result := r.db.WithContext(ctx).Model(&domain.Section{}).
+		Where("section_id = ?", sectionID).
+		Updates(map[string]any{
+			"waitlist_frozen":            frozen,
+			"waitlist_promotions_paused": promotionsPaused,
+			"updated_at":                 time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update section waitlist freeze: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("section %s not found", sectionID)
+	}
+
+	return nil
+}
+
+func (r *SectionRepository) SetWaitlistTTL(ctx context.Context, sectionID uuid.UUID, ttlHours *int) error {
+	result := r.db.WithContext(ctx).Model(&domain.Section{}).
+		Where("section_id = ?", sectionID).
+		Updates(map[string]any{
+			"waitlist_ttl_hours": ttlHours,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update section waitlist TTL: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("section %s not found", sectionID)
+	}
+
+	return nil
+}
+
+func (r *SectionRepository) SetPolicy(ctx context.Context, sectionID uuid.UUID, policy domain.SectionPolicy) error {
+	result := r.db.WithContext(ctx).Model(&domain.Section{}).
+		Where("section_id = ?", sectionID).
+		Updates(map[string]any{
+			"allow_waitlist":              policy.AllowWaitlist,
+			"reserved_seats":              policy.ReservedSeats,
+			"instructor_consent_required": policy.InstructorConsentRequired,
+			"waitlist_max_size":           policy.MaxWaitlist,
+			"updated_at":                  time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update section policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("section %s not found", sectionID)
+	}
+
+	return nil
+}
+
+func (r *SectionRepository) ReplaceSeatPools(ctx context.Context, sectionID uuid.UUID, pools []domain.SeatPool) ([]domain.SeatPool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []domain.SeatPool
+		if err := tx.Where("section_id = ?", sectionID).Find(&existing).Error; err != nil {
+			return err
+		}
+		ids := make(map[string]uuid.UUID, len(existing))
+		for _, pool := range existing {
+			ids[pool.Name] = pool.PoolID
+		}
+
+		if err := tx.Where("section_id = ?", sectionID).Delete(&domain.SeatPool{}).Error; err != nil {
+			return err
+		}
+		for i := range pools {
+			pools[i].SectionID = sectionID
+			if id, ok := ids[pools[i].Name]; ok {
+				pools[i].PoolID = id
+			} else {
+				pools[i].PoolID = uuid.New()
+			}
+		}
+		if len(pools) == 0 {
+			return nil
+		}
+		return tx.Create(&pools).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace seat pools: %w", err)
+	}
+	return pools, nil
+}
+
+func (r *SectionRepository) UpdateTags(ctx context.Context, sectionID uuid.UUID, tags []string, attributes map[string]string) error {
+	result := r.db.WithContext(ctx).Model(&domain.Section{SectionID: sectionID}).
+		Select("tags", "attributes", "updated_at").
+		Updates(&domain.Section{Tags: tags, Attributes: attributes, UpdatedAt: time.Now()})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update section tags: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("section %s not found", sectionID)
 	}
 
 	return nil
@@ -67,6 +216,24 @@ func (r *SectionRepository) GetByCourseAndSemester(ctx context.Context, courseID
 	}
 	return sections, nil
 }
+
+// GetByIDs loads several sections with their course and semester in one query. Unknown IDs
+// are skipped.
+func (r *SectionRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Section, error) {
+	var sections []*domain.Section
+	if len(ids) == 0 {
+		return sections, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Course").
+		Preload("Semester").
+		Where("section_id IN ?", ids).
+		Find(&sections).Error
+	if err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
 func (r *SectionRepository) GetBySemester(ctx context.Context, semesterID uuid.UUID) ([]*domain.Section, error) {
 	var sections []*domain.Section
 	err := r.db.WithContext(ctx).
@@ -79,12 +246,53 @@ func (r *SectionRepository) GetBySemester(ctx context.Context, semesterID uuid.U
 	}
 	return sections, nil
 }
+
+func (r *SectionRepository) SearchByCourses(ctx context.Context, courseIDs []uuid.UUID, filter interfaces.CourseSearchFilter) ([]*domain.Section, error) {
+	if len(courseIDs) == 0 {
+		return nil, nil
+	}
+
+	var sections []*domain.Section
+	err := sectionSearchConditions(r.db.WithContext(ctx).Model(&domain.Section{}), filter).
+		Preload("Semester").
+		Where("sections.course_id IN ?", courseIDs).
+		Order("sections.section_number").
+		Find(&sections).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search sections: %w", err)
+	}
+	return sections, nil
+}
+
+// sectionSearchConditions adds the section fields of filter to a query on sections. Only
+// active sections are ever returned.
+func sectionSearchConditions(query *gorm.DB, filter interfaces.CourseSearchFilter) *gorm.DB {
+	query = query.Where("sections.is_active")
+	if filter.SemesterID != nil {
+		query = query.Where("sections.semester_id = ?", *filter.SemesterID)
+	}
+	if filter.Days != "" {
+		// translate() strips the wanted days, so anything left is a day outside them
+		query = query.Where("sections.meeting_days <> '' AND translate(sections.meeting_days, ?, '') = ''", strings.ToUpper(filter.Days))
+	}
+	if filter.StartAfter != "" {
+		query = query.Where("sections.start_time >= ?", filter.StartAfter)
+	}
+	if filter.EndBefore != "" {
+		query = query.Where("sections.end_time <> '' AND sections.end_time <= ?", filter.EndBefore)
+	}
+	if filter.OnlyWithSeats {
+		query = query.Where("sections.available_seats > 0")
+	}
+	return query
+}
+
 func (r *SectionRepository) GetAllActive(ctx context.Context) ([]*domain.Section, error) {
 	var sections []*domain.Section
 	err := r.db.WithContext(ctx).
 		Preload("Course").
 		Preload("Semester").
-		Where("available_seats > 0").
+		Where("is_active AND available_seats > 0").
 		Find(&sections).Error
 	if err != nil {
 		return nil, err
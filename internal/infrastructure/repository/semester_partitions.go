@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Registrations and waitlist entries are partitioned by semester. Queries that know the
+// section are narrowed to its semester as well, so Postgres only scans that semester's
+// partition instead of every semester's.
+const sectionSemesterCondition = "semester_id = (SELECT semester_id FROM sections WHERE section_id = ?)"
+
+// Once a semester is archived its partitions belong to registrations_archive and
+// waitlist_archive. Queries that look back at past semesters, for reports and course
+// history, read the live and archive tables together.
+const (
+	registrationsWithArchive = "(SELECT * FROM registrations UNION ALL SELECT * FROM registrations_archive)"
+	waitlistWithArchive      = "(SELECT * FROM waitlist UNION ALL SELECT * FROM waitlist_archive)"
+)
+
+// sectionSemester looks up the semester of a section, the partition key of the rows written
+// for it
+func sectionSemester(ctx context.Context, db *gorm.DB, sectionID uuid.UUID) (uuid.UUID, error) {
+	var semesterID uuid.UUID
+	err := db.WithContext(ctx).
+		Raw("SELECT semester_id FROM sections WHERE section_id = ?", sectionID).
+		Row().
+		Scan(&semesterID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("section %s not found", sectionID)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get semester of section %s: %w", sectionID, err)
+	}
+	return semesterID, nil
+}
+
+// withSectionSemester fills in semesterID from the section when the caller left it unset
+func withSectionSemester(ctx context.Context, db *gorm.DB, semesterID *uuid.UUID, sectionID uuid.UUID) error {
+	if *semesterID != uuid.Nil {
+		return nil
+	}
+	id, err := sectionSemester(ctx, db, sectionID)
+	if err != nil {
+		return err
+	}
+	*semesterID = id
+	return nil
+}
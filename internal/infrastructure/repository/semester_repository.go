@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	domain "cobra-template/internal/domain/registration"
@@ -37,6 +38,18 @@ func (r *SemesterRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	return &semester, nil
 }
 
+func (r *SemesterRepository) GetByCode(ctx context.Context, code string) (*domain.Semester, error) {
+	var semester domain.Semester
+	err := r.db.WithContext(ctx).First(&semester, "semester_code = ?", code).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &semester, nil
+}
+
 func (r *SemesterRepository) GetCurrent(ctx context.Context) (*domain.Semester, error) {
 	var semester domain.Semester
 	now := time.Now()
@@ -62,3 +75,62 @@ func (r *SemesterRepository) GetAllActive(ctx context.Context) ([]*domain.Semest
 	}
 	return semesters, nil
 }
+
+func (r *SemesterRepository) GetAll(ctx context.Context) ([]*domain.Semester, error) {
+	var semesters []*domain.Semester
+	err := r.db.WithContext(ctx).
+		Order("start_date").
+		Find(&semesters).Error
+	if err != nil {
+		return nil, err
+	}
+	return semesters, nil
+}
+
+func (r *SemesterRepository) Update(ctx context.Context, semester *domain.Semester) error {
+	result := r.db.WithContext(ctx).Model(&domain.Semester{}).
+		Where("semester_id = ?", semester.SemesterID).
+		Updates(map[string]any{
+			"semester_name":      semester.SemesterName,
+			"start_date":         semester.StartDate,
+			"end_date":           semester.EndDate,
+			"registration_start": semester.RegistrationStart,
+			"registration_end":   semester.RegistrationEnd,
+			"is_active":          semester.IsActive,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update semester: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("semester %s not found", semester.SemesterID)
+	}
+
+	return nil
+}
+
+func (r *SemesterRepository) Archive(ctx context.Context, semesterID uuid.UUID) (*interfaces.ArchivedSemesterRows, error) {
+	var rows *interfaces.ArchivedSemesterRows
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var archived bool
+		if err := tx.Raw("SELECT archive_semester(?)", semesterID).Row().Scan(&archived); err != nil {
+			return fmt.Errorf("failed to archive semester: %w", err)
+		}
+		if !archived {
+			return nil
+		}
+
+		rows = &interfaces.ArchivedSemesterRows{}
+		if err := tx.Table("registrations_archive").Where("semester_id = ?", semesterID).Count(&rows.Registrations).Error; err != nil {
+			return fmt.Errorf("failed to count archived registrations: %w", err)
+		}
+		if err := tx.Table("waitlist_archive").Where("semester_id = ?", semesterID).Count(&rows.WaitlistEntries).Error; err != nil {
+			return fmt.Errorf("failed to count archived waitlist entries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
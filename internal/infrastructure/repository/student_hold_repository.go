@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StudentHoldRepository struct {
+	db *gorm.DB
+}
+
+func NewStudentHoldRepository(db *gorm.DB) interfaces.StudentHoldRepository {
+	return &StudentHoldRepository{
+		db: db,
+	}
+}
+
+func (r *StudentHoldRepository) Create(ctx context.Context, hold *domain.StudentHold) error {
+	return r.db.WithContext(ctx).Create(hold).Error
+}
+
+func (r *StudentHoldRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.StudentHold, error) {
+	var hold domain.StudentHold
+	err := r.db.WithContext(ctx).Where("hold_id = ?", id).First(&hold).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *StudentHoldRepository) GetByExternalRef(ctx context.Context, source, externalRef string) (*domain.StudentHold, error) {
+	var hold domain.StudentHold
+	err := r.db.WithContext(ctx).
+		Where("source = ? AND external_ref = ?", source, externalRef).
+		First(&hold).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *StudentHoldRepository) GetActiveByStudent(ctx context.Context, studentID uuid.UUID) ([]*domain.StudentHold, error) {
+	var holds []*domain.StudentHold
+	err := r.db.WithContext(ctx).
+		Where("student_id = ? AND released_at IS NULL", studentID).
+		Order("placed_at ASC").
+		Find(&holds).Error
+	if err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+func (r *StudentHoldRepository) Release(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.StudentHold{}).
+		Where("hold_id = ? AND released_at IS NULL", id).
+		Updates(map[string]any{
+			"released_at": at,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to release hold: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
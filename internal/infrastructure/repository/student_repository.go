@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	domain "cobra-template/internal/domain/registration"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StudentRepository struct {
@@ -61,3 +65,112 @@ func (r *StudentRepository) GetRecentlyActive(ctx context.Context, limit int) ([
 
 	return students, nil
 }
+
+func (r *StudentRepository) GetByEmail(ctx context.Context, email string) (*domain.Student, error) {
+	var student domain.Student
+	err := r.db.WithContext(ctx).First(&student, "LOWER(email) = LOWER(?)", email).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &student, nil
+}
+
+// UpdateProfile writes the name, contact and notification preference fields of the student
+// and bumps its version
+func (r *StudentRepository) UpdateProfile(ctx context.Context, student *domain.Student) error {
+	result := r.db.WithContext(ctx).Model(&domain.Student{}).
+		Where("student_id = ?", student.StudentID).
+		Updates(map[string]any{
+			"first_name":         student.FirstName,
+			"last_name":          student.LastName,
+			"preferred_name":     student.PreferredName,
+			"email":              student.Email,
+			"phone":              student.Phone,
+			"deadline_reminders": student.DeadlineReminders,
+			"version":            gorm.Expr("version + 1"),
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update student profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("student %s not found", student.StudentID)
+	}
+
+	return nil
+}
+
+func (r *StudentRepository) Update(ctx context.Context, student *domain.Student) error {
+	result := r.db.WithContext(ctx).Model(&domain.Student{}).
+		Where("student_id = ?", student.StudentID).
+		Updates(map[string]any{
+			"student_number":    student.StudentNumber,
+			"first_name":        student.FirstName,
+			"last_name":         student.LastName,
+			"preferred_name":    student.PreferredName,
+			"email":             student.Email,
+			"phone":             student.Phone,
+			"enrollment_status": student.EnrollmentStatus,
+			"major":             student.Major,
+			"cohort":            student.Cohort,
+			"year":              student.Year,
+			"version":           gorm.Expr("version + 1"),
+			"updated_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update student: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("student %s not found", student.StudentID)
+	}
+
+	return nil
+}
+
+func (r *StudentRepository) Archive(ctx context.Context, studentIDs []uuid.UUID, studentNumberPrefix string, archivedAt time.Time) ([]uuid.UUID, error) {
+	var archived []uuid.UUID
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.Student{}).Where("enrollment_status <> ?", domain.EnrollmentStatusArchived)
+		switch {
+		case len(studentIDs) > 0 && studentNumberPrefix != "":
+			query = query.Where("student_id IN ? OR student_number LIKE ?", studentIDs, likePrefix(studentNumberPrefix))
+		case len(studentIDs) > 0:
+			query = query.Where("student_id IN ?", studentIDs)
+		case studentNumberPrefix != "":
+			query = query.Where("student_number LIKE ?", likePrefix(studentNumberPrefix))
+		default:
+			return nil
+		}
+
+		if err := query.Clauses(clause.Locking{Strength: "UPDATE"}).Pluck("student_id", &archived).Error; err != nil {
+			return err
+		}
+		if len(archived) == 0 {
+			return nil
+		}
+
+		return tx.Model(&domain.Student{}).
+			Where("student_id IN ?", archived).
+			Updates(map[string]any{
+				"enrollment_status": domain.EnrollmentStatusArchived,
+				"archived_at":       archivedAt,
+				"version":           gorm.Expr("version + 1"),
+				"updated_at":        time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive students: %w", err)
+	}
+
+	return archived, nil
+}
+
+// likePrefix escapes LIKE wildcards in prefix and matches anything after it
+func likePrefix(prefix string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+	return escaped + "%"
+}
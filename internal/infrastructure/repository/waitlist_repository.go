@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	domain "cobra-template/internal/domain/registration"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
@@ -21,6 +23,9 @@ func NewWaitlistRepository(db *gorm.DB) interfaces.WaitlistRepository {
 }
 
 func (r *WaitlistRepository) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
+	if err := withSectionSemester(ctx, r.db, &entry.SemesterID, entry.SectionID); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Create(entry).Error
 }
 
@@ -30,6 +35,7 @@ func (r *WaitlistRepository) GetByStudentAndSection(ctx context.Context, student
 		Preload("Student").
 		Preload("Section").
 		Where("student_id = ? AND section_id = ?", studentID, sectionID).
+		Where(sectionSemesterCondition, sectionID).
 		First(&entry).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -46,6 +52,7 @@ func (r *WaitlistRepository) GetNextInLine(ctx context.Context, sectionID uuid.U
 		Preload("Student").
 		Preload("Section").
 		Where("section_id = ?", sectionID).
+		Where(sectionSemesterCondition, sectionID).
 		Order("position ASC").
 		First(&entry).Error
 	if err != nil {
@@ -61,6 +68,7 @@ func (r *WaitlistRepository) GetNextPosition(ctx context.Context, sectionID uuid
 	var count int64
 	err := r.db.WithContext(ctx).Model(&domain.WaitlistEntry{}).
 		Where("section_id = ?", sectionID).
+		Where(sectionSemesterCondition, sectionID).
 		Count(&count).Error
 	if err != nil {
 		return 0, err
@@ -78,6 +86,7 @@ func (r *WaitlistRepository) GetBySectionID(ctx context.Context, sectionID uuid.
 		Preload("Student").
 		Preload("Section").
 		Where("section_id = ?", sectionID).
+		Where(sectionSemesterCondition, sectionID).
 		Order("position ASC").
 		Find(&entries).Error
 	if err != nil {
@@ -99,3 +108,58 @@ func (r *WaitlistRepository) GetByStudentID(ctx context.Context, studentID uuid.
 	}
 	return entries, nil
 }
+
+func (r *WaitlistRepository) RenumberPositions(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, error) {
+	var moved []*domain.WaitlistEntry
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entries []*domain.WaitlistEntry
+		if err := tx.Where("section_id = ?", sectionID).
+			Where(sectionSemesterCondition, sectionID).
+			Order("position ASC, timestamp ASC").
+			Find(&entries).Error; err != nil {
+			return fmt.Errorf("failed to get section waitlist: %w", err)
+		}
+
+		now := time.Now()
+		for i, entry := range entries {
+			position := i + 1
+			if entry.Position == position {
+				continue
+			}
+
+			if err := tx.Model(&domain.WaitlistEntry{}).
+				Where("waitlist_id = ? AND semester_id = ?", entry.WaitlistID, entry.SemesterID).
+				Updates(map[string]any{
+					"position":   position,
+					"updated_at": now,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to renumber waitlist entry %s: %w", entry.WaitlistID, err)
+			}
+
+			entry.Position = position
+			entry.UpdatedAt = now
+			moved = append(moved, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return moved, nil
+}
+
+func (r *WaitlistRepository) GetExpired(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error) {
+	var entries []*domain.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at < ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired waitlist entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// lockKeyPrefix namespaces the locks that let only one instance run a singleton task
+const lockKeyPrefix = "lock:scheduler:"
+
+// Task is a recurring maintenance job
+type Task struct {
+	Name     string
+	Interval time.Duration
+	// Timeout bounds one run; zero uses the interval
+	Timeout time.Duration
+	// Singleton tasks run on one instance per interval. Every instance schedules the task and
+	// the first to take a lock held for half the interval runs it.
+	Singleton bool
+	Run       func(ctx context.Context) error
+}
+
+// Scheduler runs recurring tasks, each on its own ticker, until it is stopped. A run that is
+// still going when the next tick arrives delays that tick rather than overlapping it.
+type Scheduler struct {
+	locker interfaces.CacheService
+	tasks  []Task
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	started  bool
+}
+
+// New creates a scheduler. locker is only needed for singleton tasks.
+func New(locker interfaces.CacheService) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		locker: locker,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Add registers a task. Tasks added after Start are not run.
+func (s *Scheduler) Add(task Task) {
+	s.tasks = append(s.tasks, task)
+}
+
+// Tasks reports the names of the registered tasks
+func (s *Scheduler) Tasks() []string {
+	names := make([]string, len(s.tasks))
+	for i, task := range s.tasks {
+		names[i] = task.Name
+	}
+	return names
+}
+
+func (s *Scheduler) Start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, task := range s.tasks {
+		logger.Info("Scheduling %s every %v", task.Name, task.Interval)
+		s.wg.Add(1)
+		go s.loop(task)
+	}
+}
+
+// Stop stops the tickers, cancels the runs in progress and waits for them to return
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+		logger.Info("Scheduler stopped")
+	})
+}
+
+func (s *Scheduler) loop(task Task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.runTask(task)
+		}
+	}
+}
+
+func (s *Scheduler) runTask(task Task) {
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = task.Interval
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+
+	if task.Singleton {
+		acquired, err := s.locker.AcquireLock(ctx, lockKeyPrefix+task.Name, uuid.NewString(), task.Interval/2)
+		if err != nil {
+			logger.Error("Failed to take the lock of scheduled task %s: %v", task.Name, err)
+			metrics.SchedulerTaskRuns.WithLabelValues(task.Name, metrics.ScheduledTaskFailed).Inc()
+			return
+		}
+		if !acquired {
+			logger.Debug("Scheduled task %s already run by another instance", task.Name)
+			metrics.SchedulerTaskRuns.WithLabelValues(task.Name, metrics.ScheduledTaskSkipped).Inc()
+			return
+		}
+	}
+
+	start := time.Now()
+	err := run(ctx, task)
+	metrics.SchedulerTaskDuration.WithLabelValues(task.Name).Observe(time.Since(start).Seconds())
+	if err != nil && s.ctx.Err() != nil {
+		logger.Info("Scheduled task %s interrupted by shutdown", task.Name)
+		return
+	}
+	if err != nil {
+		logger.Error("Scheduled task %s failed: %v", task.Name, err)
+		metrics.SchedulerTaskRuns.WithLabelValues(task.Name, metrics.ScheduledTaskFailed).Inc()
+		return
+	}
+	logger.Debug("Scheduled task %s finished in %v", task.Name, time.Since(start).Round(time.Millisecond))
+	metrics.SchedulerTaskRuns.WithLabelValues(task.Name, metrics.ScheduledTaskSucceeded).Inc()
+}
+
+// run calls the task, turning a panic into an error so one bad run does not stop the ticker
+func run(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return task.Run(ctx)
+}
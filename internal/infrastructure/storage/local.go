@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+)
+
+// LocalFilesPath is the API route serving signed downloads for the local backend
+const LocalFilesPath = "/api/v1/files/"
+
+var (
+	ErrInvalidKey       = errors.New("invalid storage key")
+	ErrSignatureExpired = errors.New("signed URL has expired")
+	ErrSignatureInvalid = errors.New("signed URL signature is invalid")
+)
+
+type LocalStorage struct {
+	basePath   string
+	baseURL    string
+	signingKey []byte
+}
+
+func NewLocalStorage(cfg *config.LocalStorageConfig) *LocalStorage {
+	signingKey := []byte(cfg.SigningKey)
+	if len(signingKey) == 0 {
+		// Without a configured key signed URLs stop working after a restart
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			logger.Error("Failed to generate storage signing key: %v", err)
+		}
+		logger.Warn("storage.local.signing_key is not set, using a random key for signed URLs")
+	}
+
+	return &LocalStorage{
+		basePath:   cfg.BasePath,
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		signingKey: signingKey,
+	}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error {
+	target, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partially written object
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, reader); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to store object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, *interfaces.ObjectInfo, error) {
+	target, err := s.resolve(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, interfaces.ErrObjectNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open object %s: %w", key, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+
+	return file, &interfaces.ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  mime.TypeByExtension(path.Ext(key)),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]interfaces.ObjectInfo, error) {
+	objects := make([]interfaces.ObjectInfo, 0)
+
+	err := filepath.WalkDir(s.basePath, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.basePath, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		objects = append(objects, interfaces.ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			ContentType:  mime.TypeByExtension(path.Ext(key)),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// SignedURL points at the files endpoint of the registration API with an HMAC signature
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := s.resolve(key); err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(expiry).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(key, expires))
+
+	return s.baseURL + LocalFilesPath + key + "?" + query.Encode(), nil
+}
+
+func (s *LocalStorage) VerifySignedURL(key string, expires int64, signature string) error {
+	if time.Now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+
+	expected := s.sign(key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// resolve maps a key to a path below the base directory, rejecting keys that escape it
+func (s *LocalStorage) resolve(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned[1:] != key {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.basePath, filepath.FromSlash(key)), nil
+}
+
+var _ interfaces.StorageService = (*LocalStorage)(nil)
+var _ interfaces.SignedURLVerifier = (*LocalStorage)(nil)
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// ObjectStorage stores objects in an S3 compatible bucket. It backs both the s3 and gcs
+// providers, the latter through the GCS XML API with HMAC credentials.
+type ObjectStorage struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewObjectStorage(cfg *config.BucketConfig) (*ObjectStorage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage bucket is not configured")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object storage client: %w", err)
+	}
+
+	return &ObjectStorage{
+		client: client,
+		bucket: cfg.Bucket,
+	}, nil
+}
+
+func (s *ObjectStorage) Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *ObjectStorage) Get(ctx context.Context, key string) (io.ReadCloser, *interfaces.ObjectInfo, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	stat, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil, interfaces.ErrObjectNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+
+	return object, &interfaces.ObjectInfo{
+		Key:          key,
+		Size:         stat.Size,
+		ContentType:  stat.ContentType,
+		LastModified: stat.LastModified,
+	}, nil
+}
+
+func (s *ObjectStorage) List(ctx context.Context, prefix string) ([]interfaces.ObjectInfo, error) {
+	objects := make([]interfaces.ObjectInfo, 0)
+
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+
+		objects = append(objects, interfaces.ObjectInfo{
+			Key:          object.Key,
+			Size:         object.Size,
+			ContentType:  object.ContentType,
+			LastModified: object.LastModified,
+		})
+	}
+
+	return objects, nil
+}
+
+func (s *ObjectStorage) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *ObjectStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	signed, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL for object %s: %w", key, err)
+	}
+
+	return signed.String(), nil
+}
+
+var _ interfaces.StorageService = (*ObjectStorage)(nil)
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"fmt"
+
+	"cobra-template/internal/config"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+)
+
+const (
+	ProviderLocal = "local"
+	ProviderS3    = "s3"
+	ProviderGCS   = "gcs"
+)
+
+// New creates the storage backend selected by storage.provider
+func New(cfg *config.StorageConfig) (interfaces.StorageService, error) {
+	switch cfg.Provider {
+	case ProviderLocal, "":
+		return NewLocalStorage(&cfg.Local), nil
+	case ProviderS3:
+		return NewObjectStorage(&cfg.S3)
+	case ProviderGCS:
+		return NewObjectStorage(&cfg.GCS)
+	default:
+		return nil, fmt.Errorf("unknown storage provider: %s", cfg.Provider)
+	}
+}
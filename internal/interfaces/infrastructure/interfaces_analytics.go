@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnalyticsEventType string
+
+const (
+	// AnalyticsRegistrationAttempted is emitted for every registration a student asks for,
+	// whatever its outcome; Result holds the registration result status
+	AnalyticsRegistrationAttempted AnalyticsEventType = "registration_attempted"
+	AnalyticsEnrolled              AnalyticsEventType = "enrolled"
+	AnalyticsWaitlisted            AnalyticsEventType = "waitlisted"
+	AnalyticsDropped               AnalyticsEventType = "dropped"
+	AnalyticsWithdrawn             AnalyticsEventType = "withdrawn"
+	// AnalyticsPromoted means the student was taken off a waitlist and enrolled
+	AnalyticsPromoted AnalyticsEventType = "promoted"
+)
+
+// AnalyticsEvent is one registration fact for dashboards. Events are facts about what
+// happened, not commands, so a consumer may see them late or, after a sink failure, not at all.
+type AnalyticsEvent struct {
+	EventID    uuid.UUID          `json:"event_id"`
+	Type       AnalyticsEventType `json:"type"`
+	StudentID  uuid.UUID          `json:"student_id"`
+	SectionID  uuid.UUID          `json:"section_id"`
+	Result     string             `json:"result,omitempty"`
+	Position   *int               `json:"position,omitempty"`
+	OccurredAt time.Time          `json:"occurred_at"`
+}
+
+// AnalyticsSink delivers analytics events to wherever the dashboards read them from
+type AnalyticsSink interface {
+	Emit(ctx context.Context, event AnalyticsEvent) error
+	Close() error
+}
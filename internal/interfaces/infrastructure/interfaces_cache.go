@@ -2,19 +2,152 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrCacheTransient marks cache errors that are expected to clear on their own, such as a
+// Redis failover in progress, after the cache's own retry budget ran out. Callers can tell
+// users to try again rather than report a failure.
+var ErrCacheTransient = errors.New("cache temporarily unavailable")
+
+// ErrCacheNotExecuted marks transient cache errors that prove the command never ran, such as
+// Redis refusing it during a failover, so nothing it would have written was written. It
+// matches ErrCacheTransient as well.
+var ErrCacheNotExecuted = fmt.Errorf("%w: command not executed", ErrCacheTransient)
+
+// ErrWaitlistFull is returned by AddToWaitlist when the waitlist has reached its cap
+var ErrWaitlistFull = errors.New("waitlist is full")
+
+// ErrNoSeatsLeft is returned by ReserveSeat when no seat the student may take is left
+var ErrNoSeatsLeft = errors.New("no seats left")
+
+// ErrSeatsHeldForWaitlist is returned by ReserveSeat when the seats left are kept for the
+// students on the section's waitlist
+var ErrSeatsHeldForWaitlist = errors.New("seats held for waitlist")
+
+// ErrCapacityBelowEnrolled is returned by SetSeatCapacity when the section would have fewer
+// seats than are taken
+var ErrCapacityBelowEnrolled = errors.New("capacity below seats taken")
+
+// ErrSeatPoolsNotLoaded is returned by ReserveSeat for a section with seat pools whose pool
+// counters are not in the cache; they have to be set with SetSeatPools first
+var ErrSeatPoolsNotLoaded = errors.New("seat pool counters not loaded")
+
+// SeatRequest says which seats ReserveSeat may take. Reserved seats at the end of the
+// counter are never taken. When the section has seat pools, Pools are those the student is
+// eligible for in order of preference: the first with seats left is used, and a student
+// none of them can seat only gets a seat that no pool holds. With HoldForWaitlist no seat is
+// taken while anyone is on the section's waitlist.
+type SeatRequest struct {
+	Reserved        int
+	HasPools        bool
+	Pools           []uuid.UUID
+	HoldForWaitlist bool
+}
+
+// SeatReservation is the seat of one section in a ReserveSeats call, with the jobs that
+// record it
+type SeatReservation struct {
+	SectionID uuid.UUID
+	Seat      SeatRequest
+	Jobs      []DatabaseSyncJob
+}
+
+// SeatReservationError is returned by ReserveSeats when the seat of one section cannot be
+// taken. It wraps the error ReserveSeat would have returned for that section.
+type SeatReservationError struct {
+	SectionID uuid.UUID
+	Err       error
+}
+
+func (e *SeatReservationError) Error() string {
+	return fmt.Sprintf("section %s: %v", e.SectionID, e.Err)
+}
+
+func (e *SeatReservationError) Unwrap() error {
+	return e.Err
+}
+
+// SeatCounter is a section's seat counter: the seats left, the section's capacity and the
+// seats it keeps back, as one hash so they change together. Version goes up with every
+// change to the counter.
+type SeatCounter struct {
+	Available int   `json:"available"`
+	Total     int   `json:"total"`
+	Reserved  int   `json:"reserved"`
+	Version   int64 `json:"version"`
+	// Pool is the seat pool ReserveSeat and ReserveSeats took the seat from, nil for a seat
+	// no pool holds. It is not part of the stored counter.
+	Pool *uuid.UUID `json:"-"`
+}
+
+// Enrolled is the number of seats taken
+func (c SeatCounter) Enrolled() int {
+	return c.Total - c.Available
+}
+
+// OverEnrolled reports whether more seats are taken than the section has
+func (c SeatCounter) OverEnrolled() bool {
+	return c.Available < 0
+}
+
+// SeatChange is published whenever a section's cached seat counter changes
+type SeatChange struct {
+	SectionID      uuid.UUID `json:"section_id"`
+	AvailableSeats int       `json:"available_seats"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
 type CacheService interface {
-	// Seat management
+	// Seat management. The methods that only deal in available seats read and move the
+	// Available field of the section's SeatCounter.
 	GetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error)
-	SetAvailableSeats(ctx context.Context, sectionID uuid.UUID, seats int, ttl time.Duration) error
+	// GetAvailableSeatsBatch returns the available seats of every section in one round trip.
+	// Sections whose counter is not cached are left out.
+	GetAvailableSeatsBatch(ctx context.Context, sectionIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	GetSeatCounter(ctx context.Context, sectionID uuid.UUID) (*SeatCounter, error)
+	// SetSeatCounter replaces the section's seat counter; its Version is ignored and the
+	// stored one moved on
+	SetSeatCounter(ctx context.Context, sectionID uuid.UUID, counter SeatCounter, ttl time.Duration) error
+	// SetSeatCounters sets the seat counters of several sections in one round trip
+	SetSeatCounters(ctx context.Context, counters map[uuid.UUID]SeatCounter, ttl time.Duration) error
+	// SetSeatCapacity changes the total and reserved seats of the counter and moves its
+	// available seats by as much as the total, in one step. It fails with
+	// ErrCapacityBelowEnrolled, returning the counter as it stands, when total is below the
+	// seats taken.
+	SetSeatCapacity(ctx context.Context, sectionID uuid.UUID, total, reserved int) (*SeatCounter, error)
 	DecrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error
 	IncrementAvailableSeats(ctx context.Context, sectionID uuid.UUID) error
 	DecrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error)
 	IncrementAndGetAvailableSeats(ctx context.Context, sectionID uuid.UUID) (int, error)
+	// ReserveSeat takes a seat like DecrementAndGetAvailableSeats and appends jobs to the
+	// sync outbox in the same atomic step, so a seat is never taken without the jobs that
+	// record it, nor ahead of a waitlist it is held for. A seat taken from a pool is stamped
+	// on the jobs as their SeatPoolID. Returns the counter as the seat left it.
+	ReserveSeat(ctx context.Context, sectionID uuid.UUID, seat SeatRequest, jobs []DatabaseSyncJob) (*SeatCounter, error)
+	// ReserveSeats takes a seat in every section like ReserveSeat in one atomic step, or in
+	// none of them, failing with a SeatReservationError for the first section that has no
+	// seat for its request. Returns the counters in the order of the reservations.
+	ReserveSeats(ctx context.Context, reservations []SeatReservation) ([]*SeatCounter, error)
+	// ReleaseSeat gives a seat back like IncrementAndGetAvailableSeats, and to the seat pool
+	// it came from when pool is set
+	ReleaseSeat(ctx context.Context, sectionID uuid.UUID, pool *uuid.UUID) (int, error)
+	// RetakeSeat takes back a seat ReleaseSeat gave back, from the pool too when pool is set,
+	// to undo a release that could not be recorded. Unlike ReserveSeat it never refuses.
+	RetakeSeat(ctx context.Context, sectionID uuid.UUID, pool *uuid.UUID) (int, error)
+	// SetSeatPools replaces the seats left in each seat pool of the section.
+	// GetSeatPools returns them, or nil when they are not loaded.
+	SetSeatPools(ctx context.Context, sectionID uuid.UUID, remaining map[uuid.UUID]int, ttl time.Duration) error
+	GetSeatPools(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error)
+	// CompareAndSetAvailableSeats replaces the seat counter only if it still holds expected
+	CompareAndSetAvailableSeats(ctx context.Context, sectionID uuid.UUID, expected, seats int) (bool, error)
+	// SubscribeSeatChanges delivers every change to the section's seat counter until ctx is
+	// cancelled, then closes the channel
+	SubscribeSeatChanges(ctx context.Context, sectionID uuid.UUID) (<-chan SeatChange, error)
 
 	// Section details
 	GetSectionDetails(ctx context.Context, sectionID uuid.UUID) (interface{}, error)
@@ -43,17 +176,34 @@ type CacheService interface {
 	// General cache operations
 	Delete(ctx context.Context, key string) error
 	Clear(ctx context.Context, pattern string) error
-	InvalidateStudentCache(ctx context.Context, studentID uuid.UUID) error
+	// InvalidateStudentCache drops the student's cached views, their per-semester views only
+	// for the given semesters when any are given. InvalidateSectionCache drops the section's
+	// views and the available section lists. Neither touches seat counters.
+	InvalidateStudentCache(ctx context.Context, studentID uuid.UUID, semesterIDs ...uuid.UUID) error
 	InvalidateSectionCache(ctx context.Context, sectionID uuid.UUID) error
 
-	// Waitlist management using Redis sorted sets
-	AddToWaitlist(ctx context.Context, sectionID, studentID uuid.UUID, position int, entry interface{}) error
+	// Waitlist management using Redis sorted sets. AddToWaitlist refuses a student who is not
+	// already waiting once maxSize students are, with ErrWaitlistFull; maxSize 0 is no cap.
+	AddToWaitlist(ctx context.Context, sectionID, studentID uuid.UUID, position int, entry interface{}, maxSize int) error
 	RemoveFromWaitlist(ctx context.Context, sectionID, studentID uuid.UUID) error
+	// LeaveWaitlist removes a student and moves everyone behind them up one place. It returns
+	// the position the student held, or -1 if they were not on the waitlist.
+	LeaveWaitlist(ctx context.Context, sectionID, studentID uuid.UUID) (int, error)
+	// CompactWaitlist renumbers positions to 1..n after removals and returns the new
+	// position of every student that moved.
+	CompactWaitlist(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error)
 	GetNextInWaitlist(ctx context.Context, sectionID uuid.UUID) (interface{}, error)
 	GetWaitlistPosition(ctx context.Context, sectionID, studentID uuid.UUID) (int, error)
 	GetWaitlistSize(ctx context.Context, sectionID uuid.UUID) (int, error)
+	// GetWaitlistSizes returns the waitlist size of every section in one round trip
+	GetWaitlistSizes(ctx context.Context, sectionIDs []uuid.UUID) (map[uuid.UUID]int, error)
 	GetStudentWaitlists(ctx context.Context, studentID uuid.UUID) ([]interface{}, error)
 
+	// Distributed locks. AcquireLock stores token under key if the key is free; ReleaseLock
+	// deletes the key only while it still holds token.
+	AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, token string) (bool, error)
+
 	// Cache statistics and monitoring
 	GetCacheStats(ctx context.Context) (map[string]interface{}, error)
 
@@ -0,0 +1,120 @@
+package interfaces
+
+import "github.com/google/uuid"
+
+// CacheScope is the kind of entity a cached view belongs to
+type CacheScope string
+
+const (
+	CacheScopeStudent  CacheScope = "student"
+	CacheScopeSection  CacheScope = "section"
+	CacheScopeCourse   CacheScope = "course"
+	CacheScopeSemester CacheScope = "semester"
+)
+
+// CacheKeyFamily builds the keys of one kind of cached data: Prefix followed by the ID of
+// the entity it belongs to and, for per-semester views, the semester ID. The cache keeps a
+// registry of the live keys of Tracked families, so the whole family can be dropped without
+// scanning the keyspace.
+type CacheKeyFamily struct {
+	Prefix      string
+	Scope       CacheScope
+	PerSemester bool
+	Tracked     bool
+}
+
+// Key is the key of the entity's entry
+func (f CacheKeyFamily) Key(id uuid.UUID) string {
+	return f.Prefix + id.String()
+}
+
+// SemesterKey is the key of the entity's entry for one semester
+func (f CacheKeyFamily) SemesterKey(id, semesterID uuid.UUID) string {
+	return f.Prefix + id.String() + ":" + semesterID.String()
+}
+
+// Pattern matches the keys of every entity in the family
+func (f CacheKeyFamily) Pattern() string {
+	return f.Prefix + "*"
+}
+
+// cachedViews lists every family registered with newCachedView
+var cachedViews []CacheKeyFamily
+
+// newCachedView registers a family of cached views. Invalidating an entity drops every
+// registered view in its scope, so a new view is covered as soon as it is declared here.
+// Families that are dropped as a whole should be tracked.
+func newCachedView(prefix string, scope CacheScope, perSemester, tracked bool) CacheKeyFamily {
+	family := CacheKeyFamily{Prefix: prefix, Scope: scope, PerSemester: perSemester, Tracked: tracked}
+	cachedViews = append(cachedViews, family)
+	return family
+}
+
+// TrackedCacheView returns the tracked family whose Pattern is pattern
+func TrackedCacheView(pattern string) (CacheKeyFamily, bool) {
+	for _, family := range cachedViews {
+		if family.Tracked && family.Pattern() == pattern {
+			return family, true
+		}
+	}
+	return CacheKeyFamily{}, false
+}
+
+// Cached views: copies of database state that can be dropped at any time and rebuilt
+var (
+	StudentDetailsCache       = newCachedView("student:details:", CacheScopeStudent, false, false)
+	StudentRegistrationsCache = newCachedView("student:registrations:", CacheScopeStudent, false, false)
+	StudentWaitlistCache      = newCachedView("student:waitlist:", CacheScopeStudent, false, false)
+	StudentHoldsCache         = newCachedView("student:holds:", CacheScopeStudent, false, false)
+	StudentScheduleCache      = newCachedView("student:schedule:", CacheScopeStudent, true, false)
+	SectionDetailsCache       = newCachedView("section:details:", CacheScopeSection, false, true)
+	SectionRosterCache        = newCachedView("section:roster:", CacheScopeSection, false, false)
+	CourseDetailsCache        = newCachedView("course:details:", CacheScopeCourse, false, false)
+	AvailableSectionsCache    = newCachedView("sections:available:", CacheScopeSemester, false, true)
+	SemesterCalendarCache     = newCachedView("semester:calendar:", CacheScopeSemester, false, false)
+	SectionStatsReportCache   = newCachedView("reports:sections:", CacheScopeSemester, false, false)
+)
+
+// SectionSeatsKey holds the seat counter of a section, a hash with the fields of
+// SeatCounter. The counter is the source of truth for seats rather than a view, so it is
+// not registered and invalidation never drops it. The hashes took over from bare integer
+// keys under section:seats:, which are left to expire.
+var SectionSeatsKey = CacheKeyFamily{Prefix: "section:seat_counter:", Scope: CacheScopeSection}
+
+// SectionSeatPoolsKey holds a hash of the seats left in each seat pool of a section, next
+// to its seat counter and just as authoritative
+var SectionSeatPoolsKey = CacheKeyFamily{Prefix: "section:seat_pools:", Scope: CacheScopeSection}
+
+// PermissionNumberClaimKey is set when a permission number is used, holding the student
+// who used it, so two registrations cannot both get through on the same number
+var PermissionNumberClaimKey = CacheKeyFamily{Prefix: "permission:claimed:"}
+
+// InvalidationSet is what invalidating an entity drops: exact keys, and patterns for
+// per-semester views whose semesters are not known
+type InvalidationSet struct {
+	Keys     []string
+	Patterns []string
+}
+
+// CacheInvalidationSet returns the cached views of an entity. Per-semester views are
+// limited to the given semesters, or all semesters when none are given.
+func CacheInvalidationSet(scope CacheScope, id uuid.UUID, semesterIDs ...uuid.UUID) InvalidationSet {
+	var set InvalidationSet
+	for _, family := range cachedViews {
+		if family.Scope != scope {
+			continue
+		}
+		if !family.PerSemester {
+			set.Keys = append(set.Keys, family.Key(id))
+			continue
+		}
+		if len(semesterIDs) == 0 {
+			set.Patterns = append(set.Patterns, family.Key(id)+":*")
+			continue
+		}
+		for _, semesterID := range semesterIDs {
+			set.Keys = append(set.Keys, family.SemesterKey(id, semesterID))
+		}
+	}
+	return set
+}
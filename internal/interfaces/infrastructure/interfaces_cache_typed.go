@@ -0,0 +1,41 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrCacheUndecodable is returned by CacheGet when a value is cached but is not what the
+// caller asked for. Callers should treat it as a miss and overwrite the entry.
+var ErrCacheUndecodable = errors.New("cached value could not be decoded")
+
+// CacheGetter is the shape of the CacheService getters for JSON views, such as
+// GetStudentRegistrations or GetAvailableSections
+type CacheGetter func(ctx context.Context, id uuid.UUID) (interface{}, error)
+
+// CacheGet reads a view through get and decodes it into T. Cache backends hand views back
+// as json.RawMessage; a backend that already holds a T is returned as is.
+func CacheGet[T any](ctx context.Context, get CacheGetter, id uuid.UUID) (T, error) {
+	var value T
+
+	cached, err := get(ctx, id)
+	if err != nil {
+		return value, err
+	}
+
+	switch cached := cached.(type) {
+	case T:
+		return cached, nil
+	case json.RawMessage:
+		if err := json.Unmarshal(cached, &value); err != nil {
+			return value, fmt.Errorf("%w: %v", ErrCacheUndecodable, err)
+		}
+		return value, nil
+	default:
+		return value, fmt.Errorf("%w: unexpected %T", ErrCacheUndecodable, cached)
+	}
+}
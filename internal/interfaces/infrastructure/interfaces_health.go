@@ -0,0 +1,32 @@
+package interfaces
+
+import "context"
+
+// DatabaseHealth checks the database the registrations are kept in
+type DatabaseHealth interface {
+	Ping(ctx context.Context) error
+	// PendingMigrations returns the IDs of the migrations not yet applied
+	PendingMigrations(ctx context.Context) ([]string, error)
+}
+
+// SentinelState is what the Sentinels report about the Redis master
+type SentinelState struct {
+	MasterName string   `json:"master_name"`
+	MasterAddr string   `json:"master_addr,omitempty"`
+	Flags      []string `json:"flags,omitempty"`
+	// Reachable counts the Sentinels that answered, of Configured
+	Reachable  int `json:"reachable"`
+	Configured int `json:"configured"`
+	// FailoverInProgress is set while the Sentinels are promoting a replica; writes fail
+	// until it finishes
+	FailoverInProgress bool `json:"failover_in_progress"`
+	// MasterDown is set when a Sentinel considers the master unreachable
+	MasterDown bool `json:"master_down"`
+}
+
+// RedisHealth checks the Redis behind the seat counters, queues and locks
+type RedisHealth interface {
+	Ping(ctx context.Context) error
+	// Sentinel asks the Sentinels about the master. It fails only when none answers.
+	Sentinel(ctx context.Context) (SentinelState, error)
+}
@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KPIMetric string
+
+const (
+	KPIRegistrations KPIMetric = "registrations"
+	KPIDrops         KPIMetric = "drops"
+	KPIWaitlistAdds  KPIMetric = "waitlist_adds"
+)
+
+var KPIMetrics = []KPIMetric{KPIRegistrations, KPIDrops, KPIWaitlistAdds}
+
+// KPICounterStore keeps cheap running counters for the live registration dashboard so it
+// never has to aggregate the registrations table while registration is open.
+type KPICounterStore interface {
+	// Increment counts one occurrence of metric in a section during the minute containing at
+	Increment(ctx context.Context, metric KPIMetric, sectionID uuid.UUID, at time.Time) error
+	// PerMinute returns the counts of the given number of minutes ending with the minute
+	// containing until, oldest first
+	PerMinute(ctx context.Context, metric KPIMetric, until time.Time, minutes int) ([]int64, error)
+	// SectionTotals returns the all-time count of metric for every section that has one
+	SectionTotals(ctx context.Context, metric KPIMetric) (map[uuid.UUID]int64, error)
+}
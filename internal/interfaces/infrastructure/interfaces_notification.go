@@ -0,0 +1,63 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StudentEventType string
+
+const (
+	// StudentEventWaitlistPromoted means the student was taken off a waitlist and enrolled
+	StudentEventWaitlistPromoted StudentEventType = "waitlist_promoted"
+	// StudentEventSeatOffered means a freed seat is being held for the student until the
+	// offer expires
+	StudentEventSeatOffered StudentEventType = "seat_offered"
+	// StudentEventWaitlistExpired means the student's waitlist entry ran out and they were
+	// taken off the waitlist
+	StudentEventWaitlistExpired StudentEventType = "waitlist_expired"
+	// StudentEventDeadlineReminder warns the student that a registration deadline is close
+	// while they are still waitlisted or their schedule is incomplete
+	StudentEventDeadlineReminder StudentEventType = "deadline_reminder"
+	// StudentEventHoldPlaced means a hold now keeps the student from registering
+	StudentEventHoldPlaced StudentEventType = "hold_placed"
+	// StudentEventHoldReleased means a hold was lifted
+	StudentEventHoldReleased StudentEventType = "hold_released"
+	// StudentEventOverrideApproved means an advisor approved a schedule override; the
+	// registration made on approval is in the student's registrations
+	StudentEventOverrideApproved StudentEventType = "schedule_override_approved"
+	// StudentEventOverrideDenied means an advisor turned a schedule override down
+	StudentEventOverrideDenied StudentEventType = "schedule_override_denied"
+)
+
+// Reasons a deadline reminder is sent
+const (
+	ReminderReasonPendingWaitlist    = "pending_waitlist"
+	ReminderReasonIncompleteSchedule = "incomplete_schedule"
+)
+
+// StudentEvent is a real-time notification for one student
+type StudentEvent struct {
+	Type       StudentEventType `json:"type"`
+	StudentID  uuid.UUID        `json:"student_id"`
+	SectionID  uuid.UUID        `json:"section_id"`
+	OfferID    *uuid.UUID       `json:"offer_id,omitempty"`
+	ExpiresAt  *time.Time       `json:"expires_at,omitempty"`
+	SemesterID *uuid.UUID       `json:"semester_id,omitempty"`
+	DeadlineAt *time.Time       `json:"deadline_at,omitempty"`
+	HoldID     *uuid.UUID       `json:"hold_id,omitempty"`
+	OverrideID *uuid.UUID       `json:"override_id,omitempty"`
+	Reason     string           `json:"reason,omitempty"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+// StudentNotifier fans student events out to every API instance, since the instance that
+// promotes a student is rarely the one holding that student's connection.
+type StudentNotifier interface {
+	Publish(ctx context.Context, event StudentEvent) error
+	// SubscribeAll delivers the events of every student until ctx is cancelled, then closes
+	// the channel
+	SubscribeAll(ctx context.Context) (<-chan StudentEvent, error)
+}
@@ -1,7 +1,9 @@
 package interfaces
 
 import (
+	domain "cobra-template/internal/domain/registration"
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,9 +12,11 @@ import (
 type JobType string
 
 const (
-	JobTypeCreateRegistration JobType = "create_registration"
-	JobTypeUpdateSeats        JobType = "update_seats"
-	JobTypeDropRegistration   JobType = "drop_registration"
+	JobTypeCreateRegistration  JobType = "create_registration"
+	JobTypePromoteRegistration JobType = "promote_registration"
+	JobTypeUpdateSeats         JobType = "update_seats"
+	JobTypeDropRegistration    JobType = "drop_registration"
+	JobTypeCompactWaitlist     JobType = "compact_waitlist"
 )
 
 type Status string
@@ -32,28 +36,170 @@ type RegistrationJob struct {
 }
 
 type DatabaseSyncJob struct {
-	JobType   JobType   `json:"job_type"` // "create_registration", "update_seats", "drop_registration"
-	Status    Status    `json:"status"`   // "enrolled", "failed", "dropped", "waitlisted"
-	StudentID uuid.UUID `json:"student_id"`
-	SectionID uuid.UUID `json:"section_id"`
-	Timestamp time.Time `json:"timestamp"`
+	JobID     string     `json:"job_id"`
+	JobType   JobType    `json:"job_type"` // "create_registration", "promote_registration", "update_seats", "drop_registration", "compact_waitlist"
+	Status    Status     `json:"status"`   // "enrolled", "failed", "dropped", "waitlisted"
+	StudentID uuid.UUID  `json:"student_id"`
+	SectionID uuid.UUID  `json:"section_id"`
+	Timestamp time.Time  `json:"timestamp"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	FailedAt  *time.Time `json:"failed_at,omitempty"`
+	// DedupKey identifies the intent behind the job. Jobs delivered through the outbox may
+	// arrive more than once; a job whose key was already processed is skipped.
+	DedupKey string `json:"dedup_key,omitempty"`
+	// SeatFreedAt is when the seat filled by a waitlist promotion was freed, carried along
+	// to measure how long the promotion took
+	SeatFreedAt *time.Time `json:"seat_freed_at,omitempty"`
+	// RequestID is the ID of the API request that caused the job, so worker logs can be
+	// tied back to it
+	RequestID string `json:"request_id,omitempty"`
+	// SeatPoolID is the seat pool a registration job's seat was taken from
+	SeatPoolID *uuid.UUID `json:"seat_pool_id,omitempty"`
 }
 
 type WaitlistJob struct {
-	StudentID uuid.UUID `json:"student_id"`
-	SectionID uuid.UUID `json:"section_id"`
-	Position  int       `json:"position"`
-	Timestamp time.Time `json:"timestamp"`
+	StudentID uuid.UUID  `json:"student_id"`
+	SectionID uuid.UUID  `json:"section_id"`
+	Position  int        `json:"position"`
+	Timestamp time.Time  `json:"timestamp"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// LinkedSectionID is the other section of a linked pair the student waits for together
+	LinkedSectionID *uuid.UUID `json:"linked_section_id,omitempty"`
+	RequestID       string     `json:"request_id,omitempty"`
+}
+
+// WaitlistPromotionJob asks for the next waitlisted student to be promoted into a freed
+// seat. SeatEventID identifies the seat-open event that caused it, so duplicates of the
+// same event promote only once. Timestamp is when the seat was freed.
+type WaitlistPromotionJob struct {
+	SectionID   uuid.UUID `json:"section_id"`
+	SeatEventID string    `json:"seat_event_id,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// ReminderJob sends the reminders due ahead of one semester deadline. ReminderID is derived
+// from the semester, the deadline and the lead time, so planning the same reminder again
+// does not schedule it twice.
+type ReminderJob struct {
+	ReminderID   string    `json:"reminder_id"`
+	SemesterID   uuid.UUID `json:"semester_id"`
+	DeadlineType string    `json:"deadline_type"`
+	DeadlineAt   time.Time `json:"deadline_at"`
+	Attempts     int       `json:"attempts"`
+}
+
+// QueueNames are the configured names of the job queues. They are used for the Redis keys
+// and as queue labels on metrics and poison jobs, so environments sharing one Redis and one
+// metrics backend stay apart.
+type QueueNames struct {
+	DatabaseSync  string `json:"database_sync"`
+	Waitlist      string `json:"waitlist"`
+	WaitlistEntry string `json:"waitlist_entry"`
+	Poison        string `json:"poison"`
+	Reminders     string `json:"reminders"`
+	Audit         string `json:"audit"`
 }
 
 type QueueService interface {
 	EnqueueDatabaseSync(ctx context.Context, job DatabaseSyncJob) error
 	DequeueDatabaseSync(ctx context.Context) (*DatabaseSyncJob, error)
-	EnqueueWaitlistProcessing(ctx context.Context, sectionID uuid.UUID) error
-	DequeueWaitlistProcessing(ctx context.Context) (uuid.UUID, error)
+	EnqueueWaitlistProcessing(ctx context.Context, job WaitlistPromotionJob) error
+	DequeueWaitlistProcessing(ctx context.Context) (*WaitlistPromotionJob, error)
 	EnqueueWaitlistEntry(ctx context.Context, job WaitlistJob) error
 	DequeueWaitlistEntry(ctx context.Context) (*WaitlistJob, error)
+	// ScheduleReminder runs job at the given time. It reports false when a job with the same
+	// ReminderID is already scheduled.
+	ScheduleReminder(ctx context.Context, job ReminderJob, at time.Time) (bool, error)
 	SetRegistrationService(service interface{})
+	// SetReminderService enables the reminder workers. Without it no reminders are planned or sent.
+	SetReminderService(service interface{})
+	// EnqueueAuditEvent hands an audit event to the workers that write it to repo
+	EnqueueAuditEvent(ctx context.Context, event domain.AuditEvent) error
+	// SetAuditRepository enables the audit workers. Without it audit events are not written.
+	SetAuditRepository(repo AuditRepository)
 	StartWorkers()
 	StopWorkers()
+	// Names reports the queue names in use
+	Names() QueueNames
+}
+
+// DeadLetterQueue is implemented by queues that park database sync jobs after their retries
+// are exhausted, so operators can inspect and replay them.
+type DeadLetterQueue interface {
+	ListDeadDatabaseSyncJobs(ctx context.Context, offset, limit int) ([]DatabaseSyncJob, int64, error)
+	// ReplayDeadDatabaseSyncJobs re-enqueues the given dead jobs, or all of them when jobIDs is empty
+	ReplayDeadDatabaseSyncJobs(ctx context.Context, jobIDs []string) (int, error)
+}
+
+// PoisonJob is a job whose handler panicked. It is set aside rather than retried, since a
+// retry would most likely panic again.
+type PoisonJob struct {
+	Queue    string          `json:"queue"`
+	Payload  json.RawMessage `json:"payload"`
+	Panic    string          `json:"panic"`
+	Stack    string          `json:"stack"`
+	FailedAt time.Time       `json:"failed_at"`
+}
+
+// PoisonQueue is implemented by queues that isolate jobs whose handler panicked
+type PoisonQueue interface {
+	ListPoisonJobs(ctx context.Context, offset, limit int) ([]PoisonJob, int64, error)
+}
+
+// DrainStatus reports the progress of a queue drain. Jobs still in progress when the drain
+// gives up are requeued, left for the broker to deliver again, or, where the queue cannot
+// keep them, abandoned and logged in full.
+type DrainStatus struct {
+	Draining    bool       `json:"draining"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	InFlight    int        `json:"in_flight"`
+	TimedOut    bool       `json:"timed_out"`
+	Requeued    int        `json:"requeued"`
+	Redelivered int        `json:"redelivered"`
+	Abandoned   int        `json:"abandoned"`
+}
+
+// DrainableQueue is implemented by queues that can shut down without losing the jobs they
+// have taken
+type DrainableQueue interface {
+	// Drain stops taking new jobs, waits up to timeout for the jobs in progress and puts back
+	// those that did not finish, then stops the workers. It is used instead of StopWorkers.
+	Drain(timeout time.Duration) DrainStatus
+	DrainStatus() DrainStatus
+}
+
+// WorkerStatus reports on a queue's workers. Expected counts the workers started and
+// Running those still running; a worker that returned early, or a stopped queue, leaves
+// Running short.
+type WorkerStatus struct {
+	Started  bool  `json:"started"`
+	Expected int   `json:"expected"`
+	Running  int   `json:"running"`
+	Restarts int64 `json:"restarts"`
+}
+
+// WorkerHealth is implemented by queues that report whether their workers are alive
+type WorkerHealth interface {
+	WorkerStatus() WorkerStatus
+}
+
+// OutboxEntry is a database sync job waiting in the outbox
+type OutboxEntry struct {
+	ID  string
+	Job DatabaseSyncJob
+}
+
+// SyncOutbox holds database sync jobs written in the same atomic step as the seat change
+// they record, until a dispatcher has handed them to the queue. An entry stays in the outbox
+// until it is acknowledged, so a dispatcher that dies mid-delivery leaves it for another.
+type SyncOutbox interface {
+	// Read claims up to count entries for consumer: first those another consumer claimed
+	// more than minIdle ago without acknowledging, then new ones, waiting up to block for
+	// those
+	Read(ctx context.Context, consumer string, count int, block, minIdle time.Duration) ([]OutboxEntry, error)
+	Ack(ctx context.Context, ids ...string) error
+	// Pending counts entries not yet acknowledged
+	Pending(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitResult is the outcome of one rate limited request
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAt is when the current window ends and the budget is refilled
+	ResetAt time.Time
+}
+
+// RateLimiter counts requests per key in fixed windows shared by every instance
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+}
@@ -3,15 +3,32 @@ package interfaces
 import (
 	domain "cobra-template/internal/domain/registration"
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrSectionVersionConflict is returned by SectionRepository.UpdateWithOptimisticLock when
+// the section changed after it was read. Reading it again and retrying is safe.
+var ErrSectionVersionConflict = errors.New("optimistic lock failure: section has been modified by another process")
+
+// ErrAlreadyRegistered is returned by RegistrationRepository.EnrollLocked when the student
+// already has a registration in the section
+var ErrAlreadyRegistered = errors.New("student already has a registration in the section")
+
 type StudentRepository interface {
 	Create(ctx context.Context, student *domain.Student) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Student, error)
 	GetByStudentNumber(ctx context.Context, studentNumber string) (*domain.Student, error)
 	GetRecentlyActive(ctx context.Context, limit int) ([]*domain.Student, error)
+	GetByEmail(ctx context.Context, email string) (*domain.Student, error)
+	// Archive marks the given students, and every student whose number starts with
+	// studentNumberPrefix when it is not empty, as archived. It returns the students that changed.
+	Archive(ctx context.Context, studentIDs []uuid.UUID, studentNumberPrefix string, archivedAt time.Time) ([]uuid.UUID, error)
+	UpdateProfile(ctx context.Context, student *domain.Student) error
+	// Update writes every field an administrator maintains and bumps the student's version
+	Update(ctx context.Context, student *domain.Student) error
 }
 
 type CourseRepository interface {
@@ -19,21 +36,87 @@ type CourseRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Course, error)
 	GetByCode(ctx context.Context, courseCode string) (*domain.Course, error)
 	GetAllActive(ctx context.Context) ([]*domain.Course, error)
+	// List returns the courses ordered by course code, inactive ones only if asked for
+	List(ctx context.Context, includeInactive bool) ([]*domain.Course, error)
+	// Update writes the name, department and credit hours of the course and bumps its version
+	Update(ctx context.Context, course *domain.Course) error
+	SetActive(ctx context.Context, courseID uuid.UUID, active bool) error
+	// UpdateTags replaces the catalog tags and attributes of a course
+	UpdateTags(ctx context.Context, courseID uuid.UUID, tags []string, attributes map[string]string) error
+	// Search returns the active courses after afterCode, ordered by course code, that have an
+	// active section matching filter
+	Search(ctx context.Context, filter CourseSearchFilter, afterCode string, limit int) ([]*domain.Course, error)
+}
+
+// CourseSearchFilter narrows a catalog search. Zero values do not filter. The course
+// fields select courses; the others select their sections.
+type CourseSearchFilter struct {
+	CodePrefix string
+	Title      string
+	Department string
+	MinCredits *int
+	MaxCredits *int
+	SemesterID *uuid.UUID
+	// Days keeps sections that meet only on these day letters
+	Days string
+	// StartAfter and EndBefore keep sections meeting within the HH:MM bounds
+	StartAfter    string
+	EndBefore     string
+	OnlyWithSeats bool
 }
 
 type SemesterRepository interface {
 	Create(ctx context.Context, semester *domain.Semester) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Semester, error)
+	GetByCode(ctx context.Context, code string) (*domain.Semester, error)
 	GetCurrent(ctx context.Context) (*domain.Semester, error)
 	GetAllActive(ctx context.Context) ([]*domain.Semester, error)
+	// GetAll returns every semester, inactive ones included, ordered by start date
+	GetAll(ctx context.Context) ([]*domain.Semester, error)
+	Update(ctx context.Context, semester *domain.Semester) error
+	// Archive moves the semester's registrations and waitlist entries to the archive tables
+	// and marks it archived and inactive. It returns how many rows were moved, nil when the
+	// semester was archived already.
+	Archive(ctx context.Context, semesterID uuid.UUID) (*ArchivedSemesterRows, error)
+}
+
+// ArchivedSemesterRows counts the rows a semester's archiving moved
+type ArchivedSemesterRows struct {
+	Registrations   int64
+	WaitlistEntries int64
+}
+
+type CalendarEventRepository interface {
+	Create(ctx context.Context, event *domain.CalendarEvent) error
+	// GetBySemesterID returns the key dates of a semester ordered by start time
+	GetBySemesterID(ctx context.Context, semesterID uuid.UUID) ([]*domain.CalendarEvent, error)
 }
 
 type SectionRepository interface {
 	Create(ctx context.Context, section *domain.Section) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Section, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*domain.Section, error)
 	UpdateWithOptimisticLock(ctx context.Context, section *domain.Section) error
+	UpdateCapacity(ctx context.Context, sectionID uuid.UUID, totalSeats, availableSeats int) error
+	SetActive(ctx context.Context, sectionID uuid.UUID, active bool) error
+	SetWaitlistFreeze(ctx context.Context, sectionID uuid.UUID, frozen, promotionsPaused bool) error
+	// SetWaitlistTTL sets how many hours waitlist entries of the section last; nil goes back
+	// to the configured default
+	SetWaitlistTTL(ctx context.Context, sectionID uuid.UUID, ttlHours *int) error
+	// SetPolicy replaces the registration policy of a section
+	SetPolicy(ctx context.Context, sectionID uuid.UUID, policy domain.SectionPolicy) error
+	// ReplaceSeatPools replaces the seat pools of a section. A pool named like an existing
+	// one takes over its ID, so registrations keep pointing at it.
+	ReplaceSeatPools(ctx context.Context, sectionID uuid.UUID, pools []domain.SeatPool) ([]domain.SeatPool, error)
+	// UpdateTags replaces the catalog tags and attributes of a section
+	UpdateTags(ctx context.Context, sectionID uuid.UUID, tags []string, attributes map[string]string) error
 	GetByCourseAndSemester(ctx context.Context, courseID, semesterID uuid.UUID) ([]*domain.Section, error)
 	GetBySemester(ctx context.Context, semesterID uuid.UUID) ([]*domain.Section, error)
+	// SearchByCourses returns the active sections of the courses that match the section
+	// fields of filter
+	SearchByCourses(ctx context.Context, courseIDs []uuid.UUID, filter CourseSearchFilter) ([]*domain.Section, error)
+	// GetAllActive returns the active sections with seats left; these are the sections whose
+	// seat counters are warmed up
 	GetAllActive(ctx context.Context) ([]*domain.Section, error)
 }
 
@@ -43,6 +126,37 @@ type RegistrationRepository interface {
 	Update(ctx context.Context, registration *domain.Registration) error
 	GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.Registration, error)
 	GetBySectionID(ctx context.Context, sectionID uuid.UUID) ([]*domain.Registration, error)
+	// GetByStudentAndCourse returns the student's registrations in every section of the
+	// course, across all semesters, archived ones included, with their sections
+	GetByStudentAndCourse(ctx context.Context, studentID, courseID uuid.UUID) ([]*domain.Registration, error)
+	// CountEnrolledBySection returns the enrolled registrations of each section of a semester
+	CountEnrolledBySection(ctx context.Context, semesterID uuid.UUID) (map[uuid.UUID]int, error)
+	// CountEnrolledBySeatPool returns the enrolled registrations of a section that took a
+	// seat from each of its seat pools
+	CountEnrolledBySeatPool(ctx context.Context, sectionID uuid.UUID) (map[uuid.UUID]int, error)
+	// CountBySectionStatus counts the registrations and waitlist entries of every section of
+	// a semester, archived or not, ordered by course code and section number
+	CountBySectionStatus(ctx context.Context, semesterID uuid.UUID) ([]SectionEnrollmentCounts, error)
+	// EnrollLocked takes a seat from the section row and writes the registration in one
+	// transaction, holding the student and section rows locked so concurrent enrollments
+	// queue up behind it. heldSeats of the section's available seats are not offered. It
+	// returns the section's seats and version as the enrollment left them, or
+	// ErrNoSeatsLeft or ErrAlreadyRegistered.
+	EnrollLocked(ctx context.Context, registration *domain.Registration, heldSeats int) (*domain.Section, error)
+}
+
+// SectionEnrollmentCounts is where the registrations of one section stand. LastSeatTakenAt
+// is when the latest registration still holding a seat was made.
+type SectionEnrollmentCounts struct {
+	SectionID       uuid.UUID
+	CourseCode      string
+	SectionNumber   string
+	TotalSeats      int
+	Enrolled        int
+	Withdrawn       int
+	Dropped         int
+	Waitlisted      int
+	LastSeatTakenAt *time.Time
 }
 
 type WaitlistRepository interface {
@@ -53,11 +167,162 @@ type WaitlistRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetBySectionID(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, error)
 	GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.WaitlistEntry, error)
+	// RenumberPositions closes gaps left by removals, numbering entries 1..n in queue order.
+	// It returns the entries whose position changed.
+	RenumberPositions(ctx context.Context, sectionID uuid.UUID) ([]*domain.WaitlistEntry, error)
+	// GetExpired returns up to limit entries whose expiry is before the given time, the
+	// longest expired first
+	GetExpired(ctx context.Context, before time.Time, limit int) ([]*domain.WaitlistEntry, error)
+}
+
+type PermissionNumberRepository interface {
+	Create(ctx context.Context, permissions []*domain.PermissionNumber) error
+	GetByCode(ctx context.Context, code string) (*domain.PermissionNumber, error)
+	ListBySection(ctx context.Context, sectionID uuid.UUID) ([]*domain.PermissionNumber, error)
+	// MarkUsed records the use of a number that was not used yet, and reports whether it did
+	MarkUsed(ctx context.Context, permissionID, studentID uuid.UUID, usedAt time.Time) (bool, error)
 }
 
 type IdempotencyRepository interface {
 	Create(ctx context.Context, key *domain.IdempotencyKey) error
+	// Reserve stores key for ttl only if nothing is stored under it yet, and reports whether
+	// it did, so only one of several concurrent requests with the same key goes ahead
+	Reserve(ctx context.Context, key *domain.IdempotencyKey, ttl time.Duration) (bool, error)
 	GetByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error)
 	DeleteExpired(ctx context.Context) error
 	Delete(ctx context.Context, key string) error
 }
+
+type SeatOfferRepository interface {
+	Create(ctx context.Context, offer *domain.SeatOffer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.SeatOffer, error)
+	GetByStudentID(ctx context.Context, studentID uuid.UUID) ([]*domain.SeatOffer, error)
+	// TransitionStatus atomically moves an offer from one status to another and
+	// reports whether the transition happened.
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.SeatOfferStatus) (bool, error)
+	// PopExpired removes and returns up to limit offer IDs whose expiry is before the given time.
+	PopExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+type SeatHoldRepository interface {
+	Create(ctx context.Context, hold *domain.SeatHold) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.SeatHold, error)
+	// GetActive returns the unconfirmed hold of a student on a section, or nil
+	GetActive(ctx context.Context, studentID, sectionID uuid.UUID) (*domain.SeatHold, error)
+	// TransitionStatus atomically moves a hold from one status to another and reports
+	// whether the transition happened.
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.SeatHoldStatus) (bool, error)
+	// PopExpired removes and returns up to limit hold IDs whose expiry is before the given time.
+	PopExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// RegistrationEventRepository is the append-only event store backing the event-sourced
+// persistence mode.
+type RegistrationEventRepository interface {
+	Append(ctx context.Context, event *domain.RegistrationEvent) error
+	// GetBySection returns the events of a section after the given sequence, oldest first.
+	// A zero until returns all of them, otherwise only events that occurred at or before it.
+	GetBySection(ctx context.Context, sectionID uuid.UUID, afterSequence int64, until time.Time) ([]*domain.RegistrationEvent, error)
+	CountSince(ctx context.Context, sectionID uuid.UUID, afterSequence int64) (int64, error)
+	// GetLatestSnapshot returns the newest snapshot taken at or before asOf, or nil.
+	GetLatestSnapshot(ctx context.Context, sectionID uuid.UUID, asOf time.Time) (*domain.RegistrationSnapshot, error)
+	SaveSnapshot(ctx context.Context, snapshot *domain.RegistrationSnapshot) error
+}
+
+type RegistrationTransitionRepository interface {
+	Append(ctx context.Context, transition *domain.RegistrationTransition) error
+	// ListByStudent returns the student's transitions in every section, oldest first
+	ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*domain.RegistrationTransition, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	// List returns every key, newest first. Revoked keys are included only when asked for.
+	List(ctx context.Context, includeRevoked bool) ([]*domain.APIKey, error)
+	// Rotate stores replacement and sets the expiry of the key it replaces in one transaction
+	Rotate(ctx context.Context, replacement *domain.APIKey, oldKeyID uuid.UUID, oldExpiresAt time.Time) error
+	// Revoke marks a key revoked and reports whether it was active before
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type ApprovalRepository interface {
+	Create(ctx context.Context, approval *domain.AdminApproval) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AdminApproval, error)
+	// GetPending returns the open proposal for the operation on target, if any
+	GetPending(ctx context.Context, operation string, targetID uuid.UUID) (*domain.AdminApproval, error)
+	// List returns approvals newest first, only those in status unless it is empty
+	List(ctx context.Context, status domain.ApprovalStatus) ([]*domain.AdminApproval, error)
+	// Decide moves a pending approval to status and reports whether it was still pending, so
+	// two admins deciding at once cannot both win
+	Decide(ctx context.Context, id uuid.UUID, status domain.ApprovalStatus, decidedBy *string, at time.Time) (bool, error)
+	// Complete records the outcome of an approved operation
+	Complete(ctx context.Context, id uuid.UUID, status domain.ApprovalStatus, result string) error
+}
+
+type StudentHoldRepository interface {
+	Create(ctx context.Context, hold *domain.StudentHold) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.StudentHold, error)
+	// GetByExternalRef returns the hold another system placed under its own reference
+	GetByExternalRef(ctx context.Context, source, externalRef string) (*domain.StudentHold, error)
+	// GetActiveByStudent returns the student's holds that have not been released, oldest first
+	GetActiveByStudent(ctx context.Context, studentID uuid.UUID) ([]*domain.StudentHold, error)
+	// Release marks a hold released and reports whether it was active before
+	Release(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+}
+
+type ScheduleOverrideRepository interface {
+	Create(ctx context.Context, override *domain.ScheduleOverride) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleOverride, error)
+	// GetOpen returns the student's pending or approved override for the section
+	GetOpen(ctx context.Context, studentID, sectionID uuid.UUID) (*domain.ScheduleOverride, error)
+	// ListByStudent returns the student's overrides, newest first
+	ListByStudent(ctx context.Context, studentID uuid.UUID) ([]*domain.ScheduleOverride, error)
+	// ListByStatus returns up to limit overrides in status, oldest first
+	ListByStatus(ctx context.Context, status domain.ScheduleOverrideStatus, limit int) ([]*domain.ScheduleOverride, error)
+	// Decide moves a pending override to status and reports whether it was still pending
+	Decide(ctx context.Context, id uuid.UUID, status domain.ScheduleOverrideStatus, decidedBy, note string, at time.Time) (bool, error)
+	// RecordRegistration stores the outcome of the registration made on approval
+	RecordRegistration(ctx context.Context, id uuid.UUID, status, message string) error
+}
+
+type AuditRepository interface {
+	// Create stores an event; storing one that was already stored changes nothing, since the
+	// queue may deliver an event twice
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	// List returns the events matching filter, newest first
+	List(ctx context.Context, filter AuditEventFilter) ([]*domain.AuditEvent, error)
+}
+
+// AuditEventFilter narrows an audit log query. Zero values do not filter; From is
+// inclusive and To exclusive.
+type AuditEventFilter struct {
+	StudentID     *uuid.UUID
+	SectionID     *uuid.UUID
+	Action        domain.AuditAction
+	CorrelationID string
+	From          *time.Time
+	To            *time.Time
+	Limit         int
+}
+
+// RegistrationDayCount is how many of a semester's enrollments were made on one day of its
+// registration window, day 0 being the day registration opened
+type RegistrationDayCount struct {
+	SemesterID uuid.UUID
+	Day        int
+	Count      int
+}
+
+type EnrollmentForecastRepository interface {
+	// GetRegistrationCurves counts the enrolled registrations of every semester whose
+	// registration closed before the given time, per day of its registration window
+	GetRegistrationCurves(ctx context.Context, closedBefore time.Time) ([]RegistrationDayCount, error)
+	// ReplaceForSemester swaps the semester's forecasts for the given ones in one transaction
+	ReplaceForSemester(ctx context.Context, semesterID uuid.UUID, forecasts []*domain.EnrollmentForecast) error
+	// GetBySemester returns the semester's forecasts ordered by course code and section,
+	// only those of department unless it is empty
+	GetBySemester(ctx context.Context, semesterID uuid.UUID, department string) ([]*domain.EnrollmentForecast, error)
+}
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size_bytes"`
+	ContentType  string    `json:"content_type,omitempty"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// StorageService stores generated files such as exports, archives and profiles. Keys are
+// slash separated paths relative to the configured bucket or base directory.
+type StorageService interface {
+	Put(ctx context.Context, key string, reader io.Reader, size int64, contentType string) error
+	// Get returns ErrObjectNotFound when the key does not exist. Callers must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited URL that can be used to download the object without
+	// further authentication.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// SignedURLVerifier is implemented by storage backends that serve their own signed URLs
+// through the API instead of a cloud provider.
+type SignedURLVerifier interface {
+	VerifySignedURL(key string, expires int64, signature string) error
+}
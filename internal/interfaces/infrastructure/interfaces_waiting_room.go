@@ -0,0 +1,31 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// WaitingTicket is a caller's place in the registration waiting room
+type WaitingTicket struct {
+	Token string `json:"token"`
+	// Position is 1 for the next caller to be admitted and 0 once admitted
+	Position int  `json:"position"`
+	Admitted bool `json:"admitted"`
+}
+
+// WaitingRoom bounds the registrations in flight across every instance. Callers over the
+// limit queue up in arrival order and are admitted as slots free up.
+type WaitingRoom interface {
+	// Enter takes a slot for slotID if one is free and nobody is queued ahead. The slot is
+	// held until Release or until ttl passes.
+	Enter(ctx context.Context, slotID string, limit int, ttl time.Duration) (bool, error)
+	// Join queues token at the back of the line
+	Join(ctx context.Context, token string) (*WaitingTicket, error)
+	// Poll keeps token in line and admits queued callers into free slots. An admitted caller
+	// holds its slot for admitTTL. It returns nil for tokens that are unknown or have lapsed.
+	Poll(ctx context.Context, token string, limit int, admitTTL time.Duration) (*WaitingTicket, error)
+	// Claim turns an admitted token's slot into an in-flight slot held for ttl. It reports
+	// false when the token was not admitted or its admission has lapsed.
+	Claim(ctx context.Context, token string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, slotID string) error
+}
@@ -4,6 +4,7 @@ import (
 	domain "cobra-template/internal/domain/registration"
 	infrastructure "cobra-template/internal/interfaces/infrastructure"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,6 +13,8 @@ type RegisterRequest struct {
 	StudentID      uuid.UUID   `json:"student_id" validate:"required"`
 	SectionIDs     []uuid.UUID `json:"section_ids" validate:"required,min=1"`
 	IdempotencyKey string      `json:"idempotency_key,omitempty" validate:"omitempty,min=1,max=255"`
+	// PermissionCode is a permission number for one of the sections
+	PermissionCode string `json:"permission_code,omitempty" validate:"omitempty,max=20"`
 }
 
 type RegisterResponse struct {
@@ -23,14 +26,442 @@ type RegistrationResult struct {
 	Status    string    `json:"status"`
 	Message   string    `json:"message"`
 	Position  *int      `json:"waitlist_position,omitempty"`
+	// HoldTypes are the kinds of hold that blocked the registration
+	HoldTypes []string `json:"hold_types,omitempty"`
+	// AvailableSeats and EnrolledCount are where the section stands once the student is
+	// enrolled, as the seat counter read when the seat was taken
+	AvailableSeats *int `json:"available_seats,omitempty"`
+	EnrolledCount  *int `json:"enrolled_count,omitempty"`
 }
+
+type EligibilityCheckStatus string
+
+const (
+	CheckPassed  EligibilityCheckStatus = "pass"
+	CheckFailed  EligibilityCheckStatus = "fail"
+	CheckWarning EligibilityCheckStatus = "warn"
+)
+
+type EligibilityCheck struct {
+	Name   string                 `json:"name"`
+	Status EligibilityCheckStatus `json:"status"`
+	Reason string                 `json:"reason"`
+}
+
+// EligibilityResponse is the outcome of a registration pre-check. Eligible is false when any
+// check failed; warnings do not block registration.
+type EligibilityResponse struct {
+	StudentID uuid.UUID          `json:"student_id"`
+	SectionID uuid.UUID          `json:"section_id"`
+	Eligible  bool               `json:"eligible"`
+	Checks    []EligibilityCheck `json:"checks"`
+}
+
+// CreateCourseRequest adds a course to the catalog. The course code is stored in upper case
+// and cannot be changed afterwards.
+type CreateCourseRequest struct {
+	CourseCode  string `json:"course_code" validate:"required,min=2,max=20"`
+	CourseName  string `json:"course_name" validate:"required,max=200"`
+	Department  string `json:"department,omitempty" validate:"omitempty,max=20"`
+	CreditHours int    `json:"credit_hours" validate:"min=0,max=30"`
+	AllowRepeat bool   `json:"allow_repeat,omitempty"`
+}
+
+// UpdateCourseRequest is a partial update: omitted fields are left unchanged
+type UpdateCourseRequest struct {
+	CourseName  *string `json:"course_name,omitempty" validate:"omitempty,min=1,max=200"`
+	Department  *string `json:"department,omitempty" validate:"omitempty,max=20"`
+	CreditHours *int    `json:"credit_hours,omitempty" validate:"omitempty,min=0,max=30"`
+	AllowRepeat *bool   `json:"allow_repeat,omitempty"`
+}
+
+// CreateSemesterRequest adds a term. StartDate and EndDate are calendar dates
+// (YYYY-MM-DD); the registration window is a pair of instants.
+type CreateSemesterRequest struct {
+	SemesterCode      string     `json:"semester_code" validate:"required,min=2,max=20"`
+	SemesterName      string     `json:"semester_name" validate:"required,max=100"`
+	StartDate         string     `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate           string     `json:"end_date" validate:"required,datetime=2006-01-02"`
+	RegistrationStart time.Time  `json:"registration_start" validate:"required"`
+	RegistrationEnd   time.Time  `json:"registration_end" validate:"required"`
+	DropDeadline      *time.Time `json:"drop_deadline,omitempty"`
+	WithdrawDeadline  *time.Time `json:"withdraw_deadline,omitempty"`
+}
+
+// UpdateSemesterRequest is a partial update: omitted fields are left unchanged. Setting
+// is_active to false closes registration for the semester.
+type UpdateSemesterRequest struct {
+	SemesterName      *string    `json:"semester_name,omitempty" validate:"omitempty,min=1,max=100"`
+	StartDate         *string    `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate           *string    `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	RegistrationStart *time.Time `json:"registration_start,omitempty"`
+	RegistrationEnd   *time.Time `json:"registration_end,omitempty"`
+	DropDeadline      *time.Time `json:"drop_deadline,omitempty"`
+	WithdrawDeadline  *time.Time `json:"withdraw_deadline,omitempty"`
+	IsActive          *bool      `json:"is_active,omitempty"`
+}
+
+// ProposeApprovalRequest asks for a destructive operation on the target section or
+// semester. It runs only once another admin approves it.
+type ProposeApprovalRequest struct {
+	Operation string    `json:"operation" validate:"required,oneof=section.deactivate section.drop_all semester.deactivate"`
+	TargetID  uuid.UUID `json:"target_id" validate:"required"`
+	Reason    string    `json:"reason" validate:"required,max=500"`
+}
+
+// ScheduleOverrideRequest asks an advisor to let the student take a section that clashes
+// with their schedule
+type ScheduleOverrideRequest struct {
+	SectionID uuid.UUID `json:"section_id" validate:"required"`
+	Reason    string    `json:"reason" validate:"required,max=1000"`
+}
+
+// ScheduleOverrideDecision is the advisor's answer to a schedule override
+type ScheduleOverrideDecision struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// IssuePermissionNumbersRequest issues Count permission numbers for a section. With a
+// StudentID the numbers only work for that student.
+type IssuePermissionNumbersRequest struct {
+	Count     int        `json:"count" validate:"min=1,max=200"`
+	StudentID *uuid.UUID `json:"student_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Note      string     `json:"note" validate:"max=1000"`
+}
+
+// PlaceHoldRequest places a hold on a student on behalf of staff
+type PlaceHoldRequest struct {
+	HoldType string `json:"hold_type" validate:"required,oneof=financial advising"`
+	Reason   string `json:"reason" validate:"max=1000"`
+}
+
+// Bursar hold callback event types
+const (
+	BursarHoldPlaced   = "hold.placed"
+	BursarHoldReleased = "hold.released"
+)
+
+// BursarHoldEvent is a callback from the bursar placing or releasing a financial hold.
+// HoldRef is the bursar's own reference, which a release repeats.
+type BursarHoldEvent struct {
+	EventType     string    `json:"event_type" validate:"required,oneof=hold.placed hold.released"`
+	HoldRef       string    `json:"hold_ref" validate:"required,max=255"`
+	StudentNumber string    `json:"student_number" validate:"required"`
+	Reason        string    `json:"reason" validate:"max=500"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+type CreateSectionRequest struct {
+	CourseID      uuid.UUID `json:"course_id" validate:"required"`
+	SemesterID    uuid.UUID `json:"semester_id" validate:"required"`
+	SectionNumber string    `json:"section_number" validate:"required,min=1,max=10"`
+	TotalSeats    int       `json:"total_seats" validate:"required,min=1"`
+	// MeetingDays, StartTime and EndTime are the weekly schedule, e.g. "MWF" 09:00-09:50.
+	// Leave them out for sections without a fixed schedule.
+	MeetingDays string `json:"meeting_days,omitempty" validate:"omitempty,meeting_days"`
+	StartTime   string `json:"start_time,omitempty" validate:"required_with=MeetingDays,omitempty,time_of_day"`
+	EndTime     string `json:"end_time,omitempty" validate:"required_with=MeetingDays,omitempty,time_of_day"`
+	Room        string `json:"room,omitempty" validate:"max=50"`
+	Instructor  string `json:"instructor,omitempty" validate:"max=100"`
+	// LinkedSectionID makes the section a component of another section of the semester,
+	// such as a lab of a lecture, which students then register for together
+	LinkedSectionID *uuid.UUID `json:"linked_section_id,omitempty"`
+}
+
+type UpdateSectionCapacityRequest struct {
+	TotalSeats int `json:"total_seats" validate:"required,min=1"`
+}
+
+// SetWaitlistFreezeRequest freezes or unfreezes a section's waitlist. PausePromotions only
+// applies to a frozen waitlist.
+type SetWaitlistFreezeRequest struct {
+	Frozen          *bool `json:"frozen" validate:"required"`
+	PausePromotions bool  `json:"pause_promotions"`
+}
+
+// SetWaitlistTTLRequest sets how many hours a section's waitlist entries last. TTLHours 0
+// stops them expiring; leaving it out goes back to the configured default. Entries already
+// on the waitlist keep the expiry they joined with.
+type SetWaitlistTTLRequest struct {
+	TTLHours *int `json:"ttl_hours" validate:"omitempty,min=0,max=8760"`
+}
+
+// SetSectionPolicyRequest replaces the registration policy of a section. MaxWaitlist 0
+// removes the waitlist cap; leaving it out goes back to the configured default. Students
+// already waiting beyond a lowered cap keep their place.
+type SetSectionPolicyRequest struct {
+	AllowWaitlist             *bool `json:"allow_waitlist" validate:"required"`
+	ReservedSeats             int   `json:"reserved_seats" validate:"min=0"`
+	InstructorConsentRequired bool  `json:"instructor_consent_required"`
+	MaxWaitlist               *int  `json:"max_waitlist" validate:"omitempty,min=0"`
+}
+
+// SetSeatPoolsRequest replaces the seat pools of a section; an empty list removes them all.
+// Pools are matched to the existing ones by name, so renaming a pool starts it afresh.
+type SetSeatPoolsRequest struct {
+	Pools []SeatPoolRequest `json:"pools" validate:"max=20,dive"`
+}
+
+// SeatPoolRequest describes one seat pool. Each eligibility list is the values a student may
+// have; an empty list admits everyone.
+type SeatPoolRequest struct {
+	Name    string   `json:"name" validate:"required,max=100"`
+	Seats   int      `json:"seats" validate:"min=1"`
+	Majors  []string `json:"majors" validate:"max=50,dive,required,max=100"`
+	Cohorts []string `json:"cohorts" validate:"max=50,dive,required,max=50"`
+	Years   []int    `json:"years" validate:"max=10,dive,min=1,max=10"`
+}
+
+// SetTagsRequest replaces the catalog tags and attributes of a course or section. Tags are
+// normalised to lowercase kebab-case, so "Writing Intensive" becomes "writing-intensive".
+type SetTagsRequest struct {
+	Tags       []string          `json:"tags" validate:"max=20,dive,required,max=40"`
+	Attributes map[string]string `json:"attributes" validate:"max=20,dive,keys,required,max=40,endkeys,max=100"`
+}
+
+// SectionTagFilter keeps the sections that carry every tag and attribute value, either
+// themselves or through their course
+type SectionTagFilter struct {
+	Tags       []string
+	Attributes map[string]string
+}
+
+// SectionCountsInclude selects the optional counts added to each listed section
+type SectionCountsInclude struct {
+	WaitlistCount bool
+	EnrolledCount bool
+}
+
+// SectionWithCounts is a listed section with the counts that were asked for; the others are
+// left out of the JSON
+type SectionWithCounts struct {
+	*domain.Section
+	WaitlistCount *int `json:"waitlist_count,omitempty"`
+	EnrolledCount *int `json:"enrolled_count,omitempty"`
+}
+
+// CourseSearchRequest is the query of a catalog search. Days, StartAfter and EndBefore
+// describe when the student is free; sections meeting outside them are left out.
+type CourseSearchRequest struct {
+	Code       string `form:"code" validate:"omitempty,max=20"`
+	Query      string `form:"q" validate:"omitempty,max=100"`
+	Department string `form:"department" validate:"omitempty,max=20"`
+	MinCredits *int   `form:"min_credits" validate:"omitempty,min=0"`
+	MaxCredits *int   `form:"max_credits" validate:"omitempty,min=0"`
+	SemesterID string `form:"semester_id" validate:"omitempty,uuid"`
+	Days       string `form:"days" validate:"omitempty,meeting_days"`
+	StartAfter string `form:"start_after" validate:"omitempty,time_of_day"`
+	EndBefore  string `form:"end_before" validate:"omitempty,time_of_day"`
+	HasSeats   bool   `form:"has_seats"`
+	// Cursor is the next_cursor of the previous page
+	Cursor string `form:"cursor" validate:"omitempty,max=200"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// CourseSearchResult is a matching course with the sections that matched
+type CourseSearchResult struct {
+	Course   *domain.Course    `json:"course"`
+	Sections []*domain.Section `json:"sections"`
+}
+
+// CourseSearchPage is one page of search results. NextCursor is empty on the last page.
+type CourseSearchPage struct {
+	Results    []CourseSearchResult `json:"results"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// CreateStudentRequest adds a student record, active unless EnrollmentStatus says otherwise
+type CreateStudentRequest struct {
+	StudentNumber    string `json:"student_number" validate:"required,min=2,max=20,alphanum"`
+	FirstName        string `json:"first_name" validate:"required,person_name,max=100"`
+	LastName         string `json:"last_name" validate:"required,person_name,max=100"`
+	PreferredName    string `json:"preferred_name,omitempty" validate:"omitempty,max=100"`
+	Email            string `json:"email,omitempty" validate:"omitempty,contact_email,max=255"`
+	Phone            string `json:"phone,omitempty" validate:"omitempty,phone"`
+	EnrollmentStatus string `json:"enrollment_status,omitempty" validate:"omitempty,oneof=active inactive"`
+	Major            string `json:"major,omitempty" validate:"omitempty,max=100"`
+	Cohort           string `json:"cohort,omitempty" validate:"omitempty,max=50"`
+	Year             int    `json:"year,omitempty" validate:"min=0,max=10"`
+}
+
+// UpdateStudentRequest is an administrator's partial update: omitted fields are left
+// unchanged. Archived students cannot be changed this way.
+type UpdateStudentRequest struct {
+	StudentNumber    *string `json:"student_number,omitempty" validate:"omitempty,min=2,max=20,alphanum"`
+	FirstName        *string `json:"first_name,omitempty" validate:"omitempty,person_name,max=100"`
+	LastName         *string `json:"last_name,omitempty" validate:"omitempty,person_name,max=100"`
+	PreferredName    *string `json:"preferred_name,omitempty" validate:"omitempty,max=100"`
+	Email            *string `json:"email,omitempty" validate:"omitempty,contact_email,max=255"`
+	Phone            *string `json:"phone,omitempty" validate:"omitempty,phone"`
+	EnrollmentStatus *string `json:"enrollment_status,omitempty" validate:"omitempty,oneof=active inactive"`
+	Major            *string `json:"major,omitempty" validate:"omitempty,max=100"`
+	Cohort           *string `json:"cohort,omitempty" validate:"omitempty,max=50"`
+	Year             *int    `json:"year,omitempty" validate:"omitempty,min=0,max=10"`
+}
+
+// UpdateStudentProfileRequest is a partial update: omitted fields are left unchanged and an
+// empty string clears an optional contact field.
+type UpdateStudentProfileRequest struct {
+	FirstName     *string `json:"first_name,omitempty" validate:"omitempty,person_name,max=100"`
+	LastName      *string `json:"last_name,omitempty" validate:"omitempty,person_name,max=100"`
+	PreferredName *string `json:"preferred_name,omitempty" validate:"omitempty,max=100"`
+	Email         *string `json:"email,omitempty" validate:"omitempty,contact_email,max=255"`
+	Phone         *string `json:"phone,omitempty" validate:"omitempty,phone"`
+	// DeadlineReminders opts the student in to or out of registration deadline reminders
+	DeadlineReminders *bool `json:"deadline_reminders,omitempty"`
+}
+
+// ArchiveStudentsRequest selects the students to archive: explicit IDs, a cohort given as a
+// student number prefix, or both.
+type ArchiveStudentsRequest struct {
+	StudentIDs []uuid.UUID `json:"student_ids,omitempty" validate:"omitempty,max=10000"`
+	Cohort     string      `json:"cohort,omitempty" validate:"omitempty,min=2,max=20,alphanum"`
+}
+
+type ArchiveStudentsResult struct {
+	Archived   int         `json:"archived"`
+	StudentIDs []uuid.UUID `json:"student_ids"`
+}
+
+// EnrollmentForecastReport lists a semester's forecasts with how many sections fall under
+// each outlook. GeneratedAt is nil when no forecast has been made for the semester yet.
+type EnrollmentForecastReport struct {
+	SemesterID  uuid.UUID                      `json:"semester_id"`
+	Department  string                         `json:"department,omitempty"`
+	GeneratedAt *time.Time                     `json:"generated_at,omitempty"`
+	Outlooks    map[domain.ForecastOutlook]int `json:"outlooks"`
+	Forecasts   []*domain.EnrollmentForecast   `json:"forecasts"`
+}
+
+// SectionStatsReport is how full every section of a semester is, as of GeneratedAt
+type SectionStatsReport struct {
+	SemesterID  uuid.UUID      `json:"semester_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Sections    []SectionStats `json:"sections"`
+}
+
+// SectionStats describes the enrollment of one section. FillRate is the percentage of its
+// seats held, withdrawn students included. A full section has FilledAt, when it last became
+// full, and TimeToFillHours, how long after registration opened that was.
+type SectionStats struct {
+	SectionID       uuid.UUID  `json:"section_id"`
+	CourseCode      string     `json:"course_code"`
+	SectionNumber   string     `json:"section_number"`
+	Capacity        int        `json:"capacity"`
+	Enrolled        int        `json:"enrolled"`
+	Withdrawn       int        `json:"withdrawn"`
+	Waitlisted      int        `json:"waitlisted"`
+	Dropped         int        `json:"dropped"`
+	FillRate        float64    `json:"fill_rate"`
+	FilledAt        *time.Time `json:"filled_at,omitempty"`
+	TimeToFillHours *float64   `json:"time_to_fill_hours,omitempty"`
+}
+
+// ForecastRunResult counts what one forecasting run covered
+type ForecastRunResult struct {
+	Semesters        int `json:"semesters"`
+	Sections         int `json:"sections"`
+	HistorySemesters int `json:"history_semesters"`
+}
+
+// SeatDiscrepancy is a section whose Redis seat counter disagrees with its capacity less its
+// enrolled registrations. CounterSeats is nil when the section has no counter cached.
+type SeatDiscrepancy struct {
+	SectionID     uuid.UUID `json:"section_id"`
+	CourseCode    string    `json:"course_code"`
+	SectionNumber string    `json:"section_number"`
+	TotalSeats    int       `json:"total_seats"`
+	Enrolled      int       `json:"enrolled"`
+	CounterSeats  *int      `json:"counter_seats"`
+	ExpectedSeats int       `json:"expected_seats"`
+	Healed        bool      `json:"healed"`
+}
+
+// SeatReconcileReport is the outcome of one seat counter reconciliation run
+type SeatReconcileReport struct {
+	Sections      int               `json:"sections"`
+	Discrepancies []SeatDiscrepancy `json:"discrepancies"`
+	Healed        int               `json:"healed"`
+}
+
+// SemesterArchiveReport is the outcome of archiving a semester. The rows moved are zero
+// when the semester was archived by an earlier run, which only purged the cache again.
+type SemesterArchiveReport struct {
+	SemesterID      uuid.UUID `json:"semester_id"`
+	SemesterCode    string    `json:"semester_code"`
+	AlreadyArchived bool      `json:"already_archived"`
+	Registrations   int64     `json:"registrations"`
+	WaitlistEntries int64     `json:"waitlist_entries"`
+	Sections        int       `json:"sections"`
+	Students        int       `json:"students"`
+	KeysFailed      int       `json:"keys_failed"`
+}
+
+type KPIMinuteCount struct {
+	Minute time.Time `json:"minute"`
+	Count  int64     `json:"count"`
+}
+
+// KPIRate describes one counter over the requested window. LastMinute is the most recent
+// complete minute; CurrentMinute is still filling up.
+type KPIRate struct {
+	CurrentMinute    int64            `json:"current_minute"`
+	LastMinute       int64            `json:"last_minute"`
+	AveragePerMinute float64          `json:"average_per_minute"`
+	Series           []KPIMinuteCount `json:"series"`
+}
+
+type SemesterKPITotals struct {
+	SemesterID    uuid.UUID `json:"semester_id"`
+	Registrations int64     `json:"registrations"`
+	Drops         int64     `json:"drops"`
+	WaitlistAdds  int64     `json:"waitlist_adds"`
+}
+
+type KPISnapshot struct {
+	GeneratedAt   time.Time                            `json:"generated_at"`
+	WindowMinutes int                                  `json:"window_minutes"`
+	Rates         map[infrastructure.KPIMetric]KPIRate `json:"rates"`
+	Semesters     []SemesterKPITotals                  `json:"semesters"`
+}
+
+// IssueAPIKeyRequest describes a new machine client. A zero rate limit uses the configured
+// default.
+type IssueAPIKeyRequest struct {
+	Name               string     `json:"name" validate:"required,max=100"`
+	Scopes             []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute,omitempty" validate:"omitempty,min=1,max=100000"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+}
+
+// RotateAPIKeyRequest sets how long the old key keeps working so clients can switch over
+// without downtime. Zero revokes it immediately.
+type RotateAPIKeyRequest struct {
+	GracePeriodMinutes int `json:"grace_period_minutes,omitempty" validate:"omitempty,min=0,max=10080"`
+}
+
+// IssuedAPIKey carries the plaintext key. It is shown once and cannot be recovered later.
+type IssuedAPIKey struct {
+	Key    string         `json:"key"`
+	APIKey *domain.APIKey `json:"api_key"`
+}
+
 type RegistrationService interface {
 	Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error)
-	DropCourse(ctx context.Context, studentID, sectionID uuid.UUID) error
+	DropCourse(ctx context.Context, studentID, sectionID uuid.UUID) (domain.RegistrationStatus, error)
 	GetStudentRegistrations(ctx context.Context, studentID uuid.UUID) ([]*domain.Registration, error)
 	GetStudentWaitlistStatus(ctx context.Context, studentID uuid.UUID) ([]*domain.WaitlistEntry, error)
 	GetAvailableSections(ctx context.Context, semesterID uuid.UUID) ([]*domain.Section, error)
 	ProcessDatabaseSyncJob(ctx context.Context, job infrastructure.DatabaseSyncJob) error
 	ProcessWaitlistJob(ctx context.Context, job infrastructure.WaitlistJob) error
-	ProcessWaitlist(ctx context.Context, sectionID uuid.UUID) error
+	ProcessWaitlist(ctx context.Context, job infrastructure.WaitlistPromotionJob) error
+	ExpireSeatOffers(ctx context.Context) error
+	ExpireSeatHolds(ctx context.Context) error
+}
+
+// ReminderService plans and sends the deadline reminders run by the queue's reminder workers
+type ReminderService interface {
+	PlanDeadlineReminders(ctx context.Context) error
+	SendDeadlineReminders(ctx context.Context, job infrastructure.ReminderJob) error
 }
@@ -0,0 +1,153 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// analyticsEmitTimeout bounds the delivery of one event to the sink
+const analyticsEmitTimeout = 5 * time.Second
+
+// AnalyticsProducer hands registration analytics events to a sink from a background
+// goroutine. Emit never blocks: an event that finds the buffer full is dropped and logged,
+// since dashboards can live with a gap but a registration must not wait on them. A nil
+// producer emits nothing.
+type AnalyticsProducer struct {
+	sink   interfaces.AnalyticsSink
+	events chan interfaces.AnalyticsEvent
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewAnalyticsProducer(sink interfaces.AnalyticsSink, bufferSize int) *AnalyticsProducer {
+	return &AnalyticsProducer{
+		sink:   sink,
+		events: make(chan interfaces.AnalyticsEvent, max(bufferSize, 1)),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func (p *AnalyticsProducer) Start() {
+	go p.run()
+}
+
+// Emit queues an event for the sink, filling in its ID and time if they are unset
+func (p *AnalyticsProducer) Emit(event interfaces.AnalyticsEvent) {
+	if p == nil {
+		return
+	}
+	if event.EventID == uuid.Nil {
+		event.EventID = uuid.New()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	select {
+	case p.events <- event:
+	default:
+		logger.Warn("Analytics buffer full, dropping %s event of student %s in section %s", event.Type, event.StudentID, event.SectionID)
+	}
+}
+
+// Stop delivers the events still buffered and closes the sink
+func (p *AnalyticsProducer) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+		if err := p.sink.Close(); err != nil {
+			logger.Warn("Failed to close analytics sink: %v", err)
+		}
+	})
+}
+
+func (p *AnalyticsProducer) run() {
+	defer close(p.done)
+
+	for {
+		select {
+		case event := <-p.events:
+			p.deliver(event)
+		case <-p.stop:
+			for {
+				select {
+				case event := <-p.events:
+					p.deliver(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *AnalyticsProducer) deliver(event interfaces.AnalyticsEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), analyticsEmitTimeout)
+	defer cancel()
+	if err := p.sink.Emit(ctx, event); err != nil {
+		logger.Warn("Failed to emit %s analytics event of student %s in section %s: %v", event.Type, event.StudentID, event.SectionID, err)
+	}
+}
+
+// EnableAnalytics emits registration analytics events to sink. Call it before the server
+// starts taking registrations.
+func (s *RegistrationService) EnableAnalytics(sink interfaces.AnalyticsSink, bufferSize int) {
+	s.analytics = NewAnalyticsProducer(sink, bufferSize)
+	s.analytics.Start()
+}
+
+// StopAnalytics delivers the analytics events still buffered. Call it after the queue
+// workers have stopped, since promotions they make emit events too.
+func (s *RegistrationService) StopAnalytics() {
+	if s.analytics != nil {
+		s.analytics.Stop()
+	}
+}
+
+// emitRegistrationAttempt records that the student asked for a seat in the section, and
+// what came of it
+func (s *RegistrationService) emitRegistrationAttempt(studentID uuid.UUID, result RegistrationResult) {
+	s.analytics.Emit(interfaces.AnalyticsEvent{
+		Type:      interfaces.AnalyticsRegistrationAttempted,
+		StudentID: studentID,
+		SectionID: result.SectionID,
+		Result:    result.Status,
+		Position:  result.Position,
+	})
+}
+
+// emitTransition records a registration status change. Leaving a waitlist is not a fact
+// the dashboards track, so it emits nothing.
+func (s *RegistrationService) emitTransition(eventType domain.RegistrationEventType, studentID, sectionID uuid.UUID, position *int, occurredAt time.Time) {
+	var analyticsType interfaces.AnalyticsEventType
+	switch eventType {
+	case domain.EventRegistered:
+		analyticsType = interfaces.AnalyticsEnrolled
+	case domain.EventWaitlisted:
+		analyticsType = interfaces.AnalyticsWaitlisted
+	case domain.EventPromoted:
+		analyticsType = interfaces.AnalyticsPromoted
+	case domain.EventDropped:
+		analyticsType = interfaces.AnalyticsDropped
+	case domain.EventWithdrawn:
+		analyticsType = interfaces.AnalyticsWithdrawn
+	default:
+		return
+	}
+	s.analytics.Emit(interfaces.AnalyticsEvent{
+		Type:       analyticsType,
+		StudentID:  studentID,
+		SectionID:  sectionID,
+		Position:   position,
+		OccurredAt: occurredAt,
+	})
+}
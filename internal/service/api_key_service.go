@@ -0,0 +1,234 @@
+package service
+
+import (
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefix      = "crs"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+	// apiKeyTouchInterval bounds how often last_used_at is written for a busy key
+	apiKeyTouchInterval = time.Minute
+	apiKeyRateWindow    = time.Minute
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid, expired or revoked API key")
+	ErrUnknownScope   = errors.New("unknown API key scope")
+	ErrAPIKeyRevoked  = errors.New("API key is already revoked")
+)
+
+type IssueAPIKeyRequest = serviceInterfaces.IssueAPIKeyRequest
+type RotateAPIKeyRequest = serviceInterfaces.RotateAPIKeyRequest
+type IssuedAPIKey = serviceInterfaces.IssuedAPIKey
+
+// APIKeyService issues API keys for machine clients and authenticates the requests they
+// make. Keys look like crs_<prefix>_<secret>; only the prefix and a hash of the whole key
+// are stored.
+type APIKeyService struct {
+	apiKeyRepo       interfaces.APIKeyRepository
+	rateLimiter      interfaces.RateLimiter
+	defaultRateLimit int
+}
+
+func NewAPIKeyService(apiKeyRepo interfaces.APIKeyRepository, rateLimiter interfaces.RateLimiter, defaultRateLimit int) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:       apiKeyRepo,
+		rateLimiter:      rateLimiter,
+		defaultRateLimit: defaultRateLimit,
+	}
+}
+
+func (s *APIKeyService) Issue(ctx context.Context, req *IssueAPIKeyRequest) (*IssuedAPIKey, error) {
+	for _, scope := range req.Scopes {
+		if !auth.ValidScope(auth.Scope(scope)) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownScope, scope)
+		}
+	}
+
+	key := &domain.APIKey{
+		Name:               strings.TrimSpace(req.Name),
+		Scopes:             req.Scopes,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		ExpiresAt:          req.ExpiresAt,
+	}
+	plaintext, err := newAPIKeySecret(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	logger.Info("Issued API key %s (%s) with scopes %v", key.KeyID, key.Name, key.Scopes)
+	return &IssuedAPIKey{Key: plaintext, APIKey: key}, nil
+}
+
+// Rotate issues a replacement with the same name, scopes and limits. The old key keeps
+// working for the grace period so the client can switch over.
+func (s *APIKeyService) Rotate(ctx context.Context, keyID uuid.UUID, req *RotateAPIKeyRequest) (*IssuedAPIKey, error) {
+	old, err := s.GetAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if old.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	replacement := &domain.APIKey{
+		Name:               old.Name,
+		Scopes:             old.Scopes,
+		RateLimitPerMinute: old.RateLimitPerMinute,
+		ExpiresAt:          old.ExpiresAt,
+		RotatedFromID:      &old.KeyID,
+	}
+	plaintext, err := newAPIKeySecret(replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	oldExpiresAt := time.Now().Add(time.Duration(req.GracePeriodMinutes) * time.Minute)
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = *old.ExpiresAt
+	}
+	if err := s.apiKeyRepo.Rotate(ctx, replacement, old.KeyID, oldExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to rotate API key: %w", err)
+	}
+
+	logger.Info("Rotated API key %s (%s) to %s; old key expires at %s", old.KeyID, old.Name, replacement.KeyID, oldExpiresAt.Format(time.RFC3339))
+	return &IssuedAPIKey{Key: plaintext, APIKey: replacement}, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, keyID uuid.UUID) error {
+	if _, err := s.GetAPIKey(ctx, keyID); err != nil {
+		return err
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(ctx, keyID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if !revoked {
+		return ErrAPIKeyRevoked
+	}
+
+	logger.Info("Revoked API key %s", keyID)
+	return nil
+}
+
+func (s *APIKeyService) GetAPIKey(ctx context.Context, keyID uuid.UUID) (*domain.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, includeRevoked bool) ([]*domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.List(ctx, includeRevoked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Authenticate returns the active key matching plaintext. Every failure is reported as
+// ErrInvalidAPIKey so callers cannot probe which prefixes exist.
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (*domain.APIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(plaintext)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(plaintext)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.KeyID, now); err != nil {
+			logger.Warn("Failed to record use of API key %s: %v", key.KeyID, err)
+		}
+	}
+
+	return key, nil
+}
+
+// Allow counts a request against the key's per-minute budget. If the limiter is unavailable
+// the request is let through; rate limits protect capacity and must not take integrations down.
+func (s *APIKeyService) Allow(ctx context.Context, key *domain.APIKey) *interfaces.RateLimitResult {
+	limit := key.RateLimitPerMinute
+	if limit <= 0 {
+		limit = s.defaultRateLimit
+	}
+
+	result, err := s.rateLimiter.Allow(ctx, "apikey:"+key.KeyID.String(), limit, apiKeyRateWindow)
+	if err != nil {
+		logger.Warn("Rate limiter unavailable for API key %s: %v", key.KeyID, err)
+		return &interfaces.RateLimitResult{Allowed: true, Limit: limit, Remaining: limit}
+	}
+	return result
+}
+
+// newAPIKeySecret generates a key for key, fills in its prefix and hash and returns the
+// plaintext
+func newAPIKeySecret(key *domain.APIKey) (string, error) {
+	prefix := make([]byte, apiKeyPrefixBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key.Prefix = hex.EncodeToString(prefix)
+	plaintext := fmt.Sprintf("%s_%s_%s", apiKeyPrefix, key.Prefix, hex.EncodeToString(secret))
+	key.KeyHash = hashAPIKey(plaintext)
+	return plaintext, nil
+}
+
+func parseAPIKeyPrefix(plaintext string) (string, bool) {
+	parts := strings.Split(plaintext, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || len(parts[1]) != 2*apiKeyPrefixBytes || len(parts[2]) != 2*apiKeySecretBytes {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
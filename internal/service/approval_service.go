@@ -0,0 +1,255 @@
+package service
+
+import (
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrApprovalNotFound   = errors.New("approval not found")
+	ErrApprovalNotPending = errors.New("approval has already been decided")
+	ErrApprovalExpired    = errors.New("approval window has passed")
+	ErrApprovalPending    = errors.New("the operation is already awaiting approval")
+	ErrSelfApproval       = errors.New("an operation must be approved by someone other than its proposer")
+	ErrUnknownOperation   = errors.New("unknown operation")
+	ErrAnonymousApprover  = errors.New("approvals need an identified admin")
+)
+
+type ProposeApprovalRequest = serviceInterfaces.ProposeApprovalRequest
+
+// ApprovalService runs destructive admin operations under two-person control: one admin
+// proposes the operation and a different admin approves it within the window. Proposer,
+// approver and the outcome are kept on the approval.
+type ApprovalService struct {
+	approvalRepo        interfaces.ApprovalRepository
+	sectionService      *SectionService
+	semesterService     *SemesterService
+	registrationService *RegistrationService
+	window              time.Duration
+}
+
+func NewApprovalService(
+	approvalRepo interfaces.ApprovalRepository,
+	sectionService *SectionService,
+	semesterService *SemesterService,
+	registrationService *RegistrationService,
+	window time.Duration,
+) *ApprovalService {
+	return &ApprovalService{
+		approvalRepo:        approvalRepo,
+		sectionService:      sectionService,
+		semesterService:     semesterService,
+		registrationService: registrationService,
+		window:              window,
+	}
+}
+
+func (s *ApprovalService) Propose(ctx context.Context, req *ProposeApprovalRequest) (*domain.AdminApproval, error) {
+	actor, err := approvalActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTarget(ctx, req.Operation, req.TargetID); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.approvalRepo.GetPending(ctx, req.Operation, req.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending approvals: %w", err)
+	}
+	if pending != nil {
+		if time.Now().Before(pending.ExpiresAt) {
+			return nil, fmt.Errorf("%w: %s", ErrApprovalPending, pending.ApprovalID)
+		}
+		s.expire(ctx, pending)
+	}
+
+	now := time.Now()
+	approval := &domain.AdminApproval{
+		ApprovalID: uuid.New(),
+		Operation:  req.Operation,
+		TargetID:   req.TargetID,
+		Reason:     strings.TrimSpace(req.Reason),
+		Status:     domain.ApprovalPending,
+		ProposedBy: actor,
+		ProposedAt: now,
+		ExpiresAt:  now.Add(s.window),
+	}
+	if err := s.approvalRepo.Create(ctx, approval); err != nil {
+		return nil, fmt.Errorf("failed to create approval: %w", err)
+	}
+
+	logger.Info("%s proposed %s on %s (approval %s): %s", actor, approval.Operation, approval.TargetID, approval.ApprovalID, approval.Reason)
+	return approval, nil
+}
+
+// Approve confirms a pending proposal and runs the operation. The approval is claimed before
+// the operation runs, so it runs at most once however many admins approve at the same time.
+func (s *ApprovalService) Approve(ctx context.Context, approvalID uuid.UUID) (*domain.AdminApproval, error) {
+	actor, err := approvalActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := s.getPending(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.ProposedBy == actor {
+		return nil, ErrSelfApproval
+	}
+
+	now := time.Now()
+	claimed, err := s.approvalRepo.Decide(ctx, approvalID, domain.ApprovalApproved, &actor, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrApprovalNotPending
+	}
+	approval.Status = domain.ApprovalApproved
+	approval.DecidedBy = &actor
+	approval.DecidedAt = &now
+	logger.Info("%s approved %s on %s (approval %s) proposed by %s", actor, approval.Operation, approval.TargetID, approvalID, approval.ProposedBy)
+
+	result, err := s.execute(ctx, approval)
+	approval.Status = domain.ApprovalExecuted
+	approval.Result = result
+	if err != nil {
+		approval.Status = domain.ApprovalFailed
+		approval.Result = err.Error()
+		logger.Error("Approved %s on %s failed (approval %s): %v", approval.Operation, approval.TargetID, approvalID, err)
+	}
+	if err := s.approvalRepo.Complete(ctx, approvalID, approval.Status, approval.Result); err != nil {
+		logger.Error("Failed to record outcome of approval %s: %v", approvalID, err)
+	}
+
+	return approval, nil
+}
+
+// Reject turns a proposal down. The proposer may reject their own proposal to withdraw it.
+func (s *ApprovalService) Reject(ctx context.Context, approvalID uuid.UUID) (*domain.AdminApproval, error) {
+	actor, err := approvalActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := s.getPending(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claimed, err := s.approvalRepo.Decide(ctx, approvalID, domain.ApprovalRejected, &actor, now)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrApprovalNotPending
+	}
+	approval.Status = domain.ApprovalRejected
+	approval.DecidedBy = &actor
+	approval.DecidedAt = &now
+
+	logger.Info("%s rejected %s on %s (approval %s) proposed by %s", actor, approval.Operation, approval.TargetID, approvalID, approval.ProposedBy)
+	return approval, nil
+}
+
+func (s *ApprovalService) GetApproval(ctx context.Context, approvalID uuid.UUID) (*domain.AdminApproval, error) {
+	approval, err := s.approvalRepo.GetByID(ctx, approvalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+	if approval == nil {
+		return nil, ErrApprovalNotFound
+	}
+	return approval, nil
+}
+
+func (s *ApprovalService) ListApprovals(ctx context.Context, status domain.ApprovalStatus) ([]*domain.AdminApproval, error) {
+	approvals, err := s.approvalRepo.List(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	return approvals, nil
+}
+
+// getPending returns the approval if it can still be decided. A proposal found past its
+// window is marked expired on the way.
+func (s *ApprovalService) getPending(ctx context.Context, approvalID uuid.UUID) (*domain.AdminApproval, error) {
+	approval, err := s.GetApproval(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status != domain.ApprovalPending {
+		return nil, fmt.Errorf("%w: it is %s", ErrApprovalNotPending, approval.Status)
+	}
+	if !time.Now().Before(approval.ExpiresAt) {
+		s.expire(ctx, approval)
+		return nil, ErrApprovalExpired
+	}
+	return approval, nil
+}
+
+func (s *ApprovalService) expire(ctx context.Context, approval *domain.AdminApproval) {
+	if _, err := s.approvalRepo.Decide(ctx, approval.ApprovalID, domain.ApprovalExpired, nil, time.Now()); err != nil {
+		logger.Warn("Failed to expire approval %s: %v", approval.ApprovalID, err)
+	}
+}
+
+// checkTarget makes sure the operation is known and its target exists before anyone is
+// asked to approve it
+func (s *ApprovalService) checkTarget(ctx context.Context, operation string, targetID uuid.UUID) error {
+	switch operation {
+	case domain.OperationDeactivateSection, domain.OperationDropAllFromSection:
+		_, err := s.sectionService.GetSection(ctx, targetID)
+		return err
+	case domain.OperationDeactivateSemester:
+		_, err := s.semesterService.GetSemester(ctx, targetID)
+		return err
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownOperation, operation)
+	}
+}
+
+func (s *ApprovalService) execute(ctx context.Context, approval *domain.AdminApproval) (string, error) {
+	switch approval.Operation {
+	case domain.OperationDeactivateSection:
+		if _, err := s.sectionService.DeactivateSection(ctx, approval.TargetID); err != nil {
+			return "", err
+		}
+		return "section deactivated", nil
+	case domain.OperationDropAllFromSection:
+		dropped, err := s.registrationService.DropAllFromSection(ctx, approval.TargetID)
+		if err != nil {
+			return "", fmt.Errorf("dropped %d students before failing: %w", dropped, err)
+		}
+		return fmt.Sprintf("dropped %d students", dropped), nil
+	case domain.OperationDeactivateSemester:
+		inactive := false
+		if _, err := s.semesterService.UpdateSemester(ctx, approval.TargetID, &UpdateSemesterRequest{IsActive: &inactive}); err != nil {
+			return "", err
+		}
+		return "semester deactivated", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownOperation, approval.Operation)
+	}
+}
+
+// approvalActor identifies the admin acting on an approval. With authentication disabled
+// every caller is the same anonymous admin, so a proposal can never find a second approver.
+func approvalActor(ctx context.Context) (string, error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok || claims.Subject == "" {
+		return "", ErrAnonymousApprover
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	DefaultAuditListLimit = 100
+	MaxAuditListLimit     = 1000
+	// auditSystemActor is the actor of changes made by background jobs rather than a caller
+	auditSystemActor = "system"
+)
+
+var ErrInvalidAuditRange = errors.New("from must be before to")
+
+type AuditEventFilter = interfaces.AuditEventFilter
+
+// AuditChange describes one change to record. Nil IDs are left out of the event.
+type AuditChange struct {
+	Action    domain.AuditAction
+	StudentID uuid.UUID
+	SectionID uuid.UUID
+	Before    map[string]any
+	After     map[string]any
+}
+
+// AuditService keeps the audit log of registration and section changes. Events go through
+// the queue, so recording one never slows down or fails the change it describes; an event
+// the queue refuses is written directly instead.
+type AuditService struct {
+	auditRepo    interfaces.AuditRepository
+	queueService interfaces.QueueService
+}
+
+func NewAuditService(auditRepo interfaces.AuditRepository, queueService interfaces.QueueService) *AuditService {
+	return &AuditService{
+		auditRepo:    auditRepo,
+		queueService: queueService,
+	}
+}
+
+// Record logs a change made by the caller in ctx. The request ID of ctx, or failing that its
+// trace ID, becomes the event's correlation ID. A nil service records nothing.
+func (s *AuditService) Record(ctx context.Context, change AuditChange) {
+	if s == nil {
+		return
+	}
+
+	event := domain.AuditEvent{
+		EventID:    uuid.New(),
+		Action:     change.Action,
+		ActorID:    auditSystemActor,
+		Before:     change.Before,
+		After:      change.After,
+		OccurredAt: time.Now(),
+	}
+	if claims, ok := auth.FromContext(ctx); ok {
+		event.ActorID = claims.Subject
+		event.ActorRole = string(claims.Role)
+	}
+	if change.StudentID != uuid.Nil {
+		event.StudentID = &change.StudentID
+	}
+	if change.SectionID != uuid.Nil {
+		event.SectionID = &change.SectionID
+	}
+	if requestID := logger.RequestID(ctx); requestID != "" {
+		event.CorrelationID = requestID
+	} else if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		event.CorrelationID = spanContext.TraceID().String()
+	}
+
+	// The change has happened, so it is logged even if the caller has gone away
+	ctx = context.WithoutCancel(ctx)
+	err := s.queueService.EnqueueAuditEvent(ctx, event)
+	if err == nil {
+		return
+	}
+	logger.WarnContext(ctx, "Failed to enqueue audit event %s, writing it directly: %v", event.Action, err)
+	if err := s.auditRepo.Create(ctx, &event); err != nil {
+		logger.ErrorContext(ctx, "Failed to write audit event %s for student %s, section %s: %v", event.Action, change.StudentID, change.SectionID, err)
+	}
+}
+
+// ListEvents returns the audit events matching filter, newest first
+func (s *AuditService) ListEvents(ctx context.Context, filter AuditEventFilter) ([]*domain.AuditEvent, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidAuditRange
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditListLimit
+	}
+	if filter.Limit > MaxAuditListLimit {
+		filter.Limit = MaxAuditListLimit
+	}
+
+	events, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	return events, nil
+}
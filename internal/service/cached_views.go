@@ -0,0 +1,21 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// readCachedView reads a cached view into T. ok is false on a miss and when the cached
+// value does not decode; the latter is logged, since the caller falls back to the
+// database and rewrites the entry either way.
+func readCachedView[T any](ctx context.Context, get interfaces.CacheGetter, view string, id uuid.UUID) (T, bool) {
+	value, err := interfaces.CacheGet[T](ctx, get, id)
+	if errors.Is(err, interfaces.ErrCacheUndecodable) {
+		logger.Warn("Failed to decode cached %s for %s: %v", view, id, err)
+	}
+	return value, err == nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidTag = errors.New("tags and attribute keys may only contain letters, digits and dashes")
+
+type SetTagsRequest = serviceInterfaces.SetTagsRequest
+type SectionTagFilter = serviceInterfaces.SectionTagFilter
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// SetCourseTags replaces the tags and attributes of a course. Every section of the course
+// inherits them, so all cached section lists are dropped.
+func (s *SectionService) SetCourseTags(ctx context.Context, courseID uuid.UUID, req *SetTagsRequest) (*domain.Course, error) {
+	tags, attributes, err := normaliseTags(req)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+
+	if err := s.courseRepo.UpdateTags(ctx, courseID, tags, attributes); err != nil {
+		return nil, err
+	}
+	course.Tags = tags
+	course.Attributes = attributes
+
+	if err := s.cacheService.Delete(ctx, interfaces.CourseDetailsCache.Key(courseID)); err != nil {
+		logger.Warn("Failed to invalidate course details for %s: %v", courseID, err)
+	}
+	if err := s.cacheService.Clear(ctx, interfaces.AvailableSectionsCache.Pattern()); err != nil {
+		logger.Warn("Failed to invalidate available sections: %v", err)
+	}
+
+	logger.Info("Set tags of course %s (%s) to %v", courseID, course.CourseCode, tags)
+	return course, nil
+}
+
+// SetSectionTags replaces the section's own tags and attributes
+func (s *SectionService) SetSectionTags(ctx context.Context, sectionID uuid.UUID, req *SetTagsRequest) (*domain.Section, error) {
+	tags, attributes, err := normaliseTags(req)
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := s.GetSection(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sectionRepo.UpdateTags(ctx, sectionID, tags, attributes); err != nil {
+		return nil, err
+	}
+	section.Tags = tags
+	section.Attributes = attributes
+
+	if err := s.cacheService.Delete(ctx, interfaces.SectionDetailsCache.Key(sectionID)); err != nil {
+		logger.Warn("Failed to invalidate section details for %s: %v", sectionID, err)
+	}
+	if err := s.cacheService.Delete(ctx, interfaces.AvailableSectionsCache.Key(section.SemesterID)); err != nil {
+		logger.Warn("Failed to invalidate available sections for semester %s: %v", section.SemesterID, err)
+	}
+
+	logger.Info("Set tags of section %s to %v", sectionID, tags)
+	return section, nil
+}
+
+// SearchAvailableSections is GetAvailableSections narrowed to the sections matching filter
+func (s *RegistrationService) SearchAvailableSections(ctx context.Context, semesterID uuid.UUID, filter SectionTagFilter) ([]*domain.Section, error) {
+	sections, err := s.GetAvailableSections(ctx, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(filter.Tags) == 0 && len(filter.Attributes) == 0 {
+		return sections, nil
+	}
+
+	tags := make([]string, len(filter.Tags))
+	for i, tag := range filter.Tags {
+		tags[i] = normaliseTag(tag)
+	}
+
+	matching := make([]*domain.Section, 0, len(sections))
+	for _, section := range sections {
+		if sectionMatches(section, tags, filter.Attributes) {
+			matching = append(matching, section)
+		}
+	}
+	return matching, nil
+}
+
+func sectionMatches(section *domain.Section, tags []string, attributes map[string]string) bool {
+	for _, tag := range tags {
+		if !section.HasTag(tag) {
+			return false
+		}
+	}
+	for key, want := range attributes {
+		value, ok := section.Attribute(normaliseTag(key))
+		if !ok || !strings.EqualFold(value, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// normaliseTags cleans up a SetTagsRequest, dropping duplicate tags and sorting them so the
+// stored order does not depend on the client
+func normaliseTags(req *SetTagsRequest) ([]string, map[string]string, error) {
+	seen := make(map[string]bool, len(req.Tags))
+	tags := make([]string, 0, len(req.Tags))
+	for _, raw := range req.Tags {
+		tag := normaliseTag(raw)
+		if !tagPattern.MatchString(tag) {
+			return nil, nil, fmt.Errorf("%w: %q", ErrInvalidTag, raw)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	attributes := make(map[string]string, len(req.Attributes))
+	for rawKey, value := range req.Attributes {
+		key := normaliseTag(rawKey)
+		if !tagPattern.MatchString(key) {
+			return nil, nil, fmt.Errorf("%w: %q", ErrInvalidTag, rawKey)
+		}
+		attributes[key] = strings.TrimSpace(value)
+	}
+
+	return tags, attributes, nil
+}
+
+func normaliseTag(raw string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(raw, "_", " "))), "-")
+}
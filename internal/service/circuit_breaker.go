@@ -0,0 +1,91 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// CircuitBreaker stops calls to a dependency that keeps failing. It opens after threshold
+// failures in a row, refuses calls for openFor, then lets a single probe through: the
+// probe's success closes it and its failure opens it again.
+type CircuitBreaker struct {
+	threshold int
+	openFor   time.Duration
+	// onChange is called with the new state whenever the breaker opens or closes
+	onChange func(state string)
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+}
+
+func NewCircuitBreaker(threshold int, openFor time.Duration, onChange func(state string)) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: max(threshold, 1),
+		openFor:   openFor,
+		onChange:  onChange,
+		state:     CircuitClosed,
+	}
+}
+
+// Allow reports whether a call may go through. Once openFor has passed it lets the first
+// caller through as the probe and reports probe as true; the others are refused until the
+// probe reports back.
+func (b *CircuitBreaker) Allow() (allowed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		return true, false
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.openFor {
+			return false, false
+		}
+		b.state = CircuitHalfOpen
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	changed := b.state != CircuitClosed
+	b.state = CircuitClosed
+	b.failures = 0
+	b.mu.Unlock()
+
+	if changed && b.onChange != nil {
+		b.onChange(CircuitClosed)
+	}
+}
+
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	b.failures++
+	opened := b.state == CircuitHalfOpen || (b.state == CircuitClosed && b.failures >= b.threshold)
+	if opened {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+	b.mu.Unlock()
+
+	if opened && b.onChange != nil {
+		b.onChange(CircuitOpen)
+	}
+}
+
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CourseSearchTTL is short because results carry seat counts; the counts are refreshed
+	// from the seat counters on every read, but sections filling up or opening are not
+	CourseSearchTTL          = 30 * time.Second
+	defaultCourseSearchLimit = 20
+	courseSearchPrefix       = "courses:search:"
+)
+
+var (
+	ErrInvalidCursor  = errors.New("invalid search cursor")
+	ErrCourseExists   = errors.New("course code already exists")
+	ErrCourseInactive = errors.New("course is no longer offered")
+)
+
+type CreateCourseRequest = serviceInterfaces.CreateCourseRequest
+type UpdateCourseRequest = serviceInterfaces.UpdateCourseRequest
+type CourseSearchRequest = serviceInterfaces.CourseSearchRequest
+type CourseSearchResult = serviceInterfaces.CourseSearchResult
+type CourseSearchPage = serviceInterfaces.CourseSearchPage
+
+// CourseService manages and searches the course catalog. Sections embed their course in
+// cached details and lists, so every write drops those caches as well.
+type CourseService struct {
+	courseRepo   interfaces.CourseRepository
+	sectionRepo  interfaces.SectionRepository
+	cacheService interfaces.CacheService
+}
+
+func NewCourseService(
+	courseRepo interfaces.CourseRepository,
+	sectionRepo interfaces.SectionRepository,
+	cacheService interfaces.CacheService,
+) *CourseService {
+	return &CourseService{
+		courseRepo:   courseRepo,
+		sectionRepo:  sectionRepo,
+		cacheService: cacheService,
+	}
+}
+
+func (s *CourseService) CreateCourse(ctx context.Context, req *CreateCourseRequest) (*domain.Course, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.CourseCode))
+	existing, err := s.courseRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check course code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrCourseExists
+	}
+
+	course := &domain.Course{
+		CourseID:    uuid.New(),
+		CourseCode:  code,
+		CourseName:  strings.TrimSpace(req.CourseName),
+		Department:  strings.ToUpper(strings.TrimSpace(req.Department)),
+		CreditHours: req.CreditHours,
+		AllowRepeat: req.AllowRepeat,
+		Active:      true,
+		Version:     1,
+	}
+	if err := s.courseRepo.Create(ctx, course); err != nil {
+		return nil, fmt.Errorf("failed to create course: %w", err)
+	}
+
+	s.invalidateCourseCaches(ctx, course.CourseID)
+
+	logger.Info("Created course %s (%s)", course.CourseID, course.CourseCode)
+	return course, nil
+}
+
+func (s *CourseService) GetCourse(ctx context.Context, courseID uuid.UUID) (*domain.Course, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+func (s *CourseService) ListCourses(ctx context.Context, includeInactive bool) ([]*domain.Course, error) {
+	courses, err := s.courseRepo.List(ctx, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+func (s *CourseService) UpdateCourse(ctx context.Context, courseID uuid.UUID, req *UpdateCourseRequest) (*domain.Course, error) {
+	course, err := s.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CourseName != nil {
+		course.CourseName = strings.TrimSpace(*req.CourseName)
+	}
+	if req.Department != nil {
+		course.Department = strings.ToUpper(strings.TrimSpace(*req.Department))
+	}
+	if req.CreditHours != nil {
+		course.CreditHours = *req.CreditHours
+	}
+	if req.AllowRepeat != nil {
+		course.AllowRepeat = *req.AllowRepeat
+	}
+
+	if err := s.courseRepo.Update(ctx, course); err != nil {
+		return nil, err
+	}
+	course.Version++
+
+	s.invalidateCourseCaches(ctx, courseID)
+
+	logger.Info("Updated course %s (%s)", courseID, course.CourseCode)
+	return course, nil
+}
+
+// DeactivateCourse takes a course out of the catalog. Its sections stay as they are, but
+// no longer accept registrations or appear in searches.
+func (s *CourseService) DeactivateCourse(ctx context.Context, courseID uuid.UUID) (*domain.Course, error) {
+	course, err := s.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.Active {
+		return course, nil
+	}
+
+	if err := s.courseRepo.SetActive(ctx, courseID, false); err != nil {
+		return nil, err
+	}
+	course.Active = false
+
+	s.invalidateCourseCaches(ctx, courseID)
+
+	logger.Info("Deactivated course %s (%s)", courseID, course.CourseCode)
+	return course, nil
+}
+
+// invalidateCourseCaches drops the course details and everything that embeds the course:
+// section details, available section lists and search results
+func (s *CourseService) invalidateCourseCaches(ctx context.Context, courseID uuid.UUID) {
+	if err := s.cacheService.Delete(ctx, interfaces.CourseDetailsCache.Key(courseID)); err != nil {
+		logger.Warn("Failed to invalidate course details for %s: %v", courseID, err)
+	}
+	for _, pattern := range []string{interfaces.SectionDetailsCache.Pattern(), interfaces.AvailableSectionsCache.Pattern(), courseSearchPrefix + "*"} {
+		if err := s.cacheService.Clear(ctx, pattern); err != nil {
+			logger.Warn("Failed to invalidate %s: %v", pattern, err)
+		}
+	}
+}
+
+// Search finds the courses with an active section matching req, ordered by course code.
+// Pages are keyed on the last course code rather than an offset, so they stay stable while
+// the catalog changes.
+func (s *CourseService) Search(ctx context.Context, req *CourseSearchRequest) (*CourseSearchPage, error) {
+	filter, err := courseSearchFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	afterCode, err := decodeSearchCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultCourseSearchLimit
+	}
+
+	key := courseSearchCacheKey(filter, afterCode, limit)
+	var page CourseSearchPage
+	if cached, err := s.cacheService.Get(ctx, key); err == nil && json.Unmarshal([]byte(cached), &page) == nil {
+		return s.withLiveSeats(ctx, &page, filter.OnlyWithSeats), nil
+	}
+
+	// One extra course tells whether there is another page
+	courses, err := s.courseRepo.Search(ctx, filter, afterCode, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(courses) > limit {
+		courses = courses[:limit]
+		page.NextCursor = encodeSearchCursor(courses[limit-1].CourseCode)
+	}
+
+	courseIDs := make([]uuid.UUID, len(courses))
+	for i, course := range courses {
+		courseIDs[i] = course.CourseID
+	}
+	sections, err := s.sectionRepo.SearchByCourses(ctx, courseIDs, filter)
+	if err != nil {
+		return nil, err
+	}
+	byCourse := make(map[uuid.UUID][]*domain.Section, len(courses))
+	for _, section := range sections {
+		byCourse[section.CourseID] = append(byCourse[section.CourseID], section)
+	}
+
+	page.Results = make([]CourseSearchResult, 0, len(courses))
+	for _, course := range courses {
+		page.Results = append(page.Results, CourseSearchResult{Course: course, Sections: byCourse[course.CourseID]})
+	}
+
+	if data, err := json.Marshal(&page); err == nil {
+		if err := s.cacheService.Set(ctx, key, string(data), CourseSearchTTL); err != nil {
+			logger.Warn("Failed to cache course search: %v", err)
+		}
+	}
+
+	return s.withLiveSeats(ctx, &page, filter.OnlyWithSeats), nil
+}
+
+// withLiveSeats replaces the stored seat counts with the seat counters. With onlyWithSeats,
+// sections that filled up since are dropped, and courses left without sections with them.
+func (s *CourseService) withLiveSeats(ctx context.Context, page *CourseSearchPage, onlyWithSeats bool) *CourseSearchPage {
+	var all []*domain.Section
+	for _, result := range page.Results {
+		all = append(all, result.Sections...)
+	}
+	seats := liveSeats(ctx, s.cacheService, all)
+
+	results := page.Results[:0]
+	for _, result := range page.Results {
+		sections := result.Sections[:0]
+		for _, section := range result.Sections {
+			if available, ok := seats[section.SectionID]; ok {
+				section.AvailableSeats = available
+			}
+			if !onlyWithSeats || section.AvailableSeats > 0 {
+				sections = append(sections, section)
+			}
+		}
+		if len(sections) > 0 {
+			result.Sections = sections
+			results = append(results, result)
+		}
+	}
+	page.Results = results
+	return page
+}
+
+func courseSearchFilter(req *CourseSearchRequest) (interfaces.CourseSearchFilter, error) {
+	filter := interfaces.CourseSearchFilter{
+		CodePrefix:    strings.ToUpper(strings.TrimSpace(req.Code)),
+		Title:         strings.TrimSpace(req.Query),
+		Department:    strings.TrimSpace(req.Department),
+		MinCredits:    req.MinCredits,
+		MaxCredits:    req.MaxCredits,
+		Days:          strings.ToUpper(req.Days),
+		StartAfter:    req.StartAfter,
+		EndBefore:     req.EndBefore,
+		OnlyWithSeats: req.HasSeats,
+	}
+	if req.SemesterID != "" {
+		semesterID, err := uuid.Parse(req.SemesterID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid semester_id: %w", err)
+		}
+		filter.SemesterID = &semesterID
+	}
+	return filter, nil
+}
+
+func courseSearchCacheKey(filter interfaces.CourseSearchFilter, afterCode string, limit int) string {
+	data, _ := json.Marshal(struct {
+		Filter    interfaces.CourseSearchFilter
+		AfterCode string
+		Limit     int
+	}{filter, afterCode, limit})
+	hash := sha256.Sum256(data)
+	return courseSearchPrefix + hex.EncodeToString(hash[:])
+}
+
+func encodeSearchCursor(courseCode string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(courseCode))
+}
+
+func decodeSearchCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	courseCode, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	return string(courseCode), nil
+}
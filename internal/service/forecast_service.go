@@ -0,0 +1,305 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// forecastRunTimeout bounds one forecasting run over every open semester
+	forecastRunTimeout = 10 * time.Minute
+	// forecastLockTTL keeps other instances from repeating a day's run
+	forecastLockTTL = 20 * time.Hour
+)
+
+type EnrollmentForecastReport = serviceInterfaces.EnrollmentForecastReport
+type ForecastRunResult = serviceInterfaces.ForecastRunResult
+
+// ForecastService predicts the final enrollment of every section in the semesters whose
+// registration is open. Earlier semesters give a registration curve: the share of their
+// final enrollment reached by each day of the registration window, averaged over all of
+// them. A section's prediction is its enrollment so far divided by the share expected by
+// today. The forecasts are recomputed nightly and read back by the admin reports API.
+type ForecastService struct {
+	forecastRepo     interfaces.EnrollmentForecastRepository
+	registrationRepo interfaces.RegistrationRepository
+	sectionRepo      interfaces.SectionRepository
+	semesterRepo     interfaces.SemesterRepository
+	cacheService     interfaces.CacheService
+	location         *time.Location
+	runHour          int
+	underSubRatio    float64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewForecastService(
+	forecastRepo interfaces.EnrollmentForecastRepository,
+	registrationRepo interfaces.RegistrationRepository,
+	sectionRepo interfaces.SectionRepository,
+	semesterRepo interfaces.SemesterRepository,
+	cacheService interfaces.CacheService,
+	location *time.Location,
+	runHour int,
+	underSubscribedRatio float64,
+) *ForecastService {
+	return &ForecastService{
+		forecastRepo:     forecastRepo,
+		registrationRepo: registrationRepo,
+		sectionRepo:      sectionRepo,
+		semesterRepo:     semesterRepo,
+		cacheService:     cacheService,
+		location:         location,
+		runHour:          runHour,
+		underSubRatio:    underSubscribedRatio,
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+}
+
+// Start runs the forecast every night at the configured hour. Every instance schedules it;
+// a lock taken for the day lets only the first one run.
+func (s *ForecastService) Start() {
+	go s.run()
+}
+
+func (s *ForecastService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+func (s *ForecastService) run() {
+	defer close(s.done)
+
+	for {
+		next := s.nextRun(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), forecastRunTimeout)
+		lockKey := fmt.Sprintf("lock:forecast:%s", next.Format("2006-01-02"))
+		acquired, err := s.cacheService.AcquireLock(ctx, lockKey, uuid.NewString(), forecastLockTTL)
+		switch {
+		case err != nil:
+			logger.Error("Failed to take the enrollment forecast lock: %v", err)
+		case !acquired:
+			logger.Info("Enrollment forecast for %s already run by another instance", next.Format("2006-01-02"))
+		default:
+			if _, err := s.RunForecasts(ctx); err != nil {
+				logger.Error("Nightly enrollment forecast failed: %v", err)
+			}
+		}
+		cancel()
+	}
+}
+
+// nextRun is the next time the configured hour comes round in the institution's time zone
+func (s *ForecastService) nextRun(now time.Time) time.Time {
+	local := now.In(s.location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.runHour, 0, 0, 0, s.location)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunForecasts recomputes the forecasts of every active semester whose registration is open
+func (s *ForecastService) RunForecasts(ctx context.Context) (*ForecastRunResult, error) {
+	now := time.Now()
+
+	counts, err := s.forecastRepo.GetRegistrationCurves(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration history: %w", err)
+	}
+	curve := buildRegistrationCurve(counts)
+
+	semesters, err := s.semesterRepo.GetAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semesters: %w", err)
+	}
+
+	result := &ForecastRunResult{HistorySemesters: curve.semesters}
+	for _, semester := range semesters {
+		if now.Before(semester.RegistrationStart) || now.After(semester.RegistrationEnd) {
+			continue
+		}
+
+		sections, err := s.forecastSemester(ctx, semester, curve, now)
+		if err != nil {
+			return nil, err
+		}
+		result.Semesters++
+		result.Sections += sections
+	}
+
+	logger.Info("Forecast enrollment of %d sections in %d semesters from %d earlier semesters",
+		result.Sections, result.Semesters, result.HistorySemesters)
+	return result, nil
+}
+
+func (s *ForecastService) forecastSemester(ctx context.Context, semester *domain.Semester, curve registrationCurve, now time.Time) (int, error) {
+	sections, err := s.sectionRepo.GetBySemester(ctx, semester.SemesterID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sections of semester %s: %w", semester.SemesterCode, err)
+	}
+	enrolled, err := s.registrationRepo.CountEnrolledBySection(ctx, semester.SemesterID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count enrollments of semester %s: %w", semester.SemesterCode, err)
+	}
+
+	share := curve.shareBy(int(now.Sub(semester.RegistrationStart) / (24 * time.Hour)))
+
+	forecasts := make([]*domain.EnrollmentForecast, 0, len(sections))
+	for _, section := range sections {
+		if !section.IsActive {
+			continue
+		}
+
+		current := enrolled[section.SectionID]
+		predicted := current
+		if share > 0 {
+			predicted = int(math.Round(float64(current) / share))
+		}
+
+		forecasts = append(forecasts, &domain.EnrollmentForecast{
+			SectionID:           section.SectionID,
+			SemesterID:          semester.SemesterID,
+			CourseCode:          section.Course.CourseCode,
+			SectionNumber:       section.SectionNumber,
+			Department:          section.Course.Department,
+			Capacity:            section.TotalSeats,
+			Enrolled:            current,
+			PredictedEnrollment: predicted,
+			Outlook:             s.outlook(predicted, section.TotalSeats),
+			HistorySemesters:    curve.semesters,
+			GeneratedAt:         now,
+		})
+	}
+
+	if err := s.forecastRepo.ReplaceForSemester(ctx, semester.SemesterID, forecasts); err != nil {
+		return 0, fmt.Errorf("failed to save forecasts of semester %s: %w", semester.SemesterCode, err)
+	}
+	return len(forecasts), nil
+}
+
+func (s *ForecastService) outlook(predicted, capacity int) domain.ForecastOutlook {
+	switch {
+	case predicted > capacity:
+		return domain.OutlookOverSubscribed
+	case float64(predicted) < float64(capacity)*s.underSubRatio:
+		return domain.OutlookUnderSubscribed
+	default:
+		return domain.OutlookOnTrack
+	}
+}
+
+// GetForecasts returns the latest forecasts of a semester, only those of department unless
+// it is empty
+func (s *ForecastService) GetForecasts(ctx context.Context, semesterID uuid.UUID, department string) (*EnrollmentForecastReport, error) {
+	semester, err := s.semesterRepo.GetByID(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semester: %w", err)
+	}
+	if semester == nil {
+		return nil, ErrSemesterNotFound
+	}
+
+	forecasts, err := s.forecastRepo.GetBySemester(ctx, semesterID, department)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get forecasts: %w", err)
+	}
+
+	report := &EnrollmentForecastReport{
+		SemesterID: semesterID,
+		Department: department,
+		Outlooks:   make(map[domain.ForecastOutlook]int, 3),
+		Forecasts:  forecasts,
+	}
+	for _, forecast := range forecasts {
+		report.Outlooks[forecast.Outlook]++
+		if report.GeneratedAt == nil || forecast.GeneratedAt.After(*report.GeneratedAt) {
+			generatedAt := forecast.GeneratedAt
+			report.GeneratedAt = &generatedAt
+		}
+	}
+	return report, nil
+}
+
+// registrationCurve holds, for each day of the registration window, the average share of
+// final enrollment reached by the end of that day
+type registrationCurve struct {
+	shares    []float64
+	semesters int
+}
+
+func buildRegistrationCurve(counts []interfaces.RegistrationDayCount) registrationCurve {
+	perSemester := make(map[uuid.UUID][]int)
+	days := 0
+	for _, count := range counts {
+		daily := perSemester[count.SemesterID]
+		for len(daily) <= count.Day {
+			daily = append(daily, 0)
+		}
+		daily[count.Day] += count.Count
+		perSemester[count.SemesterID] = daily
+		days = max(days, count.Day+1)
+	}
+
+	curve := registrationCurve{shares: make([]float64, days)}
+	for _, daily := range perSemester {
+		total := 0
+		for _, count := range daily {
+			total += count
+		}
+		if total == 0 {
+			continue
+		}
+
+		// A semester whose window was shorter was complete from its last day on
+		cumulative := 0
+		for day := range curve.shares {
+			if day < len(daily) {
+				cumulative += daily[day]
+			}
+			curve.shares[day] += float64(cumulative) / float64(total)
+		}
+		curve.semesters++
+	}
+
+	for day := range curve.shares {
+		if curve.semesters > 0 {
+			curve.shares[day] /= float64(curve.semesters)
+		}
+	}
+	return curve
+}
+
+// shareBy is the share of final enrollment expected by the end of the given day. Past the
+// longest window seen, registration is taken to be complete. Without history it is 0.
+func (c registrationCurve) shareBy(day int) float64 {
+	if c.semesters == 0 {
+		return 0
+	}
+	if day >= len(c.shares) {
+		return 1
+	}
+	return c.shares[max(day, 0)]
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout bounds each dependency check, so one hung dependency cannot hold up
+// the probe past the load balancer's timeout
+const healthCheckTimeout = 2 * time.Second
+
+// Status of one dependency
+const (
+	DependencyUp       = "up"
+	DependencyDegraded = "degraded"
+	DependencyDown     = "down"
+)
+
+// Overall status of the instance
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// DependencyCheck is the outcome of checking one dependency. A critical dependency that is
+// down makes the instance unhealthy; anything else short of up only degrades it.
+type DependencyCheck struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+	Details   any    `json:"details,omitempty"`
+}
+
+type HealthReport struct {
+	Status string `json:"status"`
+	// Degraded is set when the instance serves registrations without some of its
+	// non-critical dependencies
+	Degraded     bool                       `json:"degraded"`
+	Dependencies map[string]DependencyCheck `json:"dependencies"`
+	CheckedAt    time.Time                  `json:"checked_at"`
+}
+
+// Ready reports whether the instance can take registrations
+func (r *HealthReport) Ready() bool {
+	return r.Status != HealthUnhealthy
+}
+
+// HealthService checks the dependencies registrations rely on: Postgres and Redis, which
+// they cannot do without, and the Sentinels, queue workers and migrations, without which
+// they still go through but something lags or is at risk. With the database fallback on,
+// registrations get by without Redis too, so losing it only degrades the instance.
+type HealthService struct {
+	database interfaces.DatabaseHealth
+	redis    interfaces.RedisHealth
+	// workers is nil for queues that do not report on their workers
+	workers       interfaces.WorkerHealth
+	redisCritical bool
+}
+
+func NewHealthService(database interfaces.DatabaseHealth, redis interfaces.RedisHealth, workers interfaces.WorkerHealth, redisCritical bool) *HealthService {
+	return &HealthService{
+		database:      database,
+		redis:         redis,
+		workers:       workers,
+		redisCritical: redisCritical,
+	}
+}
+
+type dependencyCheckFunc func(ctx context.Context) (status string, details any, err error)
+
+type dependency struct {
+	critical bool
+	check    dependencyCheckFunc
+}
+
+// Check runs every dependency check at once and sums them up
+func (s *HealthService) Check(ctx context.Context) *HealthReport {
+	checks := map[string]dependency{
+		"database":   {true, s.checkDatabase},
+		"redis":      {s.redisCritical, s.checkRedis},
+		"sentinel":   {false, s.checkSentinel},
+		"migrations": {false, s.checkMigrations},
+	}
+	if s.workers != nil {
+		checks["queue_workers"] = dependency{false, s.checkWorkers}
+	}
+
+	report := &HealthReport{
+		Status:       HealthHealthy,
+		Dependencies: make(map[string]DependencyCheck, len(checks)),
+		CheckedAt:    time.Now(),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, dep := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := runDependencyCheck(ctx, dep.critical, dep.check)
+			mu.Lock()
+			report.Dependencies[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Dependencies {
+		switch {
+		case result.Status == DependencyUp:
+		case result.Critical && result.Status == DependencyDown:
+			report.Status = HealthUnhealthy
+		case report.Status == HealthHealthy:
+			report.Status = HealthDegraded
+		}
+	}
+	report.Degraded = report.Status == HealthDegraded
+	return report
+}
+
+func runDependencyCheck(ctx context.Context, critical bool, check dependencyCheckFunc) DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	status, details, err := check(ctx)
+	result := DependencyCheck{
+		Status:    status,
+		Critical:  critical,
+		LatencyMS: time.Since(start).Milliseconds(),
+		Details:   details,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (s *HealthService) checkDatabase(ctx context.Context) (string, any, error) {
+	if err := s.database.Ping(ctx); err != nil {
+		return DependencyDown, nil, err
+	}
+	return DependencyUp, nil, nil
+}
+
+func (s *HealthService) checkRedis(ctx context.Context) (string, any, error) {
+	if err := s.redis.Ping(ctx); err != nil {
+		return DependencyDown, nil, err
+	}
+	return DependencyUp, nil, nil
+}
+
+// checkSentinel degrades while the Sentinels see the master down or fail it over, and
+// when some of them cannot be reached, since another failure might then go unhandled
+func (s *HealthService) checkSentinel(ctx context.Context) (string, any, error) {
+	state, err := s.redis.Sentinel(ctx)
+	if err != nil {
+		return DependencyDown, state, err
+	}
+	switch {
+	case state.FailoverInProgress:
+		return DependencyDegraded, state, fmt.Errorf("failover of %s in progress", state.MasterName)
+	case state.MasterDown:
+		return DependencyDegraded, state, fmt.Errorf("master %s is flagged down", state.MasterName)
+	case state.Reachable < state.Configured:
+		return DependencyDegraded, state, fmt.Errorf("%d of %d sentinels answered", state.Reachable, state.Configured)
+	}
+	return DependencyUp, state, nil
+}
+
+// checkMigrations degrades while migrations are pending, since a deploy ahead of its schema
+// fails the registrations that touch the new columns
+func (s *HealthService) checkMigrations(ctx context.Context) (string, any, error) {
+	pending, err := s.database.PendingMigrations(ctx)
+	if err != nil {
+		return DependencyDegraded, nil, err
+	}
+	if len(pending) > 0 {
+		return DependencyDegraded, map[string]any{"pending": pending}, fmt.Errorf("migrations %s not applied", strings.Join(pending, ", "))
+	}
+	return DependencyUp, nil, nil
+}
+
+// checkWorkers degrades when some queue workers are gone and reports the workers down when
+// none runs; seats are still taken, but the database falls behind the cache
+func (s *HealthService) checkWorkers(ctx context.Context) (string, any, error) {
+	status := s.workers.WorkerStatus()
+	switch {
+	case !status.Started:
+		return DependencyDown, status, fmt.Errorf("queue workers not started")
+	case status.Running == 0:
+		return DependencyDown, status, fmt.Errorf("no queue worker is running")
+	case status.Running < status.Expected:
+		return DependencyDegraded, status, fmt.Errorf("%d of %d queue workers running", status.Running, status.Expected)
+	}
+	return DependencyUp, status, nil
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxImportRows bounds a single upload so one request cannot hold a worker for too long
+const MaxImportRows = 5000
+
+const (
+	ImportRowEnrolled          = "enrolled"
+	ImportRowWaitlisted        = "waitlisted"
+	ImportRowAlreadyRegistered = "already_registered"
+	ImportRowFailed            = "failed"
+	ImportRowInvalid           = "invalid"
+	ImportRowEligible          = "eligible"
+	ImportRowIneligible        = "ineligible"
+)
+
+var (
+	ErrImportHeader   = errors.New("CSV header must contain student_number, course_code and section_number")
+	ErrImportTooLarge = fmt.Errorf("CSV has more than %d rows", MaxImportRows)
+	ErrNoSemester     = errors.New("no semester given and no semester is currently running")
+)
+
+var importColumns = []string{"student_number", "course_code", "section_number"}
+
+type ImportOptions struct {
+	SemesterID uuid.UUID
+	// DryRun resolves every row and runs the eligibility checks without taking seats
+	DryRun bool
+}
+
+type ImportRowResult struct {
+	Row              int       `json:"row"`
+	StudentNumber    string    `json:"student_number"`
+	CourseCode       string    `json:"course_code"`
+	SectionNumber    string    `json:"section_number"`
+	StudentID        uuid.UUID `json:"student_id,omitempty"`
+	SectionID        uuid.UUID `json:"section_id,omitempty"`
+	Status           string    `json:"status"`
+	Message          string    `json:"message,omitempty"`
+	WaitlistPosition *int      `json:"waitlist_position,omitempty"`
+}
+
+type ImportReport struct {
+	SemesterID uuid.UUID         `json:"semester_id"`
+	DryRun     bool              `json:"dry_run"`
+	Rows       int               `json:"rows"`
+	Summary    map[string]int    `json:"summary"`
+	Results    []ImportRowResult `json:"results"`
+}
+
+type ImportService struct {
+	registrationService *RegistrationService
+	studentRepo         interfaces.StudentRepository
+	courseRepo          interfaces.CourseRepository
+	sectionRepo         interfaces.SectionRepository
+	semesterRepo        interfaces.SemesterRepository
+}
+
+func NewImportService(
+	registrationService *RegistrationService,
+	studentRepo interfaces.StudentRepository,
+	courseRepo interfaces.CourseRepository,
+	sectionRepo interfaces.SectionRepository,
+	semesterRepo interfaces.SemesterRepository,
+) *ImportService {
+	return &ImportService{
+		registrationService: registrationService,
+		studentRepo:         studentRepo,
+		courseRepo:          courseRepo,
+		sectionRepo:         sectionRepo,
+		semesterRepo:        semesterRepo,
+	}
+}
+
+// importLookups remembers resolved IDs for the length of one import, since registrar files
+// repeat the same students and sections on many rows.
+type importLookups struct {
+	students map[string]*domain.Student
+	sections map[string]*domain.Section
+}
+
+// ImportRegistrations reads registration rows from r one at a time and sends each through the
+// normal registration pipeline, so seats, waitlists and idempotency behave exactly as for
+// students registering themselves. A bad row is reported and does not stop the import.
+func (s *ImportService) ImportRegistrations(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	semesterID, err := s.resolveSemester(ctx, opts.SemesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrImportHeader
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns, ok := importColumnIndexes(header, importColumns)
+	if !ok {
+		return nil, ErrImportHeader
+	}
+
+	report := &ImportReport{
+		SemesterID: semesterID,
+		DryRun:     opts.DryRun,
+		Summary:    make(map[string]int),
+		Results:    make([]ImportRowResult, 0),
+	}
+	lookups := &importLookups{
+		students: make(map[string]*domain.Student),
+		sections: make(map[string]*domain.Section),
+	}
+
+	for row := 2; ; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if report.Rows >= MaxImportRows {
+			return nil, ErrImportTooLarge
+		}
+		report.Rows++
+
+		var result ImportRowResult
+		if err != nil {
+			result = ImportRowResult{Row: row, Status: ImportRowInvalid, Message: fmt.Sprintf("Malformed CSV row: %v", err)}
+		} else {
+			result = s.importRow(ctx, row, record, columns, semesterID, opts.DryRun, lookups)
+		}
+
+		report.Summary[result.Status]++
+		report.Results = append(report.Results, result)
+	}
+
+	logger.Info("Imported %d registration rows for semester %s (dry run: %t): %v", report.Rows, semesterID, opts.DryRun, report.Summary)
+	return report, nil
+}
+
+func (s *ImportService) importRow(
+	ctx context.Context,
+	row int,
+	record []string,
+	columns map[string]int,
+	semesterID uuid.UUID,
+	dryRun bool,
+	lookups *importLookups,
+) ImportRowResult {
+	result := ImportRowResult{
+		Row:           row,
+		StudentNumber: importField(record, columns["student_number"]),
+		CourseCode:    importField(record, columns["course_code"]),
+		SectionNumber: importField(record, columns["section_number"]),
+	}
+	if result.StudentNumber == "" || result.CourseCode == "" || result.SectionNumber == "" {
+		result.Status = ImportRowInvalid
+		result.Message = "student_number, course_code and section_number are required"
+		return result
+	}
+
+	student, err := s.lookupStudent(ctx, result.StudentNumber, lookups)
+	if err != nil || student == nil {
+		result.Status = ImportRowInvalid
+		result.Message = importLookupMessage("student", result.StudentNumber, err)
+		return result
+	}
+	result.StudentID = student.StudentID
+
+	section, err := s.lookupSection(ctx, result.CourseCode, result.SectionNumber, semesterID, lookups)
+	if err != nil || section == nil {
+		result.Status = ImportRowInvalid
+		result.Message = importLookupMessage("section", result.CourseCode+"-"+result.SectionNumber, err)
+		return result
+	}
+	result.SectionID = section.SectionID
+
+	if dryRun {
+		return s.checkRow(ctx, result)
+	}
+
+	response, err := s.registrationService.Register(ctx, &RegisterRequest{
+		StudentID:  student.StudentID,
+		SectionIDs: []uuid.UUID{section.SectionID},
+	})
+	if err != nil {
+		result.Status = ImportRowFailed
+		result.Message = err.Error()
+		return result
+	}
+
+	registration := response.Results[0]
+	result.Status = registration.Status
+	result.Message = registration.Message
+	result.WaitlistPosition = registration.Position
+	return result
+}
+
+// checkRow reports whether the row would register, using the same checks as the
+// eligibility endpoint
+func (s *ImportService) checkRow(ctx context.Context, result ImportRowResult) ImportRowResult {
+	eligibility, err := s.registrationService.CheckEligibility(ctx, result.StudentID, result.SectionID)
+	if err != nil {
+		result.Status = ImportRowFailed
+		result.Message = err.Error()
+		return result
+	}
+
+	if eligibility.Eligible {
+		result.Status = ImportRowEligible
+		return result
+	}
+
+	var reasons []string
+	for _, check := range eligibility.Checks {
+		if check.Status == serviceInterfaces.CheckFailed {
+			reasons = append(reasons, check.Reason)
+		}
+	}
+	result.Status = ImportRowIneligible
+	result.Message = strings.Join(reasons, "; ")
+	return result
+}
+
+func (s *ImportService) resolveSemester(ctx context.Context, semesterID uuid.UUID) (uuid.UUID, error) {
+	if semesterID != uuid.Nil {
+		semester, err := s.semesterRepo.GetByID(ctx, semesterID)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("failed to get semester: %w", err)
+		}
+		if semester == nil {
+			return uuid.Nil, ErrSemesterNotFound
+		}
+		return semesterID, nil
+	}
+
+	semester, err := s.semesterRepo.GetCurrent(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get current semester: %w", err)
+	}
+	if semester == nil {
+		return uuid.Nil, ErrNoSemester
+	}
+	return semester.SemesterID, nil
+}
+
+func (s *ImportService) lookupStudent(ctx context.Context, studentNumber string, lookups *importLookups) (*domain.Student, error) {
+	if student, ok := lookups.students[studentNumber]; ok {
+		return student, nil
+	}
+
+	student, err := s.studentRepo.GetByStudentNumber(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	lookups.students[studentNumber] = student
+	return student, nil
+}
+
+func (s *ImportService) lookupSection(ctx context.Context, courseCode, sectionNumber string, semesterID uuid.UUID, lookups *importLookups) (*domain.Section, error) {
+	key := courseCode + "\x00" + sectionNumber
+	if section, ok := lookups.sections[key]; ok {
+		return section, nil
+	}
+
+	course, err := s.courseRepo.GetByCode(ctx, courseCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *domain.Section
+	if course != nil {
+		sections, err := s.sectionRepo.GetByCourseAndSemester(ctx, course.CourseID, semesterID)
+		if err != nil {
+			return nil, err
+		}
+		for _, section := range sections {
+			if strings.EqualFold(section.SectionNumber, sectionNumber) {
+				found = section
+				break
+			}
+		}
+	}
+
+	lookups.sections[key] = found
+	return found, nil
+}
+
+// importColumnIndexes maps the header's column names to their indexes, reporting whether
+// every required column is there
+func importColumnIndexes(header, required []string) (map[string]int, bool) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, false
+		}
+	}
+	return columns, true
+}
+
+func importField(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+func importLookupMessage(kind, value string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("Failed to look up %s %s: %v", kind, value, err)
+	}
+	return fmt.Sprintf("Unknown %s %s", kind, value)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultKPIWindowMinutes = 15
+	MaxKPIWindowMinutes     = 120
+)
+
+type KPISnapshot = serviceInterfaces.KPISnapshot
+type KPIRate = serviceInterfaces.KPIRate
+type KPIMinuteCount = serviceInterfaces.KPIMinuteCount
+type SemesterKPITotals = serviceInterfaces.SemesterKPITotals
+
+type KPIService struct {
+	counters    interfaces.KPICounterStore
+	sectionRepo interfaces.SectionRepository
+}
+
+func NewKPIService(counters interfaces.KPICounterStore, sectionRepo interfaces.SectionRepository) *KPIService {
+	return &KPIService{
+		counters:    counters,
+		sectionRepo: sectionRepo,
+	}
+}
+
+// GetKPIs reads the dashboard counters. Only the per-semester rollup touches the database,
+// and then only to map the counted sections to their semesters.
+func (s *KPIService) GetKPIs(ctx context.Context, windowMinutes int) (*KPISnapshot, error) {
+	now := time.Now()
+	currentMinute := now.Truncate(time.Minute)
+
+	snapshot := &KPISnapshot{
+		GeneratedAt:   now,
+		WindowMinutes: windowMinutes,
+		Rates:         make(map[interfaces.KPIMetric]KPIRate, len(interfaces.KPIMetrics)),
+	}
+
+	totals := make(map[uuid.UUID]map[interfaces.KPIMetric]int64)
+	for _, metric := range interfaces.KPIMetrics {
+		// One extra slot for the minute that is still in progress
+		counts, err := s.counters.PerMinute(ctx, metric, currentMinute, windowMinutes+1)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Rates[metric] = buildKPIRate(counts, currentMinute)
+
+		sectionTotals, err := s.counters.SectionTotals(ctx, metric)
+		if err != nil {
+			return nil, err
+		}
+		for sectionID, count := range sectionTotals {
+			if totals[sectionID] == nil {
+				totals[sectionID] = make(map[interfaces.KPIMetric]int64, len(interfaces.KPIMetrics))
+			}
+			totals[sectionID][metric] = count
+		}
+	}
+
+	semesters, err := s.rollupBySemester(ctx, totals)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Semesters = semesters
+
+	return snapshot, nil
+}
+
+func buildKPIRate(counts []int64, currentMinute time.Time) KPIRate {
+	complete := counts[:len(counts)-1]
+	rate := KPIRate{
+		CurrentMinute: counts[len(counts)-1],
+		Series:        make([]KPIMinuteCount, len(complete)),
+	}
+
+	var sum int64
+	for i, count := range complete {
+		rate.Series[i] = KPIMinuteCount{
+			Minute: currentMinute.Add(-time.Duration(len(complete)-i) * time.Minute),
+			Count:  count,
+		}
+		sum += count
+	}
+	if len(complete) > 0 {
+		rate.LastMinute = complete[len(complete)-1]
+		rate.AveragePerMinute = float64(sum) / float64(len(complete))
+	}
+	return rate
+}
+
+func (s *KPIService) rollupBySemester(ctx context.Context, totals map[uuid.UUID]map[interfaces.KPIMetric]int64) ([]SemesterKPITotals, error) {
+	if len(totals) == 0 {
+		return []SemesterKPITotals{}, nil
+	}
+
+	sections, err := s.sectionRepo.GetAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	semesterOf := make(map[uuid.UUID]uuid.UUID, len(sections))
+	for _, section := range sections {
+		semesterOf[section.SectionID] = section.SemesterID
+	}
+
+	bySemester := make(map[uuid.UUID]*SemesterKPITotals)
+	for sectionID, counts := range totals {
+		semesterID, ok := semesterOf[sectionID]
+		if !ok {
+			// Sections closed since they were counted are not in the active list
+			section, err := s.sectionRepo.GetByID(ctx, sectionID)
+			if err != nil || section == nil {
+				logger.Warn("Skipping KPI totals of unknown section %s", sectionID)
+				continue
+			}
+			semesterID = section.SemesterID
+			semesterOf[sectionID] = semesterID
+		}
+
+		semester := bySemester[semesterID]
+		if semester == nil {
+			semester = &SemesterKPITotals{SemesterID: semesterID}
+			bySemester[semesterID] = semester
+		}
+		semester.Registrations += counts[interfaces.KPIRegistrations]
+		semester.Drops += counts[interfaces.KPIDrops]
+		semester.WaitlistAdds += counts[interfaces.KPIWaitlistAdds]
+	}
+
+	result := make([]SemesterKPITotals, 0, len(bySemester))
+	for _, semester := range bySemester {
+		result = append(result, *semester)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SemesterID.String() < result[j].SemesterID.String()
+	})
+	return result, nil
+}
+
+// countKPI bumps a dashboard counter. A failure only costs dashboard accuracy, so it is
+// logged rather than failing the registration.
+func (s *RegistrationService) countKPI(ctx context.Context, metric interfaces.KPIMetric, sectionID uuid.UUID) {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	if s.kpiCounters == nil {
+		return
+	}
+	if err := s.kpiCounters.Increment(ctx, metric, sectionID, time.Now()); err != nil {
+		log.Warn("Failed to count %s for section %s: %v", metric, sectionID, err)
+	}
+}
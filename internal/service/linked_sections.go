@@ -0,0 +1,293 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Results of registrations for linked sections
+const (
+	// ResultLinkedSectionRequired is a lecture requested without one of its components, or
+	// a component without its lecture, which the student is not enrolled in either
+	ResultLinkedSectionRequired = "linked_section_required"
+	// ResultLinkedSectionFailed is a section left alone because its linked section could
+	// not be registered for
+	ResultLinkedSectionFailed = "linked_section_failed"
+	// ResultLinkedSectionWaitlisted is a section the student waits for together with its
+	// linked section, which is full and holds the waitlist place
+	ResultLinkedSectionWaitlisted = "linked_section_waitlisted"
+)
+
+// registrationUnit is what Register takes in one step: a single section, a lecture with the
+// component requested with it, or a result that stops the sections from being tried
+type registrationUnit struct {
+	sectionIDs []uuid.UUID
+	result     *RegistrationResult
+}
+
+// registrationUnits pairs the requested lectures with their requested components, in the
+// order the lectures were requested. A lecture needs exactly one of its components, unless
+// the student is enrolled in one already, and a component needs its lecture the same way.
+// Sections that cannot be looked up go on alone and fail there.
+func (s *RegistrationService) registrationUnits(ctx context.Context, studentID uuid.UUID, sectionIDs []uuid.UUID) []registrationUnit {
+	sections := make(map[uuid.UUID]*domain.Section, len(sectionIDs))
+	for _, sectionID := range sectionIDs {
+		if section, err := s.getSectionMetadata(ctx, sectionID); err == nil && section != nil {
+			sections[sectionID] = section
+		}
+	}
+
+	var enrolled map[uuid.UUID]bool
+	isEnrolled := func(sectionID uuid.UUID) bool {
+		if enrolled == nil {
+			enrolled = make(map[uuid.UUID]bool)
+			registrations, err := s.GetStudentRegistrations(ctx, studentID)
+			if err != nil {
+				registrationLog(ctx, studentID, uuid.Nil).Warn("Failed to get registrations of student %s for linked sections: %v", studentID, err)
+			}
+			for _, registration := range registrations {
+				if registration.Status == domain.StatusEnrolled {
+					enrolled[registration.SectionID] = true
+				}
+			}
+		}
+		return enrolled[sectionID]
+	}
+	required := func(sectionID uuid.UUID, message string) registrationUnit {
+		return registrationUnit{result: &RegistrationResult{SectionID: sectionID, Status: ResultLinkedSectionRequired, Message: message}}
+	}
+
+	units := make([]registrationUnit, 0, len(sectionIDs))
+	for _, sectionID := range sectionIDs {
+		section := sections[sectionID]
+		switch {
+		case section == nil:
+			units = append(units, registrationUnit{sectionIDs: []uuid.UUID{sectionID}})
+
+		case section.LinkedSectionID != nil:
+			// A component requested with its lecture is taken in the lecture's unit
+			if slices.Contains(sectionIDs, *section.LinkedSectionID) {
+				continue
+			}
+			if isEnrolled(*section.LinkedSectionID) {
+				units = append(units, registrationUnit{sectionIDs: []uuid.UUID{sectionID}})
+				continue
+			}
+			units = append(units, required(sectionID, "Register for this section together with the section it is linked to"))
+
+		case len(section.ComponentIDs) > 0:
+			var components []uuid.UUID
+			for _, componentID := range section.ComponentIDs {
+				if slices.Contains(sectionIDs, componentID) {
+					components = append(components, componentID)
+				}
+			}
+			switch {
+			case len(components) == 1:
+				units = append(units, registrationUnit{sectionIDs: []uuid.UUID{sectionID, components[0]}})
+			case len(components) > 1:
+				units = append(units, required(sectionID, "Choose only one of the sections linked to this section"))
+				for _, componentID := range components {
+					units = append(units, required(componentID, "Choose only one of the sections linked to this section"))
+				}
+			case slices.ContainsFunc(section.ComponentIDs, isEnrolled):
+				units = append(units, registrationUnit{sectionIDs: []uuid.UUID{sectionID}})
+			default:
+				units = append(units, required(sectionID, "Register for this section together with one of its linked sections"))
+			}
+
+		default:
+			units = append(units, registrationUnit{sectionIDs: []uuid.UUID{sectionID}})
+		}
+	}
+	return units
+}
+
+// registerLinked takes a seat in a lecture and in its component together, or in neither.
+// When one of them is full the student is waitlisted for it, linked to the other, and takes
+// both seats when promoted. The checks register makes for one section are made for both
+// before any seat is taken.
+func (s *RegistrationService) registerLinked(ctx context.Context, studentID uuid.UUID, pair [2]uuid.UUID, permission *domain.PermissionNumber) (results []RegistrationResult) {
+	log := registrationLog(ctx, studentID, pair[0])
+	ctx, span := startSpan(ctx, "RegistrationService.registerLinked",
+		attribute.String("student.id", studentID.String()),
+		attribute.String("section.id", pair[0].String()),
+		attribute.String("section.linked_id", pair[1].String()),
+	)
+	results = make([]RegistrationResult, len(pair))
+	defer func() {
+		span.End()
+		for _, result := range results {
+			s.auditRegistration(ctx, studentID, result)
+			s.emitRegistrationAttempt(studentID, result)
+		}
+	}()
+
+	// fail reports the section that stopped the pair and leaves the other alone
+	fail := func(i int, result RegistrationResult) []RegistrationResult {
+		results[i] = result
+		results[1-i] = RegistrationResult{
+			SectionID: pair[1-i],
+			Status:    ResultLinkedSectionFailed,
+			Message:   "Not registered because its linked section could not be registered for",
+		}
+		return results
+	}
+
+	for i, sectionID := range pair {
+		existing, err := s.registrationRepo.GetByStudentAndSection(ctx, studentID, sectionID)
+		if err == nil && existing != nil {
+			return fail(i, RegistrationResult{
+				SectionID: sectionID,
+				Status:    "already_registered",
+				Message:   fmt.Sprintf("Already registered with status: %s", existing.Status),
+			})
+		}
+		if err := s.checkSectionOpen(ctx, sectionID); err != nil {
+			return fail(i, closedSectionResult(sectionID, err))
+		}
+		if permission == nil || permission.SectionID != sectionID {
+			if err := s.checkInstructorConsent(ctx, sectionID); err != nil {
+				return fail(i, closedSectionResult(sectionID, err))
+			}
+		}
+		if err := s.checkRepeatEnrollment(ctx, studentID, sectionID); err != nil {
+			return fail(i, closedSectionResult(sectionID, err))
+		}
+		if result, ok := s.checkScheduleConflict(ctx, studentID, sectionID); !ok {
+			return fail(i, result)
+		}
+	}
+
+	reservations := make([]interfaces.SeatReservation, len(pair))
+	for i, sectionID := range pair {
+		seat := s.seatRequest(ctx, studentID, sectionID)
+		if permission != nil && permission.SectionID == sectionID {
+			claimed, err := s.claimPermissionNumber(ctx, studentID, permission)
+			if err != nil {
+				log.Error("Failed to claim permission number %s: %v", permission.PermissionID, err)
+				return fail(i, RegistrationResult{SectionID: sectionID, Status: "failed", Message: "Failed to process registration"})
+			}
+			if !claimed {
+				return fail(i, RegistrationResult{SectionID: sectionID, Status: ResultPermissionUsed, Message: "Permission number has already been used"})
+			}
+			defer func() { s.settlePermissionNumber(ctx, studentID, permission, results[i]) }()
+			seat = interfaces.SeatRequest{}
+		}
+		reservations[i] = interfaces.SeatReservation{
+			SectionID: sectionID,
+			Seat:      seat,
+			Jobs:      enrollmentJobs(ctx, studentID, sectionID),
+		}
+	}
+
+	counters, err := s.reserveLinkedSeats(ctx, studentID, reservations)
+	var failed *interfaces.SeatReservationError
+	at := 0
+	if errors.As(err, &failed) && failed.SectionID == pair[1] {
+		at = 1
+	}
+	switch {
+	case err == nil:
+	case errors.Is(err, interfaces.ErrCacheTransient):
+		for i, sectionID := range pair {
+			results[i] = seatCounterUnavailableResult(sectionID, err)
+		}
+		return results
+	case errors.Is(err, interfaces.ErrNoSeatsLeft):
+		waitlisted := s.waitlistForSection(ctx, studentID, pair[at], &pair[1-at])
+		if waitlisted.Status != string(domain.StatusWaitlisted) {
+			return fail(at, waitlisted)
+		}
+		results[at] = waitlisted
+		results[1-at] = RegistrationResult{
+			SectionID: pair[1-at],
+			Status:    ResultLinkedSectionWaitlisted,
+			Message:   "Waiting for a seat together with its linked section",
+		}
+		return results
+	case errors.Is(err, interfaces.ErrSeatsHeldForWaitlist):
+		return fail(at, closedSectionResult(pair[at], ErrWaitlistFrozen))
+	case errors.Is(err, ErrSectionNotFound):
+		return fail(at, closedSectionResult(pair[at], err))
+	default:
+		log.Error("Failed to reserve linked seats in cache: %v", err)
+		return fail(at, RegistrationResult{SectionID: pair[at], Status: "failed", Message: "Failed to process registration"})
+	}
+
+	log.Info("Successfully reserved seats for student %s in linked sections %s and %s", studentID, pair[0], pair[1])
+	for i, sectionID := range pair {
+		s.recordEnrollment(ctx, studentID, sectionID, counters[i].Available)
+		results[i] = enrolledResult(sectionID, counters[i])
+	}
+	return results
+}
+
+// reserveLinkedSeats takes the seats of a linked pair from the counters in one script,
+// loading counters that dropped out of the cache like reserveSeatFromCounter. The database
+// fallback takes one section's seat at a time, so while the counters are unavailable a pair
+// is not taken at all.
+func (s *RegistrationService) reserveLinkedSeats(ctx context.Context, studentID uuid.UUID, reservations []interfaces.SeatReservation) ([]*interfaces.SeatCounter, error) {
+	if !s.useSeatCounter(ctx) {
+		return nil, fmt.Errorf("%w: seat counter circuit is open", interfaces.ErrCacheTransient)
+	}
+
+	// Each section may need a load of its seat counter and of its pool counters
+	for attempt := 1; ; attempt++ {
+		counters, err := s.cacheService.ReserveSeats(ctx, reservations)
+		var failed *interfaces.SeatReservationError
+		if err == nil || attempt > 2*len(reservations) || !errors.As(err, &failed) {
+			s.observeSeatCounter(err)
+			return counters, err
+		}
+		reloaded, reloadErr := s.reloadSeatCounters(ctx, studentID, failed.SectionID, err)
+		if reloadErr != nil {
+			return nil, &interfaces.SeatReservationError{SectionID: failed.SectionID, Err: reloadErr}
+		}
+		if !reloaded {
+			s.observeSeatCounter(err)
+			return counters, err
+		}
+	}
+}
+
+// takeLinkedSeat takes the seat in the linked section of a waitlist entry being promoted,
+// whose own seat was just taken off the counter and who was just taken off the waitlist. If
+// the linked section has no seat for the student, the caller gives back the promoted seat
+// and puts the student back in their waitlist place, so the waitlist waits until both
+// sections have a seat for them. A student already enrolled in the linked section, such as
+// by a promotion that failed after taking it, needs no seat. While the student's
+// registrations cannot be read, no linked seat is taken, since they may hold one already.
+func (s *RegistrationService) takeLinkedSeat(ctx context.Context, sectionID uuid.UUID, entry *domain.WaitlistEntry) bool {
+	if entry.LinkedSectionID == nil {
+		return true
+	}
+	log := registrationLog(ctx, entry.StudentID, sectionID)
+	linkedID := *entry.LinkedSectionID
+
+	registrations, err := s.GetStudentRegistrations(ctx, entry.StudentID)
+	if err != nil {
+		log.Error("Failed to get registrations of student %s to check their seat in section %s linked to section %s, putting them back on the waitlist: %v", entry.StudentID, linkedID, sectionID, err)
+		return false
+	}
+	if slices.ContainsFunc(registrations, func(registration *domain.Registration) bool {
+		return registration.SectionID == linkedID && registration.Status == domain.StatusEnrolled
+	}) {
+		return true
+	}
+
+	counter, err := s.reserveSeat(ctx, entry.StudentID, linkedID, s.seatRequest(ctx, entry.StudentID, linkedID))
+	if err != nil {
+		log.Info("No seat for student %s in section %s linked to section %s, putting them back on the waitlist: %v", entry.StudentID, linkedID, sectionID, err)
+		return false
+	}
+	s.recordEnrollment(ctx, entry.StudentID, linkedID, counter.Available)
+	return true
+}
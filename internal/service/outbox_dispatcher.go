@@ -0,0 +1,150 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// outboxBatchSize is how many outbox entries one read claims
+	outboxBatchSize = 100
+	// outboxBlock is how long a read waits for new entries before checking for stale ones
+	outboxBlock = 2 * time.Second
+	// outboxMinIdle is how long an entry may stay claimed without acknowledgement before
+	// another dispatcher takes it over
+	outboxMinIdle = 30 * time.Second
+	// outboxRetryDelay is how long the dispatcher backs off after the outbox or queue fails
+	outboxRetryDelay = time.Second
+	// outboxDoneTTL is how long a processed job's dedup key is remembered. It only has to
+	// outlive redelivery, which happens within outboxMinIdle.
+	outboxDoneTTL = 24 * time.Hour
+)
+
+// OutboxDispatcher moves database sync jobs from the outbox to the queue. The outbox entry
+// and the seat it records are written in one atomic step; the dispatcher acknowledges an
+// entry only after the queue has accepted its job, so a crash anywhere between leaves the
+// entry for a later read. Jobs can therefore reach the queue more than once, and the
+// consumer skips those whose dedup key it has already processed.
+type OutboxDispatcher struct {
+	outbox       interfaces.SyncOutbox
+	queueService interfaces.QueueService
+	consumer     string
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewOutboxDispatcher(outbox interfaces.SyncOutbox, queueService interfaces.QueueService) *OutboxDispatcher {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "dispatcher"
+	}
+	return &OutboxDispatcher{
+		outbox:       outbox,
+		queueService: queueService,
+		consumer:     fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8]),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (d *OutboxDispatcher) Start() {
+	go d.run()
+}
+
+// Stop finishes the batch in hand and stops reading. Entries read but not yet queued are
+// picked up again by the next dispatcher to run.
+func (d *OutboxDispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		<-d.done
+	})
+}
+
+func (d *OutboxDispatcher) run() {
+	defer close(d.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-d.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+
+		if err := d.dispatch(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("Outbox dispatch failed: %v", err)
+			select {
+			case <-time.After(outboxRetryDelay):
+			case <-d.stop:
+				return
+			}
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context) error {
+	entries, err := d.outbox.Read(ctx, d.consumer, outboxBatchSize, outboxBlock, outboxMinIdle)
+	if err != nil {
+		return err
+	}
+
+	delivered := make([]string, 0, len(entries))
+	defer func() {
+		// Acknowledge what was queued even if a later entry failed, so it is not sent twice
+		if err := d.outbox.Ack(context.Background(), delivered...); err != nil {
+			logger.Warn("Failed to acknowledge %d outbox entries: %v", len(delivered), err)
+		}
+	}()
+
+	for _, entry := range entries {
+		if err := d.queueService.EnqueueDatabaseSync(ctx, entry.Job); err != nil {
+			return fmt.Errorf("failed to queue outbox entry %s: %w", entry.ID, err)
+		}
+		delivered = append(delivered, entry.ID)
+	}
+	return nil
+}
+
+func outboxDoneKey(dedupKey string) string {
+	return "outbox:done:" + dedupKey
+}
+
+// outboxJobDone reports whether a job delivered through the outbox was already processed
+func (s *RegistrationService) outboxJobDone(ctx context.Context, job interfaces.DatabaseSyncJob) bool {
+	if job.DedupKey == "" {
+		return false
+	}
+	_, err := s.cacheService.Get(ctx, outboxDoneKey(job.DedupKey))
+	return err == nil
+}
+
+func (s *RegistrationService) markOutboxJobDone(ctx context.Context, job interfaces.DatabaseSyncJob) {
+	log := registrationLog(ctx, job.StudentID, job.SectionID)
+	if job.DedupKey == "" {
+		return
+	}
+	if err := s.cacheService.Set(ctx, outboxDoneKey(job.DedupKey), job.Timestamp.Format(time.RFC3339), outboxDoneTTL); err != nil {
+		log.Warn("Failed to record outbox job %s as done: %v", job.DedupKey, err)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// permissionCodeAlphabet leaves out letters and digits that are easily mistaken for one
+	// another when read out or copied by hand
+	permissionCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	permissionCodeLength   = 8
+	// permissionNumberClaimTTL is how long the Redis claim on a used number lasts; it only
+	// has to outlive the database write that records the use
+	permissionNumberClaimTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrPermissionNumberNotFound = errors.New("permission number not found")
+	ErrPermissionNumberUsed     = errors.New("permission number has already been used or has expired")
+	ErrPermissionNumberSection  = errors.New("permission number is for a section not in the request")
+	ErrInvalidPermissionExpiry  = errors.New("expires_at must be in the future")
+)
+
+// ResultPermissionUsed is the registration result status when another registration used
+// the permission number first
+const ResultPermissionUsed = "permission_used"
+
+type IssuePermissionNumbersRequest = serviceInterfaces.IssuePermissionNumbersRequest
+
+// IssuePermissionNumbers creates req.Count permission numbers for the section
+func (s *RegistrationService) IssuePermissionNumbers(ctx context.Context, sectionID uuid.UUID, req *IssuePermissionNumbersRequest) ([]*domain.PermissionNumber, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidPermissionExpiry
+	}
+
+	var issuedBy string
+	if claims, ok := auth.FromContext(ctx); ok {
+		issuedBy = claims.Subject
+	}
+	permissions := make([]*domain.PermissionNumber, req.Count)
+	for i := range permissions {
+		code, err := newPermissionCode()
+		if err != nil {
+			return nil, err
+		}
+		permissions[i] = &domain.PermissionNumber{
+			PermissionID: uuid.New(),
+			SectionID:    sectionID,
+			Code:         code,
+			StudentID:    req.StudentID,
+			IssuedBy:     issuedBy,
+			Note:         strings.TrimSpace(req.Note),
+			ExpiresAt:    req.ExpiresAt,
+		}
+	}
+	if err := s.permissionNumberRepo.Create(ctx, permissions); err != nil {
+		return nil, fmt.Errorf("failed to create permission numbers: %w", err)
+	}
+
+	registrationLog(ctx, uuid.Nil, sectionID).Info("Issued %d permission numbers for section %s", len(permissions), sectionID)
+	change := AuditChange{
+		Action:    domain.AuditPermissionIssued,
+		SectionID: sectionID,
+		After:     map[string]any{"count": len(permissions), "expires_at": req.ExpiresAt},
+	}
+	if req.StudentID != nil {
+		change.StudentID = *req.StudentID
+	}
+	s.auditService.Record(ctx, change)
+	return permissions, nil
+}
+
+func (s *RegistrationService) ListPermissionNumbers(ctx context.Context, sectionID uuid.UUID) ([]*domain.PermissionNumber, error) {
+	permissions, err := s.permissionNumberRepo.ListBySection(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list permission numbers: %w", err)
+	}
+	return permissions, nil
+}
+
+// getPermissionNumber looks up a code given with a registration. Numbers issued to another
+// student are reported as missing.
+func (s *RegistrationService) getPermissionNumber(ctx context.Context, studentID uuid.UUID, code string, sectionIDs []uuid.UUID) (*domain.PermissionNumber, error) {
+	permission, err := s.permissionNumberRepo.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permission number: %w", err)
+	}
+	if permission == nil || (permission.StudentID != nil && *permission.StudentID != studentID) {
+		return nil, ErrPermissionNumberNotFound
+	}
+	if !permission.Usable(studentID, time.Now()) {
+		return nil, ErrPermissionNumberUsed
+	}
+	if !slices.Contains(sectionIDs, permission.SectionID) {
+		return nil, ErrPermissionNumberSection
+	}
+	return permission, nil
+}
+
+// claimPermissionNumber takes the number for the student in Redis. Only one registration
+// can hold the claim, so a number handed to two students cannot seat both.
+func (s *RegistrationService) claimPermissionNumber(ctx context.Context, studentID uuid.UUID, permission *domain.PermissionNumber) (bool, error) {
+	key := interfaces.PermissionNumberClaimKey.Key(permission.PermissionID)
+	return s.cacheService.AcquireLock(ctx, key, studentID.String(), permissionNumberClaimTTL)
+}
+
+// settlePermissionNumber records the use of a claimed number once the student is enrolled.
+// Any other outcome, such as a waitlist place, gives the number back.
+func (s *RegistrationService) settlePermissionNumber(ctx context.Context, studentID uuid.UUID, permission *domain.PermissionNumber, result RegistrationResult) {
+	log := registrationLog(ctx, studentID, permission.SectionID)
+	if result.Status != string(domain.StatusEnrolled) {
+		key := interfaces.PermissionNumberClaimKey.Key(permission.PermissionID)
+		if _, err := s.cacheService.ReleaseLock(ctx, key, studentID.String()); err != nil {
+			log.Warn("Failed to release claim on permission number %s: %v", permission.PermissionID, err)
+		}
+		return
+	}
+
+	// The Redis claim already stops the number being used again, so a failed write is
+	// logged rather than undoing the enrollment
+	ctx = context.WithoutCancel(ctx)
+	if _, err := s.permissionNumberRepo.MarkUsed(ctx, permission.PermissionID, studentID, time.Now()); err != nil {
+		log.Error("Failed to record use of permission number %s by student %s: %v", permission.PermissionID, studentID, err)
+	}
+	log.Info("Student %s used permission number %s for section %s", studentID, permission.PermissionID, permission.SectionID)
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditPermissionUsed,
+		StudentID: studentID,
+		SectionID: permission.SectionID,
+		After:     map[string]any{"permission_id": permission.PermissionID},
+	})
+}
+
+func newPermissionCode() (string, error) {
+	random := make([]byte, permissionCodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate permission number: %w", err)
+	}
+	code := make([]byte, permissionCodeLength)
+	for i, b := range random {
+		code[i] = permissionCodeAlphabet[int(b)%len(permissionCodeAlphabet)]
+	}
+	return string(code), nil
+}
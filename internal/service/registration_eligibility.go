@@ -0,0 +1,276 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EligibilityCheck = serviceInterfaces.EligibilityCheck
+type EligibilityResponse = serviceInterfaces.EligibilityResponse
+
+const (
+	CheckStudentExists      = "student_exists"
+	CheckStudentActive      = "student_active"
+	CheckNoHolds            = "no_holds"
+	CheckSectionExists      = "section_exists"
+	CheckSectionActive      = "section_active"
+	CheckRegistrationWindow = "registration_window"
+	CheckAddDropDeadline    = "add_drop_deadline"
+	CheckNotRegistered      = "not_already_registered"
+	CheckNotWaitlisted      = "not_already_waitlisted"
+	CheckRepeatPolicy       = "repeat_policy"
+	CheckNoTimeConflict     = "no_time_conflict"
+	CheckSeatAvailability   = "seat_availability"
+)
+
+// CheckEligibility evaluates the registration rules for a student and section without
+// reserving anything, so clients can explain up front why registering would not succeed.
+func (s *RegistrationService) CheckEligibility(ctx context.Context, studentID, sectionID uuid.UUID) (*EligibilityResponse, error) {
+	response := &EligibilityResponse{
+		StudentID: studentID,
+		SectionID: sectionID,
+		Checks:    make([]EligibilityCheck, 0, 8),
+	}
+
+	student, err := s.GetStudentDetails(ctx, studentID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+	if student == nil {
+		addEligibilityCheck(response, CheckStudentExists, serviceInterfaces.CheckFailed, "Student record was not found")
+	} else {
+		addEligibilityCheck(response, CheckStudentExists, serviceInterfaces.CheckPassed, "Student record found")
+		if student.IsArchived() {
+			addEligibilityCheck(response, CheckStudentActive, serviceInterfaces.CheckFailed,
+				"Student is archived; past registrations can be viewed but not changed")
+		} else if student.EnrollmentStatus != domain.EnrollmentStatusActive {
+			addEligibilityCheck(response, CheckStudentActive, serviceInterfaces.CheckFailed,
+				fmt.Sprintf("Student enrollment status is %q; only active students can register", student.EnrollmentStatus))
+		} else {
+			addEligibilityCheck(response, CheckStudentActive, serviceInterfaces.CheckPassed, "Student is in active status")
+		}
+		if err := s.checkHolds(ctx, response, studentID); err != nil {
+			return nil, err
+		}
+	}
+
+	section, err := s.GetSectionDetails(ctx, sectionID)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+	if section == nil {
+		addEligibilityCheck(response, CheckSectionExists, serviceInterfaces.CheckFailed, "Section was not found")
+	} else {
+		addEligibilityCheck(response, CheckSectionExists, serviceInterfaces.CheckPassed, "Section found")
+		s.checkSectionRules(response, section)
+		s.checkCalendarRules(ctx, response, section)
+	}
+
+	if student != nil && section != nil {
+		if err := s.checkStudentSectionRules(ctx, response, studentID, section); err != nil {
+			return nil, err
+		}
+	}
+
+	response.Eligible = true
+	for _, check := range response.Checks {
+		if check.Status == serviceInterfaces.CheckFailed {
+			response.Eligible = false
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (s *RegistrationService) checkHolds(ctx context.Context, response *EligibilityResponse, studentID uuid.UUID) error {
+	holds, err := s.studentHoldRepo.GetActiveByStudent(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("failed to check student holds: %w", err)
+	}
+	if len(holds) == 0 {
+		addEligibilityCheck(response, CheckNoHolds, serviceInterfaces.CheckPassed, "Student has no holds")
+		return nil
+	}
+
+	types := make([]string, len(holds))
+	for i, hold := range holds {
+		types[i] = hold.HoldType
+	}
+	addEligibilityCheck(response, CheckNoHolds, serviceInterfaces.CheckFailed,
+		fmt.Sprintf("Student has holds that block registration: %s", strings.Join(types, ", ")))
+	return nil
+}
+
+func (s *RegistrationService) checkSectionRules(response *EligibilityResponse, section *domain.Section) {
+	if !section.IsActive {
+		addEligibilityCheck(response, CheckSectionActive, serviceInterfaces.CheckFailed, "Section is not open for registration")
+	} else {
+		addEligibilityCheck(response, CheckSectionActive, serviceInterfaces.CheckPassed, "Section is active")
+	}
+
+	semester := section.Semester
+	if semester.SemesterID == uuid.Nil {
+		return
+	}
+
+	now := s.termNow()
+	opens := semester.RegistrationStart.In(now.Location())
+	closes := semester.RegistrationEnd.In(now.Location())
+	switch {
+	case now.Before(opens):
+		addEligibilityCheck(response, CheckRegistrationWindow, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("Registration for %s opens at %s", semester.SemesterName, opens.Format(time.RFC3339)))
+	case now.After(closes):
+		addEligibilityCheck(response, CheckRegistrationWindow, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("Registration for %s closed at %s", semester.SemesterName, closes.Format(time.RFC3339)))
+	default:
+		addEligibilityCheck(response, CheckRegistrationWindow, serviceInterfaces.CheckPassed,
+			fmt.Sprintf("Registration for %s is open until %s", semester.SemesterName, closes.Format(time.RFC3339)))
+	}
+}
+
+// checkCalendarRules applies the deadlines from the semester calendar. A calendar that
+// cannot be loaded is reported as a warning rather than blocking registration.
+func (s *RegistrationService) checkCalendarRules(ctx context.Context, response *EligibilityResponse, section *domain.Section) {
+	if s.semesterService == nil || section.SemesterID == uuid.Nil {
+		return
+	}
+
+	calendar, err := s.semesterService.GetCalendar(ctx, section.SemesterID)
+	if err != nil {
+		addEligibilityCheck(response, CheckAddDropDeadline, serviceInterfaces.CheckWarning,
+			fmt.Sprintf("Semester calendar is unavailable: %v", err))
+		return
+	}
+
+	deadline, ok := calendar.Deadline(domain.CalendarAddDropDeadline)
+	if !ok {
+		return
+	}
+	deadline = deadline.In(s.semesterService.Location())
+	if s.termNow().After(deadline) {
+		addEligibilityCheck(response, CheckAddDropDeadline, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("The add/drop deadline for %s passed at %s", calendar.Semester.SemesterName, deadline.Format(time.RFC3339)))
+	} else {
+		addEligibilityCheck(response, CheckAddDropDeadline, serviceInterfaces.CheckPassed,
+			fmt.Sprintf("Courses can be added until %s", deadline.Format(time.RFC3339)))
+	}
+}
+
+func (s *RegistrationService) checkStudentSectionRules(ctx context.Context, response *EligibilityResponse, studentID uuid.UUID, section *domain.Section) error {
+	existing, err := s.registrationRepo.GetByStudentAndSection(ctx, studentID, section.SectionID)
+	if err != nil {
+		return fmt.Errorf("failed to check existing registration: %w", err)
+	}
+	if existing != nil {
+		addEligibilityCheck(response, CheckNotRegistered, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("Already registered for this section with status: %s", existing.Status))
+	} else {
+		addEligibilityCheck(response, CheckNotRegistered, serviceInterfaces.CheckPassed, "Not registered for this section")
+	}
+
+	waitlistEntries, err := s.GetStudentWaitlistStatus(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("failed to check waitlist status: %w", err)
+	}
+	waitlisted := false
+	for _, entry := range waitlistEntries {
+		if entry.SectionID == section.SectionID {
+			addEligibilityCheck(response, CheckNotWaitlisted, serviceInterfaces.CheckFailed,
+				fmt.Sprintf("Already on the waitlist for this section at position %d", entry.Position))
+			waitlisted = true
+			break
+		}
+	}
+	if !waitlisted {
+		addEligibilityCheck(response, CheckNotWaitlisted, serviceInterfaces.CheckPassed, "Not on the waitlist for this section")
+	}
+
+	if err := s.checkRepeatPolicy(ctx, response, studentID, section); err != nil {
+		return err
+	}
+
+	if err := s.checkTimeConflicts(ctx, response, studentID, section); err != nil {
+		return err
+	}
+
+	if section.AvailableSeats > 0 {
+		addEligibilityCheck(response, CheckSeatAvailability, serviceInterfaces.CheckPassed,
+			fmt.Sprintf("%d of %d seats available", section.AvailableSeats, section.TotalSeats))
+	} else {
+		addEligibilityCheck(response, CheckSeatAvailability, serviceInterfaces.CheckWarning,
+			"Section is full; registering will add the student to the waitlist")
+	}
+
+	return nil
+}
+
+func (s *RegistrationService) checkRepeatPolicy(ctx context.Context, response *EligibilityResponse, studentID uuid.UUID, section *domain.Section) error {
+	if section.Course.AllowRepeat {
+		addEligibilityCheck(response, CheckRepeatPolicy, serviceInterfaces.CheckPassed, "Course may be taken more than once")
+		return nil
+	}
+	taken, err := s.takenCourseSection(ctx, studentID, section)
+	if err != nil {
+		return fmt.Errorf("failed to check repeat policy: %w", err)
+	}
+	if taken != nil {
+		addEligibilityCheck(response, CheckRepeatPolicy, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("Already enrolled in section %s of this course, which cannot be repeated", taken.SectionNumber))
+		return nil
+	}
+	addEligibilityCheck(response, CheckRepeatPolicy, serviceInterfaces.CheckPassed, "Course has not been taken before")
+	return nil
+}
+
+func (s *RegistrationService) checkTimeConflicts(ctx context.Context, response *EligibilityResponse, studentID uuid.UUID, section *domain.Section) error {
+	conflicts, err := s.scheduleConflicts(ctx, studentID, section)
+	if err != nil {
+		return fmt.Errorf("failed to check schedule conflicts: %w", err)
+	}
+	if len(conflicts) == 0 {
+		addEligibilityCheck(response, CheckNoTimeConflict, serviceInterfaces.CheckPassed, "Does not clash with enrolled sections")
+		return nil
+	}
+
+	override, err := s.scheduleOverrideRepo.GetOpen(ctx, studentID, section.SectionID)
+	if err != nil {
+		return fmt.Errorf("failed to check schedule overrides: %w", err)
+	}
+	switch {
+	case override != nil && override.Status == domain.ScheduleOverrideApproved:
+		addEligibilityCheck(response, CheckNoTimeConflict, serviceInterfaces.CheckPassed,
+			fmt.Sprintf("Meets at the same time as %s; an advisor approved a schedule override", describeSections(conflicts)))
+	case override != nil:
+		addEligibilityCheck(response, CheckNoTimeConflict, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("Meets at the same time as %s; the schedule override is waiting for an advisor", describeSections(conflicts)))
+	default:
+		addEligibilityCheck(response, CheckNoTimeConflict, serviceInterfaces.CheckFailed,
+			fmt.Sprintf("Meets at the same time as %s; request a schedule override to register anyway", describeSections(conflicts)))
+	}
+	return nil
+}
+
+func addEligibilityCheck(response *EligibilityResponse, name string, status serviceInterfaces.EligibilityCheckStatus, reason string) {
+	response.Checks = append(response.Checks, EligibilityCheck{
+		Name:   name,
+		Status: status,
+		Reason: reason,
+	})
+}
+
+// termNow is the current time in the term-local time zone, used for every window and
+// deadline comparison so messages show the dates students see on the calendar.
+func (s *RegistrationService) termNow() time.Time {
+	if s.semesterService == nil {
+		return time.Now().UTC()
+	}
+	return s.semesterService.Now()
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PersistenceModeState        = "state"
+	PersistenceModeEventSourced = "event_sourced"
+)
+
+var ErrEventStoreDisabled = errors.New("event-sourced persistence is not enabled")
+
+// RegistrationEventStore records registration state changes as events and rebuilds section
+// rosters from them, starting at the latest snapshot when one is available.
+type RegistrationEventStore struct {
+	eventRepo        interfaces.RegistrationEventRepository
+	snapshotInterval int
+}
+
+func NewRegistrationEventStore(eventRepo interfaces.RegistrationEventRepository, snapshotInterval int) *RegistrationEventStore {
+	return &RegistrationEventStore{
+		eventRepo:        eventRepo,
+		snapshotInterval: snapshotInterval,
+	}
+}
+
+func (e *RegistrationEventStore) Record(ctx context.Context, eventType domain.RegistrationEventType, studentID, sectionID uuid.UUID, position *int, occurredAt time.Time) error {
+	event := &domain.RegistrationEvent{
+		EventID:    uuid.New(),
+		EventType:  eventType,
+		StudentID:  studentID,
+		SectionID:  sectionID,
+		Position:   position,
+		OccurredAt: occurredAt,
+	}
+
+	if err := e.eventRepo.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append %s event: %w", eventType, err)
+	}
+
+	if err := e.snapshotIfDue(ctx, sectionID); err != nil {
+		logger.Warn("Failed to snapshot roster for section %s: %v", sectionID, err)
+	}
+
+	return nil
+}
+
+// RosterAt rebuilds the roster of a section as it was at the given time
+func (e *RegistrationEventStore) RosterAt(ctx context.Context, sectionID uuid.UUID, asOf time.Time) (*domain.SectionRoster, error) {
+	roster := domain.NewSectionRoster(sectionID)
+
+	snapshot, err := e.eventRepo.GetLatestSnapshot(ctx, sectionID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roster snapshot: %w", err)
+	}
+	if snapshot != nil {
+		if err := json.Unmarshal([]byte(snapshot.State), roster); err != nil {
+			return nil, fmt.Errorf("failed to decode roster snapshot %s: %w", snapshot.SnapshotID, err)
+		}
+	}
+
+	events, err := e.eventRepo.GetBySection(ctx, sectionID, roster.Sequence, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration events: %w", err)
+	}
+
+	for _, event := range events {
+		roster.Apply(event)
+	}
+
+	return roster, nil
+}
+
+func (e *RegistrationEventStore) GetSectionEvents(ctx context.Context, sectionID uuid.UUID, afterSequence int64) ([]*domain.RegistrationEvent, error) {
+	events, err := e.eventRepo.GetBySection(ctx, sectionID, afterSequence, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration events: %w", err)
+	}
+	return events, nil
+}
+
+func (e *RegistrationEventStore) snapshotIfDue(ctx context.Context, sectionID uuid.UUID) error {
+	if e.snapshotInterval <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	latest, err := e.eventRepo.GetLatestSnapshot(ctx, sectionID, now)
+	if err != nil {
+		return err
+	}
+
+	var afterSequence int64
+	if latest != nil {
+		afterSequence = latest.Sequence
+	}
+
+	pending, err := e.eventRepo.CountSince(ctx, sectionID, afterSequence)
+	if err != nil {
+		return err
+	}
+	if pending < int64(e.snapshotInterval) {
+		return nil
+	}
+
+	roster, err := e.RosterAt(ctx, sectionID, now)
+	if err != nil {
+		return err
+	}
+
+	state, err := json.Marshal(roster)
+	if err != nil {
+		return fmt.Errorf("failed to encode roster: %w", err)
+	}
+
+	snapshot := &domain.RegistrationSnapshot{
+		SnapshotID: uuid.New(),
+		SectionID:  sectionID,
+		Sequence:   roster.Sequence,
+		AsOf:       roster.AsOf,
+		State:      string(state),
+	}
+	if err := e.eventRepo.SaveSnapshot(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to save roster snapshot: %w", err)
+	}
+
+	logger.Info("Saved roster snapshot for section %s at sequence %d", sectionID, roster.Sequence)
+	return nil
+}
+
+// recordEvent appends a registration event when the event-sourced mode is enabled, and the
+// status transition it stands for to the student's history in every mode. The history is
+// informational, so failing to write it does not fail the change.
+func (s *RegistrationService) recordEvent(ctx context.Context, eventType domain.RegistrationEventType, studentID, sectionID uuid.UUID, position *int, occurredAt time.Time) error {
+	if s.eventStore != nil {
+		if err := s.eventStore.Record(ctx, eventType, studentID, sectionID, position, occurredAt); err != nil {
+			return err
+		}
+	}
+
+	from, to := eventType.Transition()
+	transition := &domain.RegistrationTransition{
+		TransitionID: uuid.New(),
+		StudentID:    studentID,
+		SectionID:    sectionID,
+		EventType:    eventType,
+		FromStatus:   from,
+		ToStatus:     to,
+		Position:     position,
+		OccurredAt:   occurredAt,
+	}
+	if err := s.transitionRepo.Append(ctx, transition); err != nil {
+		registrationLog(ctx, studentID, sectionID).Warn("Failed to record %s transition of student %s in section %s: %v", eventType, studentID, sectionID, err)
+	}
+	s.invalidateSectionRoster(ctx, sectionID)
+	s.invalidateStudentSchedule(ctx, studentID, sectionID)
+	s.emitTransition(eventType, studentID, sectionID, position, occurredAt)
+	return nil
+}
+
+func (s *RegistrationService) GetSectionRosterAt(ctx context.Context, sectionID uuid.UUID, asOf time.Time) (*domain.SectionRoster, error) {
+	if s.eventStore == nil {
+		return nil, ErrEventStoreDisabled
+	}
+	return s.eventStore.RosterAt(ctx, sectionID, asOf)
+}
+
+func (s *RegistrationService) GetSectionEvents(ctx context.Context, sectionID uuid.UUID, afterSequence int64) ([]*domain.RegistrationEvent, error) {
+	if s.eventStore == nil {
+		return nil, ErrEventStoreDisabled
+	}
+	return s.eventStore.GetSectionEvents(ctx, sectionID, afterSequence)
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// databaseFallback lets registrations go on at a lower rate while Redis is unreachable.
+// Seats are taken from the section rows, which the seat counters no longer know about, so
+// the seats taken per section are kept until the counters can be brought down by as many.
+type databaseFallback struct {
+	breaker *CircuitBreaker
+
+	mu    sync.Mutex
+	taken map[uuid.UUID]int
+}
+
+// EnableDatabaseFallback takes seats straight from Postgres once failureThreshold seat
+// counter calls in a row fail, trying the counter again every openFor. Without it a Redis
+// outage fails every registration.
+func (s *RegistrationService) EnableDatabaseFallback(failureThreshold int, openFor time.Duration) {
+	s.fallback = &databaseFallback{
+		breaker: NewCircuitBreaker(failureThreshold, openFor, func(state string) {
+			if state == CircuitOpen {
+				metrics.SeatCounterCircuitOpen.Set(1)
+				logger.Error("Seat counter unavailable, registrations are taking seats from the database")
+				return
+			}
+			metrics.SeatCounterCircuitOpen.Set(0)
+			logger.Info("Seat counter is back, registrations are using it again")
+		}),
+		taken: make(map[uuid.UUID]int),
+	}
+}
+
+// cacheOutage reports whether a failed Redis call should be put up with because the
+// database fallback is on, counting the failure against the seat counter
+func (s *RegistrationService) cacheOutage(ctx context.Context, what string, err error) bool {
+	if s.fallback == nil {
+		return false
+	}
+	s.fallback.breaker.Failure()
+	logger.WarnContext(ctx, "Failed to %s, going on without Redis: %v", what, err)
+	return true
+}
+
+// skipCache reports whether Redis is known to be down, so calls that can be done without
+// it are not made
+func (s *RegistrationService) skipCache() bool {
+	return s.fallback != nil && s.fallback.breaker.State() == CircuitOpen
+}
+
+// useSeatCounter reports whether a seat should come from the counter. When the breaker
+// lets a probe through, the seats taken from the database meanwhile are first taken off
+// the counters, so the probe does not sell them again.
+func (s *RegistrationService) useSeatCounter(ctx context.Context) bool {
+	if s.fallback == nil {
+		return true
+	}
+	allowed, probe := s.fallback.breaker.Allow()
+	if !allowed {
+		return false
+	}
+	if probe {
+		if err := s.settleFallbackSeats(ctx); err != nil {
+			logger.WarnContext(ctx, "Seat counter still unavailable: %v", err)
+			s.fallback.breaker.Failure()
+			return false
+		}
+	}
+	return true
+}
+
+// observeSeatCounter feeds the outcome of a seat counter call to the breaker. Running out
+// of seats is an answer, so only transient errors count as failures.
+func (s *RegistrationService) observeSeatCounter(err error) {
+	if s.fallback == nil {
+		return
+	}
+	if errors.Is(err, interfaces.ErrCacheTransient) {
+		s.fallback.breaker.Failure()
+		return
+	}
+	s.fallback.breaker.Success()
+}
+
+// reserveSeatFromDatabase takes a seat by locking the section row. Students get the
+// section's general seats only: its reserved seats and whatever its seat pools still hold
+// are kept back, since the pool counters are in Redis too. Seats held for the waitlist are
+// checked against the waitlist rows.
+func (s *RegistrationService) reserveSeatFromDatabase(ctx context.Context, studentID, sectionID uuid.UUID, seat interfaces.SeatRequest) (*interfaces.SeatCounter, error) {
+	log := registrationLog(ctx, studentID, sectionID)
+	if seat.HoldForWaitlist {
+		next, err := s.waitlistRepo.GetNextPosition(ctx, sectionID)
+		if err != nil {
+			metrics.FallbackRegistrations.WithLabelValues(metrics.FallbackFailed).Inc()
+			return nil, fmt.Errorf("failed to get waitlist size: %w", err)
+		}
+		if next > 1 {
+			return nil, interfaces.ErrSeatsHeldForWaitlist
+		}
+	}
+
+	held := seat.Reserved
+	if seat.HasPools {
+		pooled, err := s.pooledSeatsLeft(ctx, sectionID)
+		if err != nil {
+			metrics.FallbackRegistrations.WithLabelValues(metrics.FallbackFailed).Inc()
+			return nil, err
+		}
+		held += pooled
+	}
+
+	now := time.Now()
+	registration := &domain.Registration{
+		RegistrationID:   uuid.New(),
+		StudentID:        studentID,
+		SectionID:        sectionID,
+		Status:           domain.StatusEnrolled,
+		RegistrationDate: now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Version:          1,
+	}
+	section, err := s.registrationRepo.EnrollLocked(ctx, registration, held)
+	switch {
+	case errors.Is(err, interfaces.ErrNoSeatsLeft):
+		metrics.FallbackRegistrations.WithLabelValues(metrics.FallbackFull).Inc()
+		return nil, err
+	case err != nil:
+		metrics.FallbackRegistrations.WithLabelValues(metrics.FallbackFailed).Inc()
+		return nil, fmt.Errorf("failed to enroll from database: %w", err)
+	}
+	metrics.FallbackRegistrations.WithLabelValues(metrics.FallbackEnrolled).Inc()
+
+	s.fallback.mu.Lock()
+	s.fallback.taken[sectionID]++
+	s.fallback.mu.Unlock()
+
+	if err := s.recordEvent(ctx, domain.EventRegistered, studentID, sectionID, nil, now); err != nil {
+		log.Warn("Failed to record registration event of student %s in section %s: %v", studentID, sectionID, err)
+	}
+	log.Info("Enrolled student %s in section %s from the database, %d seats left", studentID, sectionID, section.AvailableSeats)
+	// The counter's version is left unset, since the section row has its own
+	return &interfaces.SeatCounter{
+		Available: section.AvailableSeats,
+		Total:     section.TotalSeats,
+		Reserved:  seat.Reserved,
+	}, nil
+}
+
+// pooledSeatsLeft counts the seats the section's pools still hold
+func (s *RegistrationService) pooledSeatsLeft(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return 0, err
+	}
+	if section == nil {
+		return 0, ErrSectionNotFound
+	}
+	taken, err := s.registrationRepo.CountEnrolledBySeatPool(ctx, sectionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count seat pool enrollments: %w", err)
+	}
+
+	pooled := 0
+	for _, pool := range section.SeatPools {
+		pooled += max(pool.Seats-taken[pool.PoolID], 0)
+	}
+	return pooled, nil
+}
+
+// settleFallbackSeats takes the seats enrolled from the database off the seat counters.
+// A counter that is not cached is left alone; it is loaded from the section row, which
+// has them already. The counts are copied out so registrations are not held up while Redis
+// answers; only the breaker's single probe settles, and seats enrolled meanwhile are kept
+// for the next one.
+func (s *RegistrationService) settleFallbackSeats(ctx context.Context) error {
+	s.fallback.mu.Lock()
+	pending := maps.Clone(s.fallback.taken)
+	s.fallback.mu.Unlock()
+
+	for sectionID, taken := range pending {
+		if err := s.settleSeatCounter(ctx, sectionID, taken); err != nil {
+			return err
+		}
+
+		s.fallback.mu.Lock()
+		s.fallback.taken[sectionID] -= taken
+		if s.fallback.taken[sectionID] <= 0 {
+			delete(s.fallback.taken, sectionID)
+		}
+		s.fallback.mu.Unlock()
+		logger.InfoContext(ctx, "Took %d seats enrolled from the database off the counter of section %s", taken, sectionID)
+	}
+	return nil
+}
+
+// settleSeatCounter lowers a section's seat counter by taken. Other instances may be using
+// the counter already, so a counter that moved between the read and the write is read again.
+func (s *RegistrationService) settleSeatCounter(ctx context.Context, sectionID uuid.UUID, taken int) error {
+	for attempt := 1; ; attempt++ {
+		seats, err := s.cacheService.GetAvailableSeats(ctx, sectionID)
+		if err != nil {
+			if seatCounterMissing(err) {
+				return nil
+			}
+			return err
+		}
+
+		swapped, err := s.cacheService.CompareAndSetAvailableSeats(ctx, sectionID, seats, max(seats-taken, 0))
+		if err != nil {
+			return err
+		}
+		if swapped {
+			return nil
+		}
+		if attempt >= seatSyncConflictAttempts {
+			return fmt.Errorf("seat counter of section %s kept moving while it was settled", sectionID)
+		}
+	}
+}
+
+// seatCounterMissing reports whether GetAvailableSeats failed because the counter is not
+// cached rather than because Redis did not answer
+func seatCounterMissing(err error) bool {
+	return err.Error() == "section seats not cached"
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSectionInactive    = errors.New("section is not open for registration")
+	ErrRegistrationClosed = errors.New("registration is not open for the section's semester")
+	ErrWaitlistFrozen     = errors.New("section is full and its waitlist is frozen")
+	ErrWaitlistFull       = interfaces.ErrWaitlistFull
+	ErrNoWaitlist         = errors.New("section is full and has no waitlist")
+	ErrConsentRequired    = errors.New("section requires instructor consent")
+	ErrRepeatNotAllowed   = errors.New("course has already been taken and does not allow repeats")
+)
+
+// Registration result statuses for sections that cannot take registrations
+const (
+	ResultSectionNotFound    = "section_not_found"
+	ResultSectionInactive    = "section_inactive"
+	ResultRegistrationClosed = "registration_closed"
+	ResultWaitlistFrozen     = "waitlist_frozen"
+	ResultWaitlistFull       = "waitlist_full"
+	ResultSectionFull        = "section_full"
+	ResultConsentRequired    = "consent_required"
+	ResultRepeatNotAllowed   = "repeat_not_allowed"
+)
+
+// checkSectionOpen rejects sections that are inactive, belong to a course no longer offered
+// or whose semester is not open for registration right now. It runs before a seat is taken, so a rejected request leaves the
+// seat counter alone.
+func (s *RegistrationService) checkSectionOpen(ctx context.Context, sectionID uuid.UUID) error {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	if section == nil {
+		return ErrSectionNotFound
+	}
+	if !section.IsActive || !section.Course.Active {
+		return ErrSectionInactive
+	}
+
+	semester := section.Semester
+	if semester.SemesterID == uuid.Nil {
+		return fmt.Errorf("%w: section has no semester", ErrRegistrationClosed)
+	}
+	if !semester.IsActive {
+		return fmt.Errorf("%w: %s is not an active semester", ErrRegistrationClosed, semester.SemesterName)
+	}
+
+	now := s.termNow()
+	if opens := semester.RegistrationStart.In(now.Location()); now.Before(opens) {
+		return fmt.Errorf("%w: registration for %s opens at %s", ErrRegistrationClosed, semester.SemesterName, opens.Format(time.RFC3339))
+	}
+	if closes := semester.RegistrationEnd.In(now.Location()); now.After(closes) {
+		return fmt.Errorf("%w: registration for %s closed at %s", ErrRegistrationClosed, semester.SemesterName, closes.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// getSectionMetadata returns the section with its course and semester, from the section
+// details cache when possible. Seat counts in the result may be stale; the seat counter is
+// authoritative for those. Deactivating a section drops its cached details.
+func (s *RegistrationService) getSectionMetadata(ctx context.Context, sectionID uuid.UUID) (*domain.Section, error) {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	if section, ok := readCachedView[*domain.Section](ctx, s.cacheService.GetSectionDetails, "section details", sectionID); ok {
+		return section, nil
+	}
+
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, nil
+	}
+
+	if err := s.cacheService.SetSectionDetails(ctx, sectionID, section, SectionDetailsTTL); err != nil {
+		log.Warn("Failed to cache section details for %s: %v", sectionID, err)
+	}
+	return section, nil
+}
+
+// checkWaitlistOpen rejects waitlist adds to sections without a waitlist or whose waitlist
+// an administrator froze
+func (s *RegistrationService) checkWaitlistOpen(ctx context.Context, sectionID uuid.UUID) error {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	if section != nil && !section.Policy.AllowWaitlist {
+		return ErrNoWaitlist
+	}
+	if section != nil && section.WaitlistFrozen {
+		return ErrWaitlistFrozen
+	}
+	return nil
+}
+
+// checkInstructorConsent rejects registrations for sections that need the instructor's
+// consent. It runs before a seat is taken.
+func (s *RegistrationService) checkInstructorConsent(ctx context.Context, sectionID uuid.UUID) error {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	if section != nil && section.Policy.InstructorConsentRequired {
+		return ErrConsentRequired
+	}
+	return nil
+}
+
+// checkRepeatEnrollment rejects registrations for a course the student is enrolled in, in
+// this semester or an earlier one where the enrollment stands as completed, unless the
+// course allows repeats. Dropped, withdrawn and failed courses may be taken again.
+func (s *RegistrationService) checkRepeatEnrollment(ctx context.Context, studentID, sectionID uuid.UUID) error {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	if section == nil || section.Course.AllowRepeat {
+		return nil
+	}
+	taken, err := s.takenCourseSection(ctx, studentID, section)
+	if err != nil {
+		return err
+	}
+	if taken != nil {
+		return fmt.Errorf("%w: enrolled in section %s", ErrRepeatNotAllowed, taken.SectionNumber)
+	}
+	return nil
+}
+
+// takenCourseSection returns another section of the section's course the student is
+// enrolled in, or nil. Enrollments the database has not caught up with are found in the
+// student's cached registrations.
+func (s *RegistrationService) takenCourseSection(ctx context.Context, studentID uuid.UUID, section *domain.Section) (*domain.Section, error) {
+	registrations, err := s.registrationRepo.GetByStudentAndCourse(ctx, studentID, section.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registrations for course: %w", err)
+	}
+	for _, registration := range registrations {
+		if registration.Status == domain.StatusEnrolled && registration.SectionID != section.SectionID {
+			return &registration.Section, nil
+		}
+	}
+
+	cached, err := s.GetStudentRegistrations(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, registration := range cached {
+		if registration.Status != domain.StatusEnrolled || registration.SectionID == section.SectionID {
+			continue
+		}
+		other, err := s.getSectionMetadata(ctx, registration.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		if other != nil && other.CourseID == section.CourseID {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// waitlistMaxSize returns the cap on the section's waitlist, 0 when it has none. The
+// section's own cap wins over the configured default.
+func (s *RegistrationService) waitlistMaxSize(ctx context.Context, sectionID uuid.UUID) int {
+	if section, err := s.getSectionMetadata(ctx, sectionID); err == nil && section != nil && section.Policy.MaxWaitlist != nil {
+		return *section.Policy.MaxWaitlist
+	}
+	return s.defaultWaitlistMaxSize
+}
+
+// closedSectionResult turns a checkSectionOpen error into the registration result
+func closedSectionResult(sectionID uuid.UUID, err error) RegistrationResult {
+	switch {
+	case errors.Is(err, ErrSectionNotFound):
+		return RegistrationResult{SectionID: sectionID, Status: ResultSectionNotFound, Message: "Section not found"}
+	case errors.Is(err, ErrSectionInactive):
+		return RegistrationResult{SectionID: sectionID, Status: ResultSectionInactive, Message: "Section is not open for registration"}
+	case errors.Is(err, ErrRegistrationClosed):
+		return RegistrationResult{SectionID: sectionID, Status: ResultRegistrationClosed, Message: err.Error()}
+	case errors.Is(err, ErrWaitlistFrozen):
+		return RegistrationResult{SectionID: sectionID, Status: ResultWaitlistFrozen, Message: "Section is full and its waitlist is not taking new students"}
+	case errors.Is(err, ErrNoWaitlist):
+		return RegistrationResult{SectionID: sectionID, Status: ResultSectionFull, Message: "Section is full and does not keep a waitlist"}
+	case errors.Is(err, ErrConsentRequired):
+		return RegistrationResult{SectionID: sectionID, Status: ResultConsentRequired, Message: "Section requires the instructor's consent to register"}
+	case errors.Is(err, ErrWaitlistFull):
+		return RegistrationResult{SectionID: sectionID, Status: ResultWaitlistFull, Message: "Section is full and so is its waitlist"}
+	case errors.Is(err, ErrRepeatNotAllowed):
+		return RegistrationResult{SectionID: sectionID, Status: ResultRepeatNotAllowed, Message: "Course has already been taken and cannot be repeated"}
+	default:
+		logger.Error("Failed to check section %s: %v", sectionID, err)
+		return RegistrationResult{SectionID: sectionID, Status: "failed", Message: "Failed to process registration"}
+	}
+}
+
+// auditRegistration records a registration that took a seat or a waitlist place
+func (s *RegistrationService) auditRegistration(ctx context.Context, studentID uuid.UUID, result RegistrationResult) {
+	change := AuditChange{StudentID: studentID, SectionID: result.SectionID}
+	switch result.Status {
+	case string(domain.StatusEnrolled):
+		change.Action = domain.AuditRegistered
+		change.After = map[string]any{"status": domain.StatusEnrolled}
+	case string(domain.StatusWaitlisted):
+		change.Action = domain.AuditWaitlistAdded
+		change.After = map[string]any{"status": domain.StatusWaitlisted}
+		if result.Position != nil {
+			change.After["waitlist_position"] = *result.Position
+		}
+	default:
+		return
+	}
+	s.auditService.Record(ctx, change)
+}
+
+// registrationLog returns the logger for work on a student's registration in a section,
+// tagged with the request ID and whichever of the two IDs are set
+func registrationLog(ctx context.Context, studentID, sectionID uuid.UUID) *logger.Logger {
+	log := logger.FromContext(ctx)
+	if studentID != uuid.Nil {
+		log = log.With("student_id", studentID.String())
+	}
+	if sectionID != uuid.Nil {
+		log = log.With("section_id", sectionID.String())
+	}
+	return log
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SectionTimeline is the history of a student in one section: the status transitions in the
+// order they happened, and the status the last of them left the student in
+type SectionTimeline struct {
+	SectionID     uuid.UUID                        `json:"section_id"`
+	CourseCode    string                           `json:"course_code,omitempty"`
+	SectionNumber string                           `json:"section_number,omitempty"`
+	Status        domain.RegistrationStatus        `json:"status,omitempty"`
+	Transitions   []*domain.RegistrationTransition `json:"transitions"`
+}
+
+// GetRegistrationHistory returns a timeline for every section the student has registered or
+// waitlisted for, in the order the student first joined them
+func (s *RegistrationService) GetRegistrationHistory(ctx context.Context, studentID uuid.UUID) ([]*SectionTimeline, error) {
+	transitions, err := s.transitionRepo.ListByStudent(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration history: %w", err)
+	}
+
+	timelines := make([]*SectionTimeline, 0)
+	bySection := make(map[uuid.UUID]*SectionTimeline)
+	for _, transition := range transitions {
+		timeline, ok := bySection[transition.SectionID]
+		if !ok {
+			timeline = &SectionTimeline{SectionID: transition.SectionID}
+			bySection[transition.SectionID] = timeline
+			timelines = append(timelines, timeline)
+		}
+		timeline.Transitions = append(timeline.Transitions, transition)
+		timeline.Status = transition.ToStatus
+	}
+
+	for _, timeline := range timelines {
+		section, err := s.getSectionMetadata(ctx, timeline.SectionID)
+		if err != nil || section == nil {
+			// Sections can be deleted; their history is still the student's
+			continue
+		}
+		timeline.CourseCode = section.Course.CourseCode
+		timeline.SectionNumber = section.SectionNumber
+	}
+	return timelines, nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const seatHoldSweepBatchSize = 100
+
+var (
+	ErrSeatHoldsDisabled = errors.New("seat holds are disabled")
+	ErrSeatHoldNotFound  = errors.New("seat hold not found")
+	ErrSeatHoldNotHeld   = errors.New("seat hold is no longer held")
+	ErrSeatHoldExpired   = errors.New("seat hold has expired")
+	ErrNoSeatsAvailable  = errors.New("no seats available in section")
+	ErrAlreadyRegistered = errors.New("student is already registered for section")
+)
+
+// HoldSeat takes a seat in a section for the student and keeps it for the configured hold
+// time. The seat is only turned into a registration by ConfirmSeatHold; otherwise the sweep
+// gives it back. Holding again while a hold is active returns the existing hold.
+func (s *RegistrationService) HoldSeat(ctx context.Context, studentID, sectionID uuid.UUID) (*domain.SeatHold, error) {
+	log := registrationLog(ctx, studentID, sectionID)
+	if s.seatHoldTTL <= 0 {
+		return nil, ErrSeatHoldsDisabled
+	}
+
+	unlock, err := s.lockStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := s.checkStudentCanRegister(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.registrationRepo.GetByStudentAndSection(ctx, studentID, sectionID)
+	if err == nil && existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyRegistered, existing.Status)
+	}
+
+	if err := s.checkSectionOpen(ctx, sectionID); err != nil {
+		return nil, err
+	}
+
+	active, err := s.seatHoldRepo.GetActive(ctx, studentID, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active seat hold: %w", err)
+	}
+	if active != nil && !active.IsExpired() {
+		return active, nil
+	}
+
+	if err := s.ensureSeatCacheInitialized(ctx, sectionID); err != nil {
+		return nil, err
+	}
+
+	newSeatCount, err := s.cacheService.DecrementAndGetAvailableSeats(ctx, sectionID)
+	if err != nil {
+		if available, getErr := s.cacheService.GetAvailableSeats(ctx, sectionID); getErr == nil && available <= 0 {
+			return nil, ErrNoSeatsAvailable
+		}
+		return nil, fmt.Errorf("failed to reserve seat: %w", err)
+	}
+
+	now := time.Now()
+	hold := &domain.SeatHold{
+		HoldID:    uuid.New(),
+		StudentID: studentID,
+		SectionID: sectionID,
+		Status:    domain.HoldStatusHeld,
+		ExpiresAt: now.Add(s.seatHoldTTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.seatHoldRepo.Create(ctx, hold); err != nil {
+		if rollbackErr := s.cacheService.IncrementAvailableSeats(ctx, sectionID); rollbackErr != nil {
+			log.Error("Failed to rollback cache after seat hold failure: %v", rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to create seat hold: %w", err)
+	}
+
+	s.updateAvailableSectionsCacheForSection(ctx, sectionID, newSeatCount)
+
+	log.Info("Held seat in section %s for student %s until %s", sectionID, studentID, hold.ExpiresAt.Format(time.RFC3339))
+	return hold, nil
+}
+
+// ConfirmSeatHold turns an active hold into a registration using the seat it already holds
+func (s *RegistrationService) ConfirmSeatHold(ctx context.Context, holdID, studentID uuid.UUID) (*domain.SeatHold, error) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	unlock, err := s.lockStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := s.checkStudentNotArchived(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	hold, err := s.seatHoldRepo.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat hold: %w", err)
+	}
+	// Holds belonging to other students are reported as missing
+	if hold == nil || hold.StudentID != studentID {
+		return nil, ErrSeatHoldNotFound
+	}
+
+	if hold.Status == domain.HoldStatusHeld && hold.IsExpired() {
+		s.expireSeatHold(ctx, hold)
+		return nil, ErrSeatHoldExpired
+	}
+
+	confirmed, err := s.seatHoldRepo.TransitionStatus(ctx, holdID, domain.HoldStatusHeld, domain.HoldStatusConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm seat hold: %w", err)
+	}
+	if !confirmed {
+		if hold.Status == domain.HoldStatusExpired || hold.IsExpired() {
+			return nil, ErrSeatHoldExpired
+		}
+		return nil, ErrSeatHoldNotHeld
+	}
+
+	newSeatCount, err := s.cacheService.GetAvailableSeats(ctx, hold.SectionID)
+	if err != nil {
+		log.Warn("Failed to read seat count for section %s: %v", hold.SectionID, err)
+	}
+
+	if err := s.enrollReservedSeat(ctx, studentID, hold.SectionID, newSeatCount); err != nil {
+		log.Error("Failed to enqueue registration for seat hold %s, releasing seat: %v", holdID, err)
+		s.releaseHeldSeat(ctx, hold)
+		return nil, fmt.Errorf("failed to confirm seat hold: %w", err)
+	}
+
+	hold.Status = domain.HoldStatusConfirmed
+	hold.UpdatedAt = time.Now()
+
+	log.Info("Student %s confirmed seat hold %s for section %s", studentID, holdID, hold.SectionID)
+	return hold, nil
+}
+
+// ExpireSeatHolds returns the seats of holds that were not confirmed in time and promotes
+// waitlisted students into them. It is run periodically by the scheduler.
+func (s *RegistrationService) ExpireSeatHolds(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	holdIDs, err := s.seatHoldRepo.PopExpired(ctx, time.Now(), seatHoldSweepBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expired seat holds: %w", err)
+	}
+
+	expired := 0
+	for _, holdID := range holdIDs {
+		hold, err := s.seatHoldRepo.GetByID(ctx, holdID)
+		if err != nil {
+			log.Warn("Failed to load expired seat hold %s: %v", holdID, err)
+			continue
+		}
+		if hold == nil {
+			continue
+		}
+
+		if s.expireSeatHold(ctx, hold) {
+			expired++
+		}
+	}
+
+	if expired > 0 {
+		log.Info("Expired %d seat holds", expired)
+	}
+
+	return nil
+}
+
+func (s *RegistrationService) expireSeatHold(ctx context.Context, hold *domain.SeatHold) bool {
+	log := registrationLog(ctx, hold.StudentID, hold.SectionID)
+	expired, err := s.seatHoldRepo.TransitionStatus(ctx, hold.HoldID, domain.HoldStatusHeld, domain.HoldStatusExpired)
+	if err != nil {
+		log.Error("Failed to expire seat hold %s: %v", hold.HoldID, err)
+		return false
+	}
+	if !expired {
+		return false
+	}
+
+	log.Info("Seat hold %s for student %s in section %s expired", hold.HoldID, hold.StudentID, hold.SectionID)
+	s.releaseHeldSeat(ctx, hold)
+	return true
+}
+
+// releaseHeldSeat returns a held seat to the section and promotes the next waitlisted student
+func (s *RegistrationService) releaseHeldSeat(ctx context.Context, hold *domain.SeatHold) {
+	log := registrationLog(ctx, hold.StudentID, hold.SectionID)
+	newSeatCount, err := s.cacheService.IncrementAndGetAvailableSeats(ctx, hold.SectionID)
+	if err != nil {
+		log.Error("Failed to release held seat for section %s: %v", hold.SectionID, err)
+		return
+	}
+
+	s.updateAvailableSectionsCacheForSection(ctx, hold.SectionID, newSeatCount)
+
+	if err := s.enqueuePromotion(ctx, hold.SectionID, "hold:"+hold.HoldID.String()); err != nil {
+		log.Error("Failed to enqueue waitlist processing after releasing hold %s: %v", hold.HoldID, err)
+	}
+}
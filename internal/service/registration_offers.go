@@ -0,0 +1,203 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const seatOfferSweepBatchSize = 100
+
+var (
+	ErrSeatOfferNotFound   = errors.New("seat offer not found")
+	ErrSeatOfferNotPending = errors.New("seat offer is no longer pending")
+	ErrSeatOfferExpired    = errors.New("seat offer has expired")
+)
+
+// offerSeatIfEnabled creates a pending seat offer for a promoted waitlist entry and returns it,
+// or nil when offers are disabled. The caller has taken the seat from pool, and gives it back
+// if the offer cannot be stored. seatFreedAt, when known, times the promotion.
+func (s *RegistrationService) offerSeatIfEnabled(ctx context.Context, sectionID uuid.UUID, entry *domain.WaitlistEntry, pool *uuid.UUID, seatFreedAt time.Time) (*domain.SeatOffer, error) {
+	log := registrationLog(ctx, entry.StudentID, sectionID)
+	// A linked entry was given its linked section's seat, which an offer would hold idle
+	if s.seatOfferTTL <= 0 || entry.LinkedSectionID != nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	offer := &domain.SeatOffer{
+		OfferID:    uuid.New(),
+		StudentID:  entry.StudentID,
+		SectionID:  sectionID,
+		WaitlistID: entry.WaitlistID,
+		SeatPoolID: pool,
+		Status:     domain.OfferStatusPending,
+		ExpiresAt:  now.Add(s.seatOfferTTL),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.seatOfferRepo.Create(ctx, offer); err != nil {
+		log.Error("Failed to create seat offer for student %s in section %s: %v", entry.StudentID, sectionID, err)
+		return nil, fmt.Errorf("failed to create seat offer: %w", err)
+	}
+
+	if !seatFreedAt.IsZero() {
+		metrics.WaitlistPromotionLatency.WithLabelValues(metrics.PromotionOffered).Observe(now.Sub(seatFreedAt).Seconds())
+	}
+
+	log.Info("Offered seat in section %s to student %s until %s", sectionID, entry.StudentID, offer.ExpiresAt.Format(time.RFC3339))
+	return offer, nil
+}
+
+func (s *RegistrationService) GetStudentSeatOffers(ctx context.Context, studentID uuid.UUID) ([]*domain.SeatOffer, error) {
+	offers, err := s.seatOfferRepo.GetByStudentID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat offers: %w", err)
+	}
+	return offers, nil
+}
+
+func (s *RegistrationService) AcceptSeatOffer(ctx context.Context, offerID, studentID uuid.UUID) (*domain.SeatOffer, error) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	if err := s.checkStudentNotArchived(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	offer, err := s.getStudentSeatOffer(ctx, offerID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.Status == domain.OfferStatusPending && offer.IsExpired() {
+		s.expireSeatOffer(ctx, offer)
+		return nil, ErrSeatOfferExpired
+	}
+
+	accepted, err := s.seatOfferRepo.TransitionStatus(ctx, offerID, domain.OfferStatusPending, domain.OfferStatusAccepted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept seat offer: %w", err)
+	}
+	if !accepted {
+		return nil, ErrSeatOfferNotPending
+	}
+
+	// The time the student took to accept is theirs, not the promotion's
+	s.enrollPromotedStudent(ctx, offer.StudentID, offer.SectionID, offer.SeatPoolID, time.Time{})
+
+	offer.Status = domain.OfferStatusAccepted
+	offer.UpdatedAt = time.Now()
+
+	log.Info("Student %s accepted seat offer %s for section %s", studentID, offerID, offer.SectionID)
+	return offer, nil
+}
+
+func (s *RegistrationService) DeclineSeatOffer(ctx context.Context, offerID, studentID uuid.UUID) (*domain.SeatOffer, error) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	offer, err := s.getStudentSeatOffer(ctx, offerID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	declined, err := s.seatOfferRepo.TransitionStatus(ctx, offerID, domain.OfferStatusPending, domain.OfferStatusDeclined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decline seat offer: %w", err)
+	}
+	if !declined {
+		return nil, ErrSeatOfferNotPending
+	}
+
+	s.releaseOfferedSeat(ctx, offer)
+
+	offer.Status = domain.OfferStatusDeclined
+	offer.UpdatedAt = time.Now()
+
+	log.Info("Student %s declined seat offer %s for section %s", studentID, offerID, offer.SectionID)
+	return offer, nil
+}
+
+// ExpireSeatOffers releases the seats of pending offers past their deadline so the next
+// waitlisted student gets promoted. It is run periodically by the scheduler.
+func (s *RegistrationService) ExpireSeatOffers(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	offerIDs, err := s.seatOfferRepo.PopExpired(ctx, time.Now(), seatOfferSweepBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expired seat offers: %w", err)
+	}
+
+	expired := 0
+	for _, offerID := range offerIDs {
+		offer, err := s.seatOfferRepo.GetByID(ctx, offerID)
+		if err != nil {
+			log.Warn("Failed to load expired seat offer %s: %v", offerID, err)
+			continue
+		}
+		if offer == nil {
+			continue
+		}
+
+		if s.expireSeatOffer(ctx, offer) {
+			expired++
+		}
+	}
+
+	if expired > 0 {
+		log.Info("Expired %d seat offers", expired)
+	}
+
+	return nil
+}
+
+func (s *RegistrationService) expireSeatOffer(ctx context.Context, offer *domain.SeatOffer) bool {
+	log := registrationLog(ctx, offer.StudentID, offer.SectionID)
+	expired, err := s.seatOfferRepo.TransitionStatus(ctx, offer.OfferID, domain.OfferStatusPending, domain.OfferStatusExpired)
+	if err != nil {
+		log.Error("Failed to expire seat offer %s: %v", offer.OfferID, err)
+		return false
+	}
+	if !expired {
+		return false
+	}
+
+	log.Info("Seat offer %s for student %s in section %s expired", offer.OfferID, offer.StudentID, offer.SectionID)
+	s.releaseOfferedSeat(ctx, offer)
+	return true
+}
+
+// releaseOfferedSeat returns a held seat to the section and its pool and promotes the next
+// waitlisted student
+func (s *RegistrationService) releaseOfferedSeat(ctx context.Context, offer *domain.SeatOffer) {
+	log := registrationLog(ctx, offer.StudentID, offer.SectionID)
+	newSeatCount, err := s.cacheService.ReleaseSeat(ctx, offer.SectionID, offer.SeatPoolID)
+	if err != nil {
+		log.Error("Failed to release offered seat for section %s: %v", offer.SectionID, err)
+		return
+	}
+
+	s.updateAvailableSectionsCacheForSection(ctx, offer.SectionID, newSeatCount)
+
+	// Decline and expiry can race for the same offer; keying the event by offer promotes once
+	if err := s.enqueuePromotion(ctx, offer.SectionID, "offer:"+offer.OfferID.String()); err != nil {
+		log.Error("Failed to enqueue waitlist processing after releasing offer %s: %v", offer.OfferID, err)
+	}
+}
+
+func (s *RegistrationService) getStudentSeatOffer(ctx context.Context, offerID, studentID uuid.UUID) (*domain.SeatOffer, error) {
+	offer, err := s.seatOfferRepo.GetByID(ctx, offerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat offer: %w", err)
+	}
+
+	// Offers belonging to other students are reported as missing
+	if offer == nil || offer.StudentID != studentID {
+		return nil, ErrSeatOfferNotFound
+	}
+
+	return offer, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromotionClaimTTL is how long a processed seat-open event is remembered. Redeliveries
+// of the same event arrive within seconds, so a day leaves ample margin.
+const PromotionClaimTTL = 24 * time.Hour
+
+// enqueuePromotion asks the queue to promote the next waitlisted student into a seat freed
+// by the given seat-open event.
+func (s *RegistrationService) enqueuePromotion(ctx context.Context, sectionID uuid.UUID, seatEventID string) error {
+	return s.queueService.EnqueueWaitlistProcessing(ctx, interfaces.WaitlistPromotionJob{
+		SectionID:   sectionID,
+		SeatEventID: seatEventID,
+		Timestamp:   time.Now(),
+	})
+}
+
+// ProcessWaitlist promotes the next waitlisted student of the job's section. Each seat-open
+// event is claimed in Redis first, so a job enqueued twice for the same freed seat promotes
+// at most one student. A failed promotion gives the claim back for a retry.
+func (s *RegistrationService) ProcessWaitlist(ctx context.Context, job interfaces.WaitlistPromotionJob) error {
+	log := registrationLog(ctx, uuid.Nil, job.SectionID)
+	if job.SeatEventID == "" {
+		return s.processWaitlist(ctx, job.SectionID, job.Timestamp)
+	}
+
+	key := fmt.Sprintf("waitlist:promotion:%s", job.SeatEventID)
+	token := uuid.NewString()
+
+	claimed, err := s.cacheService.AcquireLock(ctx, key, token, PromotionClaimTTL)
+	if err != nil {
+		return fmt.Errorf("failed to claim seat event %s: %w", job.SeatEventID, err)
+	}
+	if !claimed {
+		log.Info("Seat event %s for section %s was already processed, skipping promotion", job.SeatEventID, job.SectionID)
+		return nil
+	}
+
+	if err := s.processWaitlist(ctx, job.SectionID, job.Timestamp); err != nil {
+		if _, releaseErr := s.cacheService.ReleaseLock(ctx, key, token); releaseErr != nil {
+			log.Warn("Failed to release claim on seat event %s: %v", job.SeatEventID, releaseErr)
+		}
+		return err
+	}
+
+	return nil
+}
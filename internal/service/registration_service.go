@@ -2,6 +2,7 @@ package service
 
 import (
 	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
 	interfaces "cobra-template/internal/interfaces/infrastructure"
 	serviceInterfaces "cobra-template/internal/interfaces/service"
 	"cobra-template/pkg/logger"
@@ -11,10 +12,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -28,6 +32,11 @@ const (
 	HTTPResponseTTL   = 5 * time.Minute
 	ShortTermCacheTTL = 2 * time.Minute
 	LongTermCacheTTL  = 2 * time.Hour
+
+	// seatSyncConflictAttempts caps the seat count writes one sync makes while other writers
+	// keep winning the optimistic lock; seatSyncConflictBackoff is the jitter between them
+	seatSyncConflictAttempts = 3
+	seatSyncConflictBackoff  = 20 * time.Millisecond
 )
 
 var _ serviceInterfaces.RegistrationService = (*RegistrationService)(nil)
@@ -40,7 +49,29 @@ type RegistrationService struct {
 	cacheService            interfaces.CacheService
 	queueService            interfaces.QueueService
 	idempotencyRepo         interfaces.IdempotencyRepository
+	seatOfferRepo           interfaces.SeatOfferRepository
+	seatHoldRepo            interfaces.SeatHoldRepository
+	studentHoldRepo         interfaces.StudentHoldRepository
+	scheduleOverrideRepo    interfaces.ScheduleOverrideRepository
+	permissionNumberRepo    interfaces.PermissionNumberRepository
+	transitionRepo          interfaces.RegistrationTransitionRepository
+	eventStore              *RegistrationEventStore
+	semesterService         *SemesterService
+	sectionCacheWarmer      *SectionCacheWarmer
+	kpiCounters             interfaces.KPICounterStore
+	studentNotifier         interfaces.StudentNotifier
+	auditService            *AuditService
+	seatSync                *SeatSyncBatcher
+	analytics               *AnalyticsProducer
+	fallback                *databaseFallback
+	seatReconciles          sync.Map
 	waitlistFallbackEnabled bool
+	seatOfferTTL            time.Duration
+	seatHoldTTL             time.Duration
+	waitlistTTL             time.Duration
+	defaultWaitlistMaxSize  int
+	studentLockTTL          time.Duration
+	studentLockWait         time.Duration
 }
 
 func NewRegistrationService(
@@ -51,7 +82,25 @@ func NewRegistrationService(
 	cacheService interfaces.CacheService,
 	queueService interfaces.QueueService,
 	idempotencyRepo interfaces.IdempotencyRepository,
+	seatOfferRepo interfaces.SeatOfferRepository,
+	seatHoldRepo interfaces.SeatHoldRepository,
+	studentHoldRepo interfaces.StudentHoldRepository,
+	scheduleOverrideRepo interfaces.ScheduleOverrideRepository,
+	permissionNumberRepo interfaces.PermissionNumberRepository,
+	transitionRepo interfaces.RegistrationTransitionRepository,
+	eventStore *RegistrationEventStore,
+	semesterService *SemesterService,
+	sectionCacheWarmer *SectionCacheWarmer,
+	kpiCounters interfaces.KPICounterStore,
+	studentNotifier interfaces.StudentNotifier,
+	auditService *AuditService,
 	waitlistFallbackEnabled bool,
+	seatOfferTTL time.Duration,
+	seatHoldTTL time.Duration,
+	waitlistTTL time.Duration,
+	waitlistMaxSize int,
+	studentLockTTL time.Duration,
+	studentLockWait time.Duration,
 ) *RegistrationService {
 	return &RegistrationService{
 		studentRepo:             studentRepo,
@@ -61,7 +110,25 @@ func NewRegistrationService(
 		cacheService:            cacheService,
 		queueService:            queueService,
 		idempotencyRepo:         idempotencyRepo,
+		seatOfferRepo:           seatOfferRepo,
+		seatHoldRepo:            seatHoldRepo,
+		studentHoldRepo:         studentHoldRepo,
+		scheduleOverrideRepo:    scheduleOverrideRepo,
+		permissionNumberRepo:    permissionNumberRepo,
+		transitionRepo:          transitionRepo,
+		eventStore:              eventStore,
+		semesterService:         semesterService,
+		sectionCacheWarmer:      sectionCacheWarmer,
+		kpiCounters:             kpiCounters,
+		studentNotifier:         studentNotifier,
+		auditService:            auditService,
 		waitlistFallbackEnabled: waitlistFallbackEnabled,
+		seatOfferTTL:            seatOfferTTL,
+		seatHoldTTL:             seatHoldTTL,
+		waitlistTTL:             waitlistTTL,
+		defaultWaitlistMaxSize:  waitlistMaxSize,
+		studentLockTTL:          studentLockTTL,
+		studentLockWait:         studentLockWait,
 	}
 }
 
@@ -69,8 +136,25 @@ type RegisterRequest = serviceInterfaces.RegisterRequest
 type RegisterResponse = serviceInterfaces.RegisterResponse
 type RegistrationResult = serviceInterfaces.RegistrationResult
 
-func (s *RegistrationService) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
-	logger.Info("Processing registration for student %s with %d sections", req.StudentID, len(req.SectionIDs))
+// ResultTemporarilyUnavailable is the registration result status when the seat counter
+// could not be reached for a reason that should clear shortly, so the request can be retried
+const ResultTemporarilyUnavailable = "temporarily_unavailable"
+
+func (s *RegistrationService) Register(ctx context.Context, req *RegisterRequest) (_ *RegisterResponse, err error) {
+	log := registrationLog(ctx, req.StudentID, uuid.Nil)
+	ctx, span := startSpan(ctx, "RegistrationService.Register",
+		attribute.String("student.id", req.StudentID.String()),
+		attribute.Int("registration.section_count", len(req.SectionIDs)),
+	)
+	defer func() { endSpan(span, err) }()
+
+	log.Info("Processing registration for student %s with %d sections", req.StudentID, len(req.SectionIDs))
+
+	unlock, err := s.lockStudent(ctx, req.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	if req.IdempotencyKey != "" {
 		existingKey, isDuplicate, err := s.checkIdempotency(ctx, req.IdempotencyKey, req.StudentID, req)
@@ -80,35 +164,49 @@ func (s *RegistrationService) Register(ctx context.Context, req *RegisterRequest
 		if isDuplicate {
 			var cachedResponse RegisterResponse
 			if err := json.Unmarshal([]byte(existingKey.ResponseData), &cachedResponse); err == nil {
-				logger.Info("Returning cached response for idempotency key: %s", req.IdempotencyKey)
+				log.Info("Returning cached response for idempotency key: %s", req.IdempotencyKey)
 				return &cachedResponse, nil
 			}
 		}
 	}
 
-	student, err := s.GetStudentDetails(ctx, req.StudentID)
-	if err != nil {
-		return nil, fmt.Errorf("student not found: %w", err)
-	}
-	if student == nil {
-		return nil, errors.New("student not found")
+	if err := s.checkStudentCanRegister(ctx, req.StudentID); err != nil {
+		if !errors.Is(err, ErrStudentOnHold) {
+			return nil, err
+		}
+		// Not stored under the idempotency key, so a retry after the hold is lifted registers
+		return s.holdBlockedResponse(ctx, req.StudentID, req.SectionIDs)
 	}
-	if student.EnrollmentStatus != "active" {
-		return nil, errors.New("student is not in active status")
+
+	var permission *domain.PermissionNumber
+	if req.PermissionCode != "" {
+		if permission, err = s.getPermissionNumber(ctx, req.StudentID, req.PermissionCode, req.SectionIDs); err != nil {
+			return nil, err
+		}
 	}
 
 	response := &RegisterResponse{
 		Results: make([]RegistrationResult, 0, len(req.SectionIDs)),
 	}
 
-	for _, sectionID := range req.SectionIDs {
-		result := s.registerForSection(ctx, req.StudentID, sectionID)
-		response.Results = append(response.Results, result)
+	for _, unit := range s.registrationUnits(ctx, req.StudentID, req.SectionIDs) {
+		switch {
+		case unit.result != nil:
+			s.emitRegistrationAttempt(req.StudentID, *unit.result)
+			response.Results = append(response.Results, *unit.result)
+		case len(unit.sectionIDs) == 2:
+			pair := [2]uuid.UUID{unit.sectionIDs[0], unit.sectionIDs[1]}
+			response.Results = append(response.Results, s.registerLinked(ctx, req.StudentID, pair, permission)...)
+		case permission != nil && permission.SectionID == unit.sectionIDs[0]:
+			response.Results = append(response.Results, s.register(ctx, req.StudentID, unit.sectionIDs[0], permission))
+		default:
+			response.Results = append(response.Results, s.registerForSection(ctx, req.StudentID, unit.sectionIDs[0]))
+		}
 	}
 
 	if req.IdempotencyKey != "" {
 		if err := s.storeIdempotencyResult(ctx, req.IdempotencyKey, req.StudentID, req, response, 200); err != nil {
-			logger.Warn("Failed to store idempotency result: %v", err)
+			log.Warn("Failed to store idempotency result: %v", err)
 		}
 	}
 
@@ -116,6 +214,25 @@ func (s *RegistrationService) Register(ctx context.Context, req *RegisterRequest
 }
 
 func (s *RegistrationService) registerForSection(ctx context.Context, studentID, sectionID uuid.UUID) RegistrationResult {
+	return s.register(ctx, studentID, sectionID, nil)
+}
+
+// register takes a seat in the section for the student, or a waitlist place when none is
+// left. A permission number for the section stands in for instructor consent and opens the
+// reserved and pooled seats, including those held for the waitlist.
+func (s *RegistrationService) register(ctx context.Context, studentID, sectionID uuid.UUID, permission *domain.PermissionNumber) (result RegistrationResult) {
+	log := registrationLog(ctx, studentID, sectionID)
+	ctx, span := startSpan(ctx, "RegistrationService.registerForSection",
+		attribute.String("student.id", studentID.String()),
+		attribute.String("section.id", sectionID.String()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.String("registration.status", result.Status))
+		span.End()
+		s.auditRegistration(ctx, studentID, result)
+		s.emitRegistrationAttempt(studentID, result)
+	}()
+
 	existing, err := s.registrationRepo.GetByStudentAndSection(ctx, studentID, sectionID)
 	if err == nil && existing != nil {
 		return RegistrationResult{
@@ -125,138 +242,263 @@ func (s *RegistrationService) registerForSection(ctx context.Context, studentID,
 		}
 	}
 
-	newSeatCount, err := s.cacheService.DecrementAndGetAvailableSeats(ctx, sectionID)
-	if err != nil {
-		// If seat key not found, try to initialize it from database
-		if strings.Contains(err.Error(), "seat key not found") {
-			logger.Info("Seat key not found for section %s, initializing from database", sectionID)
-
-			// Get section from database to get current seat count
-			section, dbErr := s.sectionRepo.GetByID(ctx, sectionID)
-			if dbErr != nil {
-				logger.Error("Failed to get section from database: %v", dbErr)
-				return RegistrationResult{
-					SectionID: sectionID,
-					Status:    "failed",
-					Message:   "Failed to process registration",
-				}
-			}
+	if err := s.checkSectionOpen(ctx, sectionID); err != nil {
+		return closedSectionResult(sectionID, err)
+	}
+	if permission == nil {
+		if err := s.checkInstructorConsent(ctx, sectionID); err != nil {
+			return closedSectionResult(sectionID, err)
+		}
+	}
+	if err := s.checkRepeatEnrollment(ctx, studentID, sectionID); err != nil {
+		return closedSectionResult(sectionID, err)
+	}
+	if result, ok := s.checkScheduleConflict(ctx, studentID, sectionID); !ok {
+		return result
+	}
 
-			if section == nil {
-				return RegistrationResult{
-					SectionID: sectionID,
-					Status:    "failed",
-					Message:   "Section not found",
-				}
-			}
+	seat := s.seatRequest(ctx, studentID, sectionID)
+	if permission != nil {
+		claimed, err := s.claimPermissionNumber(ctx, studentID, permission)
+		if err != nil {
+			log.Error("Failed to claim permission number %s: %v", permission.PermissionID, err)
+			return RegistrationResult{SectionID: sectionID, Status: "failed", Message: "Failed to process registration"}
+		}
+		if !claimed {
+			return RegistrationResult{SectionID: sectionID, Status: ResultPermissionUsed, Message: "Permission number has already been used"}
+		}
+		defer func() { s.settlePermissionNumber(ctx, studentID, permission, result) }()
+		seat = interfaces.SeatRequest{}
+	}
+	// The seat, its outbox jobs and the waitlist hold are settled in one script, so there is
+	// nothing to give back when a step fails
+	counter, err := s.reserveSeat(ctx, studentID, sectionID, seat)
+	switch {
+	case err == nil:
+	case errors.Is(err, interfaces.ErrCacheTransient):
+		return seatCounterUnavailableResult(sectionID, err)
+	case errors.Is(err, interfaces.ErrNoSeatsLeft):
+		return s.waitlistForSection(ctx, studentID, sectionID, nil)
+	case errors.Is(err, interfaces.ErrSeatsHeldForWaitlist):
+		return closedSectionResult(sectionID, ErrWaitlistFrozen)
+	case errors.Is(err, interfaces.ErrAlreadyRegistered):
+		return RegistrationResult{SectionID: sectionID, Status: "already_registered", Message: "Already registered"}
+	case errors.Is(err, ErrSectionNotFound):
+		return closedSectionResult(sectionID, err)
+	default:
+		log.Error("Failed to reserve seat in cache: %v", err)
+		return RegistrationResult{
+			SectionID: sectionID,
+			Status:    "failed",
+			Message:   "Failed to process registration",
+		}
+	}
 
-			// Initialize cache with current database value
-			if setErr := s.cacheService.SetAvailableSeats(ctx, sectionID, section.AvailableSeats, 24*time.Hour); setErr != nil {
-				logger.Error("Failed to initialize seat cache for section %s: %v", sectionID, setErr)
-				return RegistrationResult{
-					SectionID: sectionID,
-					Status:    "failed",
-					Message:   "Failed to process registration",
-				}
-			}
+	log.Info("Successfully reserved seat for student %s in section %s, remaining seats: %d", studentID, sectionID, counter.Available)
 
-			// Try to decrement again
-			newSeatCount, err = s.cacheService.DecrementAndGetAvailableSeats(ctx, sectionID)
-			if err != nil {
-				logger.Error("Failed to decrement seats after cache initialization: %v", err)
-				return RegistrationResult{
-					SectionID: sectionID,
-					Status:    "failed",
-					Message:   "Failed to process registration",
-				}
-			}
-		} else {
-			// Handle other types of errors (no seats available, etc.)
-			available, getErr := s.cacheService.GetAvailableSeats(ctx, sectionID)
-			if getErr == nil && available <= 0 {
-				position, waitlistErr := s.addToWaitlist(ctx, studentID, sectionID)
-				if waitlistErr != nil {
-					logger.Error("Failed to add to waitlist: %v", waitlistErr)
-					return RegistrationResult{
-						SectionID: sectionID,
-						Status:    "failed",
-						Message:   "Failed to add to waitlist",
-					}
-				}
-				return RegistrationResult{
-					SectionID: sectionID,
-					Status:    "waitlisted",
-					Message:   "Added to waitlist",
-					Position:  &position,
-				}
-			}
+	// The registration and seat update jobs went to the outbox with the seat itself
+	s.recordEnrollment(ctx, studentID, sectionID, counter.Available)
 
-			logger.Error("Failed to decrement seats in cache: %v", err)
-			return RegistrationResult{
-				SectionID: sectionID,
-				Status:    "failed",
-				Message:   "Failed to process registration",
-			}
-		}
-	}
+	return enrolledResult(sectionID, counter)
+}
 
-	logger.Info("Successfully reserved seat for student %s in section %s, remaining seats: %d", studentID, sectionID, newSeatCount)
+// enrolledResult reports a seat taken in the section, with the counter as the seat left it
+func enrolledResult(sectionID uuid.UUID, counter *interfaces.SeatCounter) RegistrationResult {
+	result := RegistrationResult{
+		SectionID:      sectionID,
+		Status:         "enrolled",
+		Message:        "Registration completed successfully",
+		AvailableSeats: &counter.Available,
+	}
+	// A counter rebuilt from a released seat does not know the section's capacity
+	if counter.Total > 0 {
+		enrolled := counter.Enrolled()
+		result.EnrolledCount = &enrolled
+	}
+	return result
+}
 
-	dbSyncJob := interfaces.DatabaseSyncJob{
-		JobType:   interfaces.JobTypeCreateRegistration,
-		Status:    interfaces.StatusEnrolled,
-		StudentID: studentID,
-		SectionID: sectionID,
-		Timestamp: time.Now(),
+// waitlistForSection puts a student who found no seat they may take on the waitlist. For a
+// linked pair linkedSectionID is the other section, whose seat the student is waiting for
+// together with this one.
+func (s *RegistrationService) waitlistForSection(ctx context.Context, studentID, sectionID uuid.UUID, linkedSectionID *uuid.UUID) RegistrationResult {
+	if err := s.checkWaitlistOpen(ctx, sectionID); err != nil {
+		return closedSectionResult(sectionID, err)
 	}
-	if err := s.queueService.EnqueueDatabaseSync(ctx, dbSyncJob); err != nil {
-		logger.Error("Failed to enqueue database sync job, rolling back cache: %v", err)
-		if rollbackErr := s.cacheService.IncrementAvailableSeats(ctx, sectionID); rollbackErr != nil {
-			logger.Error("Failed to rollback cache after sync job failure: %v", rollbackErr)
-		}
+	position, err := s.addToWaitlist(ctx, studentID, sectionID, linkedSectionID)
+	if errors.Is(err, ErrWaitlistFull) {
+		return closedSectionResult(sectionID, err)
+	}
+	if err != nil {
+		registrationLog(ctx, studentID, sectionID).Error("Failed to add to waitlist: %v", err)
 		return RegistrationResult{
 			SectionID: sectionID,
 			Status:    "failed",
-			Message:   "Failed to process registration",
+			Message:   "Failed to add to waitlist",
 		}
 	}
-
-	seatUpdateJob := interfaces.DatabaseSyncJob{
-		JobType:   interfaces.JobTypeUpdateSeats,
+	s.countKPI(ctx, interfaces.KPIWaitlistAdds, sectionID)
+	return RegistrationResult{
 		SectionID: sectionID,
-		Timestamp: time.Now(),
+		Status:    "waitlisted",
+		Message:   "Added to waitlist",
+		Position:  &position,
 	}
-	if err := s.queueService.EnqueueDatabaseSync(ctx, seatUpdateJob); err != nil {
-		logger.Warn("Failed to enqueue seat update job: %v", err)
-	}
-	s.updateStudentRegistrationCache(ctx, studentID, sectionID, domain.StatusEnrolled)
-	s.updateAvailableSectionsCacheForSection(ctx, sectionID, newSeatCount)
+}
 
+// seatCounterUnavailableResult reports a seat reservation that failed on a transient cache
+// error. If the connection dropped after the script ran, the seat was taken together with
+// its outbox jobs, and the registration still appears once they are processed.
+func seatCounterUnavailableResult(sectionID uuid.UUID, err error) RegistrationResult {
+	logger.Warn("Seat counter for section %s is temporarily unavailable: %v", sectionID, err)
 	return RegistrationResult{
 		SectionID: sectionID,
-		Status:    "enrolled",
-		Message:   "Registration completed successfully",
+		Status:    ResultTemporarilyUnavailable,
+		Message:   "Seat availability is temporarily unavailable, please try again",
+	}
+}
+
+// checkStudentCanRegister rejects students who may not take new seats. Archived students get
+// ErrStudentArchived and students with an active hold ErrStudentOnHold, so callers can tell
+// them apart from inactive ones.
+func (s *RegistrationService) checkStudentCanRegister(ctx context.Context, studentID uuid.UUID) error {
+	student, err := s.GetStudentDetails(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found: %w", err)
+	}
+	if student == nil {
+		return errors.New("student not found")
+	}
+	if student.IsArchived() {
+		return ErrStudentArchived
+	}
+	if student.EnrollmentStatus != domain.EnrollmentStatusActive {
+		return errors.New("student is not in active status")
+	}
+
+	holdTypes, err := s.activeHoldTypes(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if len(holdTypes) > 0 {
+		return fmt.Errorf("%w: %s", ErrStudentOnHold, strings.Join(holdTypes, ", "))
+	}
+	return nil
+}
+
+// checkStudentNotArchived guards changes to existing registrations, which stay allowed for
+// inactive students but not for archived ones.
+func (s *RegistrationService) checkStudentNotArchived(ctx context.Context, studentID uuid.UUID) error {
+	student, err := s.GetStudentDetails(ctx, studentID)
+	if err != nil {
+		return fmt.Errorf("student not found: %w", err)
+	}
+	if student != nil && student.IsArchived() {
+		return ErrStudentArchived
+	}
+	return nil
+}
+
+// enrollmentJobs are the database sync jobs that record a new enrollment. The registration
+// job carries a dedup key because the outbox may deliver it more than once. The request ID
+// is stamped here because jobs sent through the outbox never pass the queue's Enqueue.
+func enrollmentJobs(ctx context.Context, studentID, sectionID uuid.UUID) []interfaces.DatabaseSyncJob {
+	now := time.Now()
+	requestID := logger.RequestID(ctx)
+	return []interfaces.DatabaseSyncJob{
+		{
+			JobType:   interfaces.JobTypeCreateRegistration,
+			Status:    interfaces.StatusEnrolled,
+			StudentID: studentID,
+			SectionID: sectionID,
+			Timestamp: now,
+			DedupKey:  "registration:" + uuid.NewString(),
+			RequestID: requestID,
+		},
+		{
+			JobType:   interfaces.JobTypeUpdateSeats,
+			SectionID: sectionID,
+			Timestamp: now,
+			RequestID: requestID,
+		},
 	}
 }
 
+// enrollReservedSeat persists a registration for a seat already taken from the counter. The
+// caller gives the seat back if this fails.
+func (s *RegistrationService) enrollReservedSeat(ctx context.Context, studentID, sectionID uuid.UUID, newSeatCount int) error {
+	log := registrationLog(ctx, studentID, sectionID)
+	jobs := enrollmentJobs(ctx, studentID, sectionID)
+	if err := s.queueService.EnqueueDatabaseSync(ctx, jobs[0]); err != nil {
+		return err
+	}
+	if err := s.queueService.EnqueueDatabaseSync(ctx, jobs[1]); err != nil {
+		log.Warn("Failed to enqueue seat update job: %v", err)
+	}
+	s.recordEnrollment(ctx, studentID, sectionID, newSeatCount)
+	return nil
+}
+
+// recordEnrollment updates the caches and counters once a seat is taken and its jobs queued
+func (s *RegistrationService) recordEnrollment(ctx context.Context, studentID, sectionID uuid.UUID, newSeatCount int) {
+	s.updateStudentRegistrationCache(ctx, studentID, sectionID, domain.StatusEnrolled)
+	s.updateAvailableSectionsCacheForSection(ctx, sectionID, newSeatCount)
+	s.countKPI(ctx, interfaces.KPIRegistrations, sectionID)
+}
+
 func (s *RegistrationService) ProcessDatabaseSyncJob(ctx context.Context, job interfaces.DatabaseSyncJob) error {
-	logger.Info("Processing database sync job: %s for student %s and section %s", job.JobType, job.StudentID, job.SectionID)
+	log := registrationLog(ctx, job.StudentID, job.SectionID)
+	log.Info("Processing database sync job: %s for student %s and section %s", job.JobType, job.StudentID, job.SectionID)
 
+	if s.outboxJobDone(ctx, job) {
+		log.Info("Skipping database sync job %s, already processed", job.DedupKey)
+		return nil
+	}
+	if err := s.processDatabaseSyncJob(ctx, job); err != nil {
+		return err
+	}
+	s.markOutboxJobDone(ctx, job)
+	return nil
+}
+
+func (s *RegistrationService) processDatabaseSyncJob(ctx context.Context, job interfaces.DatabaseSyncJob) error {
 	switch job.JobType {
 	case interfaces.JobTypeCreateRegistration:
-		return s.createRegistrationRecord(ctx, job.StudentID, job.SectionID)
+		_, err := s.createRegistrationRecord(ctx, job.StudentID, job.SectionID, job.SeatPoolID, domain.EventRegistered, job.Timestamp)
+		return err
+	case interfaces.JobTypePromoteRegistration:
+		created, err := s.createRegistrationRecord(ctx, job.StudentID, job.SectionID, job.SeatPoolID, domain.EventPromoted, job.Timestamp)
+		if created && job.SeatFreedAt != nil {
+			metrics.WaitlistPromotionLatency.WithLabelValues(metrics.PromotionEnrolled).Observe(time.Since(*job.SeatFreedAt).Seconds())
+		}
+		return err
 	case interfaces.JobTypeUpdateSeats:
+		if s.seatSync != nil {
+			s.seatSync.Add(job.SectionID)
+			return nil
+		}
 		return s.updateSectionSeats(ctx, job.SectionID)
+	case interfaces.JobTypeCompactWaitlist:
+		return s.renumberWaitlist(ctx, job.SectionID)
 	default:
 		return fmt.Errorf("unknown job type: %s", job.JobType)
 	}
 }
 
-func (s *RegistrationService) createRegistrationRecord(ctx context.Context, studentID, sectionID uuid.UUID) error {
+// createRegistrationRecord stores an enrolled registration and reports whether it created
+// one; a registration that already exists is left alone. seatPoolID is the seat pool the
+// seat came from, if any.
+func (s *RegistrationService) createRegistrationRecord(ctx context.Context, studentID, sectionID uuid.UUID, seatPoolID *uuid.UUID, eventType domain.RegistrationEventType, occurredAt time.Time) (bool, error) {
+	log := registrationLog(ctx, studentID, sectionID)
 	existing, err := s.registrationRepo.GetByStudentAndSection(ctx, studentID, sectionID)
 	if err == nil && existing != nil {
-		logger.Info("Registration already exists for student %s and section %s", studentID, sectionID)
-		return nil
+		log.Info("Registration already exists for student %s and section %s", studentID, sectionID)
+		return false, nil
+	}
+
+	// In event-sourced mode the event is the source of truth and the row is its projection
+	if err := s.recordEvent(ctx, eventType, studentID, sectionID, nil, occurredAt); err != nil {
+		return false, err
 	}
 
 	registration := &domain.Registration{
@@ -268,50 +510,101 @@ func (s *RegistrationService) createRegistrationRecord(ctx context.Context, stud
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 		Version:          1,
+		SeatPoolID:       seatPoolID,
 	}
 
 	if err := s.registrationRepo.Create(ctx, registration); err != nil {
-		logger.Error("Failed to create registration record: %v", err)
-		return fmt.Errorf("failed to create registration: %w", err)
+		log.Error("Failed to create registration record: %v", err)
+		return false, fmt.Errorf("failed to create registration: %w", err)
 	}
 
-	logger.Info("Successfully created registration record for student %s in section %s", studentID, sectionID)
-	return nil
+	log.Info("Successfully created registration record for student %s in section %s", studentID, sectionID)
+	return true, nil
 }
 
+// EnableSeatSyncBatching coalesces seat update jobs, writing each section's seat count at
+// most once per window or per maxEvents jobs instead of once per registration. Call it
+// before the queue workers start.
+func (s *RegistrationService) EnableSeatSyncBatching(window time.Duration, maxEvents int) {
+	s.seatSync = NewSeatSyncBatcher(s.updateSectionSeats, window, maxEvents)
+	s.seatSync.Start()
+}
+
+// StopSeatSync writes the seat counts still waiting for a flush. Call it after the queue
+// workers have stopped.
+func (s *RegistrationService) StopSeatSync() {
+	if s.seatSync != nil {
+		s.seatSync.Stop()
+	}
+}
+
+// updateSectionSeats copies the seat counter to the section row. Losing the optimistic lock
+// only means another writer got there first, so the counter and row are read again and the
+// write retried a few times before the job is handed back to the queue. The student was
+// told the outcome of the seat reservation already; nothing here changes it.
 func (s *RegistrationService) updateSectionSeats(ctx context.Context, sectionID uuid.UUID) error {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	var cachedSeats int
+	for attempt := 1; ; attempt++ {
+		seats, err := s.writeSectionSeats(ctx, sectionID)
+		if err == nil {
+			cachedSeats = seats
+			break
+		}
+		if !errors.Is(err, interfaces.ErrSectionVersionConflict) {
+			log.Error("Failed to update section seat count: %v", err)
+			return err
+		}
+		if attempt >= seatSyncConflictAttempts {
+			metrics.SeatSyncConflicts.WithLabelValues(metrics.SeatSyncConflictExhausted).Inc()
+			log.Warn("Seat count of section %s still conflicting after %d attempts, leaving it to a retry", sectionID, attempt)
+			return fmt.Errorf("failed to update section: %w", err)
+		}
+
+		metrics.SeatSyncConflicts.WithLabelValues(metrics.SeatSyncConflictRetried).Inc()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(rand.Int63n(int64(seatSyncConflictBackoff) * int64(attempt)))):
+		}
+	}
+
+	// Update section details cache with new seat count
+	// Removed: s.updateSectionDetailsCache(ctx, section)
+
+	// Update available sections cache for the specific semester this section belongs to
+	s.updateAvailableSectionsCacheForSection(ctx, sectionID, cachedSeats)
+
+	log.Info("Successfully synchronized seat count for section %s to %d", sectionID, cachedSeats)
+	return nil
+}
+
+// writeSectionSeats writes the current seat counter to the section row under its version
+func (s *RegistrationService) writeSectionSeats(ctx context.Context, sectionID uuid.UUID) (int, error) {
 	cachedSeats, err := s.cacheService.GetAvailableSeats(ctx, sectionID)
 	if err != nil {
-		logger.Error("Failed to get cached seat count for section %s: %v", sectionID, err)
-		return fmt.Errorf("failed to get cached seat count: %w", err)
+		return 0, fmt.Errorf("failed to get cached seat count: %w", err)
 	}
 
 	section, err := s.sectionRepo.GetByID(ctx, sectionID)
 	if err != nil {
-		return fmt.Errorf("failed to get section: %w", err)
+		return 0, fmt.Errorf("failed to get section: %w", err)
 	}
 	if section == nil {
-		return fmt.Errorf("section not found")
+		return 0, fmt.Errorf("section not found")
 	}
 
 	section.AvailableSeats = cachedSeats
 	section.Version++
+	section.UpdatedAt = time.Now()
 	if err := s.sectionRepo.UpdateWithOptimisticLock(ctx, section); err != nil {
-		logger.Error("Failed to update section seat count: %v", err)
-		return fmt.Errorf("failed to update section: %w", err)
+		return 0, fmt.Errorf("failed to update section: %w", err)
 	}
-
-	// Update section details cache with new seat count
-	// Removed: s.updateSectionDetailsCache(ctx, section)
-
-	// Update available sections cache for the specific semester this section belongs to
-	s.updateAvailableSectionsCacheForSection(ctx, sectionID, cachedSeats)
-
-	logger.Info("Successfully synchronized seat count for section %s to %d", sectionID, cachedSeats)
-	return nil
+	return cachedSeats, nil
 }
 
-func (s *RegistrationService) addToWaitlist(ctx context.Context, studentID, sectionID uuid.UUID) (int, error) {
+func (s *RegistrationService) addToWaitlist(ctx context.Context, studentID, sectionID uuid.UUID, linkedSectionID *uuid.UUID) (int, error) {
+	log := registrationLog(ctx, studentID, sectionID)
 	position, err := s.cacheService.GetWaitlistSize(ctx, sectionID)
 	if err != nil {
 		position, err = s.waitlistRepo.GetNextPosition(ctx, sectionID)
@@ -322,24 +615,40 @@ func (s *RegistrationService) addToWaitlist(ctx context.Context, studentID, sect
 		position++
 	}
 
+	now := time.Now()
 	waitlistEntry := &domain.WaitlistEntry{
 		WaitlistID: uuid.New(),
 		StudentID:  studentID,
 		SectionID:  sectionID,
 		Position:   position,
-		Timestamp:  time.Now(),
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	if err := s.cacheService.AddToWaitlist(ctx, sectionID, studentID, position, waitlistEntry); err != nil {
+		Timestamp:  now,
+		ExpiresAt:  s.waitlistExpiry(ctx, sectionID, now),
+		// A linked entry is promoted only with a seat in its linked section too
+		LinkedSectionID: linkedSectionID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	maxSize := s.waitlistMaxSize(ctx, sectionID)
+	if err := s.cacheService.AddToWaitlist(ctx, sectionID, studentID, position, waitlistEntry, maxSize); err != nil {
+		if errors.Is(err, ErrWaitlistFull) {
+			log.Info("Waitlist for section %s is full at %d students, not adding student %s", sectionID, maxSize, studentID)
+			return 0, err
+		}
 		if s.waitlistFallbackEnabled {
-			logger.Warn("Failed to add to Redis waitlist, falling back to database queue: %v", err)
+			log.Warn("Failed to add to Redis waitlist, falling back to database queue: %v", err)
+			// Without Redis the cap can only be checked against the database, which is not
+			// atomic with the add; a burst of adds may overshoot it by a few places
+			if maxSize > 0 && position > maxSize {
+				return 0, ErrWaitlistFull
+			}
 			waitlistJob := interfaces.WaitlistJob{
-				StudentID: studentID,
-				SectionID: sectionID,
-				Position:  position,
-				Timestamp: time.Now(),
+				StudentID:       studentID,
+				SectionID:       sectionID,
+				Position:        position,
+				Timestamp:       time.Now(),
+				ExpiresAt:       waitlistEntry.ExpiresAt,
+				LinkedSectionID: linkedSectionID,
 			}
 
 			if err := s.queueService.EnqueueWaitlistEntry(ctx, waitlistJob); err != nil {
@@ -356,57 +665,129 @@ func (s *RegistrationService) addToWaitlist(ctx context.Context, studentID, sect
 	}
 
 	waitlistJob := interfaces.WaitlistJob{
-		StudentID: studentID,
-		SectionID: sectionID,
-		Position:  position,
-		Timestamp: time.Now(),
+		StudentID:       studentID,
+		SectionID:       sectionID,
+		Position:        position,
+		Timestamp:       time.Now(),
+		ExpiresAt:       waitlistEntry.ExpiresAt,
+		LinkedSectionID: linkedSectionID,
 	}
 
 	if err := s.queueService.EnqueueWaitlistEntry(ctx, waitlistJob); err != nil {
-		logger.Warn("Failed to enqueue waitlist entry for database persistence: %v", err)
+		log.Warn("Failed to enqueue waitlist entry for database persistence: %v", err)
 	}
 
 	// Update student waitlist cache
 	s.updateStudentWaitlistCache(ctx, studentID, waitlistEntry, "add")
 
-	logger.Info("Successfully added student %s to waitlist for section %s at position %d", studentID, sectionID, position)
+	log.Info("Successfully added student %s to waitlist for section %s at position %d", studentID, sectionID, position)
 	return position, nil
 }
 
-func (s *RegistrationService) DropCourse(ctx context.Context, studentID, sectionID uuid.UUID) error {
-	logger.Info("Processing course drop for student %s and section %s", studentID.String(), sectionID.String())
+// DropCourse takes the student out of an enrolled section and returns what happened: before
+// the semester's drop deadline the course is dropped and its seat freed, after it the course
+// is withdrawn and the seat kept. Once the withdraw deadline has passed it fails with
+// ErrWithdrawDeadlinePassed.
+func (s *RegistrationService) DropCourse(ctx context.Context, studentID, sectionID uuid.UUID) (domain.RegistrationStatus, error) {
+	return s.dropCourse(ctx, studentID, sectionID, true)
+}
+
+// dropCourse drops or withdraws the student from the section. Without deadlines the course
+// is always dropped, which is how staff clear a section.
+func (s *RegistrationService) dropCourse(ctx context.Context, studentID, sectionID uuid.UUID, deadlines bool) (outcome domain.RegistrationStatus, err error) {
+	log := registrationLog(ctx, studentID, sectionID)
+	ctx, span := startSpan(ctx, "RegistrationService.DropCourse",
+		attribute.String("student.id", studentID.String()),
+		attribute.String("section.id", sectionID.String()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.String("registration.status", string(outcome)))
+		endSpan(span, err)
+	}()
+
+	log.Info("Processing course drop for student %s and section %s", studentID.String(), sectionID.String())
+
+	unlock, err := s.lockStudent(ctx, studentID)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if err := s.checkStudentNotArchived(ctx, studentID); err != nil {
+		return "", err
+	}
 
 	registration, err := s.registrationRepo.GetByStudentAndSection(ctx, studentID, sectionID)
 	if err != nil {
-		return fmt.Errorf("registration not found: %w", err)
+		return "", fmt.Errorf("registration not found: %w", err)
 	}
 	if registration == nil {
-		return errors.New("registration not found")
+		return "", errors.New("registration not found")
 	}
 
 	if registration.Status != domain.StatusEnrolled {
-		return errors.New("can only drop enrolled courses")
+		return "", errors.New("can only drop enrolled courses")
 	}
 
-	newSeatCount, err := s.cacheService.IncrementAndGetAvailableSeats(ctx, sectionID)
+	outcome = domain.StatusDropped
+	if deadlines {
+		if outcome, err = s.dropOutcome(ctx, sectionID); err != nil {
+			return "", err
+		}
+	}
+	if outcome == domain.StatusWithdrawn {
+		if err := s.withdrawCourse(ctx, registration); err != nil {
+			return "", err
+		}
+		return outcome, nil
+	}
+	if err := s.freeDroppedSeat(ctx, registration); err != nil {
+		return "", err
+	}
+	return outcome, nil
+}
+
+// freeDroppedSeat drops an enrolled registration, gives its seat back and offers it to the
+// waitlist
+func (s *RegistrationService) freeDroppedSeat(ctx context.Context, registration *domain.Registration) error {
+	studentID, sectionID := registration.StudentID, registration.SectionID
+	log := registrationLog(ctx, studentID, sectionID)
+
+	newSeatCount, err := s.cacheService.ReleaseSeat(ctx, sectionID, registration.SeatPoolID)
 	if err != nil {
-		logger.Error("Failed to increment seats in cache: %v", err)
+		log.Error("Failed to increment seats in cache: %v", err)
 		return fmt.Errorf("failed to update seat availability: %w", err)
 	}
 
-	logger.Info("Successfully freed seat for section %s, new seat count: %d", sectionID.String(), newSeatCount)
+	log.Info("Successfully freed seat for section %s, new seat count: %d", sectionID.String(), newSeatCount)
 
 	registration.Status = domain.StatusDropped
 	registration.UpdatedAt = time.Now()
 
+	if err := s.recordEvent(ctx, domain.EventDropped, studentID, sectionID, nil, registration.UpdatedAt); err != nil {
+		log.Error("Failed to record drop event, rolling back cache: %v", err)
+		if _, rollbackErr := s.cacheService.RetakeSeat(ctx, sectionID, registration.SeatPoolID); rollbackErr != nil {
+			log.Error("Failed to rollback cache after event store failure: %v", rollbackErr)
+		}
+		return fmt.Errorf("failed to record drop: %w", err)
+	}
+
 	if err := s.registrationRepo.Update(ctx, registration); err != nil {
-		logger.Error("Failed to update registration, rolling back cache: %v", err)
-		if rollbackErr := s.cacheService.DecrementAvailableSeats(ctx, sectionID); rollbackErr != nil {
-			logger.Error("Failed to rollback cache after DB failure: %v", rollbackErr)
+		log.Error("Failed to update registration, rolling back cache: %v", err)
+		if _, rollbackErr := s.cacheService.RetakeSeat(ctx, sectionID, registration.SeatPoolID); rollbackErr != nil {
+			log.Error("Failed to rollback cache after DB failure: %v", rollbackErr)
 		}
 		return fmt.Errorf("failed to update registration: %w", err)
 	}
 
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditDropped,
+		StudentID: studentID,
+		SectionID: sectionID,
+		Before:    map[string]any{"status": domain.StatusEnrolled},
+		After:     map[string]any{"status": domain.StatusDropped, "available_seats": newSeatCount},
+	})
+
 	dbSyncJob := interfaces.DatabaseSyncJob{
 		JobType:   interfaces.JobTypeUpdateSeats,
 		StudentID: studentID,
@@ -414,7 +795,7 @@ func (s *RegistrationService) DropCourse(ctx context.Context, studentID, section
 		Timestamp: time.Now(),
 	}
 	if err := s.queueService.EnqueueDatabaseSync(ctx, dbSyncJob); err != nil {
-		logger.Warn("Failed to enqueue database sync job for seat update: %v", err)
+		log.Warn("Failed to enqueue database sync job for seat update: %v", err)
 	}
 
 	// Update student registration cache instead of deleting
@@ -422,17 +803,47 @@ func (s *RegistrationService) DropCourse(ctx context.Context, studentID, section
 
 	// Update available sections cache for all semesters this section belongs to
 	s.updateAvailableSectionsCacheForSection(ctx, sectionID, newSeatCount)
+	s.countKPI(ctx, interfaces.KPIDrops, sectionID)
 
-	if err := s.queueService.EnqueueWaitlistProcessing(ctx, sectionID); err != nil {
-		logger.Error("Failed to process waitlist after course drop: %v", err)
+	seatEventID := fmt.Sprintf("drop:%s:%d", registration.RegistrationID, registration.UpdatedAt.UnixNano())
+	if err := s.enqueuePromotion(ctx, sectionID, seatEventID); err != nil {
+		log.Error("Failed to process waitlist after course drop: %v", err)
 	}
 
-	logger.Info("Course drop completed for student %s and section %s", studentID.String(), sectionID.String())
+	log.Info("Course drop completed for student %s and section %s", studentID.String(), sectionID.String())
 	return nil
 }
 
+// DropAllFromSection drops every student enrolled in the section and returns how many were
+// dropped. It carries on past students that fail and reports them together at the end.
+func (s *RegistrationService) DropAllFromSection(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	registrations, err := s.registrationRepo.GetBySectionID(ctx, sectionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get registrations: %w", err)
+	}
+
+	dropped := 0
+	var errs []error
+	for _, registration := range registrations {
+		if registration.Status != domain.StatusEnrolled {
+			continue
+		}
+		// The section is being cleared, so students get their seats back whatever the deadline
+		if _, err := s.dropCourse(ctx, registration.StudentID, sectionID, false); err != nil {
+			errs = append(errs, fmt.Errorf("student %s: %w", registration.StudentID, err))
+			continue
+		}
+		dropped++
+	}
+
+	log.Info("Dropped %d students from section %s", dropped, sectionID)
+	return dropped, errors.Join(errs...)
+}
+
 func (s *RegistrationService) ProcessWaitlistJob(ctx context.Context, job interfaces.WaitlistJob) error {
-	logger.Info("Processing waitlist job for student %s and section %s at position %d", job.StudentID, job.SectionID, job.Position)
+	log := registrationLog(ctx, job.StudentID, job.SectionID)
+	log.Info("Processing waitlist job for student %s and section %s at position %d", job.StudentID, job.SectionID, job.Position)
 
 	waitlistEntry := &domain.WaitlistEntry{
 		WaitlistID: uuid.New(),
@@ -440,23 +851,41 @@ func (s *RegistrationService) ProcessWaitlistJob(ctx context.Context, job interf
 		SectionID:  job.SectionID,
 		Position:   job.Position,
 		Timestamp:  job.Timestamp,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ExpiresAt:  job.ExpiresAt,
+		// Entries queued before linked sections carry no link and wait for one seat
+		LinkedSectionID: job.LinkedSectionID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	position := job.Position
+	if err := s.recordEvent(ctx, domain.EventWaitlisted, job.StudentID, job.SectionID, &position, job.Timestamp); err != nil {
+		return err
 	}
 
 	if err := s.waitlistRepo.Create(ctx, waitlistEntry); err != nil {
 		return fmt.Errorf("failed to create waitlist entry: %w", err)
 	}
 
-	logger.Info("Successfully created waitlist entry for student %s in section %s at position %d", job.StudentID, job.SectionID, job.Position)
+	log.Info("Successfully created waitlist entry for student %s in section %s at position %d", job.StudentID, job.SectionID, job.Position)
 	return nil
 }
 
-func (s *RegistrationService) ProcessWaitlist(ctx context.Context, sectionID uuid.UUID) error {
-	return s.processWaitlist(ctx, sectionID)
-}
+// processWaitlist promotes the next waitlisted student of a section. seatFreedAt is when the
+// seat was freed, or zero when unknown.
+func (s *RegistrationService) processWaitlist(ctx context.Context, sectionID uuid.UUID, seatFreedAt time.Time) error {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	// A cancelled section keeps its waitlist but promotes nobody into it
+	if err := s.checkSectionOpen(ctx, sectionID); errors.Is(err, ErrSectionInactive) {
+		log.Info("Section %s is inactive, skipping waitlist promotion", sectionID)
+		return nil
+	}
+	// Promotions paused by a waitlist freeze are caught up when the freeze is lifted
+	if section, err := s.getSectionMetadata(ctx, sectionID); err == nil && section != nil && section.WaitlistPromotionsPaused {
+		log.Info("Waitlist promotions for section %s are paused, skipping waitlist promotion", sectionID)
+		return nil
+	}
 
-func (s *RegistrationService) processWaitlist(ctx context.Context, sectionID uuid.UUID) error {
 	nextEntryData, err := s.cacheService.GetNextInWaitlist(ctx, sectionID)
 	if err != nil || nextEntryData == nil {
 		if s.waitlistFallbackEnabled {
@@ -464,7 +893,7 @@ func (s *RegistrationService) processWaitlist(ctx context.Context, sectionID uui
 			if err != nil || nextEntry == nil {
 				return nil
 			}
-			return s.processWaitlistFromDB(ctx, sectionID, nextEntry)
+			return s.processWaitlistFromDB(ctx, sectionID, nextEntry, seatFreedAt)
 		} else {
 			if err != nil {
 				return fmt.Errorf("failed to get next in waitlist from Redis and fallback is disabled: %w", err)
@@ -476,142 +905,191 @@ func (s *RegistrationService) processWaitlist(ctx context.Context, sectionID uui
 	var nextEntry domain.WaitlistEntry
 	entryBytes, err := json.Marshal(nextEntryData)
 	if err != nil {
-		logger.Error("Failed to marshal waitlist entry from Redis: %v", err)
+		log.Error("Failed to marshal waitlist entry from Redis: %v", err)
 		if s.waitlistFallbackEnabled {
 			dbEntry, err := s.waitlistRepo.GetNextInLine(ctx, sectionID)
 			if err != nil || dbEntry == nil {
 				return nil
 			}
-			return s.processWaitlistFromDB(ctx, sectionID, dbEntry)
+			return s.processWaitlistFromDB(ctx, sectionID, dbEntry, seatFreedAt)
 		} else {
 			return fmt.Errorf("failed to process waitlist entry from Redis and fallback is disabled: %w", err)
 		}
 	}
 
 	if err := json.Unmarshal(entryBytes, &nextEntry); err != nil {
-		logger.Error("Failed to unmarshal waitlist entry from Redis: %v", err)
+		log.Error("Failed to unmarshal waitlist entry from Redis: %v", err)
 		if s.waitlistFallbackEnabled {
 			dbEntry, err := s.waitlistRepo.GetNextInLine(ctx, sectionID)
 			if err != nil || dbEntry == nil {
 				return nil
 			}
-			return s.processWaitlistFromDB(ctx, sectionID, dbEntry)
+			return s.processWaitlistFromDB(ctx, sectionID, dbEntry, seatFreedAt)
 		} else {
 			return fmt.Errorf("failed to unmarshal waitlist entry from Redis and fallback is disabled: %w", err)
 		}
 	}
 
-	return s.processWaitlistFromRedis(ctx, sectionID, &nextEntry)
+	return s.processWaitlistFromRedis(ctx, sectionID, &nextEntry, seatFreedAt)
 }
 
-func (s *RegistrationService) processWaitlistFromRedis(ctx context.Context, sectionID uuid.UUID, nextEntry *domain.WaitlistEntry) error {
-	available, err := s.cacheService.GetAvailableSeats(ctx, sectionID)
-	if err != nil || available <= 0 {
+func (s *RegistrationService) processWaitlistFromRedis(ctx context.Context, sectionID uuid.UUID, nextEntry *domain.WaitlistEntry, seatFreedAt time.Time) error {
+	log := registrationLog(ctx, nextEntry.StudentID, sectionID)
+	counter, err := s.takePromotedSeat(ctx, nextEntry.StudentID, sectionID)
+	if errors.Is(err, interfaces.ErrNoSeatsLeft) {
 		return nil
 	}
-
-	newSeatCount, err := s.cacheService.DecrementAndGetAvailableSeats(ctx, sectionID)
 	if err != nil {
-		return nil
+		// The job is retried with the seat event unclaimed, so the seat still goes to the waitlist
+		log.Error("Failed to take promoted seat in section %s: %v", sectionID, err)
+		return fmt.Errorf("failed to take promoted seat: %w", err)
 	}
+	newSeatCount := counter.Available
 
+	// The entry leaves the waitlist before the linked seat or an offer is given out, so a
+	// failed removal has only the promoted seat to give back
 	if err := s.cacheService.RemoveFromWaitlist(ctx, sectionID, nextEntry.StudentID); err != nil {
-		logger.Error("Failed to remove from Redis waitlist: %v", err)
-		if rollbackErr := s.cacheService.IncrementAvailableSeats(ctx, sectionID); rollbackErr != nil {
-			logger.Error("Failed to rollback cache after Redis waitlist removal failure: %v", rollbackErr)
-		}
+		log.Error("Failed to remove from Redis waitlist: %v", err)
+		s.releasePromotedSeat(ctx, nextEntry.StudentID, sectionID, counter.Pool)
 		return fmt.Errorf("failed to remove from Redis waitlist: %w", err)
 	}
+	if !s.takeLinkedSeat(ctx, sectionID, nextEntry) {
+		s.releasePromotedSeat(ctx, nextEntry.StudentID, sectionID, counter.Pool)
+		s.restoreWaitlistEntry(ctx, sectionID, nextEntry, false)
+		return nil
+	}
 
-	if err := s.waitlistRepo.Delete(ctx, nextEntry.WaitlistID); err != nil {
-		logger.Warn("Failed to remove waitlist entry from database: %v", err)
+	offer, err := s.offerSeatIfEnabled(ctx, sectionID, nextEntry, counter.Pool, seatFreedAt)
+	if err != nil {
+		s.releasePromotedSeat(ctx, nextEntry.StudentID, sectionID, counter.Pool)
+		s.restoreWaitlistEntry(ctx, sectionID, nextEntry, false)
+		return err
 	}
 
-	dbSyncJob := interfaces.DatabaseSyncJob{
-		JobType:   interfaces.JobTypeCreateRegistration,
-		StudentID: nextEntry.StudentID,
-		SectionID: sectionID,
-		Timestamp: time.Now(),
+	if err := s.waitlistRepo.Delete(ctx, nextEntry.WaitlistID); err != nil {
+		log.Warn("Failed to remove waitlist entry from database: %v", err)
 	}
-	if err := s.queueService.EnqueueDatabaseSync(ctx, dbSyncJob); err != nil {
-		logger.Error("Failed to enqueue database sync job for waitlisted student: %v", err)
+
+	// With seat offers enabled the student enrolls only after accepting the offer, unless
+	// they hold the seat of a linked section already
+	if s.seatOfferTTL <= 0 || nextEntry.LinkedSectionID != nil {
+		s.enrollPromotedStudent(ctx, nextEntry.StudentID, sectionID, counter.Pool, seatFreedAt)
 	}
 
 	// Update caches efficiently instead of invalidating
-	s.updateStudentRegistrationCache(ctx, nextEntry.StudentID, sectionID, domain.StatusEnrolled)
 	s.updateStudentWaitlistCache(ctx, nextEntry.StudentID, nextEntry, "remove")
 	s.updateAvailableSectionsCacheForSection(ctx, sectionID, newSeatCount)
+	s.compactWaitlist(ctx, sectionID)
+	s.notifyPromotion(ctx, sectionID, nextEntry.StudentID, offer)
 
-	logger.Info("Successfully processed waitlist entry from Redis for student %s in section %s, remaining seats: %d",
+	log.Info("Successfully processed waitlist entry from Redis for student %s in section %s, remaining seats: %d",
 		nextEntry.StudentID, sectionID, newSeatCount)
 
 	return nil
 }
 
-func (s *RegistrationService) processWaitlistFromDB(ctx context.Context, sectionID uuid.UUID, nextEntry *domain.WaitlistEntry) error {
-	available, err := s.cacheService.GetAvailableSeats(ctx, sectionID)
-	if err != nil || available <= 0 {
+func (s *RegistrationService) processWaitlistFromDB(ctx context.Context, sectionID uuid.UUID, nextEntry *domain.WaitlistEntry, seatFreedAt time.Time) error {
+	log := registrationLog(ctx, nextEntry.StudentID, sectionID)
+	counter, err := s.takePromotedSeat(ctx, nextEntry.StudentID, sectionID)
+	if errors.Is(err, interfaces.ErrNoSeatsLeft) {
 		return nil
 	}
-
-	newSeatCount, err := s.cacheService.DecrementAndGetAvailableSeats(ctx, sectionID)
 	if err != nil {
-		return nil
+		log.Error("Failed to take promoted seat in section %s: %v", sectionID, err)
+		return fmt.Errorf("failed to take promoted seat: %w", err)
 	}
+	newSeatCount := counter.Available
 
+	// As from Redis, the entry is removed before anything else is given out for it
 	if err := s.waitlistRepo.Delete(ctx, nextEntry.WaitlistID); err != nil {
-		if rollbackErr := s.cacheService.IncrementAvailableSeats(ctx, sectionID); rollbackErr != nil {
-			logger.Error("Failed to rollback cache after waitlist removal failure: %v", rollbackErr)
-		}
+		s.releasePromotedSeat(ctx, nextEntry.StudentID, sectionID, counter.Pool)
 		return fmt.Errorf("failed to remove from waitlist: %w", err)
 	}
+	if !s.takeLinkedSeat(ctx, sectionID, nextEntry) {
+		s.releasePromotedSeat(ctx, nextEntry.StudentID, sectionID, counter.Pool)
+		s.restoreWaitlistEntry(ctx, sectionID, nextEntry, true)
+		return nil
+	}
 
-	if err := s.cacheService.RemoveFromWaitlist(ctx, sectionID, nextEntry.StudentID); err != nil {
-		logger.Warn("Failed to remove from Redis waitlist (continuing): %v", err)
+	offer, err := s.offerSeatIfEnabled(ctx, sectionID, nextEntry, counter.Pool, seatFreedAt)
+	if err != nil {
+		s.releasePromotedSeat(ctx, nextEntry.StudentID, sectionID, counter.Pool)
+		s.restoreWaitlistEntry(ctx, sectionID, nextEntry, true)
+		return err
 	}
 
-	dbSyncJob := interfaces.DatabaseSyncJob{
-		JobType:   interfaces.JobTypeCreateRegistration,
-		StudentID: nextEntry.StudentID,
-		SectionID: sectionID,
-		Timestamp: time.Now(),
+	if err := s.cacheService.RemoveFromWaitlist(ctx, sectionID, nextEntry.StudentID); err != nil {
+		log.Warn("Failed to remove from Redis waitlist (continuing): %v", err)
 	}
-	if err := s.queueService.EnqueueDatabaseSync(ctx, dbSyncJob); err != nil {
-		logger.Error("Failed to enqueue database sync job for waitlisted student: %v", err)
+
+	// With seat offers enabled the student enrolls only after accepting the offer, unless
+	// they hold the seat of a linked section already
+	if s.seatOfferTTL <= 0 || nextEntry.LinkedSectionID != nil {
+		s.enrollPromotedStudent(ctx, nextEntry.StudentID, sectionID, counter.Pool, seatFreedAt)
 	}
 
 	// Update caches efficiently instead of invalidating
-	s.updateStudentRegistrationCache(ctx, nextEntry.StudentID, sectionID, domain.StatusEnrolled)
 	s.updateStudentWaitlistCache(ctx, nextEntry.StudentID, nextEntry, "remove")
 	s.updateAvailableSectionsCacheForSection(ctx, sectionID, newSeatCount)
+	s.compactWaitlist(ctx, sectionID)
+	s.notifyPromotion(ctx, sectionID, nextEntry.StudentID, offer)
 
-	logger.Info("Successfully processed waitlist entry from database for student %s in section %s, remaining seats: %d",
+	log.Info("Successfully processed waitlist entry from database for student %s in section %s, remaining seats: %d",
 		nextEntry.StudentID, sectionID, newSeatCount)
 
 	return nil
 }
 
+// restoreWaitlistEntry puts a removed entry back in its place when its promotion cannot go
+// through, the promoted seat having been given back already. fromDB says the entry was
+// removed from the waitlist table rather than the Redis waitlist.
+func (s *RegistrationService) restoreWaitlistEntry(ctx context.Context, sectionID uuid.UUID, entry *domain.WaitlistEntry, fromDB bool) {
+	var err error
+	if fromDB {
+		err = s.waitlistRepo.Create(ctx, entry)
+	} else {
+		err = s.cacheService.AddToWaitlist(ctx, sectionID, entry.StudentID, entry.Position, entry, 0)
+	}
+	if err != nil {
+		registrationLog(ctx, entry.StudentID, sectionID).Error("Failed to put student %s back on the waitlist of section %s after a failed promotion: %v", entry.StudentID, sectionID, err)
+	}
+}
+
+// enrollPromotedStudent records the enrollment of a promoted student in the seat taken from
+// pool. A non-zero seatFreedAt travels with the job so the promotion latency is observed
+// once the record exists.
+func (s *RegistrationService) enrollPromotedStudent(ctx context.Context, studentID, sectionID uuid.UUID, pool *uuid.UUID, seatFreedAt time.Time) {
+	log := registrationLog(ctx, studentID, sectionID)
+	dbSyncJob := interfaces.DatabaseSyncJob{
+		JobType:    interfaces.JobTypePromoteRegistration,
+		StudentID:  studentID,
+		SectionID:  sectionID,
+		SeatPoolID: pool,
+		Timestamp:  time.Now(),
+	}
+	if !seatFreedAt.IsZero() {
+		dbSyncJob.SeatFreedAt = &seatFreedAt
+	}
+	if err := s.queueService.EnqueueDatabaseSync(ctx, dbSyncJob); err != nil {
+		log.Error("Failed to enqueue database sync job for waitlisted student: %v", err)
+	}
+
+	s.updateStudentRegistrationCache(ctx, studentID, sectionID, domain.StatusEnrolled)
+	s.countKPI(ctx, interfaces.KPIRegistrations, sectionID)
+}
+
 // Smart cache update methods
 
 func (s *RegistrationService) updateStudentRegistrationCache(ctx context.Context, studentID, sectionID uuid.UUID, status domain.RegistrationStatus) {
+	log := registrationLog(ctx, studentID, sectionID)
+	s.invalidateStudentSchedule(ctx, studentID, sectionID)
 	// Get current cached registrations
-	cached, err := s.cacheService.GetStudentRegistrations(ctx, studentID)
-	if err != nil {
+	registrations, ok := readCachedView[[]*domain.Registration](ctx, s.cacheService.GetStudentRegistrations, "registrations", studentID)
+	if !ok {
 		// If no cache exists, we'll let it be populated on next read
 		return
 	}
 
-	var registrations []*domain.Registration
-	if rawJSON, ok := cached.(json.RawMessage); ok {
-		if err := json.Unmarshal(rawJSON, &registrations); err != nil {
-			logger.Warn("Failed to unmarshal cached registrations for student %s: %v", studentID, err)
-			return
-		}
-	} else {
-		logger.Warn("Failed to cast cached registrations for student %s to json.RawMessage", studentID)
-		return
-	}
-
 	// Find and update the specific registration
 	found := false
 	for _, reg := range registrations {
@@ -640,25 +1118,15 @@ func (s *RegistrationService) updateStudentRegistrationCache(ctx context.Context
 
 	// Update cache with modified data
 	if err := s.cacheService.SetStudentRegistrations(ctx, studentID, registrations, StudentRegistrationsTTL); err != nil {
-		logger.Warn("Failed to update student registrations cache for %s: %v", studentID, err)
+		log.Warn("Failed to update student registrations cache for %s: %v", studentID, err)
 	}
 }
 
 func (s *RegistrationService) updateStudentWaitlistCache(ctx context.Context, studentID uuid.UUID, entry *domain.WaitlistEntry, action string) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
 	// Get current cached waitlist
-	cached, err := s.cacheService.GetStudentWaitlistStatus(ctx, studentID)
-	if err != nil {
-		return
-	}
-
-	var waitlistEntries []*domain.WaitlistEntry
-	if rawJSON, ok := cached.(json.RawMessage); ok {
-		if err := json.Unmarshal(rawJSON, &waitlistEntries); err != nil {
-			logger.Warn("Failed to unmarshal cached waitlist status for student %s: %v", studentID, err)
-			return
-		}
-	} else {
-		logger.Warn("Failed to cast cached waitlist status for student %s to json.RawMessage", studentID)
+	waitlistEntries, ok := readCachedView[[]*domain.WaitlistEntry](ctx, s.cacheService.GetStudentWaitlistStatus, "waitlist status", studentID)
+	if !ok {
 		return
 	}
 
@@ -673,30 +1141,39 @@ func (s *RegistrationService) updateStudentWaitlistCache(ctx context.Context, st
 				break
 			}
 		}
+	case "reposition":
+		found := false
+		for _, we := range waitlistEntries {
+			if we.SectionID == entry.SectionID && we.StudentID == entry.StudentID {
+				we.Position = entry.Position
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
 	}
 
 	// Update cache with modified data
 	if err := s.cacheService.SetStudentWaitlistStatus(ctx, studentID, waitlistEntries, StudentWaitlistTTL); err != nil {
-		logger.Warn("Failed to update student waitlist cache for %s: %v", studentID, err)
+		log.Warn("Failed to update student waitlist cache for %s: %v", studentID, err)
 	}
 }
 
+// updateAvailableSectionsCacheForSection patches the seat count of one section in its
+// semester's cached list of available sections
 func (s *RegistrationService) updateAvailableSectionsCacheForSection(ctx context.Context, sectionID uuid.UUID, newSeatCount int) {
-	semesterID := uuid.MustParse("e093bb58-78e2-4985-bb7f-7a9b36c9102d")
-	cached, err := s.cacheService.GetAvailableSections(ctx, semesterID)
-	if err != nil {
-	
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil || section == nil {
+		log.Warn("Failed to find the semester of section %s for the available sections cache: %v", sectionID, err)
 		return
 	}
+	semesterID := section.SemesterID
 
-	var sections []*domain.Section
-	if rawJSON, ok := cached.(json.RawMessage); ok {
-		if err := json.Unmarshal(rawJSON, &sections); err != nil {
-			logger.Warn("Failed to unmarshal cached available sections for semester %s: %v", semesterID, err)
-			return
-		}
-	} else {
-		logger.Warn("Failed to cast cached available sections for semester %s to json.RawMessage", semesterID)
+	sections, ok := readCachedView[[]*domain.Section](ctx, s.cacheService.GetAvailableSections, "available sections", semesterID)
+	if !ok {
 		return
 	}
 
@@ -711,12 +1188,9 @@ func (s *RegistrationService) updateAvailableSectionsCacheForSection(ctx context
 	}
 
 	// If section not found in cache and has available seats, add it
-	if !sectionFound && newSeatCount > 0 {
-		section, err := s.sectionRepo.GetByID(ctx, sectionID)
-		if err == nil && section != nil && section.SemesterID == semesterID {
-			section.AvailableSeats = newSeatCount
-			sections = append(sections, section)
-		}
+	if !sectionFound && newSeatCount > 0 && section.IsActive && section.Course.Active {
+		section.AvailableSeats = newSeatCount
+		sections = append(sections, section)
 	}
 
 	// Filter sections that still have available seats
@@ -729,25 +1203,17 @@ func (s *RegistrationService) updateAvailableSectionsCacheForSection(ctx context
 
 	// Update cache with modified data
 	if err := s.cacheService.SetAvailableSections(ctx, semesterID, availableSections, AvailableSectionsTTL); err != nil {
-		logger.Warn("Failed to update available sections cache for semester %s: %v", semesterID, err)
+		log.Warn("Failed to update available sections cache for semester %s: %v", semesterID, err)
 	}
 }
 
 func (s *RegistrationService) GetStudentRegistrations(ctx context.Context, studentID uuid.UUID) ([]*domain.Registration, error) {
-	logger.Info("Getting registrations for student %s", studentID)
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	log.Info("Getting registrations for student %s", studentID)
 
-	cached, err := s.cacheService.GetStudentRegistrations(ctx, studentID)
-	if err == nil {
-		logger.Info("Found cached registrations for student %s", studentID)
-		if rawJSON, ok := cached.(json.RawMessage); ok {
-			var registrations []*domain.Registration
-			if err := json.Unmarshal(rawJSON, &registrations); err == nil {
-				return registrations, nil
-			}
-			logger.Warn("Failed to unmarshal cached registrations for student %s: %v", studentID, err)
-		} else {
-			logger.Warn("Failed to cast cached registrations for student %s to json.RawMessage", studentID)
-		}
+	if registrations, ok := readCachedView[[]*domain.Registration](ctx, s.cacheService.GetStudentRegistrations, "registrations", studentID); ok {
+		log.Info("Found cached registrations for student %s", studentID)
+		return registrations, nil
 	}
 
 	registrations, err := s.registrationRepo.GetByStudentID(ctx, studentID)
@@ -756,30 +1222,31 @@ func (s *RegistrationService) GetStudentRegistrations(ctx context.Context, stude
 	}
 
 	if err := s.cacheService.SetStudentRegistrations(ctx, studentID, registrations, StudentRegistrationsTTL); err != nil {
-		logger.Warn("Failed to cache student registrations for %s: %v", studentID, err)
+		log.Warn("Failed to cache student registrations for %s: %v", studentID, err)
 	}
 
 	return registrations, nil
 }
 
 func (s *RegistrationService) GetStudentWaitlistStatus(ctx context.Context, studentID uuid.UUID) ([]*domain.WaitlistEntry, error) {
-	logger.Info("Getting waitlist status for student %s", studentID)
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	log.Info("Getting waitlist status for student %s", studentID)
 
 	waitlistData, err := s.cacheService.GetStudentWaitlists(ctx, studentID)
 	if err == nil && len(waitlistData) > 0 {
-		logger.Info("Found Redis waitlist status for student %s", studentID)
+		log.Info("Found Redis waitlist status for student %s", studentID)
 
 		waitlistEntries := make([]*domain.WaitlistEntry, 0, len(waitlistData))
 		for _, data := range waitlistData {
 			entryBytes, err := json.Marshal(data)
 			if err != nil {
-				logger.Warn("Failed to marshal waitlist entry from Redis: %v", err)
+				log.Warn("Failed to marshal waitlist entry from Redis: %v", err)
 				continue
 			}
 
 			var entry domain.WaitlistEntry
 			if err := json.Unmarshal(entryBytes, &entry); err != nil {
-				logger.Warn("Failed to unmarshal waitlist entry from Redis: %v", err)
+				log.Warn("Failed to unmarshal waitlist entry from Redis: %v", err)
 				continue
 			}
 
@@ -788,79 +1255,63 @@ func (s *RegistrationService) GetStudentWaitlistStatus(ctx context.Context, stud
 
 		if len(waitlistEntries) > 0 {
 			if err := s.cacheService.SetStudentWaitlistStatus(ctx, studentID, waitlistEntries, StudentWaitlistTTL); err != nil {
-				logger.Warn("Failed to cache student waitlist status backup for %s: %v", studentID, err)
+				log.Warn("Failed to cache student waitlist status backup for %s: %v", studentID, err)
 			}
 			return waitlistEntries, nil
 		}
 	}
 
-	cached, err := s.cacheService.GetStudentWaitlistStatus(ctx, studentID)
-	if err == nil {
-		logger.Info("Found cached waitlist status for student %s", studentID)
-		if rawJSON, ok := cached.(json.RawMessage); ok {
-			var waitlistEntries []*domain.WaitlistEntry
-			if err := json.Unmarshal(rawJSON, &waitlistEntries); err == nil {
-				return waitlistEntries, nil
-			}
-			logger.Warn("Failed to unmarshal cached waitlist status for %s: %v", studentID, err)
-		} else {
-			logger.Warn("Failed to cast cached waitlist status for student %s to json.RawMessage", studentID)
-		}
+	if waitlistEntries, ok := readCachedView[[]*domain.WaitlistEntry](ctx, s.cacheService.GetStudentWaitlistStatus, "waitlist status", studentID); ok {
+		log.Info("Found cached waitlist status for student %s", studentID)
+		return waitlistEntries, nil
 	}
 
 	if s.waitlistFallbackEnabled {
-		logger.Info("Fetching waitlist status from database for student %s", studentID)
+		log.Info("Fetching waitlist status from database for student %s", studentID)
 		waitlistEntries, err := s.waitlistRepo.GetByStudentID(ctx, studentID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get student waitlist status: %w", err)
 		}
 
 		if err := s.cacheService.SetStudentWaitlistStatus(ctx, studentID, waitlistEntries, StudentWaitlistTTL); err != nil {
-			logger.Warn("Failed to cache student waitlist status for %s: %v", studentID, err)
+			log.Warn("Failed to cache student waitlist status for %s: %v", studentID, err)
 		}
 
 		for _, entry := range waitlistEntries {
-			if err := s.cacheService.AddToWaitlist(ctx, entry.SectionID, entry.StudentID, entry.Position, entry); err != nil {
-				logger.Warn("Failed to populate Redis waitlist for student %s, section %s: %v", studentID, entry.SectionID, err)
+			if err := s.cacheService.AddToWaitlist(ctx, entry.SectionID, entry.StudentID, entry.Position, entry, 0); err != nil {
+				log.Warn("Failed to populate Redis waitlist for student %s, section %s: %v", studentID, entry.SectionID, err)
 			}
 		}
 
 		return waitlistEntries, nil
 	}
 
-	logger.Info("No waitlist data found for student %s and database fallback is disabled", studentID)
+	log.Info("No waitlist data found for student %s and database fallback is disabled", studentID)
 	return []*domain.WaitlistEntry{}, nil
 }
 
 func (s *RegistrationService) GetAvailableSections(ctx context.Context, semesterID uuid.UUID) ([]*domain.Section, error) {
-	logger.Info("Getting available sections for semester %s", semesterID)
-
-	cached, err := s.cacheService.GetAvailableSections(ctx, semesterID)
-	if err == nil {
-		logger.Info("Found cached available sections for semester %s", semesterID)
-		if rawJSON, ok := cached.(json.RawMessage); ok {
-			var sections []*domain.Section
-			if err := json.Unmarshal(rawJSON, &sections); err == nil {
-				// Update with real-time seat counts from cache
-				updatedSections := make([]*domain.Section, 0, len(sections))
-				for _, section := range sections {
-					if cachedSeats, cacheErr := s.cacheService.GetAvailableSeats(ctx, section.SectionID); cacheErr == nil {
-						// Create a copy to avoid modifying the cached object
-						updatedSection := *section
-						updatedSection.AvailableSeats = cachedSeats
-						if updatedSection.AvailableSeats > 0 {
-							updatedSections = append(updatedSections, &updatedSection)
-						}
-					} else if section.AvailableSeats > 0 {
-						updatedSections = append(updatedSections, section)
-					}
+	log := logger.FromContext(ctx)
+	log.Info("Getting available sections for semester %s", semesterID)
+
+	if sections, ok := readCachedView[[]*domain.Section](ctx, s.cacheService.GetAvailableSections, "available sections", semesterID); ok {
+		log.Info("Found cached available sections for semester %s", semesterID)
+		// Update with real-time seat counts from cache
+		seats := liveSeats(ctx, s.cacheService, sections)
+		updatedSections := make([]*domain.Section, 0, len(sections))
+		for _, section := range sections {
+			if cachedSeats, ok := seats[section.SectionID]; ok {
+				// Create a copy to avoid modifying the cached object
+				updatedSection := *section
+				updatedSection.AvailableSeats = s.checkedSeatCount(section, cachedSeats)
+				if updatedSection.AvailableSeats > 0 {
+					updatedSections = append(updatedSections, &updatedSection)
 				}
-				return updatedSections, nil
+			} else if section.AvailableSeats > 0 {
+				updatedSections = append(updatedSections, section)
 			}
-			logger.Warn("Failed to unmarshal cached available sections for semester %s: %v", semesterID, err)
-		} else {
-			logger.Warn("Failed to cast cached available sections for semester %s to json.RawMessage", semesterID)
 		}
+		return updatedSections, nil
 	}
 
 	sections, err := s.sectionRepo.GetBySemester(ctx, semesterID)
@@ -868,11 +1319,15 @@ func (s *RegistrationService) GetAvailableSections(ctx context.Context, semester
 		return nil, fmt.Errorf("failed to get available sections: %w", err)
 	}
 
+	seats := liveSeats(ctx, s.cacheService, sections)
 	availableSections := make([]*domain.Section, 0)
 	for _, section := range sections {
+		if !section.IsActive {
+			continue
+		}
 
-		if cachedSeats, cacheErr := s.cacheService.GetAvailableSeats(ctx, section.SectionID); cacheErr == nil {
-			section.AvailableSeats = cachedSeats
+		if cachedSeats, ok := seats[section.SectionID]; ok {
+			section.AvailableSeats = s.checkedSeatCount(section, cachedSeats)
 		}
 
 		if section.AvailableSeats > 0 {
@@ -881,27 +1336,19 @@ func (s *RegistrationService) GetAvailableSections(ctx context.Context, semester
 	}
 
 	if err := s.cacheService.SetAvailableSections(ctx, semesterID, availableSections, AvailableSectionsTTL); err != nil {
-		logger.Warn("Failed to cache available sections for semester %s: %v", semesterID, err)
+		log.Warn("Failed to cache available sections for semester %s: %v", semesterID, err)
 	}
 
 	return availableSections, nil
 }
 
 func (s *RegistrationService) GetStudentDetails(ctx context.Context, studentID uuid.UUID) (*domain.Student, error) {
-	logger.Info("Getting student details for %s", studentID)
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	log.Info("Getting student details for %s", studentID)
 
-	cached, err := s.cacheService.GetStudentDetails(ctx, studentID)
-	if err == nil {
-		logger.Info("Found cached student details for %s", studentID)
-		if rawJSON, ok := cached.(json.RawMessage); ok {
-			var student domain.Student
-			if err := json.Unmarshal(rawJSON, &student); err == nil {
-				return &student, nil
-			}
-			logger.Warn("Failed to unmarshal cached student details for %s: %v", studentID, err)
-		} else {
-			logger.Warn("Failed to cast cached student details for %s to json.RawMessage", studentID)
-		}
+	if student, ok := readCachedView[*domain.Student](ctx, s.cacheService.GetStudentDetails, "student details", studentID); ok {
+		log.Info("Found cached student details for %s", studentID)
+		return student, nil
 	}
 
 	student, err := s.studentRepo.GetByID(ctx, studentID)
@@ -910,33 +1357,31 @@ func (s *RegistrationService) GetStudentDetails(ctx context.Context, studentID u
 	}
 
 	if student == nil {
-		return nil, fmt.Errorf("student not found")
+		return nil, ErrStudentNotFound
 	}
 
 	if err := s.cacheService.SetStudentDetails(ctx, studentID, student, StudentDetailsTTL); err != nil {
-		logger.Warn("Failed to cache student details for %s: %v", studentID, err)
+		log.Warn("Failed to cache student details for %s: %v", studentID, err)
 	}
 
 	return student, nil
 }
 
 func (s *RegistrationService) GetCourseDetails(ctx context.Context, courseID uuid.UUID) (*domain.Course, error) {
-	logger.Info("Getting course details for %s", courseID)
+	log := logger.FromContext(ctx)
+	log.Info("Getting course details for %s", courseID)
 
-	cached, err := s.cacheService.GetCourseDetails(ctx, courseID)
-	if err == nil {
-		logger.Info("Found cached course details for %s", courseID)
-		if course, ok := cached.(*domain.Course); ok {
-			return course, nil
-		}
-		logger.Warn("Failed to cast cached course details for %s", courseID)
+	if course, ok := readCachedView[*domain.Course](ctx, s.cacheService.GetCourseDetails, "course details", courseID); ok {
+		log.Info("Found cached course details for %s", courseID)
+		return course, nil
 	}
 
 	return nil, fmt.Errorf("course repository not available in registration service")
 }
 
 func (s *RegistrationService) GetSectionDetails(ctx context.Context, sectionID uuid.UUID) (*domain.Section, error) {
-	logger.Info("Getting section details for %s", sectionID)
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	log.Info("Getting section details for %s", sectionID)
 
 	section, err := s.sectionRepo.GetByID(ctx, sectionID)
 	if err != nil {
@@ -949,13 +1394,14 @@ func (s *RegistrationService) GetSectionDetails(ctx context.Context, sectionID u
 
 	// Update with real-time seat count from cache
 	if cachedSeats, cacheErr := s.cacheService.GetAvailableSeats(ctx, sectionID); cacheErr == nil {
-		section.AvailableSeats = cachedSeats
+		section.AvailableSeats = s.checkedSeatCount(section, cachedSeats)
 	}
 
 	return section, nil
 }
 
 func (s *RegistrationService) RefreshSectionCache(ctx context.Context, sectionID uuid.UUID) error {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
 	// Get fresh section data from database
 	section, err := s.sectionRepo.GetByID(ctx, sectionID)
 	if err != nil {
@@ -968,99 +1414,86 @@ func (s *RegistrationService) RefreshSectionCache(ctx context.Context, sectionID
 
 	// Update with current cached seat count
 	if cachedSeats, cacheErr := s.cacheService.GetAvailableSeats(ctx, sectionID); cacheErr == nil {
-		section.AvailableSeats = cachedSeats
+		section.AvailableSeats = s.checkedSeatCount(section, cachedSeats)
 	}
 
 	// Update available sections cache for the semester
 	s.updateAvailableSectionsCacheForSection(ctx, sectionID, section.AvailableSeats)
 
-	logger.Info("Successfully refreshed cache for section %s", sectionID)
+	log.Info("Successfully refreshed cache for section %s", sectionID)
 	return nil
 }
 
-func (s *RegistrationService) RefreshAllSectionCaches(ctx context.Context) error {
-	logger.Info("Starting bulk refresh of all section seat caches")
-
-	// Get all active sections from database
-	sections, err := s.sectionRepo.GetAllActive(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get active sections: %w", err)
-	}
-
-	cached := 0
-	failed := 0
-
-	for _, section := range sections {
-		if err := s.cacheService.SetAvailableSeats(ctx, section.SectionID, section.AvailableSeats, 24*time.Hour); err != nil {
-			logger.Warn("Failed to cache seats for section %s: %v", section.SectionID, err)
-			failed++
-			continue
-		}
-		cached++
-	}
-
-	logger.Info("Bulk cache refresh completed: %d sections cached, %d failed", cached, failed)
-
-	if failed > 0 {
-		return fmt.Errorf("failed to cache %d sections", failed)
-	}
-
-	return nil
+// RefreshAllSectionCaches reseeds the seat counters and available sections lists of every
+// active semester from the database
+func (s *RegistrationService) RefreshAllSectionCaches(ctx context.Context) ([]SemesterWarmup, error) {
+	log := logger.FromContext(ctx)
+	log.Info("Starting bulk refresh of all section seat caches")
+	return s.sectionCacheWarmer.WarmActiveSemesters(ctx)
 }
 
-func (s *RegistrationService) InvalidateStudentCaches(ctx context.Context, studentID uuid.UUID) {
-	// Only use this when we need to force a cache refresh
-	keys := []string{
-		fmt.Sprintf("student:registrations:%s", studentID.String()),
-		fmt.Sprintf("student:waitlist:%s", studentID.String()),
-		fmt.Sprintf("student:details:%s", studentID.String()),
-	}
-
-	for _, key := range keys {
-		if err := s.cacheService.Delete(ctx, key); err != nil {
-			logger.Warn("Failed to delete cache key %s: %v", key, err)
-		}
+// InvalidateStudentCaches drops every cached view of the student listed in the cache key
+// catalog. Per-semester views are limited to the given semesters, or all of them when none
+// are given. Only use this when we need to force a cache refresh.
+func (s *RegistrationService) InvalidateStudentCaches(ctx context.Context, studentID uuid.UUID, semesterIDs ...uuid.UUID) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	if err := s.cacheService.InvalidateStudentCache(ctx, studentID, semesterIDs...); err != nil {
+		log.Warn("Failed to invalidate caches for student %s: %v", studentID, err)
+		return
 	}
 
-	logger.Info("Invalidated caches for student %s", studentID)
+	log.Info("Invalidated caches for student %s", studentID)
 }
 
 func (s *RegistrationService) WarmupCaches(ctx context.Context, studentID uuid.UUID) error {
+	log := registrationLog(ctx, studentID, uuid.Nil)
 	// Pre-populate caches with fresh data
 	go func() {
 		if _, err := s.GetStudentDetails(ctx, studentID); err != nil {
-			logger.Warn("Failed to warmup student details cache: %v", err)
+			log.Warn("Failed to warmup student details cache: %v", err)
 		}
 
 		if _, err := s.GetStudentRegistrations(ctx, studentID); err != nil {
-			logger.Warn("Failed to warmup student registrations cache: %v", err)
+			log.Warn("Failed to warmup student registrations cache: %v", err)
 		}
 
 		if _, err := s.GetStudentWaitlistStatus(ctx, studentID); err != nil {
-			logger.Warn("Failed to warmup student waitlist cache: %v", err)
+			log.Warn("Failed to warmup student waitlist cache: %v", err)
 		}
 	}()
 
 	return nil
 }
 
+// ErrIdempotencyKeyConflict is returned when an idempotency key is reused for a different
+// request than the one it was first recorded with
+var ErrIdempotencyKeyConflict = errors.New("idempotency key already used with different request data")
+
 func (s *RegistrationService) checkIdempotency(ctx context.Context, key string, studentID uuid.UUID, requestData interface{}) (*domain.IdempotencyKey, bool, error) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
 	if key == "" {
 		return nil, false, nil
 	}
 
+	if s.skipCache() {
+		// Retries are still caught as already registered
+		return nil, false, nil
+	}
 	existingKey, err := s.idempotencyRepo.GetByKey(ctx, key)
 	if err != nil {
 		if err.Error() == "idempotency key not found" {
 			return nil, false, nil
 		}
+		if s.cacheOutage(ctx, "check idempotency key", err) {
+			return nil, false, nil
+		}
 		return nil, false, fmt.Errorf("failed to check idempotency key: %w", err)
 	}
 
 	if existingKey != nil {
 		if existingKey.IsExpired() {
 			if err := s.idempotencyRepo.Delete(ctx, key); err != nil {
-				logger.Warn("Failed to delete expired idempotency key %s: %v", key, err)
+				log.Warn("Failed to delete expired idempotency key %s: %v", key, err)
 			}
 			return nil, false, nil
 		}
@@ -1069,7 +1502,7 @@ func (s *RegistrationService) checkIdempotency(ctx context.Context, key string,
 		if existingKey.RequestHash == requestHash {
 			return existingKey, true, nil
 		} else {
-			return nil, false, fmt.Errorf("idempotency key already used with different request data")
+			return nil, false, ErrIdempotencyKeyConflict
 		}
 	}
 
@@ -1116,6 +1549,7 @@ func (s *RegistrationService) generateRequestHash(studentID uuid.UUID, requestDa
 // ensureSeatCacheInitialized ensures that the seat count for a section is cached in Redis
 // If not cached, it fetches from database and initializes the cache
 func (s *RegistrationService) ensureSeatCacheInitialized(ctx context.Context, sectionID uuid.UUID) error {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
 	// Check if seat count is already cached
 	_, err := s.cacheService.GetAvailableSeats(ctx, sectionID)
 	if err == nil {
@@ -1124,7 +1558,7 @@ func (s *RegistrationService) ensureSeatCacheInitialized(ctx context.Context, se
 	}
 
 	// Not cached, fetch from database and initialize cache
-	logger.Info("Initializing seat cache for section %s from database", sectionID)
+	log.Info("Initializing seat cache for section %s from database", sectionID)
 
 	section, dbErr := s.sectionRepo.GetByID(ctx, sectionID)
 	if dbErr != nil {
@@ -1136,10 +1570,10 @@ func (s *RegistrationService) ensureSeatCacheInitialized(ctx context.Context, se
 	}
 
 	// Initialize cache with current database value and set 24-hour TTL
-	if setErr := s.cacheService.SetAvailableSeats(ctx, sectionID, section.AvailableSeats, 24*time.Hour); setErr != nil {
+	if setErr := s.cacheService.SetSeatCounter(ctx, sectionID, sectionSeatCounter(section), 24*time.Hour); setErr != nil {
 		return fmt.Errorf("failed to initialize seat cache: %w", setErr)
 	}
 
-	logger.Info("Successfully initialized seat cache for section %s with %d seats", sectionID, section.AvailableSeats)
+	log.Info("Successfully initialized seat cache for section %s with %d seats", sectionID, section.AvailableSeats)
 	return nil
 }
@@ -0,0 +1,196 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotOnWaitlist = errors.New("student is not on the waitlist for this section")
+
+// waitlistExpirySweepBatchSize bounds how many expired entries one sweep removes
+const waitlistExpirySweepBatchSize = 100
+
+// LeaveWaitlist takes a student off a section waitlist at their own request. Students behind
+// them move up one place in Redis right away and in the waitlist repository via compaction.
+func (s *RegistrationService) LeaveWaitlist(ctx context.Context, studentID, sectionID uuid.UUID) error {
+	log := registrationLog(ctx, studentID, sectionID)
+	log.Info("Processing waitlist removal for student %s and section %s", studentID, sectionID)
+
+	entry, err := s.waitlistRepo.GetByStudentAndSection(ctx, studentID, sectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+
+	position, err := s.cacheService.LeaveWaitlist(ctx, sectionID, studentID)
+	if err != nil {
+		if !s.waitlistFallbackEnabled {
+			return fmt.Errorf("failed to leave Redis waitlist and fallback is disabled: %w", err)
+		}
+		log.Warn("Failed to leave Redis waitlist, continuing with database: %v", err)
+		position = -1
+	}
+
+	if entry == nil && position < 0 {
+		return ErrNotOnWaitlist
+	}
+
+	if entry != nil {
+		if position < 0 {
+			position = entry.Position
+		}
+
+		if err := s.waitlistRepo.Delete(ctx, entry.WaitlistID); err != nil {
+			return fmt.Errorf("failed to delete waitlist entry: %w", err)
+		}
+	} else {
+		entry = &domain.WaitlistEntry{
+			StudentID: studentID,
+			SectionID: sectionID,
+			Position:  position,
+		}
+	}
+
+	if err := s.recordEvent(ctx, domain.EventLeftWaitlist, studentID, sectionID, &position, time.Now()); err != nil {
+		log.Error("Failed to record waitlist removal event for student %s: %v", studentID, err)
+	}
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditWaitlistRemoved,
+		StudentID: studentID,
+		SectionID: sectionID,
+		Before:    map[string]any{"status": domain.StatusWaitlisted, "waitlist_position": position},
+	})
+
+	s.updateStudentWaitlistCache(ctx, studentID, entry, "remove")
+	s.compactWaitlist(ctx, sectionID)
+
+	log.Info("Student %s left the waitlist for section %s at position %d", studentID, sectionID, position)
+	return nil
+}
+
+// waitlistExpiry returns when an entry that joins the section's waitlist at joinedAt expires,
+// or nil when the section's entries do not expire. The section's own lifetime wins over the
+// configured default.
+func (s *RegistrationService) waitlistExpiry(ctx context.Context, sectionID uuid.UUID, joinedAt time.Time) *time.Time {
+	ttl := s.waitlistTTL
+	if section, err := s.getSectionMetadata(ctx, sectionID); err == nil && section != nil && section.WaitlistTTLHours != nil {
+		ttl = time.Duration(*section.WaitlistTTLHours) * time.Hour
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	expiresAt := joinedAt.Add(ttl)
+	return &expiresAt
+}
+
+// ExpireWaitlistEntries takes students whose waitlist entry has expired off the waitlist and
+// tells them; the students behind them move up. It is run periodically by the scheduler.
+func (s *RegistrationService) ExpireWaitlistEntries(ctx context.Context) error {
+	log := logger.FromContext(ctx)
+	entries, err := s.waitlistRepo.GetExpired(ctx, time.Now(), waitlistExpirySweepBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expired waitlist entries: %w", err)
+	}
+
+	expired := 0
+	sections := make(map[uuid.UUID]bool)
+	for _, entry := range entries {
+		if s.expireWaitlistEntry(ctx, entry) {
+			expired++
+			sections[entry.SectionID] = true
+		}
+	}
+	for sectionID := range sections {
+		s.compactWaitlist(ctx, sectionID)
+	}
+
+	if expired > 0 {
+		log.Info("Expired %d waitlist entries in %d sections", expired, len(sections))
+	}
+	return nil
+}
+
+// expireWaitlistEntry removes one expired entry from Redis and the waitlist repository. An
+// entry that cannot be removed from Redis is left for the next sweep, so the two stay in step.
+func (s *RegistrationService) expireWaitlistEntry(ctx context.Context, entry *domain.WaitlistEntry) bool {
+	log := registrationLog(ctx, entry.StudentID, entry.SectionID)
+
+	position, err := s.cacheService.LeaveWaitlist(ctx, entry.SectionID, entry.StudentID)
+	if err != nil {
+		log.Warn("Failed to remove expired waitlist entry %s from Redis, retrying next sweep: %v", entry.WaitlistID, err)
+		return false
+	}
+	if position < 0 {
+		position = entry.Position
+	}
+
+	if err := s.waitlistRepo.Delete(ctx, entry.WaitlistID); err != nil {
+		log.Error("Failed to delete expired waitlist entry %s: %v", entry.WaitlistID, err)
+		return false
+	}
+
+	if err := s.recordEvent(ctx, domain.EventLeftWaitlist, entry.StudentID, entry.SectionID, &position, time.Now()); err != nil {
+		log.Error("Failed to record waitlist expiry event for student %s: %v", entry.StudentID, err)
+	}
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditWaitlistExpired,
+		StudentID: entry.StudentID,
+		SectionID: entry.SectionID,
+		Before:    map[string]any{"status": domain.StatusWaitlisted, "waitlist_position": position, "expires_at": entry.ExpiresAt},
+	})
+
+	s.updateStudentWaitlistCache(ctx, entry.StudentID, entry, "remove")
+	s.notifyWaitlistExpired(ctx, entry)
+
+	log.Info("Waitlist entry of student %s for section %s expired at position %d", entry.StudentID, entry.SectionID, position)
+	return true
+}
+
+// compactWaitlist closes the gaps that removals leave in a section waitlist. Redis is
+// renumbered right away and the database copy by a sync job; students who moved get their
+// cached waitlist status updated from whichever side renumbered them.
+func (s *RegistrationService) compactWaitlist(ctx context.Context, sectionID uuid.UUID) {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	moved, err := s.cacheService.CompactWaitlist(ctx, sectionID)
+	if err != nil {
+		log.Warn("Failed to compact Redis waitlist for section %s: %v", sectionID, err)
+	}
+	for studentID, position := range moved {
+		s.updateStudentWaitlistCache(ctx, studentID, &domain.WaitlistEntry{
+			StudentID: studentID,
+			SectionID: sectionID,
+			Position:  position,
+		}, "reposition")
+	}
+
+	job := interfaces.DatabaseSyncJob{
+		JobType:   interfaces.JobTypeCompactWaitlist,
+		SectionID: sectionID,
+		Timestamp: time.Now(),
+	}
+	if err := s.queueService.EnqueueDatabaseSync(ctx, job); err != nil {
+		log.Error("Failed to enqueue waitlist compaction for section %s: %v", sectionID, err)
+	}
+}
+
+func (s *RegistrationService) renumberWaitlist(ctx context.Context, sectionID uuid.UUID) error {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	moved, err := s.waitlistRepo.RenumberPositions(ctx, sectionID)
+	if err != nil {
+		return fmt.Errorf("failed to renumber waitlist: %w", err)
+	}
+
+	for _, entry := range moved {
+		s.updateStudentWaitlistCache(ctx, entry.StudentID, entry, "reposition")
+	}
+
+	log.Info("Renumbered %d waitlist entries for section %s", len(moved), sectionID)
+	return nil
+}
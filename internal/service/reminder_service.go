@@ -0,0 +1,212 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderService reminds students ahead of the add/drop deadline while they can still act:
+// those still on a waitlist, and those who registered for the semester but hold fewer than
+// minEnrolled enrolled sections. Reminders run as delayed jobs on the queue.
+type ReminderService struct {
+	semesterService  *SemesterService
+	sectionRepo      interfaces.SectionRepository
+	registrationRepo interfaces.RegistrationRepository
+	waitlistRepo     interfaces.WaitlistRepository
+	studentRepo      interfaces.StudentRepository
+	studentNotifier  interfaces.StudentNotifier
+	queueService     interfaces.QueueService
+	leadTimes        []time.Duration
+	minEnrolled      int
+}
+
+func NewReminderService(
+	semesterService *SemesterService,
+	sectionRepo interfaces.SectionRepository,
+	registrationRepo interfaces.RegistrationRepository,
+	waitlistRepo interfaces.WaitlistRepository,
+	studentRepo interfaces.StudentRepository,
+	studentNotifier interfaces.StudentNotifier,
+	queueService interfaces.QueueService,
+	leadHours []int,
+	minEnrolled int,
+) *ReminderService {
+	leadTimes := make([]time.Duration, 0, len(leadHours))
+	for _, hours := range leadHours {
+		if hours > 0 {
+			leadTimes = append(leadTimes, time.Duration(hours)*time.Hour)
+		}
+	}
+	return &ReminderService{
+		semesterService:  semesterService,
+		sectionRepo:      sectionRepo,
+		registrationRepo: registrationRepo,
+		waitlistRepo:     waitlistRepo,
+		studentRepo:      studentRepo,
+		studentNotifier:  studentNotifier,
+		queueService:     queueService,
+		leadTimes:        leadTimes,
+		minEnrolled:      minEnrolled,
+	}
+}
+
+// PlanDeadlineReminders schedules a reminder job for every lead time ahead of each active
+// semester's add/drop deadline. Reminders already scheduled are left alone, so planning runs
+// repeatedly and picks up deadlines as registrars add or move them.
+func (s *ReminderService) PlanDeadlineReminders(ctx context.Context) error {
+	semesters, err := s.semesterService.ListSemesters(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, semester := range semesters {
+		calendar, err := s.semesterService.GetCalendar(ctx, semester.SemesterID)
+		if err != nil {
+			logger.Warn("Failed to get calendar of semester %s for reminders: %v", semester.SemesterID, err)
+			continue
+		}
+		deadline, ok := calendar.Deadline(domain.CalendarAddDropDeadline)
+		if !ok || !deadline.After(now) {
+			continue
+		}
+
+		for _, lead := range s.leadTimes {
+			dueAt := deadline.Add(-lead)
+			if !dueAt.After(now) {
+				continue
+			}
+
+			job := interfaces.ReminderJob{
+				ReminderID:   reminderID(semester.SemesterID, domain.CalendarAddDropDeadline, deadline, lead),
+				SemesterID:   semester.SemesterID,
+				DeadlineType: string(domain.CalendarAddDropDeadline),
+				DeadlineAt:   deadline.UTC(),
+			}
+			scheduled, err := s.queueService.ScheduleReminder(ctx, job, dueAt)
+			if err != nil {
+				return err
+			}
+			if scheduled {
+				logger.Info("Scheduled %s reminder for %s at %s", job.DeadlineType, semester.SemesterName, dueAt.Format(time.RFC3339))
+			}
+		}
+	}
+
+	return nil
+}
+
+// SendDeadlineReminders notifies the students of the semester who still have something to
+// sort out before the deadline. A job whose deadline has since moved or been removed is
+// dropped; planning has already scheduled reminders for the new date.
+func (s *ReminderService) SendDeadlineReminders(ctx context.Context, job interfaces.ReminderJob) error {
+	calendar, err := s.semesterService.GetCalendar(ctx, job.SemesterID)
+	if err != nil {
+		return err
+	}
+	deadline, ok := calendar.Deadline(domain.CalendarEventType(job.DeadlineType))
+	if !ok || !deadline.Equal(job.DeadlineAt) {
+		logger.Info("Skipping reminder %s: the %s deadline has changed", job.ReminderID, job.DeadlineType)
+		return nil
+	}
+	if !deadline.After(time.Now()) {
+		logger.Info("Skipping reminder %s: the %s deadline has passed", job.ReminderID, job.DeadlineType)
+		return nil
+	}
+
+	sections, err := s.sectionRepo.GetBySemester(ctx, job.SemesterID)
+	if err != nil {
+		return fmt.Errorf("failed to get sections: %w", err)
+	}
+
+	waitlisted := make(map[uuid.UUID][]uuid.UUID)
+	registered := make(map[uuid.UUID]int)
+	for _, section := range sections {
+		entries, err := s.waitlistRepo.GetBySectionID(ctx, section.SectionID)
+		if err != nil {
+			return fmt.Errorf("failed to get waitlist of section %s: %w", section.SectionID, err)
+		}
+		for _, entry := range entries {
+			waitlisted[entry.StudentID] = append(waitlisted[entry.StudentID], section.SectionID)
+		}
+
+		registrations, err := s.registrationRepo.GetBySectionID(ctx, section.SectionID)
+		if err != nil {
+			return fmt.Errorf("failed to get registrations of section %s: %w", section.SectionID, err)
+		}
+		for _, registration := range registrations {
+			enrolled := registered[registration.StudentID]
+			if registration.Status == domain.StatusEnrolled {
+				enrolled++
+			}
+			registered[registration.StudentID] = enrolled
+		}
+	}
+
+	sent := 0
+	for studentID, sectionIDs := range waitlisted {
+		if !s.wantsReminder(ctx, studentID) {
+			continue
+		}
+		for _, sectionID := range sectionIDs {
+			if s.notify(ctx, job, studentID, sectionID, interfaces.ReminderReasonPendingWaitlist) {
+				sent++
+			}
+		}
+	}
+	for studentID, enrolled := range registered {
+		if enrolled >= s.minEnrolled || len(waitlisted[studentID]) > 0 {
+			continue
+		}
+		if !s.wantsReminder(ctx, studentID) {
+			continue
+		}
+		if s.notify(ctx, job, studentID, uuid.Nil, interfaces.ReminderReasonIncompleteSchedule) {
+			sent++
+		}
+	}
+
+	logger.Info("Sent %d %s reminders for %s", sent, job.DeadlineType, calendar.Semester.SemesterName)
+	return nil
+}
+
+// wantsReminder checks the student's notification preference. Archived students are never
+// reminded.
+func (s *ReminderService) wantsReminder(ctx context.Context, studentID uuid.UUID) bool {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		logger.Warn("Failed to get student %s for reminder: %v", studentID, err)
+		return false
+	}
+	return student != nil && !student.IsArchived() && student.DeadlineReminders
+}
+
+func (s *ReminderService) notify(ctx context.Context, job interfaces.ReminderJob, studentID, sectionID uuid.UUID, reason string) bool {
+	semesterID := job.SemesterID
+	deadlineAt := job.DeadlineAt
+	event := interfaces.StudentEvent{
+		Type:       interfaces.StudentEventDeadlineReminder,
+		StudentID:  studentID,
+		SectionID:  sectionID,
+		SemesterID: &semesterID,
+		DeadlineAt: &deadlineAt,
+		Reason:     reason,
+		OccurredAt: time.Now(),
+	}
+
+	if err := s.studentNotifier.Publish(ctx, event); err != nil {
+		logger.Warn("Failed to remind student %s of the %s deadline: %v", studentID, job.DeadlineType, err)
+		return false
+	}
+	return true
+}
+
+func reminderID(semesterID uuid.UUID, deadlineType domain.CalendarEventType, deadline time.Time, lead time.Duration) string {
+	return fmt.Sprintf("%s:%s:%d:%d", semesterID, deadlineType, deadline.Unix(), int(lead.Hours()))
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"cobra-template/internal/auth"
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrScheduleOverrideNotFound = errors.New("schedule override not found")
+	ErrScheduleOverrideExists   = errors.New("an override for this section is already pending or approved")
+	ErrScheduleOverrideDecided  = errors.New("schedule override has already been decided")
+	ErrNoScheduleConflict       = errors.New("section does not clash with the student's schedule")
+	ErrInvalidOverrideStatus    = errors.New("status must be pending, approved or denied")
+)
+
+// ResultTimeConflict is the registration result status for a section that meets at the same
+// time as one the student is enrolled in and for which no override was approved
+const ResultTimeConflict = "time_conflict"
+
+// maxScheduleOverrideList bounds the advising queue returned in one response
+const maxScheduleOverrideList = 500
+
+type ScheduleOverrideRequest = serviceInterfaces.ScheduleOverrideRequest
+type ScheduleOverrideDecision = serviceInterfaces.ScheduleOverrideDecision
+
+// scheduleConflicts returns the sections the student is enrolled in that meet at the same
+// time as section. Registrations are read from the student's cached view, which includes
+// enrollments the database has not caught up with yet.
+func (s *RegistrationService) scheduleConflicts(ctx context.Context, studentID uuid.UUID, section *domain.Section) ([]*domain.Section, error) {
+	if section.StartTime == "" {
+		return nil, nil
+	}
+
+	registrations, err := s.GetStudentRegistrations(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []*domain.Section
+	for _, registration := range registrations {
+		if registration.Status != domain.StatusEnrolled || registration.SectionID == section.SectionID {
+			continue
+		}
+		enrolled, err := s.getSectionMetadata(ctx, registration.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		if enrolled != nil && section.ClashesWith(enrolled) {
+			conflicts = append(conflicts, enrolled)
+		}
+	}
+	return conflicts, nil
+}
+
+// checkScheduleConflict stops a registration for a section that clashes with the student's
+// schedule unless an advisor approved an override for it. It runs before a seat is taken.
+func (s *RegistrationService) checkScheduleConflict(ctx context.Context, studentID, sectionID uuid.UUID) (RegistrationResult, bool) {
+	log := registrationLog(ctx, studentID, sectionID)
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil || section == nil {
+		// checkSectionOpen already loaded the section, so this only fails if it vanished since
+		return closedSectionResult(sectionID, ErrSectionNotFound), false
+	}
+
+	conflicts, err := s.scheduleConflicts(ctx, studentID, section)
+	if err != nil {
+		log.Error("Failed to check schedule conflicts of student %s for section %s: %v", studentID, sectionID, err)
+		return RegistrationResult{SectionID: sectionID, Status: "failed", Message: "Failed to process registration"}, false
+	}
+	if len(conflicts) == 0 {
+		return RegistrationResult{}, true
+	}
+
+	override, err := s.scheduleOverrideRepo.GetOpen(ctx, studentID, sectionID)
+	if err != nil {
+		log.Error("Failed to get schedule override of student %s for section %s: %v", studentID, sectionID, err)
+		return RegistrationResult{SectionID: sectionID, Status: "failed", Message: "Failed to process registration"}, false
+	}
+	if override != nil && override.Status == domain.ScheduleOverrideApproved {
+		return RegistrationResult{}, true
+	}
+
+	message := fmt.Sprintf("Meets at the same time as %s; request a schedule override to register anyway", describeSections(conflicts))
+	if override != nil {
+		message = fmt.Sprintf("Meets at the same time as %s; the schedule override is waiting for an advisor", describeSections(conflicts))
+	}
+	return RegistrationResult{SectionID: sectionID, Status: ResultTimeConflict, Message: message}, false
+}
+
+// describeSections names sections by course code and section number for messages
+func describeSections(sections []*domain.Section) string {
+	names := make([]string, len(sections))
+	for i, section := range sections {
+		names[i] = strings.TrimSpace(section.Course.CourseCode + " section " + section.SectionNumber)
+	}
+	return strings.Join(names, ", ")
+}
+
+// RequestScheduleOverride asks an advisor to let the student register for a section that
+// clashes with their schedule. The clash must exist when the request is made.
+func (s *RegistrationService) RequestScheduleOverride(ctx context.Context, studentID uuid.UUID, req *ScheduleOverrideRequest) (*domain.ScheduleOverride, error) {
+	log := registrationLog(ctx, studentID, req.SectionID)
+	if err := s.checkStudentNotArchived(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	section, err := s.getSectionMetadata(ctx, req.SectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	existing, err := s.scheduleOverrideRepo.GetOpen(ctx, studentID, req.SectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule override: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleOverrideExists, existing.OverrideID)
+	}
+
+	conflicts, err := s.scheduleConflicts(ctx, studentID, section)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, ErrNoScheduleConflict
+	}
+
+	conflictIDs := make([]uuid.UUID, len(conflicts))
+	for i, conflict := range conflicts {
+		conflictIDs[i] = conflict.SectionID
+	}
+	override := &domain.ScheduleOverride{
+		OverrideID:          uuid.New(),
+		StudentID:           studentID,
+		SectionID:           req.SectionID,
+		ConflictingSections: conflictIDs,
+		Reason:              strings.TrimSpace(req.Reason),
+		Status:              domain.ScheduleOverridePending,
+	}
+	if err := s.scheduleOverrideRepo.Create(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to create schedule override: %w", err)
+	}
+
+	log.Info("Student %s requested schedule override %s for section %s", studentID, override.OverrideID, req.SectionID)
+	return override, nil
+}
+
+func (s *RegistrationService) GetStudentScheduleOverrides(ctx context.Context, studentID uuid.UUID) ([]*domain.ScheduleOverride, error) {
+	overrides, err := s.scheduleOverrideRepo.ListByStudent(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// ListScheduleOverrides returns the overrides in status, oldest first. The advising queue is
+// the pending ones, which is what an empty status lists.
+func (s *RegistrationService) ListScheduleOverrides(ctx context.Context, status domain.ScheduleOverrideStatus) ([]*domain.ScheduleOverride, error) {
+	switch status {
+	case "":
+		status = domain.ScheduleOverridePending
+	case domain.ScheduleOverridePending, domain.ScheduleOverrideApproved, domain.ScheduleOverrideDenied:
+	default:
+		return nil, ErrInvalidOverrideStatus
+	}
+
+	overrides, err := s.scheduleOverrideRepo.ListByStatus(ctx, status, maxScheduleOverrideList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// ApproveScheduleOverride approves a pending override and registers the student for the
+// section straight away. The registration goes through the usual rules apart from the time
+// conflict, so a full section puts the student on its waitlist; the outcome is recorded on
+// the override rather than returned as an error, since the approval stands either way.
+func (s *RegistrationService) ApproveScheduleOverride(ctx context.Context, overrideID uuid.UUID, decision *ScheduleOverrideDecision) (*domain.ScheduleOverride, error) {
+	override, err := s.getScheduleOverride(ctx, overrideID)
+	if err != nil {
+		return nil, err
+	}
+	log := registrationLog(ctx, override.StudentID, override.SectionID)
+
+	unlock, err := s.lockStudent(ctx, override.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := s.decideScheduleOverride(ctx, override, domain.ScheduleOverrideApproved, decision); err != nil {
+		return nil, err
+	}
+
+	var result RegistrationResult
+	if err := s.checkStudentCanRegister(ctx, override.StudentID); err != nil {
+		result = RegistrationResult{SectionID: override.SectionID, Status: "failed", Message: err.Error()}
+	} else {
+		result = s.registerForSection(ctx, override.StudentID, override.SectionID)
+	}
+
+	override.RegistrationStatus = result.Status
+	override.RegistrationMessage = result.Message
+	if err := s.scheduleOverrideRepo.RecordRegistration(ctx, override.OverrideID, result.Status, result.Message); err != nil {
+		log.Warn("Failed to record registration of schedule override %s: %v", override.OverrideID, err)
+	}
+
+	log.Info("Schedule override %s approved by %s; registration %s", override.OverrideID, override.DecidedBy, result.Status)
+	s.notifyScheduleOverride(ctx, override)
+	return override, nil
+}
+
+func (s *RegistrationService) DenyScheduleOverride(ctx context.Context, overrideID uuid.UUID, decision *ScheduleOverrideDecision) (*domain.ScheduleOverride, error) {
+	override, err := s.getScheduleOverride(ctx, overrideID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decideScheduleOverride(ctx, override, domain.ScheduleOverrideDenied, decision); err != nil {
+		return nil, err
+	}
+
+	registrationLog(ctx, override.StudentID, override.SectionID).Info("Schedule override %s denied by %s", override.OverrideID, override.DecidedBy)
+	s.notifyScheduleOverride(ctx, override)
+	return override, nil
+}
+
+func (s *RegistrationService) getScheduleOverride(ctx context.Context, overrideID uuid.UUID) (*domain.ScheduleOverride, error) {
+	override, err := s.scheduleOverrideRepo.GetByID(ctx, overrideID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule override: %w", err)
+	}
+	if override == nil {
+		return nil, ErrScheduleOverrideNotFound
+	}
+	return override, nil
+}
+
+// decideScheduleOverride records the advisor's decision if the override is still pending,
+// so two advisors answering at once cannot both act on it
+func (s *RegistrationService) decideScheduleOverride(ctx context.Context, override *domain.ScheduleOverride, status domain.ScheduleOverrideStatus, decision *ScheduleOverrideDecision) error {
+	var decidedBy string
+	if claims, ok := auth.FromContext(ctx); ok {
+		decidedBy = claims.Subject
+	}
+	note := strings.TrimSpace(decision.Note)
+	now := time.Now()
+
+	decided, err := s.scheduleOverrideRepo.Decide(ctx, override.OverrideID, status, decidedBy, note, now)
+	if err != nil {
+		return err
+	}
+	if !decided {
+		return ErrScheduleOverrideDecided
+	}
+
+	override.Status = status
+	override.DecidedBy = decidedBy
+	override.DecisionNote = note
+	override.DecidedAt = &now
+	return nil
+}
+
+func (s *RegistrationService) notifyScheduleOverride(ctx context.Context, override *domain.ScheduleOverride) {
+	log := registrationLog(ctx, override.StudentID, override.SectionID)
+	if s.studentNotifier == nil {
+		return
+	}
+
+	eventType := interfaces.StudentEventOverrideApproved
+	if override.Status == domain.ScheduleOverrideDenied {
+		eventType = interfaces.StudentEventOverrideDenied
+	}
+	overrideID := override.OverrideID
+	event := interfaces.StudentEvent{
+		Type:       eventType,
+		StudentID:  override.StudentID,
+		SectionID:  override.SectionID,
+		OverrideID: &overrideID,
+		Reason:     override.DecisionNote,
+		OccurredAt: time.Now(),
+	}
+	if err := s.studentNotifier.Publish(ctx, event); err != nil {
+		log.Warn("Failed to notify student %s of %s: %v", override.StudentID, eventType, err)
+	}
+}
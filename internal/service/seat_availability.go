@@ -0,0 +1,60 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SeatChange = interfaces.SeatChange
+
+// WatchSeatAvailability returns the section's current seat count followed by a channel of
+// every later change. The subscription is taken before the count is read so no change in
+// between is lost; the channel closes when ctx is cancelled.
+func (s *RegistrationService) WatchSeatAvailability(ctx context.Context, sectionID uuid.UUID) (*SeatChange, <-chan SeatChange, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, nil, ErrSectionNotFound
+	}
+
+	changes, err := s.cacheService.SubscribeSeatChanges(ctx, sectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seats, err := s.cacheService.GetAvailableSeats(ctx, sectionID)
+	if err != nil {
+		seats = section.AvailableSeats
+	} else {
+		seats = s.checkedSeatCount(section, seats)
+	}
+
+	return &SeatChange{
+		SectionID:      sectionID,
+		AvailableSeats: seats,
+		ChangedAt:      time.Now(),
+	}, changes, nil
+}
+
+// liveSeats reads the seat counters of the sections in one round trip. A section missing
+// from the result keeps its stored seat count, and so do all of them if the read fails.
+func liveSeats(ctx context.Context, cacheService interfaces.CacheService, sections []*domain.Section) map[uuid.UUID]int {
+	ids := make([]uuid.UUID, len(sections))
+	for i, section := range sections {
+		ids[i] = section.SectionID
+	}
+	seats, err := cacheService.GetAvailableSeatsBatch(ctx, ids)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to read the seat counters of %d sections: %v", len(ids), err)
+		return map[uuid.UUID]int{}
+	}
+	return seats
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// seatReconcileTimeout bounds one background reconciliation of a seat counter
+const seatReconcileTimeout = 10 * time.Second
+
+// seatCounterAnomaly reports why a seat counter value cannot be right for the section, or
+// an empty string when it is plausible
+func seatCounterAnomaly(section *domain.Section, seats int) string {
+	switch {
+	case seats < 0:
+		return metrics.SeatAnomalyNegative
+	case seats > section.TotalSeats:
+		return metrics.SeatAnomalyAboveCapacity
+	default:
+		return ""
+	}
+}
+
+// checkedSeatCount returns the seat counter value read for a section when it is plausible.
+// A negative counter or one above capacity is never shown; the database seat count is
+// served instead while the counter is rebuilt in the background.
+func (s *RegistrationService) checkedSeatCount(section *domain.Section, seats int) int {
+	reason := seatCounterAnomaly(section, seats)
+	if reason == "" {
+		return seats
+	}
+
+	metrics.SeatCounterAnomalies.WithLabelValues(reason).Inc()
+	logger.Error("Seat counter for section %s reads %d with %d total seats (%s), reconciling from the database",
+		section.SectionID, seats, section.TotalSeats, reason)
+
+	if _, running := s.seatReconciles.LoadOrStore(section.SectionID, struct{}{}); !running {
+		go func() {
+			defer s.seatReconciles.Delete(section.SectionID)
+			s.reconcileSeatCounter(section.SectionID, seats)
+		}()
+	}
+
+	return min(max(section.AvailableSeats, 0), section.TotalSeats)
+}
+
+// reconcileSeatCounter rebuilds a section's seat counter from the enrolled registrations in
+// the database. The counter is only replaced while it still holds the suspicious value, so
+// a registration that moved it in the meantime is not overwritten.
+func (s *RegistrationService) reconcileSeatCounter(sectionID uuid.UUID, observed int) {
+	ctx, cancel := context.WithTimeout(context.Background(), seatReconcileTimeout)
+	defer cancel()
+
+	seats, err := s.seatsFromDatabase(ctx, sectionID)
+	if err != nil {
+		metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileFailed).Inc()
+		logger.Error("Failed to reconcile seat counter for section %s: %v", sectionID, err)
+		return
+	}
+
+	swapped, err := s.cacheService.CompareAndSetAvailableSeats(ctx, sectionID, observed, seats)
+	if err != nil {
+		metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileFailed).Inc()
+		logger.Error("Failed to reset seat counter for section %s: %v", sectionID, err)
+		return
+	}
+	if !swapped {
+		metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileSkipped).Inc()
+		logger.Warn("Seat counter for section %s changed from %d before it was reconciled, leaving it", sectionID, observed)
+		return
+	}
+
+	metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileCorrected).Inc()
+	logger.Warn("Reconciled seat counter for section %s from %d to %d", sectionID, observed, seats)
+}
+
+// seatsFromDatabase is the section's capacity less the registrations holding a seat, kept
+// within 0..TotalSeats
+func (s *RegistrationService) seatsFromDatabase(ctx context.Context, sectionID uuid.UUID) (int, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return 0, ErrSectionNotFound
+	}
+
+	registrations, err := s.registrationRepo.GetBySectionID(ctx, sectionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get registrations: %w", err)
+	}
+	enrolled := 0
+	for _, registration := range registrations {
+		if registration.Status.HoldsSeat() {
+			enrolled++
+		}
+	}
+
+	return min(max(section.TotalSeats-enrolled, 0), section.TotalSeats), nil
+}
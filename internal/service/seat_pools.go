@@ -0,0 +1,241 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSeatPools  = errors.New("seat pools hold more seats than the section has")
+	ErrDuplicateSeatPool = errors.New("seat pool names must be unique within a section")
+)
+
+// seatRequest says which seats the student may take in the section: everything but its
+// reserved seats, and the pools whose rules admit the student. Students whose details
+// cannot be read are offered the seats no pool holds. While waitlist promotions are paused,
+// seats freed by drops stay on the counter until they resume, so the seats are held for
+// anyone still on the waitlist.
+func (s *RegistrationService) seatRequest(ctx context.Context, studentID, sectionID uuid.UUID) interfaces.SeatRequest {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil || section == nil {
+		return interfaces.SeatRequest{}
+	}
+
+	seat := interfaces.SeatRequest{
+		Reserved:        section.Policy.ReservedSeats,
+		HasPools:        len(section.SeatPools) > 0,
+		HoldForWaitlist: section.WaitlistPromotionsPaused,
+	}
+	if !seat.HasPools {
+		return seat
+	}
+
+	student, err := s.GetStudentDetails(ctx, studentID)
+	if err != nil || student == nil {
+		registrationLog(ctx, studentID, sectionID).Warn("Failed to get details of student %s for seat pools, offering general seats only: %v", studentID, err)
+		return seat
+	}
+	for i := range section.SeatPools {
+		if section.SeatPools[i].Admits(student) {
+			seat.Pools = append(seat.Pools, section.SeatPools[i].PoolID)
+		}
+	}
+	return seat
+}
+
+// reserveSeat takes a seat for the student's enrollment from the seat counter, or from the
+// section row while the database fallback has the counter cut off, and returns the seats
+// the section has left. A reservation the counter failed on is only taken from the section
+// row when the script is known not to have run; after a timeout the counter may have taken
+// the seat already, so the student is told to try again instead.
+func (s *RegistrationService) reserveSeat(ctx context.Context, studentID, sectionID uuid.UUID, seat interfaces.SeatRequest) (*interfaces.SeatCounter, error) {
+	if !s.useSeatCounter(ctx) {
+		return s.reserveSeatFromDatabase(ctx, studentID, sectionID, seat)
+	}
+
+	counter, err := s.reserveSeatFromCounter(ctx, studentID, sectionID, seat, enrollmentJobs(ctx, studentID, sectionID))
+	s.observeSeatCounter(err)
+	if errors.Is(err, interfaces.ErrCacheNotExecuted) && s.fallback != nil {
+		registrationLog(ctx, studentID, sectionID).Warn("Seat counter of section %s unavailable, enrolling from the database: %v", sectionID, err)
+		return s.reserveSeatFromDatabase(ctx, studentID, sectionID, seat)
+	}
+	return counter, err
+}
+
+// takePromotedSeat takes the seat freed for the student at the head of the waitlist from
+// the seat counter, through the seat pools and reserved seats the student may take like an
+// enrollment, and returns the seats the section has left and the pool the seat came from.
+// Promotions wait for the seat counter rather than taking seats from the database.
+func (s *RegistrationService) takePromotedSeat(ctx context.Context, studentID, sectionID uuid.UUID) (*interfaces.SeatCounter, error) {
+	if !s.useSeatCounter(ctx) {
+		return nil, fmt.Errorf("%w: seat counter circuit is open", interfaces.ErrCacheTransient)
+	}
+
+	seat := s.seatRequest(ctx, studentID, sectionID)
+	// The seats held for the waitlist are held for this student
+	seat.HoldForWaitlist = false
+	counter, err := s.reserveSeatFromCounter(ctx, studentID, sectionID, seat, nil)
+	s.observeSeatCounter(err)
+	return counter, err
+}
+
+// releasePromotedSeat gives the seat taken by a promotion that cannot go through back to
+// the section and to the pool it came from
+func (s *RegistrationService) releasePromotedSeat(ctx context.Context, studentID, sectionID uuid.UUID, pool *uuid.UUID) {
+	if _, err := s.cacheService.ReleaseSeat(ctx, sectionID, pool); err != nil {
+		registrationLog(ctx, studentID, sectionID).Error("Failed to give back the promoted seat of section %s: %v", sectionID, err)
+	}
+}
+
+// reserveSeatFromCounter takes the seat from the Redis counter, writing jobs to the outbox
+// with it. A seat counter or pool counters that dropped out of the cache are loaded from
+// the database and the reservation tried again; a section missing both takes a load of each.
+func (s *RegistrationService) reserveSeatFromCounter(ctx context.Context, studentID, sectionID uuid.UUID, seat interfaces.SeatRequest, jobs []interfaces.DatabaseSyncJob) (*interfaces.SeatCounter, error) {
+	for attempt := 1; ; attempt++ {
+		counter, err := s.cacheService.ReserveSeat(ctx, sectionID, seat, jobs)
+		if err == nil || attempt > 2 {
+			return counter, err
+		}
+		reloaded, reloadErr := s.reloadSeatCounters(ctx, studentID, sectionID, err)
+		if reloadErr != nil {
+			return nil, reloadErr
+		}
+		if !reloaded {
+			return counter, err
+		}
+	}
+}
+
+// reloadSeatCounters loads the seat counter or the pool counters of a section from the
+// database when err shows they dropped out of the cache, and reports whether it did
+func (s *RegistrationService) reloadSeatCounters(ctx context.Context, studentID, sectionID uuid.UUID, err error) (bool, error) {
+	if strings.Contains(err.Error(), "seat key not found") {
+		registrationLog(ctx, studentID, sectionID).Info("Seat key not found for section %s, initializing from database", sectionID)
+		if err := s.ensureSeatCacheInitialized(ctx, sectionID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if !errors.Is(err, interfaces.ErrSeatPoolsNotLoaded) {
+		return false, nil
+	}
+
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return false, ErrSectionNotFound
+	}
+	if _, err := loadSeatPools(ctx, s.cacheService, s.registrationRepo, section); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// loadSeatPools sets the pool counters of the section to each pool's seats less those taken
+// by enrolled registrations, and returns them
+func loadSeatPools(ctx context.Context, cacheService interfaces.CacheService, registrationRepo interfaces.RegistrationRepository, section *domain.Section) (map[uuid.UUID]int, error) {
+	taken, err := registrationRepo.CountEnrolledBySeatPool(ctx, section.SectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count seat pool enrollments: %w", err)
+	}
+
+	remaining := make(map[uuid.UUID]int, len(section.SeatPools))
+	for _, pool := range section.SeatPools {
+		remaining[pool.PoolID] = max(pool.Seats-taken[pool.PoolID], 0)
+	}
+	if err := cacheService.SetSeatPools(ctx, section.SectionID, remaining, sectionSeatsTTL); err != nil {
+		return nil, err
+	}
+	return remaining, nil
+}
+
+// SetSeatPools replaces the seat pools of a section and reloads their counters. Seats
+// already taken from a pool that keeps its name still count against it.
+func (s *SectionService) SetSeatPools(ctx context.Context, sectionID uuid.UUID, req *SetSeatPoolsRequest) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	pools := make([]domain.SeatPool, 0, len(req.Pools))
+	names := make(map[string]bool, len(req.Pools))
+	total := 0
+	for _, pool := range req.Pools {
+		name := strings.TrimSpace(pool.Name)
+		if names[name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSeatPool, name)
+		}
+		names[name] = true
+		total += pool.Seats
+
+		pools = append(pools, domain.SeatPool{
+			Name:    name,
+			Seats:   pool.Seats,
+			Majors:  nonNil(pool.Majors),
+			Cohorts: nonNil(pool.Cohorts),
+			Years:   nonNil(pool.Years),
+		})
+	}
+	if total > section.TotalSeats {
+		return nil, fmt.Errorf("%w: %d pooled seats for %d total", ErrInvalidSeatPools, total, section.TotalSeats)
+	}
+
+	before := seatPoolAuditFields(section.SeatPools)
+	section.SeatPools, err = s.sectionRepo.ReplaceSeatPools(ctx, sectionID, pools)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := loadSeatPools(ctx, s.cacheService, s.registrationRepo, section)
+	if err != nil {
+		// Registrations reload the counters when they find them missing
+		logger.Warn("Failed to load seat pool counters of section %s: %v", sectionID, err)
+		if err := s.cacheService.SetSeatPools(ctx, sectionID, nil, 0); err != nil {
+			logger.Error("Failed to clear seat pool counters of section %s: %v", sectionID, err)
+		}
+	}
+	for i := range section.SeatPools {
+		if left, ok := remaining[section.SeatPools[i].PoolID]; ok {
+			section.SeatPools[i].Remaining = &left
+		}
+	}
+
+	s.invalidateSectionCaches(ctx, section)
+
+	logger.Info("Set %d seat pools holding %d seats on section %s", len(pools), total, sectionID)
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditSeatPoolsChanged,
+		SectionID: sectionID,
+		Before:    before,
+		After:     seatPoolAuditFields(section.SeatPools),
+	})
+	return section, nil
+}
+
+func seatPoolAuditFields(pools []domain.SeatPool) map[string]any {
+	seats := make(map[string]int, len(pools))
+	for _, pool := range pools {
+		seats[pool.Name] = pool.Seats
+	}
+	return map[string]any{"seat_pools": seats}
+}
+
+// nonNil turns a missing eligibility list into an empty one, which the column requires
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"cobra-template/internal/infrastructure/metrics"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SeatDiscrepancy = serviceInterfaces.SeatDiscrepancy
+type SeatReconcileReport = serviceInterfaces.SeatReconcileReport
+
+// SeatReconciler compares the Redis seat counter of every active section with the section's
+// capacity less its enrolled registrations. Counters drift when a process dies between
+// taking a seat and recording it, or when a rollback fails; the anomaly guard only catches
+// drift that leaves a counter out of range, this catches the rest.
+//
+// The database trails the counters while database syncs sit in the queue, so a mismatch on
+// a busy section is expected. A discrepancy is only healed once it has held for the settle
+// period with neither the counter nor the enrolled count having moved, and the counter is
+// then replaced only if it still holds the value that was checked.
+type SeatReconciler struct {
+	registrationRepo interfaces.RegistrationRepository
+	sectionRepo      interfaces.SectionRepository
+	semesterRepo     interfaces.SemesterRepository
+	cacheService     interfaces.CacheService
+	settle           time.Duration
+}
+
+func NewSeatReconciler(
+	registrationRepo interfaces.RegistrationRepository,
+	sectionRepo interfaces.SectionRepository,
+	semesterRepo interfaces.SemesterRepository,
+	cacheService interfaces.CacheService,
+	settle time.Duration,
+) *SeatReconciler {
+	return &SeatReconciler{
+		registrationRepo: registrationRepo,
+		sectionRepo:      sectionRepo,
+		semesterRepo:     semesterRepo,
+		cacheService:     cacheService,
+		settle:           settle,
+	}
+}
+
+// Reconcile compares the seat counters of the sections of every active semester with the
+// database and reports the sections that disagree. With heal set, the discrepancies that
+// survive the settle period are corrected.
+func (r *SeatReconciler) Reconcile(ctx context.Context, heal bool) (*SeatReconcileReport, error) {
+	semesters, err := r.semesterRepo.GetAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semesters: %w", err)
+	}
+
+	report := &SeatReconcileReport{Discrepancies: []SeatDiscrepancy{}}
+	semesterOf := make(map[uuid.UUID]uuid.UUID)
+	for _, semester := range semesters {
+		sections, err := r.sectionRepo.GetBySemester(ctx, semester.SemesterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sections of semester %s: %w", semester.SemesterCode, err)
+		}
+		enrolled, err := r.registrationRepo.CountEnrolledBySection(ctx, semester.SemesterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count enrollments of semester %s: %w", semester.SemesterCode, err)
+		}
+
+		for _, section := range sections {
+			if !section.IsActive {
+				continue
+			}
+			report.Sections++
+
+			if discrepancy := r.compare(ctx, section, enrolled[section.SectionID]); discrepancy != nil {
+				report.Discrepancies = append(report.Discrepancies, *discrepancy)
+				semesterOf[section.SectionID] = semester.SemesterID
+			}
+		}
+	}
+	metrics.SeatCounterDiscrepancies.Set(float64(len(report.Discrepancies)))
+
+	if heal && len(report.Discrepancies) > 0 {
+		if err := r.healAfterSettle(ctx, report, semesterOf); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, discrepancy := range report.Discrepancies {
+		logger.Warn("Seat counter for %s section %s (%s) reads %s, expected %d from %d enrolled of %d seats (healed: %t)",
+			discrepancy.CourseCode, discrepancy.SectionNumber, discrepancy.SectionID, counterText(discrepancy.CounterSeats),
+			discrepancy.ExpectedSeats, discrepancy.Enrolled, discrepancy.TotalSeats, discrepancy.Healed)
+	}
+	logger.Info("Reconciled seat counters of %d sections: %d discrepancies, %d healed",
+		report.Sections, len(report.Discrepancies), report.Healed)
+	return report, nil
+}
+
+// compare returns the section's discrepancy, or nil when its counter matches the database
+func (r *SeatReconciler) compare(ctx context.Context, section *domain.Section, enrolled int) *SeatDiscrepancy {
+	expected := min(max(section.TotalSeats-enrolled, 0), section.TotalSeats)
+
+	// A counter that cannot be read is reported as missing, like a cache miss elsewhere
+	var counter *int
+	if seats, err := r.cacheService.GetAvailableSeats(ctx, section.SectionID); err == nil {
+		if seats == expected {
+			return nil
+		}
+		counter = &seats
+	}
+
+	return &SeatDiscrepancy{
+		SectionID:     section.SectionID,
+		CourseCode:    section.Course.CourseCode,
+		SectionNumber: section.SectionNumber,
+		TotalSeats:    section.TotalSeats,
+		Enrolled:      enrolled,
+		CounterSeats:  counter,
+		ExpectedSeats: expected,
+	}
+}
+
+// healAfterSettle waits out the settle period, then resets each counter whose discrepancy
+// is unchanged. A missing counter is left for the cache warmer to fill.
+func (r *SeatReconciler) healAfterSettle(ctx context.Context, report *SeatReconcileReport, semesterOf map[uuid.UUID]uuid.UUID) error {
+	timer := time.NewTimer(r.settle)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	recounted := make(map[uuid.UUID]map[uuid.UUID]int)
+	for i := range report.Discrepancies {
+		discrepancy := &report.Discrepancies[i]
+		if discrepancy.CounterSeats == nil {
+			continue
+		}
+
+		semesterID := semesterOf[discrepancy.SectionID]
+		enrolled, ok := recounted[semesterID]
+		if !ok {
+			var err error
+			enrolled, err = r.registrationRepo.CountEnrolledBySection(ctx, semesterID)
+			if err != nil {
+				return fmt.Errorf("failed to recount enrollments: %w", err)
+			}
+			recounted[semesterID] = enrolled
+		}
+		if enrolled[discrepancy.SectionID] != discrepancy.Enrolled {
+			metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileSkipped).Inc()
+			continue
+		}
+
+		swapped, err := r.cacheService.CompareAndSetAvailableSeats(ctx, discrepancy.SectionID, *discrepancy.CounterSeats, discrepancy.ExpectedSeats)
+		switch {
+		case err != nil:
+			metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileFailed).Inc()
+			logger.Error("Failed to reset seat counter for section %s: %v", discrepancy.SectionID, err)
+		case !swapped:
+			metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileSkipped).Inc()
+		default:
+			metrics.SeatCounterReconciliations.WithLabelValues(metrics.SeatReconcileCorrected).Inc()
+			discrepancy.Healed = true
+			report.Healed++
+		}
+	}
+	return nil
+}
+
+func counterText(seats *int) string {
+	if seats == nil {
+		return "nothing"
+	}
+	return fmt.Sprintf("%d", *seats)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"cobra-template/pkg/logger"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// seatSyncFlushTimeout bounds one flush, however many sections it writes
+	seatSyncFlushTimeout = 30 * time.Second
+	// seatSyncMaxRetries is how many flushes a failing section is retried in before it is
+	// left to the next registration or drop on it
+	seatSyncMaxRetries = 3
+)
+
+// SeatSyncBatcher coalesces seat count writes. Every registration and drop asks for its
+// section's seat count to be written to Postgres; the batcher collects those requests and
+// writes each section once per flush, every window or after maxEvents requests, whichever
+// comes first. The write copies the seat counter, which already holds every change, so
+// coalescing loses nothing.
+type SeatSyncBatcher struct {
+	syncSection func(ctx context.Context, sectionID uuid.UUID) error
+	window      time.Duration
+	maxEvents   int
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]struct{}
+	retries map[uuid.UUID]int
+	events  int
+
+	full     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewSeatSyncBatcher(syncSection func(ctx context.Context, sectionID uuid.UUID) error, window time.Duration, maxEvents int) *SeatSyncBatcher {
+	return &SeatSyncBatcher{
+		syncSection: syncSection,
+		window:      window,
+		maxEvents:   maxEvents,
+		pending:     make(map[uuid.UUID]struct{}),
+		retries:     make(map[uuid.UUID]int),
+		full:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (b *SeatSyncBatcher) Start() {
+	go b.run()
+}
+
+// Add asks for the seat count of a section to be written with the next flush
+func (b *SeatSyncBatcher) Add(sectionID uuid.UUID) {
+	b.mu.Lock()
+	b.pending[sectionID] = struct{}{}
+	b.events++
+	full := b.maxEvents > 0 && b.events >= b.maxEvents
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Stop writes whatever is pending and stops flushing
+func (b *SeatSyncBatcher) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+		<-b.done
+	})
+}
+
+func (b *SeatSyncBatcher) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.full:
+		case <-b.stop:
+			b.flush()
+			return
+		}
+		b.flush()
+	}
+}
+
+func (b *SeatSyncBatcher) flush() {
+	b.mu.Lock()
+	pending, events := b.pending, b.events
+	b.pending = make(map[uuid.UUID]struct{})
+	b.events = 0
+	b.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), seatSyncFlushTimeout)
+	defer cancel()
+
+	failed := 0
+	for sectionID := range pending {
+		err := b.syncSection(ctx, sectionID)
+
+		b.mu.Lock()
+		switch {
+		case err == nil:
+			delete(b.retries, sectionID)
+		case b.retries[sectionID]+1 < seatSyncMaxRetries:
+			b.retries[sectionID]++
+			b.pending[sectionID] = struct{}{}
+		default:
+			delete(b.retries, sectionID)
+		}
+		b.mu.Unlock()
+
+		if err != nil {
+			logger.Warn("Failed to sync seat count of section %s: %v", sectionID, err)
+			failed++
+		}
+	}
+
+	logger.Debug("Flushed %d seat updates as %d section writes (%d failed)", events, len(pending), failed)
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWarmupConcurrency = 4
+	defaultWarmupBatchSize   = 500
+)
+
+// SemesterWarmup reports how warming the section caches of one semester went
+type SemesterWarmup struct {
+	SemesterID   uuid.UUID `json:"semester_id"`
+	SemesterCode string    `json:"semester_code"`
+	// Sections is the number of open sections whose seat counter was seeded
+	Sections   int   `json:"sections"`
+	Available  int   `json:"available"`
+	Failed     int   `json:"failed"`
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// SectionCacheWarmer seeds the seat counters and available sections lists of every active
+// semester. Seat counters of a semester are written in batches of batchSize, one round trip
+// each, by concurrency workers at once, which keeps warming a large catalog from taking
+// minutes.
+type SectionCacheWarmer struct {
+	sectionRepo     interfaces.SectionRepository
+	semesterService *SemesterService
+	cacheService    interfaces.CacheService
+	concurrency     int
+	batchSize       int
+}
+
+func NewSectionCacheWarmer(
+	sectionRepo interfaces.SectionRepository,
+	semesterService *SemesterService,
+	cacheService interfaces.CacheService,
+	concurrency int,
+	batchSize int,
+) *SectionCacheWarmer {
+	if concurrency <= 0 {
+		concurrency = defaultWarmupConcurrency
+	}
+	if batchSize <= 0 {
+		batchSize = defaultWarmupBatchSize
+	}
+	return &SectionCacheWarmer{
+		sectionRepo:     sectionRepo,
+		semesterService: semesterService,
+		cacheService:    cacheService,
+		concurrency:     concurrency,
+		batchSize:       batchSize,
+	}
+}
+
+// WarmActiveSemesters warms each active semester in turn, logging progress as it goes. A
+// semester that fails does not stop the others; the error names every one that failed.
+func (w *SectionCacheWarmer) WarmActiveSemesters(ctx context.Context) ([]SemesterWarmup, error) {
+	return w.warmActiveSemesters(ctx, false)
+}
+
+// WarmForLoadTest also caches the details of every open section, so the first wave of a load
+// test does not fall through to the database for them
+func (w *SectionCacheWarmer) WarmForLoadTest(ctx context.Context) ([]SemesterWarmup, error) {
+	return w.warmActiveSemesters(ctx, true)
+}
+
+func (w *SectionCacheWarmer) warmActiveSemesters(ctx context.Context, withDetails bool) ([]SemesterWarmup, error) {
+	semesters, err := w.semesterService.ListSemesters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SemesterWarmup, 0, len(semesters))
+	var failed []string
+	for i, semester := range semesters {
+		result, err := w.warmSemester(ctx, semester, withDetails)
+		if err != nil {
+			logger.Warn("Failed to warm section caches of semester %s (%d/%d): %v", semester.SemesterCode, i+1, len(semesters), err)
+			failed = append(failed, semester.SemesterCode)
+			continue
+		}
+		results = append(results, *result)
+		logger.Info("Warmed semester %s (%d/%d): %d sections, %d with seats, %d failed in %dms",
+			semester.SemesterCode, i+1, len(semesters), result.Sections, result.Available, result.Failed, result.DurationMS)
+	}
+
+	if len(failed) > 0 {
+		return results, fmt.Errorf("failed to warm semesters %v", failed)
+	}
+	return results, nil
+}
+
+// WarmSemester warms the section caches of one semester
+func (w *SectionCacheWarmer) WarmSemester(ctx context.Context, semesterID uuid.UUID) (*SemesterWarmup, error) {
+	semester, err := w.semesterService.GetSemester(ctx, semesterID)
+	if err != nil {
+		return nil, err
+	}
+	return w.warmSemester(ctx, semester, false)
+}
+
+// warmSemester seeds the seat counter of every open section from the database and caches
+// the list of those with seats left. With withDetails the section details are cached too.
+func (w *SectionCacheWarmer) warmSemester(ctx context.Context, semester *domain.Semester, withDetails bool) (*SemesterWarmup, error) {
+	start := time.Now()
+	sections, err := w.sectionRepo.GetBySemester(ctx, semester.SemesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+
+	open := make([]*domain.Section, 0, len(sections))
+	for _, section := range sections {
+		if section.IsActive && section.Course.Active {
+			open = append(open, section)
+		}
+	}
+
+	batches := make([][]*domain.Section, 0, (len(open)+w.batchSize-1)/w.batchSize)
+	for i := 0; i < len(open); i += w.batchSize {
+		batches = append(batches, open[i:min(i+w.batchSize, len(open))])
+	}
+
+	var failed, seeded atomic.Int64
+	work := make(chan []*domain.Section)
+	var wg sync.WaitGroup
+	for i := 0; i < min(w.concurrency, len(batches)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range work {
+				w.warmBatch(ctx, batch, withDetails, &failed)
+				done := seeded.Add(int64(len(batch)))
+				logger.Info("Warming semester %s: %d/%d sections seeded", semester.SemesterCode, done, len(open))
+			}
+		}()
+	}
+	for _, batch := range batches {
+		work <- batch
+	}
+	close(work)
+	wg.Wait()
+
+	available := make([]*domain.Section, 0, len(open))
+	for _, section := range open {
+		if section.AvailableSeats > 0 {
+			available = append(available, section)
+		}
+	}
+	if err := w.cacheService.SetAvailableSections(ctx, semester.SemesterID, available, AvailableSectionsTTL); err != nil {
+		return nil, fmt.Errorf("failed to cache available sections: %w", err)
+	}
+
+	return &SemesterWarmup{
+		SemesterID:   semester.SemesterID,
+		SemesterCode: semester.SemesterCode,
+		Sections:     len(open),
+		Available:    len(available),
+		Failed:       int(failed.Load()),
+		DurationMS:   time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// warmBatch seeds the seat counters of a batch of sections in one call. A batch that fails
+// counts every section in it as failed, and their details are not cached.
+func (w *SectionCacheWarmer) warmBatch(ctx context.Context, batch []*domain.Section, withDetails bool, failed *atomic.Int64) {
+	counters := make(map[uuid.UUID]interfaces.SeatCounter, len(batch))
+	for _, section := range batch {
+		counters[section.SectionID] = sectionSeatCounter(section)
+	}
+	if err := w.cacheService.SetSeatCounters(ctx, counters, sectionSeatsTTL); err != nil {
+		logger.Warn("Failed to cache seats for a batch of %d sections: %v", len(batch), err)
+		failed.Add(int64(len(batch)))
+		return
+	}
+	if !withDetails {
+		return
+	}
+
+	for _, section := range batch {
+		if err := w.cacheService.SetSectionDetails(ctx, section.SectionID, section, SectionDetailsTTL); err != nil {
+			logger.Warn("Failed to cache details of section %s: %v", section.SectionID, err)
+			failed.Add(1)
+		}
+	}
+}
+
+// SemesterCacheReadiness counts how many of a semester's open sections are served from the
+// cache
+type SemesterCacheReadiness struct {
+	SemesterID        uuid.UUID `json:"semester_id"`
+	SemesterCode      string    `json:"semester_code"`
+	Sections          int       `json:"sections"`
+	SeatCounters      int       `json:"seat_counters"`
+	SectionDetails    int       `json:"section_details"`
+	AvailableSections bool      `json:"available_sections_cached"`
+	Ready             bool      `json:"ready"`
+}
+
+// CacheReadiness tells whether the cache is warm enough for a load test: every open section
+// of every active semester has its seat counter and details cached, and every semester its
+// available sections list
+type CacheReadiness struct {
+	Ready     bool                     `json:"ready"`
+	Semesters []SemesterCacheReadiness `json:"semesters"`
+	CheckedAt time.Time                `json:"checked_at"`
+}
+
+func (w *SectionCacheWarmer) Readiness(ctx context.Context) (*CacheReadiness, error) {
+	semesters, err := w.semesterService.ListSemesters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CacheReadiness{Ready: true, Semesters: make([]SemesterCacheReadiness, 0, len(semesters)), CheckedAt: time.Now()}
+	for _, semester := range semesters {
+		sections, err := w.sectionRepo.GetBySemester(ctx, semester.SemesterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sections of semester %s: %w", semester.SemesterCode, err)
+		}
+
+		readiness := SemesterCacheReadiness{SemesterID: semester.SemesterID, SemesterCode: semester.SemesterCode}
+		seats := liveSeats(ctx, w.cacheService, sections)
+		for _, section := range sections {
+			if !section.IsActive || !section.Course.Active {
+				continue
+			}
+			readiness.Sections++
+			if _, ok := seats[section.SectionID]; ok {
+				readiness.SeatCounters++
+			}
+			if _, err := w.cacheService.GetSectionDetails(ctx, section.SectionID); err == nil {
+				readiness.SectionDetails++
+			}
+		}
+		_, err = w.cacheService.GetAvailableSections(ctx, semester.SemesterID)
+		readiness.AvailableSections = err == nil
+		readiness.Ready = readiness.AvailableSections &&
+			readiness.SeatCounters == readiness.Sections &&
+			readiness.SectionDetails == readiness.Sections
+
+		report.Ready = report.Ready && readiness.Ready
+		report.Semesters = append(report.Semesters, readiness)
+	}
+	return report, nil
+}
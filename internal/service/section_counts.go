@@ -0,0 +1,48 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SectionCountsInclude = serviceInterfaces.SectionCountsInclude
+type SectionWithCounts = serviceInterfaces.SectionWithCounts
+
+// WithSectionCounts adds the requested counts to listed sections. The enrolled count is the
+// seats taken according to the seat counter already applied to each section, and all
+// waitlist sizes are read in one round trip. Counts that cannot be read are left out
+// rather than failing the listing.
+func (s *RegistrationService) WithSectionCounts(ctx context.Context, sections []*domain.Section, include SectionCountsInclude) []*SectionWithCounts {
+	log := logger.FromContext(ctx)
+	var waitlistSizes map[uuid.UUID]int
+	if include.WaitlistCount && len(sections) > 0 {
+		sectionIDs := make([]uuid.UUID, len(sections))
+		for i, section := range sections {
+			sectionIDs[i] = section.SectionID
+		}
+
+		sizes, err := s.cacheService.GetWaitlistSizes(ctx, sectionIDs)
+		if err != nil {
+			log.Warn("Failed to get waitlist sizes for %d sections: %v", len(sections), err)
+		}
+		waitlistSizes = sizes
+	}
+
+	result := make([]*SectionWithCounts, len(sections))
+	for i, section := range sections {
+		counted := &SectionWithCounts{Section: section}
+		if size, ok := waitlistSizes[section.SectionID]; ok {
+			counted.WaitlistCount = &size
+		}
+		if include.EnrolledCount {
+			enrolled := max(section.TotalSeats-section.AvailableSeats, 0)
+			counted.EnrolledCount = &enrolled
+		}
+		result[i] = counted
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrAsOfInFuture = errors.New("timestamp must not be in the future")
+
+// SectionStateAsOf is the enrollment and waitlist of a section at a point in time. When a
+// student is given, their entry and the events that led to it are included so a "I was
+// enrolled and then disappeared" ticket can be traced to the change that caused it.
+type SectionStateAsOf struct {
+	SectionID     uuid.UUID                   `json:"section_id"`
+	AsOf          time.Time                   `json:"as_of"`
+	Sequence      int64                       `json:"sequence"`
+	EnrolledCount int                         `json:"enrolled_count"`
+	WaitlistCount int                         `json:"waitlist_count"`
+	Enrolled      []*domain.RosterEntry       `json:"enrolled"`
+	Waitlisted    []*domain.RosterEntry       `json:"waitlisted"`
+	Dropped       []*domain.RosterEntry       `json:"dropped"`
+	Student       *domain.RosterEntry         `json:"student,omitempty"`
+	StudentEvents []*domain.RegistrationEvent `json:"student_events,omitempty"`
+}
+
+// GetSectionStateAsOf replays the registration events of a section up to asOf. It needs the
+// event-sourced persistence mode, since state rows only hold the latest status.
+func (s *RegistrationService) GetSectionStateAsOf(ctx context.Context, sectionID uuid.UUID, asOf time.Time, studentID *uuid.UUID) (*SectionStateAsOf, error) {
+	if s.eventStore == nil {
+		return nil, ErrEventStoreDisabled
+	}
+	if asOf.After(time.Now()) {
+		return nil, ErrAsOfInFuture
+	}
+
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	roster, err := s.eventStore.RosterAt(ctx, sectionID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &SectionStateAsOf{
+		SectionID:  sectionID,
+		AsOf:       asOf,
+		Sequence:   roster.Sequence,
+		Enrolled:   roster.WithStatus(domain.StatusEnrolled),
+		Waitlisted: roster.WithStatus(domain.StatusWaitlisted),
+		Dropped:    roster.WithStatus(domain.StatusDropped),
+	}
+	state.EnrolledCount = len(state.Enrolled)
+	state.WaitlistCount = len(state.Waitlisted)
+
+	if studentID != nil {
+		state.Student = roster.Entries[*studentID]
+		state.StudentEvents, err = s.eventStore.StudentEvents(ctx, sectionID, *studentID, asOf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// StudentEvents returns the events of one student in a section up to the given time,
+// oldest first
+func (e *RegistrationEventStore) StudentEvents(ctx context.Context, sectionID, studentID uuid.UUID, until time.Time) ([]*domain.RegistrationEvent, error) {
+	events, err := e.eventRepo.GetBySection(ctx, sectionID, 0, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration events: %w", err)
+	}
+
+	studentEvents := make([]*domain.RegistrationEvent, 0)
+	for _, event := range events {
+		if event.StudentID == studentID {
+			studentEvents = append(studentEvents, event)
+		}
+	}
+	return studentEvents, nil
+}
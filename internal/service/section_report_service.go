@@ -0,0 +1,134 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SectionStatsReportTTL is how long a semester's section report is served from the cache.
+// Reports are read during registration, when recomputing them on every request would add
+// load to the tables registration writes to.
+const SectionStatsReportTTL = 5 * time.Minute
+
+type SectionStatsReport = serviceInterfaces.SectionStatsReport
+type SectionStats = serviceInterfaces.SectionStats
+
+// SectionReportService reports how full the sections of a semester are, from the
+// registrations and waitlist tables
+type SectionReportService struct {
+	registrationRepo interfaces.RegistrationRepository
+	semesterRepo     interfaces.SemesterRepository
+	cacheService     interfaces.CacheService
+}
+
+func NewSectionReportService(
+	registrationRepo interfaces.RegistrationRepository,
+	semesterRepo interfaces.SemesterRepository,
+	cacheService interfaces.CacheService,
+) *SectionReportService {
+	return &SectionReportService{
+		registrationRepo: registrationRepo,
+		semesterRepo:     semesterRepo,
+		cacheService:     cacheService,
+	}
+}
+
+func (s *SectionReportService) GetSectionStats(ctx context.Context, semesterID uuid.UUID) (*SectionStatsReport, error) {
+	key := interfaces.SectionStatsReportCache.Key(semesterID)
+	if cached, err := s.cacheService.Get(ctx, key); err == nil {
+		var report SectionStatsReport
+		if err := json.Unmarshal([]byte(cached), &report); err == nil {
+			return &report, nil
+		}
+		logger.Warn("Failed to decode cached section report of semester %s", semesterID)
+	}
+
+	semester, err := s.semesterRepo.GetByID(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semester: %w", err)
+	}
+	if semester == nil {
+		return nil, ErrSemesterNotFound
+	}
+
+	counts, err := s.registrationRepo.CountBySectionStatus(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count section registrations: %w", err)
+	}
+
+	report := &SectionStatsReport{
+		SemesterID:  semesterID,
+		GeneratedAt: time.Now(),
+		Sections:    make([]SectionStats, len(counts)),
+	}
+	for i, count := range counts {
+		stats := SectionStats{
+			SectionID:     count.SectionID,
+			CourseCode:    count.CourseCode,
+			SectionNumber: count.SectionNumber,
+			Capacity:      count.TotalSeats,
+			Enrolled:      count.Enrolled,
+			Withdrawn:     count.Withdrawn,
+			Waitlisted:    count.Waitlisted,
+			Dropped:       count.Dropped,
+		}
+		held := count.Enrolled + count.Withdrawn
+		if count.TotalSeats > 0 {
+			stats.FillRate = math.Round(float64(held)*1000/float64(count.TotalSeats)) / 10
+		}
+		if held >= count.TotalSeats && count.LastSeatTakenAt != nil {
+			stats.FilledAt = count.LastSeatTakenAt
+			hours := math.Round(max(count.LastSeatTakenAt.Sub(semester.RegistrationStart).Hours(), 0)*10) / 10
+			stats.TimeToFillHours = &hours
+		}
+		report.Sections[i] = stats
+	}
+
+	if data, err := json.Marshal(report); err == nil {
+		if err := s.cacheService.Set(ctx, key, string(data), SectionStatsReportTTL); err != nil {
+			logger.Warn("Failed to cache section report of semester %s: %v", semesterID, err)
+		}
+	}
+	return report, nil
+}
+
+// WriteSectionStatsCSV writes the report as CSV, one row per section
+func WriteSectionStatsCSV(w io.Writer, report *SectionStatsReport) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"section_id", "course_code", "section_number", "capacity", "enrolled", "withdrawn", "waitlisted", "dropped", "fill_rate", "filled_at", "time_to_fill_hours"})
+	for _, stats := range report.Sections {
+		filledAt, timeToFill := "", ""
+		if stats.FilledAt != nil {
+			filledAt = stats.FilledAt.UTC().Format(time.RFC3339)
+		}
+		if stats.TimeToFillHours != nil {
+			timeToFill = strconv.FormatFloat(*stats.TimeToFillHours, 'f', 1, 64)
+		}
+		writer.Write([]string{
+			stats.SectionID.String(),
+			stats.CourseCode,
+			stats.SectionNumber,
+			strconv.Itoa(stats.Capacity),
+			strconv.Itoa(stats.Enrolled),
+			strconv.Itoa(stats.Withdrawn),
+			strconv.Itoa(stats.Waitlisted),
+			strconv.Itoa(stats.Dropped),
+			strconv.FormatFloat(stats.FillRate, 'f', 1, 64),
+			filledAt,
+			timeToFill,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sectionRosterTTL is short because the roster is dropped when a registration event is
+// recorded, which can happen just before the registration row it describes is written
+const sectionRosterTTL = 2 * time.Minute
+
+// ClassRoster is the list an instructor works from: the students holding a seat in the
+// section, withdrawn ones included, and its waitlist in position order
+type ClassRoster struct {
+	SectionID     uuid.UUID        `json:"section_id"`
+	CourseCode    string           `json:"course_code"`
+	SectionNumber string           `json:"section_number"`
+	EnrolledCount int              `json:"enrolled_count"`
+	WaitlistCount int              `json:"waitlist_count"`
+	Enrolled      []*RosterStudent `json:"enrolled"`
+	Waitlist      []*RosterStudent `json:"waitlist"`
+}
+
+type RosterStudent struct {
+	StudentID     uuid.UUID                 `json:"student_id"`
+	StudentNumber string                    `json:"student_number"`
+	FirstName     string                    `json:"first_name"`
+	LastName      string                    `json:"last_name"`
+	PreferredName string                    `json:"preferred_name,omitempty"`
+	Email         string                    `json:"email,omitempty"`
+	Status        domain.RegistrationStatus `json:"status"`
+	Position      *int                      `json:"waitlist_position,omitempty"`
+	Since         time.Time                 `json:"since"`
+}
+
+// GetSectionRoster returns the roster of a section from the database, cached for a short while
+func (s *RegistrationService) GetSectionRoster(ctx context.Context, sectionID uuid.UUID) (*ClassRoster, error) {
+	log := registrationLog(ctx, uuid.Nil, sectionID)
+	key := interfaces.SectionRosterCache.Key(sectionID)
+	if cached, err := s.cacheService.Get(ctx, key); err == nil {
+		var roster ClassRoster
+		if err := json.Unmarshal([]byte(cached), &roster); err == nil {
+			return &roster, nil
+		}
+		log.Warn("Failed to decode cached roster of section %s", sectionID)
+	}
+
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	registrations, err := s.registrationRepo.GetBySectionID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section registrations: %w", err)
+	}
+	entries, err := s.waitlistRepo.GetBySectionID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section waitlist: %w", err)
+	}
+
+	roster := &ClassRoster{
+		SectionID:     sectionID,
+		CourseCode:    section.Course.CourseCode,
+		SectionNumber: section.SectionNumber,
+		Enrolled:      make([]*RosterStudent, 0, len(registrations)),
+		Waitlist:      make([]*RosterStudent, 0, len(entries)),
+	}
+	for _, registration := range registrations {
+		if !registration.Status.HoldsSeat() {
+			continue
+		}
+		student := rosterStudent(&registration.Student, registration.StudentID, registration.Status)
+		student.Since = registration.RegistrationDate
+		roster.Enrolled = append(roster.Enrolled, student)
+	}
+	sort.SliceStable(roster.Enrolled, func(i, j int) bool {
+		a, b := roster.Enrolled[i], roster.Enrolled[j]
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	})
+	for _, entry := range entries {
+		student := rosterStudent(&entry.Student, entry.StudentID, domain.StatusWaitlisted)
+		position := entry.Position
+		student.Position = &position
+		student.Since = entry.Timestamp
+		roster.Waitlist = append(roster.Waitlist, student)
+	}
+	roster.EnrolledCount = len(roster.Enrolled)
+	roster.WaitlistCount = len(roster.Waitlist)
+
+	if data, err := json.Marshal(roster); err == nil {
+		if err := s.cacheService.Set(ctx, key, string(data), sectionRosterTTL); err != nil {
+			log.Warn("Failed to cache roster of section %s: %v", sectionID, err)
+		}
+	}
+	return roster, nil
+}
+
+func rosterStudent(student *domain.Student, studentID uuid.UUID, status domain.RegistrationStatus) *RosterStudent {
+	return &RosterStudent{
+		StudentID:     studentID,
+		StudentNumber: student.StudentNumber,
+		FirstName:     student.FirstName,
+		LastName:      student.LastName,
+		PreferredName: student.PreferredName,
+		Email:         student.Email,
+		Status:        status,
+	}
+}
+
+func (s *RegistrationService) invalidateSectionRoster(ctx context.Context, sectionID uuid.UUID) {
+	if err := s.cacheService.Delete(ctx, interfaces.SectionRosterCache.Key(sectionID)); err != nil {
+		registrationLog(ctx, uuid.Nil, sectionID).Warn("Failed to invalidate roster of section %s: %v", sectionID, err)
+	}
+}
+
+// Page returns a copy of the roster holding the enrolled students from offset on, at most
+// limit of them. The waitlist and the counts are kept whole.
+func (r *ClassRoster) Page(offset, limit int) *ClassRoster {
+	page := *r
+	start := min(offset, len(r.Enrolled))
+	end := min(start+limit, len(r.Enrolled))
+	page.Enrolled = r.Enrolled[start:end]
+	return &page
+}
+
+// WriteCSV writes the enrolled students and then the waitlist, one row per student
+func (r *ClassRoster) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"student_id", "student_number", "first_name", "last_name", "preferred_name", "email", "status", "waitlist_position", "since"})
+	for _, student := range append(append([]*RosterStudent{}, r.Enrolled...), r.Waitlist...) {
+		position := ""
+		if student.Position != nil {
+			position = strconv.Itoa(*student.Position)
+		}
+		writer.Write([]string{
+			student.StudentID.String(),
+			student.StudentNumber,
+			student.FirstName,
+			student.LastName,
+			student.PreferredName,
+			student.Email,
+			string(student.Status),
+			position,
+			student.Since.UTC().Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,445 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const sectionSeatsTTL = 24 * time.Hour
+
+var (
+	ErrSectionNotFound         = errors.New("section not found")
+	ErrSectionExists           = errors.New("section number already exists for this course and semester")
+	ErrCourseNotFound          = errors.New("course not found")
+	ErrSemesterNotFound        = errors.New("semester not found")
+	ErrCapacityBelowEnrollment = errors.New("capacity is below the number of seats already taken")
+	ErrInvalidMeetingTime      = errors.New("start_time must be before end_time")
+	ErrInvalidSectionPolicy    = errors.New("reserved_seats cannot exceed the section's total seats")
+	ErrInvalidLinkedSection    = errors.New("linked section must be an active section of the same semester that is not linked itself")
+)
+
+type CreateSectionRequest = serviceInterfaces.CreateSectionRequest
+type UpdateSectionCapacityRequest = serviceInterfaces.UpdateSectionCapacityRequest
+type SetWaitlistFreezeRequest = serviceInterfaces.SetWaitlistFreezeRequest
+type SetWaitlistTTLRequest = serviceInterfaces.SetWaitlistTTLRequest
+type SetSectionPolicyRequest = serviceInterfaces.SetSectionPolicyRequest
+type SetSeatPoolsRequest = serviceInterfaces.SetSeatPoolsRequest
+
+// SectionService manages sections on behalf of administrators. The Redis seat counter is
+// the source of truth for available seats, so capacity changes are applied there first.
+type SectionService struct {
+	sectionRepo      interfaces.SectionRepository
+	courseRepo       interfaces.CourseRepository
+	semesterRepo     interfaces.SemesterRepository
+	registrationRepo interfaces.RegistrationRepository
+	cacheService     interfaces.CacheService
+	queueService     interfaces.QueueService
+	auditService     *AuditService
+}
+
+func NewSectionService(
+	sectionRepo interfaces.SectionRepository,
+	courseRepo interfaces.CourseRepository,
+	semesterRepo interfaces.SemesterRepository,
+	registrationRepo interfaces.RegistrationRepository,
+	cacheService interfaces.CacheService,
+	queueService interfaces.QueueService,
+	auditService *AuditService,
+) *SectionService {
+	return &SectionService{
+		sectionRepo:      sectionRepo,
+		courseRepo:       courseRepo,
+		semesterRepo:     semesterRepo,
+		registrationRepo: registrationRepo,
+		cacheService:     cacheService,
+		queueService:     queueService,
+		auditService:     auditService,
+	}
+}
+
+func (s *SectionService) GetSection(ctx context.Context, sectionID uuid.UUID) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	// The database copy trails the counter until the seat sync job runs
+	if seats, err := s.cacheService.GetAvailableSeats(ctx, sectionID); err == nil {
+		section.AvailableSeats = seats
+	}
+
+	return section, nil
+}
+
+func (s *SectionService) CreateSection(ctx context.Context, req *CreateSectionRequest) (*domain.Section, error) {
+	if req.StartTime != "" && req.EndTime != "" && req.StartTime >= req.EndTime {
+		return nil, ErrInvalidMeetingTime
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, req.CourseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	if !course.Active {
+		return nil, ErrCourseInactive
+	}
+
+	semester, err := s.semesterRepo.GetByID(ctx, req.SemesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semester: %w", err)
+	}
+	if semester == nil {
+		return nil, ErrSemesterNotFound
+	}
+
+	existing, err := s.sectionRepo.GetByCourseAndSemester(ctx, req.CourseID, req.SemesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing sections: %w", err)
+	}
+	for _, section := range existing {
+		if strings.EqualFold(section.SectionNumber, req.SectionNumber) {
+			return nil, ErrSectionExists
+		}
+	}
+	if req.LinkedSectionID != nil {
+		if err := s.checkLinkedSection(ctx, *req.LinkedSectionID, req.SemesterID); err != nil {
+			return nil, err
+		}
+	}
+
+	section := &domain.Section{
+		SectionID:       uuid.New(),
+		CourseID:        req.CourseID,
+		SemesterID:      req.SemesterID,
+		SectionNumber:   req.SectionNumber,
+		TotalSeats:      req.TotalSeats,
+		AvailableSeats:  req.TotalSeats,
+		IsActive:        true,
+		Policy:          domain.SectionPolicy{AllowWaitlist: true},
+		MeetingDays:     strings.ToUpper(req.MeetingDays),
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		Room:            strings.TrimSpace(req.Room),
+		Instructor:      strings.TrimSpace(req.Instructor),
+		LinkedSectionID: req.LinkedSectionID,
+		Version:         1,
+	}
+	if err := s.sectionRepo.Create(ctx, section); err != nil {
+		return nil, fmt.Errorf("failed to create section: %w", err)
+	}
+
+	if err := s.cacheService.SetSeatCounter(ctx, section.SectionID, sectionSeatCounter(section), sectionSeatsTTL); err != nil {
+		logger.Warn("Failed to seed seat counter for section %s: %v", section.SectionID, err)
+	}
+	if err := s.cacheService.Delete(ctx, interfaces.AvailableSectionsCache.Key(section.SemesterID)); err != nil {
+		logger.Warn("Failed to invalidate available sections for semester %s: %v", section.SemesterID, err)
+	}
+	s.invalidateLinkedSection(ctx, section)
+
+	section.Course = *course
+	section.Semester = *semester
+
+	logger.Info("Created section %s (%s %s) with %d seats", section.SectionID, course.CourseCode, section.SectionNumber, section.TotalSeats)
+	return section, nil
+}
+
+// UpdateCapacity changes the total seats of a section. The seat counter takes the new
+// capacity and moves its available seats by the same amount in one step, refusing a
+// decrease below the seats that registrations already hold. Freed capacity is handed to the
+// waitlist.
+func (s *SectionService) UpdateCapacity(ctx context.Context, sectionID uuid.UUID, totalSeats int) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	delta := totalSeats - section.TotalSeats
+	if delta == 0 {
+		return section, nil
+	}
+
+	available, err := s.adjustSeatCounter(ctx, section, totalSeats)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sectionRepo.UpdateCapacity(ctx, sectionID, totalSeats, available); err != nil {
+		logger.Error("Failed to persist capacity for section %s, rolling back seat counter: %v", sectionID, err)
+		if _, rollbackErr := s.adjustSeatCounter(ctx, section, section.TotalSeats); rollbackErr != nil {
+			logger.Error("Failed to rollback seat counter for section %s: %v", sectionID, rollbackErr)
+		}
+		return nil, err
+	}
+
+	logger.Info("Changed capacity of section %s from %d to %d, available seats: %d", sectionID, section.TotalSeats, totalSeats, available)
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditCapacityChanged,
+		SectionID: sectionID,
+		Before:    map[string]any{"total_seats": section.TotalSeats, "available_seats": available - delta},
+		After:     map[string]any{"total_seats": totalSeats, "available_seats": available},
+	})
+
+	section.TotalSeats = totalSeats
+	section.AvailableSeats = available
+	section.Version++
+
+	if delta > 0 {
+		// Each waitlist job promotes at most one student
+		for i := 0; i < delta && i < available; i++ {
+			job := interfaces.WaitlistPromotionJob{
+				SectionID:   sectionID,
+				SeatEventID: fmt.Sprintf("capacity:%s:%d:%d", sectionID, section.Version, i),
+				Timestamp:   time.Now(),
+			}
+			if err := s.queueService.EnqueueWaitlistProcessing(ctx, job); err != nil {
+				logger.Error("Failed to enqueue waitlist processing for section %s: %v", sectionID, err)
+				break
+			}
+		}
+	}
+
+	s.invalidateSectionCaches(ctx, section)
+	return section, nil
+}
+
+func (s *SectionService) DeactivateSection(ctx context.Context, sectionID uuid.UUID) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+	if !section.IsActive {
+		return section, nil
+	}
+
+	if err := s.sectionRepo.SetActive(ctx, sectionID, false); err != nil {
+		return nil, err
+	}
+	section.IsActive = false
+
+	s.invalidateSectionCaches(ctx, section)
+
+	logger.Info("Deactivated section %s", sectionID)
+	return section, nil
+}
+
+// SetWaitlistFreeze freezes or unfreezes the waitlist of a section. A frozen waitlist takes
+// no new students; with promotions paused as well, students already waiting stay put and
+// open seats are kept for them. Lifting the pause queues a promotion for every open seat.
+func (s *SectionService) SetWaitlistFreeze(ctx context.Context, sectionID uuid.UUID, req *SetWaitlistFreezeRequest) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	frozen := *req.Frozen
+	paused := frozen && req.PausePromotions
+	if section.WaitlistFrozen == frozen && section.WaitlistPromotionsPaused == paused {
+		return section, nil
+	}
+
+	if err := s.sectionRepo.SetWaitlistFreeze(ctx, sectionID, frozen, paused); err != nil {
+		return nil, err
+	}
+	resumed := section.WaitlistPromotionsPaused && !paused
+	section.WaitlistFrozen = frozen
+	section.WaitlistPromotionsPaused = paused
+
+	s.invalidateSectionCaches(ctx, section)
+
+	logger.Info("Set waitlist of section %s to frozen=%t, promotions paused=%t", sectionID, frozen, paused)
+
+	if resumed {
+		s.resumeWaitlistPromotions(ctx, section)
+	}
+	return section, nil
+}
+
+// SetWaitlistTTL sets the lifetime of the section's new waitlist entries
+func (s *SectionService) SetWaitlistTTL(ctx context.Context, sectionID uuid.UUID, req *SetWaitlistTTLRequest) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+
+	if err := s.sectionRepo.SetWaitlistTTL(ctx, sectionID, req.TTLHours); err != nil {
+		return nil, err
+	}
+	section.WaitlistTTLHours = req.TTLHours
+
+	s.invalidateSectionCaches(ctx, section)
+
+	if req.TTLHours == nil {
+		logger.Info("Reset waitlist TTL of section %s to the default", sectionID)
+	} else {
+		logger.Info("Set waitlist TTL of section %s to %d hours", sectionID, *req.TTLHours)
+	}
+	return section, nil
+}
+
+// SetPolicy replaces the registration policy of a section. A section cannot reserve more
+// seats than it has.
+func (s *SectionService) SetPolicy(ctx context.Context, sectionID uuid.UUID, req *SetSectionPolicyRequest) (*domain.Section, error) {
+	section, err := s.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	if section == nil {
+		return nil, ErrSectionNotFound
+	}
+	if req.ReservedSeats > section.TotalSeats {
+		return nil, fmt.Errorf("%w: %d reserved seats for %d total", ErrInvalidSectionPolicy, req.ReservedSeats, section.TotalSeats)
+	}
+
+	policy := domain.SectionPolicy{
+		AllowWaitlist:             *req.AllowWaitlist,
+		ReservedSeats:             req.ReservedSeats,
+		InstructorConsentRequired: req.InstructorConsentRequired,
+		MaxWaitlist:               req.MaxWaitlist,
+	}
+	if err := s.sectionRepo.SetPolicy(ctx, sectionID, policy); err != nil {
+		return nil, err
+	}
+	before := section.Policy
+	section.Policy = policy
+
+	s.invalidateSectionCaches(ctx, section)
+	if policy.ReservedSeats != before.ReservedSeats {
+		// A counter that is not cached picks the reserved seats up when it is seeded
+		if _, err := s.cacheService.SetSeatCapacity(ctx, sectionID, section.TotalSeats, policy.ReservedSeats); err != nil && !strings.Contains(err.Error(), "seat key not found") {
+			logger.Warn("Failed to set reserved seats on the seat counter of section %s: %v", sectionID, err)
+		}
+	}
+
+	logger.Info("Set policy of section %s: waitlist %t, %d reserved seats, consent required %t", sectionID, policy.AllowWaitlist, policy.ReservedSeats, policy.InstructorConsentRequired)
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditPolicyChanged,
+		SectionID: sectionID,
+		Before:    policyAuditFields(before),
+		After:     policyAuditFields(policy),
+	})
+	return section, nil
+}
+
+func policyAuditFields(policy domain.SectionPolicy) map[string]any {
+	return map[string]any{
+		"allow_waitlist":              policy.AllowWaitlist,
+		"reserved_seats":              policy.ReservedSeats,
+		"instructor_consent_required": policy.InstructorConsentRequired,
+		"max_waitlist":                policy.MaxWaitlist,
+	}
+}
+
+// resumeWaitlistPromotions queues one promotion for each seat that opened while promotions
+// were paused, up to the number of students waiting
+func (s *SectionService) resumeWaitlistPromotions(ctx context.Context, section *domain.Section) {
+	available, err := s.cacheService.GetAvailableSeats(ctx, section.SectionID)
+	if err != nil {
+		available = section.AvailableSeats
+	}
+	if waiting, err := s.cacheService.GetWaitlistSize(ctx, section.SectionID); err == nil && waiting < available {
+		available = waiting
+	}
+
+	now := time.Now()
+	for i := 0; i < available; i++ {
+		job := interfaces.WaitlistPromotionJob{
+			SectionID:   section.SectionID,
+			SeatEventID: fmt.Sprintf("waitlist-resume:%s:%d:%d", section.SectionID, now.UnixNano(), i),
+			Timestamp:   now,
+		}
+		if err := s.queueService.EnqueueWaitlistProcessing(ctx, job); err != nil {
+			logger.Error("Failed to enqueue waitlist processing for section %s: %v", section.SectionID, err)
+			return
+		}
+	}
+}
+
+// adjustSeatCounter sets the capacity of the section's seat counter to totalSeats and
+// returns its available seats. A missing counter is seeded from the database first.
+func (s *SectionService) adjustSeatCounter(ctx context.Context, section *domain.Section, totalSeats int) (int, error) {
+	counter, err := s.cacheService.SetSeatCapacity(ctx, section.SectionID, totalSeats, section.Policy.ReservedSeats)
+	if err != nil && strings.Contains(err.Error(), "seat key not found") {
+		if err := s.cacheService.SetSeatCounter(ctx, section.SectionID, sectionSeatCounter(section), sectionSeatsTTL); err != nil {
+			return 0, fmt.Errorf("failed to seed seat counter: %w", err)
+		}
+		counter, err = s.cacheService.SetSeatCapacity(ctx, section.SectionID, totalSeats, section.Policy.ReservedSeats)
+	}
+	if errors.Is(err, interfaces.ErrCapacityBelowEnrolled) {
+		return 0, fmt.Errorf("%w: %d seats are taken", ErrCapacityBelowEnrollment, counter.Enrolled())
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counter.Available, nil
+}
+
+// sectionSeatCounter is the seat counter of a section as its row has it
+func sectionSeatCounter(section *domain.Section) interfaces.SeatCounter {
+	return interfaces.SeatCounter{
+		Available: section.AvailableSeats,
+		Total:     section.TotalSeats,
+		Reserved:  section.Policy.ReservedSeats,
+	}
+}
+
+// invalidateSectionCaches drops cached copies of the section without touching the seat counter
+func (s *SectionService) invalidateSectionCaches(ctx context.Context, section *domain.Section) {
+	if err := s.cacheService.Delete(ctx, interfaces.SectionDetailsCache.Key(section.SectionID)); err != nil {
+		logger.Warn("Failed to invalidate section details for %s: %v", section.SectionID, err)
+	}
+	if err := s.cacheService.Delete(ctx, interfaces.AvailableSectionsCache.Key(section.SemesterID)); err != nil {
+		logger.Warn("Failed to invalidate available sections for semester %s: %v", section.SemesterID, err)
+	}
+	s.invalidateLinkedSection(ctx, section)
+}
+
+// checkLinkedSection makes sure a new component is linked to an active section of its
+// semester. Components cannot have components of their own.
+func (s *SectionService) checkLinkedSection(ctx context.Context, linkedSectionID, semesterID uuid.UUID) error {
+	linked, err := s.sectionRepo.GetByID(ctx, linkedSectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get linked section: %w", err)
+	}
+	if linked == nil || !linked.IsActive || linked.SemesterID != semesterID || linked.LinkedSectionID != nil {
+		return ErrInvalidLinkedSection
+	}
+	return nil
+}
+
+// invalidateLinkedSection drops the cached details of the section a component is linked
+// to, which list its active components
+func (s *SectionService) invalidateLinkedSection(ctx context.Context, section *domain.Section) {
+	if section.LinkedSectionID == nil {
+		return
+	}
+	if err := s.cacheService.Delete(ctx, interfaces.SectionDetailsCache.Key(*section.LinkedSectionID)); err != nil {
+		logger.Warn("Failed to invalidate section details for %s: %v", *section.LinkedSectionID, err)
+	}
+}
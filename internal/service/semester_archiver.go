@@ -0,0 +1,169 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSemesterNotEnded is returned when a semester is archived before its last day is over
+var ErrSemesterNotEnded = errors.New("semester has not ended")
+
+type SemesterArchiveReport = serviceInterfaces.SemesterArchiveReport
+
+// SemesterArchiver takes a semester that has ended out of the hot path. Its registrations
+// and waitlist entries move to the archive tables, where reports and course history still
+// read them, and the Redis keys of its sections and students are purged, so the live tables
+// and the cache only hold the semesters still being registered for.
+type SemesterArchiver struct {
+	semesterRepo     interfaces.SemesterRepository
+	sectionRepo      interfaces.SectionRepository
+	registrationRepo interfaces.RegistrationRepository
+	waitlistRepo     interfaces.WaitlistRepository
+	cacheService     interfaces.CacheService
+	location         *time.Location
+}
+
+// NewSemesterArchiver creates the archiver for the institution's time zone, in which the
+// semester's last day ends. A nil location means UTC.
+func NewSemesterArchiver(
+	semesterRepo interfaces.SemesterRepository,
+	sectionRepo interfaces.SectionRepository,
+	registrationRepo interfaces.RegistrationRepository,
+	waitlistRepo interfaces.WaitlistRepository,
+	cacheService interfaces.CacheService,
+	location *time.Location,
+) *SemesterArchiver {
+	if location == nil {
+		location = time.UTC
+	}
+	return &SemesterArchiver{
+		semesterRepo:     semesterRepo,
+		sectionRepo:      sectionRepo,
+		registrationRepo: registrationRepo,
+		waitlistRepo:     waitlistRepo,
+		cacheService:     cacheService,
+		location:         location,
+	}
+}
+
+// Archive archives the semester and purges its cache. Archiving a semester again only
+// purges the keys of its sections, since its students are no longer known from the live
+// tables; their views expire on their own. Keys that fail to purge are logged and counted
+// rather than stopping the run, which can be repeated for them.
+func (a *SemesterArchiver) Archive(ctx context.Context, semesterID uuid.UUID) (*SemesterArchiveReport, error) {
+	semester, err := a.semesterRepo.GetByID(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semester: %w", err)
+	}
+	if semester == nil {
+		return nil, ErrSemesterNotFound
+	}
+	if semester.ArchivedAt == nil && time.Now().Before(semester.EndsAt(a.location)) {
+		return nil, ErrSemesterNotEnded
+	}
+
+	sections, err := a.sectionRepo.GetBySemester(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections of semester %s: %w", semester.SemesterCode, err)
+	}
+
+	// The students and waitlist entries are read before the rows leave the live tables
+	students := make(map[uuid.UUID]bool)
+	waitlists := make(map[uuid.UUID][]*domain.WaitlistEntry, len(sections))
+	for _, section := range sections {
+		registrations, err := a.registrationRepo.GetBySectionID(ctx, section.SectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get registrations of section %s: %w", section.SectionID, err)
+		}
+		for _, registration := range registrations {
+			students[registration.StudentID] = true
+		}
+		entries, err := a.waitlistRepo.GetBySectionID(ctx, section.SectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get waitlist of section %s: %w", section.SectionID, err)
+		}
+		for _, entry := range entries {
+			students[entry.StudentID] = true
+		}
+		waitlists[section.SectionID] = entries
+	}
+
+	rows, err := a.semesterRepo.Archive(ctx, semesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SemesterArchiveReport{
+		SemesterID:      semesterID,
+		SemesterCode:    semester.SemesterCode,
+		AlreadyArchived: rows == nil,
+		Sections:        len(sections),
+		Students:        len(students),
+	}
+	if rows != nil {
+		report.Registrations = rows.Registrations
+		report.WaitlistEntries = rows.WaitlistEntries
+	}
+
+	for _, section := range sections {
+		report.KeysFailed += a.purgeSection(ctx, section.SectionID, waitlists[section.SectionID])
+	}
+	for studentID := range students {
+		if err := a.cacheService.InvalidateStudentCache(ctx, studentID, semesterID); err != nil {
+			logger.Warn("Failed to purge cached views of student %s: %v", studentID, err)
+			report.KeysFailed++
+		}
+	}
+	if err := a.cacheService.Delete(ctx, activeSemestersCacheKey); err != nil {
+		logger.Warn("Failed to invalidate active semesters: %v", err)
+		report.KeysFailed++
+	}
+
+	logger.Info("Archived semester %s: %d registrations and %d waitlist entries moved, cache of %d sections and %d students purged (already archived: %t, %d keys failed)",
+		semester.SemesterCode, report.Registrations, report.WaitlistEntries, report.Sections, report.Students, report.AlreadyArchived, report.KeysFailed)
+	return report, nil
+}
+
+// purgeSection drops the section's seat counters, cached views and waitlist, returning how
+// many of them failed. Entries the waitlist repository still holds once the rows are
+// archived are kept in Redis, and are deleted there.
+func (a *SemesterArchiver) purgeSection(ctx context.Context, sectionID uuid.UUID, entries []*domain.WaitlistEntry) int {
+	failed := 0
+	for _, key := range []string{interfaces.SectionSeatsKey.Key(sectionID), interfaces.SectionSeatPoolsKey.Key(sectionID)} {
+		if err := a.cacheService.Delete(ctx, key); err != nil {
+			logger.Warn("Failed to purge %s: %v", key, err)
+			failed++
+		}
+	}
+	if err := a.cacheService.InvalidateSectionCache(ctx, sectionID); err != nil {
+		logger.Warn("Failed to purge cached views of section %s: %v", sectionID, err)
+		failed++
+	}
+
+	for _, entry := range entries {
+		if err := a.cacheService.RemoveFromWaitlist(ctx, sectionID, entry.StudentID); err != nil {
+			logger.Warn("Failed to remove student %s from cached waitlist of section %s: %v", entry.StudentID, sectionID, err)
+			failed++
+		}
+	}
+	remaining, err := a.waitlistRepo.GetBySectionID(ctx, sectionID)
+	if err != nil {
+		logger.Warn("Failed to get remaining waitlist of section %s: %v", sectionID, err)
+		return failed + 1
+	}
+	for _, entry := range remaining {
+		if err := a.waitlistRepo.Delete(ctx, entry.WaitlistID); err != nil {
+			logger.Warn("Failed to delete waitlist entry %s of section %s: %v", entry.WaitlistID, sectionID, err)
+			failed++
+		}
+	}
+	return failed
+}
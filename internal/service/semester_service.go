@@ -0,0 +1,320 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Semester dates change a few times a term at most, so they are cached for long
+const SemesterCalendarTTL = 6 * time.Hour
+
+const activeSemestersCacheKey = "semesters:active"
+
+const semesterDateLayout = "2006-01-02"
+
+var (
+	ErrSemesterExists       = errors.New("semester code already exists")
+	ErrInvalidSemesterDates = errors.New("invalid semester dates")
+)
+
+type CreateSemesterRequest = serviceInterfaces.CreateSemesterRequest
+type UpdateSemesterRequest = serviceInterfaces.UpdateSemesterRequest
+
+type SemesterService struct {
+	semesterRepo interfaces.SemesterRepository
+	calendarRepo interfaces.CalendarEventRepository
+	cacheService interfaces.CacheService
+	location     *time.Location
+}
+
+// NewSemesterService creates the service for the institution's time zone. A nil location
+// means UTC.
+func NewSemesterService(
+	semesterRepo interfaces.SemesterRepository,
+	calendarRepo interfaces.CalendarEventRepository,
+	cacheService interfaces.CacheService,
+	location *time.Location,
+) *SemesterService {
+	if location == nil {
+		location = time.UTC
+	}
+	return &SemesterService{
+		semesterRepo: semesterRepo,
+		calendarRepo: calendarRepo,
+		cacheService: cacheService,
+		location:     location,
+	}
+}
+
+// Location is the term-local time zone windows and deadlines are evaluated in
+func (s *SemesterService) Location() *time.Location {
+	return s.location
+}
+
+// Now is the current time in the term-local time zone
+func (s *SemesterService) Now() time.Time {
+	return time.Now().In(s.location)
+}
+
+func (s *SemesterService) ListSemesters(ctx context.Context) ([]*domain.Semester, error) {
+	var semesters []*domain.Semester
+	if s.getCached(ctx, activeSemestersCacheKey, &semesters) {
+		return semesters, nil
+	}
+
+	semesters, err := s.semesterRepo.GetAllActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semesters: %w", err)
+	}
+	sort.Slice(semesters, func(i, j int) bool {
+		return semesters[i].StartDate.Before(semesters[j].StartDate)
+	})
+
+	s.setCached(ctx, activeSemestersCacheKey, semesters)
+	return semesters, nil
+}
+
+// ListAllSemesters returns every semester, inactive ones included, for administration
+func (s *SemesterService) ListAllSemesters(ctx context.Context) ([]*domain.Semester, error) {
+	semesters, err := s.semesterRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semesters: %w", err)
+	}
+	return semesters, nil
+}
+
+func (s *SemesterService) GetSemester(ctx context.Context, semesterID uuid.UUID) (*domain.Semester, error) {
+	semester, err := s.semesterRepo.GetByID(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semester: %w", err)
+	}
+	if semester == nil {
+		return nil, ErrSemesterNotFound
+	}
+	return semester, nil
+}
+
+func (s *SemesterService) CreateSemester(ctx context.Context, req *CreateSemesterRequest) (*domain.Semester, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.SemesterCode))
+	existing, err := s.semesterRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check semester code: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrSemesterExists
+	}
+
+	semester := &domain.Semester{
+		SemesterID:        uuid.New(),
+		SemesterCode:      code,
+		SemesterName:      strings.TrimSpace(req.SemesterName),
+		RegistrationStart: req.RegistrationStart,
+		RegistrationEnd:   req.RegistrationEnd,
+		DropDeadline:      req.DropDeadline,
+		WithdrawDeadline:  req.WithdrawDeadline,
+		IsActive:          true,
+	}
+	if semester.StartDate, err = time.Parse(semesterDateLayout, req.StartDate); err != nil {
+		return nil, fmt.Errorf("%w: start_date: %v", ErrInvalidSemesterDates, err)
+	}
+	if semester.EndDate, err = time.Parse(semesterDateLayout, req.EndDate); err != nil {
+		return nil, fmt.Errorf("%w: end_date: %v", ErrInvalidSemesterDates, err)
+	}
+	if err := validateSemesterDates(semester); err != nil {
+		return nil, err
+	}
+
+	if err := s.semesterRepo.Create(ctx, semester); err != nil {
+		return nil, fmt.Errorf("failed to create semester: %w", err)
+	}
+
+	s.invalidateSemesterCaches(ctx, semester.SemesterID)
+
+	logger.Info("Created semester %s (%s)", semester.SemesterID, semester.SemesterCode)
+	return semester, nil
+}
+
+// UpdateSemester changes the dates, name or status of a semester. Sections carry their
+// semester in cached details, which the registration guard reads, so those are dropped too.
+func (s *SemesterService) UpdateSemester(ctx context.Context, semesterID uuid.UUID, req *UpdateSemesterRequest) (*domain.Semester, error) {
+	semester, err := s.GetSemester(ctx, semesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SemesterName != nil {
+		semester.SemesterName = strings.TrimSpace(*req.SemesterName)
+	}
+	if req.StartDate != nil {
+		if semester.StartDate, err = time.Parse(semesterDateLayout, *req.StartDate); err != nil {
+			return nil, fmt.Errorf("%w: start_date: %v", ErrInvalidSemesterDates, err)
+		}
+	}
+	if req.EndDate != nil {
+		if semester.EndDate, err = time.Parse(semesterDateLayout, *req.EndDate); err != nil {
+			return nil, fmt.Errorf("%w: end_date: %v", ErrInvalidSemesterDates, err)
+		}
+	}
+	if req.RegistrationStart != nil {
+		semester.RegistrationStart = *req.RegistrationStart
+	}
+	if req.RegistrationEnd != nil {
+		semester.RegistrationEnd = *req.RegistrationEnd
+	}
+	if req.DropDeadline != nil {
+		semester.DropDeadline = req.DropDeadline
+	}
+	if req.WithdrawDeadline != nil {
+		semester.WithdrawDeadline = req.WithdrawDeadline
+	}
+	if req.IsActive != nil {
+		semester.IsActive = *req.IsActive
+	}
+	if err := validateSemesterDates(semester); err != nil {
+		return nil, err
+	}
+
+	if err := s.semesterRepo.Update(ctx, semester); err != nil {
+		return nil, err
+	}
+
+	s.invalidateSemesterCaches(ctx, semesterID)
+
+	logger.Info("Updated semester %s (%s)", semesterID, semester.SemesterCode)
+	return semester, nil
+}
+
+func validateSemesterDates(semester *domain.Semester) error {
+	if semester.EndDate.Before(semester.StartDate) {
+		return fmt.Errorf("%w: end_date is before start_date", ErrInvalidSemesterDates)
+	}
+	if !semester.RegistrationEnd.After(semester.RegistrationStart) {
+		return fmt.Errorf("%w: registration_end must be after registration_start", ErrInvalidSemesterDates)
+	}
+	if semester.DropDeadline != nil && semester.WithdrawDeadline != nil && semester.WithdrawDeadline.Before(*semester.DropDeadline) {
+		return fmt.Errorf("%w: withdraw_deadline is before drop_deadline", ErrInvalidSemesterDates)
+	}
+	return nil
+}
+
+// invalidateSemesterCaches drops the semester list, every cached view of the semester in
+// the cache key catalog, and the section details that embed the semester
+func (s *SemesterService) invalidateSemesterCaches(ctx context.Context, semesterID uuid.UUID) {
+	set := interfaces.CacheInvalidationSet(interfaces.CacheScopeSemester, semesterID)
+	for _, key := range append([]string{activeSemestersCacheKey}, set.Keys...) {
+		if err := s.cacheService.Delete(ctx, key); err != nil {
+			logger.Warn("Failed to invalidate %s: %v", key, err)
+		}
+	}
+	for _, pattern := range append(set.Patterns, interfaces.SectionDetailsCache.Pattern()) {
+		if err := s.cacheService.Clear(ctx, pattern); err != nil {
+			logger.Warn("Failed to invalidate %s for semester %s: %v", pattern, semesterID, err)
+		}
+	}
+}
+
+// GetCalendar returns the key dates of a semester in term-local time. The registration
+// window and any drop and withdraw deadlines come from the semester itself; other deadlines
+// and holidays come from its calendar events.
+func (s *SemesterService) GetCalendar(ctx context.Context, semesterID uuid.UUID) (*domain.SemesterCalendar, error) {
+	key := interfaces.SemesterCalendarCache.Key(semesterID)
+
+	var calendar domain.SemesterCalendar
+	if s.getCached(ctx, key, &calendar) {
+		local := calendar.InLocation(s.location)
+		return &local, nil
+	}
+
+	semester, err := s.semesterRepo.GetByID(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semester: %w", err)
+	}
+	if semester == nil {
+		return nil, ErrSemesterNotFound
+	}
+
+	events, err := s.calendarRepo.GetBySemesterID(ctx, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	registrationEnd := semester.RegistrationEnd
+	calendar = domain.SemesterCalendar{
+		Semester: *semester,
+		Events: []domain.CalendarEvent{{
+			SemesterID: semesterID,
+			EventType:  domain.CalendarRegistrationWindow,
+			Name:       "Registration",
+			StartsAt:   semester.RegistrationStart,
+			EndsAt:     &registrationEnd,
+		}},
+	}
+	if semester.DropDeadline != nil {
+		calendar.Events = append(calendar.Events, domain.CalendarEvent{
+			SemesterID: semesterID,
+			EventType:  domain.CalendarAddDropDeadline,
+			Name:       "Drop deadline",
+			StartsAt:   *semester.DropDeadline,
+		})
+	}
+	if semester.WithdrawDeadline != nil {
+		calendar.Events = append(calendar.Events, domain.CalendarEvent{
+			SemesterID: semesterID,
+			EventType:  domain.CalendarWithdrawalDeadline,
+			Name:       "Withdrawal deadline",
+			StartsAt:   *semester.WithdrawDeadline,
+		})
+	}
+	for _, event := range events {
+		// The semester row is authoritative for the registration window and for the
+		// deadlines it sets
+		switch {
+		case event.EventType == domain.CalendarRegistrationWindow,
+			event.EventType == domain.CalendarAddDropDeadline && semester.DropDeadline != nil,
+			event.EventType == domain.CalendarWithdrawalDeadline && semester.WithdrawDeadline != nil:
+			continue
+		}
+		calendar.Events = append(calendar.Events, *event)
+	}
+	sort.SliceStable(calendar.Events, func(i, j int) bool {
+		return calendar.Events[i].StartsAt.Before(calendar.Events[j].StartsAt)
+	})
+
+	s.setCached(ctx, key, &calendar)
+	local := calendar.InLocation(s.location)
+	return &local, nil
+}
+
+func (s *SemesterService) getCached(ctx context.Context, key string, dest any) bool {
+	cached, err := s.cacheService.Get(ctx, key)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(cached), dest); err != nil {
+		logger.Warn("Failed to unmarshal cached %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (s *SemesterService) setCached(ctx context.Context, key string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		logger.Warn("Failed to marshal %s for caching: %v", key, err)
+		return
+	}
+	if err := s.cacheService.Set(ctx, key, string(data), SemesterCalendarTTL); err != nil {
+		logger.Warn("Failed to cache %s: %v", key, err)
+	}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrHoldNotFound     = errors.New("hold not found")
+	ErrHoldOtherStudent = errors.New("hold reference belongs to another student")
+	ErrStudentOnHold    = errors.New("student has a hold on registration")
+)
+
+// ResultHoldBlocked is the registration result status for every requested section when the
+// student has an active hold; the result lists the kinds of hold
+const ResultHoldBlocked = "hold_blocked"
+
+type BursarHoldEvent = serviceInterfaces.BursarHoldEvent
+type PlaceHoldRequest = serviceInterfaces.PlaceHoldRequest
+
+// StudentHoldService places and releases the holds that keep students from registering,
+// and tells the student each time one is placed or lifted.
+type StudentHoldService struct {
+	holdRepo        interfaces.StudentHoldRepository
+	studentRepo     interfaces.StudentRepository
+	cacheService    interfaces.CacheService
+	studentNotifier interfaces.StudentNotifier
+}
+
+func NewStudentHoldService(
+	holdRepo interfaces.StudentHoldRepository,
+	studentRepo interfaces.StudentRepository,
+	cacheService interfaces.CacheService,
+	studentNotifier interfaces.StudentNotifier,
+) *StudentHoldService {
+	return &StudentHoldService{
+		holdRepo:        holdRepo,
+		studentRepo:     studentRepo,
+		cacheService:    cacheService,
+		studentNotifier: studentNotifier,
+	}
+}
+
+// activeHoldTypes returns the kinds of the student's active holds. The list is cached
+// alongside the student's details and dropped whenever a hold is placed or released.
+func (s *RegistrationService) activeHoldTypes(ctx context.Context, studentID uuid.UUID) ([]string, error) {
+	key := interfaces.StudentHoldsCache.Key(studentID)
+	if cached, err := s.cacheService.Get(ctx, key); err == nil {
+		var holdTypes []string
+		if err := json.Unmarshal([]byte(cached), &holdTypes); err == nil {
+			return holdTypes, nil
+		}
+		registrationLog(ctx, studentID, uuid.Nil).Warn("Failed to decode cached holds of student %s", studentID)
+	}
+
+	holds, err := s.studentHoldRepo.GetActiveByStudent(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check student holds: %w", err)
+	}
+	holdTypes := make([]string, 0, len(holds))
+	for _, hold := range holds {
+		if !slices.Contains(holdTypes, hold.HoldType) {
+			holdTypes = append(holdTypes, hold.HoldType)
+		}
+	}
+
+	if data, err := json.Marshal(holdTypes); err == nil {
+		if err := s.cacheService.Set(ctx, key, string(data), StudentDetailsTTL); err != nil {
+			registrationLog(ctx, studentID, uuid.Nil).Warn("Failed to cache holds of student %s: %v", studentID, err)
+		}
+	}
+	return holdTypes, nil
+}
+
+// holdBlockedResponse answers a registration from a student with an active hold, with a
+// hold_blocked result for each section naming the holds to clear
+func (s *RegistrationService) holdBlockedResponse(ctx context.Context, studentID uuid.UUID, sectionIDs []uuid.UUID) (*RegisterResponse, error) {
+	holdTypes, err := s.activeHoldTypes(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Registration is blocked by a %s hold", strings.Join(holdTypes, " and "))
+	response := &RegisterResponse{Results: make([]RegistrationResult, len(sectionIDs))}
+	for i, sectionID := range sectionIDs {
+		response.Results[i] = RegistrationResult{
+			SectionID: sectionID,
+			Status:    ResultHoldBlocked,
+			Message:   message,
+			HoldTypes: holdTypes,
+		}
+		s.emitRegistrationAttempt(studentID, response.Results[i])
+	}
+	registrationLog(ctx, studentID, uuid.Nil).Info("Registration of student %s blocked by holds: %s", studentID, strings.Join(holdTypes, ", "))
+	return response, nil
+}
+
+// PlaceHold puts a hold of the requested kind on the student. Staff holds carry no external
+// reference and are only released through ReleaseHold.
+func (s *StudentHoldService) PlaceHold(ctx context.Context, studentID uuid.UUID, req *PlaceHoldRequest) (*domain.StudentHold, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student: %w", err)
+	}
+	if student == nil {
+		return nil, ErrStudentNotFound
+	}
+
+	hold := &domain.StudentHold{
+		HoldID:    uuid.New(),
+		StudentID: studentID,
+		HoldType:  req.HoldType,
+		Source:    domain.HoldSourceStaff,
+		Reason:    strings.TrimSpace(req.Reason),
+		PlacedAt:  time.Now(),
+	}
+	if err := s.holdRepo.Create(ctx, hold); err != nil {
+		return nil, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	logger.Info("Placed %s hold %s on student %s", hold.HoldType, hold.HoldID, studentID)
+	s.holdsChanged(ctx, interfaces.StudentEventHoldPlaced, hold)
+	return hold, nil
+}
+
+// ReleaseHold lifts one of the student's holds. Releasing a hold that is already released
+// returns it unchanged.
+func (s *StudentHoldService) ReleaseHold(ctx context.Context, studentID, holdID uuid.UUID) (*domain.StudentHold, error) {
+	hold, err := s.holdRepo.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold == nil || hold.StudentID != studentID {
+		return nil, ErrHoldNotFound
+	}
+	if !hold.IsActive() {
+		return hold, nil
+	}
+
+	now := time.Now()
+	released, err := s.holdRepo.Release(ctx, holdID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		return hold, nil
+	}
+	hold.ReleasedAt = &now
+
+	logger.Info("Released %s hold %s on student %s", hold.HoldType, holdID, studentID)
+	s.holdsChanged(ctx, interfaces.StudentEventHoldReleased, hold)
+	return hold, nil
+}
+
+func (s *StudentHoldService) ListActiveHolds(ctx context.Context, studentID uuid.UUID) ([]*domain.StudentHold, error) {
+	holds, err := s.holdRepo.GetActiveByStudent(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+	return holds, nil
+}
+
+// ApplyBursarEvent places or releases the financial hold named by the bursar's reference.
+// The bursar retries callbacks it got no answer to, so applying an event twice changes
+// nothing the second time.
+func (s *StudentHoldService) ApplyBursarEvent(ctx context.Context, event *BursarHoldEvent) (*domain.StudentHold, error) {
+	student, err := s.studentRepo.GetByStudentNumber(ctx, strings.TrimSpace(event.StudentNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student: %w", err)
+	}
+	if student == nil {
+		return nil, ErrStudentNotFound
+	}
+
+	hold, err := s.holdRepo.GetByExternalRef(ctx, domain.HoldSourceBursar, event.HoldRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold != nil && hold.StudentID != student.StudentID {
+		return nil, fmt.Errorf("%w: %s", ErrHoldOtherStudent, event.HoldRef)
+	}
+
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	switch event.EventType {
+	case serviceInterfaces.BursarHoldPlaced:
+		if hold != nil {
+			return hold, nil
+		}
+		hold = &domain.StudentHold{
+			HoldID:      uuid.New(),
+			StudentID:   student.StudentID,
+			HoldType:    domain.HoldTypeFinancial,
+			Source:      domain.HoldSourceBursar,
+			ExternalRef: event.HoldRef,
+			Reason:      strings.TrimSpace(event.Reason),
+			PlacedAt:    occurredAt,
+		}
+		if err := s.holdRepo.Create(ctx, hold); err != nil {
+			return nil, fmt.Errorf("failed to place hold: %w", err)
+		}
+		logger.Info("Bursar placed financial hold %s (%s) on student %s", hold.HoldID, event.HoldRef, student.StudentNumber)
+		s.holdsChanged(ctx, interfaces.StudentEventHoldPlaced, hold)
+		return hold, nil
+
+	case serviceInterfaces.BursarHoldReleased:
+		if hold == nil {
+			return nil, ErrHoldNotFound
+		}
+		released, err := s.holdRepo.Release(ctx, hold.HoldID, occurredAt)
+		if err != nil {
+			return nil, err
+		}
+		if !released {
+			return hold, nil
+		}
+		hold.ReleasedAt = &occurredAt
+		logger.Info("Bursar released financial hold %s (%s) on student %s", hold.HoldID, event.HoldRef, student.StudentNumber)
+		s.holdsChanged(ctx, interfaces.StudentEventHoldReleased, hold)
+		return hold, nil
+
+	default:
+		return nil, fmt.Errorf("unknown bursar event type: %s", event.EventType)
+	}
+}
+
+// holdsChanged drops the student's cached holds, so the next registration sees the change,
+// and tells the student about it
+func (s *StudentHoldService) holdsChanged(ctx context.Context, eventType interfaces.StudentEventType, hold *domain.StudentHold) {
+	if err := s.cacheService.Delete(ctx, interfaces.StudentHoldsCache.Key(hold.StudentID)); err != nil {
+		logger.Warn("Failed to invalidate cached holds of student %s: %v", hold.StudentID, err)
+	}
+	s.notify(ctx, eventType, hold)
+}
+
+func (s *StudentHoldService) notify(ctx context.Context, eventType interfaces.StudentEventType, hold *domain.StudentHold) {
+	holdID := hold.HoldID
+	event := interfaces.StudentEvent{
+		Type:       eventType,
+		StudentID:  hold.StudentID,
+		HoldID:     &holdID,
+		Reason:     hold.HoldType,
+		OccurredAt: time.Now(),
+	}
+	if err := s.studentNotifier.Publish(ctx, event); err != nil {
+		logger.Warn("Failed to notify student %s of %s: %v", hold.StudentID, eventType, err)
+	}
+}
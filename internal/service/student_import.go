@@ -0,0 +1,187 @@
+package service
+
+import (
+	"cobra-template/pkg/logger"
+	"cobra-template/pkg/validator"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StudentImportCreated   = "created"
+	StudentImportDuplicate = "duplicate"
+	StudentImportValid     = "valid"
+	StudentImportInvalid   = "invalid"
+	StudentImportFailed    = "failed"
+)
+
+var ErrStudentImportHeader = errors.New("CSV header must contain student_number, first_name and last_name")
+
+// studentImportColumns are required; status, email, major, cohort and year may be given too
+var studentImportColumns = []string{"student_number", "first_name", "last_name"}
+
+type StudentImportRowResult struct {
+	Row           int       `json:"row"`
+	StudentNumber string    `json:"student_number"`
+	StudentID     uuid.UUID `json:"student_id,omitempty"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message,omitempty"`
+}
+
+type StudentImportReport struct {
+	DryRun  bool                     `json:"dry_run"`
+	Rows    int                      `json:"rows"`
+	Summary map[string]int           `json:"summary"`
+	Results []StudentImportRowResult `json:"results"`
+}
+
+// ImportStudents creates a student for every row read from r, as when a term's intake is
+// set up. A row whose student number is already taken, in the database or earlier in the
+// file, is reported as a duplicate and leaves the existing student alone. With dryRun the
+// rows are checked the same way without creating anyone.
+func (s *StudentService) ImportStudents(ctx context.Context, r io.Reader, dryRun bool) (*StudentImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrStudentImportHeader
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns, ok := importColumnIndexes(header, studentImportColumns)
+	if !ok {
+		return nil, ErrStudentImportHeader
+	}
+
+	report := &StudentImportReport{
+		DryRun:  dryRun,
+		Summary: make(map[string]int),
+		Results: make([]StudentImportRowResult, 0),
+	}
+	// Student numbers seen so far, so a file listing a student twice creates them once
+	seen := make(map[string]int)
+
+	for row := 2; ; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if report.Rows >= MaxImportRows {
+			return nil, ErrImportTooLarge
+		}
+		report.Rows++
+
+		var result StudentImportRowResult
+		if err != nil {
+			result = StudentImportRowResult{Row: row, Status: StudentImportInvalid, Message: fmt.Sprintf("Malformed CSV row: %v", err)}
+		} else {
+			result = s.importStudentRow(ctx, row, record, columns, dryRun, seen)
+		}
+
+		report.Summary[result.Status]++
+		report.Results = append(report.Results, result)
+	}
+
+	logger.Info("Imported %d student rows (dry run: %t): %v", report.Rows, dryRun, report.Summary)
+	return report, nil
+}
+
+func (s *StudentService) importStudentRow(ctx context.Context, row int, record []string, columns map[string]int, dryRun bool, seen map[string]int) StudentImportRowResult {
+	field := func(name string) string {
+		index, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return importField(record, index)
+	}
+
+	req := CreateStudentRequest{
+		StudentNumber:    field("student_number"),
+		FirstName:        field("first_name"),
+		LastName:         field("last_name"),
+		Email:            field("email"),
+		EnrollmentStatus: strings.ToLower(field("status")),
+		Major:            field("major"),
+		Cohort:           field("cohort"),
+	}
+	result := StudentImportRowResult{Row: row, StudentNumber: req.StudentNumber}
+	if year := field("year"); year != "" {
+		parsed, err := strconv.Atoi(year)
+		if err != nil {
+			result.Status = StudentImportInvalid
+			result.Message = fmt.Sprintf("year must be a number, got %q", year)
+			return result
+		}
+		req.Year = parsed
+	}
+	if err := validator.ValidateStruct(&req); err != nil {
+		messages := make([]string, 0)
+		for _, fieldError := range validator.FormatValidationError(err) {
+			messages = append(messages, fieldError.Message)
+		}
+		result.Status = StudentImportInvalid
+		result.Message = strings.Join(messages, "; ")
+		return result
+	}
+
+	if first, ok := seen[req.StudentNumber]; ok {
+		result.Status = StudentImportDuplicate
+		result.Message = fmt.Sprintf("Student number already appears on row %d", first)
+		return result
+	}
+	seen[req.StudentNumber] = row
+
+	existing, err := s.studentRepo.GetByStudentNumber(ctx, req.StudentNumber)
+	if err != nil {
+		result.Status = StudentImportFailed
+		result.Message = fmt.Sprintf("Failed to check student number: %v", err)
+		return result
+	}
+	if existing != nil {
+		result.StudentID = existing.StudentID
+		result.Status = StudentImportDuplicate
+		result.Message = "A student with this number already exists"
+		return result
+	}
+
+	if dryRun {
+		if err := s.checkEmailFree(ctx, strings.ToLower(req.Email), uuid.Nil); err != nil {
+			result.Status = StudentImportInvalid
+			result.Message = err.Error()
+			return result
+		}
+		result.Status = StudentImportValid
+		return result
+	}
+
+	student, err := s.CreateStudent(ctx, &req)
+	switch {
+	case errors.Is(err, ErrStudentNumberInUse):
+		result.Status = StudentImportDuplicate
+		result.Message = "A student with this number already exists"
+	case errors.Is(err, ErrEmailInUse):
+		result.Status = StudentImportInvalid
+		result.Message = err.Error()
+	case err != nil:
+		result.Status = StudentImportFailed
+		result.Message = err.Error()
+	default:
+		result.StudentID = student.StudentID
+		result.Status = StudentImportCreated
+	}
+	return result
+}
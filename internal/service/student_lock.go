@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const studentLockRetryInterval = 25 * time.Millisecond
+
+var ErrStudentBusy = errors.New("another registration request for this student is in progress")
+
+// lockStudent serialises Register and DropCourse for one student across instances, so two
+// concurrent requests cannot both spend seats or overwrite each other's cached registrations.
+// It waits up to the configured wait for the lock and returns the function that releases it.
+func (s *RegistrationService) lockStudent(ctx context.Context, studentID uuid.UUID) (func(), error) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	if s.studentLockTTL <= 0 || s.skipCache() {
+		// Enrolling from the database locks the student's row instead
+		return func() {}, nil
+	}
+
+	key := fmt.Sprintf("lock:student:%s", studentID.String())
+	token := uuid.NewString()
+	deadline := time.Now().Add(s.studentLockWait)
+
+	for {
+		acquired, err := s.cacheService.AcquireLock(ctx, key, token, s.studentLockTTL)
+		if err != nil {
+			if s.cacheOutage(ctx, "lock student", err) {
+				return func() {}, nil
+			}
+			return nil, fmt.Errorf("failed to lock student: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			return nil, ErrStudentBusy
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(studentLockRetryInterval):
+		}
+	}
+
+	return func() {
+		// Release even if the request was cancelled, otherwise the student waits out the TTL
+		released, err := s.cacheService.ReleaseLock(context.WithoutCancel(ctx), key, token)
+		if err != nil {
+			log.Warn("Failed to release lock for student %s: %v", studentID, err)
+		} else if !released {
+			log.Warn("Lock for student %s expired before it was released", studentID)
+		}
+	}, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notifyPromotion tells a promoted student what happened: a seat offer to accept when offers
+// are enabled, otherwise the enrollment itself. Notifications are best effort; the student
+// still sees the outcome in their registrations and offers.
+func (s *RegistrationService) notifyPromotion(ctx context.Context, sectionID, studentID uuid.UUID, offer *domain.SeatOffer) {
+	log := registrationLog(ctx, studentID, sectionID)
+	if s.studentNotifier == nil {
+		return
+	}
+
+	event := interfaces.StudentEvent{
+		Type:       interfaces.StudentEventWaitlistPromoted,
+		StudentID:  studentID,
+		SectionID:  sectionID,
+		OccurredAt: time.Now(),
+	}
+	if offer != nil {
+		event.Type = interfaces.StudentEventSeatOffered
+		event.OfferID = &offer.OfferID
+		event.ExpiresAt = &offer.ExpiresAt
+	}
+
+	if err := s.studentNotifier.Publish(ctx, event); err != nil {
+		log.Warn("Failed to notify student %s of %s in section %s: %v", studentID, event.Type, sectionID, err)
+	}
+}
+
+// notifyWaitlistExpired tells a student their waitlist entry ran out and they are no longer
+// waiting for the section
+func (s *RegistrationService) notifyWaitlistExpired(ctx context.Context, entry *domain.WaitlistEntry) {
+	log := registrationLog(ctx, entry.StudentID, entry.SectionID)
+	if s.studentNotifier == nil {
+		return
+	}
+
+	event := interfaces.StudentEvent{
+		Type:       interfaces.StudentEventWaitlistExpired,
+		StudentID:  entry.StudentID,
+		SectionID:  entry.SectionID,
+		ExpiresAt:  entry.ExpiresAt,
+		OccurredAt: time.Now(),
+	}
+	if err := s.studentNotifier.Publish(ctx, event); err != nil {
+		log.Warn("Failed to notify student %s of %s in section %s: %v", entry.StudentID, event.Type, entry.SectionID, err)
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// studentScheduleTTL is short for the same reason as the roster's: the schedule is dropped
+// when a registration event is recorded, which can come before the change is written
+const studentScheduleTTL = 2 * time.Minute
+
+// weekdayNames name the days of WeekdayLetters in the same order
+var weekdayNames = [...]string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// StudentSchedule is a student's week in one semester: the sections they are enrolled in,
+// laid out on the days they meet, and the sections without a fixed schedule. Meeting times
+// are in Timezone, the term-local time zone.
+type StudentSchedule struct {
+	StudentID    uuid.UUID           `json:"student_id"`
+	SemesterID   uuid.UUID           `json:"semester_id"`
+	SemesterCode string              `json:"semester_code,omitempty"`
+	SemesterName string              `json:"semester_name,omitempty"`
+	StartDate    time.Time           `json:"start_date"`
+	EndDate      time.Time           `json:"end_date"`
+	Timezone     string              `json:"timezone"`
+	CreditHours  int                 `json:"credit_hours"`
+	Days         []*ScheduleDay      `json:"days"`
+	Unscheduled  []*ScheduledSection `json:"unscheduled"`
+}
+
+// ScheduleDay holds the meetings of one weekday, earliest first
+type ScheduleDay struct {
+	Weekday  string              `json:"weekday"`
+	Meetings []*ScheduledSection `json:"meetings"`
+}
+
+type ScheduledSection struct {
+	SectionID     uuid.UUID `json:"section_id"`
+	CourseCode    string    `json:"course_code"`
+	CourseName    string    `json:"course_name"`
+	SectionNumber string    `json:"section_number"`
+	CreditHours   int       `json:"credit_hours"`
+	MeetingDays   string    `json:"meeting_days,omitempty"`
+	StartTime     string    `json:"start_time,omitempty"`
+	EndTime       string    `json:"end_time,omitempty"`
+	Room          string    `json:"room,omitempty"`
+	Instructor    string    `json:"instructor,omitempty"`
+}
+
+// GetStudentSchedule returns the weekly schedule of the sections the student is enrolled in
+// for the semester, cached for a short while. Registrations come from the student's cached
+// view, so an enrollment the database has not caught up with shows already.
+func (s *RegistrationService) GetStudentSchedule(ctx context.Context, studentID, semesterID uuid.UUID) (*StudentSchedule, error) {
+	log := registrationLog(ctx, studentID, uuid.Nil)
+	key := interfaces.StudentScheduleCache.SemesterKey(studentID, semesterID)
+	if cached, err := s.cacheService.Get(ctx, key); err == nil {
+		var schedule StudentSchedule
+		if err := json.Unmarshal([]byte(cached), &schedule); err == nil {
+			return &schedule, nil
+		}
+		log.Warn("Failed to decode cached schedule of student %s", studentID)
+	}
+
+	registrations, err := s.GetStudentRegistrations(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &StudentSchedule{
+		StudentID:   studentID,
+		SemesterID:  semesterID,
+		Timezone:    time.UTC.String(),
+		Days:        make([]*ScheduleDay, len(weekdayNames)),
+		Unscheduled: []*ScheduledSection{},
+	}
+	if s.semesterService != nil {
+		semester, err := s.semesterService.GetSemester(ctx, semesterID)
+		if err != nil {
+			return nil, err
+		}
+		schedule.SemesterCode = semester.SemesterCode
+		schedule.SemesterName = semester.SemesterName
+		schedule.StartDate = semester.StartDate
+		schedule.EndDate = semester.EndDate
+		schedule.Timezone = s.semesterService.Location().String()
+	}
+	for i, name := range weekdayNames {
+		schedule.Days[i] = &ScheduleDay{Weekday: name, Meetings: []*ScheduledSection{}}
+	}
+	for _, registration := range registrations {
+		if registration.Status != domain.StatusEnrolled {
+			continue
+		}
+		section, err := s.getSectionMetadata(ctx, registration.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		if section == nil || section.SemesterID != semesterID {
+			continue
+		}
+
+		scheduled := scheduledSection(section)
+		schedule.CreditHours += scheduled.CreditHours
+		if section.MeetingDays == "" || section.StartTime == "" {
+			schedule.Unscheduled = append(schedule.Unscheduled, scheduled)
+			continue
+		}
+		for i, letter := range domain.WeekdayLetters {
+			if strings.ContainsRune(section.MeetingDays, letter) {
+				schedule.Days[i].Meetings = append(schedule.Days[i].Meetings, scheduled)
+			}
+		}
+	}
+	for _, day := range schedule.Days {
+		sort.SliceStable(day.Meetings, func(i, j int) bool {
+			return day.Meetings[i].StartTime < day.Meetings[j].StartTime
+		})
+	}
+	sort.SliceStable(schedule.Unscheduled, func(i, j int) bool {
+		return schedule.Unscheduled[i].CourseCode < schedule.Unscheduled[j].CourseCode
+	})
+
+	if data, err := json.Marshal(schedule); err == nil {
+		if err := s.cacheService.Set(ctx, key, string(data), studentScheduleTTL); err != nil {
+			log.Warn("Failed to cache schedule of student %s: %v", studentID, err)
+		}
+	}
+	return schedule, nil
+}
+
+func scheduledSection(section *domain.Section) *ScheduledSection {
+	return &ScheduledSection{
+		SectionID:     section.SectionID,
+		CourseCode:    section.Course.CourseCode,
+		CourseName:    section.Course.CourseName,
+		SectionNumber: section.SectionNumber,
+		CreditHours:   section.Course.CreditHours,
+		MeetingDays:   section.MeetingDays,
+		StartTime:     section.StartTime,
+		EndTime:       section.EndTime,
+		Room:          section.Room,
+		Instructor:    section.Instructor,
+	}
+}
+
+// invalidateStudentSchedule drops the student's schedule for the semester of the section
+// their registration changed in. Without the section the schedule is left to expire.
+func (s *RegistrationService) invalidateStudentSchedule(ctx context.Context, studentID, sectionID uuid.UUID) {
+	log := registrationLog(ctx, studentID, sectionID)
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil || section == nil {
+		log.Warn("Failed to invalidate schedule of student %s: section %s not found: %v", studentID, sectionID, err)
+		return
+	}
+	if err := s.cacheService.Delete(ctx, interfaces.StudentScheduleCache.SemesterKey(studentID, section.SemesterID)); err != nil {
+		log.Warn("Failed to invalidate schedule of student %s: %v", studentID, err)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bufio"
+	domain "cobra-template/internal/domain/registration"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrScheduleWithoutTerm is returned when a schedule without semester dates is exported,
+// since its meetings cannot be placed on a calendar
+var ErrScheduleWithoutTerm = errors.New("schedule has no semester dates")
+
+// icsWeekdays are the iCalendar day codes of WeekdayLetters in the same order
+var icsWeekdays = [...]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+const (
+	icsDateTime    = "20060102T150405"
+	icsLineOctets  = 75
+	icsProductID   = "-//cobra-template//Course Registration//EN"
+	icsUIDHostname = "registration"
+)
+
+// WriteICS writes the schedule as an iCalendar feed with one weekly recurring event per
+// scheduled section, from its first meeting on or after the semester's start date to the
+// semester's end date. Sections without a fixed schedule are left out.
+func (sch *StudentSchedule) WriteICS(w io.Writer) error {
+	if sch.StartDate.IsZero() || sch.EndDate.IsZero() {
+		return ErrScheduleWithoutTerm
+	}
+	location, err := time.LoadLocation(sch.Timezone)
+	if err != nil {
+		return fmt.Errorf("failed to load time zone %s: %w", sch.Timezone, err)
+	}
+
+	writer := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeICSLine(writer, name+":"+value)
+	}
+	stamp := time.Now().UTC().Format(icsDateTime) + "Z"
+	// The last meeting may start at any time on the end date
+	until := time.Date(sch.EndDate.Year(), sch.EndDate.Month(), sch.EndDate.Day(), 23, 59, 59, 0, location).UTC().Format(icsDateTime) + "Z"
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", icsProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICSText(strings.TrimSpace(sch.SemesterName+" schedule")))
+	line("X-WR-TIMEZONE", location.String())
+	for _, section := range sch.scheduledSections() {
+		start, end, ok := firstMeeting(section, sch.StartDate, location)
+		if !ok {
+			continue
+		}
+		var days []string
+		for i, letter := range domain.WeekdayLetters {
+			if strings.ContainsRune(section.MeetingDays, letter) {
+				days = append(days, icsWeekdays[i])
+			}
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("%s-%s@%s", section.SectionID, sch.StudentID, icsUIDHostname))
+		line("DTSTAMP", stamp)
+		line("DTSTART;TZID="+location.String(), start.Format(icsDateTime))
+		line("DTEND;TZID="+location.String(), end.Format(icsDateTime))
+		line("RRULE", fmt.Sprintf("FREQ=WEEKLY;BYDAY=%s;UNTIL=%s", strings.Join(days, ","), until))
+		line("SUMMARY", escapeICSText(fmt.Sprintf("%s-%s %s", section.CourseCode, section.SectionNumber, section.CourseName)))
+		if section.Room != "" {
+			line("LOCATION", escapeICSText(section.Room))
+		}
+		if section.Instructor != "" {
+			line("DESCRIPTION", escapeICSText("Instructor: "+section.Instructor))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return writer.Flush()
+}
+
+// scheduledSections lists every section on the schedule's days once, in the order they
+// first meet in the week
+func (sch *StudentSchedule) scheduledSections() []*ScheduledSection {
+	seen := make(map[uuid.UUID]bool)
+	var sections []*ScheduledSection
+	for _, day := range sch.Days {
+		for _, meeting := range day.Meetings {
+			if seen[meeting.SectionID] {
+				continue
+			}
+			seen[meeting.SectionID] = true
+			sections = append(sections, meeting)
+		}
+	}
+	return sections
+}
+
+// firstMeeting is the start and end of the section's first meeting on or after the term
+// start date. ok is false when the section's times cannot be read.
+func firstMeeting(section *ScheduledSection, termStart time.Time, location *time.Location) (start, end time.Time, ok bool) {
+	startTime, err := time.Parse("15:04", section.StartTime)
+	if err != nil {
+		return start, end, false
+	}
+	endTime, err := time.Parse("15:04", section.EndTime)
+	if err != nil {
+		return start, end, false
+	}
+
+	for offset := 0; offset < len(domain.WeekdayLetters); offset++ {
+		day := time.Date(termStart.Year(), termStart.Month(), termStart.Day()+offset, 0, 0, 0, 0, location)
+		// time.Weekday starts the week on Sunday, WeekdayLetters on Monday
+		letter := domain.WeekdayLetters[(int(day.Weekday())+6)%7]
+		if !strings.ContainsRune(section.MeetingDays, rune(letter)) {
+			continue
+		}
+		start = time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, location)
+		end = time.Date(day.Year(), day.Month(), day.Day(), endTime.Hour(), endTime.Minute(), 0, 0, location)
+		return start, end, true
+	}
+	return start, end, false
+}
+
+// escapeICSText escapes the characters iCalendar TEXT values reserve
+func escapeICSText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeICSLine writes a content line ending in CRLF, folded so no line is longer than 75
+// octets. Folds fall between UTF-8 sequences, never inside one.
+func writeICSLine(w *bufio.Writer, content string) {
+	limit := icsLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		// The leading space of a continuation line counts against its length
+		limit = icsLineOctets - 1
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	serviceInterfaces "cobra-template/internal/interfaces/service"
+	"cobra-template/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStudentNotFound       = errors.New("student not found")
+	ErrEmailInUse            = errors.New("email is already used by another student")
+	ErrProfileFieldForbidden = errors.New("not allowed to change profile field")
+	ErrStudentArchived       = errors.New("student is archived; registrations are read-only")
+	ErrNoArchiveSelection    = errors.New("student_ids or cohort is required")
+	ErrStudentNumberInUse    = errors.New("student number is already used by another student")
+)
+
+type CreateStudentRequest = serviceInterfaces.CreateStudentRequest
+type UpdateStudentRequest = serviceInterfaces.UpdateStudentRequest
+type UpdateStudentProfileRequest = serviceInterfaces.UpdateStudentProfileRequest
+type ArchiveStudentsRequest = serviceInterfaces.ArchiveStudentsRequest
+type ArchiveStudentsResult = serviceInterfaces.ArchiveStudentsResult
+
+// ProfileEditor is who is changing a student profile. Legal names are owned by the
+// registrar; students may only maintain how they are addressed and contacted.
+type ProfileEditor string
+
+const (
+	ProfileEditorStudent   ProfileEditor = "student"
+	ProfileEditorRegistrar ProfileEditor = "registrar"
+)
+
+// StudentOverview is a student's details with a summary of where their registrations stand
+type StudentOverview struct {
+	Student *domain.Student `json:"student"`
+	Summary StudentSummary  `json:"summary"`
+}
+
+// StudentSummary counts the enrollments of active semesters, which past terms' completed
+// courses would otherwise inflate, and the student's waitlist places and active holds
+type StudentSummary struct {
+	EnrolledSections   int      `json:"enrolled_sections"`
+	EnrolledCredits    int      `json:"enrolled_credits"`
+	WaitlistedSections int      `json:"waitlisted_sections"`
+	Holds              int      `json:"holds"`
+	HoldTypes          []string `json:"hold_types"`
+}
+
+type StudentService struct {
+	studentRepo  interfaces.StudentRepository
+	cacheService interfaces.CacheService
+	// registrationService serves the cached views GetStudent is put together from. It is nil
+	// for tools that only maintain student records.
+	registrationService *RegistrationService
+}
+
+func NewStudentService(studentRepo interfaces.StudentRepository, cacheService interfaces.CacheService, registrationService *RegistrationService) *StudentService {
+	return &StudentService{
+		studentRepo:         studentRepo,
+		cacheService:        cacheService,
+		registrationService: registrationService,
+	}
+}
+
+// GetStudent returns the student's details with their enrollment summary. Every part comes
+// from the cached views registration keeps up to date, so enrollments the database has not
+// caught up with are counted already.
+func (s *StudentService) GetStudent(ctx context.Context, studentID uuid.UUID) (*StudentOverview, error) {
+	if s.registrationService == nil {
+		return nil, errors.New("registration service not available in student service")
+	}
+	registrations := s.registrationService
+
+	student, err := registrations.GetStudentDetails(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	overview := &StudentOverview{Student: student}
+
+	enrolled, err := registrations.GetStudentRegistrations(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, registration := range enrolled {
+		if registration.Status != domain.StatusEnrolled {
+			continue
+		}
+		section, err := registrations.getSectionMetadata(ctx, registration.SectionID)
+		if err != nil {
+			return nil, err
+		}
+		if section == nil || !section.Semester.IsActive {
+			continue
+		}
+		overview.Summary.EnrolledSections++
+		overview.Summary.EnrolledCredits += section.Course.CreditHours
+	}
+
+	waitlisted, err := registrations.GetStudentWaitlistStatus(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	overview.Summary.WaitlistedSections = len(waitlisted)
+
+	holdTypes, err := registrations.activeHoldTypes(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	overview.Summary.Holds = len(holdTypes)
+	overview.Summary.HoldTypes = holdTypes
+	return overview, nil
+}
+
+func (s *StudentService) GetProfile(ctx context.Context, studentID uuid.UUID) (*domain.Student, error) {
+	student, err := s.studentRepo.GetByID(ctx, studentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get student: %w", err)
+	}
+	if student == nil {
+		return nil, ErrStudentNotFound
+	}
+	return student, nil
+}
+
+// UpdateProfile applies the fields present in req after checking that editor may change
+// each of them. The cached student details are dropped so registration reads the new values.
+func (s *StudentService) UpdateProfile(ctx context.Context, studentID uuid.UUID, req *UpdateStudentProfileRequest, editor ProfileEditor) (*domain.Student, error) {
+	if forbidden := forbiddenProfileFields(req, editor); len(forbidden) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrProfileFieldForbidden, strings.Join(forbidden, ", "))
+	}
+
+	student, err := s.GetProfile(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FirstName != nil {
+		student.FirstName = strings.TrimSpace(*req.FirstName)
+	}
+	if req.LastName != nil {
+		student.LastName = strings.TrimSpace(*req.LastName)
+	}
+	if req.PreferredName != nil {
+		student.PreferredName = strings.TrimSpace(*req.PreferredName)
+	}
+	if req.Phone != nil {
+		student.Phone = *req.Phone
+	}
+	if req.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*req.Email))
+		if err := s.checkEmailFree(ctx, email, studentID); err != nil {
+			return nil, err
+		}
+		student.Email = email
+	}
+	if req.DeadlineReminders != nil {
+		student.DeadlineReminders = *req.DeadlineReminders
+	}
+
+	if err := s.studentRepo.UpdateProfile(ctx, student); err != nil {
+		return nil, err
+	}
+	student.Version++
+
+	s.invalidateStudentDetails(ctx, studentID)
+
+	logger.Info("Updated profile of student %s as %s", studentID, editor)
+	return student, nil
+}
+
+// CreateStudent adds a student record. Student numbers and email addresses must not belong
+// to another student.
+func (s *StudentService) CreateStudent(ctx context.Context, req *CreateStudentRequest) (*domain.Student, error) {
+	number := strings.TrimSpace(req.StudentNumber)
+	if err := s.checkStudentNumberFree(ctx, number, uuid.Nil); err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if err := s.checkEmailFree(ctx, email, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	status := req.EnrollmentStatus
+	if status == "" {
+		status = domain.EnrollmentStatusActive
+	}
+	student := &domain.Student{
+		StudentID:         uuid.New(),
+		StudentNumber:     number,
+		FirstName:         strings.TrimSpace(req.FirstName),
+		LastName:          strings.TrimSpace(req.LastName),
+		PreferredName:     strings.TrimSpace(req.PreferredName),
+		Email:             email,
+		Phone:             req.Phone,
+		EnrollmentStatus:  status,
+		Major:             strings.TrimSpace(req.Major),
+		Cohort:            strings.TrimSpace(req.Cohort),
+		Year:              req.Year,
+		DeadlineReminders: true,
+		Version:           1,
+	}
+	if err := s.studentRepo.Create(ctx, student); err != nil {
+		return nil, fmt.Errorf("failed to create student: %w", err)
+	}
+
+	logger.Info("Created student %s (%s)", student.StudentID, student.StudentNumber)
+	return student, nil
+}
+
+// UpdateStudent applies an administrator's changes to the student record, including its
+// enrollment status. Archived students are read-only; their record is history.
+func (s *StudentService) UpdateStudent(ctx context.Context, studentID uuid.UUID, req *UpdateStudentRequest) (*domain.Student, error) {
+	student, err := s.GetProfile(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if student.IsArchived() {
+		return nil, ErrStudentArchived
+	}
+
+	if req.StudentNumber != nil {
+		number := strings.TrimSpace(*req.StudentNumber)
+		if err := s.checkStudentNumberFree(ctx, number, studentID); err != nil {
+			return nil, err
+		}
+		student.StudentNumber = number
+	}
+	if req.FirstName != nil {
+		student.FirstName = strings.TrimSpace(*req.FirstName)
+	}
+	if req.LastName != nil {
+		student.LastName = strings.TrimSpace(*req.LastName)
+	}
+	if req.PreferredName != nil {
+		student.PreferredName = strings.TrimSpace(*req.PreferredName)
+	}
+	if req.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*req.Email))
+		if err := s.checkEmailFree(ctx, email, studentID); err != nil {
+			return nil, err
+		}
+		student.Email = email
+	}
+	if req.Phone != nil {
+		student.Phone = *req.Phone
+	}
+	if req.EnrollmentStatus != nil {
+		student.EnrollmentStatus = *req.EnrollmentStatus
+	}
+	if req.Major != nil {
+		student.Major = strings.TrimSpace(*req.Major)
+	}
+	if req.Cohort != nil {
+		student.Cohort = strings.TrimSpace(*req.Cohort)
+	}
+	if req.Year != nil {
+		student.Year = *req.Year
+	}
+
+	if err := s.studentRepo.Update(ctx, student); err != nil {
+		return nil, err
+	}
+	student.Version++
+
+	s.invalidateStudentDetails(ctx, studentID)
+
+	logger.Info("Updated student %s (%s)", studentID, student.StudentNumber)
+	return student, nil
+}
+
+// DeactivateStudent stops the student from taking new seats. Their registrations stay as
+// they are, and the student can be made active again with UpdateStudent.
+func (s *StudentService) DeactivateStudent(ctx context.Context, studentID uuid.UUID) (*domain.Student, error) {
+	student, err := s.GetProfile(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if student.IsArchived() {
+		return nil, ErrStudentArchived
+	}
+	if student.EnrollmentStatus == domain.EnrollmentStatusInactive {
+		return student, nil
+	}
+
+	student.EnrollmentStatus = domain.EnrollmentStatusInactive
+	if err := s.studentRepo.Update(ctx, student); err != nil {
+		return nil, err
+	}
+	student.Version++
+
+	s.invalidateStudentDetails(ctx, studentID)
+
+	logger.Info("Deactivated student %s (%s)", studentID, student.StudentNumber)
+	return student, nil
+}
+
+// checkStudentNumberFree fails with ErrStudentNumberInUse when another student than
+// studentID has the number
+func (s *StudentService) checkStudentNumberFree(ctx context.Context, number string, studentID uuid.UUID) error {
+	owner, err := s.studentRepo.GetByStudentNumber(ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to check student number: %w", err)
+	}
+	if owner != nil && owner.StudentID != studentID {
+		return ErrStudentNumberInUse
+	}
+	return nil
+}
+
+// checkEmailFree fails with ErrEmailInUse when another student than studentID has the
+// email address. An empty address is always free.
+func (s *StudentService) checkEmailFree(ctx context.Context, email string, studentID uuid.UUID) error {
+	if email == "" {
+		return nil
+	}
+	owner, err := s.studentRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if owner != nil && owner.StudentID != studentID {
+		return ErrEmailInUse
+	}
+	return nil
+}
+
+// invalidateStudentDetails drops the cached student, so registration checks read the change
+func (s *StudentService) invalidateStudentDetails(ctx context.Context, studentID uuid.UUID) {
+	if err := s.cacheService.Delete(ctx, interfaces.StudentDetailsCache.Key(studentID)); err != nil {
+		logger.Warn("Failed to invalidate student details for %s: %v", studentID, err)
+	}
+}
+
+// forbiddenProfileFields lists the fields in req that editor is not allowed to change
+func forbiddenProfileFields(req *UpdateStudentProfileRequest, editor ProfileEditor) []string {
+	if editor == ProfileEditorRegistrar {
+		return nil
+	}
+
+	var forbidden []string
+	if req.FirstName != nil {
+		forbidden = append(forbidden, "first_name")
+	}
+	if req.LastName != nil {
+		forbidden = append(forbidden, "last_name")
+	}
+	return forbidden
+}
+
+// ArchiveStudents moves graduated students to the archived status. Students already archived
+// are left alone, so re-running the job for a cohort is harmless.
+func (s *StudentService) ArchiveStudents(ctx context.Context, req *ArchiveStudentsRequest) (*ArchiveStudentsResult, error) {
+	if len(req.StudentIDs) == 0 && req.Cohort == "" {
+		return nil, ErrNoArchiveSelection
+	}
+
+	archived, err := s.studentRepo.Archive(ctx, req.StudentIDs, req.Cohort, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Registration checks read the cached student, so it has to go for the archive to bite
+	for _, studentID := range archived {
+		s.invalidateStudentDetails(ctx, studentID)
+	}
+
+	logger.Info("Archived %d students (cohort %q, %d explicit IDs)", len(archived), req.Cohort, len(req.StudentIDs))
+	return &ArchiveStudentsResult{
+		Archived:   len(archived),
+		StudentIDs: archived,
+	}, nil
+}
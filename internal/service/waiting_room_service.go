@@ -0,0 +1,124 @@
+package service
+
+import (
+	interfaces "cobra-template/internal/interfaces/infrastructure"
+	"cobra-template/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// WaitingRoomPollInterval is how often queued callers are asked to poll their ticket
+	WaitingRoomPollInterval = 2 * time.Second
+	// WaitingRoomStaleAfter drops queued callers that stopped polling
+	WaitingRoomStaleAfter = 30 * time.Second
+	// waitingRoomSlotTTL bounds how long a request that never released its slot holds it
+	waitingRoomSlotTTL = 30 * time.Second
+	// waitingRoomAdmitTTL is how long an admitted caller has to retry the request
+	waitingRoomAdmitTTL = time.Minute
+)
+
+var ErrWaitingTicketNotFound = errors.New("waiting room ticket not found or expired")
+
+// WaitingRoomService is the front door of registration. While fewer than limit
+// registrations are in flight requests go straight through; beyond that each caller gets a
+// ticket and waits its turn. A limit of zero or less disables the waiting room.
+type WaitingRoomService struct {
+	waitingRoom interfaces.WaitingRoom
+	limit       int
+}
+
+func NewWaitingRoomService(waitingRoom interfaces.WaitingRoom, limit int) *WaitingRoomService {
+	return &WaitingRoomService{
+		waitingRoom: waitingRoom,
+		limit:       limit,
+	}
+}
+
+func (s *WaitingRoomService) Enabled() bool {
+	return s.limit > 0
+}
+
+// Admit decides whether a request may run now. It returns the slot the request holds until
+// Release, or the ticket to wait with when it has to queue. A ticket token from an earlier
+// response is honoured once admitted; a lapsed one queues the caller again. If the waiting
+// room is unavailable requests are let through rather than blocking registration.
+func (s *WaitingRoomService) Admit(ctx context.Context, token string) (string, *interfaces.WaitingTicket) {
+	if token != "" {
+		ticket, err := s.waitingRoom.Poll(ctx, token, s.limit, waitingRoomAdmitTTL)
+		if err != nil {
+			logger.Warn("Waiting room unavailable, admitting request: %v", err)
+			return "", nil
+		}
+		if ticket != nil && !ticket.Admitted {
+			return "", ticket
+		}
+		if ticket != nil {
+			claimed, err := s.waitingRoom.Claim(ctx, token, waitingRoomSlotTTL)
+			if err != nil {
+				logger.Warn("Waiting room unavailable, admitting request: %v", err)
+				return "", nil
+			}
+			if claimed {
+				return token, nil
+			}
+		}
+	}
+
+	slotID := uuid.NewString()
+	entered, err := s.waitingRoom.Enter(ctx, slotID, s.limit, waitingRoomSlotTTL)
+	if err != nil {
+		logger.Warn("Waiting room unavailable, admitting request: %v", err)
+		return "", nil
+	}
+	if entered {
+		return slotID, nil
+	}
+
+	token, err = newWaitingRoomToken()
+	if err == nil {
+		var ticket *interfaces.WaitingTicket
+		if ticket, err = s.waitingRoom.Join(ctx, token); err == nil {
+			return "", ticket
+		}
+	}
+	logger.Warn("Failed to queue request in waiting room, admitting it: %v", err)
+	return "", nil
+}
+
+// Release frees the slot taken by Admit so the next caller in line can be admitted
+func (s *WaitingRoomService) Release(ctx context.Context, slotID string) {
+	if slotID == "" {
+		return
+	}
+	if err := s.waitingRoom.Release(ctx, slotID); err != nil {
+		logger.Warn("Failed to release waiting room slot %s: %v", slotID, err)
+	}
+}
+
+// GetTicket reports the caller's place in line, admitting them if their turn has come.
+// Polling is what keeps a ticket alive.
+func (s *WaitingRoomService) GetTicket(ctx context.Context, token string) (*interfaces.WaitingTicket, error) {
+	ticket, err := s.waitingRoom.Poll(ctx, token, s.limit, waitingRoomAdmitTTL)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrWaitingTicketNotFound
+	}
+	return ticket, nil
+}
+
+func newWaitingRoomToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate waiting room token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
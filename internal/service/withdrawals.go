@@ -0,0 +1,58 @@
+package service
+
+import (
+	domain "cobra-template/internal/domain/registration"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrWithdrawDeadlinePassed = errors.New("the withdraw deadline for the section's semester has passed")
+
+// dropOutcome says what dropping the section does now: a drop that frees the seat before the
+// semester's drop deadline, and a withdrawal that keeps it until the withdraw deadline
+func (s *RegistrationService) dropOutcome(ctx context.Context, sectionID uuid.UUID) (domain.RegistrationStatus, error) {
+	section, err := s.getSectionMetadata(ctx, sectionID)
+	if err != nil {
+		return "", err
+	}
+	if section == nil {
+		return "", ErrSectionNotFound
+	}
+
+	outcome, ok := section.Semester.DropOutcome(s.termNow())
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrWithdrawDeadlinePassed, section.Semester.WithdrawDeadline.Format(time.RFC3339))
+	}
+	return outcome, nil
+}
+
+// withdrawCourse marks an enrolled registration withdrawn. The student keeps the seat, so the
+// seat counter is left alone and nobody is promoted from the waitlist.
+func (s *RegistrationService) withdrawCourse(ctx context.Context, registration *domain.Registration) error {
+	log := registrationLog(ctx, registration.StudentID, registration.SectionID)
+	registration.Status = domain.StatusWithdrawn
+	registration.UpdatedAt = time.Now()
+
+	if err := s.recordEvent(ctx, domain.EventWithdrawn, registration.StudentID, registration.SectionID, nil, registration.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to record withdrawal: %w", err)
+	}
+	if err := s.registrationRepo.Update(ctx, registration); err != nil {
+		return fmt.Errorf("failed to update registration: %w", err)
+	}
+
+	s.auditService.Record(ctx, AuditChange{
+		Action:    domain.AuditWithdrawn,
+		StudentID: registration.StudentID,
+		SectionID: registration.SectionID,
+		Before:    map[string]any{"status": domain.StatusEnrolled},
+		After:     map[string]any{"status": domain.StatusWithdrawn},
+	})
+	s.updateStudentRegistrationCache(ctx, registration.StudentID, registration.SectionID, domain.StatusWithdrawn)
+
+	log.Info("Student %s withdrew from section %s", registration.StudentID, registration.SectionID)
+	return nil
+}
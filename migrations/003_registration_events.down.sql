@@ -0,0 +1,6 @@
+-- Migration: 003_registration_events (down)
+-- Description: Drop the registration event store and roster snapshots
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS registration_snapshots;
+DROP TABLE IF EXISTS registration_events;
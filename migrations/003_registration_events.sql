@@ -0,0 +1,29 @@
+-- Migration: 003_registration_events
+-- Description: Append-only registration event store and roster snapshots
+-- Created: 2026-10-16
+
+-- Create registration_events table
+CREATE TABLE IF NOT EXISTS registration_events (
+    sequence BIGSERIAL PRIMARY KEY,
+    event_id UUID UNIQUE NOT NULL DEFAULT uuid_generate_v4(),
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('registered', 'waitlisted', 'promoted', 'dropped')),
+    student_id UUID NOT NULL,
+    section_id UUID NOT NULL,
+    position INTEGER,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create registration_snapshots table
+CREATE TABLE IF NOT EXISTS registration_snapshots (
+    snapshot_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    section_id UUID NOT NULL,
+    sequence BIGINT NOT NULL,
+    as_of TIMESTAMP WITH TIME ZONE NOT NULL,
+    state JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_events_section_sequence ON registration_events(section_id, sequence);
+CREATE INDEX IF NOT EXISTS idx_registration_events_section_occurred_at ON registration_events(section_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_registration_snapshots_section_as_of ON registration_snapshots(section_id, as_of DESC);
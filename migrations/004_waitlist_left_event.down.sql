@@ -0,0 +1,10 @@
+-- Migration: 004_waitlist_left_event (down)
+-- Description: Disallow waitlist_left in the registration event store
+-- Created: 2026-10-16
+
+-- The events have no type to fall back to under the old constraint
+DELETE FROM registration_events WHERE event_type = 'waitlist_left';
+
+ALTER TABLE registration_events DROP CONSTRAINT IF EXISTS registration_events_event_type_check;
+ALTER TABLE registration_events ADD CONSTRAINT registration_events_event_type_check
+    CHECK (event_type IN ('registered', 'waitlisted', 'promoted', 'dropped'));
@@ -0,0 +1,7 @@
+-- Migration: 004_waitlist_left_event
+-- Description: Allow waitlist_left in the registration event store
+-- Created: 2026-10-16
+
+ALTER TABLE registration_events DROP CONSTRAINT IF EXISTS registration_events_event_type_check;
+ALTER TABLE registration_events ADD CONSTRAINT registration_events_event_type_check
+    CHECK (event_type IN ('registered', 'waitlisted', 'promoted', 'dropped', 'waitlist_left'));
@@ -0,0 +1,9 @@
+-- Migration: 005_student_contact_info (down)
+-- Description: Drop the preferred name, email and phone of students
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_students_email;
+
+ALTER TABLE students DROP COLUMN IF EXISTS phone;
+ALTER TABLE students DROP COLUMN IF EXISTS email;
+ALTER TABLE students DROP COLUMN IF EXISTS preferred_name;
@@ -0,0 +1,9 @@
+-- Migration: 005_student_contact_info
+-- Description: Preferred name, email and phone on students
+-- Created: 2026-10-16
+
+ALTER TABLE students ADD COLUMN IF NOT EXISTS preferred_name VARCHAR(100);
+ALTER TABLE students ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+ALTER TABLE students ADD COLUMN IF NOT EXISTS phone VARCHAR(20);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_students_email ON students(LOWER(email)) WHERE email IS NOT NULL AND email <> '';
@@ -0,0 +1,5 @@
+-- Migration: 006_semester_calendar (down)
+-- Description: Drop the key dates of semesters
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS calendar_events;
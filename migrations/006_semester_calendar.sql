@@ -0,0 +1,17 @@
+-- Migration: 006_semester_calendar
+-- Description: Key dates of a semester such as add/drop deadlines and holidays
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS calendar_events (
+    event_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    semester_id UUID NOT NULL REFERENCES semesters(semester_id) ON DELETE CASCADE,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('registration_window', 'add_drop_deadline', 'withdrawal_deadline', 'holiday')),
+    name TEXT NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT check_calendar_event_range CHECK (ends_at IS NULL OR ends_at >= starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_events_semester_starts_at ON calendar_events(semester_id, starts_at);
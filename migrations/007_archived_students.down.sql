@@ -0,0 +1,7 @@
+-- Migration: 007_archived_students (down)
+-- Description: Drop the archived flag of students
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_students_student_number_pattern;
+
+ALTER TABLE students DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,8 @@
+-- Migration: 007_archived_students
+-- Description: Archived (alumni) students whose registrations are read-only
+-- Created: 2026-10-16
+
+ALTER TABLE students ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_students_student_number_pattern
+    ON students (student_number text_pattern_ops);
@@ -0,0 +1,5 @@
+-- Migration: 008_api_keys (down)
+-- Description: Drop the API keys of service-to-service clients
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,11 @@
+-- Migration: 009_course_section_tags (down)
+-- Description: Drop the catalog tags and attributes of courses and sections
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_sections_tags;
+DROP INDEX IF EXISTS idx_courses_tags;
+
+ALTER TABLE sections DROP COLUMN IF EXISTS attributes;
+ALTER TABLE sections DROP COLUMN IF EXISTS tags;
+ALTER TABLE courses DROP COLUMN IF EXISTS attributes;
+ALTER TABLE courses DROP COLUMN IF EXISTS tags;
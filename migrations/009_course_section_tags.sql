@@ -0,0 +1,11 @@
+-- Migration: 009_course_section_tags
+-- Description: Catalog tags and attributes on courses and sections
+-- Created: 2026-10-16
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_courses_tags ON courses USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_sections_tags ON sections USING GIN (tags);
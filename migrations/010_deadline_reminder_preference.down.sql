@@ -0,0 +1,5 @@
+-- Migration: 010_deadline_reminder_preference (down)
+-- Description: Drop the deadline reminder preference of students
+-- Created: 2026-10-16
+
+ALTER TABLE students DROP COLUMN IF EXISTS deadline_reminders;
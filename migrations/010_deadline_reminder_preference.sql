@@ -0,0 +1,5 @@
+-- Migration: 010_deadline_reminder_preference
+-- Description: Let students opt out of registration deadline reminders
+-- Created: 2026-10-16
+
+ALTER TABLE students ADD COLUMN IF NOT EXISTS deadline_reminders BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,13 @@
+-- Migration: 011_course_search (down)
+-- Description: Drop the course search columns and indexes
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_sections_course_active;
+DROP INDEX IF EXISTS idx_courses_department;
+DROP INDEX IF EXISTS idx_courses_course_code_pattern;
+
+ALTER TABLE sections DROP COLUMN IF EXISTS end_time;
+ALTER TABLE sections DROP COLUMN IF EXISTS start_time;
+ALTER TABLE sections DROP COLUMN IF EXISTS meeting_days;
+ALTER TABLE courses DROP COLUMN IF EXISTS credit_hours;
+ALTER TABLE courses DROP COLUMN IF EXISTS department;
@@ -0,0 +1,14 @@
+-- Migration: 011_course_search
+-- Description: Department, credit hours and meeting times for the course search
+-- Created: 2026-10-16
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS department VARCHAR(20);
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS credit_hours INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS meeting_days VARCHAR(7);
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS start_time VARCHAR(5);
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS end_time VARCHAR(5);
+
+-- Code prefix searches use LIKE 'CS1%', which needs the pattern operator class
+CREATE INDEX IF NOT EXISTS idx_courses_course_code_pattern ON courses (course_code text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_courses_department ON courses (LOWER(department));
+CREATE INDEX IF NOT EXISTS idx_sections_course_active ON sections (course_id) WHERE is_active;
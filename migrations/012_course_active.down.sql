@@ -0,0 +1,5 @@
+-- Migration: 012_course_active (down)
+-- Description: Drop the active flag of courses
+-- Created: 2026-10-16
+
+ALTER TABLE courses DROP COLUMN IF EXISTS active;
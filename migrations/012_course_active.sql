@@ -0,0 +1,5 @@
+-- Migration: 012_course_active
+-- Description: Active flag on courses so retired courses can be taken out of the catalog
+-- Created: 2026-10-16
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
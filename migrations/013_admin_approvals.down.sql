@@ -0,0 +1,5 @@
+-- Migration: 013_admin_approvals (down)
+-- Description: Drop the two-person approvals of admin operations
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS admin_approvals;
@@ -0,0 +1,25 @@
+-- Migration: 013_admin_approvals
+-- Description: Two-person approval of destructive admin operations such as cancelling a section
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS admin_approvals (
+    approval_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    operation VARCHAR(50) NOT NULL,
+    target_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'executed', 'failed', 'rejected', 'expired')),
+    proposed_by VARCHAR(255) NOT NULL,
+    proposed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    decided_by VARCHAR(255),
+    decided_at TIMESTAMP WITH TIME ZONE,
+    result TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_approvals_proposed ON admin_approvals(proposed_at DESC);
+
+-- At most one open proposal per operation and target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_approvals_pending
+    ON admin_approvals(operation, target_id) WHERE status = 'pending';
@@ -0,0 +1,68 @@
+-- Migration: 014_partition_by_semester (down)
+-- Description: Turn registrations and waitlist back into plain tables without a semester column
+-- Created: 2026-10-16
+
+DROP TRIGGER IF EXISTS trg_semesters_create_partitions ON semesters;
+DROP FUNCTION IF EXISTS semesters_create_partitions();
+DROP FUNCTION IF EXISTS create_semester_partitions(UUID);
+
+CREATE TABLE registrations_plain (
+    registration_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL,
+    section_id UUID NOT NULL,
+    status registration_status NOT NULL DEFAULT 'enrolled',
+    registration_date TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    version INTEGER DEFAULT 1,
+    UNIQUE(student_id, section_id),
+    CONSTRAINT registrations_student_id_fkey FOREIGN KEY (student_id) REFERENCES students(student_id) ON DELETE CASCADE,
+    CONSTRAINT registrations_section_id_fkey FOREIGN KEY (section_id) REFERENCES sections(section_id) ON DELETE CASCADE
+);
+
+CREATE TABLE waitlist_plain (
+    waitlist_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL,
+    section_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT fk_waitlist_student FOREIGN KEY (student_id) REFERENCES students(student_id) ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_section FOREIGN KEY (section_id) REFERENCES sections(section_id) ON DELETE CASCADE,
+    CONSTRAINT unique_waitlist_student_section UNIQUE (student_id, section_id)
+);
+
+INSERT INTO registrations_plain (
+    registration_id, student_id, section_id, status, registration_date, created_at, updated_at, version
+)
+SELECT registration_id, student_id, section_id, status, registration_date, created_at, updated_at, version
+FROM registrations;
+
+INSERT INTO waitlist_plain (
+    waitlist_id, student_id, section_id, position, timestamp, expires_at, created_at, updated_at
+)
+SELECT waitlist_id, student_id, section_id, position, timestamp, expires_at, created_at, updated_at
+FROM waitlist;
+
+-- Dropping the partitioned tables drops every semester partition with them
+DROP TABLE registrations;
+DROP TABLE waitlist;
+ALTER TABLE registrations_plain RENAME TO registrations;
+ALTER TABLE waitlist_plain RENAME TO waitlist;
+ALTER INDEX registrations_plain_pkey RENAME TO registrations_pkey;
+ALTER INDEX registrations_plain_student_id_section_id_key RENAME TO registrations_student_id_section_id_key;
+ALTER INDEX waitlist_plain_pkey RENAME TO waitlist_pkey;
+
+CREATE INDEX idx_registrations_student_id ON registrations(student_id);
+CREATE INDEX idx_registrations_section_id ON registrations(section_id);
+CREATE INDEX idx_registrations_status ON registrations(status);
+CREATE INDEX idx_registrations_student_status ON registrations(student_id, status);
+CREATE INDEX idx_registrations_section_student_status ON registrations(section_id, student_id, status);
+
+CREATE INDEX idx_waitlist_section_id ON waitlist(section_id);
+CREATE INDEX idx_waitlist_student_id ON waitlist(student_id);
+CREATE INDEX idx_waitlist_position ON waitlist(section_id, position);
+CREATE INDEX idx_waitlist_timestamp ON waitlist(timestamp);
+CREATE INDEX idx_waitlist_section_position ON waitlist(section_id, position);
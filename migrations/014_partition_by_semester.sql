@@ -0,0 +1,128 @@
+-- Migration: 014_partition_by_semester
+-- Description: Partition registrations and waitlist by semester, so registration-day writes and historical reads hit different tables
+-- Created: 2026-10-16
+
+-- Every registration and waitlist entry carries the semester of its section as the partition key
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS semester_id UUID;
+UPDATE registrations r SET semester_id = s.semester_id FROM sections s WHERE s.section_id = r.section_id;
+
+ALTER TABLE waitlist ADD COLUMN IF NOT EXISTS semester_id UUID;
+UPDATE waitlist w SET semester_id = s.semester_id FROM sections s WHERE s.section_id = w.section_id;
+
+-- Partitioned tables. Primary and unique keys must include the partition key; since a
+-- section belongs to exactly one semester, (student, section) stays unique.
+CREATE TABLE registrations_partitioned (
+    registration_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL,
+    section_id UUID NOT NULL,
+    status registration_status NOT NULL DEFAULT 'enrolled',
+    registration_date TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    version INTEGER DEFAULT 1,
+    semester_id UUID NOT NULL,
+    CONSTRAINT pk_registrations PRIMARY KEY (registration_id, semester_id),
+    CONSTRAINT uq_registrations_student_section UNIQUE (student_id, section_id, semester_id),
+    CONSTRAINT fk_registrations_student FOREIGN KEY (student_id) REFERENCES students(student_id) ON DELETE CASCADE,
+    CONSTRAINT fk_registrations_section FOREIGN KEY (section_id) REFERENCES sections(section_id) ON DELETE CASCADE,
+    CONSTRAINT fk_registrations_semester FOREIGN KEY (semester_id) REFERENCES semesters(semester_id)
+) PARTITION BY LIST (semester_id);
+
+CREATE TABLE waitlist_partitioned (
+    waitlist_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL,
+    section_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    semester_id UUID NOT NULL,
+    CONSTRAINT pk_waitlist PRIMARY KEY (waitlist_id, semester_id),
+    CONSTRAINT uq_waitlist_student_section UNIQUE (student_id, section_id, semester_id),
+    CONSTRAINT fk_waitlist_student FOREIGN KEY (student_id) REFERENCES students(student_id) ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_section FOREIGN KEY (section_id) REFERENCES sections(section_id) ON DELETE CASCADE,
+    CONSTRAINT fk_waitlist_semester FOREIGN KEY (semester_id) REFERENCES semesters(semester_id)
+) PARTITION BY LIST (semester_id);
+
+-- Rows of a semester without a partition yet land in the default partition until
+-- create_semester_partitions moves them out
+CREATE TABLE registrations_default PARTITION OF registrations_partitioned DEFAULT;
+CREATE TABLE waitlist_default PARTITION OF waitlist_partitioned DEFAULT;
+
+INSERT INTO registrations_partitioned (
+    registration_id, student_id, section_id, status, registration_date, created_at, updated_at, version, semester_id
+)
+SELECT registration_id, student_id, section_id, status, registration_date, created_at, updated_at, version, semester_id
+FROM registrations;
+
+INSERT INTO waitlist_partitioned (
+    waitlist_id, student_id, section_id, position, timestamp, expires_at, created_at, updated_at, semester_id
+)
+SELECT waitlist_id, student_id, section_id, position, timestamp, expires_at, created_at, updated_at, semester_id
+FROM waitlist;
+
+DROP TABLE registrations;
+DROP TABLE waitlist;
+ALTER TABLE registrations_partitioned RENAME TO registrations;
+ALTER TABLE waitlist_partitioned RENAME TO waitlist;
+
+-- Indexes are declared on the parents and created on every partition
+CREATE INDEX idx_registrations_student_id ON registrations(student_id);
+CREATE INDEX idx_registrations_section_id ON registrations(section_id);
+CREATE INDEX idx_registrations_status ON registrations(status);
+CREATE INDEX idx_registrations_student_status ON registrations(student_id, status);
+CREATE INDEX idx_registrations_section_student_status ON registrations(section_id, student_id, status);
+
+CREATE INDEX idx_waitlist_section_id ON waitlist(section_id);
+CREATE INDEX idx_waitlist_student_id ON waitlist(student_id);
+CREATE INDEX idx_waitlist_position ON waitlist(position);
+CREATE INDEX idx_waitlist_timestamp ON waitlist(timestamp);
+CREATE INDEX idx_waitlist_section_position ON waitlist(section_id, position);
+
+-- create_semester_partitions gives a semester its own registrations and waitlist partitions,
+-- named after the semester code. Rows of the semester already in the default partition are
+-- moved into the new one before it is attached. Calling it again for the same semester does
+-- nothing.
+CREATE OR REPLACE FUNCTION create_semester_partitions(p_semester_id UUID) RETURNS VOID AS $$
+DECLARE
+    suffix TEXT;
+    parent TEXT;
+    child TEXT;
+BEGIN
+    SELECT lower(regexp_replace(semester_code, '[^A-Za-z0-9]', '_', 'g')) INTO suffix
+    FROM semesters WHERE semester_id = p_semester_id;
+    IF suffix IS NULL THEN
+        RAISE EXCEPTION 'semester % not found', p_semester_id;
+    END IF;
+
+    FOREACH parent IN ARRAY ARRAY['registrations', 'waitlist'] LOOP
+        child := parent || '_' || suffix;
+        IF to_regclass(child) IS NOT NULL THEN
+            CONTINUE;
+        END IF;
+
+        EXECUTE format('CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', child, parent);
+        EXECUTE format(
+            'WITH moved AS (DELETE FROM %I WHERE semester_id = %L RETURNING *) INSERT INTO %I SELECT * FROM moved',
+            parent || '_default', p_semester_id, child
+        );
+        EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES IN (%L)', parent, child, p_semester_id);
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- New semesters get their partitions as soon as they are created
+CREATE OR REPLACE FUNCTION semesters_create_partitions() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM create_semester_partitions(NEW.semester_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_semesters_create_partitions ON semesters;
+CREATE TRIGGER trg_semesters_create_partitions
+    AFTER INSERT ON semesters
+    FOR EACH ROW EXECUTE FUNCTION semesters_create_partitions();
+
+SELECT create_semester_partitions(semester_id) FROM semesters;
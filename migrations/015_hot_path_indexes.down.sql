@@ -0,0 +1,17 @@
+-- Migration: 015_hot_path_indexes (down)
+-- Description: Put back the indexes the covering indexes superseded
+-- Created: 2026-10-16
+
+CREATE INDEX IF NOT EXISTS idx_registrations_student_id ON registrations(student_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_section_position ON waitlist(section_id, position);
+CREATE INDEX IF NOT EXISTS idx_sections_semester_id ON sections(semester_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_position ON waitlist(position);
+
+DROP INDEX IF EXISTS idx_sections_semester_available;
+DROP INDEX IF EXISTS idx_waitlist_section_position_covering;
+DROP INDEX IF EXISTS idx_registrations_section_status;
+DROP INDEX IF EXISTS idx_registrations_student_covering;
+
+ANALYZE registrations;
+ANALYZE waitlist;
+ANALYZE sections;
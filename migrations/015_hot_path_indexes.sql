@@ -0,0 +1,27 @@
+-- Migration: 015_hot_path_indexes
+-- Description: Covering indexes for the student schedule, section roster, waitlist and available sections lookups
+-- Created: 2026-10-16
+
+-- A student's registrations with their sections and status
+CREATE INDEX IF NOT EXISTS idx_registrations_student_covering ON registrations (student_id) INCLUDE (section_id, status);
+
+-- Section rosters and enrollment counts by status
+CREATE INDEX IF NOT EXISTS idx_registrations_section_status ON registrations (section_id, status) INCLUDE (student_id);
+
+-- Next in line and the position of a student on a section's waitlist
+CREATE INDEX IF NOT EXISTS idx_waitlist_section_position_covering ON waitlist (section_id, position) INCLUDE (student_id);
+
+-- Sections of a semester, and those of them with seats left
+CREATE INDEX IF NOT EXISTS idx_sections_semester_available ON sections (semester_id, available_seats) INCLUDE (is_active, course_id);
+
+-- Superseded by the indexes above: each is a prefix of one of them
+DROP INDEX IF EXISTS idx_registrations_student_id;
+DROP INDEX IF EXISTS idx_waitlist_section_position;
+DROP INDEX IF EXISTS idx_sections_semester_id;
+
+-- Only indexed position, which no query filters on by itself
+DROP INDEX IF EXISTS idx_waitlist_position;
+
+ANALYZE registrations;
+ANALYZE waitlist;
+ANALYZE sections;
@@ -0,0 +1,5 @@
+-- Migration: 016_student_holds (down)
+-- Description: Drop the holds that keep students from registering
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS student_holds;
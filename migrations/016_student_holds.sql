@@ -0,0 +1,23 @@
+-- Migration: 016_student_holds
+-- Description: Holds that keep a student from registering, starting with financial holds placed by the bursar
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS student_holds (
+    hold_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(student_id) ON DELETE CASCADE,
+    hold_type VARCHAR(30) NOT NULL,
+    source VARCHAR(30) NOT NULL,
+    external_ref VARCHAR(255),
+    reason TEXT,
+    placed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Checked on every registration
+CREATE INDEX IF NOT EXISTS idx_student_holds_active ON student_holds(student_id) WHERE released_at IS NULL;
+
+-- A callback delivered twice finds the hold it already placed
+CREATE UNIQUE INDEX IF NOT EXISTS idx_student_holds_external_ref
+    ON student_holds(source, external_ref) WHERE external_ref <> '';
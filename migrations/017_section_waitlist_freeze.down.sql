@@ -0,0 +1,7 @@
+-- Migration: 017_section_waitlist_freeze (down)
+-- Description: Drop the per-section waitlist freeze and promotion pause flags
+-- Created: 2026-10-16
+
+ALTER TABLE sections
+    DROP COLUMN IF EXISTS waitlist_promotions_paused,
+    DROP COLUMN IF EXISTS waitlist_frozen;
@@ -0,0 +1,7 @@
+-- Migration: 017_section_waitlist_freeze
+-- Description: Per-section flags that stop new waitlist adds and optionally pause promotions
+-- Created: 2026-10-16
+
+ALTER TABLE sections
+    ADD COLUMN IF NOT EXISTS waitlist_frozen BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS waitlist_promotions_paused BOOLEAN NOT NULL DEFAULT false;
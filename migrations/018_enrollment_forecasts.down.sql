@@ -0,0 +1,5 @@
+-- Migration: 018_enrollment_forecasts (down)
+-- Description: Drop the nightly enrollment forecasts
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS enrollment_forecasts;
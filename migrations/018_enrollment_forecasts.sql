@@ -0,0 +1,20 @@
+-- Migration: 018_enrollment_forecasts
+-- Description: Nightly predictions of each section's final enrollment, from how earlier semesters filled up
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS enrollment_forecasts (
+    section_id UUID PRIMARY KEY REFERENCES sections(section_id) ON DELETE CASCADE,
+    semester_id UUID NOT NULL REFERENCES semesters(semester_id) ON DELETE CASCADE,
+    course_code TEXT NOT NULL,
+    section_number VARCHAR(10) NOT NULL,
+    department VARCHAR(20),
+    capacity INTEGER NOT NULL,
+    enrolled INTEGER NOT NULL,
+    predicted_enrollment INTEGER NOT NULL,
+    outlook VARCHAR(20) NOT NULL,
+    history_semesters INTEGER NOT NULL,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- The report lists a semester's forecasts, optionally for one department
+CREATE INDEX IF NOT EXISTS idx_enrollment_forecasts_semester ON enrollment_forecasts(semester_id, department);
@@ -0,0 +1,5 @@
+-- Migration: 019_idempotency_keys (down)
+-- Description: Drop the durable copy of idempotency keys, leaving them in Redis only
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,20 @@
+-- Migration: 019_idempotency_keys
+-- Description: Durable copy of idempotency keys for the hybrid store, so a Redis flush does not forget processed requests
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,
+    student_id UUID NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'completed',
+    response_data TEXT,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    processed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Keys taken by HTTP callers that are not students are stored under the nil UUID, so there
+-- is no foreign key to students
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_student_id ON idempotency_keys(student_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
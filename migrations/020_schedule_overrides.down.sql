@@ -0,0 +1,5 @@
+-- Migration: 020_schedule_overrides (down)
+-- Description: Drop the advisor-approved schedule conflict overrides
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS schedule_overrides;
@@ -0,0 +1,28 @@
+-- Migration: 020_schedule_overrides
+-- Description: Advisor-approved overrides that let a student register for a section that clashes with their schedule
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS schedule_overrides (
+    override_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL REFERENCES students(student_id) ON DELETE CASCADE,
+    section_id UUID NOT NULL REFERENCES sections(section_id) ON DELETE CASCADE,
+    conflicting_sections JSONB NOT NULL DEFAULT '[]',
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    decided_by VARCHAR(255),
+    decision_note TEXT,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    registration_status VARCHAR(30),
+    registration_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- The advising queue, oldest first
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_status ON schedule_overrides(status, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_student ON schedule_overrides(student_id, created_at DESC);
+
+-- A student has at most one open override per section; a denied one may be asked for again
+CREATE UNIQUE INDEX IF NOT EXISTS idx_schedule_overrides_open
+    ON schedule_overrides(student_id, section_id) WHERE status IN ('pending', 'approved');
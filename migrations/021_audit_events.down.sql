@@ -0,0 +1,5 @@
+-- Migration: 021_audit_events (down)
+-- Description: Drop the audit log of registration and section changes
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS audit_events;
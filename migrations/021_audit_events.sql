@@ -0,0 +1,23 @@
+-- Migration: 021_audit_events
+-- Description: Audit log of registration and section changes, with who made them and before/after snapshots
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    event_id UUID PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    actor_role VARCHAR(20),
+    student_id UUID,
+    section_id UUID,
+    before JSONB,
+    after JSONB,
+    correlation_id VARCHAR(64),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Events outlive the students and sections they mention, so there are no foreign keys
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events(occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_student ON audit_events(student_id, occurred_at DESC) WHERE student_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_events_section ON audit_events(section_id, occurred_at DESC) WHERE section_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_events_correlation ON audit_events(correlation_id) WHERE correlation_id <> '';
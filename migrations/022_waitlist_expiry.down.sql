@@ -0,0 +1,7 @@
+-- Migration: 022_waitlist_expiry (down)
+-- Description: Drop the per-section waitlist entry lifetime and the expiry sweep index
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_waitlist_expires_at;
+
+ALTER TABLE sections DROP COLUMN IF EXISTS waitlist_ttl_hours;
@@ -0,0 +1,10 @@
+-- Migration: 022_waitlist_expiry
+-- Description: Per-section waitlist entry lifetime and an index for the waitlist expiry sweep
+-- Created: 2026-10-16
+
+-- NULL uses the configured default; 0 means entries of the section never expire
+ALTER TABLE sections
+    ADD COLUMN IF NOT EXISTS waitlist_ttl_hours INTEGER CHECK (waitlist_ttl_hours >= 0);
+
+-- Entries past their expiry, oldest first
+CREATE INDEX IF NOT EXISTS idx_waitlist_expires_at ON waitlist (expires_at) WHERE expires_at IS NOT NULL;
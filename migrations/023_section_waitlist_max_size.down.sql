@@ -0,0 +1,5 @@
+-- Migration: 023_section_waitlist_max_size (down)
+-- Description: Drop the per-section waitlist cap
+-- Created: 2026-10-16
+
+ALTER TABLE sections DROP COLUMN IF EXISTS waitlist_max_size;
@@ -0,0 +1,7 @@
+-- Migration: 023_section_waitlist_max_size
+-- Description: Per-section cap on the number of waitlisted students
+-- Created: 2026-10-16
+
+-- NULL uses the configured default; 0 means the section's waitlist has no cap
+ALTER TABLE sections
+    ADD COLUMN IF NOT EXISTS waitlist_max_size INTEGER CHECK (waitlist_max_size >= 0);
@@ -0,0 +1,8 @@
+-- Migration: 024_section_policy (down)
+-- Description: Drop the per-section registration policy
+-- Created: 2026-10-16
+
+ALTER TABLE sections
+    DROP COLUMN IF EXISTS instructor_consent_required,
+    DROP COLUMN IF EXISTS reserved_seats,
+    DROP COLUMN IF EXISTS allow_waitlist;
@@ -0,0 +1,9 @@
+-- Migration: 024_section_policy
+-- Description: Per-section registration policy: waitlist on or off, reserved seats and instructor consent
+-- Created: 2026-10-16
+
+-- The policy's waitlist cap is the waitlist_max_size column added by 023
+ALTER TABLE sections
+    ADD COLUMN IF NOT EXISTS allow_waitlist BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS reserved_seats INTEGER NOT NULL DEFAULT 0 CHECK (reserved_seats >= 0),
+    ADD COLUMN IF NOT EXISTS instructor_consent_required BOOLEAN NOT NULL DEFAULT false;
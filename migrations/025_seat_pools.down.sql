@@ -0,0 +1,12 @@
+-- Migration: 025_seat_pools (down)
+-- Description: Drop seat pools and the student details they are matched on
+-- Created: 2026-10-16
+
+ALTER TABLE registrations DROP COLUMN IF EXISTS seat_pool_id;
+
+DROP TABLE IF EXISTS section_seat_pools;
+
+ALTER TABLE students
+    DROP COLUMN IF EXISTS year,
+    DROP COLUMN IF EXISTS cohort,
+    DROP COLUMN IF EXISTS major;
@@ -0,0 +1,25 @@
+-- Migration: 025_seat_pools
+-- Description: Seat pools that set aside some of a section's seats for students by major, cohort or year
+-- Created: 2026-10-16
+
+ALTER TABLE students
+    ADD COLUMN IF NOT EXISTS major VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS cohort VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS year INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS section_seat_pools (
+    pool_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    section_id UUID NOT NULL REFERENCES sections(section_id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    seats INTEGER NOT NULL CHECK (seats > 0),
+    majors JSONB NOT NULL DEFAULT '[]',
+    cohorts JSONB NOT NULL DEFAULT '[]',
+    years JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Pools are replaced as a set and matched by name, so a pool keeps its ID across edits
+CREATE UNIQUE INDEX IF NOT EXISTS idx_section_seat_pools_name ON section_seat_pools(section_id, name);
+
+-- The pool a registration's seat came from, given back to it on drop
+ALTER TABLE registrations ADD COLUMN IF NOT EXISTS seat_pool_id UUID;
@@ -0,0 +1,5 @@
+-- Migration: 026_permission_numbers (down)
+-- Description: Drop the single-use permission numbers
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS permission_numbers;
@@ -0,0 +1,18 @@
+-- Migration: 026_permission_numbers
+-- Description: Single-use permission numbers that let a student register past instructor consent and reserved seats
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS permission_numbers (
+    permission_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    section_id UUID NOT NULL REFERENCES sections(section_id) ON DELETE CASCADE,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    student_id UUID REFERENCES students(student_id) ON DELETE CASCADE,
+    issued_by VARCHAR(255),
+    note TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    used_by UUID REFERENCES students(student_id) ON DELETE SET NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_permission_numbers_section ON permission_numbers(section_id, created_at);
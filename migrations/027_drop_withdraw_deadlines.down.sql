@@ -0,0 +1,31 @@
+-- Migration: 027_drop_withdraw_deadlines (down)
+-- Description: Drop the semester drop and withdraw deadlines and the withdrawn registration status, refusing while any registration is withdrawn
+-- Created: 2026-10-16
+
+-- A withdrawn registration keeps its seat, so it has no status to fall back to
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM registrations WHERE status = 'withdrawn') THEN
+        RAISE EXCEPTION 'registrations are withdrawn; they must be dropped or enrolled again first';
+    END IF;
+END;
+$$;
+
+-- Enum values cannot be dropped, so the type is made again without it
+ALTER TYPE registration_status RENAME TO registration_status_old;
+CREATE TYPE registration_status AS ENUM ('enrolled', 'waitlisted', 'dropped', 'failed');
+ALTER TABLE registrations ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE registrations ALTER COLUMN status TYPE registration_status USING status::text::registration_status;
+ALTER TABLE registrations ALTER COLUMN status SET DEFAULT 'enrolled';
+DROP TYPE registration_status_old;
+
+-- The events of withdrawals have no type to fall back to under the old constraint
+DELETE FROM registration_events WHERE event_type = 'withdrawn';
+ALTER TABLE registration_events DROP CONSTRAINT IF EXISTS registration_events_event_type_check;
+ALTER TABLE registration_events ADD CONSTRAINT registration_events_event_type_check
+    CHECK (event_type IN ('registered', 'waitlisted', 'promoted', 'dropped', 'waitlist_left'));
+
+ALTER TABLE semesters DROP CONSTRAINT IF EXISTS check_semester_withdraw_deadline;
+ALTER TABLE semesters
+    DROP COLUMN IF EXISTS withdraw_deadline,
+    DROP COLUMN IF EXISTS drop_deadline;
@@ -0,0 +1,18 @@
+-- Migration: 027_drop_withdraw_deadlines
+-- Description: Semester drop and withdraw deadlines, and the withdrawn registration status
+-- Created: 2026-10-16
+
+ALTER TABLE semesters
+    ADD COLUMN IF NOT EXISTS drop_deadline TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS withdraw_deadline TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE semesters DROP CONSTRAINT IF EXISTS check_semester_withdraw_deadline;
+ALTER TABLE semesters ADD CONSTRAINT check_semester_withdraw_deadline
+    CHECK (drop_deadline IS NULL OR withdraw_deadline IS NULL OR withdraw_deadline >= drop_deadline);
+
+-- A withdrawn registration keeps its seat, unlike a dropped one
+ALTER TYPE registration_status ADD VALUE IF NOT EXISTS 'withdrawn';
+
+ALTER TABLE registration_events DROP CONSTRAINT IF EXISTS registration_events_event_type_check;
+ALTER TABLE registration_events ADD CONSTRAINT registration_events_event_type_check
+    CHECK (event_type IN ('registered', 'waitlisted', 'promoted', 'dropped', 'withdrawn', 'waitlist_left'));
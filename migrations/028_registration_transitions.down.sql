@@ -0,0 +1,5 @@
+-- Migration: 028_registration_transitions (down)
+-- Description: Drop the status transitions of registrations
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS registration_transitions;
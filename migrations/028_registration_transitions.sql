@@ -0,0 +1,17 @@
+-- Migration: 028_registration_transitions
+-- Description: Status transitions of registrations for the student registration history
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS registration_transitions (
+    transition_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    student_id UUID NOT NULL,
+    section_id UUID NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20),
+    position INTEGER,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_transitions_student ON registration_transitions(student_id, occurred_at);
@@ -0,0 +1,6 @@
+-- Migration: 029_section_room_instructor (down)
+-- Description: Drop the room and instructor of sections
+-- Created: 2026-10-16
+
+ALTER TABLE sections DROP COLUMN IF EXISTS instructor;
+ALTER TABLE sections DROP COLUMN IF EXISTS room;
@@ -0,0 +1,6 @@
+-- Migration: 029_section_room_instructor
+-- Description: Room and instructor of a section for the student schedule
+-- Created: 2026-10-16
+
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS room VARCHAR(50);
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS instructor VARCHAR(100);
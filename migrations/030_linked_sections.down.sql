@@ -0,0 +1,8 @@
+-- Migration: 030_linked_sections (down)
+-- Description: Drop linked sections
+-- Created: 2026-10-16
+
+DROP INDEX IF EXISTS idx_sections_linked_section;
+
+ALTER TABLE waitlist DROP COLUMN IF EXISTS linked_section_id;
+ALTER TABLE sections DROP COLUMN IF EXISTS linked_section_id;
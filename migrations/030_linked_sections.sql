@@ -0,0 +1,8 @@
+-- Migration: 030_linked_sections
+-- Description: Linked sections, such as a lab taken together with its lecture
+-- Created: 2026-10-16
+
+ALTER TABLE sections ADD COLUMN IF NOT EXISTS linked_section_id UUID REFERENCES sections(section_id) ON DELETE SET NULL;
+ALTER TABLE waitlist ADD COLUMN IF NOT EXISTS linked_section_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_sections_linked_section ON sections (linked_section_id) WHERE linked_section_id IS NOT NULL;
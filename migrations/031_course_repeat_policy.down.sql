@@ -0,0 +1,5 @@
+-- Migration: 031_course_repeat_policy (down)
+-- Description: Drop the course repeat policy, letting every course be taken again
+-- Created: 2026-10-16
+
+ALTER TABLE courses DROP COLUMN IF EXISTS allow_repeat;
@@ -0,0 +1,5 @@
+-- Migration: 031_course_repeat_policy
+-- Description: Courses that students may take again after completing or while enrolled
+-- Created: 2026-10-16
+
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS allow_repeat BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,17 @@
+-- Migration: 032_semester_archive (down)
+-- Description: Drop the archive tables, refusing while any semester is archived
+-- Created: 2026-10-16
+
+-- Dropping the archive tables would drop the partitions of archived semesters with them
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM semesters WHERE archived_at IS NOT NULL) THEN
+        RAISE EXCEPTION 'semesters are archived; their partitions must be moved back to registrations and waitlist first';
+    END IF;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS archive_semester(UUID);
+DROP TABLE IF EXISTS registrations_archive;
+DROP TABLE IF EXISTS waitlist_archive;
+ALTER TABLE semesters DROP COLUMN IF EXISTS archived_at;
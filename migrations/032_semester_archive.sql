@@ -0,0 +1,51 @@
+-- Migration: 032_semester_archive
+-- Description: Archive tables that take the registrations and waitlist partitions of semesters that have ended
+-- Created: 2026-10-16
+
+ALTER TABLE semesters ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+-- The archive tables have the columns of the live tables in the same order, so the two can
+-- be read together with UNION ALL. A column added to registrations or waitlist must be
+-- added to its archive table as well.
+CREATE TABLE IF NOT EXISTS registrations_archive (LIKE registrations INCLUDING DEFAULTS) PARTITION BY LIST (semester_id);
+CREATE TABLE IF NOT EXISTS waitlist_archive (LIKE waitlist INCLUDING DEFAULTS) PARTITION BY LIST (semester_id);
+
+-- Archived rows are only read for reports and course history
+CREATE INDEX IF NOT EXISTS idx_registrations_archive_student_id ON registrations_archive (student_id);
+CREATE INDEX IF NOT EXISTS idx_registrations_archive_section_id ON registrations_archive (section_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_archive_section_id ON waitlist_archive (section_id);
+
+-- archive_semester moves a semester's registrations and waitlist partitions from the live
+-- tables to the archive tables, without copying a row, and marks the semester archived and
+-- inactive. Rows of the semester still in the default partitions are moved into its own
+-- first. It returns false, doing nothing, for a semester that is archived already.
+CREATE OR REPLACE FUNCTION archive_semester(p_semester_id UUID) RETURNS BOOLEAN AS $$
+DECLARE
+    suffix TEXT;
+    archived TIMESTAMP WITH TIME ZONE;
+    parent TEXT;
+    child TEXT;
+BEGIN
+    SELECT lower(regexp_replace(semester_code, '[^A-Za-z0-9]', '_', 'g')), archived_at INTO suffix, archived
+    FROM semesters WHERE semester_id = p_semester_id
+    FOR UPDATE;
+    IF suffix IS NULL THEN
+        RAISE EXCEPTION 'semester % not found', p_semester_id;
+    END IF;
+    IF archived IS NOT NULL THEN
+        RETURN false;
+    END IF;
+
+    PERFORM create_semester_partitions(p_semester_id);
+
+    FOREACH parent IN ARRAY ARRAY['registrations', 'waitlist'] LOOP
+        child := parent || '_' || suffix;
+        EXECUTE format('ALTER TABLE %I DETACH PARTITION %I', parent, child);
+        EXECUTE format('ALTER TABLE %I ATTACH PARTITION %I FOR VALUES IN (%L)', parent || '_archive', child, p_semester_id);
+    END LOOP;
+
+    UPDATE semesters SET archived_at = NOW(), is_active = false, updated_at = NOW()
+    WHERE semester_id = p_semester_id;
+    RETURN true;
+END;
+$$ LANGUAGE plpgsql;
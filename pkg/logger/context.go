@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type requestIDKey struct{}
+
+// Logger writes log lines carrying a set of structured fields. Loggers are immutable:
+// With returns a new Logger, so one can be shared and extended freely.
+type Logger struct {
+	entry *logrus.Entry
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID, which loggers taken from
+// the context add to every line
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext returns a logger carrying the fields found on ctx, which for now is the
+// request ID. Further fields are added with With:
+//
+//	logger.FromContext(ctx).With("student_id", studentID).Info("Registered")
+func FromContext(ctx context.Context) *Logger {
+	l := &Logger{entry: logrus.NewEntry(GetLogger())}
+	if requestID := RequestID(ctx); requestID != "" {
+		l = l.With("request_id", requestID)
+	}
+	return l
+}
+
+// With returns a logger that adds key to every line
+func (l *Logger) With(key string, value any) *Logger {
+	return &Logger{entry: l.entry.WithField(key, value)}
+}
+
+func (l *Logger) Debug(format string, args ...any) {
+	l.entry.Debugf(format, args...)
+}
+
+func (l *Logger) Info(format string, args ...any) {
+	l.entry.Infof(format, args...)
+}
+
+func (l *Logger) Warn(format string, args ...any) {
+	l.entry.Warnf(format, args...)
+}
+
+func (l *Logger) Error(format string, args ...any) {
+	l.entry.Errorf(format, args...)
+}
+
+func DebugContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Debug(format, args...)
+}
+
+func InfoContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Info(format, args...)
+}
+
+func WarnContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Warn(format, args...)
+}
+
+func ErrorContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).Error(format, args...)
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -9,8 +10,66 @@ import (
 
 var validate *validator.Validate
 
+// phonePattern accepts E.164 numbers, which is what SMS providers expect
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// timeOfDayPattern accepts 24-hour HH:MM times
+var timeOfDayPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// weekdayLetters are the day letters of section meeting days, Monday to Sunday
+const weekdayLetters = "MTWRFSU"
+
 func init() {
 	validate = validator.New()
+	_ = validate.RegisterValidation("phone", validatePhone)
+	_ = validate.RegisterValidation("contact_email", validateContactEmail)
+	_ = validate.RegisterValidation("person_name", validatePersonName)
+	_ = validate.RegisterValidation("time_of_day", validateTimeOfDay)
+	_ = validate.RegisterValidation("meeting_days", validateMeetingDays)
+}
+
+// validatePhone checks for an E.164 number. An empty value is allowed so clients can clear it.
+func validatePhone(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value == "" || phonePattern.MatchString(value)
+}
+
+// validateContactEmail checks for a valid address. An empty value is allowed so clients can clear it.
+func validateContactEmail(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value == "" || validate.Var(value, "email") == nil
+}
+
+// validatePersonName rejects names that are blank or contain control characters
+func validatePersonName(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if strings.TrimSpace(value) == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func validateTimeOfDay(fl validator.FieldLevel) bool {
+	return timeOfDayPattern.MatchString(fl.Field().String())
+}
+
+// validateMeetingDays checks for distinct day letters such as "MWF", in any case
+func validateMeetingDays(fl validator.FieldLevel) bool {
+	value := strings.ToUpper(fl.Field().String())
+	if value == "" {
+		return false
+	}
+	for i, r := range value {
+		if !strings.ContainsRune(weekdayLetters, r) || strings.ContainsRune(value[:i], r) {
+			return false
+		}
+	}
+	return true
 }
 func GetValidator() *validator.Validate {
 	return validate
@@ -45,8 +104,16 @@ func getErrorMessage(fieldError validator.FieldError) string {
 	switch fieldError.Tag() {
 	case "required":
 		return fmt.Sprintf("%s is required", field)
-	case "email":
+	case "email", "contact_email":
 		return fmt.Sprintf("%s must be a valid email address", field)
+	case "phone":
+		return fmt.Sprintf("%s must be an international phone number such as +14155550123", field)
+	case "person_name":
+		return fmt.Sprintf("%s must not be blank or contain control characters", field)
+	case "time_of_day":
+		return fmt.Sprintf("%s must be a 24-hour time such as 09:30", field)
+	case "meeting_days":
+		return fmt.Sprintf("%s must be distinct day letters from %s, e.g. MWF", field, weekdayLetters)
 	case "min":
 		return fmt.Sprintf("%s must be at least %s characters long", field, fieldError.Param())
 	case "max":
@@ -0,0 +1,884 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: registration/v1/registration.proto
+
+package registrationv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RegisterRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StudentId      string                 `protobuf:"bytes,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	SectionIds     []string               `protobuf:"bytes,2,rep,name=section_ids,json=sectionIds,proto3" json:"section_ids,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_registration_v1_registration_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterRequest) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetSectionIds() []string {
+	if x != nil {
+		return x.SectionIds
+	}
+	return nil
+}
+
+func (x *RegisterRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RegistrationResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SectionId string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	// One of "enrolled", "waitlisted", "already_registered" or "failed"
+	Status           string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message          string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	WaitlistPosition *int32 `protobuf:"varint,4,opt,name=waitlist_position,json=waitlistPosition,proto3,oneof" json:"waitlist_position,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RegistrationResult) Reset() {
+	*x = RegistrationResult{}
+	mi := &file_registration_v1_registration_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegistrationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistrationResult) ProtoMessage() {}
+
+func (x *RegistrationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistrationResult.ProtoReflect.Descriptor instead.
+func (*RegistrationResult) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegistrationResult) GetSectionId() string {
+	if x != nil {
+		return x.SectionId
+	}
+	return ""
+}
+
+func (x *RegistrationResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RegistrationResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegistrationResult) GetWaitlistPosition() int32 {
+	if x != nil && x.WaitlistPosition != nil {
+		return *x.WaitlistPosition
+	}
+	return 0
+}
+
+type RegisterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*RegistrationResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_registration_v1_registration_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterResponse) GetResults() []*RegistrationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type DropCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudentId     string                 `protobuf:"bytes,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	SectionId     string                 `protobuf:"bytes,2,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropCourseRequest) Reset() {
+	*x = DropCourseRequest{}
+	mi := &file_registration_v1_registration_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropCourseRequest) ProtoMessage() {}
+
+func (x *DropCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropCourseRequest.ProtoReflect.Descriptor instead.
+func (*DropCourseRequest) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DropCourseRequest) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+func (x *DropCourseRequest) GetSectionId() string {
+	if x != nil {
+		return x.SectionId
+	}
+	return ""
+}
+
+type DropCourseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropCourseResponse) Reset() {
+	*x = DropCourseResponse{}
+	mi := &file_registration_v1_registration_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropCourseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropCourseResponse) ProtoMessage() {}
+
+func (x *DropCourseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropCourseResponse.ProtoReflect.Descriptor instead.
+func (*DropCourseResponse) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{4}
+}
+
+type GetAvailableSectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SemesterId    string                 `protobuf:"bytes,1,opt,name=semester_id,json=semesterId,proto3" json:"semester_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAvailableSectionsRequest) Reset() {
+	*x = GetAvailableSectionsRequest{}
+	mi := &file_registration_v1_registration_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAvailableSectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAvailableSectionsRequest) ProtoMessage() {}
+
+func (x *GetAvailableSectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAvailableSectionsRequest.ProtoReflect.Descriptor instead.
+func (*GetAvailableSectionsRequest) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetAvailableSectionsRequest) GetSemesterId() string {
+	if x != nil {
+		return x.SemesterId
+	}
+	return ""
+}
+
+type Section struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SectionId      string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	CourseId       string                 `protobuf:"bytes,2,opt,name=course_id,json=courseId,proto3" json:"course_id,omitempty"`
+	SemesterId     string                 `protobuf:"bytes,3,opt,name=semester_id,json=semesterId,proto3" json:"semester_id,omitempty"`
+	SectionNumber  string                 `protobuf:"bytes,4,opt,name=section_number,json=sectionNumber,proto3" json:"section_number,omitempty"`
+	TotalSeats     int32                  `protobuf:"varint,5,opt,name=total_seats,json=totalSeats,proto3" json:"total_seats,omitempty"`
+	AvailableSeats int32                  `protobuf:"varint,6,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	CourseCode     string                 `protobuf:"bytes,7,opt,name=course_code,json=courseCode,proto3" json:"course_code,omitempty"`
+	CourseName     string                 `protobuf:"bytes,8,opt,name=course_name,json=courseName,proto3" json:"course_name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Section) Reset() {
+	*x = Section{}
+	mi := &file_registration_v1_registration_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Section) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Section) ProtoMessage() {}
+
+func (x *Section) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Section.ProtoReflect.Descriptor instead.
+func (*Section) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Section) GetSectionId() string {
+	if x != nil {
+		return x.SectionId
+	}
+	return ""
+}
+
+func (x *Section) GetCourseId() string {
+	if x != nil {
+		return x.CourseId
+	}
+	return ""
+}
+
+func (x *Section) GetSemesterId() string {
+	if x != nil {
+		return x.SemesterId
+	}
+	return ""
+}
+
+func (x *Section) GetSectionNumber() string {
+	if x != nil {
+		return x.SectionNumber
+	}
+	return ""
+}
+
+func (x *Section) GetTotalSeats() int32 {
+	if x != nil {
+		return x.TotalSeats
+	}
+	return 0
+}
+
+func (x *Section) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *Section) GetCourseCode() string {
+	if x != nil {
+		return x.CourseCode
+	}
+	return ""
+}
+
+func (x *Section) GetCourseName() string {
+	if x != nil {
+		return x.CourseName
+	}
+	return ""
+}
+
+type GetAvailableSectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sections      []*Section             `protobuf:"bytes,1,rep,name=sections,proto3" json:"sections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAvailableSectionsResponse) Reset() {
+	*x = GetAvailableSectionsResponse{}
+	mi := &file_registration_v1_registration_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAvailableSectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAvailableSectionsResponse) ProtoMessage() {}
+
+func (x *GetAvailableSectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAvailableSectionsResponse.ProtoReflect.Descriptor instead.
+func (*GetAvailableSectionsResponse) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetAvailableSectionsResponse) GetSections() []*Section {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+type GetWaitlistStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudentId     string                 `protobuf:"bytes,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWaitlistStatusRequest) Reset() {
+	*x = GetWaitlistStatusRequest{}
+	mi := &file_registration_v1_registration_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWaitlistStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWaitlistStatusRequest) ProtoMessage() {}
+
+func (x *GetWaitlistStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWaitlistStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetWaitlistStatusRequest) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetWaitlistStatusRequest) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+type WaitlistEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WaitlistId    string                 `protobuf:"bytes,1,opt,name=waitlist_id,json=waitlistId,proto3" json:"waitlist_id,omitempty"`
+	SectionId     string                 `protobuf:"bytes,2,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	Position      int32                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	JoinedAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitlistEntry) Reset() {
+	*x = WaitlistEntry{}
+	mi := &file_registration_v1_registration_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitlistEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitlistEntry) ProtoMessage() {}
+
+func (x *WaitlistEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitlistEntry.ProtoReflect.Descriptor instead.
+func (*WaitlistEntry) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WaitlistEntry) GetWaitlistId() string {
+	if x != nil {
+		return x.WaitlistId
+	}
+	return ""
+}
+
+func (x *WaitlistEntry) GetSectionId() string {
+	if x != nil {
+		return x.SectionId
+	}
+	return ""
+}
+
+func (x *WaitlistEntry) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *WaitlistEntry) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
+type GetWaitlistStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*WaitlistEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWaitlistStatusResponse) Reset() {
+	*x = GetWaitlistStatusResponse{}
+	mi := &file_registration_v1_registration_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWaitlistStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWaitlistStatusResponse) ProtoMessage() {}
+
+func (x *GetWaitlistStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWaitlistStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetWaitlistStatusResponse) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetWaitlistStatusResponse) GetEntries() []*WaitlistEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetWaitlistPositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StudentId     string                 `protobuf:"bytes,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	SectionId     string                 `protobuf:"bytes,2,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWaitlistPositionRequest) Reset() {
+	*x = GetWaitlistPositionRequest{}
+	mi := &file_registration_v1_registration_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWaitlistPositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWaitlistPositionRequest) ProtoMessage() {}
+
+func (x *GetWaitlistPositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWaitlistPositionRequest.ProtoReflect.Descriptor instead.
+func (*GetWaitlistPositionRequest) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetWaitlistPositionRequest) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+func (x *GetWaitlistPositionRequest) GetSectionId() string {
+	if x != nil {
+		return x.SectionId
+	}
+	return ""
+}
+
+type GetWaitlistPositionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when the student is not on the section's waitlist
+	Waitlisted    bool  `protobuf:"varint,1,opt,name=waitlisted,proto3" json:"waitlisted,omitempty"`
+	Position      int32 `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWaitlistPositionResponse) Reset() {
+	*x = GetWaitlistPositionResponse{}
+	mi := &file_registration_v1_registration_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWaitlistPositionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWaitlistPositionResponse) ProtoMessage() {}
+
+func (x *GetWaitlistPositionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_registration_v1_registration_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWaitlistPositionResponse.ProtoReflect.Descriptor instead.
+func (*GetWaitlistPositionResponse) Descriptor() ([]byte, []int) {
+	return file_registration_v1_registration_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetWaitlistPositionResponse) GetWaitlisted() bool {
+	if x != nil {
+		return x.Waitlisted
+	}
+	return false
+}
+
+func (x *GetWaitlistPositionResponse) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+var File_registration_v1_registration_proto protoreflect.FileDescriptor
+
+const file_registration_v1_registration_proto_rawDesc = "" +
+	"\n" +
+	"\"registration/v1/registration.proto\x12\x0fregistration.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"z\n" +
+	"\x0fRegisterRequest\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\tR\tstudentId\x12\x1f\n" +
+	"\vsection_ids\x18\x02 \x03(\tR\n" +
+	"sectionIds\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\xad\x01\n" +
+	"\x12RegistrationResult\x12\x1d\n" +
+	"\n" +
+	"section_id\x18\x01 \x01(\tR\tsectionId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x120\n" +
+	"\x11waitlist_position\x18\x04 \x01(\x05H\x00R\x10waitlistPosition\x88\x01\x01B\x14\n" +
+	"\x12_waitlist_position\"Q\n" +
+	"\x10RegisterResponse\x12=\n" +
+	"\aresults\x18\x01 \x03(\v2#.registration.v1.RegistrationResultR\aresults\"Q\n" +
+	"\x11DropCourseRequest\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\tR\tstudentId\x12\x1d\n" +
+	"\n" +
+	"section_id\x18\x02 \x01(\tR\tsectionId\"\x14\n" +
+	"\x12DropCourseResponse\">\n" +
+	"\x1bGetAvailableSectionsRequest\x12\x1f\n" +
+	"\vsemester_id\x18\x01 \x01(\tR\n" +
+	"semesterId\"\x99\x02\n" +
+	"\aSection\x12\x1d\n" +
+	"\n" +
+	"section_id\x18\x01 \x01(\tR\tsectionId\x12\x1b\n" +
+	"\tcourse_id\x18\x02 \x01(\tR\bcourseId\x12\x1f\n" +
+	"\vsemester_id\x18\x03 \x01(\tR\n" +
+	"semesterId\x12%\n" +
+	"\x0esection_number\x18\x04 \x01(\tR\rsectionNumber\x12\x1f\n" +
+	"\vtotal_seats\x18\x05 \x01(\x05R\n" +
+	"totalSeats\x12'\n" +
+	"\x0favailable_seats\x18\x06 \x01(\x05R\x0eavailableSeats\x12\x1f\n" +
+	"\vcourse_code\x18\a \x01(\tR\n" +
+	"courseCode\x12\x1f\n" +
+	"\vcourse_name\x18\b \x01(\tR\n" +
+	"courseName\"T\n" +
+	"\x1cGetAvailableSectionsResponse\x124\n" +
+	"\bsections\x18\x01 \x03(\v2\x18.registration.v1.SectionR\bsections\"9\n" +
+	"\x18GetWaitlistStatusRequest\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\tR\tstudentId\"\xa4\x01\n" +
+	"\rWaitlistEntry\x12\x1f\n" +
+	"\vwaitlist_id\x18\x01 \x01(\tR\n" +
+	"waitlistId\x12\x1d\n" +
+	"\n" +
+	"section_id\x18\x02 \x01(\tR\tsectionId\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\x05R\bposition\x127\n" +
+	"\tjoined_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt\"U\n" +
+	"\x19GetWaitlistStatusResponse\x128\n" +
+	"\aentries\x18\x01 \x03(\v2\x1e.registration.v1.WaitlistEntryR\aentries\"Z\n" +
+	"\x1aGetWaitlistPositionRequest\x12\x1d\n" +
+	"\n" +
+	"student_id\x18\x01 \x01(\tR\tstudentId\x12\x1d\n" +
+	"\n" +
+	"section_id\x18\x02 \x01(\tR\tsectionId\"Y\n" +
+	"\x1bGetWaitlistPositionResponse\x12\x1e\n" +
+	"\n" +
+	"waitlisted\x18\x01 \x01(\bR\n" +
+	"waitlisted\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition2\x90\x04\n" +
+	"\x13RegistrationService\x12O\n" +
+	"\bRegister\x12 .registration.v1.RegisterRequest\x1a!.registration.v1.RegisterResponse\x12U\n" +
+	"\n" +
+	"DropCourse\x12\".registration.v1.DropCourseRequest\x1a#.registration.v1.DropCourseResponse\x12s\n" +
+	"\x14GetAvailableSections\x12,.registration.v1.GetAvailableSectionsRequest\x1a-.registration.v1.GetAvailableSectionsResponse\x12j\n" +
+	"\x11GetWaitlistStatus\x12).registration.v1.GetWaitlistStatusRequest\x1a*.registration.v1.GetWaitlistStatusResponse\x12p\n" +
+	"\x13GetWaitlistPosition\x12+.registration.v1.GetWaitlistPositionRequest\x1a,.registration.v1.GetWaitlistPositionResponseB5Z3cobra-template/proto/registration/v1;registrationv1b\x06proto3"
+
+var (
+	file_registration_v1_registration_proto_rawDescOnce sync.Once
+	file_registration_v1_registration_proto_rawDescData []byte
+)
+
+func file_registration_v1_registration_proto_rawDescGZIP() []byte {
+	file_registration_v1_registration_proto_rawDescOnce.Do(func() {
+		file_registration_v1_registration_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_registration_v1_registration_proto_rawDesc), len(file_registration_v1_registration_proto_rawDesc)))
+	})
+	return file_registration_v1_registration_proto_rawDescData
+}
+
+var file_registration_v1_registration_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_registration_v1_registration_proto_goTypes = []any{
+	(*RegisterRequest)(nil),              // 0: registration.v1.RegisterRequest
+	(*RegistrationResult)(nil),           // 1: registration.v1.RegistrationResult
+	(*RegisterResponse)(nil),             // 2: registration.v1.RegisterResponse
+	(*DropCourseRequest)(nil),            // 3: registration.v1.DropCourseRequest
+	(*DropCourseResponse)(nil),           // 4: registration.v1.DropCourseResponse
+	(*GetAvailableSectionsRequest)(nil),  // 5: registration.v1.GetAvailableSectionsRequest
+	(*Section)(nil),                      // 6: registration.v1.Section
+	(*GetAvailableSectionsResponse)(nil), // 7: registration.v1.GetAvailableSectionsResponse
+	(*GetWaitlistStatusRequest)(nil),     // 8: registration.v1.GetWaitlistStatusRequest
+	(*WaitlistEntry)(nil),                // 9: registration.v1.WaitlistEntry
+	(*GetWaitlistStatusResponse)(nil),    // 10: registration.v1.GetWaitlistStatusResponse
+	(*GetWaitlistPositionRequest)(nil),   // 11: registration.v1.GetWaitlistPositionRequest
+	(*GetWaitlistPositionResponse)(nil),  // 12: registration.v1.GetWaitlistPositionResponse
+	(*timestamppb.Timestamp)(nil),        // 13: google.protobuf.Timestamp
+}
+var file_registration_v1_registration_proto_depIdxs = []int32{
+	1,  // 0: registration.v1.RegisterResponse.results:type_name -> registration.v1.RegistrationResult
+	6,  // 1: registration.v1.GetAvailableSectionsResponse.sections:type_name -> registration.v1.Section
+	13, // 2: registration.v1.WaitlistEntry.joined_at:type_name -> google.protobuf.Timestamp
+	9,  // 3: registration.v1.GetWaitlistStatusResponse.entries:type_name -> registration.v1.WaitlistEntry
+	0,  // 4: registration.v1.RegistrationService.Register:input_type -> registration.v1.RegisterRequest
+	3,  // 5: registration.v1.RegistrationService.DropCourse:input_type -> registration.v1.DropCourseRequest
+	5,  // 6: registration.v1.RegistrationService.GetAvailableSections:input_type -> registration.v1.GetAvailableSectionsRequest
+	8,  // 7: registration.v1.RegistrationService.GetWaitlistStatus:input_type -> registration.v1.GetWaitlistStatusRequest
+	11, // 8: registration.v1.RegistrationService.GetWaitlistPosition:input_type -> registration.v1.GetWaitlistPositionRequest
+	2,  // 9: registration.v1.RegistrationService.Register:output_type -> registration.v1.RegisterResponse
+	4,  // 10: registration.v1.RegistrationService.DropCourse:output_type -> registration.v1.DropCourseResponse
+	7,  // 11: registration.v1.RegistrationService.GetAvailableSections:output_type -> registration.v1.GetAvailableSectionsResponse
+	10, // 12: registration.v1.RegistrationService.GetWaitlistStatus:output_type -> registration.v1.GetWaitlistStatusResponse
+	12, // 13: registration.v1.RegistrationService.GetWaitlistPosition:output_type -> registration.v1.GetWaitlistPositionResponse
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_registration_v1_registration_proto_init() }
+func file_registration_v1_registration_proto_init() {
+	if File_registration_v1_registration_proto != nil {
+		return
+	}
+	file_registration_v1_registration_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_registration_v1_registration_proto_rawDesc), len(file_registration_v1_registration_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_registration_v1_registration_proto_goTypes,
+		DependencyIndexes: file_registration_v1_registration_proto_depIdxs,
+		MessageInfos:      file_registration_v1_registration_proto_msgTypes,
+	}.Build()
+	File_registration_v1_registration_proto = out.File
+	file_registration_v1_registration_proto_goTypes = nil
+	file_registration_v1_registration_proto_depIdxs = nil
+}
@@ -288,7 +288,8 @@ class RegistrationDatabaseReset:
         try:
             # Patterns of keys to clear
             cache_patterns = [
-                'section:seats:*',      # Section seat counts
+                'section:seat_counter:*',  # Section seat counters
+                'section:seats:*',      # Section seat counts from before the counter hashes
                 'student:registrations:*',  # Student registration cache
                 'student:waitlist:*',   # Student waitlist cache
                 'sections:available:*',  # Available sections cache